@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"cloudweave/docs"
 	"cloudweave/internal/config"
@@ -14,7 +20,6 @@ import (
 	"cloudweave/internal/repositories"
 	"cloudweave/internal/services"
 
-	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	ginSwagger "github.com/swaggo/gin-swagger"
@@ -29,6 +34,9 @@ func main() {
 
 	// Load configuration
 	cfg := config.Load()
+	if err := config.ValidateJWTSigningKeys(cfg); err != nil {
+		log.Fatalf("Invalid JWT configuration: %v", err)
+	}
 
 	// Initialize database
 	dbConfig := database.Config{
@@ -38,12 +46,24 @@ func main() {
 		Password: cfg.DatabasePassword,
 		DBName:   cfg.DatabaseName,
 		SSLMode:  cfg.DatabaseSSLMode,
+
+		MaxOpenConns:    cfg.DatabaseMaxOpenConns,
+		MaxIdleConns:    cfg.DatabaseMaxIdleConns,
+		ConnMaxLifetime: cfg.DatabaseConnMaxLifetime,
+
+		ConnectRetries:    cfg.DatabaseConnectRetries,
+		ConnectRetryDelay: cfg.DatabaseConnectRetryDelay,
 	}
 
 	log.Printf("DEBUG: Connecting to database: host=%s port=%s user=%s dbname=%s sslmode=%s",
 		cfg.DatabaseHost, cfg.DatabasePort, cfg.DatabaseUser, cfg.DatabaseName, cfg.DatabaseSSLMode)
 
 	db, err := database.NewDatabase(dbConfig)
+	if errors.Is(err, database.ErrDatabaseUnavailable) {
+		// Start degraded instead of exiting; database-backed routes return 503 until it is reachable
+		log.Printf("Warning: %v; starting in degraded mode", err)
+		db, err = database.Open(dbConfig)
+	}
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
 	}
@@ -52,8 +72,24 @@ func main() {
 	// Skip Go migrations - database already has schema from Knex migrations
 	log.Println("Skipping Go migrations - using existing Knex schema")
 
+	// Route list and query reads to the read replica, if one is configured
+	var replicaDB *sql.DB
+	if cfg.DatabaseReplicaHost != "" {
+		replicaConfig := dbConfig
+		replicaConfig.Host = cfg.DatabaseReplicaHost
+		replicaConfig.Port = cfg.DatabaseReplicaPort
+
+		replica, err := database.Open(replicaConfig)
+		if err != nil {
+			log.Fatal("Failed to open read replica connection:", err)
+		}
+		defer replica.Close()
+		replicaDB = replica.DB
+		log.Printf("Read replica configured: host=%s port=%s", cfg.DatabaseReplicaHost, cfg.DatabaseReplicaPort)
+	}
+
 	// Initialize repository manager
-	repoManager := repositories.NewRepositoryManager(db.DB)
+	repoManager := repositories.NewRepositoryManagerWithReplica(db.DB, replicaDB)
 	log.Println("Repository layer initialized successfully")
 
 	// Initialize service manager with enhanced error handling and logging
@@ -62,18 +98,66 @@ func main() {
 
 	// Initialize services
 	wsService := services.NewWebSocketService()
+	wsService.SetConnectionLimits(cfg.WebSocketSendBuffer, cfg.WebSocketMaxConnectionsPerOrg)
+	services.SetProviderCallTimeout(cfg.ProviderCallTimeout)
 	infraService := services.NewInfrastructureService(repoManager)
-	deploymentService := services.NewDeploymentService(repoManager, wsService)
+	infraService.SetDeletedRetention(cfg.InfrastructureDeletedRetention)
+	infraService.SetProvisionTimeout(cfg.ProviderProvisionTimeout)
+	var awsAccounts *services.AWSAccounts
+	if accounts, err := services.NewAWSAccounts(context.Background(), repoManager.CloudCredentials); err != nil {
+		log.Printf("Warning: %v; organizations' AWS credentials will not be used", err)
+	} else {
+		awsAccounts = accounts
+	}
+	infraService.SetAWSAccounts(awsAccounts)
 
 	// Initialize metrics and alerts services with cloud providers from infrastructure service
 	providers := infraService.GetProviders()
 	metricsService := services.NewMetricsService(repoManager, providers)
+	metricsService.SetAWSAccounts(awsAccounts)
+	metricsService.SetScalingExecution(cfg.AutoscalingExecution)
+	deploymentService := services.NewDeploymentService(repoManager, wsService, metricsService)
 	alertService := services.NewAlertService(repoManager)
+	notificationService := services.NewNotificationService(repoManager.NotificationChannel, services.SMTPConfig{
+		Host:     cfg.SMTPHost,
+		Port:     cfg.SMTPPort,
+		Username: cfg.SMTPUsername,
+		Password: cfg.SMTPPassword,
+		From:     cfg.SMTPFrom,
+	})
+	alertService.SetNotificationService(notificationService)
+	webhookService := services.NewWebhookService(repoManager.Webhook)
+	infraService.SetWebhookService(webhookService)
+	deploymentService.SetWebhookService(webhookService)
+	infraService.SetAlertService(alertService)
+	resourceCache := services.NewResourceCache(cfg.ProviderCacheTTL)
+	infraService.SetResourceCache(resourceCache)
 	costService := services.NewCostManagementService(repoManager, providers)
-	securityService := services.NewSecurityService(repoManager.SecurityScan, repoManager.Vulnerability, repoManager.AuditLog)
-	complianceService := services.NewComplianceService(repoManager.ComplianceFramework, repoManager.ComplianceControl, repoManager.ComplianceAssessment, repoManager.AuditLog, repoManager.Transaction)
-	rbacService := services.NewRBACService(repoManager.Role, repoManager.UserRole, repoManager.ResourcePermission, repoManager.APIKey, repoManager.Session, repoManager.AuditLog, repoManager.Transaction)
-	auditService := services.NewAuditService(repoManager.AuditLog)
+	costService.SetAWSAccounts(awsAccounts)
+	costService.SetResourceCache(resourceCache)
+	costService.SetAnomalyThreshold(cfg.CostAnomalyThreshold)
+	auditWriter := services.NewAuditWriter(repoManager.AuditLog, cfg.AuditBatchSize)
+	deploymentService.SetAuditWriter(auditWriter)
+	securityService := services.NewSecurityService(repoManager.SecurityScan, repoManager.Vulnerability, auditWriter)
+	sbomService := services.NewSBOMService(repoManager, services.NewTrivyScanner(cfg.TrivyPath, cfg.TrivyTimeout))
+	securityService.SetScanner(models.ScanTypeContainer, sbomService)
+	misconfigurationScanner := services.NewMisconfigurationScanner(repoManager.Infrastructure, providers)
+	misconfigurationScanner.SetAWSAccounts(awsAccounts)
+	securityService.SetScanner(models.ScanTypeInfrastructure, misconfigurationScanner)
+	complianceService := services.NewComplianceService(repoManager.ComplianceFramework, repoManager.ComplianceControl, repoManager.ComplianceAssessment, repoManager.ComplianceReport, repoManager.ComplianceSchedule, repoManager.Organization, repoManager.Infrastructure, repoManager.SecurityScan, repoManager.Vulnerability, auditWriter, repoManager.Transaction)
+	complianceService.SetNotificationService(notificationService)
+	rbacService := services.NewRBACService(repoManager.Role, repoManager.UserRole, repoManager.ResourcePermission, repoManager.APIKey, repoManager.Session, repoManager.Organization, auditWriter, repoManager.Transaction)
+	auditService := services.NewAuditService(repoManager.AuditLog, auditWriter)
+	statsService := services.NewStatsService(repoManager, complianceService)
+	searchService := services.NewSearchService(repoManager.Search, rbacService)
+	idempotencyService := services.NewIdempotencyService(repoManager.IdempotencyKey)
+	idempotencyService.SetTTL(cfg.IdempotencyKeyTTL)
+	retentionService := services.NewRetentionService(repoManager)
+	retentionService.SetDefaultRetention(cfg.MetricsRawRetention, cfg.MetricsHourlyRetention, cfg.MetricsDailyRetention, cfg.AlertResolvedRetention)
+	jobService := services.NewJobService(repoManager.Job)
+	jobService.SetWorkers(cfg.JobWorkers)
+	jobService.SetRetention(cfg.JobRetention)
+	infraService.SetJobService(jobService)
 
 	log.Println("WebSocket service initialized successfully")
 	log.Println("Metrics and alerts services initialized successfully")
@@ -81,12 +165,22 @@ func main() {
 	// Initialize authentication services
 	handlers.InitializeAuthServices(cfg, db, auditService)
 	authService := handlers.GetAuthService()
+	if cfg.SMTPHost != "" {
+		authService.SetEmailVerification(notificationService, cfg.EmailVerificationURL)
+	}
 
 	// Initialize security service
 	handlers.InitializeSecurityService(securityService)
 
 	// Initialize cloud credentials service
 	_ = services.NewCloudCredentialsService(repoManager.CloudCredentials, repoManager.Organization)
+	handlers.InitializeCloudCredentialsServices(
+		repoManager.CloudCredentials,
+		services.NewAWSProvider(),
+		services.NewGCPProvider(),
+		services.NewAzureProvider(),
+		services.NewCloudCredentialsValidator(),
+	)
 
 	// Initialize demo data service
 	demoDataService := services.NewDemoDataService(
@@ -97,10 +191,73 @@ func main() {
 		repoManager.Alert,
 		repoManager.DemoData,
 	)
+	demoDataService.SetMetricsRange(cfg.DemoMetricsRange)
 
 	// Start WebSocket service in background
 	go wsService.Start()
 
+	// Background jobs run until shutdown begins
+	backgroundCtx, stopBackgroundJobs := context.WithCancel(context.Background())
+
+	// Track database availability so the server recovers from degraded mode
+	go db.StartAvailabilityMonitor(backgroundCtx, 5*time.Second)
+
+	// Write buffered audit logs in batches
+	auditCtx, stopAuditWriter := context.WithCancel(context.Background())
+	go auditWriter.Start(auditCtx, cfg.AuditFlushInterval)
+
+	// Deactivate expired role assignments in background
+	go rbacService.StartRoleExpiryCleanup(backgroundCtx, time.Hour)
+
+	// Drop JWT signing keys once every token they signed has expired
+	go handlers.GetJWTService().StartKeyRetirement(backgroundCtx, time.Hour)
+
+	// Remove OAuth states left behind by SSO logins that were never completed
+	go handlers.GetOAuthStateService().StartExpiryCleanup(backgroundCtx, time.Hour)
+
+	// Purge expired demo data in background
+	go demoDataService.StartExpiryCleanup(backgroundCtx, time.Hour)
+
+	// Evict expired provider details and metrics
+	go resourceCache.StartCleanup(backgroundCtx, time.Minute)
+
+	// Remove idempotency keys past their replay window
+	go idempotencyService.StartExpiryCleanup(backgroundCtx, time.Hour)
+
+	// Permanently remove deleted infrastructure past its restore window
+	go infraService.StartDeletedInfrastructurePurge(backgroundCtx, time.Hour)
+
+	// Fail infrastructure left pending by provisioning interrupted by a restart
+	go infraService.StartPendingReconciliation(backgroundCtx, time.Hour)
+
+	// Compare provisioned resources with their cloud state and alert on critical drift
+	if cfg.DriftDetectionInterval > 0 {
+		go infraService.StartDriftDetection(backgroundCtx, cfg.DriftDetectionInterval)
+	}
+
+	// Record daily stat snapshots used for period-over-period trends
+	go statsService.StartStatSnapshots(backgroundCtx, time.Hour)
+
+	// Record daily cost snapshots used as the baseline for cost anomaly detection
+	go costService.StartCostSnapshots(backgroundCtx, time.Hour)
+
+	// Roll up raw metrics into hourly and daily buckets
+	go metricsService.StartMetricsRollup(backgroundCtx, time.Hour)
+
+	// Purge metrics and resolved alerts past their organization's retention
+	go retentionService.StartRetentionPurge(backgroundCtx, time.Hour)
+
+	// Generate and email scheduled compliance reports once their month or quarter ends
+	go complianceService.StartComplianceReportScheduler(backgroundCtx, time.Hour)
+
+	// Run background jobs, requeueing those interrupted by the last shutdown
+	go jobService.Start(backgroundCtx)
+
+	// Resume deployments scheduled before the server restarted
+	if err := deploymentService.LoadScheduledDeployments(backgroundCtx); err != nil {
+		log.Printf("Warning: failed to load scheduled deployments: %v", err)
+	}
+
 	// Set Gin mode
 	if cfg.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -113,12 +270,37 @@ func main() {
 	middleware.RegisterCustomValidators()
 
 	// CORS middleware
-	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowOrigins = []string{"http://localhost:5173", "http://localhost:5174", "http://localhost:5176", "http://localhost:3000"}
-	corsConfig.AllowCredentials = true
-	corsConfig.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Request-ID"}
-	corsConfig.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
-	router.Use(cors.New(corsConfig))
+	if err := config.ValidateCORSOrigins(cfg.CORSOrigins); err != nil {
+		log.Fatalf("Invalid CORS_ORIGIN: %v", err)
+	}
+	corsPolicy, err := middleware.NewCORS(cfg.CORSOrigins)
+	if err != nil {
+		log.Fatalf("Invalid CORS_ORIGIN: %v", err)
+	}
+	router.Use(corsPolicy.Handler())
+
+	// Rate limiters, adjusted when the configuration is reloaded
+	authLimiter := middleware.NewRateLimiter(cfg.RateLimitAuth, cfg.RateLimitWindow)
+	readLimiter := middleware.NewRateLimiter(cfg.RateLimitRead, cfg.RateLimitWindow)
+	writeLimiter := middleware.NewRateLimiter(cfg.RateLimitWrite, cfg.RateLimitWindow)
+
+	if cfg.LogLevel != "" {
+		serviceManager.LoggingService.SetLevel(services.LogLevel(cfg.LogLevel))
+	}
+
+	// Apply hot-reloadable settings when the configuration is reloaded
+	configReloader := config.NewReloader(cfg, ".env")
+	configReloader.OnReload(func(reloaded *config.Config) {
+		authLimiter.SetLimit(reloaded.RateLimitAuth, reloaded.RateLimitWindow)
+		readLimiter.SetLimit(reloaded.RateLimitRead, reloaded.RateLimitWindow)
+		writeLimiter.SetLimit(reloaded.RateLimitWrite, reloaded.RateLimitWindow)
+		if err := corsPolicy.SetOrigins(reloaded.CORSOrigins); err != nil {
+			log.Printf("Failed to apply reloaded CORS origins: %v", err)
+		}
+		if reloaded.LogLevel != "" {
+			serviceManager.LoggingService.SetLevel(services.LogLevel(reloaded.LogLevel))
+		}
+	})
 
 	// Security middleware
 	router.Use(middleware.SecurityHeaders())
@@ -127,9 +309,6 @@ func main() {
 	// Request deduplication to handle React StrictMode double-invocation
 	router.Use(middleware.SmartDeduplicationMiddleware())
 	
-	// Adaptive rate limiting based on endpoint type
-	router.Use(middleware.AdaptiveRateLimitMiddleware())
-	
 	// Caching middleware for frequently accessed endpoints
 	router.Use(middleware.SmartCacheMiddleware())
 
@@ -152,6 +331,9 @@ func main() {
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	router.GET("/api/info", handlers.SwaggerInfo)
 
+	// Prometheus scrape endpoint
+	router.GET("/metrics", handlers.PrometheusMetrics(repoManager))
+
 	// API routes
 	api := router.Group("/api/v1")
 	{
@@ -168,10 +350,14 @@ func main() {
 				})
 				return
 			}
-			c.JSON(http.StatusOK, gin.H{
+			response := gin.H{
 				"status": "healthy",
-				"stats":  repoManager.Stats(),
-			})
+				"pool":   database.NewPoolStats(repoManager.Stats()),
+			}
+			if replicaStats, ok := repoManager.ReplicaStats(); ok {
+				response["replicaPool"] = database.NewPoolStats(replicaStats)
+			}
+			c.JSON(http.StatusOK, response)
 		})
 		
 		// Service manager stats endpoint
@@ -191,12 +377,29 @@ func main() {
 
 		// Auth routes
 		auth := api.Group("/auth")
+		auth.Use(middleware.LimiterMiddleware(authLimiter))
+		auth.Use(middleware.RequireDatabase(db))
 		{
 			auth.POST("/login", handlers.Login)
 			auth.POST("/register", handlers.Register)
 			auth.POST("/refresh", handlers.RefreshToken)
 			auth.POST("/logout", handlers.Logout)
 			auth.GET("/me", middleware.AuthRequired(handlers.GetJWTService()), handlers.GetCurrentUser)
+			auth.GET("/verify-email", handlers.VerifyEmail)
+			auth.POST("/verify-email/resend", middleware.AuthRequired(handlers.GetJWTService()), handlers.ResendVerificationEmail)
+			auth.POST("/forgot-password", handlers.ForgotPassword)
+			auth.POST("/reset-password", handlers.ResetPassword)
+			auth.GET("/organizations", middleware.AuthRequired(handlers.GetJWTService()), handlers.ListOrganizations)
+			auth.POST("/switch-org", middleware.AuthRequired(handlers.GetJWTService()), middleware.RejectImpersonation(), handlers.SwitchOrganization)
+
+			// MFA routes
+			mfa := auth.Group("/mfa")
+			{
+				mfa.POST("/verify", handlers.VerifyMFALogin)
+				mfa.POST("/enroll", middleware.AuthRequired(handlers.GetJWTService()), middleware.RejectImpersonation(), handlers.EnrollMFA)
+				mfa.POST("/enroll/verify", middleware.AuthRequired(handlers.GetJWTService()), middleware.RejectImpersonation(), handlers.VerifyMFAEnrollment)
+				mfa.POST("/disable", middleware.AuthRequired(handlers.GetJWTService()), middleware.RejectImpersonation(), handlers.DisableMFA)
+			}
 
 			// SSO routes
 			sso := auth.Group("/sso")
@@ -209,7 +412,9 @@ func main() {
 
 		// Protected routes
 		protected := api.Group("/")
-		protected.Use(middleware.AuthRequired(handlers.GetJWTService()))
+		protected.Use(middleware.RequireDatabase(db))
+		protected.Use(middleware.AuthOrAPIKey(handlers.GetJWTService(), rbacService))
+		protected.Use(middleware.ReadWriteLimiterMiddleware(readLimiter, writeLimiter))
 		protected.Use(middleware.AuditLog(auditService))
 		{
 			// Dashboard routes
@@ -224,10 +429,12 @@ func main() {
 				dashboard.GET("/security", dashboardHandler.GetSecurityMetrics)
 				dashboard.GET("/infrastructure", dashboardHandler.GetInfrastructureMetrics)
 				dashboard.GET("/reports", dashboardHandler.GetReportsMetrics)
+				dashboard.GET("/batch", dashboardHandler.GetDashboardBatch)
 			}
 
 			// Infrastructure handler
-			infraHandler := handlers.NewInfrastructureHandler(repoManager, infraService)
+			infraHandler := handlers.NewInfrastructureHandler(repoManager, infraService, statsService)
+			costHandler := handlers.NewCostManagementHandler(repoManager, costService)
 			
 			// Infrastructure overview routes
 			protected.GET("/infrastructure/stats", infraHandler.GetInfrastructureStats)
@@ -235,17 +442,27 @@ func main() {
 			protected.GET("/infrastructure/recent-changes", infraHandler.GetRecentChanges)
 			protected.GET("/infrastructure/batch", infraHandler.GetInfrastructureBatch)
 			protected.GET("/infrastructure/providers", infraHandler.GetProviders)
+			protected.GET("/infrastructure/export", infraHandler.ExportInfrastructure)
+
+			// Bulk sync runs as a background job, polled at /jobs/:id
+			protected.POST("/infrastructure/sync-all", middleware.RequirePermission(rbacService, models.PermissionInfrastructureUpdate), infraHandler.SyncAllInfrastructure)
+
+			// Organization quota routes; changing quotas is limited to organization admins
+			protected.GET("/infrastructure/quota", infraHandler.GetQuota)
+			protected.PUT("/infrastructure/quota", middleware.RequirePermission(rbacService, models.PermissionOrgManage), infraHandler.UpdateQuota)
+			protected.DELETE("/infrastructure/quota", middleware.RequirePermission(rbacService, models.PermissionOrgManage), infraHandler.DeleteQuota)
 			
 			// Infrastructure CRUD routes
 			infrastructure := protected.Group("/infrastructure")
 			{
-				infrastructure.POST("/", infraHandler.CreateInfrastructure)
+				infrastructure.POST("/", middleware.Idempotency(idempotencyService), infraHandler.CreateInfrastructure)
 				infrastructure.GET("/", 
 					middleware.ValidateQuery(map[string]string{
 						"page": "numeric",
 						"limit": "numeric",
 						"provider": "alpha",
 						"status": "alpha",
+						"includeDeleted": "alpha",
 					}),
 					infraHandler.ListInfrastructure)
 				infrastructure.GET("/:id", 
@@ -260,31 +477,91 @@ func main() {
 				infrastructure.GET("/:id/metrics", 
 					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
 					infraHandler.GetInfrastructureMetrics)
+				infrastructure.GET("/:id/drift", 
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					infraHandler.GetInfrastructureDrift)
+				infrastructure.GET("/:id/history",
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					infraHandler.GetInfrastructureHistory)
+				infrastructure.GET("/:id/cost-history",
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					costHandler.GetResourceCostHistory)
 				infrastructure.POST("/:id/sync", 
 					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
 					infraHandler.SyncInfrastructure)
+				infrastructure.POST("/:id/restore", 
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					infraHandler.RestoreInfrastructure)
+				// POST /infrastructure/:provider/import; gin requires the segment to reuse the :id wildcard name
+				infrastructure.POST("/:id/import", middleware.Idempotency(idempotencyService), infraHandler.ImportInfrastructure)
+				infrastructure.POST("/:id/tags", 
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					infraHandler.AddInfrastructureTags)
+				infrastructure.DELETE("/:id/tags", 
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					infraHandler.RemoveInfrastructureTags)
+
+				// Database snapshots; restoring provisions a new resource from a snapshot
+				infrastructure.POST("/:id/snapshot",
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					middleware.RequirePermission(rbacService, models.PermissionInfrastructureUpdate),
+					infraHandler.CreateInfrastructureSnapshot)
+				infrastructure.GET("/:id/snapshots",
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					infraHandler.ListInfrastructureSnapshots)
+				infrastructure.POST("/:id/snapshots/:snapshotId/restore",
+					middleware.ValidatePathParams(map[string]string{"id": "uuid", "snapshotId": "uuid"}),
+					middleware.RequirePermission(rbacService, models.PermissionInfrastructureCreate),
+					middleware.Idempotency(idempotencyService),
+					infraHandler.RestoreInfrastructureSnapshot)
+			}
+
+			// Background job routes
+			jobHandler := handlers.NewJobHandler(jobService)
+			jobs := protected.Group("/jobs")
+			{
+				jobs.GET("/:id",
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					jobHandler.GetJob)
+				jobs.GET("/:id/download",
+					middleware.ValidatePathParams(map[string]string{"id": "uuid"}),
+					jobHandler.DownloadJobFile)
 			}
 
 			// Deployment routes
-			deploymentHandler := handlers.NewDeploymentHandler(repoManager, deploymentService)
+			deploymentHandler := handlers.NewDeploymentHandler(repoManager, deploymentService, statsService)
+			sbomHandler := handlers.NewSBOMHandler(sbomService)
 			deployments := protected.Group("/deployments")
 			{
 				deployments.GET("/stats", deploymentHandler.GetDeploymentStats)
 				deployments.GET("/recent", deploymentHandler.GetRecentDeployments)
 				deployments.GET("/pipelines", deploymentHandler.GetPipelines)
+				deployments.POST("/pipelines", deploymentHandler.CreatePipeline)
+				deployments.GET("/pipelines/:id", deploymentHandler.GetPipeline)
+				deployments.PUT("/pipelines/:id", deploymentHandler.UpdatePipeline)
+				deployments.DELETE("/pipelines/:id", deploymentHandler.DeletePipeline)
 				deployments.GET("/environments", deploymentHandler.GetEnvironments)
-				deployments.POST("/", deploymentHandler.CreateDeployment)
+				deployments.POST("/environments", deploymentHandler.CreateEnvironment)
+				deployments.DELETE("/environments/:id", deploymentHandler.DeleteEnvironment)
+				deployments.POST("/", middleware.Idempotency(idempotencyService), deploymentHandler.CreateDeployment)
+				deployments.POST("/preview", deploymentHandler.PreviewDeployment)
 				deployments.GET("/", deploymentHandler.ListDeployments)
 				deployments.GET("/history", deploymentHandler.GetDeploymentHistory)
+				deployments.GET("/scheduled", deploymentHandler.ListScheduledDeployments)
 				deployments.GET("/:id", deploymentHandler.GetDeployment)
 				deployments.PUT("/:id", deploymentHandler.UpdateDeployment)
 				deployments.DELETE("/:id", deploymentHandler.DeleteDeployment)
 				deployments.GET("/:id/status", deploymentHandler.GetDeploymentStatus)
 				deployments.GET("/:id/logs", deploymentHandler.GetDeploymentLogs)
+				deployments.GET("/:id/sbom", sbomHandler.GetDeploymentSBOM)
 				deployments.POST("/:id/rollback", deploymentHandler.RollbackDeployment)
 				deployments.POST("/:id/cancel", deploymentHandler.CancelDeployment)
 			}
 
+			// Log streaming accepts the token as a query parameter since browsers cannot set
+			// headers on WebSocket upgrades
+			api.GET("/deployments/:id/logs/stream", middleware.WebSocketAuthRequired(handlers.GetJWTService()), deploymentHandler.StreamDeploymentLogs)
+
 			// Metrics routes
 			metricsHandler := handlers.NewMetricsHandler(metricsService)
 			metrics := protected.Group("/metrics")
@@ -295,6 +572,9 @@ func main() {
 				metrics.GET("/resources/:id", metricsHandler.GetResourceMetrics)
 				metrics.POST("/collect", metricsHandler.CollectMetrics)
 				metrics.GET("/stream", metricsHandler.StreamMetrics)
+				metrics.GET("/scaling-recommendations", metricsHandler.GetScalingRecommendations)
+				metrics.POST("/resources/:id/scale", middleware.RequirePermission(rbacService, models.PermissionInfrastructureUpdate), metricsHandler.ScaleResource)
+				metrics.POST("/resources/:id/scale/rollback", middleware.RequirePermission(rbacService, models.PermissionInfrastructureUpdate), metricsHandler.RollbackScaling)
 			}
 
 			// Alerts routes
@@ -304,13 +584,43 @@ func main() {
 				alerts.GET("/", alertsHandler.GetAlerts)
 				alerts.GET("/active", alertsHandler.GetActiveAlerts)
 				alerts.GET("/summary", alertsHandler.GetAlertSummary)
+				alerts.GET("/:id", alertsHandler.GetAlert)
+				alerts.POST("/bulk-acknowledge", alertsHandler.BulkAcknowledgeAlerts)
+				alerts.POST("/bulk-resolve", alertsHandler.BulkResolveAlerts)
 				alerts.POST("/:id/acknowledge", alertsHandler.AcknowledgeAlert)
 				alerts.PUT("/:id/status", alertsHandler.UpdateAlertStatus)
 				alerts.POST("/rules", alertsHandler.CreateAlertRule)
+				alerts.GET("/rules", alertsHandler.GetAlertRules)
+				alerts.DELETE("/rules/:id", alertsHandler.DeleteAlertRule)
+			}
+
+			// Notification routes
+			notificationHandler := handlers.NewNotificationHandler(notificationService)
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("/channels", middleware.RequirePermission(rbacService, models.PermissionMonitoringView), notificationHandler.GetChannels)
+				notifications.POST("/channels", middleware.RejectImpersonation(), middleware.RequirePermission(rbacService, models.PermissionMonitoringManage), notificationHandler.CreateChannel)
+				notifications.PUT("/channels/:id", middleware.RejectImpersonation(), middleware.RequirePermission(rbacService, models.PermissionMonitoringManage), notificationHandler.UpdateChannel)
+				notifications.DELETE("/channels/:id", middleware.RejectImpersonation(), middleware.RequirePermission(rbacService, models.PermissionMonitoringManage), notificationHandler.DeleteChannel)
+				notifications.GET("/deliveries", middleware.RequirePermission(rbacService, models.PermissionMonitoringView), notificationHandler.GetDeliveries)
+			}
+
+			// Webhook routes
+			webhookHandler := handlers.NewWebhookHandler(webhookService)
+			webhooks := protected.Group("/webhooks")
+			{
+				webhooks.GET("/", middleware.RequirePermission(rbacService, models.PermissionOrgView), webhookHandler.GetWebhooks)
+				webhooks.POST("/", middleware.RejectImpersonation(), middleware.RequirePermission(rbacService, models.PermissionOrgManage), webhookHandler.CreateWebhook)
+				webhooks.DELETE("/:id", middleware.RejectImpersonation(), middleware.RequirePermission(rbacService, models.PermissionOrgManage), webhookHandler.DeleteWebhook)
+				webhooks.POST("/:id/test", middleware.RejectImpersonation(), middleware.RequirePermission(rbacService, models.PermissionOrgManage), webhookHandler.TestWebhook)
+				webhooks.GET("/:id/deliveries", middleware.RequirePermission(rbacService, models.PermissionOrgView), webhookHandler.GetDeliveries)
 			}
 
+			// Search routes
+			searchHandler := handlers.NewSearchHandler(searchService)
+			protected.GET("/search", searchHandler.Search)
+
 			// Cost Management routes
-			costHandler := handlers.NewCostManagementHandler(repoManager, costService)
 			costs := protected.Group("/costs")
 			{
 				costs.GET("/overview", costHandler.GetCostOverview)
@@ -321,20 +631,49 @@ func main() {
 				costs.POST("/by-tags", costHandler.GetCostByTags)
 				costs.GET("/real-time", costHandler.GetRealTimeCostMonitoring)
 				costs.GET("/allocation", costHandler.GetCostAllocationByTags)
+				costs.GET("/allocation/dimensions", costHandler.GetCostAllocationSettings)
+				costs.PUT("/allocation/dimensions", middleware.RequirePermission(rbacService, models.PermissionOrgManage), costHandler.UpdateCostAllocationSettings)
 				costs.GET("/recommendations", costHandler.GetCostOptimizationRecommendations)
+				costs.GET("/export", costHandler.ExportCosts)
 				costs.POST("/budgets", costHandler.CreateBudget)
 				costs.GET("/budgets", costHandler.GetBudgets)
 			}
 
+			// Data retention routes
+			retentionHandler := handlers.NewRetentionHandler(retentionService)
+			retention := protected.Group("/retention")
+			{
+				retention.GET("/", retentionHandler.GetRetentionSettings)
+				retention.PUT("/", middleware.RequirePermission(rbacService, models.PermissionOrgManage), retentionHandler.UpdateRetentionSettings)
+				retention.GET("/preview", middleware.RequirePermission(rbacService, models.PermissionOrgManage), retentionHandler.PreviewRetentionPurge)
+			}
+
+			// Server administration routes, limited to platform administrators
+			configHandler := handlers.NewConfigHandler(configReloader)
+			admin := protected.Group("/admin")
+			admin.Use(middleware.RequirePermission(rbacService, models.PermissionAdminFull))
+			{
+				admin.POST("/config/reload", configHandler.ReloadConfig)
+			}
+
+			// Impersonation routes, limited to support users. Impersonation tokens cannot
+			// impersonate again, only end their own impersonation.
+			impersonationService := services.NewImpersonationService(repoManager.User, repoManager.Organization, handlers.GetJWTService(), rbacService, auditWriter)
+			impersonationHandler := handlers.NewImpersonationHandler(impersonationService)
+			protected.POST("/admin/impersonate/:userId", middleware.RejectImpersonation(), middleware.RequirePermission(rbacService, models.PermissionAdminImpersonate), impersonationHandler.StartImpersonation)
+			protected.DELETE("/admin/impersonate", impersonationHandler.EndImpersonation)
+
 			// Security routes
 			security := protected.Group("/security")
 			{
 				security.POST("/scans", handlers.CreateSecurityScan)
 				security.GET("/scans", handlers.ListSecurityScans)
 				security.GET("/scans/:id", handlers.GetSecurityScan)
+				security.GET("/scans/:id/findings", handlers.GetScanFindings)
 				security.GET("/vulnerabilities", handlers.GetVulnerabilities)
 				security.GET("/vulnerabilities/:id", handlers.GetVulnerability)
 				security.PUT("/vulnerabilities/:id", handlers.UpdateVulnerability)
+				security.GET("/vulnerabilities/:id/history", handlers.GetVulnerabilityHistory)
 				security.GET("/metrics", handlers.GetSecurityMetrics)
 			}
 
@@ -349,6 +688,7 @@ func main() {
 				compliance.PUT("/frameworks/:id", complianceHandler.UpdateFramework)
 				compliance.DELETE("/frameworks/:id", complianceHandler.DeleteFramework)
 				compliance.GET("/frameworks/:id/statistics", complianceHandler.GetControlStatistics)
+				compliance.POST("/frameworks/:id/seed-controls", complianceHandler.SeedControls)
 
 				// Assessment routes
 				compliance.POST("/assessments", complianceHandler.CreateAssessment)
@@ -357,35 +697,51 @@ func main() {
 
 				// Metrics routes
 				compliance.GET("/metrics", complianceHandler.GetMetrics)
+				compliance.GET("/trends", complianceHandler.GetTrends)
 				compliance.GET("/violations", complianceHandler.GetViolations)
+
+				// Report routes
+				compliance.GET("/report-schedules", complianceHandler.ListReportSchedules)
+				compliance.POST("/report-schedules", middleware.RequirePermission(rbacService, models.PermissionComplianceManage), complianceHandler.CreateReportSchedule)
+				compliance.PUT("/report-schedules/:id", middleware.RequirePermission(rbacService, models.PermissionComplianceManage), complianceHandler.UpdateReportSchedule)
+				compliance.DELETE("/report-schedules/:id", middleware.RequirePermission(rbacService, models.PermissionComplianceManage), complianceHandler.DeleteReportSchedule)
+				compliance.GET("/reports", complianceHandler.ListReports)
+				compliance.GET("/reports/:id", complianceHandler.GetReport)
+				compliance.GET("/reports/:id/download", complianceHandler.DownloadReport)
 			}
 
 			// RBAC routes
 			rbacHandler := handlers.NewRBACGinHandler(rbacService)
 			rbac := protected.Group("/rbac")
 			{
-				// Role management routes
-				rbac.POST("/roles", rbacHandler.CreateRole)
+				// Role management routes. Roles, permissions and API keys cannot be changed while
+				// impersonating, so impersonation cannot grant the impersonator anything.
+				rbac.POST("/roles", middleware.RejectImpersonation(), rbacHandler.CreateRole)
 				rbac.GET("/roles", rbacHandler.ListRoles)
 				rbac.GET("/roles/:id", rbacHandler.GetRole)
-				rbac.PUT("/roles/:id", rbacHandler.UpdateRole)
-				rbac.DELETE("/roles/:id", rbacHandler.DeleteRole)
+				rbac.PUT("/roles/:id", middleware.RejectImpersonation(), rbacHandler.UpdateRole)
+				rbac.DELETE("/roles/:id", middleware.RejectImpersonation(), rbacHandler.DeleteRole)
 
 				// User role assignment routes
-				rbac.POST("/users/:userId/roles", rbacHandler.AssignRole)
-				rbac.DELETE("/users/:userId/roles/:roleId", rbacHandler.RemoveRole)
+				rbac.POST("/users/:userId/roles", middleware.RejectImpersonation(), rbacHandler.AssignRole)
+				rbac.DELETE("/users/:userId/roles/:roleId", middleware.RejectImpersonation(), rbacHandler.RemoveRole)
 				rbac.GET("/users/:userId/roles", rbacHandler.GetUserRoles)
 				rbac.GET("/users/:userId/permissions", rbacHandler.GetUserPermissions)
 
+				// Resource permission routes
+				rbac.POST("/resource-permissions", middleware.RejectImpersonation(), middleware.RequirePermission(rbacService, models.PermissionUserManage), rbacHandler.GrantResourcePermission)
+				rbac.GET("/resource-permissions", rbacHandler.ListResourcePermissions)
+				rbac.DELETE("/resource-permissions/:id", middleware.RejectImpersonation(), middleware.RequirePermission(rbacService, models.PermissionUserManage), rbacHandler.RevokeResourcePermission)
+
 				// Permission checking routes
 				rbac.POST("/check-permission", rbacHandler.CheckPermission)
 
 				// API key management routes
-				rbac.POST("/api-keys", rbacHandler.CreateAPIKey)
+				rbac.POST("/api-keys", middleware.RejectImpersonation(), rbacHandler.CreateAPIKey)
 				rbac.GET("/api-keys", rbacHandler.ListAPIKeys)
 
 				// System routes
-				rbac.POST("/system/initialize", rbacHandler.InitializeSystemRoles)
+				rbac.POST("/system/initialize", middleware.RejectImpersonation(), rbacHandler.InitializeSystemRoles)
 			}
 
 			// Cloud credentials routes
@@ -399,7 +755,7 @@ func main() {
 			}
 
 			// Audit routes
-			auditHandler := handlers.NewAuditHandler(auditService)
+			auditHandler := handlers.NewAuditHandler(auditService, repoManager.Organization)
 			audit := protected.Group("/audit")
 			{
 				audit.GET("/", auditHandler.GetAuditLogs)
@@ -450,7 +806,57 @@ func main() {
 		log.Println("Services shut down successfully")
 	}()
 
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	server := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+
+	// WebSocket connections are hijacked, so Shutdown does not wait for them; close them as
+	// soon as shutdown begins instead
+	server.RegisterOnShutdown(wsService.Stop)
+
+	// Metric streams only end when the client disconnects, so Shutdown would otherwise wait the
+	// full timeout for them
+	server.RegisterOnShutdown(metricsService.CloseStreams)
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	// Stop accepting connections and wait for in-flight requests to finish
+	log.Printf("Shutting down server, draining in-flight requests for up to %s...", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error during server shutdown: %v", err)
 	}
+
+	stopBackgroundJobs()
+
+	// Let background provisioning record its result; anything cut off is failed by the pending
+	// reconciliation after the next start. This gets its own deadline since draining requests may
+	// have used up the shutdown timeout.
+	provisioningCtx, cancelProvisioning := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelProvisioning()
+	if err := infraService.WaitForProvisioning(provisioningCtx); err != nil {
+		log.Printf("Shutdown timed out waiting for infrastructure provisioning: %v", err)
+	}
+
+	// Write any audit logs still buffered once in-flight requests have finished. This gets its own
+	// deadline so a slow drain does not cost the buffered entries.
+	stopAuditWriter()
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFlush()
+	if err := auditWriter.Flush(flushCtx); err != nil {
+		log.Printf("Failed to flush audit logs: %v", err)
+	}
+
+	// Deferred calls then close the services and the database connection
 }
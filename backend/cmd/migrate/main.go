@@ -1,14 +1,18 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"text/tabwriter"
+	"time"
 
 	"cloudweave/internal/config"
 	"cloudweave/internal/database"
 
+	"github.com/golang-migrate/migrate/v4"
 	"github.com/joho/godotenv"
 )
 
@@ -20,9 +24,10 @@ func main() {
 
 	// Parse command line flags
 	var (
-		action  = flag.String("action", "up", "Migration action: up, down, version, force")
+		action  = flag.String("action", "up", "Migration action: up, down, version, status, force")
 		steps   = flag.Int("steps", 1, "Number of migration steps (for down action)")
-		version = flag.Uint("version", 0, "Force migration to specific version")
+		version = flag.Int("version", 0, "Force migration to specific version (-1 for no version)")
+		confirm = flag.Bool("confirm", false, "Confirm the force action")
 	)
 	flag.Parse()
 
@@ -72,18 +77,79 @@ func main() {
 			fmt.Println("Warning: Migration state is dirty")
 		}
 
+	case "status":
+		statuses, err := db.Status()
+		if err != nil {
+			log.Fatal("Failed to get migration status:", err)
+		}
+
+		current, dirty := currentVersion(db)
+		fmt.Printf("Current migration version: %s (dirty: %t)\n\n", current, dirty)
+
+		pending := 0
+		known := current == "none"
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tFILE MODIFIED")
+		for _, status := range statuses {
+			state := "pending"
+			switch {
+			case status.Dirty:
+				state = "dirty"
+			case status.Applied:
+				state = "applied"
+			default:
+				pending++
+			}
+			if fmt.Sprintf("%d", status.Version) == current {
+				known = true
+			}
+			fmt.Fprintf(w, "%06d\t%s\t%s\t%s\n", status.Version, status.Name, state, status.ModifiedAt.Format(time.RFC3339))
+		}
+		w.Flush()
+
+		fmt.Printf("\n%d migration(s), %d pending\n", len(statuses), pending)
+		if !known {
+			fmt.Printf("Warning: the database is at version %s, which has no migration file\n", current)
+		}
+
 	case "force":
-		if *version == 0 {
-			log.Fatal("Version must be specified for force action")
+		if *version == 0 || *version < -1 {
+			log.Fatal("Version must be specified for force action (-1 for no version)")
 		}
+		// Forcing only rewrites the recorded version, so a wrong version silently skips or
+		// reruns migrations
+		if !*confirm {
+			log.Fatal("Force sets the migration version without running migrations; re-run with --confirm to proceed")
+		}
+
+		beforeVersion, beforeDirty := currentVersion(db)
+		fmt.Printf("Current migration version: %s (dirty: %t)\n", beforeVersion, beforeDirty)
 		fmt.Printf("Forcing migration to version %d...\n", *version)
-		// Note: This is a dangerous operation and should be used carefully
-		fmt.Println("Force migration is not implemented for safety reasons")
-		fmt.Println("Please use the migrate CLI tool directly if needed")
+		if err := db.Force(*version); err != nil {
+			log.Fatal("Failed to force migration version:", err)
+		}
+
+		afterVersion, afterDirty := currentVersion(db)
+		fmt.Printf("Migration version is now: %s (dirty: %t)\n", afterVersion, afterDirty)
+		if beforeDirty && !afterDirty {
+			fmt.Println("Dirty flag cleared")
+		}
 
 	default:
 		fmt.Printf("Unknown action: %s\n", *action)
-		fmt.Println("Available actions: up, down, version, force")
+		fmt.Println("Available actions: up, down, version, status, force")
 		os.Exit(1)
 	}
 }
+
+// currentVersion describes the current migration version for display
+func currentVersion(db *database.Database) (string, bool) {
+	version, dirty, err := db.GetVersion()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return "none", false
+	}
+	if err != nil {
+		log.Fatal("Failed to get migration version:", err)
+	}
+	return fmt.Sprintf("%d", version), dirty
+}
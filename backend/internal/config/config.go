@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
 	"strings"
@@ -11,6 +12,13 @@ type Config struct {
 	Environment string
 	Port        string
 
+	// LogLevel is the structured logger's level: debug, info, warn, error or fatal. When empty the
+	// logger keeps its default for the environment.
+	LogLevel string
+
+	// ShutdownTimeout bounds how long in-flight requests are drained on shutdown
+	ShutdownTimeout time.Duration
+
 	// Database
 	DatabaseURL      string
 	DatabaseHost     string
@@ -20,14 +28,114 @@ type Config struct {
 	DatabasePassword string
 	DatabaseSSLMode  string
 
+	// Database connection pool
+	DatabaseMaxOpenConns    int
+	DatabaseMaxIdleConns    int
+	DatabaseConnMaxLifetime time.Duration
+
+	// Database startup connection retries
+	DatabaseConnectRetries    int
+	DatabaseConnectRetryDelay time.Duration
+
+	// Optional read replica for list and query reads; it shares the primary's credentials
+	DatabaseReplicaHost string
+	DatabaseReplicaPort string
+
 	// JWT
 	JWTSecret         string
 	JWTExpirationTime time.Duration
 	JWTRefreshTime    time.Duration
+	// JWTSigningKeys are the keys tokens may be signed with, identified by the kid token header.
+	// New tokens are signed with JWTCurrentKeyID; the others only validate tokens issued before
+	// JWTKeysRotatedAt, until those tokens have expired.
+	JWTSigningKeys   []JWTSigningKey
+	JWTCurrentKeyID  string
+	JWTKeysRotatedAt time.Time
 
 	// Security
 	BCryptRounds int
-	CORSOrigins  []string
+	// CORSOrigins are the origins allowed to make credentialed cross-origin requests, read from the
+	// comma-separated CORS_ORIGIN. Origins may use a wildcard subdomain, e.g. https://*.example.com.
+	CORSOrigins      []string
+	MFAEncryptionKey string
+	MFAIssuer        string
+
+	// Password policy. When PasswordBreachCheck is set, passwords found in breaches are rejected:
+	// they are looked up in the HaveIBeenPwned range API at PasswordBreachAPIURL, falling back to
+	// a built-in list of common passwords and PasswordBreachListFile when it cannot be reached.
+	PasswordMinLength        int
+	PasswordRequireUppercase bool
+	PasswordRequireLowercase bool
+	PasswordRequireDigit     bool
+	PasswordRequireSpecial   bool
+	PasswordBreachCheck      bool
+	PasswordBreachAPIURL     string
+	PasswordBreachListFile   string
+
+	// Rate limiting, in requests per window for each caller
+	RateLimitWindow time.Duration
+	RateLimitAuth   int
+	RateLimitRead   int
+	RateLimitWrite  int
+
+	// Idempotency
+	IdempotencyKeyTTL time.Duration
+
+	// Metrics
+	MetricsRawRetention    time.Duration
+	MetricsHourlyRetention time.Duration
+	MetricsDailyRetention  time.Duration
+	AutoscalingExecution   bool
+
+	// Alerts
+	AlertResolvedRetention time.Duration
+
+	// Costs
+	CostAnomalyThreshold float64
+
+	// Security scanning
+	TrivyPath    string
+	TrivyTimeout time.Duration
+
+	// ProviderCacheTTL is how long cloud provider resource details and metrics are reused
+	ProviderCacheTTL time.Duration
+
+	// ProviderCallTimeout bounds a single cloud provider call; ProviderProvisionTimeout bounds
+	// creating a resource, which runs in the background
+	ProviderCallTimeout      time.Duration
+	ProviderProvisionTimeout time.Duration
+
+	// Infrastructure
+	InfrastructureDeletedRetention time.Duration
+	DriftDetectionInterval         time.Duration
+
+	// Audit
+	AuditBatchSize     int
+	AuditFlushInterval time.Duration
+
+	// Background jobs; finished jobs can be polled for JobRetention
+	JobWorkers   int
+	JobRetention time.Duration
+
+	// Metric history generated for new demo data sets
+	DemoMetricsRange time.Duration
+
+	// SMTP for email notifications
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+
+	// EmailVerificationURL is where email verification links point; the token is added as ?token=
+	EmailVerificationURL string
+
+	// PasswordResetURL is the page where users choose a new password; the token is added as ?token=
+	PasswordResetURL string
+
+	// WebSocket
+	WebSocketSendBuffer           int
+	WebSocketMaxConnectionsPerOrg int
 
 	// SSO Configuration
 	SSO SSOConfig
@@ -60,6 +168,12 @@ type OAuthProvider struct {
 	UserInfoURL  string
 }
 
+// JWTSigningKey is an HMAC key used to sign and validate JWTs
+type JWTSigningKey struct {
+	ID     string
+	Secret string
+}
+
 type SAMLConfig struct {
 	Enabled         bool
 	EntityID        string
@@ -69,14 +183,65 @@ type SAMLConfig struct {
 	MetadataURL     string
 }
 
+// defaultJWTSecret signs tokens when neither JWT_SECRET nor JWT_SIGNING_KEYS is set
+const defaultJWTSecret = "your-super-secret-jwt-key-that-should-be-changed-in-production"
+
 func Load() *Config {
+	shutdownTimeout, _ := time.ParseDuration(getEnv("SHUTDOWN_TIMEOUT", "30s"))
+	dbMaxOpenConns, _ := strconv.Atoi(getEnv("DB_MAX_OPEN_CONNS", "25"))
+	dbMaxIdleConns, _ := strconv.Atoi(getEnv("DB_MAX_IDLE_CONNS", "5"))
+	dbConnMaxLifetime, _ := time.ParseDuration(getEnv("DB_CONN_MAX_LIFETIME", "5m"))
+	dbConnectRetries, _ := strconv.Atoi(getEnv("DB_CONNECT_RETRIES", "5"))
+	dbConnectRetryDelay, _ := time.ParseDuration(getEnv("DB_CONNECT_RETRY_DELAY", "1s"))
 	jwtExpiration, _ := time.ParseDuration(getEnv("JWT_EXPIRES_IN", "15m"))
 	jwtRefreshExpiration, _ := time.ParseDuration(getEnv("JWT_REFRESH_EXPIRES_IN", "168h")) // 7 days
+	jwtSigningKeys := parseJWTSigningKeys(getEnvSlice("JWT_SIGNING_KEYS", nil))
+	jwtKeysRotatedAt, _ := time.Parse(time.RFC3339, getEnv("JWT_KEYS_ROTATED_AT", ""))
+	jwtSecret := getEnv("JWT_SECRET", defaultJWTSecret)
+	if len(jwtSigningKeys) > 0 {
+		// With signing keys JWT_SECRET only validates tokens issued before them, so the public
+		// default must not be accepted
+		jwtSecret = os.Getenv("JWT_SECRET")
+	}
 	bcryptRounds, _ := strconv.Atoi(getEnv("BCRYPT_ROUNDS", "12"))
+	rateLimitWindow, _ := time.ParseDuration(getEnv("RATE_LIMIT_WINDOW", "1m"))
+	rateLimitAuth, _ := strconv.Atoi(getEnv("RATE_LIMIT_AUTH", "20"))
+	rateLimitRead, _ := strconv.Atoi(getEnv("RATE_LIMIT_READ", "200"))
+	rateLimitWrite, _ := strconv.Atoi(getEnv("RATE_LIMIT_WRITE", "50"))
+	idempotencyKeyTTL, _ := time.ParseDuration(getEnv("IDEMPOTENCY_KEY_TTL", "24h"))
+	metricsRawRetention, _ := time.ParseDuration(getEnv("METRICS_RAW_RETENTION", "168h"))        // 7 days
+	metricsHourlyRetention, _ := time.ParseDuration(getEnv("METRICS_HOURLY_RETENTION", "2160h")) // 90 days
+	metricsDailyRetention, _ := time.ParseDuration(getEnv("METRICS_DAILY_RETENTION", "17520h"))  // 2 years
+	alertResolvedRetention, _ := time.ParseDuration(getEnv("ALERT_RESOLVED_RETENTION", "2160h")) // 90 days
+	trivyTimeout, _ := time.ParseDuration(getEnv("TRIVY_TIMEOUT", "10m"))
+	infraDeletedRetention, _ := time.ParseDuration(getEnv("INFRA_DELETED_RETENTION", "720h")) // 30 days
+	driftDetectionInterval, _ := time.ParseDuration(getEnv("DRIFT_DETECTION_INTERVAL", "6h"))
+	providerCacheTTL, _ := time.ParseDuration(getEnv("PROVIDER_CACHE_TTL", "30s"))
+	providerCallTimeout, _ := time.ParseDuration(getEnv("PROVIDER_CALL_TIMEOUT", "30s"))
+	providerProvisionTimeout, _ := time.ParseDuration(getEnv("PROVIDER_PROVISION_TIMEOUT", "30m"))
+	costAnomalyThreshold, _ := strconv.ParseFloat(getEnv("COST_ANOMALY_THRESHOLD", "3"), 64)
+	auditBatchSize, _ := strconv.Atoi(getEnv("AUDIT_BATCH_SIZE", "100"))
+	auditFlushInterval, _ := time.ParseDuration(getEnv("AUDIT_FLUSH_INTERVAL", "5s"))
+	jobWorkers, _ := strconv.Atoi(getEnv("JOB_WORKERS", "4"))
+	jobRetention, _ := time.ParseDuration(getEnv("JOB_RETENTION", "168h"))          // 7 days
+	demoMetricsRange, _ := time.ParseDuration(getEnv("DEMO_METRICS_RANGE", "720h")) // 30 days
+	wsSendBuffer, _ := strconv.Atoi(getEnv("WS_SEND_BUFFER", "256"))
+	wsMaxConnectionsPerOrg, _ := strconv.Atoi(getEnv("WS_MAX_CONNECTIONS_PER_ORG", "100"))
+	passwordMinLength, _ := strconv.Atoi(getEnv("PASSWORD_MIN_LENGTH", "8"))
+
+	// Production origins must be configured; the development servers are only allowed by default
+	// outside production
+	var corsOrigins []string
+	if getEnv("NODE_ENV", "development") != "production" {
+		corsOrigins = defaultCORSOrigins
+	}
 
 	return &Config{
 		Environment: getEnv("NODE_ENV", "development"),
 		Port:        getEnv("PORT", "3001"),
+		LogLevel:    strings.ToLower(getEnv("LOG_LEVEL", "")),
+
+		ShutdownTimeout: shutdownTimeout,
 
 		// Database
 		DatabaseURL:      getEnv("DATABASE_URL", ""),
@@ -87,14 +252,100 @@ func Load() *Config {
 		DatabasePassword: getEnv("DB_PASSWORD", ""),
 		DatabaseSSLMode:  getEnv("DB_SSL_MODE", "disable"),
 
+		// Database connection pool
+		DatabaseMaxOpenConns:    dbMaxOpenConns,
+		DatabaseMaxIdleConns:    dbMaxIdleConns,
+		DatabaseConnMaxLifetime: dbConnMaxLifetime,
+
+		// Database startup connection retries
+		DatabaseConnectRetries:    dbConnectRetries,
+		DatabaseConnectRetryDelay: dbConnectRetryDelay,
+
+		// Database read replica
+		DatabaseReplicaHost: getEnv("DB_REPLICA_HOST", ""),
+		DatabaseReplicaPort: getEnv("DB_REPLICA_PORT", getEnv("DB_PORT", "5432")),
+
 		// JWT
-		JWTSecret:         getEnv("JWT_SECRET", "your-super-secret-jwt-key-that-should-be-changed-in-production"),
+		JWTSecret:         jwtSecret,
 		JWTExpirationTime: jwtExpiration,
 		JWTRefreshTime:    jwtRefreshExpiration,
+		JWTSigningKeys:    jwtSigningKeys,
+		JWTCurrentKeyID:   getEnv("JWT_CURRENT_KEY_ID", ""),
+		JWTKeysRotatedAt:  jwtKeysRotatedAt,
 
 		// Security
-		BCryptRounds: bcryptRounds,
-		CORSOrigins:  []string{getEnv("CORS_ORIGIN", "*")},
+		BCryptRounds:     bcryptRounds,
+		CORSOrigins:      getEnvSlice("CORS_ORIGIN", corsOrigins),
+		MFAEncryptionKey: getEnv("MFA_ENCRYPTION_KEY", "your-mfa-encryption-key-that-should-be-changed-in-production"),
+		MFAIssuer:        getEnv("MFA_ISSUER", "CloudWeave"),
+
+		// Password policy
+		PasswordMinLength:        passwordMinLength,
+		PasswordRequireUppercase: getEnvBool("PASSWORD_REQUIRE_UPPERCASE", true),
+		PasswordRequireLowercase: getEnvBool("PASSWORD_REQUIRE_LOWERCASE", true),
+		PasswordRequireDigit:     getEnvBool("PASSWORD_REQUIRE_DIGIT", true),
+		PasswordRequireSpecial:   getEnvBool("PASSWORD_REQUIRE_SPECIAL", true),
+		PasswordBreachCheck:      getEnvBool("PASSWORD_BREACH_CHECK", true),
+		PasswordBreachAPIURL:     getEnv("PASSWORD_BREACH_API_URL", "https://api.pwnedpasswords.com/range/"),
+		PasswordBreachListFile:   getEnv("PASSWORD_BREACH_LIST_FILE", ""),
+
+		// Rate limiting
+		RateLimitWindow: rateLimitWindow,
+		RateLimitAuth:   rateLimitAuth,
+		RateLimitRead:   rateLimitRead,
+		RateLimitWrite:  rateLimitWrite,
+
+		// Idempotency
+		IdempotencyKeyTTL: idempotencyKeyTTL,
+
+		// Metrics
+		MetricsRawRetention:    metricsRawRetention,
+		MetricsHourlyRetention: metricsHourlyRetention,
+		MetricsDailyRetention:  metricsDailyRetention,
+		AutoscalingExecution:   getEnvBool("AUTOSCALING_EXECUTION_ENABLED", false),
+
+		// Alerts
+		AlertResolvedRetention: alertResolvedRetention,
+
+		// Costs
+		CostAnomalyThreshold: costAnomalyThreshold,
+
+		// Security scanning
+		TrivyPath:    getEnv("TRIVY_PATH", "trivy"),
+		TrivyTimeout: trivyTimeout,
+
+		ProviderCacheTTL:         providerCacheTTL,
+		ProviderCallTimeout:      providerCallTimeout,
+		ProviderProvisionTimeout: providerProvisionTimeout,
+
+		// Infrastructure
+		InfrastructureDeletedRetention: infraDeletedRetention,
+		DriftDetectionInterval:         driftDetectionInterval,
+
+		// Audit
+		AuditBatchSize:     auditBatchSize,
+		AuditFlushInterval: auditFlushInterval,
+
+		// Background jobs
+		JobWorkers:   jobWorkers,
+		JobRetention: jobRetention,
+
+		// Demo data
+		DemoMetricsRange: demoMetricsRange,
+
+		// SMTP
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "alerts@cloudweave.local"),
+
+		EmailVerificationURL: getEnv("EMAIL_VERIFICATION_URL", "http://localhost:3001/api/v1/auth/verify-email"),
+		PasswordResetURL:     getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+
+		// WebSocket
+		WebSocketSendBuffer:           wsSendBuffer,
+		WebSocketMaxConnectionsPerOrg: wsMaxConnectionsPerOrg,
 
 		// SSO
 		SSO: loadSSOConfig(),
@@ -157,6 +408,28 @@ func loadSSOConfig() SSOConfig {
 	}
 }
 
+// parseJWTSigningKeys parses "kid:secret" entries, skipping malformed ones
+func parseJWTSigningKeys(entries []string) []JWTSigningKey {
+	var keys []JWTSigningKey
+	for _, entry := range entries {
+		id, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" || secret == "" {
+			continue
+		}
+		keys = append(keys, JWTSigningKey{ID: id, Secret: secret})
+	}
+	return keys
+}
+
+// ValidateJWTSigningKeys checks that JWT_KEYS_ROTATED_AT is set when JWT_SIGNING_KEYS is, since the
+// keys other than the current one retire relative to it
+func ValidateJWTSigningKeys(cfg *Config) error {
+	if len(cfg.JWTSigningKeys) > 0 && cfg.JWTKeysRotatedAt.IsZero() {
+		return fmt.Errorf("%w: JWT_KEYS_ROTATED_AT must be set to an RFC 3339 time when JWT_SIGNING_KEYS is configured", ErrInvalidConfig)
+	}
+	return nil
+}
+
 func getEnvBool(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
 		if parsed, err := strconv.ParseBool(value); err == nil {
@@ -168,7 +441,11 @@ func getEnvBool(key string, defaultValue bool) bool {
 
 func getEnvSlice(key string, defaultValue []string) []string {
 	if value := os.Getenv(key); value != "" {
-		return strings.Split(value, ",")
+		values := strings.Split(value, ",")
+		for i := range values {
+			values[i] = strings.TrimSpace(values[i])
+		}
+		return values
 	}
 	return defaultValue
 }
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// defaultCORSOrigins are the origins of the frontend's development servers, allowed outside
+// production when CORS_ORIGIN is not set
+var defaultCORSOrigins = []string{"http://localhost:5173", "http://localhost:5174", "http://localhost:5176", "http://localhost:3000"}
+
+// ValidateCORSOrigins checks that each origin is a scheme and host, such as https://app.example.com,
+// optionally with a wildcard subdomain, such as https://*.example.com. The API allows credentialed
+// requests, so an origin of "*" is rejected.
+func ValidateCORSOrigins(origins []string) error {
+	if len(origins) == 0 {
+		return fmt.Errorf("%w: CORS_ORIGIN must list at least one origin", ErrInvalidConfig)
+	}
+
+	for _, origin := range origins {
+		if origin == "*" {
+			return fmt.Errorf("%w: CORS origin \"*\" cannot be used with credentialed requests; list the allowed origins or use a wildcard subdomain such as https://*.example.com", ErrInvalidConfig)
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: CORS origin %q must be an http:// or https:// origin", ErrInvalidConfig, origin)
+		}
+		if u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+			return fmt.Errorf("%w: CORS origin %q must be a scheme and host without a path", ErrInvalidConfig, origin)
+		}
+
+		if strings.Contains(u.Host, "*") && (!strings.HasPrefix(u.Host, "*.") || strings.Count(u.Host, "*") > 1 || len(u.Host) <= len("*.")) {
+			return fmt.Errorf("%w: CORS origin %q may only use a wildcard as its leftmost subdomain, e.g. https://*.example.com", ErrInvalidConfig, origin)
+		}
+	}
+
+	return nil
+}
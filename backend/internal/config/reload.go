@@ -0,0 +1,214 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/joho/godotenv"
+)
+
+var (
+	// ErrNonReloadableChange is returned when a reload would change settings that only take
+	// effect on restart
+	ErrNonReloadableChange = errors.New("configuration change requires a restart")
+
+	// ErrInvalidConfig is returned when a reload would apply an invalid setting
+	ErrInvalidConfig = errors.New("invalid configuration")
+)
+
+// logLevels are the valid LOG_LEVEL values
+var logLevels = map[string]bool{"": true, "debug": true, "info": true, "warn": true, "error": true, "fatal": true}
+
+// reloadableFields are the settings a reload applies while the server runs
+var reloadableFields = map[string]bool{
+	"RateLimitWindow": true,
+	"RateLimitAuth":   true,
+	"RateLimitRead":   true,
+	"RateLimitWrite":  true,
+	"CORSOrigins":     true,
+	"LogLevel":        true,
+}
+
+// ReloadResult lists the settings a reload changed
+type ReloadResult struct {
+	Changed []string `json:"changed"`
+}
+
+// Reloader re-reads the configuration from the environment and its env file, and applies the
+// hot-reloadable settings through the functions registered with OnReload. Variables set in the
+// process environment at startup take precedence over the file, as they do when the server
+// starts.
+type Reloader struct {
+	mu        sync.Mutex
+	current   *Config
+	envFile   string
+	fileVars  map[string]string
+	processed map[string]bool
+	appliers  []func(cfg *Config)
+}
+
+// NewReloader creates a reloader for the configuration loaded at startup, re-reading envFile on
+// each reload. envFile may be empty or missing, in which case only the environment is re-read.
+func NewReloader(cfg *Config, envFile string) *Reloader {
+	fileVars, _ := readEnvFile(envFile)
+
+	// Variables whose value did not come from the file were set by the process environment
+	processed := make(map[string]bool)
+	for _, entry := range os.Environ() {
+		key, value, _ := strings.Cut(entry, "=")
+		if fileValue, ok := fileVars[key]; !ok || fileValue != value {
+			processed[key] = true
+		}
+	}
+
+	return &Reloader{
+		current:   cfg,
+		envFile:   envFile,
+		fileVars:  fileVars,
+		processed: processed,
+	}
+}
+
+// Current returns the configuration as of the latest successful reload
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// OnReload registers a function that applies the hot-reloadable settings of a reloaded
+// configuration
+func (r *Reloader) OnReload(apply func(cfg *Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.appliers = append(r.appliers, apply)
+}
+
+// Reload re-reads the configuration and applies it. If any setting that is not hot-reloadable
+// changed, nothing is applied and the error names those settings.
+func (r *Reloader) Reload() (*ReloadResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	fileVars, err := readEnvFile(r.envFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", r.envFile, err)
+	}
+
+	restore := r.setFileVars(fileVars)
+	next := Load()
+
+	changed, restartRequired := diffConfig(r.current, next)
+	if len(restartRequired) > 0 {
+		restore()
+		return nil, fmt.Errorf("%w: %s changed; restart the server to apply them", ErrNonReloadableChange, strings.Join(restartRequired, ", "))
+	}
+	if err := validateReloadable(next); err != nil {
+		restore()
+		return nil, err
+	}
+
+	r.current = next
+	r.fileVars = fileVars
+	for _, apply := range r.appliers {
+		apply(next)
+	}
+
+	return &ReloadResult{Changed: changed}, nil
+}
+
+// setFileVars sets the environment to the env file's variables, leaving variables of the process
+// environment alone, and returns a function that undoes the change
+func (r *Reloader) setFileVars(fileVars map[string]string) (restore func()) {
+	previous := make(map[string]*string)
+	remember := func(key string) {
+		if _, ok := previous[key]; ok {
+			return
+		}
+		if value, ok := os.LookupEnv(key); ok {
+			previous[key] = &value
+		} else {
+			previous[key] = nil
+		}
+	}
+
+	for key, value := range fileVars {
+		if !r.processed[key] {
+			remember(key)
+			os.Setenv(key, value)
+		}
+	}
+	for key := range r.fileVars {
+		if _, ok := fileVars[key]; !ok && !r.processed[key] {
+			remember(key)
+			os.Unsetenv(key)
+		}
+	}
+
+	return func() {
+		for key, value := range previous {
+			if value == nil {
+				os.Unsetenv(key)
+			} else {
+				os.Setenv(key, *value)
+			}
+		}
+	}
+}
+
+// diffConfig returns the names of the settings that differ between two configurations, split
+// into hot-reloadable settings and settings that need a restart
+func diffConfig(current, next *Config) (changed, restartRequired []string) {
+	currentValue := reflect.ValueOf(current).Elem()
+	nextValue := reflect.ValueOf(next).Elem()
+	configType := currentValue.Type()
+
+	for i := 0; i < configType.NumField(); i++ {
+		name := configType.Field(i).Name
+		if reflect.DeepEqual(currentValue.Field(i).Interface(), nextValue.Field(i).Interface()) {
+			continue
+		}
+		if reloadableFields[name] {
+			changed = append(changed, name)
+		} else {
+			restartRequired = append(restartRequired, name)
+		}
+	}
+
+	sort.Strings(changed)
+	sort.Strings(restartRequired)
+	return changed, restartRequired
+}
+
+// validateReloadable checks the hot-reloadable settings, so a reload never applies a setting the
+// server cannot use
+func validateReloadable(cfg *Config) error {
+	if !logLevels[cfg.LogLevel] {
+		return fmt.Errorf("%w: LOG_LEVEL must be debug, info, warn, error or fatal", ErrInvalidConfig)
+	}
+	if err := ValidateCORSOrigins(cfg.CORSOrigins); err != nil {
+		return err
+	}
+	if cfg.RateLimitWindow <= 0 {
+		return fmt.Errorf("%w: RATE_LIMIT_WINDOW must be a positive duration", ErrInvalidConfig)
+	}
+	return nil
+}
+
+// readEnvFile reads the variables of an env file, which may be missing
+func readEnvFile(path string) (map[string]string, error) {
+	if path == "" {
+		return map[string]string{}, nil
+	}
+
+	vars, err := godotenv.Read(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	return vars, err
+}
@@ -1,9 +1,17 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
@@ -12,9 +20,24 @@ import (
 	_ "github.com/lib/pq"
 )
 
+// migrationsDir is where the SQL migration files are read from
+const migrationsDir = "./migrations"
+
+// migrationFilePattern matches up migration files, e.g. 000001_initial_schema.up.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+// ErrDatabaseUnavailable is returned when the database cannot be reached after every connection attempt
+var ErrDatabaseUnavailable = errors.New("database unavailable")
+
+// errMigratorUnavailable is returned by migration methods on a database created with Open
+var errMigratorUnavailable = errors.New("migrations are not available on a database opened without a connection")
+
 type Database struct {
 	DB       *sql.DB
 	migrator *migrate.Migrate
+
+	// available reports whether the database answered the most recent connectivity check
+	available atomic.Bool
 }
 
 type Config struct {
@@ -24,31 +47,75 @@ type Config struct {
 	Password string
 	DBName   string
 	SSLMode  string
+
+	// Connection pool sizing; zero values use the defaults below
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+
+	// Initial connection attempts and the delay before the first retry; zero values use the defaults below
+	ConnectRetries    int
+	ConnectRetryDelay time.Duration
 }
 
-// NewDatabase creates a new database connection with migration support
-func NewDatabase(config Config) (*Database, error) {
-	// Build connection string
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+// Default connection pool sizing
+const (
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = 5
+	DefaultConnMaxLifetime = 5 * time.Minute
+)
 
-	log.Printf("DEBUG: Using DSN: %s", dsn)
+// Connection retry defaults
+const (
+	DefaultConnectRetries    = 5
+	DefaultConnectRetryDelay = time.Second
+	maxConnectRetryDelay     = 30 * time.Second
+	pingTimeout              = 5 * time.Second
+)
 
-	// Open database connection
-	db, err := sql.Open("postgres", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open database connection: %w", err)
+// PoolStats is the JSON form of the connection pool statistics
+type PoolStats struct {
+	MaxOpenConnections int   `json:"maxOpenConnections"`
+	OpenConnections    int   `json:"openConnections"`
+	InUse              int   `json:"inUse"`
+	Idle               int   `json:"idle"`
+	WaitCount          int64 `json:"waitCount"`
+	WaitDurationMs     int64 `json:"waitDurationMs"`
+	MaxIdleClosed      int64 `json:"maxIdleClosed"`
+	MaxIdleTimeClosed  int64 `json:"maxIdleTimeClosed"`
+	MaxLifetimeClosed  int64 `json:"maxLifetimeClosed"`
+}
+
+// NewPoolStats converts database/sql pool statistics to PoolStats
+func NewPoolStats(stats sql.DBStats) PoolStats {
+	return PoolStats{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
 	}
+}
 
-	// Configure connection pool
-	db.SetMaxOpenConns(25)
-	db.SetMaxIdleConns(5)
-	db.SetConnMaxLifetime(5 * time.Minute)
+// NewDatabase creates a new database connection with migration support. The first connection is
+// retried with exponential backoff; ErrDatabaseUnavailable is returned if every attempt fails.
+func NewDatabase(config Config) (*Database, error) {
+	d, err := Open(config)
+	if err != nil {
+		return nil, err
+	}
 
-	// Test connection
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+	if err := waitForDatabase(d.DB, config.ConnectRetries, config.ConnectRetryDelay); err != nil {
+		d.DB.Close()
+		return nil, err
 	}
+	d.available.Store(true)
+
+	db := d.DB
 
 	// Debug: Test query to see what database we're connected to
 	var dbName, currentUser, currentSchema string
@@ -71,26 +138,95 @@ func NewDatabase(config Config) (*Database, error) {
 	// Setup migrator
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to create postgres driver: %w", err)
 	}
 
 	migrator, err := migrate.NewWithDatabaseInstance(
-		"file://./migrations",
+		"file://"+migrationsDir,
 		"postgres",
 		driver,
 	)
 	if err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to create migrator: %w", err)
 	}
 
-	return &Database{
-		DB:       db,
-		migrator: migrator,
-	}, nil
+	d.migrator = migrator
+
+	return d, nil
+}
+
+// Open creates the connection pool without connecting to the database, so the server can start
+// while the database is down and recover once it is reachable. Migrations are not available on a
+// database created this way.
+func Open(config Config) (*Database, error) {
+	// Build connection string
+	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		config.Host, config.Port, config.User, config.Password, config.DBName, config.SSLMode)
+
+	log.Printf("DEBUG: Using DSN: %s", dsn)
+
+	// Open database connection
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	// Configure connection pool
+	maxOpenConns := config.MaxOpenConns
+	if maxOpenConns <= 0 {
+		maxOpenConns = DefaultMaxOpenConns
+	}
+	maxIdleConns := config.MaxIdleConns
+	if maxIdleConns <= 0 {
+		maxIdleConns = DefaultMaxIdleConns
+	}
+	connMaxLifetime := config.ConnMaxLifetime
+	if connMaxLifetime <= 0 {
+		connMaxLifetime = DefaultConnMaxLifetime
+	}
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetConnMaxLifetime(connMaxLifetime)
+	log.Printf("Database pool: max open %d, max idle %d, max lifetime %s", maxOpenConns, maxIdleConns, connMaxLifetime)
+
+	return &Database{DB: db}, nil
+}
+
+// waitForDatabase pings the database until it answers, doubling the delay between attempts
+func waitForDatabase(db *sql.DB, attempts int, delay time.Duration) error {
+	if attempts <= 0 {
+		attempts = DefaultConnectRetries
+	}
+	if delay <= 0 {
+		delay = DefaultConnectRetryDelay
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		err = db.PingContext(ctx)
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		if attempt < attempts {
+			log.Printf("Database not reachable (attempt %d/%d): %v; retrying in %s", attempt, attempts, err, delay)
+			time.Sleep(delay)
+			delay = min(delay*2, maxConnectRetryDelay)
+		}
+	}
+
+	return fmt.Errorf("%w after %d attempts: %v", ErrDatabaseUnavailable, attempts, err)
 }
 
 // Migrate runs all pending migrations
 func (d *Database) Migrate() error {
+	if d.migrator == nil {
+		return errMigratorUnavailable
+	}
 	if err := d.migrator.Up(); err != nil && err != migrate.ErrNoChange {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
@@ -100,6 +236,9 @@ func (d *Database) Migrate() error {
 
 // MigrateDown rolls back the last migration
 func (d *Database) MigrateDown() error {
+	if d.migrator == nil {
+		return errMigratorUnavailable
+	}
 	if err := d.migrator.Steps(-1); err != nil {
 		return fmt.Errorf("failed to rollback migration: %w", err)
 	}
@@ -107,8 +246,83 @@ func (d *Database) MigrateDown() error {
 	return nil
 }
 
+// Force sets the migration version without running any migration and clears the dirty flag.
+// A version of -1 means no migration has been applied.
+func (d *Database) Force(version int) error {
+	if d.migrator == nil {
+		return errMigratorUnavailable
+	}
+	if err := d.migrator.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version: %w", err)
+	}
+	log.Printf("Migration version forced to %d", version)
+	return nil
+}
+
+// MigrationStatus describes one migration file and whether the database has applied it
+type MigrationStatus struct {
+	Version    uint
+	Name       string
+	Applied    bool
+	Dirty      bool
+	ModifiedAt time.Time
+}
+
+// Status lists every migration file with its applied or pending state. The schema_migrations
+// table only records the latest applied version, so every migration up to it counts as applied.
+func (d *Database) Status() ([]MigrationStatus, error) {
+	if d.migrator == nil {
+		return nil, errMigratorUnavailable
+	}
+	current, dirty, err := d.migrator.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("failed to get migration version: %w", err)
+	}
+	hasVersion := err == nil
+
+	entries, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var statuses []MigrationStatus
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		info, err := os.Stat(filepath.Join(migrationsDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat migration %s: %w", entry.Name(), err)
+		}
+
+		statuses = append(statuses, MigrationStatus{
+			Version:    uint(version),
+			Name:       match[2],
+			Applied:    hasVersion && uint(version) <= current,
+			Dirty:      hasVersion && dirty && uint(version) == current,
+			ModifiedAt: info.ModTime(),
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+
+	return statuses, nil
+}
+
 // GetVersion returns the current migration version
 func (d *Database) GetVersion() (uint, bool, error) {
+	if d.migrator == nil {
+		return 0, false, errMigratorUnavailable
+	}
 	return d.migrator.Version()
 }
 
@@ -129,3 +343,36 @@ func (d *Database) Close() error {
 func (d *Database) Health() error {
 	return d.DB.Ping()
 }
+
+// Available reports whether the database answered the most recent connectivity check
+func (d *Database) Available() bool {
+	return d.available.Load()
+}
+
+// StartAvailabilityMonitor pings the database every interval until ctx is cancelled, keeping
+// Available up to date and logging when the database goes down or recovers
+func (d *Database) StartAvailabilityMonitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+			err := d.DB.PingContext(pingCtx)
+			cancel()
+
+			available := err == nil
+			if d.available.Swap(available) == available {
+				continue
+			}
+			if available {
+				log.Println("Database connection recovered")
+			} else {
+				log.Printf("Database connection lost: %v", err)
+			}
+		}
+	}
+}
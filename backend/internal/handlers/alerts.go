@@ -1,10 +1,13 @@
 package handlers
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"cloudweave/internal/models"
 	"cloudweave/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -97,21 +100,52 @@ func (h *AlertsHandler) GetAlertSummary(c *gin.Context) {
 	c.JSON(http.StatusOK, mockSummary)
 }
 
+// GetAlert retrieves a single alert with its state-change history
+func (h *AlertsHandler) GetAlert(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	alert, err := h.alertService.GetAlertDetail(c.Request.Context(), orgID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrAlertNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"alert": alert})
+}
+
 // AcknowledgeAlert acknowledges an alert
 func (h *AlertsHandler) AcknowledgeAlert(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
 	alertID := c.Param("id")
 	if alertID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "alert ID is required"})
 		return
 	}
 
-	userID := c.GetString("user_id")
+	userID := c.GetString("userID")
 	if userID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
 		return
 	}
 
-	if err := h.alertService.AcknowledgeAlert(c.Request.Context(), alertID, userID); err != nil {
+	if err := h.alertService.AcknowledgeAlert(c.Request.Context(), orgID, alertID, userID); err != nil {
+		if errors.Is(err, services.ErrAlertNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -121,6 +155,12 @@ func (h *AlertsHandler) AcknowledgeAlert(c *gin.Context) {
 
 // UpdateAlertStatus updates the status of an alert
 func (h *AlertsHandler) UpdateAlertStatus(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
 	alertID := c.Param("id")
 	if alertID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "alert ID is required"})
@@ -136,7 +176,15 @@ func (h *AlertsHandler) UpdateAlertStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.alertService.UpdateAlertStatus(c.Request.Context(), alertID, req.Status); err != nil {
+	if err := h.alertService.UpdateAlertStatus(c.Request.Context(), orgID, alertID, req.Status, c.GetString("userID")); err != nil {
+		if errors.Is(err, services.ErrInvalidAlertStatus) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrAlertNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -144,15 +192,76 @@ func (h *AlertsHandler) UpdateAlertStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "alert status updated"})
 }
 
+// BulkAcknowledgeAlerts acknowledges the alerts selected by ID or filter, reporting each one's outcome
+func (h *AlertsHandler) BulkAcknowledgeAlerts(c *gin.Context) {
+	h.bulkUpdateAlerts(c, h.alertService.BulkAcknowledgeAlerts)
+}
+
+// BulkResolveAlerts resolves the alerts selected by ID or filter, reporting each one's outcome
+func (h *AlertsHandler) BulkResolveAlerts(c *gin.Context) {
+	h.bulkUpdateAlerts(c, h.alertService.BulkResolveAlerts)
+}
+
+func (h *AlertsHandler) bulkUpdateAlerts(c *gin.Context, update func(ctx context.Context, orgID, userID string, req *models.BulkAlertRequest) ([]models.BulkAlertResult, error)) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	var req models.BulkAlertRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	results, err := update(c.Request.Context(), orgID, c.GetString("userID"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulkRequest) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
 // CreateAlertRule creates a new alert rule
 func (h *AlertsHandler) CreateAlertRule(c *gin.Context) {
-	var rule services.AlertRule
-	if err := c.ShouldBindJSON(&rule); err != nil {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	var req models.CreateAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	if err := h.alertService.CreateAlertRule(c.Request.Context(), &rule); err != nil {
+	rule := &models.AlertRule{
+		OrganizationID:  orgID,
+		Name:            req.Name,
+		Description:     req.Description,
+		ResourceType:    req.ResourceType,
+		MetricName:      req.MetricName,
+		Operator:        req.Operator,
+		Threshold:       req.Threshold,
+		DurationSeconds: req.DurationSeconds,
+		Severity:        req.Severity,
+		Enabled:         true,
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if userID := c.GetString("userID"); userID != "" {
+		rule.CreatedBy = &userID
+	}
+
+	if err := h.alertService.CreateAlertRule(c.Request.Context(), rule); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -160,6 +269,40 @@ func (h *AlertsHandler) CreateAlertRule(c *gin.Context) {
 	c.JSON(http.StatusCreated, gin.H{"message": "alert rule created", "rule": rule})
 }
 
+// GetAlertRules lists the organization's alert rules
+func (h *AlertsHandler) GetAlertRules(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	rules, err := h.alertService.GetAlertRules(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// DeleteAlertRule deletes an alert rule
+func (h *AlertsHandler) DeleteAlertRule(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	ruleID := c.Param("id")
+	if err := h.alertService.DeleteAlertRule(c.Request.Context(), ruleID, orgID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "alert rule deleted"})
+}
+
 // Helper function to parse integer query parameters
 func parseIntQuery(c *gin.Context, key string, defaultValue int) int {
 	if str := c.Query(key); str != "" {
@@ -1,48 +1,123 @@
 package handlers
 
 import (
-	"encoding/csv"
 	"fmt"
+	"log"
 	"net/http"
 	"time"
 
 	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
 	"cloudweave/internal/services"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Audit log page sizes
+const (
+	defaultAuditLogPageSize = 50
+	maxAuditLogPageSize     = 500
+)
+
 type AuditHandler struct {
 	auditService *services.AuditService
+	orgRepo      repositories.OrganizationRepositoryInterface
 }
 
-func NewAuditHandler(auditService *services.AuditService) *AuditHandler {
-	return &AuditHandler{auditService: auditService}
+func NewAuditHandler(auditService *services.AuditService, orgRepo repositories.OrganizationRepositoryInterface) *AuditHandler {
+	return &AuditHandler{auditService: auditService, orgRepo: orgRepo}
 }
 
-func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+// auditDateFormat is the date-only form startDate and endDate accept besides RFC 3339 timestamps
+const auditDateFormat = "2006-01-02"
+
+// bindAuditLogQuery binds audit log filters from the query string. startDate and endDate take an
+// RFC 3339 timestamp or a date, where a date endDate includes the whole day. The time range
+// defaults to the defaultRange before now.
+func bindAuditLogQuery(c *gin.Context, defaultRange time.Duration) (models.AuditLogQuery, error) {
 	var query models.AuditLogQuery
 	if err := c.ShouldBindQuery(&query); err != nil {
+		return query, err
+	}
+
+	// Empty filters match everything rather than only empty values
+	for _, filter := range []**string{&query.UserID, &query.Action, &query.ResourceType, &query.ResourceID, &query.ImpersonatedBy} {
+		if *filter != nil && **filter == "" {
+			*filter = nil
+		}
+	}
+
+	now := time.Now()
+	query.EndTime = now
+	if endDate := c.Query("endDate"); endDate != "" {
+		endTime, err := time.Parse(time.RFC3339, endDate)
+		if err != nil {
+			date, dateErr := time.Parse(auditDateFormat, endDate)
+			if dateErr != nil {
+				return query, fmt.Errorf("invalid endDate %q: use RFC 3339 or YYYY-MM-DD", endDate)
+			}
+			endTime = date.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		query.EndTime = endTime
+	}
+
+	query.StartTime = query.EndTime.Add(-defaultRange)
+	if startDate := c.Query("startDate"); startDate != "" {
+		startTime, err := time.Parse(time.RFC3339, startDate)
+		if err != nil {
+			if startTime, err = time.Parse(auditDateFormat, startDate); err != nil {
+				return query, fmt.Errorf("invalid startDate %q: use RFC 3339 or YYYY-MM-DD", startDate)
+			}
+		}
+		query.StartTime = startTime
+	}
+
+	if query.StartTime.After(query.EndTime) {
+		return query, fmt.Errorf("startDate must not be after endDate")
+	}
+
+	return query, nil
+}
+
+// GetAuditLogs lists the organization's audit logs, filtered by user, action, resource and time
+// range, with the total number matching for pagination
+func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
+	query, err := bindAuditLogQuery(c, 24*time.Hour)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	// Set default time range if not provided
-	if query.StartTime.IsZero() {
-		query.StartTime = time.Now().Add(-24 * time.Hour)
+	if query.Limit <= 0 || query.Limit > maxAuditLogPageSize {
+		query.Limit = defaultAuditLogPageSize
 	}
-	if query.EndTime.IsZero() {
-		query.EndTime = time.Now()
+	if query.Sort == "" {
+		query.Sort = models.AuditSortDesc
 	}
 
-	orgID, _ := c.Get("organizationId")
-	logs, err := h.auditService.Query(c, orgID.(string), query)
+	orgID := c.GetString("organizationId")
+	logs, err := h.auditService.Query(c, orgID, query)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, logs)
+	total, err := h.auditService.Count(c, orgID, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if logs == nil {
+		logs = []*models.AuditLog{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"logs":   logs,
+		"total":  total,
+		"limit":  query.Limit,
+		"offset": query.Offset,
+		"sort":   query.Sort,
+	})
 }
 
 func (h *AuditHandler) GetComplianceReport(c *gin.Context) {
@@ -122,85 +197,59 @@ func (h *AuditHandler) CleanupOldLogs(c *gin.Context) {
 }
 
 func (h *AuditHandler) ExportAuditLogs(c *gin.Context) {
-	var query models.AuditLogQuery
-	if err := c.ShouldBindQuery(&query); err != nil {
+	query, err := bindAuditLogQuery(c, 30*24*time.Hour)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Set default time range if not provided
-	if query.StartTime.IsZero() {
-		query.StartTime = time.Now().Add(-30 * 24 * time.Hour) // Default to 30 days ago
-	}
-	if query.EndTime.IsZero() {
-		query.EndTime = time.Now()
+	format := c.DefaultQuery("format", services.AuditExportCSV)
+	var contentType string
+	switch format {
+	case services.AuditExportJSON:
+		contentType = "application/json"
+	case services.AuditExportCSV:
+		contentType = "text/csv"
+	case services.AuditExportPDF:
+		contentType = "application/pdf"
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of json, csv or pdf"})
+		return
 	}
 
 	orgID, _ := c.Get("organizationId")
-	logs, err := h.auditService.Query(c, orgID.(string), query)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+
+	orgName := orgID.(string)
+	if format == services.AuditExportPDF {
+		org, err := h.orgRepo.GetByID(c, orgID.(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		orgName = org.Name
 	}
 
-	// Set headers for CSV download
-	filename := fmt.Sprintf("audit_logs_%s_to_%s.csv",
+	filename := fmt.Sprintf("audit_logs_%s_to_%s.%s",
 		query.StartTime.Format("2006-01-02"),
-		query.EndTime.Format("2006-01-02"))
-	c.Header("Content-Type", "text/csv")
+		query.EndTime.Format("2006-01-02"),
+		format)
+	c.Header("Content-Type", contentType)
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
 
-	// Create CSV writer
-	writer := csv.NewWriter(c.Writer)
-	defer writer.Flush()
-
-	// Write CSV header
-	header := []string{"ID", "Timestamp", "User ID", "Action", "Resource Type", "Resource ID", "IP Address", "User Agent", "Details"}
-	if err := writer.Write(header); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write CSV header"})
-		return
+	// Logs are streamed in batches, so once writing starts errors can only be logged
+	switch format {
+	case services.AuditExportJSON:
+		err = h.auditService.ExportJSON(c, c.Writer, orgID.(string), query)
+	case services.AuditExportCSV:
+		err = h.auditService.ExportCSV(c, c.Writer, orgID.(string), query)
+	case services.AuditExportPDF:
+		err = h.auditService.ExportPDF(c, c.Writer, orgName, orgID.(string), query)
 	}
-
-	// Write audit log data
-	for _, log := range logs {
-		var userID, resourceType, resourceID, ipAddress, userAgent string
-
-		if log.UserID != nil {
-			userID = *log.UserID
-		}
-		if log.ResourceType != nil {
-			resourceType = *log.ResourceType
-		}
-		if log.ResourceID != nil {
-			resourceID = *log.ResourceID
-		}
-		if log.IPAddress != nil {
-			ipAddress = log.IPAddress.String()
-		}
-		if log.UserAgent != nil {
-			userAgent = *log.UserAgent
-		}
-
-		details := ""
-		if log.Details != nil {
-			details = fmt.Sprintf("%v", log.Details)
-		}
-
-		record := []string{
-			log.ID,
-			log.CreatedAt.Format(time.RFC3339),
-			userID,
-			log.Action,
-			resourceType,
-			resourceID,
-			ipAddress,
-			userAgent,
-			details,
-		}
-
-		if err := writer.Write(record); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to write CSV record"})
+	if err != nil {
+		if !c.Writer.Written() {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		log.Printf("Failed to export audit logs for organization %s: %v", orgID, err)
 	}
 }
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -19,20 +20,35 @@ var (
 	jwtService   *services.JWTService
 	authService  *services.AuthService
 	ssoService   *services.SSOService
+	mfaService   *services.MFAService
 	auditService *services.AuditService
+
+	oauthStateService *services.OAuthStateService
+	secureCookies     bool
+)
+
+const (
+	// oauthStateCookie keeps the OAuth state in the browser that started the login, so the
+	// callback can only be completed by that browser
+	oauthStateCookie     = "oauth_state"
+	oauthStateCookiePath = "/api/v1/auth/sso/oauth"
 )
 
 // InitializeAuthServices initializes the authentication services
 func InitializeAuthServices(cfg *config.Config, db *database.Database, as *services.AuditService) {
 	blacklistService := services.NewTokenBlacklistService(db.DB)
 	jwtService = services.NewJWTService(cfg, blacklistService)
-	passwordService := services.NewPasswordService()
+	passwordService := services.NewPasswordService(cfg)
 	userRepo := repositories.NewUserRepository(db.DB)
 	orgRepo := repositories.NewOrganizationRepository(db.DB)
 
-	authService = services.NewAuthService(userRepo, orgRepo, jwtService, passwordService, blacklistService)
-	ssoService = services.NewSSOService(cfg, userRepo, orgRepo, authService, jwtService)
+	mfaService = services.NewMFAService(cfg, userRepo)
+	authService = services.NewAuthService(userRepo, orgRepo, jwtService, passwordService, blacklistService, mfaService)
+	authService.SetPasswordReset(services.NewPasswordResetTokenService(db.DB), cfg.PasswordResetURL)
+	oauthStateService = services.NewOAuthStateService(db.DB)
+	ssoService = services.NewSSOService(cfg, userRepo, orgRepo, authService, jwtService, oauthStateService)
 	auditService = as
+	secureCookies = cfg.Environment == "production"
 }
 
 // GetJWTService returns the initialized JWT service
@@ -40,6 +56,21 @@ func GetJWTService() *services.JWTService {
 	return jwtService
 }
 
+// GetOAuthStateService returns the initialized OAuth state service
+func GetOAuthStateService() *services.OAuthStateService {
+	return oauthStateService
+}
+
+// setOAuthStateCookie stores the OAuth state in the browser. A negative maxAge removes it.
+func setOAuthStateCookie(c *gin.Context, state string, maxAge int) {
+	if secureCookies {
+		c.SetSameSite(http.SameSiteNoneMode)
+	} else {
+		c.SetSameSite(http.SameSiteLaxMode)
+	}
+	c.SetCookie(oauthStateCookie, state, maxAge, oauthStateCookiePath, "", secureCookies, true)
+}
+
 // GetAuthService returns the initialized auth service
 func GetAuthService() *services.AuthService {
 	return authService
@@ -164,6 +195,16 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	if response.MFARequired {
+		log.Printf("Password accepted for user %s, MFA code required", req.Email)
+		c.JSON(http.StatusOK, models.ApiResponse{
+			Success:   true,
+			Data:      response,
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
 	log.Printf("Login successful for user: %s", req.Email)
 
 	// Audit log
@@ -237,6 +278,7 @@ func Register(c *gin.Context) {
 			Error: &models.ApiError{
 				Code:      errorCode,
 				Message:   errorMessage,
+				Details:   passwordPolicyViolations(err),
 				Timestamp: time.Now(),
 			},
 			RequestID: c.GetString("requestID"),
@@ -282,10 +324,13 @@ func RefreshToken(c *gin.Context) {
 		errorCode := "INVALID_REFRESH_TOKEN"
 		errorMessage := "Invalid or expired refresh token"
 
-		if strings.Contains(err.Error(), "user not found") {
+		if errors.Is(err, services.ErrTokenReused) {
+			errorCode = "REFRESH_TOKEN_REUSED"
+			errorMessage = "Refresh token has already been used, please log in again"
+		} else if strings.Contains(err.Error(), "user not found") {
 			errorCode = "USER_NOT_FOUND"
 			errorMessage = "User associated with token no longer exists"
-		} else if strings.Contains(err.Error(), "generate") {
+		} else if strings.Contains(err.Error(), "generate") || strings.Contains(err.Error(), "rotate") {
 			statusCode = http.StatusInternalServerError
 			errorCode = "TOKEN_GENERATION_ERROR"
 			errorMessage = "Failed to generate new tokens"
@@ -436,7 +481,7 @@ func ChangePassword(c *gin.Context) {
 
 	var req struct {
 		CurrentPassword string `json:"currentPassword" binding:"required"`
-		NewPassword     string `json:"newPassword" binding:"required,min=8"`
+		NewPassword     string `json:"newPassword" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -470,6 +515,7 @@ func ChangePassword(c *gin.Context) {
 			Error: &models.ApiError{
 				Code:      errorCode,
 				Message:   err.Error(),
+				Details:   passwordPolicyViolations(err),
 				Timestamp: time.Now(),
 			},
 			RequestID: c.GetString("requestID"),
@@ -530,6 +576,323 @@ func LogoutAllDevices(c *gin.Context) {
 	})
 }
 
+// VerifyEmail handles the link sent in verification emails
+func VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "VALIDATION_ERROR",
+				Message:   "Verification token is required",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	user, err := authService.VerifyEmail(c.Request.Context(), token)
+	if err != nil {
+		log.Printf("Email verification failed: %v", err)
+
+		statusCode := http.StatusInternalServerError
+		errorCode := "EMAIL_VERIFICATION_FAILED"
+		message := "Failed to verify email address"
+		if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrExpiredToken) {
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_VERIFICATION_TOKEN"
+			message = "Verification link is invalid or has expired"
+		}
+
+		c.JSON(statusCode, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      errorCode,
+				Message:   message,
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	log.Printf("Email verified for user: %s", user.ID)
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success:   true,
+		Data:      user,
+		RequestID: c.GetString("requestID"),
+	})
+}
+
+// ResendVerificationEmail sends the current user a new verification link
+func ResendVerificationEmail(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "UNAUTHORIZED",
+				Message:   "Authentication required",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	userIDStr := userID.(string)
+	if err := authService.ResendVerificationEmail(c.Request.Context(), userIDStr); err != nil {
+		log.Printf("Resending verification email failed for user %s: %v", userIDStr, err)
+
+		statusCode := http.StatusInternalServerError
+		errorCode := "VERIFICATION_EMAIL_FAILED"
+		message := "Failed to send verification email"
+		switch {
+		case errors.Is(err, services.ErrEmailAlreadyVerified):
+			statusCode = http.StatusConflict
+			errorCode = "EMAIL_ALREADY_VERIFIED"
+			message = err.Error()
+		case errors.Is(err, services.ErrVerificationEmailRateLimited):
+			statusCode = http.StatusTooManyRequests
+			errorCode = "VERIFICATION_EMAIL_RATE_LIMITED"
+			message = err.Error()
+		case errors.Is(err, services.ErrEmailVerificationDisabled):
+			statusCode = http.StatusServiceUnavailable
+			errorCode = "EMAIL_VERIFICATION_DISABLED"
+			message = err.Error()
+		}
+
+		c.JSON(statusCode, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      errorCode,
+				Message:   message,
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": "Verification email sent",
+		},
+		RequestID: c.GetString("requestID"),
+	})
+}
+
+// passwordPolicyViolations returns the password policy rules reported by err, for the frontend to
+// show which requirements a new password does not meet
+func passwordPolicyViolations(err error) interface{} {
+	var policyErr *services.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return policyErr.Violations
+	}
+	return nil
+}
+
+// ForgotPassword emails a password reset link. It responds the same way whether or not the email
+// address belongs to an account.
+func ForgotPassword(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required,email"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "VALIDATION_ERROR",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	if err := authService.ForgotPassword(c.Request.Context(), req.Email); err != nil {
+		log.Printf("Password reset request failed: %v", err)
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": "If an account exists for this email address, a password reset link has been sent",
+		},
+		RequestID: c.GetString("requestID"),
+	})
+}
+
+// ResetPassword sets a new password using the token from a password reset email
+func ResetPassword(c *gin.Context) {
+	var req struct {
+		Token       string `json:"token" binding:"required"`
+		NewPassword string `json:"newPassword" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "VALIDATION_ERROR",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	if err := authService.ResetPassword(c.Request.Context(), req.Token, req.NewPassword); err != nil {
+		log.Printf("Password reset failed: %v", err)
+
+		statusCode := http.StatusInternalServerError
+		errorCode := "PASSWORD_RESET_FAILED"
+		message := "Failed to reset password"
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			statusCode = http.StatusBadRequest
+			errorCode = "INVALID_RESET_TOKEN"
+			message = err.Error()
+		} else if strings.Contains(err.Error(), "validation failed") {
+			statusCode = http.StatusBadRequest
+			errorCode = "PASSWORD_VALIDATION_ERROR"
+			message = err.Error()
+		}
+
+		c.JSON(statusCode, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      errorCode,
+				Message:   message,
+				Details:   passwordPolicyViolations(err),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"message": "Password reset successfully",
+		},
+		RequestID: c.GetString("requestID"),
+	})
+}
+
+// ListOrganizations lists the organizations the current user belongs to
+func ListOrganizations(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "UNAUTHORIZED",
+				Message:   "Authentication required",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	userIDStr := userID.(string)
+	memberships, err := authService.ListOrganizations(c.Request.Context(), userIDStr)
+	if err != nil {
+		log.Printf("Failed to list organizations for user %s: %v", userIDStr, err)
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "LIST_ORGANIZATIONS_FAILED",
+				Message:   "Failed to list organizations",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"organizations":         memberships,
+			"currentOrganizationId": c.GetString("organizationId"),
+		},
+		RequestID: c.GetString("requestID"),
+	})
+}
+
+// SwitchOrganization issues tokens scoped to another organization the current user belongs to
+func SwitchOrganization(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "UNAUTHORIZED",
+				Message:   "Authentication required",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	var req models.SwitchOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "VALIDATION_ERROR",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	userIDStr := userID.(string)
+	response, err := authService.SwitchOrganization(c.Request.Context(), userIDStr, req.OrganizationID)
+	if err != nil {
+		log.Printf("Organization switch failed for user %s: %v", userIDStr, err)
+
+		statusCode := http.StatusInternalServerError
+		errorCode := "SWITCH_ORGANIZATION_FAILED"
+		message := "Failed to switch organization"
+		if errors.Is(err, services.ErrNotOrganizationMember) {
+			statusCode = http.StatusForbidden
+			errorCode = "NOT_ORGANIZATION_MEMBER"
+			message = err.Error()
+		}
+
+		c.JSON(statusCode, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      errorCode,
+				Message:   message,
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	log.Printf("User %s switched to organization %s", userIDStr, req.OrganizationID)
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success:   true,
+		Data:      response,
+		RequestID: c.GetString("requestID"),
+	})
+}
+
 // GetSSOConfig returns the SSO configuration
 func GetSSOConfig(c *gin.Context) {
 	config := ssoService.GetSSOConfig()
@@ -557,7 +920,7 @@ func InitiateOAuthLogin(c *gin.Context) {
 		return
 	}
 
-	authURL, err := ssoService.GetOAuthAuthURL(req.Provider, req.State)
+	authURL, state, err := ssoService.GetOAuthAuthURL(c.Request.Context(), req.Provider, req.OrganizationID)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, models.ApiResponse{
 			Success: false,
@@ -572,6 +935,8 @@ func InitiateOAuthLogin(c *gin.Context) {
 		return
 	}
 
+	setOAuthStateCookie(c, state, int(services.OAuthStateTTL.Seconds()))
+
 	c.JSON(http.StatusOK, models.ApiResponse{
 		Success: true,
 		Data: map[string]string{
@@ -598,10 +963,13 @@ func HandleOAuthCallback(c *gin.Context) {
 		return
 	}
 
-	// Get organization ID from query params or use default
-	organizationID := c.Query("organizationId")
+	// The state must match the one kept in this browser when the login was started. It can only be
+	// used once, so the cookie is removed whatever the outcome.
+	browserState, _ := c.Cookie(oauthStateCookie)
+	setOAuthStateCookie(c, "", -1)
 
-	response, err := ssoService.HandleOAuthCallback(c.Request.Context(), req.Provider, req.Code, req.State, organizationID)
+	// The organization is taken from the stored OAuth state, not from the request
+	response, err := ssoService.HandleOAuthCallback(c.Request.Context(), req.Provider, req.Code, req.State, browserState)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, models.ApiResponse{
 			Success: false,
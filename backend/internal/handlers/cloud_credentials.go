@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"time"
@@ -17,6 +18,7 @@ var (
 	awsProvider          *services.AWSProvider
 	gcpProvider          *services.GCPProvider
 	azureProvider        *services.AzureProvider
+	credentialsValidator *services.CloudCredentialsValidator
 )
 
 // InitializeCloudCredentialsServices initializes the cloud credentials services
@@ -25,11 +27,13 @@ func InitializeCloudCredentialsServices(
 	aws *services.AWSProvider,
 	gcp *services.GCPProvider,
 	azure *services.AzureProvider,
+	validator *services.CloudCredentialsValidator,
 ) {
 	cloudCredentialsRepo = ccRepo
 	awsProvider = aws
 	gcpProvider = gcp
 	azureProvider = azure
+	credentialsValidator = validator
 }
 
 // GetCloudProviders returns all cloud providers for the organization
@@ -43,7 +47,7 @@ func InitializeCloudCredentialsServices(
 // @Failure 500 {object} ErrorResponse
 // @Router /cloud-providers [get]
 func GetCloudProviders(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	_, exists := c.Get("userID")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, models.ApiResponse{
 			Success: false,
@@ -57,25 +61,12 @@ func GetCloudProviders(c *gin.Context) {
 		return
 	}
 
-	// Get user's organization ID
-	user, err := authService.GetUserByID(c.Request.Context(), userID.(string))
-	if err != nil {
-		log.Printf("Failed to get user %s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Success: false,
-			Error: &models.ApiError{
-				Code:      "INTERNAL_ERROR",
-				Message:   "Failed to get user information",
-				Timestamp: time.Now(),
-			},
-			RequestID: c.GetString("requestID"),
-		})
-		return
-	}
+	// The organization the token is scoped to
+	organizationID := c.GetString("organizationId")
 
-	providers, err := cloudCredentialsRepo.ListByOrganization(c.Request.Context(), user.OrganizationID)
+	providers, err := cloudCredentialsRepo.ListByOrganization(c.Request.Context(), organizationID)
 	if err != nil {
-		log.Printf("Failed to get cloud providers for organization %s: %v", user.OrganizationID, err)
+		log.Printf("Failed to get cloud providers for organization %s: %v", organizationID, err)
 		c.JSON(http.StatusInternalServerError, models.ApiResponse{
 			Success: false,
 			Error: &models.ApiError{
@@ -110,7 +101,7 @@ func GetCloudProviders(c *gin.Context) {
 // @Failure 500 {object} ErrorResponse
 // @Router /cloud-providers [post]
 func AddCloudProvider(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	_, exists := c.Get("userID")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, models.ApiResponse{
 			Success: false,
@@ -139,24 +130,11 @@ func AddCloudProvider(c *gin.Context) {
 		return
 	}
 
-	// Get user's organization ID
-	user, err := authService.GetUserByID(c.Request.Context(), userID.(string))
-	if err != nil {
-		log.Printf("Failed to get user %s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Success: false,
-			Error: &models.ApiError{
-				Code:      "INTERNAL_ERROR",
-				Message:   "Failed to get user information",
-				Timestamp: time.Now(),
-			},
-			RequestID: c.GetString("requestID"),
-		})
-		return
-	}
+	// The organization the token is scoped to
+	organizationID := c.GetString("organizationId")
 
 	// Test the connection before saving
-	valid, err := testProviderConnection(req.Provider, req.CredentialType, req.Credentials)
+	validation, err := testProviderConnection(c.Request.Context(), req.Provider, req.CredentialType, req.Credentials)
 	if err != nil {
 		log.Printf("Failed to test cloud provider connection: %v", err)
 		c.JSON(http.StatusBadRequest, models.ApiResponse{
@@ -171,7 +149,7 @@ func AddCloudProvider(c *gin.Context) {
 		return
 	}
 
-	if !valid {
+	if !validation.Valid {
 		c.JSON(http.StatusBadRequest, models.ApiResponse{
 			Success: false,
 			Error: &models.ApiError{
@@ -186,7 +164,7 @@ func AddCloudProvider(c *gin.Context) {
 
 	// Create cloud credentials
 	credentials := &models.CloudCredentials{
-		OrganizationID: user.OrganizationID,
+		OrganizationID: organizationID,
 		Provider:       req.Provider,
 		CredentialType: req.CredentialType,
 		Credentials:    req.Credentials,
@@ -208,7 +186,7 @@ func AddCloudProvider(c *gin.Context) {
 		return
 	}
 
-	log.Printf("Cloud provider added successfully for organization: %s", user.OrganizationID)
+	log.Printf("Cloud provider added successfully for organization: %s", organizationID)
 
 	c.JSON(http.StatusCreated, models.ApiResponse{
 		Success: true,
@@ -247,7 +225,7 @@ func TestCloudProviderConnection(c *gin.Context) {
 		return
 	}
 
-	valid, err := testProviderConnection(req.Provider, req.CredentialType, req.Credentials)
+	validation, err := testProviderConnection(c.Request.Context(), req.Provider, req.CredentialType, req.Credentials)
 	if err != nil {
 		log.Printf("Connection test failed: %v", err)
 		c.JSON(http.StatusOK, models.ApiResponse{
@@ -273,14 +251,7 @@ func TestCloudProviderConnection(c *gin.Context) {
 	c.JSON(http.StatusOK, models.ApiResponse{
 		Success: true,
 		Data: map[string]interface{}{
-			"validationResult": map[string]interface{}{
-				"valid":       valid,
-				"message":     "Connection successful",
-				"permissions": []string{}, // TODO: Get actual permissions
-				"limitations": []string{}, // TODO: Get actual limitations
-				"regions":     []string{}, // TODO: Get available regions
-				"services":    []string{}, // TODO: Get available services
-			},
+			"validationResult": validation,
 		},
 		RequestID: c.GetString("requestID"),
 	})
@@ -301,7 +272,7 @@ func TestCloudProviderConnection(c *gin.Context) {
 // @Failure 500 {object} ErrorResponse
 // @Router /cloud-providers/{id} [put]
 func UpdateCloudProvider(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	_, exists := c.Get("userID")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, models.ApiResponse{
 			Success: false,
@@ -344,21 +315,8 @@ func UpdateCloudProvider(c *gin.Context) {
 		return
 	}
 
-	// Get user's organization ID
-	user, err := authService.GetUserByID(c.Request.Context(), userID.(string))
-	if err != nil {
-		log.Printf("Failed to get user %s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Success: false,
-			Error: &models.ApiError{
-				Code:      "INTERNAL_ERROR",
-				Message:   "Failed to get user information",
-				Timestamp: time.Now(),
-			},
-			RequestID: c.GetString("requestID"),
-		})
-		return
-	}
+	// The organization the token is scoped to
+	organizationID := c.GetString("organizationId")
 
 	// Get existing provider to verify ownership
 	existingProvider, err := cloudCredentialsRepo.GetByID(c.Request.Context(), providerID)
@@ -377,7 +335,7 @@ func UpdateCloudProvider(c *gin.Context) {
 	}
 
 	// Verify organization ownership
-	if existingProvider.OrganizationID != user.OrganizationID {
+	if existingProvider.OrganizationID != organizationID {
 		c.JSON(http.StatusForbidden, models.ApiResponse{
 			Success: false,
 			Error: &models.ApiError{
@@ -392,7 +350,7 @@ func UpdateCloudProvider(c *gin.Context) {
 
 	// Test the connection if credentials are being updated
 	if len(req.Credentials) > 0 {
-		valid, err := testProviderConnection(req.Provider, req.CredentialType, req.Credentials)
+		validation, err := testProviderConnection(c.Request.Context(), req.Provider, req.CredentialType, req.Credentials)
 		if err != nil {
 			log.Printf("Failed to test updated cloud provider connection: %v", err)
 			c.JSON(http.StatusBadRequest, models.ApiResponse{
@@ -407,7 +365,7 @@ func UpdateCloudProvider(c *gin.Context) {
 			return
 		}
 
-		if !valid {
+		if !validation.Valid {
 			c.JSON(http.StatusBadRequest, models.ApiResponse{
 				Success: false,
 				Error: &models.ApiError{
@@ -424,7 +382,7 @@ func UpdateCloudProvider(c *gin.Context) {
 	// Update the provider
 	updateData := &models.CloudCredentials{
 		ID:             providerID,
-		OrganizationID: user.OrganizationID,
+		OrganizationID: organizationID,
 		Provider:       req.Provider,
 		CredentialType: req.CredentialType,
 		Credentials:    req.Credentials,
@@ -470,7 +428,7 @@ func UpdateCloudProvider(c *gin.Context) {
 // @Failure 500 {object} ErrorResponse
 // @Router /cloud-providers/{id} [delete]
 func DeleteCloudProvider(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	_, exists := c.Get("userID")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, models.ApiResponse{
 			Success: false,
@@ -498,21 +456,8 @@ func DeleteCloudProvider(c *gin.Context) {
 		return
 	}
 
-	// Get user's organization ID
-	user, err := authService.GetUserByID(c.Request.Context(), userID.(string))
-	if err != nil {
-		log.Printf("Failed to get user %s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, models.ApiResponse{
-			Success: false,
-			Error: &models.ApiError{
-				Code:      "INTERNAL_ERROR",
-				Message:   "Failed to get user information",
-				Timestamp: time.Now(),
-			},
-			RequestID: c.GetString("requestID"),
-		})
-		return
-	}
+	// The organization the token is scoped to
+	organizationID := c.GetString("organizationId")
 
 	// Get existing provider to verify ownership
 	existingProvider, err := cloudCredentialsRepo.GetByID(c.Request.Context(), providerID)
@@ -531,7 +476,7 @@ func DeleteCloudProvider(c *gin.Context) {
 	}
 
 	// Verify organization ownership
-	if existingProvider.OrganizationID != user.OrganizationID {
+	if existingProvider.OrganizationID != organizationID {
 		c.JSON(http.StatusForbidden, models.ApiResponse{
 			Success: false,
 			Error: &models.ApiError{
@@ -572,27 +517,10 @@ func DeleteCloudProvider(c *gin.Context) {
 }
 
 // Helper function to test provider connections
-func testProviderConnection(provider, credentialType string, credentials map[string]interface{}) (bool, error) {
-	switch provider {
-	case "aws":
-		if awsProvider == nil {
-			return false, nil // Provider not initialized, assume valid for now
-		}
-		// TODO: Implement AWS connection test
-		return true, nil
-	case "gcp":
-		if gcpProvider == nil {
-			return false, nil // Provider not initialized, assume valid for now
-		}
-		// TODO: Implement GCP connection test
-		return true, nil
-	case "azure":
-		if azureProvider == nil {
-			return false, nil // Provider not initialized, assume valid for now
-		}
-		// TODO: Implement Azure connection test
-		return true, nil
-	default:
-		return false, nil
+func testProviderConnection(ctx context.Context, provider, credentialType string, credentials map[string]interface{}) (*models.CloudCredentialsValidation, error) {
+	if credentialsValidator == nil {
+		return &models.CloudCredentialsValidation{Valid: false, Message: "Cloud credentials validation is not configured"}, nil
 	}
-}
\ No newline at end of file
+
+	return credentialsValidator.Validate(ctx, provider, credentialType, credentials)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -171,6 +172,42 @@ func (h *ComplianceGinHandler) GetControlStatistics(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// SeedControls handles POST /api/compliance/frameworks/:id/seed-controls
+func (h *ComplianceGinHandler) SeedControls(c *gin.Context) {
+	frameworkID := c.Param("id")
+
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Organization not found"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	template := c.Query("template")
+	inserted, err := h.complianceService.SeedFrameworkControls(c.Request.Context(), orgID, frameworkID, template, userID.(string))
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownComplianceTemplate) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     err.Error(),
+				"templates": services.ComplianceTemplateNames(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Controls seeded successfully",
+		"inserted": inserted,
+	})
+}
+
 // Assessment endpoints
 
 // CreateAssessment handles POST /api/compliance/assessments
@@ -187,13 +224,13 @@ func (h *ComplianceGinHandler) CreateAssessment(c *gin.Context) {
 		return
 	}
 
-	orgID, exists := c.Get("organizationID")
-	if !exists {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Organization not found"})
 		return
 	}
 
-	assessment.OrganizationID = orgID.(string)
+	assessment.OrganizationID = orgID
 
 	if err := h.complianceService.CreateAssessment(c.Request.Context(), &assessment, userID.(string)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -205,8 +242,8 @@ func (h *ComplianceGinHandler) CreateAssessment(c *gin.Context) {
 
 // ListAssessments handles GET /api/compliance/assessments
 func (h *ComplianceGinHandler) ListAssessments(c *gin.Context) {
-	orgID, exists := c.Get("organizationID")
-	if !exists {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Organization not found"})
 		return
 	}
@@ -215,7 +252,7 @@ func (h *ComplianceGinHandler) ListAssessments(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	assessments, total, err := h.complianceService.ListAssessments(c.Request.Context(), orgID.(string), limit, offset)
+	assessments, total, err := h.complianceService.ListAssessments(c.Request.Context(), orgID, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -233,8 +270,8 @@ func (h *ComplianceGinHandler) ListAssessments(c *gin.Context) {
 func (h *ComplianceGinHandler) RunAssessment(c *gin.Context) {
 	assessmentID := c.Param("id")
 
-	orgID, exists := c.Get("organizationID")
-	if !exists {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Organization not found"})
 		return
 	}
@@ -245,7 +282,7 @@ func (h *ComplianceGinHandler) RunAssessment(c *gin.Context) {
 		return
 	}
 
-	if err := h.complianceService.RunAssessment(c.Request.Context(), orgID.(string), assessmentID, userID.(string)); err != nil {
+	if err := h.complianceService.RunAssessment(c.Request.Context(), orgID, assessmentID, userID.(string)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -255,6 +292,34 @@ func (h *ComplianceGinHandler) RunAssessment(c *gin.Context) {
 	})
 }
 
+// GetTrends handles GET /api/compliance/trends
+func (h *ComplianceGinHandler) GetTrends(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Organization not found"})
+		return
+	}
+
+	framework := c.Query("framework")
+	trendRange := c.DefaultQuery("range", services.DefaultComplianceTrendRange)
+
+	trends, err := h.complianceService.GetComplianceTrends(c.Request.Context(), orgID, framework, trendRange)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTrendRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"trends":    trends,
+		"framework": framework,
+		"range":     trendRange,
+	})
+}
+
 // GetMetrics handles GET /api/compliance/metrics
 func (h *ComplianceGinHandler) GetMetrics(c *gin.Context) {
 	_, exists := c.Get("organizationID")
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+)
+
+// Report schedule endpoints
+
+// ListReportSchedules handles GET /api/compliance/report-schedules
+func (h *ComplianceGinHandler) ListReportSchedules(c *gin.Context) {
+	schedules, err := h.complianceService.ListReportSchedules(c.Request.Context(), c.GetString("organizationId"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list report schedules"})
+		return
+	}
+	if schedules == nil {
+		schedules = []*models.ComplianceReportSchedule{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"schedules": schedules})
+}
+
+// CreateReportSchedule handles POST /api/compliance/report-schedules
+func (h *ComplianceGinHandler) CreateReportSchedule(c *gin.Context) {
+	var req models.CreateComplianceReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.complianceService.CreateReportSchedule(c.Request.Context(), c.GetString("organizationId"), c.GetString("userID"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReportSchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create report schedule"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// UpdateReportSchedule handles PUT /api/compliance/report-schedules/:id
+func (h *ComplianceGinHandler) UpdateReportSchedule(c *gin.Context) {
+	var req models.UpdateComplianceReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	schedule, err := h.complianceService.UpdateReportSchedule(c.Request.Context(), c.GetString("organizationId"), c.GetString("userID"), c.Param("id"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrReportScheduleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update report schedule"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// DeleteReportSchedule handles DELETE /api/compliance/report-schedules/:id
+func (h *ComplianceGinHandler) DeleteReportSchedule(c *gin.Context) {
+	err := h.complianceService.DeleteReportSchedule(c.Request.Context(), c.GetString("organizationId"), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrReportScheduleNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Report schedule not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete report schedule"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// Report endpoints
+
+// ListReports handles GET /api/compliance/reports
+func (h *ComplianceGinHandler) ListReports(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	reports, total, err := h.complianceService.ListReports(c.Request.Context(), c.GetString("organizationId"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list reports"})
+		return
+	}
+	if reports == nil {
+		reports = []*models.ComplianceReport{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reports": reports,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// GetReport handles GET /api/compliance/reports/:id, returning the report with the state it captured
+func (h *ComplianceGinHandler) GetReport(c *gin.Context) {
+	report, err := h.complianceService.GetReport(c.Request.Context(), c.GetString("organizationId"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrComplianceReportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// DownloadReport handles GET /api/compliance/reports/:id/download, rendering the report as a PDF
+// or, with format=json, as JSON
+func (h *ComplianceGinHandler) DownloadReport(c *gin.Context) {
+	report, err := h.complianceService.GetReport(c.Request.Context(), c.GetString("organizationId"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrComplianceReportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Report not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get report"})
+		return
+	}
+
+	file, err := h.complianceService.RenderReport(report, c.DefaultQuery("format", services.ComplianceReportPDF))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidReportFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of pdf or json"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render report"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Filename))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"cloudweave/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigHandler handles server configuration administration
+type ConfigHandler struct {
+	reloader *config.Reloader
+}
+
+// NewConfigHandler creates a new config handler
+func NewConfigHandler(reloader *config.Reloader) *ConfigHandler {
+	return &ConfigHandler{
+		reloader: reloader,
+	}
+}
+
+// ReloadConfig re-reads the configuration and applies its rate limits, CORS origins and log
+// level without a restart. Reloads that change any other setting are rejected.
+func (h *ConfigHandler) ReloadConfig(c *gin.Context) {
+	result, err := h.reloader.Reload()
+	if err != nil {
+		switch {
+		case errors.Is(err, config.ErrNonReloadableChange):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, config.ErrInvalidConfig):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload configuration"})
+		}
+		return
+	}
+
+	log.Printf("Configuration reloaded by user %s, changed: %v", c.GetString("userID"), result.Changed)
+	c.JSON(http.StatusOK, result)
+}
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"time"
 
+	"cloudweave/internal/models"
 	"cloudweave/internal/repositories"
 	"cloudweave/internal/services"
 
@@ -134,6 +139,10 @@ func (h *CostManagementHandler) GetCostByTags(c *gin.Context) {
 	// Get cost by tags from service
 	costByTags, err := h.costService.GetCostByTags(c.Request.Context(), orgID, tags)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidTag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get cost by tags"})
 		return
 	}
@@ -177,6 +186,50 @@ func (h *CostManagementHandler) GetCostAllocationByTags(c *gin.Context) {
 	c.JSON(http.StatusOK, allocationData)
 }
 
+// GetCostAllocationSettings retrieves the tag keys the organization's costs are allocated by
+func (h *CostManagementHandler) GetCostAllocationSettings(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	settings, err := h.costService.GetCostAllocationSettings(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get cost allocation settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateCostAllocationSettings replaces the tag keys the organization's costs are allocated by
+func (h *CostManagementHandler) UpdateCostAllocationSettings(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	var req models.UpdateCostAllocationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.costService.UpdateCostAllocationSettings(c.Request.Context(), orgID, c.GetString("userID"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAllocationDimensions) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update cost allocation settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
 // GetCostOptimizationRecommendations retrieves detailed cost optimization recommendations
 func (h *CostManagementHandler) GetCostOptimizationRecommendations(c *gin.Context) {
 	orgID := c.GetString("organizationId")
@@ -195,6 +248,84 @@ func (h *CostManagementHandler) GetCostOptimizationRecommendations(c *gin.Contex
 	c.JSON(http.StatusOK, gin.H{"recommendations": recommendations})
 }
 
+// ExportCosts streams the organization's daily cost per resource as FOCUS or flat CSV
+// (?format=focus|csv&start=YYYY-MM-DD&end=YYYY-MM-DD). The range defaults to the current month to date.
+func (h *CostManagementHandler) ExportCosts(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	end := now
+	for param, date := range map[string]*time.Time{"start": &start, "end": &end} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": param + " must be a date in YYYY-MM-DD format"})
+			return
+		}
+		*date = parsed
+	}
+
+	format := c.DefaultQuery("format", services.CostExportFormatFOCUS)
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("cloudweave-costs-%s-%s-%s.csv", format, start.Format("2006-01-02"), end.Format("2006-01-02"))))
+
+	err := h.costService.ExportCosts(c.Request.Context(), orgID, format, start, end, c.Writer)
+	if err == nil {
+		return
+	}
+	if c.Writer.Written() {
+		// The response is already streaming, so the error can only end it early
+		log.Printf("Cost export for organization %s failed: %v", orgID, err)
+		return
+	}
+
+	c.Header("Content-Type", "")
+	c.Header("Content-Disposition", "")
+	switch {
+	case errors.Is(err, services.ErrUnsupportedExportFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of: focus, csv"})
+	case errors.Is(err, services.ErrInvalidExportRange):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export costs"})
+	}
+}
+
+// GetResourceCostHistory returns one resource's daily cost and usage (?range=7d|30d|90d|1y)
+func (h *CostManagementHandler) GetResourceCostHistory(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	infrastructure, err := h.repoManager.Infrastructure.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil || infrastructure.OrganizationID != orgID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Infrastructure resource not found"})
+		return
+	}
+
+	history, err := h.costService.GetResourceCostHistory(c.Request.Context(), infrastructure, c.DefaultQuery("range", services.DefaultCostHistoryRange))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCostHistoryRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get cost history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 // CreateBudget creates a new budget
 func (h *CostManagementHandler) CreateBudget(c *gin.Context) {
 	c.JSON(http.StatusNotImplemented, gin.H{"error": "Budget creation not implemented yet"})
@@ -2,7 +2,13 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"log"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"cloudweave/internal/models"
 	"cloudweave/internal/repositories"
@@ -33,50 +39,12 @@ func (h *DashboardHandler) GetDashboardStats(c *gin.Context) {
 		return
 	}
 
-	// Get infrastructure count
-	infrastructures, err := h.repoManager.Infrastructure.List(c.Request.Context(), orgID, repositories.ListParams{
-		Limit:  1000,
-		Offset: 0,
-	})
+	stats, err := h.getDashboardStatsData(c.Request.Context(), orgID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure data"})
-		return
-	}
-
-	// Get deployment count
-	deployments, err := h.repoManager.Deployment.List(c.Request.Context(), orgID, repositories.ListParams{
-		Limit:  1000,
-		Offset: 0,
-	})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get deployment data"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dashboard stats"})
 		return
 	}
 
-	// Calculate stats
-	activeResources := 0
-	for _, infra := range infrastructures {
-		if infra.Status == models.InfraStatusRunning {
-			activeResources++
-		}
-	}
-
-	// Mock data for now - in real implementation, these would come from actual metrics
-	stats := map[string]interface{}{
-		"activeResources":       activeResources,
-		"activeResourcesChange": "+12%",
-		"activeResourcesTrend":  "up",
-		"deployments":           len(deployments),
-		"deploymentsChange":     "+5%",
-		"deploymentsTrend":      "up",
-		"costThisMonth":         1234.56,
-		"costThisMonthChange":   "-8%",
-		"costThisMonthTrend":    "down",
-		"uptime":                99.9876,
-		"uptimeChange":          "+0.1%",
-		"uptimeTrend":           "up",
-	}
-
 	c.JSON(http.StatusOK, stats)
 }
 
@@ -88,27 +56,12 @@ func (h *DashboardHandler) GetDashboardActivity(c *gin.Context) {
 		return
 	}
 
-	// Get recent deployments
-	deployments, err := h.repoManager.Deployment.List(c.Request.Context(), orgID, repositories.ListParams{
-		Limit:  10,
-		Offset: 0,
-	})
+	activities, err := h.getDashboardActivityData(c.Request.Context(), orgID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get deployment data"})
 		return
 	}
 
-	// Convert to activity format
-	var activities []map[string]interface{}
-	for _, deployment := range deployments {
-		activities = append(activities, map[string]interface{}{
-			"id":        deployment.ID,
-			"message":   "Deployment \"" + deployment.Name + "\" " + deployment.Status,
-			"timestamp": deployment.CreatedAt,
-			"type":      "deployment",
-		})
-	}
-
 	c.JSON(http.StatusOK, activities)
 }
 
@@ -120,16 +73,7 @@ func (h *DashboardHandler) GetPerformanceMetrics(c *gin.Context) {
 		return
 	}
 
-	// Mock performance metrics - in real implementation, these would come from actual metrics
-	metrics := map[string]interface{}{
-		"cpuUsage":     45.2,
-		"memoryUsage":  62.8,
-		"networkIO":    1.2,
-		"responseTime": 120,
-		"timestamp":    "2025-01-27T12:00:00Z",
-	}
-
-	c.JSON(http.StatusOK, metrics)
+	c.JSON(http.StatusOK, performanceMetricsData())
 }
 
 // GetCostMetrics retrieves cost metrics
@@ -140,16 +84,7 @@ func (h *DashboardHandler) GetCostMetrics(c *gin.Context) {
 		return
 	}
 
-	// Mock cost metrics - in real implementation, these would come from actual cost data
-	metrics := map[string]interface{}{
-		"thisMonth":         1234.56,
-		"lastMonth":         1342.78,
-		"projected":         1180.00,
-		"savings":           162.22,
-		"savingsPercentage": 12.1,
-	}
-
-	c.JSON(http.StatusOK, metrics)
+	c.JSON(http.StatusOK, costMetricsData())
 }
 
 // GetSecurityMetrics retrieves security metrics
@@ -160,19 +95,7 @@ func (h *DashboardHandler) GetSecurityMetrics(c *gin.Context) {
 		return
 	}
 
-	// Mock security metrics - in real implementation, these would come from actual security data
-	metrics := map[string]interface{}{
-		"securityScore": 98,
-		"vulnerabilities": map[string]interface{}{
-			"critical": 0,
-			"medium":   2,
-			"low":      5,
-		},
-		"lastScan":   "2025-01-27T10:00:00Z",
-		"compliance": []string{"SOC2", "ISO27001"},
-	}
-
-	c.JSON(http.StatusOK, metrics)
+	c.JSON(http.StatusOK, securityMetricsData())
 }
 
 // GetInfrastructureMetrics retrieves infrastructure metrics
@@ -183,45 +106,12 @@ func (h *DashboardHandler) GetInfrastructureMetrics(c *gin.Context) {
 		return
 	}
 
-	// Get infrastructure data
-	infrastructures, err := h.repoManager.Infrastructure.List(c.Request.Context(), orgID, repositories.ListParams{
-		Limit:  1000,
-		Offset: 0,
-	})
+	metrics, err := h.getInfrastructureMetricsData(c.Request.Context(), orgID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure data"})
 		return
 	}
 
-	// Calculate metrics
-	ec2Instances := 0
-	loadBalancers := 0
-	rdsCount := 0
-	dynamodbCount := 0
-
-	for _, infra := range infrastructures {
-		switch infra.Type {
-		case models.InfraTypeServer:
-			ec2Instances++
-		case models.InfraTypeDatabase:
-			if infra.Provider == "aws" {
-				rdsCount++
-			} else {
-				dynamodbCount++
-			}
-		}
-	}
-
-	metrics := map[string]interface{}{
-		"ec2Instances":  ec2Instances,
-		"loadBalancers": loadBalancers,
-		"databases": map[string]interface{}{
-			"rds":      rdsCount,
-			"dynamodb": dynamodbCount,
-		},
-		"storageUsed": 2.4, // Mock data
-	}
-
 	c.JSON(http.StatusOK, metrics)
 }
 
@@ -233,15 +123,7 @@ func (h *DashboardHandler) GetReportsMetrics(c *gin.Context) {
 		return
 	}
 
-	// Mock reports metrics - in real implementation, these would come from actual reports data
-	metrics := map[string]interface{}{
-		"monthlyReportAvailable":          true,
-		"costOptimizationRecommendations": 15,
-		"performanceTrend":                "improving",
-		"nextSecurityAudit":               "2025-02-03T10:00:00Z",
-	}
-
-	c.JSON(http.StatusOK, metrics)
+	c.JSON(http.StatusOK, reportsMetricsData())
 }
 
 // GetDashboardOverview retrieves complete dashboard overview
@@ -252,31 +134,163 @@ func (h *DashboardHandler) GetDashboardOverview(c *gin.Context) {
 		return
 	}
 
-	// Get all dashboard data
-	stats, err := h.getDashboardStatsData(orgID)
+	overview, err := h.getDashboardOverviewData(c.Request.Context(), orgID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dashboard stats"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	activity, err := h.getDashboardActivityData(orgID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get dashboard activity"})
+	c.JSON(http.StatusOK, overview)
+}
+
+const (
+	// maxConcurrentDashboardSections is how many sections a batch request loads at once
+	maxConcurrentDashboardSections = 4
+
+	// dashboardSectionTimeout bounds how long a batch request waits for any one section
+	dashboardSectionTimeout = 10 * time.Second
+)
+
+// dashboardSectionLoader loads the data of one dashboard section
+type dashboardSectionLoader func(h *DashboardHandler, ctx context.Context, orgID string) (interface{}, error)
+
+// dashboardSections are the sections a batch request can load, by name
+var dashboardSections = map[string]dashboardSectionLoader{
+	"overview": func(h *DashboardHandler, ctx context.Context, orgID string) (interface{}, error) {
+		return h.getDashboardOverviewData(ctx, orgID)
+	},
+	"stats": func(h *DashboardHandler, ctx context.Context, orgID string) (interface{}, error) {
+		return h.getDashboardStatsData(ctx, orgID)
+	},
+	"activity": func(h *DashboardHandler, ctx context.Context, orgID string) (interface{}, error) {
+		return h.getDashboardActivityData(ctx, orgID)
+	},
+	"performance": func(h *DashboardHandler, ctx context.Context, orgID string) (interface{}, error) {
+		return performanceMetricsData(), nil
+	},
+	"costs": func(h *DashboardHandler, ctx context.Context, orgID string) (interface{}, error) {
+		return costMetricsData(), nil
+	},
+	"security": func(h *DashboardHandler, ctx context.Context, orgID string) (interface{}, error) {
+		return securityMetricsData(), nil
+	},
+	"infrastructure": func(h *DashboardHandler, ctx context.Context, orgID string) (interface{}, error) {
+		return h.getInfrastructureMetricsData(ctx, orgID)
+	},
+	"reports": func(h *DashboardHandler, ctx context.Context, orgID string) (interface{}, error) {
+		return reportsMetricsData(), nil
+	},
+}
+
+// DashboardSection is one section of a batch dashboard response. A section that failed to load
+// carries its error instead of data.
+type DashboardSection struct {
+	Data        interface{} `json:"data,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	LastUpdated time.Time   `json:"lastUpdated"`
+}
+
+// GetDashboardBatch loads several dashboard sections concurrently in one request, e.g.
+// sections=stats,activity,costs. All sections are loaded when none are named. A section that
+// fails is reported with its error without failing the others.
+func (h *DashboardHandler) GetDashboardBatch(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
 		return
 	}
 
-	overview := map[string]interface{}{
-		"stats":       stats,
-		"activity":    activity,
-		"lastUpdated": "2025-01-27T12:00:00Z",
+	var names []string
+	if filter := c.Query("sections"); filter != "" {
+		seen := make(map[string]bool)
+		for _, name := range strings.Split(filter, ",") {
+			name = strings.TrimSpace(name)
+			if _, ok := dashboardSections[name]; !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "unknown dashboard section: " + name})
+				return
+			}
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	} else {
+		for name := range dashboardSections {
+			names = append(names, name)
+		}
+		sort.Strings(names)
 	}
 
-	c.JSON(http.StatusOK, overview)
+	results := make([]DashboardSection, len(names))
+	slots := make(chan struct{}, maxConcurrentDashboardSections)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, load dashboardSectionLoader) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			results[i] = h.loadDashboardSection(c.Request.Context(), orgID, load)
+		}(i, dashboardSections[name])
+	}
+	wg.Wait()
+
+	sections := make(map[string]DashboardSection, len(names))
+	for i, name := range names {
+		sections[name] = results[i]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sections": sections})
+}
+
+// loadDashboardSection loads a section within dashboardSectionTimeout, turning a panic into the
+// section's error so it cannot take down the other sections
+func (h *DashboardHandler) loadDashboardSection(ctx context.Context, orgID string, load dashboardSectionLoader) (section DashboardSection) {
+	ctx, cancel := context.WithTimeout(ctx, dashboardSectionTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Dashboard section panicked: %v", r)
+			section = DashboardSection{Error: "internal error", LastUpdated: time.Now().UTC()}
+		}
+	}()
+
+	data, err := load(h, ctx, orgID)
+	section.LastUpdated = time.Now().UTC()
+	if err != nil {
+		log.Printf("Failed to load dashboard section for organization %s: %v", orgID, err)
+		section.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			section.Error = "timed out"
+		}
+		return section
+	}
+	section.Data = data
+	return section
 }
 
 // Helper methods
-func (h *DashboardHandler) getDashboardStatsData(orgID string) (map[string]interface{}, error) {
-	infrastructures, err := h.repoManager.Infrastructure.List(context.Background(), orgID, repositories.ListParams{
+func (h *DashboardHandler) getDashboardOverviewData(ctx context.Context, orgID string) (map[string]interface{}, error) {
+	stats, err := h.getDashboardStatsData(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("Failed to get dashboard stats")
+	}
+
+	activity, err := h.getDashboardActivityData(ctx, orgID)
+	if err != nil {
+		return nil, errors.New("Failed to get dashboard activity")
+	}
+
+	return map[string]interface{}{
+		"stats":       stats,
+		"activity":    activity,
+		"lastUpdated": time.Now().UTC(),
+	}, nil
+}
+
+func (h *DashboardHandler) getDashboardStatsData(ctx context.Context, orgID string) (map[string]interface{}, error) {
+	infrastructures, err := h.repoManager.Infrastructure.List(ctx, orgID, repositories.ListParams{
 		Limit:  1000,
 		Offset: 0,
 	})
@@ -284,7 +298,7 @@ func (h *DashboardHandler) getDashboardStatsData(orgID string) (map[string]inter
 		return nil, err
 	}
 
-	deployments, err := h.repoManager.Deployment.List(context.Background(), orgID, repositories.ListParams{
+	deployments, err := h.repoManager.Deployment.List(ctx, orgID, repositories.ListParams{
 		Limit:  1000,
 		Offset: 0,
 	})
@@ -299,6 +313,7 @@ func (h *DashboardHandler) getDashboardStatsData(orgID string) (map[string]inter
 		}
 	}
 
+	// Mock data for now - in real implementation, these would come from actual metrics
 	return map[string]interface{}{
 		"activeResources":       activeResources,
 		"activeResourcesChange": "+12%",
@@ -315,8 +330,8 @@ func (h *DashboardHandler) getDashboardStatsData(orgID string) (map[string]inter
 	}, nil
 }
 
-func (h *DashboardHandler) getDashboardActivityData(orgID string) ([]map[string]interface{}, error) {
-	deployments, err := h.repoManager.Deployment.List(context.Background(), orgID, repositories.ListParams{
+func (h *DashboardHandler) getDashboardActivityData(ctx context.Context, orgID string) ([]map[string]interface{}, error) {
+	deployments, err := h.repoManager.Deployment.List(ctx, orgID, repositories.ListParams{
 		Limit:  10,
 		Offset: 0,
 	})
@@ -336,3 +351,88 @@ func (h *DashboardHandler) getDashboardActivityData(orgID string) ([]map[string]
 
 	return activities, nil
 }
+
+func (h *DashboardHandler) getInfrastructureMetricsData(ctx context.Context, orgID string) (map[string]interface{}, error) {
+	infrastructures, err := h.repoManager.Infrastructure.List(ctx, orgID, repositories.ListParams{
+		Limit:  1000,
+		Offset: 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Calculate metrics
+	ec2Instances := 0
+	loadBalancers := 0
+	rdsCount := 0
+	dynamodbCount := 0
+
+	for _, infra := range infrastructures {
+		switch infra.Type {
+		case models.InfraTypeServer:
+			ec2Instances++
+		case models.InfraTypeDatabase:
+			if infra.Provider == "aws" {
+				rdsCount++
+			} else {
+				dynamodbCount++
+			}
+		}
+	}
+
+	return map[string]interface{}{
+		"ec2Instances":  ec2Instances,
+		"loadBalancers": loadBalancers,
+		"databases": map[string]interface{}{
+			"rds":      rdsCount,
+			"dynamodb": dynamodbCount,
+		},
+		"storageUsed": 2.4, // Mock data
+	}, nil
+}
+
+// Mock performance metrics - in real implementation, these would come from actual metrics
+func performanceMetricsData() map[string]interface{} {
+	return map[string]interface{}{
+		"cpuUsage":     45.2,
+		"memoryUsage":  62.8,
+		"networkIO":    1.2,
+		"responseTime": 120,
+		"timestamp":    "2025-01-27T12:00:00Z",
+	}
+}
+
+// Mock cost metrics - in real implementation, these would come from actual cost data
+func costMetricsData() map[string]interface{} {
+	return map[string]interface{}{
+		"thisMonth":         1234.56,
+		"lastMonth":         1342.78,
+		"projected":         1180.00,
+		"savings":           162.22,
+		"savingsPercentage": 12.1,
+	}
+}
+
+// Mock security metrics - in real implementation, these would come from actual security data
+func securityMetricsData() map[string]interface{} {
+	return map[string]interface{}{
+		"securityScore": 98,
+		"vulnerabilities": map[string]interface{}{
+			"critical": 0,
+			"medium":   2,
+			"low":      5,
+		},
+		"lastScan":   "2025-01-27T10:00:00Z",
+		"compliance": []string{"SOC2", "ISO27001"},
+	}
+}
+
+// Mock reports metrics - in real implementation, these would come from actual reports data
+func reportsMetricsData() map[string]interface{} {
+	return map[string]interface{}{
+		"monthlyReportAvailable":          true,
+		"costOptimizationRecommendations": 15,
+		"performanceTrend":                "improving",
+		"nextSecurityAudit":               "2025-02-03T10:00:00Z",
+	}
+}
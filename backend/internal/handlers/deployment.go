@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"cloudweave/internal/models"
 	"cloudweave/internal/repositories"
@@ -15,12 +17,14 @@ import (
 type DeploymentHandler struct {
 	repoManager       *repositories.RepositoryManager
 	deploymentService *services.DeploymentService
+	statsService      *services.StatsService
 }
 
-func NewDeploymentHandler(repoManager *repositories.RepositoryManager, deploymentService *services.DeploymentService) *DeploymentHandler {
+func NewDeploymentHandler(repoManager *repositories.RepositoryManager, deploymentService *services.DeploymentService, statsService *services.StatsService) *DeploymentHandler {
 	return &DeploymentHandler{
 		repoManager:       repoManager,
 		deploymentService: deploymentService,
+		statsService:      statsService,
 	}
 }
 
@@ -56,10 +60,38 @@ func (h *DeploymentHandler) CreateDeployment(c *gin.Context) {
 		Progress:       0,
 		Configuration:  req.Configuration,
 		CreatedBy:      &[]string{userID.(string)}[0],
+		Strategy:       req.Strategy,
+		ScheduledFor:   req.ScheduledFor,
+	}
+
+	if req.Canary != nil {
+		if req.Strategy != models.DeploymentStrategyCanary {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Canary settings require the canary strategy"})
+			return
+		}
+		deployment.StrategyState.Canary = req.Canary
+	}
+	deployment.StrategyState.AutoRollback = req.AutoRollback
+
+	// Deployments can optionally run through one of the organization's pipelines
+	if req.PipelineID != nil {
+		if _, err := h.repoManager.Pipeline.GetByID(c.Request.Context(), *req.PipelineID, deployment.OrganizationID); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Pipeline not found"})
+			return
+		}
+		deployment.PipelineID = req.PipelineID
 	}
 
 	// Create deployment through service layer (handles orchestration)
 	if err := h.deploymentService.CreateDeployment(c.Request.Context(), deployment); err != nil {
+		if errors.Is(err, services.ErrDeploymentInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "DEPLOYMENT_IN_PROGRESS"})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidSchedule) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -67,7 +99,30 @@ func (h *DeploymentHandler) CreateDeployment(c *gin.Context) {
 	c.JSON(http.StatusCreated, deployment)
 }
 
-// GetDeployment retrieves a specific deployment
+// PreviewDeployment shows what a deployment request would change without creating it
+func (h *DeploymentHandler) PreviewDeployment(c *gin.Context) {
+	var req models.CreateDeploymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	preview, err := h.deploymentService.PreviewDeployment(c.Request.Context(), orgID.(string), &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// GetDeployment retrieves a specific deployment with its rollback history
 func (h *DeploymentHandler) GetDeployment(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -75,7 +130,7 @@ func (h *DeploymentHandler) GetDeployment(c *gin.Context) {
 		return
 	}
 
-	deployment, err := h.repoManager.Deployment.GetByID(c.Request.Context(), id)
+	deployment, err := h.deploymentService.GetDeploymentDetail(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
@@ -146,6 +201,23 @@ func (h *DeploymentHandler) DeleteDeployment(c *gin.Context) {
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// ListScheduledDeployments lists the deployments that are scheduled but have not started yet
+func (h *DeploymentHandler) ListScheduledDeployments(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	deployments, err := h.deploymentService.ListScheduledDeployments(c.Request.Context(), orgID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deployments": deployments})
+}
+
 // ListDeployments lists deployments with filtering
 func (h *DeploymentHandler) ListDeployments(c *gin.Context) {
 	orgID, exists := c.Get("organizationId")
@@ -158,6 +230,7 @@ func (h *DeploymentHandler) ListDeployments(c *gin.Context) {
 	params := repositories.ListParams{
 		Limit:  50, // default
 		Offset: 0,
+		Cursor: c.Query("cursor"),
 	}
 
 	if limitStr := c.Query("limit"); limitStr != "" {
@@ -189,10 +262,21 @@ func (h *DeploymentHandler) ListDeployments(c *gin.Context) {
 	}
 
 	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// A full page may have more results after it; the cursor is taken before post-query filtering
+	var nextCursor string
+	if len(deployments) == params.Limit {
+		last := deployments[len(deployments)-1]
+		nextCursor = repositories.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
 	// Filter by application if specified (post-query filtering for simplicity)
 	if application != "" {
 		filtered := make([]*models.Deployment, 0)
@@ -210,10 +294,11 @@ func (h *DeploymentHandler) ListDeployments(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":   deployments,
-		"count":  len(deployments),
-		"limit":  params.Limit,
-		"offset": params.Offset,
+		"data":       deployments,
+		"count":      len(deployments),
+		"limit":      params.Limit,
+		"offset":     params.Offset,
+		"nextCursor": nextCursor,
 	})
 }
 
@@ -272,7 +357,36 @@ func (h *DeploymentHandler) GetDeploymentLogs(c *gin.Context) {
 	})
 }
 
-// RollbackDeployment rolls back a deployment to a previous version
+// StreamDeploymentLogs returns a deployment's logs, or with ?follow=true upgrades to a
+// WebSocket that streams new log lines until the deployment finishes
+func (h *DeploymentHandler) StreamDeploymentLogs(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Deployment ID is required"})
+		return
+	}
+
+	// Tokens without an organization, such as refresh tokens, cannot read any deployment's logs
+	orgID := c.GetString("organizationId")
+	deployment, err := h.repoManager.Deployment.GetByID(c.Request.Context(), id)
+	if err != nil || orgID == "" || deployment.OrganizationID != orgID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Deployment not found"})
+		return
+	}
+
+	if c.Query("follow") != "true" {
+		h.GetDeploymentLogs(c)
+		return
+	}
+
+	if err := h.deploymentService.StreamDeploymentLogs(c.Writer, c.Request, c.GetString("userID"), deployment); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+}
+
+// RollbackDeployment rolls back a deployment to a previous version. Rollbacks that would
+// reintroduce fixed critical vulnerabilities are refused unless override is set with a reason.
 func (h *DeploymentHandler) RollbackDeployment(c *gin.Context) {
 	id := c.Param("id")
 	if id == "" {
@@ -283,6 +397,7 @@ func (h *DeploymentHandler) RollbackDeployment(c *gin.Context) {
 	var req struct {
 		TargetVersion string `json:"targetVersion" binding:"required"`
 		Reason        string `json:"reason,omitempty"`
+		Override      bool   `json:"override,omitempty"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -290,8 +405,20 @@ func (h *DeploymentHandler) RollbackDeployment(c *gin.Context) {
 		return
 	}
 
-	rollbackDeployment, err := h.deploymentService.RollbackDeployment(c.Request.Context(), id, req.TargetVersion, req.Reason)
+	rollbackDeployment, err := h.deploymentService.RollbackDeployment(c.Request.Context(), id, req.TargetVersion, req.Reason, c.GetString("userID"), req.Override)
 	if err != nil {
+		if errors.Is(err, services.ErrDeploymentInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "DEPLOYMENT_IN_PROGRESS"})
+			return
+		}
+		if errors.Is(err, services.ErrRollbackReintroducesVulnerabilities) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "code": "ROLLBACK_REINTRODUCES_VULNERABILITIES"})
+			return
+		}
+		if errors.Is(err, services.ErrRollbackOverrideReason) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -359,52 +486,33 @@ func (h *DeploymentHandler) GetDeploymentStats(c *gin.Context) {
 		return
 	}
 
-	// Calculate stats
-	activeDeployments := 0
-	completedDeployments := 0
-	failedDeployments := 0
-	totalDuration := 0
-	completedCount := 0
-
-	for _, deployment := range deployments {
-		switch deployment.Status {
-		case models.DeploymentStatusRunning, models.DeploymentStatusPending:
-			activeDeployments++
-		case models.DeploymentStatusCompleted:
-			completedDeployments++
-			if deployment.StartedAt != nil && deployment.CompletedAt != nil {
-				duration := int(deployment.CompletedAt.Sub(*deployment.StartedAt).Minutes())
-				totalDuration += duration
-				completedCount++
-			}
-		case models.DeploymentStatusFailed:
-			failedDeployments++
+	// Calculate stats and compare them against the snapshot from one period ago (?period=day|week)
+	period := c.DefaultQuery("period", services.DefaultStatPeriod)
+	values := services.DeploymentStatValues(deployments)
+	changes, err := h.statsService.CompareWithPeriod(c.Request.Context(), orgID.(string), period, values)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatPeriod) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "period must be one of: day, week"})
+			return
 		}
-	}
-
-	successRate := float64(0)
-	if len(deployments) > 0 {
-		successRate = float64(completedDeployments) / float64(len(deployments)) * 100
-	}
-
-	avgDeployTime := 0
-	if completedCount > 0 {
-		avgDeployTime = totalDuration / completedCount
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate deployment statistics"})
+		return
 	}
 
 	stats := gin.H{
-		"activeDeployments":        activeDeployments,
-		"activeDeploymentsChange":  "+8%",
-		"activeDeploymentsTrend":   "up",
-		"successRate":              successRate,
-		"successRateChange":        "+2%",
-		"successRateTrend":         "up",
-		"failedDeployments":        failedDeployments,
-		"failedDeploymentsChange":  "-5%",
-		"failedDeploymentsTrend":   "down",
-		"avgDeployTime":            avgDeployTime,
-		"avgDeployTimeChange":      "-10%",
-		"avgDeployTimeTrend":       "down",
+		"activeDeployments":        int(values[services.StatActiveDeployments]),
+		"activeDeploymentsChange":  changes[services.StatActiveDeployments].Change,
+		"activeDeploymentsTrend":   changes[services.StatActiveDeployments].Trend,
+		"successRate":              values[services.StatDeploymentSuccessRate],
+		"successRateChange":        changes[services.StatDeploymentSuccessRate].Change,
+		"successRateTrend":         changes[services.StatDeploymentSuccessRate].Trend,
+		"failedDeployments":        int(values[services.StatFailedDeployments]),
+		"failedDeploymentsChange":  changes[services.StatFailedDeployments].Change,
+		"failedDeploymentsTrend":   changes[services.StatFailedDeployments].Trend,
+		"avgDeployTime":            int(values[services.StatAvgDeployTime]),
+		"avgDeployTimeChange":      changes[services.StatAvgDeployTime].Change,
+		"avgDeployTimeTrend":       changes[services.StatAvgDeployTime].Trend,
+		"period":                   period,
 	}
 
 	c.JSON(http.StatusOK, stats)
@@ -442,116 +550,230 @@ func (h *DeploymentHandler) GetRecentDeployments(c *gin.Context) {
 	c.JSON(http.StatusOK, deployments)
 }
 
-// GetPipelines returns deployment pipelines
+// GetPipelines returns the organization's deployment pipelines
 func (h *DeploymentHandler) GetPipelines(c *gin.Context) {
-	_, exists := c.Get("organizationId")
+	orgID, exists := c.Get("organizationId")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
 		return
 	}
 
-	// Mock pipeline data - in real implementation, this would come from actual pipeline data
-	pipelines := []gin.H{
-		{
-			"id":         "pipeline-1",
-			"name":       "Web App CI/CD",
-			"repository": "github.com/company/web-app",
-			"branch":     "main",
-			"status":     "success",
-			"lastRun":    "2025-01-27T10:00:00Z",
-			"nextRun":    "2025-01-27T14:00:00Z",
-			"stages": []gin.H{
-				{
-					"id":          "stage-1",
-					"name":        "Build",
-					"status":      "success",
-					"duration":    120,
-					"startedAt":   "2025-01-27T10:00:00Z",
-					"completedAt": "2025-01-27T10:02:00Z",
-				},
-				{
-					"id":          "stage-2",
-					"name":        "Test",
-					"status":      "success",
-					"duration":    180,
-					"startedAt":   "2025-01-27T10:02:00Z",
-					"completedAt": "2025-01-27T10:05:00Z",
-				},
-				{
-					"id":          "stage-3",
-					"name":        "Deploy",
-					"status":      "success",
-					"duration":    300,
-					"startedAt":   "2025-01-27T10:05:00Z",
-					"completedAt": "2025-01-27T10:10:00Z",
-				},
-			},
-		},
-		{
-			"id":         "pipeline-2",
-			"name":       "API Service CI/CD",
-			"repository": "github.com/company/api-service",
-			"branch":     "develop",
-			"status":     "running",
-			"lastRun":    "2025-01-27T11:00:00Z",
-			"stages": []gin.H{
-				{
-					"id":        "stage-1",
-					"name":      "Build",
-					"status":    "success",
-					"duration":  90,
-					"startedAt": "2025-01-27T11:00:00Z",
-				},
-				{
-					"id":        "stage-2",
-					"name":      "Test",
-					"status":    "running",
-					"startedAt": "2025-01-27T11:01:30Z",
-				},
-				{
-					"id":     "stage-3",
-					"name":   "Deploy",
-					"status": "pending",
-				},
-			},
-		},
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	pipelines, err := h.repoManager.Pipeline.List(c.Request.Context(), orgID.(string), repositories.ListParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get pipelines"})
+		return
+	}
+
+	// Ensure we return an empty array instead of null
+	if pipelines == nil {
+		pipelines = []*models.Pipeline{}
 	}
 
 	c.JSON(http.StatusOK, pipelines)
 }
 
-// GetEnvironments returns available deployment environments
+// CreatePipeline creates a new deployment pipeline
+func (h *DeploymentHandler) CreatePipeline(c *gin.Context) {
+	var req models.CreatePipelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	pipeline := &models.Pipeline{
+		ID:             uuid.New().String(),
+		OrganizationID: orgID.(string),
+		Name:           req.Name,
+		Repository:     req.Repository,
+		Branch:         req.Branch,
+		Status:         models.PipelineStatusPending,
+		Stages:         newPipelineStages(req.Stages),
+		NextRun:        req.NextRun,
+	}
+	if userID := c.GetString("userID"); userID != "" {
+		pipeline.CreatedBy = &userID
+	}
+
+	if err := h.repoManager.Pipeline.Create(c.Request.Context(), pipeline); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pipeline)
+}
+
+// GetPipeline retrieves a specific pipeline
+func (h *DeploymentHandler) GetPipeline(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	pipeline, err := h.repoManager.Pipeline.GetByID(c.Request.Context(), c.Param("id"), orgID.(string))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pipeline)
+}
+
+// UpdatePipeline updates a pipeline's details or stages
+func (h *DeploymentHandler) UpdatePipeline(c *gin.Context) {
+	var req models.UpdatePipelineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	pipeline, err := h.repoManager.Pipeline.GetByID(c.Request.Context(), c.Param("id"), orgID.(string))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Update fields if provided
+	if req.Name != nil {
+		pipeline.Name = *req.Name
+	}
+	if req.Repository != nil {
+		pipeline.Repository = *req.Repository
+	}
+	if req.Branch != nil {
+		pipeline.Branch = *req.Branch
+	}
+	if req.NextRun != nil {
+		pipeline.NextRun = req.NextRun
+	}
+	if req.Stages != nil {
+		pipeline.Stages = newPipelineStages(req.Stages)
+		pipeline.Status = models.PipelineStatusPending
+	}
+
+	if err := h.repoManager.Pipeline.Update(c.Request.Context(), pipeline); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, pipeline)
+}
+
+// DeletePipeline deletes a pipeline
+func (h *DeploymentHandler) DeletePipeline(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	if err := h.repoManager.Pipeline.Delete(c.Request.Context(), c.Param("id"), orgID.(string)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// newPipelineStages builds pending stages in the order they were requested
+func newPipelineStages(reqs []models.PipelineStageRequest) []models.PipelineStage {
+	stages := make([]models.PipelineStage, 0, len(reqs))
+	for _, req := range reqs {
+		stages = append(stages, models.PipelineStage{
+			ID:     uuid.New().String(),
+			Name:   req.Name,
+			Status: models.PipelineStatusPending,
+		})
+	}
+	return stages
+}
+
+// GetEnvironments returns the environments the organization deploys to, with their health
 func (h *DeploymentHandler) GetEnvironments(c *gin.Context) {
-	environments := []gin.H{
-		{
-			"id":            models.EnvironmentDevelopment,
-			"name":          "Development",
-			"status":        "healthy",
-			"servicesCount": 4,
-			"uptime":        98.8,
-		},
-		{
-			"id":            models.EnvironmentStaging,
-			"name":          "Staging",
-			"status":        "healthy",
-			"servicesCount": 6,
-			"uptime":        99.5,
-		},
-		{
-			"id":            models.EnvironmentProduction,
-			"name":          "Production",
-			"status":        "healthy",
-			"servicesCount": 8,
-			"uptime":        99.9,
-		},
-		{
-			"id":            models.EnvironmentTesting,
-			"name":          "Testing",
-			"status":        "healthy",
-			"servicesCount": 3,
-			"uptime":        97.2,
-		},
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	environments, err := h.deploymentService.GetEnvironmentHealth(c.Request.Context(), orgID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get environments"})
+		return
 	}
 
 	c.JSON(http.StatusOK, environments)
 }
+
+// CreateEnvironment defines a custom deployment environment for the organization
+func (h *DeploymentHandler) CreateEnvironment(c *gin.Context) {
+	var req models.CreateEnvironmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	if _, builtIn := models.EnvironmentNames[req.Key]; builtIn {
+		c.JSON(http.StatusConflict, gin.H{"error": "A built-in environment with this key already exists"})
+		return
+	}
+
+	environment := &models.Environment{
+		ID:             uuid.New().String(),
+		OrganizationID: orgID.(string),
+		Key:            req.Key,
+		Name:           req.Name,
+		Description:    req.Description,
+	}
+
+	if err := h.repoManager.Environment.Create(c.Request.Context(), environment); err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, environment)
+}
+
+// DeleteEnvironment removes a custom deployment environment
+func (h *DeploymentHandler) DeleteEnvironment(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	if err := h.repoManager.Environment.Delete(c.Request.Context(), c.Param("id"), orgID.(string)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImpersonationHandler handles support users acting as a user of their organization
+type ImpersonationHandler struct {
+	impersonationService *services.ImpersonationService
+}
+
+// NewImpersonationHandler creates a new impersonation handler
+func NewImpersonationHandler(impersonationService *services.ImpersonationService) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		impersonationService: impersonationService,
+	}
+}
+
+// StartImpersonation handles POST /api/v1/admin/impersonate/:userId, issuing a short-lived token
+// acting as the user. Only users signed in with their own token can impersonate; API keys cannot.
+func (h *ImpersonationHandler) StartImpersonation(c *gin.Context) {
+	if c.GetString("authMethod") == "api_key" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "API keys cannot impersonate users"})
+		return
+	}
+
+	var req models.StartImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.impersonationService.Start(c.Request.Context(), c.GetString("userID"), c.GetString("organizationId"), c.Param("userId"), req.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrImpersonationTargetNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case errors.Is(err, services.ErrImpersonationNotAllowed):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start impersonation"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, session)
+}
+
+// EndImpersonation handles DELETE /api/v1/admin/impersonate, revoking the impersonation token the
+// request is made with
+func (h *ImpersonationHandler) EndImpersonation(c *gin.Context) {
+	err := h.impersonationService.End(c.Request.Context(), c.GetString("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrNotImpersonating) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Not impersonating a user"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to end impersonation"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
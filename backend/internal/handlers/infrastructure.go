@@ -1,8 +1,15 @@
 package handlers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"cloudweave/internal/models"
@@ -16,12 +23,14 @@ import (
 type InfrastructureHandler struct {
 	repoManager  *repositories.RepositoryManager
 	infraService *services.InfrastructureService
+	statsService *services.StatsService
 }
 
-func NewInfrastructureHandler(repoManager *repositories.RepositoryManager, infraService *services.InfrastructureService) *InfrastructureHandler {
+func NewInfrastructureHandler(repoManager *repositories.RepositoryManager, infraService *services.InfrastructureService, statsService *services.StatsService) *InfrastructureHandler {
 	return &InfrastructureHandler{
 		repoManager:  repoManager,
 		infraService: infraService,
+		statsService: statsService,
 	}
 }
 
@@ -68,6 +77,20 @@ func (h *InfrastructureHandler) CreateInfrastructure(c *gin.Context) {
 		return
 	}
 
+	tags, err := services.NormalizeTags(req.Tags)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "VALIDATION_ERROR",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
 	infrastructure := &models.Infrastructure{
 		ID:             uuid.New().String(),
 		OrganizationID: orgID.(string),
@@ -77,12 +100,122 @@ func (h *InfrastructureHandler) CreateInfrastructure(c *gin.Context) {
 		Region:         req.Region,
 		Status:         models.InfraStatusPending,
 		Specifications: req.Specifications,
-		Tags:           req.Tags,
+		Tags:           tags,
+	}
+
+	// A dry run asks the provider to validate the resource and reports what would be created
+	if c.Query("dryRun") == "true" {
+		plan, err := h.infraService.PlanInfrastructure(c.Request.Context(), infrastructure)
+		if err != nil {
+			respondCreateInfrastructureError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, plan)
+		return
 	}
 
 	// Create infrastructure resource through service layer
 	if err := h.infraService.CreateInfrastructure(c.Request.Context(), infrastructure); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondCreateInfrastructureError(c, err)
+		return
+	}
+
+	// Provisioning continues in the background; the resource stays pending until it finishes
+	c.JSON(http.StatusAccepted, infrastructure)
+}
+
+// respondCreateInfrastructureError maps an error from creating or planning a resource to a response
+func respondCreateInfrastructureError(c *gin.Context, err error) {
+	var specErr *services.SpecValidationError
+	if errors.As(err, &specErr) {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "INVALID_SPECIFICATIONS",
+				Message:   specErr.Error(),
+				Details:   specErr,
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+	var regionErr *services.RegionValidationError
+	if errors.As(err, &regionErr) {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "INVALID_REGION",
+				Message:   regionErr.Error(),
+				Details:   regionErr,
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrQuotaExceeded) {
+		c.JSON(http.StatusForbidden, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "QUOTA_EXCEEDED",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrCreateRejected) {
+		c.JSON(http.StatusUnprocessableEntity, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "CREATE_REJECTED",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+	if errors.Is(err, services.ErrProviderTimeout) {
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+// ImportInfrastructure brings an existing cloud resource under CloudWeave management
+func (h *InfrastructureHandler) ImportInfrastructure(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req models.ImportInfrastructureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The provider segment shares the :id wildcard with the per-resource routes
+	provider := c.Param("id")
+
+	infrastructure, err := h.infraService.ImportInfrastructure(c.Request.Context(), orgID.(string), provider, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnsupportedProvider), errors.Is(err, services.ErrInvalidTag):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrCloudResourceNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrInfrastructureAlreadyImported):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrProviderTimeout):
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		}
 		return
 	}
 
@@ -135,6 +268,8 @@ func (h *InfrastructureHandler) UpdateInfrastructure(c *gin.Context) {
 		return
 	}
 
+	previous := *infrastructure
+
 	// Update fields if provided
 	if req.Name != nil {
 		infrastructure.Name = *req.Name
@@ -149,7 +284,12 @@ func (h *InfrastructureHandler) UpdateInfrastructure(c *gin.Context) {
 		infrastructure.CostInfo = req.CostInfo
 	}
 	if req.Tags != nil {
-		infrastructure.Tags = req.Tags
+		tags, err := services.NormalizeTags(req.Tags)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		infrastructure.Tags = tags
 	}
 	if req.ExternalID != nil {
 		infrastructure.ExternalID = req.ExternalID
@@ -160,6 +300,16 @@ func (h *InfrastructureHandler) UpdateInfrastructure(c *gin.Context) {
 		return
 	}
 
+	// Push tag changes to the cloud resource; tags it rejects are reported, not rolled back
+	if req.Tags != nil {
+		h.infraService.SyncTags(c.Request.Context(), infrastructure, previous.Tags)
+	}
+
+	// Drop cached provider data, including under the previous external ID if it changed
+	h.infraService.InvalidateCachedResource(&previous)
+	h.infraService.InvalidateCachedResource(infrastructure)
+	h.infraService.PublishEvent(c.Request.Context(), models.WebhookEventInfrastructureUpdated, infrastructure)
+
 	c.JSON(http.StatusOK, infrastructure)
 }
 
@@ -178,9 +328,13 @@ func (h *InfrastructureHandler) DeleteInfrastructure(c *gin.Context) {
 		return
 	}
 
-	// Delete from cloud provider if it has an external ID
-	if infrastructure.ExternalID != nil {
+	// Delete from cloud provider if it has an external ID, unless only the record should be removed
+	if infrastructure.ExternalID != nil && c.Query("deprovision") != "false" {
 		if err := h.infraService.DeleteFromProvider(c.Request.Context(), infrastructure); err != nil {
+			if errors.Is(err, services.ErrProviderTimeout) {
+				c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Failed to delete from cloud provider: " + err.Error()})
+				return
+			}
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete from cloud provider: " + err.Error()})
 			return
 		}
@@ -190,10 +344,91 @@ func (h *InfrastructureHandler) DeleteInfrastructure(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	h.infraService.PublishEvent(c.Request.Context(), models.WebhookEventInfrastructureDeleted, infrastructure)
 
 	c.JSON(http.StatusNoContent, nil)
 }
 
+// RestoreInfrastructure restores a deleted infrastructure resource within the retention window
+func (h *InfrastructureHandler) RestoreInfrastructure(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	infrastructure, err := h.infraService.RestoreInfrastructure(c.Request.Context(), orgID.(string), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrInfrastructureNotRestorable) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, infrastructure)
+}
+
+// AddInfrastructureTags adds tags to an infrastructure resource without a full update
+func (h *InfrastructureHandler) AddInfrastructureTags(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req models.InfrastructureTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	infrastructure, err := h.infraService.AddTags(c.Request.Context(), orgID.(string), c.Param("id"), req.Tags)
+	if err != nil {
+		h.handleTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, infrastructure)
+}
+
+// RemoveInfrastructureTags removes the tags given as repeated ?tag= parameters from an infrastructure
+// resource; a bare key removes that key whatever its value
+func (h *InfrastructureHandler) RemoveInfrastructureTags(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	tags := c.QueryArray("tag")
+	if len(tags) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least one tag is required"})
+		return
+	}
+
+	infrastructure, err := h.infraService.RemoveTags(c.Request.Context(), orgID.(string), c.Param("id"), tags)
+	if err != nil {
+		h.handleTagError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, infrastructure)
+}
+
+// handleTagError maps tag update errors to HTTP responses
+func (h *InfrastructureHandler) handleTagError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTag):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInfrastructureNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
 // ListInfrastructure lists infrastructure resources with filtering
 func (h *InfrastructureHandler) ListInfrastructure(c *gin.Context) {
 	orgID, exists := c.Get("organizationId")
@@ -204,8 +439,10 @@ func (h *InfrastructureHandler) ListInfrastructure(c *gin.Context) {
 
 	// Parse query parameters
 	params := repositories.ListParams{
-		Limit:  50, // default
-		Offset: 0,
+		Limit:          50, // default
+		Offset:         0,
+		IncludeDeleted: c.Query("includeDeleted") == "true",
+		Cursor:         c.Query("cursor"),
 	}
 
 	if limitStr := c.Query("limit"); limitStr != "" {
@@ -220,6 +457,16 @@ func (h *InfrastructureHandler) ListInfrastructure(c *gin.Context) {
 		}
 	}
 
+	// Every tag must match (?tag=env=prod&tag=team=web)
+	if tags := c.QueryArray("tag"); len(tags) > 0 {
+		normalized, err := services.NormalizeTags(tags)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		params.Tags = normalized
+	}
+
 	provider := c.Query("provider")
 	status := c.Query("status")
 	infraType := c.Query("type")
@@ -237,10 +484,21 @@ func (h *InfrastructureHandler) ListInfrastructure(c *gin.Context) {
 	}
 
 	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// A full page may have more results after it; the cursor is taken before post-query filtering
+	var nextCursor string
+	if len(infrastructures) == params.Limit {
+		last := infrastructures[len(infrastructures)-1]
+		nextCursor = repositories.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
 	// Filter by type if specified (post-query filtering for simplicity)
 	if infraType != "" {
 		filtered := make([]*models.Infrastructure, 0)
@@ -253,10 +511,11 @@ func (h *InfrastructureHandler) ListInfrastructure(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":   infrastructures,
-		"count":  len(infrastructures),
-		"limit":  params.Limit,
-		"offset": params.Offset,
+		"data":       infrastructures,
+		"count":      len(infrastructures),
+		"limit":      params.Limit,
+		"offset":     params.Offset,
+		"nextCursor": nextCursor,
 	})
 }
 
@@ -276,6 +535,10 @@ func (h *InfrastructureHandler) GetInfrastructureMetrics(c *gin.Context) {
 
 	metrics, err := h.infraService.GetMetrics(c.Request.Context(), infrastructure)
 	if err != nil {
+		if errors.Is(err, services.ErrProviderTimeout) {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -283,6 +546,37 @@ func (h *InfrastructureHandler) GetInfrastructureMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, metrics)
 }
 
+// GetInfrastructureDrift compares a resource's recorded state with its current state at the provider
+func (h *InfrastructureHandler) GetInfrastructureDrift(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	infrastructure, err := h.repoManager.Infrastructure.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil || infrastructure.OrganizationID != orgID.(string) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Infrastructure resource not found"})
+		return
+	}
+
+	report, err := h.infraService.DetectDrift(c.Request.Context(), infrastructure)
+	if err != nil {
+		if errors.Is(err, services.ErrInfrastructureNotManaged) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrProviderTimeout) {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
 // SyncInfrastructure syncs infrastructure state with cloud provider
 func (h *InfrastructureHandler) SyncInfrastructure(c *gin.Context) {
 	id := c.Param("id")
@@ -299,6 +593,10 @@ func (h *InfrastructureHandler) SyncInfrastructure(c *gin.Context) {
 
 	updatedInfra, err := h.infraService.SyncWithProvider(c.Request.Context(), infrastructure)
 	if err != nil {
+		if errors.Is(err, services.ErrProviderTimeout) {
+			c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -306,23 +604,46 @@ func (h *InfrastructureHandler) SyncInfrastructure(c *gin.Context) {
 	c.JSON(http.StatusOK, updatedInfra)
 }
 
-// GetProviders returns available cloud providers
+// SyncAllInfrastructure queues a job that syncs every managed resource of the organization with
+// its cloud provider. Poll the job at /jobs/:id for its summary.
+func (h *InfrastructureHandler) SyncAllInfrastructure(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	job, err := h.infraService.StartSyncAll(c.Request.Context(), orgID.(string), c.GetString("userID"))
+	if err != nil {
+		if errors.Is(err, services.ErrJobInProgress) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start infrastructure sync"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetProviders returns available cloud providers with the regions resources can be created in
 func (h *InfrastructureHandler) GetProviders(c *gin.Context) {
+	ctx := c.Request.Context()
 	providers := []gin.H{
 		{
 			"id":      models.ProviderAWS,
 			"name":    "Amazon Web Services",
-			"regions": []string{"us-east-1", "us-west-2", "eu-west-1", "ap-southeast-1"},
+			"regions": h.infraService.ListRegions(ctx, models.ProviderAWS),
 		},
 		{
 			"id":      models.ProviderGCP,
 			"name":    "Google Cloud Platform",
-			"regions": []string{"us-central1", "us-east1", "europe-west1", "asia-southeast1"},
+			"regions": h.infraService.ListRegions(ctx, models.ProviderGCP),
 		},
 		{
 			"id":      models.ProviderAzure,
 			"name":    "Microsoft Azure",
-			"regions": []string{"eastus", "westus2", "westeurope", "southeastasia"},
+			"regions": h.infraService.ListRegions(ctx, models.ProviderAzure),
 		},
 	}
 
@@ -337,12 +658,30 @@ func (h *InfrastructureHandler) GetInfrastructureStats(c *gin.Context) {
 		return
 	}
 
-	// Add cache headers to help with client-side caching
-	c.Header("Cache-Control", "public, max-age=30")
-	c.Header("ETag", `"stats-v1"`)
+	// Trends compare against the snapshot from one period ago (?period=day|week)
+	period := c.DefaultQuery("period", services.DefaultStatPeriod)
+
+	// Add cache headers to help with client-side caching. Statistics also depend on the earlier
+	// snapshot and the compliance score, so the ETag covers those too.
+	statsVersion, err := h.statsService.StatsVersion(c.Request.Context(), orgID.(string), period)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatPeriod) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "period must be one of: day, week"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure data"})
+		return
+	}
+	etag, err := h.infrastructureETag(c, orgID.(string), "stats:"+period+":"+statsVersion)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure data"})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=30")
+	c.Header("ETag", etag)
 
 	// Check if client has cached version
-	if match := c.GetHeader("If-None-Match"); match == `"stats-v1"` {
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
 		c.Status(http.StatusNotModified)
 		return
 	}
@@ -357,38 +696,16 @@ func (h *InfrastructureHandler) GetInfrastructureStats(c *gin.Context) {
 		return
 	}
 
-	// Calculate statistics efficiently
-	totalResources := len(infrastructures)
-	activeInstances := 0
-	networks := 0
-	
-	for _, infra := range infrastructures {
-		if infra.Status == models.InfraStatusRunning {
-			activeInstances++
-		}
-		if infra.Type == models.InfraTypeNetwork {
-			networks++
+	stats, err := h.calculateStats(c.Request.Context(), orgID.(string), period, infrastructures)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidStatPeriod) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "period must be one of: day, week"})
+			return
 		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate infrastructure statistics"})
+		return
 	}
-
-	// Mock compliance score for now - in production, this would be calculated
-	complianceScore := 98
-
-	stats := gin.H{
-		"totalResources":         totalResources,
-		"totalResourcesChange":   "+12%",
-		"totalResourcesTrend":    "up",
-		"activeInstances":        activeInstances,
-		"activeInstancesChange":  "+8%",
-		"activeInstancesTrend":   "up",
-		"networks":               networks,
-		"networksChange":         "+2%",
-		"networksTrend":          "up",
-		"complianceScore":        complianceScore,
-		"complianceScoreChange":  "+1%",
-		"complianceScoreTrend":   "up",
-		"lastUpdated":            time.Now().Unix(),
-	}
+	stats["lastUpdated"] = time.Now().Unix()
 
 	c.JSON(http.StatusOK, stats)
 }
@@ -402,11 +719,16 @@ func (h *InfrastructureHandler) GetResourceDistribution(c *gin.Context) {
 	}
 
 	// Add cache headers
-	c.Header("Cache-Control", "public, max-age=60")
-	c.Header("ETag", `"distribution-v1"`)
+	etag, err := h.infrastructureETag(c, orgID.(string), "distribution")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure data"})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=60")
+	c.Header("ETag", etag)
 
 	// Check if client has cached version
-	if match := c.GetHeader("If-None-Match"); match == `"distribution-v1"` {
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
 		c.Status(http.StatusNotModified)
 		return
 	}
@@ -427,7 +749,7 @@ func (h *InfrastructureHandler) GetResourceDistribution(c *gin.Context) {
 		models.InfraTypeServer:    "ec2Instances",
 		models.InfraTypeStorage:   "s3Buckets",
 		models.InfraTypeDatabase:  "rdsDatabases",
-		models.InfraTypeContainer: "lambdaFunctions",
+		models.InfraTypeContainer: "ecsServices",
 	}
 
 	for _, infra := range infrastructures {
@@ -438,18 +760,18 @@ func (h *InfrastructureHandler) GetResourceDistribution(c *gin.Context) {
 
 	// Ensure all types are present
 	result := gin.H{
-		"ec2Instances":    distribution["ec2Instances"],
-		"s3Buckets":       distribution["s3Buckets"],
-		"rdsDatabases":    distribution["rdsDatabases"],
-		"lambdaFunctions": distribution["lambdaFunctions"],
-		"totalCount":      len(infrastructures),
-		"lastUpdated":     time.Now().Unix(),
+		"ec2Instances": distribution["ec2Instances"],
+		"s3Buckets":    distribution["s3Buckets"],
+		"rdsDatabases": distribution["rdsDatabases"],
+		"ecsServices":  distribution["ecsServices"],
+		"totalCount":   len(infrastructures),
+		"lastUpdated":  time.Now().Unix(),
 	}
 
 	c.JSON(http.StatusOK, result)
 }
 
-// GetRecentChanges returns recent infrastructure changes
+// GetRecentChanges returns the latest entries of the organization's infrastructure change log
 func (h *InfrastructureHandler) GetRecentChanges(c *gin.Context) {
 	orgID, exists := c.Get("organizationId")
 	if !exists {
@@ -457,46 +779,34 @@ func (h *InfrastructureHandler) GetRecentChanges(c *gin.Context) {
 		return
 	}
 
+	entries, err := h.repoManager.Infrastructure.ListRecentChanges(c.Request.Context(), orgID.(string), 10)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure changes"})
+		return
+	}
+
 	// Add cache headers with shorter TTL for recent changes
-	c.Header("Cache-Control", "public, max-age=15")
-	c.Header("ETag", `"changes-v1"`)
+	etag := changeLogETag(orgID.(string), entries)
+	c.Header("Cache-Control", "private, max-age=15")
+	c.Header("ETag", etag)
 
 	// Check if client has cached version
-	if match := c.GetHeader("If-None-Match"); match == `"changes-v1"` {
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
 		c.Status(http.StatusNotModified)
 		return
 	}
 
-	// Get recent infrastructure changes (last 10)
-	infrastructures, err := h.repoManager.Infrastructure.List(c.Request.Context(), orgID.(string), repositories.ListParams{
-		Limit:  10,
-		Offset: 0,
-	})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure data"})
-		return
-	}
-
-	// Convert to recent changes format efficiently
-	changes := make([]gin.H, 0, len(infrastructures))
-	for _, infra := range infrastructures {
-		changeType := "created"
-		timestamp := infra.CreatedAt
-		
-		if infra.UpdatedAt.After(infra.CreatedAt) {
-			changeType = "updated"
-			timestamp = infra.UpdatedAt
-		}
-
+	changes := make([]gin.H, 0, len(entries))
+	for _, entry := range entries {
 		changes = append(changes, gin.H{
-			"id":           infra.ID,
-			"message":      "Infrastructure \"" + infra.Name + "\" " + changeType,
-			"timestamp":    timestamp,
-			"type":         changeType,
-			"resourceId":   infra.ID,
-			"resourceName": infra.Name,
-			"provider":     infra.Provider,
-			"status":       infra.Status,
+			"id":           entry.ID,
+			"message":      changeMessage(entry),
+			"timestamp":    entry.ChangedAt,
+			"type":         entry.ChangeType,
+			"resourceId":   entry.InfrastructureID,
+			"resourceName": entry.ResourceName,
+			"provider":     entry.Provider,
+			"changes":      entry.Changes,
 		})
 	}
 
@@ -509,6 +819,81 @@ func (h *InfrastructureHandler) GetRecentChanges(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetInfrastructureHistory returns a resource's change log, newest first
+func (h *InfrastructureHandler) GetInfrastructureHistory(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	infrastructure, err := h.repoManager.Infrastructure.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil || infrastructure.OrganizationID != orgID.(string) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Infrastructure resource not found"})
+		return
+	}
+
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if parsed, err := strconv.Atoi(offsetStr); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	history, err := h.repoManager.Infrastructure.ListChanges(c.Request.Context(), infrastructure.ID, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure history"})
+		return
+	}
+	if history == nil {
+		history = []*models.InfrastructureChange{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"history": history,
+		"count":   len(history),
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// changeMessage describes a change log entry for display
+func changeMessage(change *models.InfrastructureChange) string {
+	subject := "Infrastructure \"" + change.ResourceName + "\" "
+	switch change.ChangeType {
+	case models.InfraChangeStatusChanged:
+		if status, ok := change.Changes["status"].After.(string); ok {
+			return subject + "status changed to " + status
+		}
+	case models.InfraChangeSynced:
+		return subject + "synced with " + change.Provider
+	case models.InfraChangeSyncFailed:
+		return subject + "failed to sync with " + change.Provider
+	case models.InfraChangeSnapshotted:
+		if change.Message != nil {
+			return subject + "snapshotted as " + *change.Message
+		}
+		return subject + "snapshotted"
+	case models.InfraChangeScaled, models.InfraChangeScaleFailed:
+		verb := "scaled"
+		if change.ChangeType == models.InfraChangeScaleFailed {
+			verb = "failed to scale"
+		}
+		for _, field := range change.Changes {
+			return fmt.Sprintf("%s%s from %v to %v", subject, verb, field.Before, field.After)
+		}
+		return subject + verb
+	}
+	return subject + change.ChangeType
+}
+
 // GetCostBreakdown retrieves cost breakdown for the organization
 func (h *InfrastructureHandler) GetCostBreakdown(c *gin.Context) {
 	orgID, exists := c.Get("organizationId")
@@ -546,6 +931,10 @@ func (h *InfrastructureHandler) GetCostByTags(c *gin.Context) {
 	costService := services.NewCostManagementService(h.repoManager, h.infraService.GetProviders())
 	costByTags, err := costService.GetCostByTags(c.Request.Context(), orgID.(string), tags)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidTag) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -615,8 +1004,14 @@ func (h *InfrastructureHandler) GetAggregatedMetrics(c *gin.Context) {
 		return
 	}
 
+	duration, err := time.ParseDuration(c.DefaultQuery("duration", "1h"))
+	if err != nil || duration <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duration format"})
+		return
+	}
+
 	metricsService := services.NewMetricsService(h.repoManager, h.infraService.GetProviders())
-	metrics, err := metricsService.GetAggregatedMetrics(c.Request.Context(), orgID.(string))
+	metrics, err := metricsService.GetAggregatedMetrics(c.Request.Context(), orgID.(string), duration)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -643,6 +1038,89 @@ func (h *InfrastructureHandler) CollectMetrics(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Metrics collection completed"})
 }
 
+// ExportInfrastructure queues a job that exports the organization's stored infrastructure as
+// Terraform or CloudFormation (?format=terraform|cloudformation). The file is downloaded from
+// /jobs/:id/download once the job completes.
+func (h *InfrastructureHandler) ExportInfrastructure(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	job, err := h.infraService.StartExport(c.Request.Context(), orgID.(string), c.GetString("userID"), c.DefaultQuery("format", services.ExportFormatTerraform))
+	if err != nil {
+		if errors.Is(err, services.ErrUnsupportedExportFormat) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "format must be one of: terraform, cloudformation"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export infrastructure"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetQuota reports the organization's resources and estimated monthly cost against its quota
+func (h *InfrastructureHandler) GetQuota(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	usage, err := h.infraService.GetQuotaUsage(c.Request.Context(), orgID.(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get quota usage"})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// UpdateQuota replaces the organization's quota
+func (h *InfrastructureHandler) UpdateQuota(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	var req models.UpdateQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	quota, err := h.infraService.UpdateQuota(c.Request.Context(), orgID.(string), c.GetString("userID"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidQuota) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update quota"})
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// DeleteQuota removes the organization's quota, lifting all of its limits
+func (h *InfrastructureHandler) DeleteQuota(c *gin.Context) {
+	orgID, exists := c.Get("organizationId")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Organization ID not found"})
+		return
+	}
+
+	if err := h.infraService.DeleteQuota(c.Request.Context(), orgID.(string)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete quota"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // GetInfrastructureBatch returns multiple infrastructure data types in a single request
 func (h *InfrastructureHandler) GetInfrastructureBatch(c *gin.Context) {
 	orgID, exists := c.Get("organizationId")
@@ -658,8 +1136,40 @@ func (h *InfrastructureHandler) GetInfrastructureBatch(c *gin.Context) {
 		requestedTypes = []string{"stats", "distribution", "recent-changes"}
 	}
 
-	// Add cache headers
-	c.Header("Cache-Control", "public, max-age=30")
+	period := c.DefaultQuery("period", services.DefaultStatPeriod)
+
+	// Add cache headers; the ETag covers the requested types since each yields a different body,
+	// and the inputs to the statistics beyond the resources when they are requested
+	view := "batch:" + strings.Join(requestedTypes, ",") + ":" + period
+	for _, dataType := range requestedTypes {
+		if dataType != "stats" {
+			continue
+		}
+		statsVersion, err := h.statsService.StatsVersion(c.Request.Context(), orgID.(string), period)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidStatPeriod) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "period must be one of: day, week"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure data"})
+			return
+		}
+		view += ":" + statsVersion
+		break
+	}
+	etag, err := h.infrastructureETag(c, orgID.(string), view)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get infrastructure data"})
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=30")
+	c.Header("ETag", etag)
+
+	// Check if client has cached version
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
 	
 	result := gin.H{
 		"timestamp": time.Now().Unix(),
@@ -679,7 +1189,16 @@ func (h *InfrastructureHandler) GetInfrastructureBatch(c *gin.Context) {
 	for _, dataType := range requestedTypes {
 		switch dataType {
 		case "stats":
-			result["stats"] = h.calculateStats(infrastructures)
+			stats, err := h.calculateStats(c.Request.Context(), orgID.(string), period, infrastructures)
+			if err != nil {
+				if errors.Is(err, services.ErrInvalidStatPeriod) {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "period must be one of: day, week"})
+					return
+				}
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate infrastructure statistics"})
+				return
+			}
+			result["stats"] = stats
 		case "distribution":
 			result["distribution"] = h.calculateDistribution(infrastructures)
 		case "recent-changes":
@@ -691,34 +1210,32 @@ func (h *InfrastructureHandler) GetInfrastructureBatch(c *gin.Context) {
 }
 
 // Helper functions for batch processing
-func (h *InfrastructureHandler) calculateStats(infrastructures []*models.Infrastructure) gin.H {
-	totalResources := len(infrastructures)
-	activeInstances := 0
-	networks := 0
-	
-	for _, infra := range infrastructures {
-		if infra.Status == models.InfraStatusRunning {
-			activeInstances++
-		}
-		if infra.Type == models.InfraTypeNetwork {
-			networks++
-		}
+func (h *InfrastructureHandler) calculateStats(ctx context.Context, orgID, period string, infrastructures []*models.Infrastructure) (gin.H, error) {
+	values, err := h.statsService.InfrastructureStatValues(ctx, orgID, infrastructures)
+	if err != nil {
+		return nil, err
 	}
 
-	return gin.H{
-		"totalResources":         totalResources,
-		"totalResourcesChange":   "+12%",
-		"totalResourcesTrend":    "up",
-		"activeInstances":        activeInstances,
-		"activeInstancesChange":  "+8%",
-		"activeInstancesTrend":   "up",
-		"networks":               networks,
-		"networksChange":         "+2%",
-		"networksTrend":          "up",
-		"complianceScore":        98,
-		"complianceScoreChange":  "+1%",
-		"complianceScoreTrend":   "up",
+	changes, err := h.statsService.CompareWithPeriod(ctx, orgID, period, values)
+	if err != nil {
+		return nil, err
 	}
+
+	return gin.H{
+		"totalResources":        int(values[services.StatTotalResources]),
+		"totalResourcesChange":  changes[services.StatTotalResources].Change,
+		"totalResourcesTrend":   changes[services.StatTotalResources].Trend,
+		"activeInstances":       int(values[services.StatActiveInstances]),
+		"activeInstancesChange": changes[services.StatActiveInstances].Change,
+		"activeInstancesTrend":  changes[services.StatActiveInstances].Trend,
+		"networks":              int(values[services.StatNetworks]),
+		"networksChange":        changes[services.StatNetworks].Change,
+		"networksTrend":         changes[services.StatNetworks].Trend,
+		"complianceScore":       math.Round(values[services.StatComplianceScore]),
+		"complianceScoreChange": changes[services.StatComplianceScore].Change,
+		"complianceScoreTrend":  changes[services.StatComplianceScore].Trend,
+		"period":                period,
+	}, nil
 }
 
 func (h *InfrastructureHandler) calculateDistribution(infrastructures []*models.Infrastructure) gin.H {
@@ -727,7 +1244,7 @@ func (h *InfrastructureHandler) calculateDistribution(infrastructures []*models.
 		models.InfraTypeServer:    "ec2Instances",
 		models.InfraTypeStorage:   "s3Buckets",
 		models.InfraTypeDatabase:  "rdsDatabases",
-		models.InfraTypeContainer: "lambdaFunctions",
+		models.InfraTypeContainer: "ecsServices",
 	}
 
 	for _, infra := range infrastructures {
@@ -737,11 +1254,11 @@ func (h *InfrastructureHandler) calculateDistribution(infrastructures []*models.
 	}
 
 	return gin.H{
-		"ec2Instances":    distribution["ec2Instances"],
-		"s3Buckets":       distribution["s3Buckets"],
-		"rdsDatabases":    distribution["rdsDatabases"],
-		"lambdaFunctions": distribution["lambdaFunctions"],
-		"totalCount":      len(infrastructures),
+		"ec2Instances": distribution["ec2Instances"],
+		"s3Buckets":    distribution["s3Buckets"],
+		"rdsDatabases": distribution["rdsDatabases"],
+		"ecsServices":  distribution["ecsServices"],
+		"totalCount":   len(infrastructures),
 	}
 }
 
@@ -779,3 +1296,42 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+// infrastructureETag derives an ETag for a view of the organization's infrastructure from the number
+// of resources and the latest change, so it changes whenever the underlying data does
+func (h *InfrastructureHandler) infrastructureETag(c *gin.Context, orgID, view string) (string, error) {
+	count, lastChanged, err := h.repoManager.Infrastructure.GetVersion(c.Request.Context(), orgID)
+	if err != nil {
+		return "", err
+	}
+
+	var changed int64
+	if lastChanged != nil {
+		changed = lastChanged.UnixNano()
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d:%d", view, orgID, count, changed)))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// changeLogETag derives an ETag from the newest entries of an organization's change log
+func changeLogETag(orgID string, changes []*models.InfrastructureChange) string {
+	latest := ""
+	if len(changes) > 0 {
+		latest = changes[0].ID
+	}
+
+	sum := sha256.Sum256([]byte(fmt.Sprintf("recent-changes:%s:%d:%s", orgID, len(changes), latest)))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches an ETag
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+)
+
+// CreateInfrastructureSnapshot handles POST /api/v1/infrastructure/:id/snapshot, taking a snapshot
+// of a database resource at its provider
+func (h *InfrastructureHandler) CreateInfrastructureSnapshot(c *gin.Context) {
+	var req models.CreateSnapshotRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	snapshot, err := h.infraService.CreateSnapshot(c.Request.Context(), c.GetString("organizationId"), c.Param("id"), c.GetString("userID"), &req)
+	if err != nil {
+		h.handleSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, snapshot)
+}
+
+// ListInfrastructureSnapshots handles GET /api/v1/infrastructure/:id/snapshots, newest first
+func (h *InfrastructureHandler) ListInfrastructureSnapshots(c *gin.Context) {
+	snapshots, err := h.infraService.ListSnapshots(c.Request.Context(), c.GetString("organizationId"), c.Param("id"))
+	if err != nil {
+		h.handleSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"snapshots": snapshots})
+}
+
+// RestoreInfrastructureSnapshot handles POST /api/v1/infrastructure/:id/snapshots/:snapshotId/restore,
+// provisioning a new resource from the snapshot. The resource stays pending until it is restored.
+func (h *InfrastructureHandler) RestoreInfrastructureSnapshot(c *gin.Context) {
+	var req models.RestoreSnapshotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	infrastructure, err := h.infraService.RestoreSnapshot(c.Request.Context(), c.GetString("organizationId"), c.Param("id"), c.Param("snapshotId"), &req)
+	if err != nil {
+		h.handleSnapshotError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, infrastructure)
+}
+
+// handleSnapshotError maps snapshot errors to HTTP responses
+func (h *InfrastructureHandler) handleSnapshotError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInfrastructureNotFound), errors.Is(err, services.ErrSnapshotNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrSnapshotsUnsupported), errors.Is(err, services.ErrInvalidTag):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInfrastructureNotManaged), errors.Is(err, services.ErrSnapshotNotAvailable):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrQuotaExceeded):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrProviderTimeout):
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
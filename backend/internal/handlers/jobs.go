@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"cloudweave/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobHandler handles polling background jobs
+type JobHandler struct {
+	jobService *services.JobService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobService *services.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// GetJob returns the status, progress and, once it finishes, the result or error of one of the
+// organization's jobs
+func (h *JobHandler) GetJob(c *gin.Context) {
+	job, err := h.jobService.GetJob(c.Request.Context(), c.GetString("organizationId"), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, services.ErrJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// DownloadJobFile downloads the file a completed job produced, such as an infrastructure export
+func (h *JobHandler) DownloadJobFile(c *gin.Context) {
+	file, err := h.jobService.GetJobFile(c.Request.Context(), c.GetString("organizationId"), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrJobNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		case errors.Is(err, services.ErrJobHasNoFile):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job"})
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Filename))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
@@ -1,9 +1,14 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"cloudweave/internal/repositories"
 	"cloudweave/internal/services"
 	"github.com/gin-gonic/gin"
 )
@@ -83,6 +88,29 @@ func (h *MetricsHandler) GetResourceMetrics(c *gin.Context) {
 		return
 	}
 
+	// Paging through raw data points by cursor; the first page is requested with an empty cursor
+	if cursor, paged := c.GetQuery("cursor"); paged {
+		limit := 1000
+		if limitStr := c.Query("limit"); limitStr != "" {
+			if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 && parsed <= 10000 {
+				limit = parsed
+			}
+		}
+
+		metrics, nextCursor, err := h.metricsService.GetResourceMetricsPage(c.Request.Context(), resourceID, duration, cursor, limit)
+		if err != nil {
+			if errors.Is(err, repositories.ErrInvalidCursor) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"metrics": metrics, "nextCursor": nextCursor})
+		return
+	}
+
 	metrics, err := h.metricsService.GetResourceMetrics(c.Request.Context(), resourceID, duration)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -100,7 +128,15 @@ func (h *MetricsHandler) GetAggregatedMetrics(c *gin.Context) {
 		return
 	}
 
-	metrics, err := h.metricsService.GetAggregatedMetrics(c.Request.Context(), orgID)
+	// Parse aggregation window from query parameters (default: 1 hour)
+	durationStr := c.DefaultQuery("duration", "1h")
+	duration, err := time.ParseDuration(durationStr)
+	if err != nil || duration <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid duration format"})
+		return
+	}
+
+	metrics, err := h.metricsService.GetAggregatedMetrics(c.Request.Context(), orgID, duration)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -142,9 +178,147 @@ func (h *MetricsHandler) GetMetricDefinitions(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"definitions": definitions})
 }
 
-// StreamMetrics handles WebSocket connections for real-time metrics
+// metricsStreamHeartbeat is how often an idle metrics stream sends a comment to keep proxies
+// from closing the connection
+const metricsStreamHeartbeat = 15 * time.Second
+
+// StreamMetrics streams freshly collected metrics as Server-Sent Events. Clients can narrow the
+// stream with resource_id and a comma-separated metrics filter, e.g. metrics=cpu_utilization.
 func (h *MetricsHandler) StreamMetrics(c *gin.Context) {
-	// This would be implemented with WebSocket streaming
-	// For now, we'll return a simple response
-	c.JSON(http.StatusOK, gin.H{"message": "metrics streaming endpoint"})
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	var metricNames []string
+	if filter := c.Query("metrics"); filter != "" {
+		for _, name := range strings.Split(filter, ",") {
+			metricNames = append(metricNames, strings.TrimSpace(name))
+		}
+	}
+
+	sub, unsubscribe := h.metricsService.SubscribeMetrics(orgID, c.Query("resource_id"), metricNames)
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(metricsStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.Done:
+			return
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case metric := <-sub.C:
+			c.SSEvent("metric", metric)
+			c.Writer.Flush()
+		}
+	}
+}
+
+// Bounds on the window scaling recommendations look back over
+const (
+	minScalingWindow = time.Hour
+	maxScalingWindow = 30 * 24 * time.Hour
+)
+
+// GetScalingRecommendations recommends resizing resources whose utilization stayed high or low
+// over the window, e.g. window=72h
+func (h *MetricsHandler) GetScalingRecommendations(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	window := services.DefaultScalingWindow
+	if windowStr := c.Query("window"); windowStr != "" {
+		parsed, err := time.ParseDuration(windowStr)
+		if err != nil || parsed < minScalingWindow || parsed > maxScalingWindow {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "window must be a duration between 1h and 720h"})
+			return
+		}
+		window = parsed
+	}
+
+	recommendations, err := h.metricsService.GetScalingRecommendations(c.Request.Context(), orgID, window)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"recommendations": recommendations, "window": window.String()})
+}
+
+// ScaleResource starts resizing a resource one size up or down. The resize runs in the background
+// and its outcome is recorded in the resource's history.
+func (h *MetricsHandler) ScaleResource(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	var req struct {
+		Size string `json:"size" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "size is required"})
+		return
+	}
+
+	operation, err := h.metricsService.ScaleResource(c.Request.Context(), orgID, c.Param("id"), req.Size)
+	if err != nil {
+		respondScalingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"operation": operation})
+}
+
+// RollbackScaling starts resizing a resource back to its size before its last scaling
+func (h *MetricsHandler) RollbackScaling(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	operation, err := h.metricsService.RollbackScaling(c.Request.Context(), orgID, c.Param("id"))
+	if err != nil {
+		respondScalingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"operation": operation})
+}
+
+// respondScalingError maps a scaling error to its HTTP status
+func respondScalingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrScalingExecutionDisabled):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInfrastructureNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Infrastructure not found"})
+	case errors.Is(err, services.ErrScalingUnsupported), errors.Is(err, services.ErrInvalidScalingSize):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrScalingInProgress), errors.Is(err, services.ErrNoScalingToRollback):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnrollMFA starts TOTP enrollment for the current user
+func EnrollMFA(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "UNAUTHORIZED",
+				Message:   "Authentication required",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	user, err := authService.GetUserByID(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("Failed to get user %s for MFA enrollment: %v", userID, err)
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "USER_NOT_FOUND",
+				Message:   "User not found",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	enrollment, err := mfaService.Enroll(c.Request.Context(), user)
+	if err != nil {
+		log.Printf("MFA enrollment failed for user %s: %v", userID, err)
+
+		statusCode := http.StatusInternalServerError
+		errorCode := "MFA_ENROLL_FAILED"
+		if strings.Contains(err.Error(), "already enabled") {
+			statusCode = http.StatusConflict
+			errorCode = "MFA_ALREADY_ENABLED"
+		}
+
+		c.JSON(statusCode, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      errorCode,
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success:   true,
+		Data:      enrollment,
+		RequestID: c.GetString("requestID"),
+	})
+}
+
+// VerifyMFAEnrollment confirms TOTP enrollment with a code from the authenticator app
+func VerifyMFAEnrollment(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "UNAUTHORIZED",
+				Message:   "Authentication required",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "VALIDATION_ERROR",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	if err := mfaService.VerifyEnrollment(c.Request.Context(), userID, req.Code); err != nil {
+		log.Printf("MFA enrollment verification failed for user %s: %v", userID, err)
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "MFA_VERIFICATION_FAILED",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"mfaEnabled": true,
+		},
+		RequestID: c.GetString("requestID"),
+	})
+}
+
+// DisableMFA turns off MFA for the current user after checking a code
+func DisableMFA(c *gin.Context) {
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "UNAUTHORIZED",
+				Message:   "Authentication required",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	var req models.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "VALIDATION_ERROR",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	if err := mfaService.Disable(c.Request.Context(), userID, req.Code); err != nil {
+		log.Printf("Failed to disable MFA for user %s: %v", userID, err)
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "MFA_DISABLE_FAILED",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success: true,
+		Data: map[string]interface{}{
+			"mfaEnabled": false,
+		},
+		RequestID: c.GetString("requestID"),
+	})
+}
+
+// VerifyMFALogin exchanges an MFA token and a valid code for access and refresh tokens
+func VerifyMFALogin(c *gin.Context) {
+	var req models.MFAVerifyLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "VALIDATION_ERROR",
+				Message:   err.Error(),
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	response, err := authService.VerifyMFALogin(c.Request.Context(), req)
+	if err != nil {
+		log.Printf("MFA login verification failed: %v", err)
+
+		statusCode := http.StatusUnauthorized
+		errorCode := "MFA_VERIFICATION_FAILED"
+		errorMessage := "Invalid MFA code"
+
+		if errors.Is(err, services.ErrMFALocked) {
+			statusCode = http.StatusTooManyRequests
+			errorCode = "MFA_LOCKED"
+			errorMessage = "Too many invalid MFA codes, please try again later"
+		} else if strings.Contains(err.Error(), "MFA token") {
+			errorCode = "INVALID_MFA_TOKEN"
+			errorMessage = "MFA session is invalid or has expired, please log in again"
+		} else if strings.Contains(err.Error(), "generate") {
+			statusCode = http.StatusInternalServerError
+			errorCode = "TOKEN_GENERATION_ERROR"
+			errorMessage = "Authentication successful but failed to generate tokens"
+		}
+
+		c.JSON(statusCode, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      errorCode,
+				Message:   errorMessage,
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	// Audit log
+	go func() {
+		ctx := c.Copy()
+		auditService.Record(ctx, models.ActionLogin, "user", response.User.ID, nil)
+	}()
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success:   true,
+		Data:      response,
+		RequestID: c.GetString("requestID"),
+	})
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationService *services.NotificationService
+}
+
+func NewNotificationHandler(notificationService *services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+	}
+}
+
+// GetChannels lists the organization's notification channels
+func (h *NotificationHandler) GetChannels(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	channels, err := h.notificationService.GetChannels(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"channels": channels})
+}
+
+// CreateChannel creates a new notification channel
+func (h *NotificationHandler) CreateChannel(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	var req models.CreateNotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel := &models.NotificationChannel{
+		OrganizationID: orgID,
+		Name:           req.Name,
+		Type:           req.Type,
+		Config:         req.Config,
+		Severities:     req.Severities,
+		Enabled:        true,
+	}
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+	if userID := c.GetString("userID"); userID != "" {
+		channel.CreatedBy = &userID
+	}
+
+	if err := h.notificationService.CreateChannel(c.Request.Context(), channel); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "notification channel created", "channel": channel})
+}
+
+// UpdateChannel updates a notification channel
+func (h *NotificationHandler) UpdateChannel(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	var req models.UpdateNotificationChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel, err := h.notificationService.UpdateChannel(c.Request.Context(), orgID, c.Param("id"), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification channel updated", "channel": channel})
+}
+
+// DeleteChannel deletes a notification channel
+func (h *NotificationHandler) DeleteChannel(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	if err := h.notificationService.DeleteChannel(c.Request.Context(), orgID, c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notification channel deleted"})
+}
+
+// GetDeliveries lists recent notification deliveries, optionally for a single alert
+func (h *NotificationHandler) GetDeliveries(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	deliveries, err := h.notificationService.GetDeliveries(c.Request.Context(), orgID, c.Query("alert_id"), parseIntQuery(c, "limit", 100))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+func (h *NotificationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidNotificationChannel):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotificationChannelNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
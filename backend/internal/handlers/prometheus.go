@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"cloudweave/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// prometheusContentType is the Prometheus text exposition format content type
+const prometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// PrometheusMetrics exposes server metrics in the Prometheus text exposition format
+func PrometheusMetrics(repoManager *repositories.RepositoryManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats := repoManager.Stats()
+
+		var b strings.Builder
+		writePrometheusMetric(&b, "cloudweave_db_max_open_connections", "gauge",
+			"Maximum number of open connections to the database.", float64(stats.MaxOpenConnections))
+		writePrometheusMetric(&b, "cloudweave_db_open_connections", "gauge",
+			"Number of established connections, both in use and idle.", float64(stats.OpenConnections))
+		writePrometheusMetric(&b, "cloudweave_db_in_use_connections", "gauge",
+			"Number of connections currently in use.", float64(stats.InUse))
+		writePrometheusMetric(&b, "cloudweave_db_idle_connections", "gauge",
+			"Number of idle connections.", float64(stats.Idle))
+		writePrometheusMetric(&b, "cloudweave_db_wait_count_total", "counter",
+			"Total number of connections waited for.", float64(stats.WaitCount))
+		writePrometheusMetric(&b, "cloudweave_db_wait_duration_seconds_total", "counter",
+			"Total time blocked waiting for a new connection.", stats.WaitDuration.Seconds())
+		writePrometheusMetric(&b, "cloudweave_db_max_idle_closed_total", "counter",
+			"Total number of connections closed due to the idle connection limit.", float64(stats.MaxIdleClosed))
+		writePrometheusMetric(&b, "cloudweave_db_max_idle_time_closed_total", "counter",
+			"Total number of connections closed due to the maximum idle time.", float64(stats.MaxIdleTimeClosed))
+		writePrometheusMetric(&b, "cloudweave_db_max_lifetime_closed_total", "counter",
+			"Total number of connections closed due to the maximum connection lifetime.", float64(stats.MaxLifetimeClosed))
+
+		c.Data(http.StatusOK, prometheusContentType, []byte(b.String()))
+	}
+}
+
+func writePrometheusMetric(b *strings.Builder, name, metricType, help string, value float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, metricType)
+	fmt.Fprintf(b, "%s %g\n", name, value)
+}
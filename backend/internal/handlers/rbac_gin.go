@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -50,6 +51,10 @@ func (h *RBACGinHandler) CreateRole(c *gin.Context) {
 	role.OrganizationID = orgID.(string)
 
 	if err := h.rbacService.CreateRole(c.Request.Context(), &role, userID.(string)); err != nil {
+		if errors.Is(err, services.ErrInvalidParentRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -87,6 +92,12 @@ func (h *RBACGinHandler) ListRoles(c *gin.Context) {
 	// Parse query parameters
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
 
 	roles, total, err := h.rbacService.ListRoles(c.Request.Context(), orgID.(string), limit, offset)
 	if err != nil {
@@ -95,10 +106,11 @@ func (h *RBACGinHandler) ListRoles(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"roles":  roles,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+		"roles":   roles,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+		"hasMore": offset+len(roles) < total,
 	})
 }
 
@@ -128,6 +140,10 @@ func (h *RBACGinHandler) UpdateRole(c *gin.Context) {
 	}
 
 	if err := h.rbacService.UpdateRole(c.Request.Context(), &role, userID.(string)); err != nil {
+		if errors.Is(err, services.ErrInvalidParentRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -230,13 +246,18 @@ func (h *RBACGinHandler) GetUserRoles(c *gin.Context) {
 		return
 	}
 
-	userRoles, err := h.rbacService.GetUserRoles(c.Request.Context(), userIDParam, orgID.(string))
+	includeExpired := c.Query("includeExpired") == "true"
+
+	userRoles, err := h.rbacService.GetUserRoles(c.Request.Context(), userIDParam, orgID.(string), includeExpired)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"userRoles": userRoles})
+	c.JSON(http.StatusOK, gin.H{
+		"userRoles": userRoles,
+		"total":     len(userRoles),
+	})
 }
 
 // GetUserPermissions handles GET /api/rbac/users/:userId/permissions
@@ -258,6 +279,97 @@ func (h *RBACGinHandler) GetUserPermissions(c *gin.Context) {
 	c.JSON(http.StatusOK, permissions)
 }
 
+// Resource Permission Endpoints
+
+// GrantResourcePermission handles POST /api/rbac/resource-permissions
+func (h *RBACGinHandler) GrantResourcePermission(c *gin.Context) {
+	var permission models.ResourcePermission
+	if err := c.ShouldBindJSON(&permission); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid JSON"})
+		return
+	}
+
+	granterID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Organization not found"})
+		return
+	}
+
+	permission.OrganizationID = orgID
+
+	if err := h.rbacService.GrantResourcePermission(c.Request.Context(), &permission, granterID.(string)); err != nil {
+		if errors.Is(err, services.ErrInvalidResourcePermission) || errors.Is(err, services.ErrNotOrganizationMember) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrResourcePermissionNotAllowed) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, permission)
+}
+
+// RevokeResourcePermission handles DELETE /api/rbac/resource-permissions/:id
+func (h *RBACGinHandler) RevokeResourcePermission(c *gin.Context) {
+	id := c.Param("id")
+
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Organization not found"})
+		return
+	}
+
+	revokerID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.rbacService.RevokeResourcePermission(c.Request.Context(), orgID, id, revokerID.(string)); err != nil {
+		if errors.Is(err, services.ErrResourcePermissionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resource permission revoked successfully"})
+}
+
+// ListResourcePermissions handles GET /api/rbac/resource-permissions, listing the grants on the
+// resource given by resourceType and resourceId, or those held by userId
+func (h *RBACGinHandler) ListResourcePermissions(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Organization not found"})
+		return
+	}
+
+	permissions, err := h.rbacService.ListResourcePermissions(c.Request.Context(), orgID,
+		c.Query("resourceType"), c.Query("resourceId"), c.Query("userId"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidResourcePermission) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resourcePermissions": permissions})
+}
+
 // Permission Checking Endpoints
 
 // CheckPermission handles POST /api/rbac/check-permission
@@ -305,6 +417,8 @@ func (h *RBACGinHandler) CreateAPIKey(c *gin.Context) {
 		Name        string     `json:"name" binding:"required"`
 		Description string     `json:"description"`
 		Permissions []string   `json:"permissions"`
+		Scopes      []string   `json:"scopes"`
+		RateLimit   int        `json:"rateLimit"`
 		ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
 	}
 
@@ -326,8 +440,16 @@ func (h *RBACGinHandler) CreateAPIKey(c *gin.Context) {
 	}
 
 	apiKey, rawKey, err := h.rbacService.CreateAPIKey(c.Request.Context(),
-		userID.(string), orgID.(string), request.Name, request.Description, request.Permissions, request.ExpiresAt)
+		userID.(string), orgID.(string), request.Name, request.Description, request.Permissions, request.Scopes, request.RateLimit, request.ExpiresAt)
 	if err != nil {
+		if errors.Is(err, services.ErrInvalidScope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "validScopes": models.APIKeyScopes})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidRateLimit) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
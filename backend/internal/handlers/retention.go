@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetentionHandler handles the organization's data retention settings
+type RetentionHandler struct {
+	retentionService *services.RetentionService
+}
+
+// NewRetentionHandler creates a new retention handler
+func NewRetentionHandler(retentionService *services.RetentionService) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+	}
+}
+
+// GetRetentionSettings retrieves how long the organization's metrics and resolved alerts are kept
+func (h *RetentionHandler) GetRetentionSettings(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	settings, err := h.retentionService.GetRetentionSettings(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get retention settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateRetentionSettings replaces how long the organization's metrics and resolved alerts are kept
+func (h *RetentionHandler) UpdateRetentionSettings(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	var req models.UpdateRetentionSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := h.retentionService.UpdateRetentionSettings(c.Request.Context(), orgID, c.GetString("userID"), &req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRetentionSettings) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update retention settings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// PreviewRetentionPurge counts the organization's records the retention purge would delete if it
+// ran now
+func (h *RetentionHandler) PreviewRetentionPurge(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	preview, err := h.retentionService.PreviewPurge(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to preview retention purge"})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
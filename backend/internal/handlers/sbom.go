@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SBOMHandler handles the software bills of materials of deployments
+type SBOMHandler struct {
+	sbomService *services.SBOMService
+}
+
+// NewSBOMHandler creates a new SBOM handler
+func NewSBOMHandler(sbomService *services.SBOMService) *SBOMHandler {
+	return &SBOMHandler{
+		sbomService: sbomService,
+	}
+}
+
+// GetDeploymentSBOM downloads the SBOM of a deployment's artifact, in CycloneDX or, with
+// ?format=spdx, SPDX JSON
+func (h *SBOMHandler) GetDeploymentSBOM(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Deployment ID is required"})
+		return
+	}
+
+	format := models.SBOMFormat(c.DefaultQuery("format", string(models.SBOMFormatCycloneDX)))
+	if !format.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be cyclonedx or spdx"})
+		return
+	}
+
+	sbom, err := h.sbomService.GetDeploymentSBOM(c.Request.Context(), c.GetString("organizationId"), id, format)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDeploymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Deployment not found"})
+		case errors.Is(err, services.ErrNoDeploymentArtifact), errors.Is(err, services.ErrScanUnsupported):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate SBOM"})
+		}
+		return
+	}
+
+	filename := fmt.Sprintf("deployment-%s%s", sbom.DeploymentID, format.FileExtension())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, format.ContentType(), sbom.Document)
+}
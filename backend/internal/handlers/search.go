@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cloudweave/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService *services.SearchService
+}
+
+func NewSearchHandler(searchService *services.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+// Search searches infrastructure, deployments and alerts in the caller's organization
+// (?q=, optional ?types=infrastructure,deployment,alert and ?limit= per type)
+func (h *SearchHandler) Search(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+	userID := c.GetString("userID")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	var types []string
+	if raw := c.Query("types"); raw != "" {
+		types = strings.Split(raw, ",")
+	}
+
+	limit := services.DefaultSearchLimit
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	response, err := h.searchService.Search(c.Request.Context(), orgID, userID, query, types, limit)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidSearchQuery) || errors.Is(err, services.ErrInvalidSearchType) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search resources"})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
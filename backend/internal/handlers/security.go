@@ -1,12 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
 	"cloudweave/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -22,7 +25,7 @@ func InitializeSecurityService(service *services.SecurityService) {
 // CreateSecurityScan creates a new security scan
 func CreateSecurityScan(c *gin.Context) {
 	userID := c.GetString("userID")
-	organizationID := c.GetString("organizationID")
+	organizationID := c.GetString("organizationId")
 
 	var req models.CreateScanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -67,7 +70,7 @@ func CreateSecurityScan(c *gin.Context) {
 
 // GetSecurityScan retrieves a security scan by ID
 func GetSecurityScan(c *gin.Context) {
-	organizationID := c.GetString("organizationID")
+	organizationID := c.GetString("organizationId")
 	scanID := c.Param("id")
 
 	log.Printf("Getting security scan: %s for organization: %s", scanID, organizationID)
@@ -96,7 +99,7 @@ func GetSecurityScan(c *gin.Context) {
 
 // ListSecurityScans retrieves security scans for an organization
 func ListSecurityScans(c *gin.Context) {
-	organizationID := c.GetString("organizationID")
+	organizationID := c.GetString("organizationId")
 
 	// Parse pagination parameters
 	limit := 50
@@ -145,7 +148,7 @@ func ListSecurityScans(c *gin.Context) {
 
 // GetVulnerabilities retrieves vulnerabilities based on query parameters
 func GetVulnerabilities(c *gin.Context) {
-	organizationID := c.GetString("organizationID")
+	organizationID := c.GetString("organizationId")
 
 	// Parse query parameters
 	query := models.VulnerabilityQuery{
@@ -174,9 +177,24 @@ func GetVulnerabilities(c *gin.Context) {
 		query.Severity = &severityEnum
 	}
 
+	// status takes a comma-separated list of lifecycle states, e.g. open,acknowledged
 	if status := c.Query("status"); status != "" {
-		statusEnum := models.VulnerabilityStatus(status)
-		query.Status = &statusEnum
+		for _, value := range strings.Split(status, ",") {
+			statusEnum := models.VulnerabilityStatus(strings.TrimSpace(value))
+			if !statusEnum.IsValid() {
+				c.JSON(http.StatusBadRequest, models.ApiResponse{
+					Success: false,
+					Error: &models.ApiError{
+						Code:      "VALIDATION_ERROR",
+						Message:   "Invalid status: " + value,
+						Timestamp: time.Now(),
+					},
+					RequestID: c.GetString("requestID"),
+				})
+				return
+			}
+			query.Statuses = append(query.Statuses, statusEnum)
+		}
 	}
 
 	if resourceType := c.Query("resourceType"); resourceType != "" {
@@ -232,9 +250,100 @@ func GetVulnerabilities(c *gin.Context) {
 	})
 }
 
+// GetScanFindings retrieves a page of the vulnerabilities a security scan found, optionally
+// filtered by severity and status, with the number of findings of each severity
+func GetScanFindings(c *gin.Context) {
+	organizationID := c.GetString("organizationId")
+	scanID := c.Param("id")
+
+	query := models.VulnerabilityQuery{
+		Limit:  50,
+		Offset: 0,
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 1000 {
+			query.Limit = parsedLimit
+		}
+	}
+
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			query.Offset = parsedOffset
+		}
+	}
+
+	if severity := c.Query("severity"); severity != "" {
+		severityEnum := models.VulnerabilitySeverity(severity)
+		if !severityEnum.IsValid() {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Success: false,
+				Error: &models.ApiError{
+					Code:      "VALIDATION_ERROR",
+					Message:   "Invalid severity: " + severity,
+					Timestamp: time.Now(),
+				},
+				RequestID: c.GetString("requestID"),
+			})
+			return
+		}
+		query.Severity = &severityEnum
+	}
+
+	if status := c.Query("status"); status != "" {
+		statusEnum := models.VulnerabilityStatus(status)
+		if !statusEnum.IsValid() {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Success: false,
+				Error: &models.ApiError{
+					Code:      "VALIDATION_ERROR",
+					Message:   "Invalid status: " + status,
+					Timestamp: time.Now(),
+				},
+				RequestID: c.GetString("requestID"),
+			})
+			return
+		}
+		query.Status = &statusEnum
+	}
+
+	findings, err := securityService.GetScanFindings(c.Request.Context(), organizationID, scanID, query)
+	if err != nil {
+		log.Printf("Failed to get scan findings: %v", err)
+		if errors.Is(err, repositories.ErrSecurityScanNotFound) {
+			c.JSON(http.StatusNotFound, models.ApiResponse{
+				Success: false,
+				Error: &models.ApiError{
+					Code:      "SCAN_NOT_FOUND",
+					Message:   "Security scan not found",
+					Timestamp: time.Now(),
+				},
+				RequestID: c.GetString("requestID"),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "SCAN_FINDINGS_FAILED",
+				Message:   "Failed to retrieve scan findings",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success:   true,
+		Data:      findings,
+		RequestID: c.GetString("requestID"),
+	})
+}
+
 // GetVulnerability retrieves a specific vulnerability
 func GetVulnerability(c *gin.Context) {
-	organizationID := c.GetString("organizationID")
+	organizationID := c.GetString("organizationId")
 	vulnerabilityID := c.Param("id")
 
 	log.Printf("Getting vulnerability: %s for organization: %s", vulnerabilityID, organizationID)
@@ -264,7 +373,7 @@ func GetVulnerability(c *gin.Context) {
 // UpdateVulnerability updates a vulnerability
 func UpdateVulnerability(c *gin.Context) {
 	userID := c.GetString("userID")
-	organizationID := c.GetString("organizationID")
+	organizationID := c.GetString("organizationId")
 	vulnerabilityID := c.Param("id")
 
 	var req models.UpdateVulnerabilityRequest
@@ -287,6 +396,18 @@ func UpdateVulnerability(c *gin.Context) {
 	vulnerability, err := securityService.UpdateVulnerability(c.Request.Context(), userID, organizationID, vulnerabilityID, req)
 	if err != nil {
 		log.Printf("Failed to update vulnerability: %v", err)
+		if errors.Is(err, services.ErrInvalidVulnerabilityTransition) {
+			c.JSON(http.StatusBadRequest, models.ApiResponse{
+				Success: false,
+				Error: &models.ApiError{
+					Code:      "INVALID_STATUS_TRANSITION",
+					Message:   err.Error(),
+					Timestamp: time.Now(),
+				},
+				RequestID: c.GetString("requestID"),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ApiResponse{
 			Success: false,
 			Error: &models.ApiError{
@@ -308,9 +429,36 @@ func UpdateVulnerability(c *gin.Context) {
 	})
 }
 
+// GetVulnerabilityHistory retrieves the status changes of a vulnerability, newest first
+func GetVulnerabilityHistory(c *gin.Context) {
+	organizationID := c.GetString("organizationId")
+	vulnerabilityID := c.Param("id")
+
+	history, err := securityService.GetVulnerabilityHistory(c.Request.Context(), organizationID, vulnerabilityID)
+	if err != nil {
+		log.Printf("Failed to get vulnerability history: %v", err)
+		c.JSON(http.StatusNotFound, models.ApiResponse{
+			Success: false,
+			Error: &models.ApiError{
+				Code:      "VULNERABILITY_NOT_FOUND",
+				Message:   "Vulnerability not found",
+				Timestamp: time.Now(),
+			},
+			RequestID: c.GetString("requestID"),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ApiResponse{
+		Success:   true,
+		Data:      history,
+		RequestID: c.GetString("requestID"),
+	})
+}
+
 // GetSecurityMetrics retrieves security metrics for an organization
 func GetSecurityMetrics(c *gin.Context) {
-	organizationID := c.GetString("organizationID")
+	organizationID := c.GetString("organizationId")
 
 	log.Printf("Getting security metrics for organization: %s", organizationID)
 
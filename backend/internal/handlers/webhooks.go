@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// GetWebhooks lists the organization's webhooks
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	webhooks, err := h.webhookService.GetWebhooks(c.Request.Context(), orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"webhooks": webhooks, "events": models.WebhookEvents})
+}
+
+// CreateWebhook registers a new webhook. The signing secret is only returned in this response.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	var req models.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook := &models.Webhook{
+		OrganizationID: orgID,
+		URL:            req.URL,
+		Secret:         req.Secret,
+		Events:         req.Events,
+		Enabled:        true,
+	}
+	if req.Enabled != nil {
+		webhook.Enabled = *req.Enabled
+	}
+	if userID := c.GetString("userID"); userID != "" {
+		webhook.CreatedBy = &userID
+	}
+
+	if err := h.webhookService.CreateWebhook(c.Request.Context(), webhook); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "webhook created", "webhook": webhook, "secret": webhook.Secret})
+}
+
+// DeleteWebhook deletes a webhook
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), orgID, c.Param("id")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "webhook deleted"})
+}
+
+// TestWebhook posts a test event to a webhook and reports the endpoint's response
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	result, err := h.webhookService.TestWebhook(c.Request.Context(), orgID, c.Param("id"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GetDeliveries lists a webhook's recent deliveries, optionally filtered by status such as dead_letter
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	orgID := c.GetString("organizationId")
+	if orgID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "organization_id is required"})
+		return
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(c.Request.Context(), orgID, c.Param("id"), c.Query("status"), parseIntQuery(c, "limit", 100))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+func (h *WebhookHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
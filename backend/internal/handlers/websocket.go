@@ -39,7 +39,7 @@ func (h *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 	}
 
 	// Handle WebSocket upgrade
-	h.wsService.HandleWebSocket(c.Writer, c.Request, userIDStr)
+	h.wsService.HandleWebSocket(c.Writer, c.Request, userIDStr, c.GetString("organizationId"))
 }
 
 // GetWebSocketStatus returns the status of WebSocket service
@@ -49,6 +49,7 @@ func (h *WebSocketHandler) GetWebSocketStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status":           "running",
 		"connectedClients": clientCount,
+		"droppedClients":   h.wsService.GetDroppedClientsCount(),
 		"message":          "WebSocket service is active",
 	})
 }
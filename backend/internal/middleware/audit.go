@@ -12,6 +12,14 @@ import (
 // AuditLogMiddleware creates a middleware for logging audit trails.
 func AuditLog(auditService *services.AuditService) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// Make the request origin available to services that write audit logs
+		auditCtx := &services.AuditContext{
+			IPAddress:      c.ClientIP(),
+			UserAgent:      c.Request.UserAgent(),
+			ImpersonatedBy: c.GetString("impersonatedBy"),
+		}
+		c.Request = c.Request.WithContext(services.WithAuditContext(c.Request.Context(), auditCtx))
+
 		// Read the request body
 		var requestBody []byte
 		if c.Request.Body != nil {
@@ -30,8 +38,8 @@ func AuditLog(auditService *services.AuditService) gin.HandlerFunc {
 		details := gin.H{
 			"method":      c.Request.Method,
 			"path":        c.Request.URL.Path,
-			"ip_address":  c.ClientIP(),
-			"user_agent":  c.Request.UserAgent(),
+			"ip_address":  auditCtx.IPAddress,
+			"user_agent":  auditCtx.UserAgent,
 			"status_code": c.Writer.Status(),
 		}
 
@@ -40,10 +48,10 @@ func AuditLog(auditService *services.AuditService) gin.HandlerFunc {
 			details["request_body"] = string(requestBody)
 		}
 
-		// Asynchronously log the audit record
+		// Asynchronously log the audit record. The copy must be taken before the handler returns,
+		// since gin reuses the context for the next request.
+		ctx := c.Copy()
 		go func() {
-			// Create a new context for the goroutine
-			ctx := c.Copy()
 			auditService.Record(ctx, "api_request", "", "", details)
 		}()
 	}
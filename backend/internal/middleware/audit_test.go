@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+	"cloudweave/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordingAuditRepo keeps the audit logs written to it
+type recordingAuditRepo struct {
+	repositories.AuditLogRepositoryInterface
+
+	mu   sync.Mutex
+	logs []*models.AuditLog
+}
+
+func (r *recordingAuditRepo) CreateBatch(ctx context.Context, logs []*models.AuditLog) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, logs...)
+	return nil
+}
+
+// waitForAuditLogs flushes the writer until an entry has been written, since the middleware
+// records asynchronously
+func waitForAuditLogs(t *testing.T, writer *services.AuditWriter, repo *recordingAuditRepo) []*models.AuditLog {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if err := writer.Flush(context.Background()); err != nil {
+			t.Fatalf("failed to flush audit logs: %v", err)
+		}
+
+		repo.mu.Lock()
+		logs := append([]*models.AuditLog{}, repo.logs...)
+		repo.mu.Unlock()
+		if len(logs) > 0 {
+			return logs
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("no audit log was written")
+	return nil
+}
+
+func TestAuditLogRecordsRequestOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &recordingAuditRepo{}
+	writer := services.NewAuditWriter(repo, 10)
+	auditService := services.NewAuditService(repo, writer)
+
+	var auditCtx *services.AuditContext
+	router := gin.New()
+	router.Use(AuditLog(auditService))
+	router.GET("/ping", func(c *gin.Context) {
+		auditCtx = services.AuditContextFromContext(c)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("User-Agent", "audit-test/1.0")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	if auditCtx == nil {
+		t.Fatal("handler saw no audit context")
+	}
+	if auditCtx.IPAddress != "203.0.113.7" || auditCtx.UserAgent != "audit-test/1.0" {
+		t.Errorf("audit context = %+v, want the request's IP and user agent", auditCtx)
+	}
+
+	logs := waitForAuditLogs(t, writer, repo)
+	entry := logs[0]
+	if entry.IPAddress == nil {
+		t.Fatal("audit log IP address is nil")
+	}
+	if got := entry.IPAddress.String(); got != "203.0.113.7" {
+		t.Errorf("audit log IP address = %s, want 203.0.113.7", got)
+	}
+	if entry.UserAgent == nil {
+		t.Fatal("audit log user agent is nil")
+	}
+	if *entry.UserAgent != "audit-test/1.0" {
+		t.Errorf("audit log user agent = %s, want audit-test/1.0", *entry.UserAgent)
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// CORS applies the CORS policy for the frontend. Its allowed origins can be replaced while the
+// server runs.
+type CORS struct {
+	handler atomic.Pointer[gin.HandlerFunc]
+}
+
+// NewCORS creates a CORS policy allowing credentialed requests from origins, which may use
+// wildcard subdomains such as https://*.example.com
+func NewCORS(origins []string) (*CORS, error) {
+	c := &CORS{}
+	if err := c.SetOrigins(origins); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// SetOrigins replaces the allowed origins. Requests already being handled finish under the
+// previous policy.
+func (c *CORS) SetOrigins(origins []string) error {
+	config := cors.DefaultConfig()
+	config.AllowOrigins = origins
+	config.AllowWildcard = true
+	config.AllowCredentials = true
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", "X-Request-ID", "X-API-Key", "Idempotency-Key"}
+	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	handler := cors.New(config)
+	c.handler.Store(&handler)
+	return nil
+}
+
+// Handler returns the middleware applying the current policy
+func (c *CORS) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		(*c.handler.Load())(ctx)
+	}
+}
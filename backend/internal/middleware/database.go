@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"cloudweave/internal/database"
+	"cloudweave/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireDatabase middleware rejects requests with 503 while the database is unreachable, so
+// routes that depend on it fail fast instead of timing out
+func RequireDatabase(db *database.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !db.Available() {
+			c.Header("Retry-After", "30")
+			c.JSON(http.StatusServiceUnavailable, models.ApiResponse{
+				Success: false,
+				Error: &models.ApiError{
+					Code:      "SERVICE_UNAVAILABLE",
+					Message:   "The database is currently unavailable. Please try again later.",
+					Timestamp: time.Now(),
+				},
+				RequestID: c.GetString("requestID"),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
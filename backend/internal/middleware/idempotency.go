@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader is the header clients send to make a request safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// Idempotency middleware replays the stored response when a request is repeated with the same
+// Idempotency-Key header within the key's TTL, instead of executing it again. Keys are scoped to
+// the caller's organization, so it must run after authentication.
+func Idempotency(idempotencyService *services.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		orgID := c.GetString("organizationId")
+		if key == "" || orgID == "" {
+			c.Next()
+			return
+		}
+
+		var body []byte
+		if c.Request.Body != nil {
+			var err error
+			body, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				idempotencyError(c, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body")
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+		}
+		// The query is part of the fingerprint, so a dry run is never replayed as the real request
+		requestHash := services.IdempotencyRequestHash(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+		stored, err := idempotencyService.Begin(c.Request.Context(), orgID, key, requestHash)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidIdempotencyKey):
+				idempotencyError(c, http.StatusBadRequest, "INVALID_IDEMPOTENCY_KEY", err.Error())
+			case errors.Is(err, services.ErrIdempotencyKeyReused):
+				idempotencyError(c, http.StatusConflict, "IDEMPOTENCY_KEY_REUSED", err.Error())
+			case errors.Is(err, services.ErrIdempotencyKeyInProgress):
+				idempotencyError(c, http.StatusConflict, "IDEMPOTENCY_KEY_IN_PROGRESS", err.Error())
+			default:
+				idempotencyError(c, http.StatusInternalServerError, "INTERNAL_ERROR", "Failed to check idempotency key")
+			}
+			return
+		}
+
+		if stored != nil {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(stored.StatusCode, stored.ContentType, stored.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		writer := &responseWriter{
+			ResponseWriter: c.Writer,
+			body:           bytes.NewBuffer([]byte{}),
+		}
+		c.Writer = writer
+
+		c.Next()
+
+		// Store the outcome even if the client went away, that is when it is most likely to retry
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		// Server errors are not replayed so that a retry gets another chance to succeed
+		if writer.Status() >= http.StatusInternalServerError {
+			if err := idempotencyService.Release(ctx, orgID, key); err != nil {
+				log.Printf("Failed to release idempotency key: %v", err)
+			}
+			return
+		}
+
+		if err := idempotencyService.Complete(ctx, orgID, key, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			log.Printf("Failed to store idempotent response: %v", err)
+		}
+	}
+}
+
+func idempotencyError(c *gin.Context, status int, code, message string) {
+	c.JSON(status, models.ApiResponse{
+		Success: false,
+		Error: &models.ApiError{
+			Code:      code,
+			Message:   message,
+			Timestamp: time.Now(),
+		},
+		RequestID: c.GetString("requestID"),
+	})
+	c.Abort()
+}
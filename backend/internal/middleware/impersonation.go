@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RejectImpersonation middleware rejects requests made with an impersonation token, guarding
+// actions that would outlive the impersonation or act on the impersonator's behalf, such as
+// issuing credentials or granting permissions. It must run after AuthRequired.
+func RejectImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("impersonatedBy") != "" {
+			c.JSON(http.StatusForbidden, models.ApiResponse{
+				Success: false,
+				Error: &models.ApiError{
+					Code:      "IMPERSONATION_NOT_ALLOWED",
+					Message:   "This action cannot be performed while impersonating a user",
+					Timestamp: time.Now(),
+				},
+				RequestID: c.GetString("requestID"),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -293,6 +293,9 @@ func AuthRequired(jwtService *services.JWTService) gin.HandlerFunc {
 		c.Set("organizationId", claims.OrganizationID)
 		c.Set("tokenID", claims.TokenID)
 		c.Set("token", tokenString)
+		if claims.ImpersonatedBy != "" {
+			c.Set("impersonatedBy", claims.ImpersonatedBy)
+		}
 
 		c.Next()
 	}
@@ -362,6 +365,9 @@ func WebSocketAuthRequired(jwtService *services.JWTService) gin.HandlerFunc {
 		c.Set("organizationId", claims.OrganizationID)
 		c.Set("tokenID", claims.TokenID)
 		c.Set("token", tokenString)
+		if claims.ImpersonatedBy != "" {
+			c.Set("impersonatedBy", claims.ImpersonatedBy)
+		}
 
 		c.Next()
 	}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission middleware rejects requests whose caller lacks an RBAC permission. Users are
+// checked against the roles they hold in the organization; API keys must be granted the
+// permission and their owner must still hold it. It must run after AuthOrAPIKey.
+func RequirePermission(rbacService *services.RBACService, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed := rbacService.HasPermission(c.Request.Context(), c.GetString("userID"), c.GetString("organizationId"), permission)
+		if c.GetString("authMethod") == "api_key" {
+			allowed = allowed && services.APIKeyHasPermission(c.GetStringSlice("apiKeyPermissions"), permission)
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, models.ApiResponse{
+				Success: false,
+				Error: &models.ApiError{
+					Code:      "INSUFFICIENT_PERMISSIONS",
+					Message:   "You do not have the required permission",
+					Details:   permission,
+					Timestamp: time.Now(),
+				},
+				RequestID: c.GetString("requestID"),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
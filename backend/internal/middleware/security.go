@@ -1,8 +1,9 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
-	"strings"
+	"strconv"
 	"sync"
 	"time"
 
@@ -27,7 +28,16 @@ type TokenBucket struct {
 	refillRate time.Duration
 }
 
-// NewRateLimiter creates a new rate limiter
+// RateLimitResult describes the outcome of taking a token from a bucket
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// NewRateLimiter creates a new rate limiter allowing limit requests per window.
+// A limit of zero or less disables limiting.
 func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	return &RateLimiter{
 		buckets: make(map[string]*TokenBucket),
@@ -38,38 +48,78 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 
 // Allow checks if a request should be allowed using token bucket algorithm
 func (rl *RateLimiter) Allow(key string) bool {
+	return rl.Take(key).Allowed
+}
+
+// Take takes a token from the key's bucket
+func (rl *RateLimiter) Take(key string) RateLimitResult {
+	rl.mutex.RLock()
+	limit := rl.limit
+	rl.mutex.RUnlock()
+
+	return rl.TakeWithLimit(key, limit)
+}
+
+// SetLimit changes the limiter to allow limit requests per window. Existing buckets are resized
+// as they are next used, without handing out extra tokens.
+func (rl *RateLimiter) SetLimit(limit int, window time.Duration) {
 	rl.mutex.Lock()
 	defer rl.mutex.Unlock()
-	
+
+	rl.limit = limit
+	rl.window = window
+}
+
+// TakeWithLimit takes a token from the key's bucket, sizing the bucket for limit requests per window
+func (rl *RateLimiter) TakeWithLimit(key string, limit int) RateLimitResult {
+	if limit <= 0 {
+		return RateLimitResult{Allowed: true}
+	}
+
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
 	now := time.Now()
+	refillRate := rl.window / time.Duration(limit)
 	bucket, exists := rl.buckets[key]
-	
+
 	if !exists {
 		bucket = &TokenBucket{
-			tokens:     rl.limit,
+			tokens:     limit,
 			lastRefill: now,
-			maxTokens:  rl.limit,
-			refillRate: rl.window / time.Duration(rl.limit),
+			maxTokens:  limit,
+			refillRate: refillRate,
 		}
 		rl.buckets[key] = bucket
+	} else if bucket.maxTokens != limit || bucket.refillRate != refillRate {
+		// The key's limit or window changed, resize the bucket without handing out extra tokens
+		bucket.tokens = min(bucket.tokens, limit)
+		bucket.maxTokens = limit
+		bucket.refillRate = refillRate
 	}
-	
-	// Refill tokens based on time elapsed
-	elapsed := now.Sub(bucket.lastRefill)
-	tokensToAdd := int(elapsed / bucket.refillRate)
-	
+
+	// Refill tokens based on time elapsed, carrying over partially elapsed intervals
+	tokensToAdd := int(now.Sub(bucket.lastRefill) / bucket.refillRate)
 	if tokensToAdd > 0 {
 		bucket.tokens = min(bucket.maxTokens, bucket.tokens+tokensToAdd)
-		bucket.lastRefill = now
+		bucket.lastRefill = bucket.lastRefill.Add(time.Duration(tokensToAdd) * bucket.refillRate)
+		if bucket.tokens == bucket.maxTokens {
+			bucket.lastRefill = now
+		}
 	}
-	
+
+	result := RateLimitResult{Limit: limit}
+
 	// Check if we have tokens available
 	if bucket.tokens <= 0 {
-		return false
+		result.RetryAfter = bucket.refillRate - now.Sub(bucket.lastRefill)
+		return result
 	}
-	
+
 	bucket.tokens--
-	return true
+	result.Allowed = true
+	result.Remaining = bucket.tokens
+	return result
 }
 
 // min returns the minimum of two integers
@@ -80,81 +130,68 @@ func min(a, b int) int {
 	return b
 }
 
-// AdaptiveRateLimiter provides different rate limits for different endpoint types
-type AdaptiveRateLimiter struct {
-	readLimiter  *RateLimiter
-	writeLimiter *RateLimiter
-	authLimiter  *RateLimiter
+// apiKeyRateLimitWindow is the window an API key's own rate limit applies to
+const apiKeyRateLimitWindow = time.Minute
+
+// RateLimitMiddleware limits each caller to limit requests per window
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	return LimiterMiddleware(NewRateLimiter(limit, window))
 }
 
-// NewAdaptiveRateLimiter creates a new adaptive rate limiter
-func NewAdaptiveRateLimiter() *AdaptiveRateLimiter {
-	return &AdaptiveRateLimiter{
-		readLimiter:  NewRateLimiter(200, time.Minute),  // 200 reads per minute
-		writeLimiter: NewRateLimiter(50, time.Minute),   // 50 writes per minute
-		authLimiter:  NewRateLimiter(20, time.Minute),   // 20 auth requests per minute
-	}
+// LimiterMiddleware limits each caller with limiter, whose limit can be changed while the
+// server runs
+func LimiterMiddleware(limiter *RateLimiter) gin.HandlerFunc {
+	return rateLimitHandler(func(c *gin.Context) *RateLimiter {
+		return limiter
+	})
 }
 
-// Allow checks if a request should be allowed based on endpoint type
-func (arl *AdaptiveRateLimiter) Allow(key, method, path string) bool {
-	// Determine endpoint type
-	if strings.Contains(path, "/auth/") || strings.Contains(path, "/login") || strings.Contains(path, "/register") {
-		return arl.authLimiter.Allow(key)
-	}
-	
-	if method == "GET" || method == "HEAD" || method == "OPTIONS" {
-		return arl.readLimiter.Allow(key)
-	}
-	
-	return arl.writeLimiter.Allow(key)
+// ReadWriteRateLimitMiddleware limits each caller's reads and writes separately, so
+// read-heavy clients such as dashboards are not throttled by the stricter write limit
+func ReadWriteRateLimitMiddleware(readLimit, writeLimit int, window time.Duration) gin.HandlerFunc {
+	return ReadWriteLimiterMiddleware(NewRateLimiter(readLimit, window), NewRateLimiter(writeLimit, window))
 }
 
-// RateLimitMiddleware provides basic rate limiting functionality
-func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
-	limiter := NewRateLimiter(limit, window)
-	
-	return func(c *gin.Context) {
-		// Use IP address as key, but could be enhanced to use user ID for authenticated requests
-		key := c.ClientIP()
-		if userID := c.GetString("userID"); userID != "" {
-			key = "user:" + userID
+// ReadWriteLimiterMiddleware limits each caller's reads with readLimiter and writes with
+// writeLimiter
+func ReadWriteLimiterMiddleware(readLimiter, writeLimiter *RateLimiter) gin.HandlerFunc {
+	return rateLimitHandler(func(c *gin.Context) *RateLimiter {
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			return readLimiter
+		default:
+			return writeLimiter
 		}
-		
-		if !limiter.Allow(key) {
-			c.JSON(http.StatusTooManyRequests, models.ApiResponse{
-				Success: false,
-				Error: &models.ApiError{
-					Code:      "RATE_LIMIT_EXCEEDED",
-					Message:   "Too many requests. Please try again later.",
-					Timestamp: time.Now(),
-				},
-				RequestID: c.GetString("requestID"),
-			})
-			c.Abort()
-			return
-		}
-		
-		c.Next()
-	}
+	})
 }
 
-// AdaptiveRateLimitMiddleware provides adaptive rate limiting based on endpoint type
-func AdaptiveRateLimitMiddleware() gin.HandlerFunc {
-	limiter := NewAdaptiveRateLimiter()
-	
+// rateLimitHandler enforces the limiter chosen for each request. API keys created with their
+// own rate limit are held to that limit instead of the route group's.
+func rateLimitHandler(limiterFor func(c *gin.Context) *RateLimiter) gin.HandlerFunc {
+	apiKeyLimiter := NewRateLimiter(0, apiKeyRateLimitWindow)
+
 	return func(c *gin.Context) {
-		// Use IP address as key, but prefer user ID for authenticated requests
-		key := c.ClientIP()
-		if userID := c.GetString("userID"); userID != "" {
-			key = "user:" + userID
+		key := rateLimitKey(c)
+
+		var result RateLimitResult
+		if keyLimit := c.GetInt("apiKeyRateLimit"); keyLimit > 0 {
+			result = apiKeyLimiter.TakeWithLimit(key, keyLimit)
+		} else {
+			result = limiterFor(c).Take(key)
 		}
-		
-		if !limiter.Allow(key, c.Request.Method, c.Request.URL.Path) {
-			c.Header("X-RateLimit-Limit", "varies")
-			c.Header("X-RateLimit-Remaining", "0")
-			c.Header("Retry-After", "60")
-			
+
+		if result.Limit > 0 {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		}
+
+		if !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+
 			c.JSON(http.StatusTooManyRequests, models.ApiResponse{
 				Success: false,
 				Error: &models.ApiError{
@@ -167,11 +204,22 @@ func AdaptiveRateLimitMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
-		
+
 		c.Next()
 	}
 }
 
+// rateLimitKey identifies who a request counts against: the API key, then the user, then the client IP
+func rateLimitKey(c *gin.Context) string {
+	if apiKeyID := c.GetString("apiKeyID"); apiKeyID != "" {
+		return "apikey:" + apiKeyID
+	}
+	if userID := c.GetString("userID"); userID != "" {
+		return "user:" + userID
+	}
+	return "ip:" + c.ClientIP()
+}
+
 // SecurityHeaders adds security headers to responses
 func SecurityHeaders() gin.HandlerFunc {
 	return func(c *gin.Context) {
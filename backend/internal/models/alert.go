@@ -26,6 +26,7 @@ const (
 	AlertTypeCompliance  = "compliance"
 	AlertTypeSystem      = "system"
 	AlertTypeCustom      = "custom"
+	AlertTypeDrift       = "drift"
 )
 
 // Alert severity levels
@@ -36,6 +37,50 @@ const (
 	AlertSeverityCritical = "critical"
 )
 
+// AlertEvent records a state change of an alert
+type AlertEvent struct {
+	ID             string    `json:"id" db:"id"`
+	AlertID        string    `json:"alertId" db:"alert_id"`
+	OrganizationID string    `json:"organizationId" db:"organization_id"`
+	EventType      string    `json:"eventType" db:"event_type"`
+	ActorID        *string   `json:"actorId" db:"actor_id"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Alert event types
+const (
+	AlertEventCreated      = "created"
+	AlertEventAcknowledged = "acknowledged"
+	AlertEventResolved     = "resolved"
+	AlertEventReopened     = "reopened"
+)
+
+// AlertDetail is an alert together with its state-change history
+type AlertDetail struct {
+	Alert
+	History []*AlertEvent `json:"history"`
+}
+
+// BulkAlertRequest selects the alerts of a bulk acknowledge or resolve, either by ID or by filter
+type BulkAlertRequest struct {
+	AlertIDs []string    `json:"alertIds" binding:"omitempty,max=500"`
+	Filter   *AlertQuery `json:"filter"`
+}
+
+// Bulk alert result statuses
+const (
+	BulkAlertAcknowledged = "acknowledged"
+	BulkAlertResolved     = "resolved"
+	BulkAlertUnchanged    = "unchanged"
+	BulkAlertNotFound     = "not_found"
+)
+
+// BulkAlertResult is the outcome of a bulk acknowledge or resolve for one alert
+type BulkAlertResult struct {
+	AlertID string `json:"alertId"`
+	Status  string `json:"status"`
+}
+
 // AlertQuery represents query parameters for alerts
 type AlertQuery struct {
 	Type         *string    `json:"type"`
@@ -47,4 +92,51 @@ type AlertQuery struct {
 	EndTime      *time.Time `json:"endTime"`
 	Limit        int        `json:"limit"`
 	Offset       int        `json:"offset"`
-}
\ No newline at end of file
+}
+// AlertRule fires an alert when a metric crosses a threshold for a sustained duration
+type AlertRule struct {
+	ID              string    `json:"id" db:"id"`
+	OrganizationID  string    `json:"organizationId" db:"organization_id"`
+	Name            string    `json:"name" db:"name"`
+	Description     string    `json:"description" db:"description"`
+	ResourceType    string    `json:"resourceType" db:"resource_type"`
+	MetricName      string    `json:"metricName" db:"metric_name"`
+	Operator        string    `json:"operator" db:"operator"`
+	Threshold       float64   `json:"threshold" db:"threshold"`
+	DurationSeconds int       `json:"durationSeconds" db:"duration_seconds"`
+	Severity        string    `json:"severity" db:"severity"`
+	Enabled         bool      `json:"enabled" db:"enabled"`
+	CreatedBy       *string   `json:"createdBy" db:"created_by"`
+	CreatedAt       time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt       time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Alert rule comparison operators
+const (
+	AlertOperatorGreaterThan        = "gt"
+	AlertOperatorGreaterThanOrEqual = "gte"
+	AlertOperatorLessThan           = "lt"
+	AlertOperatorLessThanOrEqual    = "lte"
+	AlertOperatorEqual              = "eq"
+)
+
+// AlertRuleState tracks a rule that is currently breached for a resource
+type AlertRuleState struct {
+	RuleID        string    `json:"ruleId" db:"rule_id"`
+	ResourceID    string    `json:"resourceId" db:"resource_id"`
+	BreachedSince time.Time `json:"breachedSince" db:"breached_since"`
+	AlertID       *string   `json:"alertId" db:"alert_id"`
+}
+
+// CreateAlertRuleRequest represents a request to create an alert rule
+type CreateAlertRuleRequest struct {
+	Name            string  `json:"name" binding:"required,min=1,max=255"`
+	Description     string  `json:"description,omitempty"`
+	ResourceType    string  `json:"resourceType,omitempty" binding:"max=50"`
+	MetricName      string  `json:"metricName" binding:"required,min=1,max=100"`
+	Operator        string  `json:"operator" binding:"required,oneof=gt gte lt lte eq"`
+	Threshold       float64 `json:"threshold"`
+	DurationSeconds int     `json:"durationSeconds" binding:"min=0"`
+	Severity        string  `json:"severity,omitempty" binding:"omitempty,oneof=info warning error critical"`
+	Enabled         *bool   `json:"enabled,omitempty"`
+}
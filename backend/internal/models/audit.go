@@ -15,6 +15,7 @@ type AuditLog struct {
 	Details        map[string]interface{} `json:"details" db:"details"`
 	IPAddress      *net.IP                `json:"ipAddress" db:"ip_address"`
 	UserAgent      *string                `json:"userAgent" db:"user_agent"`
+	ImpersonatedBy *string                `json:"impersonatedBy,omitempty" db:"impersonated_by"`
 	CreatedAt      time.Time              `json:"createdAt" db:"created_at"`
 }
 
@@ -27,17 +28,29 @@ type CreateAuditLogRequest struct {
 	UserAgent    *string                `json:"userAgent,omitempty"`
 }
 
+// AuditLogQuery filters, orders and pages audit logs. StartTime and EndTime are parsed by the
+// handler, which accepts dates as well as timestamps, so they are not bound from the query string.
 type AuditLogQuery struct {
-	UserID       *string   `json:"userId,omitempty"`
-	Action       *string   `json:"action,omitempty"`
-	ResourceType *string   `json:"resourceType,omitempty"`
-	ResourceID   *string   `json:"resourceId,omitempty"`
-	StartTime    time.Time `json:"startTime" binding:"required"`
-	EndTime      time.Time `json:"endTime" binding:"required"`
-	Limit        int       `json:"limit,omitempty"`
-	Offset       int       `json:"offset,omitempty"`
+	UserID         *string   `json:"userId,omitempty" form:"userId"`
+	Action         *string   `json:"action,omitempty" form:"action"`
+	ResourceType   *string   `json:"resourceType,omitempty" form:"resourceType"`
+	ResourceID     *string   `json:"resourceId,omitempty" form:"resourceId"`
+	ImpersonatedBy *string   `json:"impersonatedBy,omitempty" form:"impersonatedBy"`
+	StartTime      time.Time `json:"startTime" form:"-"`
+	EndTime        time.Time `json:"endTime" form:"-"`
+	Sort           string    `json:"sort,omitempty" form:"sort" binding:"omitempty,oneof=asc desc"`
+	Limit          int       `json:"limit,omitempty" form:"limit" binding:"omitempty,min=0"`
+	Offset         int       `json:"offset,omitempty" form:"offset" binding:"omitempty,min=0"`
+	// Cursor continues after a row by keyset on (created_at, id) instead of by offset
+	Cursor string `json:"-" form:"-"`
 }
 
+// Audit log sort orders, by time
+const (
+	AuditSortAsc  = "asc"
+	AuditSortDesc = "desc"
+)
+
 // Common audit actions
 const (
 	ActionCreate = "create"
@@ -50,4 +63,9 @@ const (
 	ActionScale  = "scale"
 	ActionStop   = "stop"
 	ActionStart  = "start"
+
+	// Impersonation actions are recorded under the impersonated user, with the support user who
+	// started or ended the session as ImpersonatedBy
+	ActionImpersonationStarted = "impersonation_started"
+	ActionImpersonationEnded   = "impersonation_ended"
 )
@@ -49,6 +49,16 @@ type SetupCloudCredentialsRequest struct {
 	Credentials    map[string]interface{} `json:"credentials" binding:"required"`
 }
 
+// CloudCredentialsValidation describes what a set of cloud credentials can access
+type CloudCredentialsValidation struct {
+	Valid       bool     `json:"valid"`
+	Message     string   `json:"message"`
+	Permissions []string `json:"permissions"`
+	Limitations []string `json:"limitations"`
+	Regions     []string `json:"regions"`
+	Services    []string `json:"services"`
+}
+
 type CloudCredentialsResponse struct {
 	Success bool              `json:"success"`
 	Data    *CloudCredentials `json:"data,omitempty"`
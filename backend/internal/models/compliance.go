@@ -21,12 +21,13 @@ const (
 type ComplianceStatus string
 
 const (
-	ComplianceStatusCompliant         ComplianceStatus = "compliant"
-	ComplianceStatusNonCompliant      ComplianceStatus = "non_compliant"
-	ComplianceStatusPartial           ComplianceStatus = "partial"
-	ComplianceStatusUnderReview       ComplianceStatus = "under_review"
-	ComplianceStatusNotApplicable     ComplianceStatus = "not_applicable"
-	ComplianceAssessmentStatusRunning ComplianceStatus = "running"
+	ComplianceStatusCompliant           ComplianceStatus = "compliant"
+	ComplianceStatusNonCompliant        ComplianceStatus = "non_compliant"
+	ComplianceStatusPartial             ComplianceStatus = "partial"
+	ComplianceStatusUnderReview         ComplianceStatus = "under_review"
+	ComplianceStatusNotApplicable       ComplianceStatus = "not_applicable"
+	ComplianceAssessmentStatusRunning   ComplianceStatus = "running"
+	ComplianceAssessmentStatusCompleted ComplianceStatus = "completed"
 )
 
 // ComplianceControlStatus represents the status of individual controls
@@ -155,29 +156,76 @@ type ComplianceGap struct {
 	UpdatedAt    time.Time               `json:"updatedAt" db:"updated_at"`
 }
 
-// ComplianceReport represents a compliance report
+// ComplianceReport represents a compliance report. Data holds the state of the report's
+// frameworks when it was generated, so the report renders the same whenever it is downloaded.
 type ComplianceReport struct {
 	ID             string                 `json:"id" db:"id"`
 	OrganizationID string                 `json:"organizationId" db:"organization_id"`
-	UserID         string                 `json:"userId" db:"user_id"`
+	UserID         *string                `json:"userId" db:"user_id"`
+	ScheduleID     *string                `json:"scheduleId" db:"schedule_id"`
 	Name           string                 `json:"name" db:"name"`
 	Description    string                 `json:"description" db:"description"`
 	Type           string                 `json:"type" db:"type"`
 	Frameworks     []ComplianceFramework  `json:"frameworks" db:"frameworks"`
 	Period         ReportPeriod           `json:"period" db:"period"`
 	Status         string                 `json:"status" db:"status"`
-	Data           map[string]interface{} `json:"data" db:"data"`
+	Data           map[string]interface{} `json:"data,omitempty" db:"data"`
 	GeneratedAt    *time.Time             `json:"generatedAt" db:"generated_at"`
+	DeliveredAt    *time.Time             `json:"deliveredAt" db:"delivered_at"`
+	DeliveryError  *string                `json:"deliveryError" db:"delivery_error"`
 	CreatedAt      time.Time              `json:"createdAt" db:"created_at"`
 	UpdatedAt      time.Time              `json:"updatedAt" db:"updated_at"`
 }
 
+// Compliance report types and statuses
+const (
+	ComplianceReportTypeScheduled   = "scheduled"
+	ComplianceReportStatusCompleted = "completed"
+)
+
 // ReportPeriod represents the period for compliance reporting
 type ReportPeriod struct {
 	StartDate time.Time `json:"startDate"`
 	EndDate   time.Time `json:"endDate"`
 }
 
+// ComplianceReportSchedule generates a report on one of an organization's frameworks every month
+// or quarter and emails it to the schedule's recipients
+type ComplianceReportSchedule struct {
+	ID             string     `json:"id" db:"id"`
+	OrganizationID string     `json:"organizationId" db:"organization_id"`
+	FrameworkID    string     `json:"frameworkId" db:"framework_id"`
+	Frequency      string     `json:"frequency" db:"frequency"`
+	Recipients     []string   `json:"recipients" db:"recipients"`
+	Enabled        bool       `json:"enabled" db:"enabled"`
+	NextRunAt      time.Time  `json:"nextRunAt" db:"next_run_at"`
+	LastRunAt      *time.Time `json:"lastRunAt" db:"last_run_at"`
+	CreatedBy      *string    `json:"createdBy" db:"created_by"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// Compliance report frequencies. Reports cover the calendar month or quarter that just ended.
+const (
+	ComplianceReportMonthly   = "monthly"
+	ComplianceReportQuarterly = "quarterly"
+)
+
+// CreateComplianceReportScheduleRequest represents a request to schedule compliance reports
+type CreateComplianceReportScheduleRequest struct {
+	FrameworkID string   `json:"frameworkId" binding:"required,uuid"`
+	Frequency   string   `json:"frequency" binding:"required,oneof=monthly quarterly"`
+	Recipients  []string `json:"recipients" binding:"required,min=1,dive,email"`
+	Enabled     *bool    `json:"enabled,omitempty"`
+}
+
+// UpdateComplianceReportScheduleRequest represents a request to update a compliance report schedule
+type UpdateComplianceReportScheduleRequest struct {
+	Frequency  *string   `json:"frequency,omitempty" binding:"omitempty,oneof=monthly quarterly"`
+	Recipients *[]string `json:"recipients,omitempty" binding:"omitempty,min=1,dive,email"`
+	Enabled    *bool     `json:"enabled,omitempty"`
+}
+
 // ComplianceMetrics represents overall compliance metrics
 type ComplianceMetrics struct {
 	OverallScore        float64                                  `json:"overallScore"`
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// CostSnapshot is an organization's projected daily spend on a given day
+type CostSnapshot struct {
+	OrganizationID string    `json:"organizationId" db:"organization_id"`
+	DailyCost      float64   `json:"dailyCost" db:"daily_cost"`
+	CapturedOn     time.Time `json:"capturedOn" db:"captured_on"`
+}
+
+// ResourceCostSnapshot is an infrastructure resource's projected daily cost and average usage on a given day
+type ResourceCostSnapshot struct {
+	InfrastructureID  string    `json:"infrastructureId" db:"infrastructure_id"`
+	DailyCost         float64   `json:"dailyCost" db:"daily_cost"`
+	CPUUtilization    float64   `json:"cpuUtilization" db:"cpu_utilization"`
+	MemoryUtilization float64   `json:"memoryUtilization" db:"memory_utilization"`
+	CapturedOn        time.Time `json:"capturedOn" db:"captured_on"`
+}
+
+// CostAllocationSettings are the tag keys, e.g. cost-center, team or project, that an
+// organization's costs are allocated by
+type CostAllocationSettings struct {
+	OrganizationID string     `json:"organizationId" db:"organization_id"`
+	Dimensions     []string   `json:"dimensions" db:"dimensions"`
+	UpdatedBy      *string    `json:"updatedBy,omitempty" db:"updated_by"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty" db:"created_at"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// UpdateCostAllocationSettingsRequest represents a request to replace an organization's cost
+// allocation dimensions
+type UpdateCostAllocationSettingsRequest struct {
+	Dimensions []string `json:"dimensions" binding:"required"`
+}
@@ -1,34 +1,249 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
 
 type Deployment struct {
-	ID             string                 `json:"id" db:"id"`
-	OrganizationID string                 `json:"organizationId" db:"organization_id"`
-	Name           string                 `json:"name" db:"name"`
-	Application    string                 `json:"application" db:"application"`
-	Version        string                 `json:"version" db:"version"`
-	Environment    string                 `json:"environment" db:"environment"`
-	Status         string                 `json:"status" db:"status"`
-	Progress       int                    `json:"progress" db:"progress"`
-	Configuration  map[string]interface{} `json:"configuration" db:"configuration"`
-	StartedAt      *time.Time             `json:"startedAt" db:"started_at"`
-	CompletedAt    *time.Time             `json:"completedAt" db:"completed_at"`
-	CreatedBy      *string                `json:"createdBy" db:"created_by"`
-	CreatedAt      time.Time              `json:"createdAt" db:"created_at"`
-	UpdatedAt      time.Time              `json:"updatedAt" db:"updated_at"`
+	ID             string                  `json:"id" db:"id"`
+	OrganizationID string                  `json:"organizationId" db:"organization_id"`
+	Name           string                  `json:"name" db:"name"`
+	Application    string                  `json:"application" db:"application"`
+	Version        string                  `json:"version" db:"version"`
+	Environment    string                  `json:"environment" db:"environment"`
+	Status         string                  `json:"status" db:"status"`
+	Progress       int                     `json:"progress" db:"progress"`
+	Configuration  map[string]interface{}  `json:"configuration" db:"configuration"`
+	StartedAt      *time.Time              `json:"startedAt" db:"started_at"`
+	CompletedAt    *time.Time              `json:"completedAt" db:"completed_at"`
+	CreatedBy      *string                 `json:"createdBy" db:"created_by"`
+	PipelineID     *string                 `json:"pipelineId,omitempty" db:"pipeline_id"`
+	Strategy       string                  `json:"strategy" db:"strategy"`
+	StrategyState  DeploymentStrategyState `json:"strategyState" db:"strategy_state"`
+	ScheduledFor   *time.Time              `json:"scheduledFor,omitempty" db:"scheduled_for"`
+	CreatedAt      time.Time               `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time               `json:"updatedAt" db:"updated_at"`
 }
 
 // Deployment status constants
 const (
+	DeploymentStatusScheduled   = "scheduled"
 	DeploymentStatusPending     = "pending"
 	DeploymentStatusRunning     = "running"
 	DeploymentStatusCompleted   = "completed"
 	DeploymentStatusFailed      = "failed"
 	DeploymentStatusCancelled   = "cancelled"
 	DeploymentStatusRollingBack = "rolling_back"
+	DeploymentStatusRolledBack  = "rolled_back"
+)
+
+// Deployment strategy constants
+const (
+	DeploymentStrategyRecreate  = "recreate"
+	DeploymentStrategyRolling   = "rolling"
+	DeploymentStrategyCanary    = "canary"
+	DeploymentStrategyBlueGreen = "blue_green"
+)
+
+// Deployment strategy phases recorded while a deployment rolls out
+const (
+	StrategyPhaseRollingOut  = "rolling_out"
+	StrategyPhaseHealthCheck = "health_check"
+	StrategyPhasePromoted    = "promoted"
+	StrategyPhaseRolledBack  = "rolled_back"
+	StrategyPhaseSwitched    = "traffic_switched"
+	StrategyPhaseComplete    = "complete"
+)
+
+// Canary defaults used when the request does not configure them
+const (
+	DefaultCanaryPercentage         = 10
+	DefaultCanaryHealthCheckSeconds = 300
+	DefaultCanaryMaxErrorRate       = 5.0
+)
+
+// Auto-rollback defaults used when the request does not configure them
+const (
+	DefaultAutoRollbackWatchSeconds = 600
+	DefaultAutoRollbackMaxErrorRate = 5.0
+)
+
+// DeploymentStrategyState records how far a deployment's rollout strategy has progressed
+type DeploymentStrategyState struct {
+	Phase             string                   `json:"phase,omitempty"`
+	TrafficPercentage int                      `json:"trafficPercentage"`
+	Canary            *CanaryConfig            `json:"canary,omitempty"`
+	ErrorRate         *float64                 `json:"errorRate,omitempty"`
+	HealthCheckUntil  *time.Time               `json:"healthCheckUntil,omitempty"`
+	ActiveColor       string                   `json:"activeColor,omitempty"`
+	AutoRollback      *AutoRollbackConfig      `json:"autoRollback,omitempty"`
+	WatchUntil        *time.Time               `json:"watchUntil,omitempty"`
+	Steps             []DeploymentStrategyStep `json:"steps,omitempty"`
+}
+
+// DeploymentStrategyStep is the state of a single orchestration step
+type DeploymentStrategyStep struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Message     string     `json:"message,omitempty"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// Value stores the strategy state as JSONB
+func (s DeploymentStrategyState) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+// Scan reads the strategy state from JSONB
+func (s *DeploymentStrategyState) Scan(value interface{}) error {
+	if value == nil {
+		*s = DeploymentStrategyState{}
+		return nil
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("unsupported strategy state type %T", value)
+	}
+
+	return json.Unmarshal(data, s)
+}
+
+// CanaryConfig controls how a canary deployment is promoted
+type CanaryConfig struct {
+	Percentage         int     `json:"percentage" binding:"omitempty,min=1,max=99"`
+	HealthCheckSeconds int     `json:"healthCheckSeconds" binding:"omitempty,min=0,max=3600"`
+	MaxErrorRate       float64 `json:"maxErrorRate" binding:"omitempty,min=0,max=100"`
+}
+
+// AutoRollbackConfig controls the health watch after a deployment completes. If the deployment's
+// error rate exceeds MaxErrorRate, or an error or critical alert fires against it, before the
+// watch window ends, it is rolled back to the previous successful version.
+type AutoRollbackConfig struct {
+	WatchSeconds int     `json:"watchSeconds" binding:"omitempty,min=0,max=86400"`
+	MaxErrorRate float64 `json:"maxErrorRate" binding:"omitempty,min=0,max=100"`
+}
+
+// HealthCheckConfig controls the HTTP health checks a deployment must pass before it completes.
+// It is read from the deployment configuration's healthCheck entry, which is either a path or URL
+// or an object of these fields. A path is resolved against the configuration's url entry.
+type HealthCheckConfig struct {
+	URL              string `json:"url,omitempty"`
+	Path             string `json:"path,omitempty"`
+	IntervalSeconds  int    `json:"intervalSeconds,omitempty"`
+	TimeoutSeconds   int    `json:"timeoutSeconds,omitempty"`
+	SuccessThreshold int    `json:"successThreshold,omitempty"`
+	FailureThreshold int    `json:"failureThreshold,omitempty"`
+}
+
+// Health check defaults used when the configuration does not set them
+const (
+	DefaultHealthCheckIntervalSeconds  = 10
+	DefaultHealthCheckTimeoutSeconds   = 5
+	DefaultHealthCheckSuccessThreshold = 1
+	DefaultHealthCheckFailureThreshold = 3
+)
+
+// DeploymentHealthCheck is the result of one health check of a deployed application
+type DeploymentHealthCheck struct {
+	ID           string    `json:"id" db:"id"`
+	DeploymentID string    `json:"deploymentId" db:"deployment_id"`
+	URL          string    `json:"url" db:"url"`
+	Healthy      bool      `json:"healthy" db:"healthy"`
+	StatusCode   *int      `json:"statusCode,omitempty" db:"status_code"`
+	LatencyMs    int64     `json:"latencyMs" db:"latency_ms"`
+	Error        *string   `json:"error,omitempty" db:"error"`
+	CheckedAt    time.Time `json:"checkedAt" db:"checked_at"`
+}
+
+// DeploymentRollback records a rollback of a deployment and what triggered it
+type DeploymentRollback struct {
+	ID                   string               `json:"id" db:"id"`
+	OrganizationID       string               `json:"organizationId" db:"organization_id"`
+	DeploymentID         string               `json:"deploymentId" db:"deployment_id"`
+	RollbackDeploymentID *string              `json:"rollbackDeploymentId" db:"rollback_deployment_id"`
+	FromVersion          string               `json:"fromVersion" db:"from_version"`
+	ToVersion            string               `json:"toVersion" db:"to_version"`
+	Trigger              string               `json:"trigger" db:"trigger_type"`
+	Reason               string               `json:"reason" db:"reason"`
+	MetricName           *string              `json:"metricName,omitempty" db:"metric_name"`
+	MetricValue          *float64             `json:"metricValue,omitempty" db:"metric_value"`
+	Threshold            *float64             `json:"threshold,omitempty" db:"threshold"`
+	InitiatedBy          *string              `json:"initiatedBy,omitempty" db:"initiated_by"`
+	SafetyCheck          *RollbackSafetyCheck `json:"safetyCheck,omitempty" db:"safety_check"`
+	CreatedAt            time.Time            `json:"createdAt" db:"created_at"`
+}
+
+// Rollback triggers
+const (
+	RollbackTriggerManual    = "manual"
+	RollbackTriggerAutomatic = "automatic"
 )
 
+// RollbackSafetyCheck records whether a rollback's target version brings back unresolved critical
+// vulnerabilities that the version being rolled back from fixed, going by the latest completed
+// scan of each version's deployments, and what was decided
+type RollbackSafetyCheck struct {
+	Decision        string                      `json:"decision"`
+	TargetScanID    *string                     `json:"targetScanId,omitempty"`
+	CurrentScanID   *string                     `json:"currentScanId,omitempty"`
+	Vulnerabilities []ReintroducedVulnerability `json:"vulnerabilities,omitempty"`
+	Message         string                      `json:"message"`
+	CheckedAt       time.Time                   `json:"checkedAt"`
+}
+
+// ReintroducedVulnerability is a critical finding of a rollback's target version that the current
+// version no longer has
+type ReintroducedVulnerability struct {
+	ID       string  `json:"id"`
+	CVEID    *string `json:"cveId,omitempty"`
+	Title    string  `json:"title"`
+	Location string  `json:"location,omitempty"`
+}
+
+// Rollback safety check decisions
+const (
+	// RollbackCheckPassed means the target version reintroduces no known critical vulnerabilities
+	RollbackCheckPassed = "passed"
+	// RollbackCheckUnverified means one of the versions has no completed scan to compare
+	RollbackCheckUnverified = "unverified"
+	// RollbackCheckRefused means the rollback was refused for reintroducing vulnerabilities
+	RollbackCheckRefused = "refused"
+	// RollbackCheckOverridden means the rollback went ahead at the initiator's request despite
+	// reintroducing vulnerabilities
+	RollbackCheckOverridden = "overridden"
+	// RollbackCheckWarned means an automatic rollback went ahead despite reintroducing
+	// vulnerabilities, since restoring a healthy version comes first
+	RollbackCheckWarned = "warned"
+)
+
+// Health metrics that trigger automatic rollbacks
+const (
+	RollbackMetricErrorRate = "error_rate"
+	RollbackMetricAlert     = "alert"
+)
+
+// DeploymentDetail is a deployment together with its rollback history, including the rollback
+// that created it if it is a rollback deployment
+type DeploymentDetail struct {
+	Deployment
+	Rollbacks []*DeploymentRollback `json:"rollbacks"`
+}
+
+// DeploymentLock is held by the one deployment allowed to run for an application and environment
+type DeploymentLock struct {
+	OrganizationID string    `json:"organizationId" db:"organization_id"`
+	Application    string    `json:"application" db:"application"`
+	Environment    string    `json:"environment" db:"environment"`
+	DeploymentID   string    `json:"deploymentId" db:"deployment_id"`
+	AcquiredAt     time.Time `json:"acquiredAt" db:"acquired_at"`
+	ExpiresAt      time.Time `json:"expiresAt" db:"expires_at"`
+}
+
 // Environment constants
 const (
 	EnvironmentDevelopment = "development"
@@ -37,6 +252,57 @@ const (
 	EnvironmentTesting     = "testing"
 )
 
+// EnvironmentNames maps the built-in environment types to their display names
+var EnvironmentNames = map[string]string{
+	EnvironmentDevelopment: "Development",
+	EnvironmentStaging:     "Staging",
+	EnvironmentProduction:  "Production",
+	EnvironmentTesting:     "Testing",
+}
+
+// Environment is a custom deployment environment defined by an organization
+type Environment struct {
+	ID             string    `json:"id" db:"id"`
+	OrganizationID string    `json:"organizationId" db:"organization_id"`
+	Key            string    `json:"key" db:"key"`
+	Name           string    `json:"name" db:"name"`
+	Description    string    `json:"description" db:"description"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+}
+
+// Environment health status constants
+const (
+	EnvironmentStatusHealthy = "healthy"
+	EnvironmentStatusWarning = "warning"
+	EnvironmentStatusError   = "error"
+)
+
+// EnvironmentHealth summarizes the state of an environment the organization deploys to
+type EnvironmentHealth struct {
+	ID             string     `json:"id"`
+	Name           string     `json:"name"`
+	Status         string     `json:"status"`
+	ServicesCount  int        `json:"servicesCount"`
+	LastDeployment *time.Time `json:"lastDeployment,omitempty"`
+	Uptime         float64    `json:"uptime"`
+	Custom         bool       `json:"custom"`
+}
+
+// EnvironmentOutage is a period an error or critical alert was open against a deployment
+// in an environment
+type EnvironmentOutage struct {
+	Environment string
+	StartedAt   time.Time
+	EndedAt     *time.Time
+}
+
+// CreateEnvironmentRequest represents a request to define a custom environment
+type CreateEnvironmentRequest struct {
+	Key         string `json:"key" binding:"required,min=1,max=50"`
+	Name        string `json:"name" binding:"required,min=1,max=255"`
+	Description string `json:"description,omitempty"`
+}
+
 // CreateDeploymentRequest represents a request to create a new deployment
 type CreateDeploymentRequest struct {
 	Name          string                 `json:"name" binding:"required,min=1,max=255"`
@@ -44,6 +310,11 @@ type CreateDeploymentRequest struct {
 	Version       string                 `json:"version" binding:"required,min=1,max=100"`
 	Environment   string                 `json:"environment" binding:"required,min=1,max=50"`
 	Configuration map[string]interface{} `json:"configuration,omitempty"`
+	PipelineID    *string                `json:"pipelineId,omitempty" binding:"omitempty,uuid"`
+	Strategy      string                 `json:"strategy,omitempty" binding:"omitempty,oneof=recreate rolling canary blue_green"`
+	Canary        *CanaryConfig          `json:"canary,omitempty"`
+	AutoRollback  *AutoRollbackConfig    `json:"autoRollback,omitempty"`
+	ScheduledFor  *time.Time             `json:"scheduledFor,omitempty"`
 }
 
 // UpdateDeploymentRequest represents a request to update a deployment
@@ -54,4 +325,45 @@ type UpdateDeploymentRequest struct {
 	Configuration map[string]interface{} `json:"configuration,omitempty"`
 	StartedAt     *time.Time             `json:"startedAt,omitempty"`
 	CompletedAt   *time.Time             `json:"completedAt,omitempty"`
-}
\ No newline at end of file
+}
+
+// DeploymentPreview describes what a deployment request would change relative to the version
+// currently running in its environment
+type DeploymentPreview struct {
+	Application        string            `json:"application"`
+	Environment        string            `json:"environment"`
+	BaselineDeployment *Deployment       `json:"baselineDeployment,omitempty"`
+	Version            VersionChange     `json:"version"`
+	Replicas           *ReplicaChange    `json:"replicas,omitempty"`
+	Configuration      ConfigurationDiff `json:"configuration"`
+	HasChanges         bool              `json:"hasChanges"`
+}
+
+// VersionChange is the version delta between the running and requested deployments
+type VersionChange struct {
+	From    string `json:"from,omitempty"`
+	To      string `json:"to"`
+	Changed bool   `json:"changed"`
+}
+
+// ReplicaChange is the change in the configured replica count
+type ReplicaChange struct {
+	From  *int `json:"from,omitempty"`
+	To    *int `json:"to,omitempty"`
+	Delta int  `json:"delta"`
+}
+
+// ConfigurationDiff lists configuration keys added, removed or changed by a deployment.
+// Nested keys are flattened into dot-separated paths.
+type ConfigurationDiff struct {
+	Added   map[string]interface{} `json:"added"`
+	Removed map[string]interface{} `json:"removed"`
+	Changed []ConfigurationChange  `json:"changed"`
+}
+
+// ConfigurationChange is a configuration key whose value differs between deployments
+type ConfigurationChange struct {
+	Key  string      `json:"key"`
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
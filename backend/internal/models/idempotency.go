@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// IdempotencyKey records a request made with an Idempotency-Key header and the response it produced.
+// StatusCode is zero while the original request is still being processed.
+type IdempotencyKey struct {
+	OrganizationID string    `json:"organizationId" db:"organization_id"`
+	Key            string    `json:"key" db:"key"`
+	RequestHash    string    `json:"requestHash" db:"request_hash"`
+	StatusCode     int       `json:"statusCode" db:"status_code"`
+	ContentType    string    `json:"contentType" db:"content_type"`
+	ResponseBody   []byte    `json:"-" db:"response_body"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+	ExpiresAt      time.Time `json:"expiresAt" db:"expires_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// StartImpersonationRequest gives the reason a support user needs to act as a user, e.g. the
+// ticket being worked on
+type StartImpersonationRequest struct {
+	Reason string `json:"reason" binding:"required,min=1,max=500"`
+}
+
+// ImpersonationSession is an access token that acts as a user on behalf of the support user who
+// impersonates them. No refresh token is issued, so the session ends when the token expires.
+type ImpersonationSession struct {
+	Token          string    `json:"token"`
+	User           User      `json:"user"`
+	OrganizationID string    `json:"organizationId"`
+	ImpersonatedBy string    `json:"impersonatedBy"`
+	ExpiresAt      time.Time `json:"expiresAt"`
+}
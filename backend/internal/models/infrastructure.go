@@ -16,6 +16,10 @@ type Infrastructure struct {
 	ExternalID     *string                `json:"externalId" db:"external_id"`
 	CreatedAt      time.Time              `json:"createdAt" db:"created_at"`
 	UpdatedAt      time.Time              `json:"updatedAt" db:"updated_at"`
+	DeletedAt      *time.Time             `json:"deletedAt,omitempty" db:"deleted_at"`
+
+	// TagSync reports how a tag change was applied to the cloud resource; it is not stored
+	TagSync *TagSyncResult `json:"tagSync,omitempty" db:"-"`
 }
 
 type CreateInfrastructureRequest struct {
@@ -36,6 +40,100 @@ type UpdateInfrastructureRequest struct {
 	ExternalID     *string                `json:"externalId,omitempty" example:"i-1234567890abcdef0"`
 }
 
+// InfrastructureTagsRequest adds tags to an infrastructure resource. Tags are normalized to "key=value".
+type InfrastructureTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1" example:"[\"environment=production\",\"team=web\"]"`
+}
+
+// Actions of tag changes applied to cloud resources
+const (
+	TagSyncActionSet    = "set"
+	TagSyncActionRemove = "remove"
+)
+
+// TagSyncFailure is a change to the tag with a key that could not be applied to the cloud resource
+type TagSyncFailure struct {
+	Key    string `json:"key"`
+	Action string `json:"action"`
+	Error  string `json:"error"`
+}
+
+// TagSyncResult reports the tag changes applied to a cloud resource, as the "key=value" tags set
+// and the keys removed, and those that failed
+type TagSyncResult struct {
+	Applied []string         `json:"applied"`
+	Removed []string         `json:"removed"`
+	Failed  []TagSyncFailure `json:"failed,omitempty"`
+}
+
+// ImportInfrastructureRequest imports an existing cloud resource. Type, name and region are taken
+// from the provider when omitted.
+type ImportInfrastructureRequest struct {
+	ExternalID string   `json:"externalId" binding:"required,min=1,max=255" example:"i-1234567890abcdef0"`
+	Name       string   `json:"name,omitempty" binding:"omitempty,max=255" example:"legacy-web-server"`
+	Type       string   `json:"type,omitempty" binding:"omitempty,oneof=server database storage network container" example:"server"`
+	Region     string   `json:"region,omitempty" binding:"omitempty,max=100" example:"us-east-1"`
+	Tags       []string `json:"tags,omitempty" example:"[\"environment=production\"]"`
+}
+
+// DriftItem is one field whose actual cloud value differs from the recorded value
+type DriftItem struct {
+	Field    string      `json:"field"`
+	Expected interface{} `json:"expected"`
+	Actual   interface{} `json:"actual"`
+	Critical bool        `json:"critical"`
+}
+
+// DriftReport compares an infrastructure resource's recorded state with its state at the provider
+type DriftReport struct {
+	InfrastructureID string      `json:"infrastructureId"`
+	Name             string      `json:"name"`
+	Provider         string      `json:"provider"`
+	ExternalID       string      `json:"externalId"`
+	Drifted          bool        `json:"drifted"`
+	Items            []DriftItem `json:"items"`
+	CheckedAt        time.Time   `json:"checkedAt"`
+}
+
+// InfrastructureStatusChange records an infrastructure resource entering a status
+type InfrastructureStatusChange struct {
+	InfrastructureID string    `json:"infrastructureId" db:"infrastructure_id"`
+	Status           string    `json:"status" db:"status"`
+	ChangedAt        time.Time `json:"changedAt" db:"changed_at"`
+}
+
+// InfrastructureChange is an entry in an infrastructure resource's change log
+type InfrastructureChange struct {
+	ID               string                               `json:"id" db:"id"`
+	InfrastructureID string                               `json:"infrastructureId" db:"infrastructure_id"`
+	ResourceName     string                               `json:"resourceName"`
+	Provider         string                               `json:"provider"`
+	ChangeType       string                               `json:"changeType" db:"change_type"`
+	Changes          map[string]InfrastructureFieldChange `json:"changes,omitempty" db:"changes"`
+	Message          *string                              `json:"message,omitempty" db:"message"`
+	ChangedAt        time.Time                            `json:"changedAt" db:"changed_at"`
+}
+
+// InfrastructureFieldChange is the value of a resource field before and after a change
+type InfrastructureFieldChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Infrastructure change types
+const (
+	InfraChangeCreated       = "created"
+	InfraChangeUpdated       = "updated"
+	InfraChangeStatusChanged = "status_changed"
+	InfraChangeDeleted       = "deleted"
+	InfraChangeRestored      = "restored"
+	InfraChangeSynced        = "synced"
+	InfraChangeSyncFailed    = "sync_failed"
+	InfraChangeScaled        = "scaled"
+	InfraChangeScaleFailed   = "scale_failed"
+	InfraChangeSnapshotted   = "snapshotted"
+)
+
 // Infrastructure status constants
 const (
 	InfraStatusPending    = "pending"
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// InfrastructureSnapshot is a backup of a database resource taken at its provider. The snapshot
+// itself is stored by the provider; CloudWeave records where it is and what it was taken of.
+type InfrastructureSnapshot struct {
+	ID               string                 `json:"id" db:"id"`
+	OrganizationID   string                 `json:"organizationId" db:"organization_id"`
+	InfrastructureID *string                `json:"infrastructureId" db:"infrastructure_id"`
+	Provider         string                 `json:"provider" db:"provider"`
+	Region           string                 `json:"region" db:"region"`
+	ExternalID       string                 `json:"externalId" db:"external_id"`
+	Name             string                 `json:"name" db:"name"`
+	Status           string                 `json:"status" db:"status"`
+	SizeGB           *float64               `json:"sizeGb,omitempty" db:"size_gb"`
+	Metadata         map[string]interface{} `json:"metadata" db:"metadata"`
+	CreatedBy        *string                `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt        time.Time              `json:"createdAt" db:"created_at"`
+	UpdatedAt        time.Time              `json:"updatedAt" db:"updated_at"`
+}
+
+// CreateSnapshotRequest takes a snapshot of a database resource, named after the resource and the
+// time when no name is given
+type CreateSnapshotRequest struct {
+	Name string `json:"name,omitempty" binding:"omitempty,min=1,max=200" example:"orders-db-before-migration"`
+}
+
+// RestoreSnapshotRequest provisions a new resource from a snapshot, in the snapshot's region. Tags
+// default to those of the snapshotted resource.
+type RestoreSnapshotRequest struct {
+	Name string   `json:"name" binding:"required,min=1,max=255" example:"orders-db-restored"`
+	Tags []string `json:"tags,omitempty" example:"[\"environment=staging\"]"`
+}
+
+// Infrastructure snapshot statuses
+const (
+	SnapshotStatusCreating  = "creating"
+	SnapshotStatusAvailable = "available"
+	SnapshotStatusFailed    = "failed"
+	SnapshotStatusDeleted   = "deleted"
+)
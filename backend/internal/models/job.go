@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// JobStatus is the state of a background job
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusCompleted JobStatus = "completed"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+// IsFinished reports whether a job in the status will not run again
+func (s JobStatus) IsFinished() bool {
+	return s == JobStatusCompleted || s == JobStatusFailed
+}
+
+// Job is a long-running operation of an organization that runs in the background. Payload holds
+// the job type's input and Result its output once completed.
+type Job struct {
+	ID             string                 `json:"id" db:"id"`
+	OrganizationID string                 `json:"organizationId" db:"organization_id"`
+	UserID         *string                `json:"userId,omitempty" db:"user_id"`
+	Type           string                 `json:"type" db:"type"`
+	Status         JobStatus              `json:"status" db:"status"`
+	Exclusive      bool                   `json:"-" db:"exclusive"`
+	Payload        map[string]interface{} `json:"payload" db:"payload"`
+	Progress       int                    `json:"progress" db:"progress"`
+	Result         map[string]interface{} `json:"result,omitempty" db:"result"`
+	Error          *string                `json:"error,omitempty" db:"error"`
+	Attempts       int                    `json:"attempts" db:"attempts"`
+	CreatedAt      time.Time              `json:"createdAt" db:"created_at"`
+	StartedAt      *time.Time             `json:"startedAt,omitempty" db:"started_at"`
+	CompletedAt    *time.Time             `json:"completedAt,omitempty" db:"completed_at"`
+	UpdatedAt      time.Time              `json:"updatedAt" db:"updated_at"`
+}
@@ -14,6 +14,27 @@ type Metric struct {
 	CreatedAt    time.Time              `json:"createdAt" db:"created_at"`
 }
 
+// MetricRollup is a bucket of raw metric data points aggregated at a coarser resolution
+type MetricRollup struct {
+	ID           string    `json:"id" db:"id"`
+	ResourceID   string    `json:"resourceId" db:"resource_id"`
+	ResourceType string    `json:"resourceType" db:"resource_type"`
+	MetricName   string    `json:"metricName" db:"metric_name"`
+	Unit         string    `json:"unit" db:"unit"`
+	Bucket       time.Time `json:"bucket" db:"bucket"`
+	Min          float64   `json:"min" db:"min_value"`
+	Max          float64   `json:"max" db:"max_value"`
+	Avg          float64   `json:"avg" db:"avg_value"`
+	Count        int       `json:"count" db:"sample_count"`
+}
+
+// Metric resolutions
+const (
+	MetricResolutionRaw    = "raw"
+	MetricResolutionHourly = "hourly"
+	MetricResolutionDaily  = "daily"
+)
+
 // Metric types
 const (
 	MetricTypeCPU         = "cpu_utilization"
@@ -44,4 +65,6 @@ type MetricQuery struct {
 	EndTime      *time.Time `json:"endTime"`
 	Limit        int        `json:"limit"`
 	Offset       int        `json:"offset"`
+	// Cursor, when set, pages by keyset on (timestamp, id) instead of by Offset
+	Cursor string `json:"cursor"`
 }
\ No newline at end of file
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// NotificationChannel is an organization-configured destination for alert notifications
+type NotificationChannel struct {
+	ID             string                    `json:"id" db:"id"`
+	OrganizationID string                    `json:"organizationId" db:"organization_id"`
+	Name           string                    `json:"name" db:"name"`
+	Type           string                    `json:"type" db:"type"`
+	Config         NotificationChannelConfig `json:"config" db:"config"`
+	Severities     []string                  `json:"severities" db:"severities"`
+	Enabled        bool                      `json:"enabled" db:"enabled"`
+	CreatedBy      *string                   `json:"createdBy" db:"created_by"`
+	CreatedAt      time.Time                 `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time                 `json:"updatedAt" db:"updated_at"`
+}
+
+// Notification channel types
+const (
+	NotificationChannelEmail   = "email"
+	NotificationChannelWebhook = "webhook"
+)
+
+// NotificationChannelConfig holds the type-specific settings of a channel
+type NotificationChannelConfig struct {
+	// Recipients are the addresses an email channel sends to
+	Recipients []string `json:"recipients,omitempty"`
+	// URL is the endpoint a webhook channel posts to
+	URL string `json:"url,omitempty"`
+}
+
+// Value stores the channel config as JSONB
+func (c NotificationChannelConfig) Value() (driver.Value, error) {
+	return json.Marshal(c)
+}
+
+// Scan reads the channel config from JSONB
+func (c *NotificationChannelConfig) Scan(value interface{}) error {
+	if value == nil {
+		*c = NotificationChannelConfig{}
+		return nil
+	}
+
+	data, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("unsupported notification channel config type %T", value)
+	}
+
+	return json.Unmarshal(data, c)
+}
+
+// AcceptsSeverity reports whether alerts of a severity are routed to the channel. A channel
+// without severities receives every alert.
+func (c *NotificationChannel) AcceptsSeverity(severity string) bool {
+	if len(c.Severities) == 0 {
+		return true
+	}
+	for _, s := range c.Severities {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+// NotificationDelivery records the delivery of an alert to a notification channel
+type NotificationDelivery struct {
+	ID             string     `json:"id" db:"id"`
+	OrganizationID string     `json:"organizationId" db:"organization_id"`
+	ChannelID      string     `json:"channelId" db:"channel_id"`
+	AlertID        string     `json:"alertId" db:"alert_id"`
+	Status         string     `json:"status" db:"status"`
+	Attempts       int        `json:"attempts" db:"attempts"`
+	LastError      *string    `json:"lastError" db:"last_error"`
+	SentAt         *time.Time `json:"sentAt" db:"sent_at"`
+	CreatedAt      time.Time  `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updatedAt" db:"updated_at"`
+}
+
+// Notification delivery statuses
+const (
+	NotificationDeliveryPending = "pending"
+	NotificationDeliverySent    = "sent"
+	NotificationDeliveryFailed  = "failed"
+)
+
+// CreateNotificationChannelRequest represents a request to create a notification channel
+type CreateNotificationChannelRequest struct {
+	Name       string                    `json:"name" binding:"required,min=1,max=255"`
+	Type       string                    `json:"type" binding:"required,oneof=email webhook"`
+	Config     NotificationChannelConfig `json:"config"`
+	Severities []string                  `json:"severities,omitempty"`
+	Enabled    *bool                     `json:"enabled,omitempty"`
+}
+
+// UpdateNotificationChannelRequest represents a request to update a notification channel
+type UpdateNotificationChannelRequest struct {
+	Name       *string                    `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Config     *NotificationChannelConfig `json:"config,omitempty"`
+	Severities *[]string                  `json:"severities,omitempty"`
+	Enabled    *bool                      `json:"enabled,omitempty"`
+}
@@ -20,3 +20,24 @@ type UpdateOrganizationRequest struct {
 	Name     *string                `json:"name,omitempty" binding:"omitempty,min=2,max=255"`
 	Settings map[string]interface{} `json:"settings,omitempty"`
 }
+
+// OrganizationMembership is an organization a user belongs to and their role in it
+type OrganizationMembership struct {
+	OrganizationID   string    `json:"organizationId" db:"organization_id"`
+	OrganizationName string    `json:"organizationName" db:"name"`
+	OrganizationSlug string    `json:"organizationSlug" db:"slug"`
+	Role             string    `json:"role" db:"role"`
+	IsHome           bool      `json:"isHome" db:"is_home"`
+	CreatedAt        time.Time `json:"createdAt" db:"created_at"`
+}
+
+type SwitchOrganizationRequest struct {
+	OrganizationID string `json:"organizationId" binding:"required"`
+}
+
+type SwitchOrganizationResponse struct {
+	Success      bool                   `json:"success"`
+	Organization OrganizationMembership `json:"organization"`
+	Token        string                 `json:"token"`
+	RefreshToken string                 `json:"refreshToken"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Pipeline is a CI/CD pipeline made up of ordered stages
+type Pipeline struct {
+	ID             string          `json:"id" db:"id"`
+	OrganizationID string          `json:"organizationId" db:"organization_id"`
+	Name           string          `json:"name" db:"name"`
+	Repository     string          `json:"repository" db:"repository"`
+	Branch         string          `json:"branch" db:"branch"`
+	Status         string          `json:"status" db:"status"`
+	Stages         []PipelineStage `json:"stages" db:"stages"`
+	LastRun        *time.Time      `json:"lastRun,omitempty" db:"last_run"`
+	NextRun        *time.Time      `json:"nextRun,omitempty" db:"next_run"`
+	CreatedBy      *string         `json:"createdBy,omitempty" db:"created_by"`
+	CreatedAt      time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+// PipelineStage is a single step of a pipeline run
+type PipelineStage struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Duration    *int       `json:"duration,omitempty"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// Pipeline and stage status constants
+const (
+	PipelineStatusPending   = "pending"
+	PipelineStatusRunning   = "running"
+	PipelineStatusSuccess   = "success"
+	PipelineStatusFailed    = "failed"
+	PipelineStatusCancelled = "cancelled"
+)
+
+// PipelineStageRequest defines a stage when creating or updating a pipeline
+type PipelineStageRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// CreatePipelineRequest represents a request to create a new pipeline
+type CreatePipelineRequest struct {
+	Name       string                 `json:"name" binding:"required,min=1,max=255"`
+	Repository string                 `json:"repository" binding:"max=500"`
+	Branch     string                 `json:"branch" binding:"max=255"`
+	Stages     []PipelineStageRequest `json:"stages" binding:"required,min=1,dive"`
+	NextRun    *time.Time             `json:"nextRun,omitempty"`
+}
+
+// UpdatePipelineRequest represents a request to update a pipeline. Replacing the stages
+// resets the pipeline's run state.
+type UpdatePipelineRequest struct {
+	Name       *string                `json:"name,omitempty" binding:"omitempty,min=1,max=255"`
+	Repository *string                `json:"repository,omitempty" binding:"omitempty,max=500"`
+	Branch     *string                `json:"branch,omitempty" binding:"omitempty,max=255"`
+	Stages     []PipelineStageRequest `json:"stages,omitempty" binding:"omitempty,min=1,dive"`
+	NextRun    *time.Time             `json:"nextRun,omitempty"`
+}
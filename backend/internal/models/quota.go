@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// OrganizationQuota limits the infrastructure an organization can create. MaxResources caps the
+// number of resources of each type, keyed by type; types without an entry and a nil
+// MaxMonthlyBudget are unlimited.
+type OrganizationQuota struct {
+	OrganizationID   string         `json:"organizationId" db:"organization_id"`
+	MaxResources     map[string]int `json:"maxResources" db:"max_resources"`
+	MaxMonthlyBudget *float64       `json:"maxMonthlyBudget" db:"max_monthly_budget"`
+	UpdatedBy        *string        `json:"updatedBy,omitempty" db:"updated_by"`
+	CreatedAt        *time.Time     `json:"createdAt,omitempty" db:"created_at"`
+	UpdatedAt        *time.Time     `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// UpdateQuotaRequest represents a request to replace an organization's quota
+type UpdateQuotaRequest struct {
+	MaxResources     map[string]int `json:"maxResources"`
+	MaxMonthlyBudget *float64       `json:"maxMonthlyBudget" binding:"omitempty,min=0"`
+}
+
+// ResourceQuotaUsage is how many resources of a type an organization has against its limit
+type ResourceQuotaUsage struct {
+	Used  int  `json:"used"`
+	Limit *int `json:"limit"`
+}
+
+// QuotaUsage is an organization's current usage against its quota. MonthlyCost is the estimated
+// monthly cost of its resources, from the hourly cost recorded with each.
+type QuotaUsage struct {
+	Resources        map[string]ResourceQuotaUsage `json:"resources"`
+	MonthlyCost      float64                       `json:"monthlyCost"`
+	MaxMonthlyBudget *float64                      `json:"maxMonthlyBudget"`
+}
@@ -11,6 +11,7 @@ type Role struct {
 	Name           string                 `json:"name" db:"name"`
 	Description    string                 `json:"description" db:"description"`
 	IsSystem       bool                   `json:"isSystem" db:"is_system"`
+	ParentRoleID   *string                `json:"parentRoleId,omitempty" db:"parent_role_id"`
 	Permissions    []string               `json:"permissions" db:"permissions"`
 	Metadata       map[string]interface{} `json:"metadata" db:"metadata"`
 	CreatedAt      time.Time              `json:"createdAt" db:"created_at"`
@@ -38,6 +39,9 @@ type UserRole struct {
 	AssignedAt     time.Time  `json:"assignedAt" db:"assigned_at"`
 	ExpiresAt      *time.Time `json:"expiresAt,omitempty" db:"expires_at"`
 	IsActive       bool       `json:"isActive" db:"is_active"`
+	// RoleName and Permissions are resolved from the assigned role when listing a user's roles
+	RoleName    string   `json:"roleName,omitempty" db:"-"`
+	Permissions []string `json:"permissions,omitempty" db:"-"`
 }
 
 // ResourcePermission represents permissions for specific resources
@@ -65,6 +69,7 @@ type APIKey struct {
 	KeyPrefix      string                 `json:"keyPrefix" db:"key_prefix"`
 	Permissions    []string               `json:"permissions" db:"permissions"`
 	Scopes         []string               `json:"scopes" db:"scopes"`
+	RateLimit      int                    `json:"rateLimit" db:"rate_limit"` // Requests per minute, 0 uses the route group limits
 	IsActive       bool                   `json:"isActive" db:"is_active"`
 	LastUsedAt     *time.Time             `json:"lastUsedAt,omitempty" db:"last_used_at"`
 	ExpiresAt      *time.Time             `json:"expiresAt,omitempty" db:"expires_at"`
@@ -135,9 +140,66 @@ const (
 	PermissionMonitoringManage = "monitoring:manage"
 
 	// Admin permissions
-	PermissionAdminFull = "admin:full"
+	PermissionAdminFull        = "admin:full"
+	PermissionAdminImpersonate = "admin:impersonate"
+
+	// PermissionWildcard matches any permission on its own, or any action as "<resource>:*"
+	PermissionWildcard = "*"
+)
+
+// API key scopes restrict a key to read or write access on an area of the API
+const (
+	ScopeReadInfrastructure    = "read:infrastructure"
+	ScopeWriteInfrastructure   = "write:infrastructure"
+	ScopeReadDeployments       = "read:deployments"
+	ScopeWriteDeployments      = "write:deployments"
+	ScopeReadSecurity          = "read:security"
+	ScopeWriteSecurity         = "write:security"
+	ScopeReadCompliance        = "read:compliance"
+	ScopeWriteCompliance       = "write:compliance"
+	ScopeReadAudit             = "read:audit"
+	ScopeWriteAudit            = "write:audit"
+	ScopeReadCosts             = "read:costs"
+	ScopeWriteCosts            = "write:costs"
+	ScopeReadMetrics           = "read:metrics"
+	ScopeWriteMetrics          = "write:metrics"
+	ScopeReadAlerts            = "read:alerts"
+	ScopeWriteAlerts           = "write:alerts"
+	ScopeReadDashboard         = "read:dashboard"
+	ScopeReadRBAC              = "read:rbac"
+	ScopeWriteRBAC             = "write:rbac"
+	ScopeReadUser              = "read:user"
+	ScopeWriteUser             = "write:user"
+	ScopeReadCloudCredentials  = "read:cloud-credentials"
+	ScopeWriteCloudCredentials = "write:cloud-credentials"
 )
 
+// APIKeyScopes lists every scope that can be granted to an API key
+var APIKeyScopes = []string{
+	ScopeReadInfrastructure, ScopeWriteInfrastructure,
+	ScopeReadDeployments, ScopeWriteDeployments,
+	ScopeReadSecurity, ScopeWriteSecurity,
+	ScopeReadCompliance, ScopeWriteCompliance,
+	ScopeReadAudit, ScopeWriteAudit,
+	ScopeReadCosts, ScopeWriteCosts,
+	ScopeReadMetrics, ScopeWriteMetrics,
+	ScopeReadAlerts, ScopeWriteAlerts,
+	ScopeReadDashboard,
+	ScopeReadRBAC, ScopeWriteRBAC,
+	ScopeReadUser, ScopeWriteUser,
+	ScopeReadCloudCredentials, ScopeWriteCloudCredentials,
+}
+
+// IsValidAPIKeyScope reports whether scope is a known API key scope
+func IsValidAPIKeyScope(scope string) bool {
+	for _, known := range APIKeyScopes {
+		if known == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // System roles
 const (
 	RoleSystemAdmin           = "system_admin"
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// RetentionSettings are how many days an organization's metrics and resolved alerts are kept
+// before they are purged. Metric rollups are kept at least as long as the data they summarize.
+type RetentionSettings struct {
+	OrganizationID     string     `json:"organizationId" db:"organization_id"`
+	RawMetricsDays     int        `json:"rawMetricsDays" db:"raw_metrics_days"`
+	HourlyMetricsDays  int        `json:"hourlyMetricsDays" db:"hourly_metrics_days"`
+	DailyMetricsDays   int        `json:"dailyMetricsDays" db:"daily_metrics_days"`
+	ResolvedAlertsDays int        `json:"resolvedAlertsDays" db:"resolved_alerts_days"`
+	IsDefault          bool       `json:"isDefault"`
+	UpdatedBy          *string    `json:"updatedBy,omitempty" db:"updated_by"`
+	CreatedAt          *time.Time `json:"createdAt,omitempty" db:"created_at"`
+	UpdatedAt          *time.Time `json:"updatedAt,omitempty" db:"updated_at"`
+}
+
+// UpdateRetentionSettingsRequest represents a request to replace an organization's retention
+// settings
+type UpdateRetentionSettingsRequest struct {
+	RawMetricsDays     int `json:"rawMetricsDays" binding:"required,min=1,max=3650"`
+	HourlyMetricsDays  int `json:"hourlyMetricsDays" binding:"required,min=1,max=3650"`
+	DailyMetricsDays   int `json:"dailyMetricsDays" binding:"required,min=1,max=3650"`
+	ResolvedAlertsDays int `json:"resolvedAlertsDays" binding:"required,min=1,max=3650"`
+}
+
+// RetentionPreview counts the records of an organization the next retention purge would delete
+type RetentionPreview struct {
+	RawMetrics     int64     `json:"rawMetrics"`
+	HourlyMetrics  int64     `json:"hourlyMetrics"`
+	DailyMetrics   int64     `json:"dailyMetrics"`
+	ResolvedAlerts int64     `json:"resolvedAlerts"`
+	GeneratedAt    time.Time `json:"generatedAt"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// SBOMFormat is the format a software bill of materials is generated in
+type SBOMFormat string
+
+const (
+	SBOMFormatCycloneDX SBOMFormat = "cyclonedx"
+	SBOMFormatSPDX      SBOMFormat = "spdx"
+)
+
+// IsValid reports whether the format is a supported SBOM format
+func (f SBOMFormat) IsValid() bool {
+	return f == SBOMFormatCycloneDX || f == SBOMFormatSPDX
+}
+
+// ContentType returns the media type of an SBOM document in the format
+func (f SBOMFormat) ContentType() string {
+	if f == SBOMFormatSPDX {
+		return "application/spdx+json"
+	}
+	return "application/vnd.cyclonedx+json"
+}
+
+// FileExtension returns the conventional file extension of an SBOM document in the format
+func (f SBOMFormat) FileExtension() string {
+	if f == SBOMFormatSPDX {
+		return ".spdx.json"
+	}
+	return ".cdx.json"
+}
+
+// DeploymentSBOM is the software bill of materials of a deployment's artifact
+type DeploymentSBOM struct {
+	ID             string     `json:"id" db:"id"`
+	DeploymentID   string     `json:"deploymentId" db:"deployment_id"`
+	OrganizationID string     `json:"organizationId" db:"organization_id"`
+	Format         SBOMFormat `json:"format" db:"format"`
+	Artifact       string     `json:"artifact" db:"artifact"`
+	Document       []byte     `json:"-" db:"document"`
+	GeneratedAt    time.Time  `json:"generatedAt" db:"generated_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// Search result types
+const (
+	SearchTypeInfrastructure = "infrastructure"
+	SearchTypeDeployment     = "deployment"
+	SearchTypeAlert          = "alert"
+)
+
+// SearchResult is a single resource matching a search query
+type SearchResult struct {
+	Type      string    `json:"type"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Subtitle  string    `json:"subtitle"`
+	Status    string    `json:"status"`
+	Link      string    `json:"link"`
+	Rank      float64   `json:"rank"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SearchResponse groups search results by type, best matches first
+type SearchResponse struct {
+	Query   string                     `json:"query"`
+	Results map[string][]*SearchResult `json:"results"`
+	Total   int                        `json:"total"`
+}
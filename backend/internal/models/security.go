@@ -15,16 +15,44 @@ const (
 	VulnSeverityInfo     VulnerabilitySeverity = "info"
 )
 
+// IsValid reports whether the severity is a known severity level
+func (s VulnerabilitySeverity) IsValid() bool {
+	switch s {
+	case VulnSeverityCritical, VulnSeverityHigh, VulnSeverityMedium, VulnSeverityLow, VulnSeverityInfo:
+		return true
+	}
+	return false
+}
+
 // VulnerabilityStatus represents the status of a vulnerability
 type VulnerabilityStatus string
 
 const (
-	VulnStatusOpen       VulnerabilityStatus = "open"
-	VulnStatusInProgress VulnerabilityStatus = "in_progress"
-	VulnStatusResolved   VulnerabilityStatus = "resolved"
-	VulnStatusIgnored    VulnerabilityStatus = "ignored"
+	VulnStatusOpen          VulnerabilityStatus = "open"
+	VulnStatusAcknowledged  VulnerabilityStatus = "acknowledged"
+	VulnStatusInProgress    VulnerabilityStatus = "in_progress"
+	VulnStatusFixed         VulnerabilityStatus = "fixed"
+	VulnStatusFalsePositive VulnerabilityStatus = "false_positive"
+	VulnStatusResolved      VulnerabilityStatus = "resolved"
+	VulnStatusIgnored       VulnerabilityStatus = "ignored"
 )
 
+// IsValid reports whether the status is a known vulnerability status
+func (s VulnerabilityStatus) IsValid() bool {
+	switch s {
+	case VulnStatusOpen, VulnStatusAcknowledged, VulnStatusInProgress, VulnStatusFixed,
+		VulnStatusFalsePositive, VulnStatusResolved, VulnStatusIgnored:
+		return true
+	}
+	return false
+}
+
+// IsActive reports whether a finding with the status still needs attention. Scans resolve active
+// findings they no longer find, and reopen fixed or resolved findings they find again.
+func (s VulnerabilityStatus) IsActive() bool {
+	return s == VulnStatusOpen || s == VulnStatusAcknowledged || s == VulnStatusInProgress
+}
+
 // ScanType represents the type of security scan
 type ScanType string
 
@@ -41,17 +69,20 @@ type ScanStatus string
 
 const (
 	ScanStatusPending   ScanStatus = "pending"
+	ScanStatusQueued    ScanStatus = "queued"
 	ScanStatusRunning   ScanStatus = "running"
 	ScanStatusCompleted ScanStatus = "completed"
 	ScanStatusFailed    ScanStatus = "failed"
 	ScanStatusCancelled ScanStatus = "cancelled"
 )
 
-// Vulnerability represents a security vulnerability
+// Vulnerability represents a security vulnerability. A finding is tracked across scans by its
+// fingerprint: ScanID is the latest scan that found it and ScanCount how many scans have.
 type Vulnerability struct {
 	ID             string                `json:"id" db:"id"`
 	OrganizationID string                `json:"organizationId" db:"organization_id"`
 	ScanID         string                `json:"scanId" db:"scan_id"`
+	Fingerprint    string                `json:"fingerprint" db:"fingerprint"`
 	Title          string                `json:"title" db:"title"`
 	Description    string                `json:"description" db:"description"`
 	Severity       VulnerabilitySeverity `json:"severity" db:"severity"`
@@ -61,11 +92,13 @@ type Vulnerability struct {
 	ResourceType   string                `json:"resourceType" db:"resource_type"`
 	ResourceID     string                `json:"resourceId" db:"resource_id"`
 	ResourceName   string                `json:"resourceName" db:"resource_name"`
+	Location       string                `json:"location" db:"location"`
 	Recommendation string                `json:"recommendation" db:"recommendation"`
 	References     []string              `json:"references" db:"reference_links"`
 	Tags           []string              `json:"tags" db:"tags"`
 	FirstDetected  time.Time             `json:"firstDetected" db:"first_detected"`
 	LastSeen       time.Time             `json:"lastSeen" db:"last_seen"`
+	ScanCount      int                   `json:"scanCount" db:"scan_count"`
 	ResolvedAt     *time.Time            `json:"resolvedAt" db:"resolved_at"`
 	CreatedAt      time.Time             `json:"createdAt" db:"created_at"`
 	UpdatedAt      time.Time             `json:"updatedAt" db:"updated_at"`
@@ -112,9 +145,24 @@ type CreateScanRequest struct {
 	Configuration map[string]interface{} `json:"configuration,omitempty"`
 }
 
+// VulnerabilityStatusChange records a finding's move from one status to another, by a user or
+// by a scan
+type VulnerabilityStatusChange struct {
+	ID              string               `json:"id" db:"id"`
+	VulnerabilityID string               `json:"vulnerabilityId" db:"vulnerability_id"`
+	OrganizationID  string               `json:"organizationId" db:"organization_id"`
+	FromStatus      *VulnerabilityStatus `json:"fromStatus" db:"from_status"`
+	ToStatus        VulnerabilityStatus  `json:"toStatus" db:"to_status"`
+	ChangedBy       *string              `json:"changedBy" db:"changed_by"`
+	ScanID          *string              `json:"scanId" db:"scan_id"`
+	Reason          string               `json:"reason" db:"reason"`
+	ChangedAt       time.Time            `json:"changedAt" db:"changed_at"`
+}
+
 // UpdateVulnerabilityRequest represents a request to update a vulnerability
 type UpdateVulnerabilityRequest struct {
 	Status         VulnerabilityStatus `json:"status" binding:"required"`
+	Reason         string              `json:"reason,omitempty" binding:"max=1000"`
 	Recommendation *string             `json:"recommendation,omitempty"`
 	Tags           []string            `json:"tags,omitempty"`
 }
@@ -124,6 +172,7 @@ type VulnerabilityQuery struct {
 	ScanID       *string                `json:"scanId,omitempty"`
 	Severity     *VulnerabilitySeverity `json:"severity,omitempty"`
 	Status       *VulnerabilityStatus   `json:"status,omitempty"`
+	Statuses     []VulnerabilityStatus  `json:"statuses,omitempty"`
 	ResourceType *string                `json:"resourceType,omitempty"`
 	ResourceID   *string                `json:"resourceId,omitempty"`
 	CVEID        *string                `json:"cveId,omitempty"`
@@ -133,6 +182,17 @@ type VulnerabilityQuery struct {
 	Offset       int                    `json:"offset,omitempty"`
 }
 
+// ScanFindings is a page of the vulnerabilities a security scan found, with the number of
+// findings of each severity across all pages
+type ScanFindings struct {
+	Scan           *SecurityScan                 `json:"scan"`
+	Findings       []*Vulnerability              `json:"findings"`
+	Total          int                           `json:"total"`
+	Limit          int                           `json:"limit"`
+	Offset         int                           `json:"offset"`
+	SeverityCounts map[VulnerabilitySeverity]int `json:"severityCounts"`
+}
+
 // SecurityMetrics represents security-related metrics
 type SecurityMetrics struct {
 	TotalVulnerabilities      int                           `json:"totalVulnerabilities"`
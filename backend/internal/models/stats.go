@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// StatSnapshot is the value of an organization statistic on a given day
+type StatSnapshot struct {
+	OrganizationID string    `json:"organizationId" db:"organization_id"`
+	Metric         string    `json:"metric" db:"metric"`
+	Value          float64   `json:"value" db:"value"`
+	CapturedOn     time.Time `json:"capturedOn" db:"captured_on"`
+}
@@ -14,6 +14,7 @@ type User struct {
 	OnboardingCompleted   bool       `json:"onboardingCompleted" db:"onboarding_completed"`
 	DemoMode              bool       `json:"demoMode" db:"demo_mode"`
 	DemoScenario          string     `json:"demoScenario" db:"demo_scenario"`
+	EmailVerified         bool       `json:"emailVerified" db:"email_verified"`
 	CreatedAt             time.Time  `json:"createdAt" db:"created_at"`
 	UpdatedAt             time.Time  `json:"updatedAt" db:"updated_at"`
 	LastLoginAt           *time.Time `json:"lastLoginAt" db:"last_login_at"`
@@ -23,10 +24,9 @@ type User struct {
 	SSOSubject  *string `json:"ssoSubject" db:"sso_subject"`
 
 	// Virtual fields for compatibility
-	Role          string                 `json:"role" db:"-"`
-	Preferences   map[string]interface{} `json:"preferences" db:"-"`
-	AvatarURL     *string                `json:"avatarUrl" db:"-"`
-	EmailVerified bool                   `json:"emailVerified" db:"-"`
+	Role        string                 `json:"role" db:"-"`
+	Preferences map[string]interface{} `json:"preferences" db:"-"`
+	AvatarURL   *string                `json:"avatarUrl" db:"-"`
 }
 
 type LoginRequest struct {
@@ -36,8 +36,8 @@ type LoginRequest struct {
 
 type RegisterRequest struct {
 	Email           string `json:"email" binding:"required,email"`
-	Password        string `json:"password" binding:"required,min=8"`
-	ConfirmPassword string `json:"confirmPassword" binding:"required,min=8"`
+	Password        string `json:"password" binding:"required"`
+	ConfirmPassword string `json:"confirmPassword" binding:"required"`
 	Name            string `json:"name" binding:"required,min=2"`
 	OrganizationID  string `json:"organizationId,omitempty"` // Optional - will create if not provided
 	CompanyName     string `json:"companyName,omitempty"`    // Used to create organization if not provided
@@ -46,8 +46,12 @@ type RegisterRequest struct {
 type LoginResponse struct {
 	Success      bool   `json:"success"`
 	User         User   `json:"user"`
-	Token        string `json:"token"`
-	RefreshToken string `json:"refreshToken"`
+	Token        string `json:"token,omitempty"`
+	RefreshToken string `json:"refreshToken,omitempty"`
+
+	// Set instead of the tokens when the user must complete a second factor
+	MFARequired bool   `json:"mfaRequired,omitempty"`
+	MFAToken    string `json:"mfaToken,omitempty"`
 }
 
 type RegisterResponse struct {
@@ -67,16 +71,43 @@ type RefreshTokenResponse struct {
 	RefreshToken string `json:"refreshToken"`
 }
 
+// MFA Models
+type UserMFA struct {
+	UserID      string   `json:"userId" db:"id"`
+	Enabled     bool     `json:"enabled" db:"mfa_enabled"`
+	Secret      string   `json:"-" db:"mfa_secret"` // Encrypted TOTP secret
+	BackupCodes []string `json:"-" db:"mfa_backup_codes"` // Hashed backup codes
+
+	// Consecutive invalid codes, and when verification unlocks after too many
+	FailedAttempts int        `json:"-" db:"mfa_failed_attempts"`
+	LockedUntil    *time.Time `json:"-" db:"mfa_locked_until"`
+}
+
+type MFAEnrollResponse struct {
+	Secret          string   `json:"secret"`
+	ProvisioningURI string   `json:"provisioningUri"`
+	BackupCodes     []string `json:"backupCodes"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFAVerifyLoginRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
 // SSO Models
 type SSOLoginRequest struct {
-	Provider string `json:"provider" binding:"required"`
-	State    string `json:"state,omitempty"`
+	Provider       string `json:"provider" binding:"required"`
+	OrganizationID string `json:"organizationId,omitempty"`
 }
 
 type SSOCallbackRequest struct {
 	Provider string `json:"provider" binding:"required"`
 	Code     string `json:"code" binding:"required"`
-	State    string `json:"state,omitempty"`
+	State    string `json:"state" binding:"required"`
 }
 
 type SSOUserInfo struct {
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook is an organization-registered endpoint that resource lifecycle events are posted to
+type Webhook struct {
+	ID             string    `json:"id" db:"id"`
+	OrganizationID string    `json:"organizationId" db:"organization_id"`
+	URL            string    `json:"url" db:"url"`
+	Secret         string    `json:"-" db:"secret"`
+	Events         []string  `json:"events" db:"events"`
+	Enabled        bool      `json:"enabled" db:"enabled"`
+	CreatedBy      *string   `json:"createdBy" db:"created_by"`
+	CreatedAt      time.Time `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time `json:"updatedAt" db:"updated_at"`
+}
+
+// Webhook event types
+const (
+	WebhookEventInfrastructureCreated = "infrastructure.created"
+	WebhookEventInfrastructureUpdated = "infrastructure.updated"
+	WebhookEventInfrastructureDeleted = "infrastructure.deleted"
+	WebhookEventDeploymentCompleted   = "deployment.completed"
+	WebhookEventDeploymentFailed      = "deployment.failed"
+	WebhookEventDeploymentCancelled   = "deployment.cancelled"
+	WebhookEventDeploymentRolledBack  = "deployment.rolled_back"
+	WebhookEventTest                  = "webhook.test"
+)
+
+// WebhookEvents are the event types a webhook can subscribe to
+var WebhookEvents = []string{
+	WebhookEventInfrastructureCreated,
+	WebhookEventInfrastructureUpdated,
+	WebhookEventInfrastructureDeleted,
+	WebhookEventDeploymentCompleted,
+	WebhookEventDeploymentFailed,
+	WebhookEventDeploymentCancelled,
+	WebhookEventDeploymentRolledBack,
+}
+
+// AcceptsEvent reports whether events of a type are posted to the webhook. A webhook without
+// events receives every event.
+func (w *Webhook) AcceptsEvent(eventType string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEvent is the body posted to a webhook
+type WebhookEvent struct {
+	ID             string      `json:"id"`
+	Type           string      `json:"type"`
+	OrganizationID string      `json:"organizationId"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	Data           interface{} `json:"data"`
+}
+
+// WebhookDelivery records the delivery of an event to a webhook
+type WebhookDelivery struct {
+	ID             string          `json:"id" db:"id"`
+	OrganizationID string          `json:"organizationId" db:"organization_id"`
+	WebhookID      string          `json:"webhookId" db:"webhook_id"`
+	EventID        string          `json:"eventId" db:"event_id"`
+	EventType      string          `json:"eventType" db:"event_type"`
+	Payload        json.RawMessage `json:"payload" db:"payload"`
+	Status         string          `json:"status" db:"status"`
+	Attempts       int             `json:"attempts" db:"attempts"`
+	ResponseStatus *int            `json:"responseStatus" db:"response_status"`
+	LastError      *string         `json:"lastError" db:"last_error"`
+	DeliveredAt    *time.Time      `json:"deliveredAt" db:"delivered_at"`
+	CreatedAt      time.Time       `json:"createdAt" db:"created_at"`
+	UpdatedAt      time.Time       `json:"updatedAt" db:"updated_at"`
+}
+
+// Webhook delivery statuses. Deliveries still failing after the last attempt become dead letters.
+const (
+	WebhookDeliveryPending    = "pending"
+	WebhookDeliveryDelivered  = "delivered"
+	WebhookDeliveryDeadLetter = "dead_letter"
+)
+
+// CreateWebhookRequest represents a request to register a webhook. A signing secret is generated
+// when none is given.
+type CreateWebhookRequest struct {
+	URL     string   `json:"url" binding:"required,url"`
+	Secret  string   `json:"secret,omitempty" binding:"omitempty,min=16,max=255"`
+	Events  []string `json:"events,omitempty"`
+	Enabled *bool    `json:"enabled,omitempty"`
+}
+
+// WebhookTestResult reports the outcome of posting a test event to a webhook
+type WebhookTestResult struct {
+	Delivered      bool    `json:"delivered"`
+	ResponseStatus *int    `json:"responseStatus,omitempty"`
+	Error          *string `json:"error,omitempty"`
+	DurationMs     int64   `json:"durationMs"`
+}
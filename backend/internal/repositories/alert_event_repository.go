@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"cloudweave/internal/models"
+)
+
+// AlertEventRepository handles alert event data operations
+type AlertEventRepository struct {
+	db *sql.DB
+}
+
+// NewAlertEventRepository creates a new alert event repository
+func NewAlertEventRepository(db *sql.DB) *AlertEventRepository {
+	return &AlertEventRepository{db: db}
+}
+
+// Create records a new alert event
+func (r *AlertEventRepository) Create(ctx context.Context, event *models.AlertEvent) error {
+	query := `
+		INSERT INTO alert_events (id, alert_id, organization_id, event_type, actor_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		event.ID, event.AlertID, event.OrganizationID, event.EventType, event.ActorID,
+	).Scan(&event.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create alert event: %w", err)
+	}
+
+	return nil
+}
+
+// CreateTx records a new alert event within a transaction
+func (r *AlertEventRepository) CreateTx(ctx context.Context, tx *sql.Tx, event *models.AlertEvent) error {
+	query := `
+		INSERT INTO alert_events (id, alert_id, organization_id, event_type, actor_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	err := tx.QueryRowContext(ctx, query,
+		event.ID, event.AlertID, event.OrganizationID, event.EventType, event.ActorID,
+	).Scan(&event.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create alert event: %w", err)
+	}
+
+	return nil
+}
+
+// ListByAlert retrieves an alert's events, oldest first
+func (r *AlertEventRepository) ListByAlert(ctx context.Context, alertID string) ([]*models.AlertEvent, error) {
+	query := `
+		SELECT id, alert_id, organization_id, event_type, actor_id, created_at
+		FROM alert_events
+		WHERE alert_id = $1
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*models.AlertEvent{}
+	for rows.Next() {
+		event := &models.AlertEvent{}
+		err := rows.Scan(
+			&event.ID,
+			&event.AlertID,
+			&event.OrganizationID,
+			&event.EventType,
+			&event.ActorID,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert event row: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert event rows: %w", err)
+	}
+
+	return events, nil
+}
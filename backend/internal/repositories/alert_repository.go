@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"cloudweave/internal/models"
 
@@ -13,6 +14,9 @@ import (
 
 type AlertRepository struct {
 	db *sql.DB
+
+	// replica serves list and query reads when set; see readDB
+	replica *sql.DB
 }
 
 func NewAlertRepository(db *sql.DB) *AlertRepository {
@@ -196,7 +200,7 @@ func (r *AlertRepository) List(ctx context.Context, orgID string, params ListPar
 
 	args = append(args, params.Limit, params.Offset)
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list alerts: %w", err)
 	}
@@ -304,7 +308,7 @@ func (r *AlertRepository) Query(ctx context.Context, orgID string, query models.
 
 	args = append(args, limit, offset)
 
-	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query alerts: %w", err)
 	}
@@ -337,6 +341,65 @@ func (r *AlertRepository) Query(ctx context.Context, orgID string, query models.
 	return alerts, nil
 }
 
+// ListForUpdateTx retrieves an organization's alerts by ID within a transaction, locking them
+// until it ends. IDs of alerts that do not exist or belong to another organization are left out.
+func (r *AlertRepository) ListForUpdateTx(ctx context.Context, tx *sql.Tx, orgID string, ids []string) ([]*models.Alert, error) {
+	query := `
+		SELECT id, organization_id, type, severity, title, message, resource_id, resource_type,
+		       acknowledged, acknowledged_by, acknowledged_at, created_at, updated_at
+		FROM alerts
+		WHERE organization_id = $1 AND id = ANY($2::uuid[])
+		FOR UPDATE`
+
+	rows, err := tx.QueryContext(ctx, query, orgID, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []*models.Alert
+	for rows.Next() {
+		alert := &models.Alert{}
+		err := rows.Scan(
+			&alert.ID,
+			&alert.OrganizationID,
+			&alert.Type,
+			&alert.Severity,
+			&alert.Title,
+			&alert.Message,
+			&alert.ResourceID,
+			&alert.ResourceType,
+			&alert.Acknowledged,
+			&alert.AcknowledgedBy,
+			&alert.AcknowledgedAt,
+			&alert.CreatedAt,
+			&alert.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert row: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// UpdateAcknowledgementTx saves an alert's acknowledgement within a transaction
+func (r *AlertRepository) UpdateAcknowledgementTx(ctx context.Context, tx *sql.Tx, alert *models.Alert) error {
+	query := `
+		UPDATE alerts
+		SET acknowledged = $2, acknowledged_by = $3, acknowledged_at = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := tx.QueryRowContext(ctx, query, alert.ID, alert.Acknowledged, alert.AcknowledgedBy, alert.AcknowledgedAt).Scan(&alert.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update alert %s: %w", alert.ID, err)
+	}
+
+	return nil
+}
+
 // Acknowledge acknowledges an alert
 func (r *AlertRepository) Acknowledge(ctx context.Context, id, userID string) error {
 	query := `
@@ -373,7 +436,7 @@ func (r *AlertRepository) ListUnacknowledged(ctx context.Context, orgID string,
 		ORDER BY severity DESC, created_at DESC
 		LIMIT $2 OFFSET $3`
 
-	rows, err := r.db.QueryContext(ctx, query, orgID, params.Limit, params.Offset)
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, orgID, params.Limit, params.Offset)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list unacknowledged alerts: %w", err)
 	}
@@ -405,3 +468,37 @@ func (r *AlertRepository) ListUnacknowledged(ctx context.Context, orgID string,
 
 	return alerts, nil
 }
+
+// ListEnvironmentOutages retrieves the error and critical deployment alerts that were open at
+// any point since the given time, along with the environment of the deployment they were raised on
+func (r *AlertRepository) ListEnvironmentOutages(ctx context.Context, orgID string, since time.Time) ([]*models.EnvironmentOutage, error) {
+	query := `
+		SELECT d.environment, a.created_at, a.acknowledged_at
+		FROM alerts a
+		JOIN deployments d ON d.id = a.resource_id
+		WHERE a.organization_id = $1 AND a.resource_type = 'deployment'
+		AND a.severity IN ('error', 'critical')
+		AND (a.acknowledged_at IS NULL OR a.acknowledged_at > $2)
+		ORDER BY d.environment, a.created_at`
+
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, orgID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environment outages: %w", err)
+	}
+	defer rows.Close()
+
+	var outages []*models.EnvironmentOutage
+	for rows.Next() {
+		outage := &models.EnvironmentOutage{}
+		if err := rows.Scan(&outage.Environment, &outage.StartedAt, &outage.EndedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan environment outage: %w", err)
+		}
+		outages = append(outages, outage)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating environment outage rows: %w", err)
+	}
+
+	return outages, nil
+}
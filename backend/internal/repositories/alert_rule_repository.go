@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"cloudweave/internal/models"
+)
+
+// AlertRuleRepository handles alert rule data operations
+type AlertRuleRepository struct {
+	db *sql.DB
+}
+
+// NewAlertRuleRepository creates a new alert rule repository
+func NewAlertRuleRepository(db *sql.DB) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db}
+}
+
+const alertRuleColumns = `id, organization_id, name, description, resource_type, metric_name, operator,
+		       threshold, duration_seconds, severity, enabled, created_by, created_at, updated_at`
+
+// Create creates a new alert rule
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *models.AlertRule) error {
+	query := `
+		INSERT INTO alert_rules (id, organization_id, name, description, resource_type, metric_name,
+		                         operator, threshold, duration_seconds, severity, enabled, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		rule.ID, rule.OrganizationID, rule.Name, rule.Description, rule.ResourceType, rule.MetricName,
+		rule.Operator, rule.Threshold, rule.DurationSeconds, rule.Severity, rule.Enabled, rule.CreatedBy,
+	).Scan(&rule.CreatedAt, &rule.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create alert rule: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves an organization's alert rules
+func (r *AlertRuleRepository) List(ctx context.Context, orgID string) ([]*models.AlertRule, error) {
+	query := `SELECT ` + alertRuleColumns + ` FROM alert_rules WHERE organization_id = $1 ORDER BY name ASC`
+
+	return r.query(ctx, query, orgID)
+}
+
+// ListEnabledForResource retrieves the enabled rules that apply to a resource type. Rules without
+// a resource type apply to every resource.
+func (r *AlertRuleRepository) ListEnabledForResource(ctx context.Context, orgID, resourceType string) ([]*models.AlertRule, error) {
+	query := `
+		SELECT ` + alertRuleColumns + `
+		FROM alert_rules
+		WHERE organization_id = $1 AND enabled = true AND (resource_type = '' OR resource_type = $2)`
+
+	return r.query(ctx, query, orgID, resourceType)
+}
+
+// Delete deletes an alert rule within an organization
+func (r *AlertRuleRepository) Delete(ctx context.Context, id, orgID string) error {
+	query := `DELETE FROM alert_rules WHERE id = $1 AND organization_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete alert rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("alert rule with id %s not found", id)
+	}
+
+	return nil
+}
+
+// GetState retrieves the breach state of a rule for a resource, or nil if the rule is not breached
+func (r *AlertRuleRepository) GetState(ctx context.Context, ruleID, resourceID string) (*models.AlertRuleState, error) {
+	query := `
+		SELECT rule_id, resource_id, breached_since, alert_id
+		FROM alert_rule_states
+		WHERE rule_id = $1 AND resource_id = $2`
+
+	state := &models.AlertRuleState{}
+	err := r.db.QueryRowContext(ctx, query, ruleID, resourceID).Scan(
+		&state.RuleID,
+		&state.ResourceID,
+		&state.BreachedSince,
+		&state.AlertID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get alert rule state: %w", err)
+	}
+
+	return state, nil
+}
+
+// SaveState creates or updates the breach state of a rule for a resource
+func (r *AlertRuleRepository) SaveState(ctx context.Context, state *models.AlertRuleState) error {
+	query := `
+		INSERT INTO alert_rule_states (rule_id, resource_id, breached_since, alert_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (rule_id, resource_id) DO UPDATE
+		SET breached_since = EXCLUDED.breached_since, alert_id = EXCLUDED.alert_id`
+
+	_, err := r.db.ExecContext(ctx, query, state.RuleID, state.ResourceID, state.BreachedSince, state.AlertID)
+	if err != nil {
+		return fmt.Errorf("failed to save alert rule state: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteState clears the breach state of a rule for a resource once it recovers
+func (r *AlertRuleRepository) DeleteState(ctx context.Context, ruleID, resourceID string) error {
+	query := `DELETE FROM alert_rule_states WHERE rule_id = $1 AND resource_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, ruleID, resourceID); err != nil {
+		return fmt.Errorf("failed to delete alert rule state: %w", err)
+	}
+
+	return nil
+}
+
+// query runs a query selecting alertRuleColumns and scans the results
+func (r *AlertRuleRepository) query(ctx context.Context, query string, args ...interface{}) ([]*models.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []*models.AlertRule
+	for rows.Next() {
+		rule := &models.AlertRule{}
+		err := rows.Scan(
+			&rule.ID,
+			&rule.OrganizationID,
+			&rule.Name,
+			&rule.Description,
+			&rule.ResourceType,
+			&rule.MetricName,
+			&rule.Operator,
+			&rule.Threshold,
+			&rule.DurationSeconds,
+			&rule.Severity,
+			&rule.Enabled,
+			&rule.CreatedBy,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan alert rule row: %w", err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating alert rule rows: %w", err)
+	}
+
+	return rules, nil
+}
@@ -0,0 +1,207 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"cloudweave/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// APIKeyRepository handles API key data operations
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+const apiKeyColumns = `id, user_id, organization_id, name, COALESCE(description, ''), key_hash, key_prefix,
+		       permissions, scopes, rate_limit, is_active, last_used_at, expires_at, created_at, updated_at, metadata`
+
+// Create creates a new API key
+func (r *APIKeyRepository) Create(ctx context.Context, apiKey *models.APIKey) error {
+	metadataJSON, err := json.Marshal(apiKey.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO api_keys (id, user_id, organization_id, name, description, key_hash, key_prefix,
+		                      permissions, scopes, rate_limit, is_active, expires_at, created_at, updated_at, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		apiKey.ID, apiKey.UserID, apiKey.OrganizationID, apiKey.Name, apiKey.Description,
+		apiKey.KeyHash, apiKey.KeyPrefix, pq.Array(apiKey.Permissions), pq.Array(apiKey.Scopes),
+		apiKey.RateLimit, apiKey.IsActive, apiKey.ExpiresAt, apiKey.CreatedAt, apiKey.UpdatedAt, metadataJSON)
+
+	if err != nil {
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an API key by ID
+func (r *APIKeyRepository) GetByID(ctx context.Context, keyID, organizationID string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE id = $1 AND organization_id = $2`
+
+	apiKey, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyID, organizationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("API key not found")
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+// GetByKeyHash retrieves an API key by the hash of the raw key
+func (r *APIKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	query := `SELECT ` + apiKeyColumns + ` FROM api_keys WHERE key_hash = $1`
+
+	apiKey, err := scanAPIKey(r.db.QueryRowContext(ctx, query, keyHash))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("API key not found")
+		}
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+// List retrieves a user's API keys with pagination
+func (r *APIKeyRepository) List(ctx context.Context, userID, organizationID string, limit, offset int) ([]*models.APIKey, int, error) {
+	// Get total count
+	countQuery := `SELECT COUNT(*) FROM api_keys WHERE user_id = $1 AND organization_id = $2`
+	var total int
+	err := r.db.QueryRowContext(ctx, countQuery, userID, organizationID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get total count: %w", err)
+	}
+
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE user_id = $1 AND organization_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, organizationID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	var apiKeys []*models.APIKey
+	for rows.Next() {
+		apiKey, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		apiKeys = append(apiKeys, apiKey)
+	}
+
+	return apiKeys, total, nil
+}
+
+// Update updates an API key
+func (r *APIKeyRepository) Update(ctx context.Context, apiKey *models.APIKey) error {
+	metadataJSON, err := json.Marshal(apiKey.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+		UPDATE api_keys
+		SET name = $3, description = $4, permissions = $5, scopes = $6, rate_limit = $7,
+		    is_active = $8, expires_at = $9, metadata = $10, updated_at = NOW()
+		WHERE id = $1 AND organization_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query,
+		apiKey.ID, apiKey.OrganizationID, apiKey.Name, apiKey.Description,
+		pq.Array(apiKey.Permissions), pq.Array(apiKey.Scopes), apiKey.RateLimit,
+		apiKey.IsActive, apiKey.ExpiresAt, metadataJSON)
+	if err != nil {
+		return fmt.Errorf("failed to update API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("API key not found")
+	}
+
+	return nil
+}
+
+// Delete deletes an API key
+func (r *APIKeyRepository) Delete(ctx context.Context, keyID, organizationID string) error {
+	query := `DELETE FROM api_keys WHERE id = $1 AND organization_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, keyID, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to delete API key: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("API key not found")
+	}
+
+	return nil
+}
+
+// UpdateLastUsed records that an API key was just used
+func (r *APIKeyRepository) UpdateLastUsed(ctx context.Context, keyID string) error {
+	query := `UPDATE api_keys SET last_used_at = NOW() WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, keyID); err != nil {
+		return fmt.Errorf("failed to update API key last used: %w", err)
+	}
+
+	return nil
+}
+
+// apiKeyScanner is satisfied by both *sql.Row and *sql.Rows
+type apiKeyScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanAPIKey scans a row selected with apiKeyColumns
+func scanAPIKey(row apiKeyScanner) (*models.APIKey, error) {
+	var apiKey models.APIKey
+	var metadataJSON []byte
+
+	err := row.Scan(
+		&apiKey.ID, &apiKey.UserID, &apiKey.OrganizationID, &apiKey.Name, &apiKey.Description,
+		&apiKey.KeyHash, &apiKey.KeyPrefix, pq.Array(&apiKey.Permissions), pq.Array(&apiKey.Scopes),
+		&apiKey.RateLimit, &apiKey.IsActive, &apiKey.LastUsedAt, &apiKey.ExpiresAt, &apiKey.CreatedAt, &apiKey.UpdatedAt,
+		&metadataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &apiKey.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+
+	return &apiKey, nil
+}
@@ -3,6 +3,8 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,6 +14,10 @@ import (
 	"github.com/lib/pq"
 )
 
+// ErrInvalidAuditLog is returned when audit log entries can never be written, such as when one
+// references a missing organization or holds a value its column rejects
+var ErrInvalidAuditLog = errors.New("invalid audit log")
+
 type AuditLogRepository struct {
 	db *sql.DB
 }
@@ -20,24 +26,51 @@ func NewAuditLogRepository(db *sql.DB) *AuditLogRepository {
 	return &AuditLogRepository{db: db}
 }
 
+// auditLogValues converts the details and IP address of an audit log to values the database
+// driver can write
+func auditLogValues(log *models.AuditLog) ([]byte, *string, error) {
+	var details []byte
+	if log.Details != nil {
+		var err error
+		details, err = json.Marshal(log.Details)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: failed to marshal details: %v", ErrInvalidAuditLog, err)
+		}
+	}
+
+	var ipAddress *string
+	if log.IPAddress != nil {
+		ip := log.IPAddress.String()
+		ipAddress = &ip
+	}
+
+	return details, ipAddress, nil
+}
+
 // Create creates a new audit log entry in the database
 func (r *AuditLogRepository) Create(ctx context.Context, log *models.AuditLog) error {
+	details, ipAddress, err := auditLogValues(log)
+	if err != nil {
+		return err
+	}
+
 	query := `
 		INSERT INTO audit_logs (id, organization_id, user_id, action, resource_type, 
-		                       resource_id, details, ip_address, user_agent)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		                       resource_id, details, ip_address, user_agent, impersonated_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING created_at`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err = r.db.QueryRowContext(ctx, query,
 		log.ID,
 		log.OrganizationID,
 		log.UserID,
 		log.Action,
 		log.ResourceType,
 		log.ResourceID,
-		log.Details,
-		log.IPAddress,
+		details,
+		ipAddress,
 		log.UserAgent,
+		log.ImpersonatedBy,
 	).Scan(&log.CreatedAt)
 
 	if err != nil {
@@ -53,12 +86,63 @@ func (r *AuditLogRepository) Create(ctx context.Context, log *models.AuditLog) e
 	return nil
 }
 
+// CreateBatch inserts several audit log entries with a single statement
+func (r *AuditLogRepository) CreateBatch(ctx context.Context, logs []*models.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+
+	var values strings.Builder
+	args := make([]interface{}, 0, len(logs)*11)
+	for i, log := range logs {
+		details, ipAddress, err := auditLogValues(log)
+		if err != nil {
+			return err
+		}
+
+		if i > 0 {
+			values.WriteString(", ")
+		}
+		base := i * 11
+		values.WriteString(fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8, base+9, base+10, base+11))
+		args = append(args,
+			log.ID,
+			log.OrganizationID,
+			log.UserID,
+			log.Action,
+			log.ResourceType,
+			log.ResourceID,
+			details,
+			ipAddress,
+			log.UserAgent,
+			log.ImpersonatedBy,
+			log.CreatedAt,
+		)
+	}
+
+	query := `
+		INSERT INTO audit_logs (id, organization_id, user_id, action, resource_type,
+		                       resource_id, details, ip_address, user_agent, impersonated_by, created_at)
+		VALUES ` + values.String()
+
+	if _, err := r.db.ExecContext(ctx, query, args...); err != nil {
+		// Data exceptions and constraint violations fail the same way however often they are retried
+		if pqErr, ok := err.(*pq.Error); ok && (pqErr.Code.Class() == "22" || pqErr.Code.Class() == "23") {
+			return fmt.Errorf("%w: %v", ErrInvalidAuditLog, err)
+		}
+		return fmt.Errorf("failed to create audit logs: %w", err)
+	}
+
+	return nil
+}
+
 // GetByID retrieves an audit log entry by its ID
 func (r *AuditLogRepository) GetByID(ctx context.Context, id string) (*models.AuditLog, error) {
 	log := &models.AuditLog{}
 	query := `
 		SELECT id, organization_id, user_id, action, resource_type, resource_id, 
-		       details, ip_address, user_agent, created_at
+		       details, ip_address, user_agent, impersonated_by, created_at
 		FROM audit_logs 
 		WHERE id = $1`
 
@@ -72,6 +156,7 @@ func (r *AuditLogRepository) GetByID(ctx context.Context, id string) (*models.Au
 		&log.Details,
 		&log.IPAddress,
 		&log.UserAgent,
+		&log.ImpersonatedBy,
 		&log.CreatedAt,
 	)
 
@@ -85,8 +170,9 @@ func (r *AuditLogRepository) GetByID(ctx context.Context, id string) (*models.Au
 	return log, nil
 }
 
-// Query retrieves audit logs based on query parameters
-func (r *AuditLogRepository) Query(ctx context.Context, orgID string, query models.AuditLogQuery) ([]*models.AuditLog, error) {
+// auditLogFilter builds the WHERE clause and arguments selecting an organization's audit logs that
+// match a query's filters and time range
+func auditLogFilter(orgID string, query models.AuditLogQuery) (string, []interface{}) {
 	var whereClause strings.Builder
 	var args []interface{}
 	argIndex := 1
@@ -128,6 +214,21 @@ func (r *AuditLogRepository) Query(ctx context.Context, orgID string, query mode
 		argIndex++
 	}
 
+	if query.ImpersonatedBy != nil {
+		whereClause.WriteString(" AND impersonated_by = $")
+		whereClause.WriteString(fmt.Sprintf("%d", argIndex))
+		args = append(args, *query.ImpersonatedBy)
+	}
+
+	return whereClause.String(), args
+}
+
+// Query retrieves audit logs based on query parameters, newest first unless the query sorts them
+// ascending
+func (r *AuditLogRepository) Query(ctx context.Context, orgID string, query models.AuditLogQuery) ([]*models.AuditLog, error) {
+	whereClause, args := auditLogFilter(orgID, query)
+	argIndex := len(args) + 1
+
 	// Set default values if not provided
 	limit := query.Limit
 	if limit <= 0 || limit > 10000 {
@@ -139,14 +240,38 @@ func (r *AuditLogRepository) Query(ctx context.Context, orgID string, query mode
 		offset = 0
 	}
 
+	// id breaks ties between logs created at the same time, so pages do not overlap
+	order := "DESC"
+	if query.Sort == models.AuditSortAsc {
+		order = "ASC"
+	}
+
+	// A cursor continues after its row in the sort order, so later pages are as cheap as the first
+	if query.Cursor != "" {
+		position, err := DecodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		comparison := "<"
+		if order == "ASC" {
+			comparison = ">"
+		}
+		whereClause += fmt.Sprintf(" AND (created_at, id) %s ($%d, $%d)", comparison, argIndex, argIndex+1)
+		args = append(args, position.Time, position.ID)
+		argIndex += 2
+		offset = 0
+	}
+
 	sqlQuery := fmt.Sprintf(`
 		SELECT id, organization_id, user_id, action, resource_type, resource_id, 
-		       details, ip_address, user_agent, created_at
+		       details, ip_address, user_agent, impersonated_by, created_at
 		FROM audit_logs 
 		%s
-		ORDER BY created_at DESC
+		ORDER BY created_at %s, id %s
 		LIMIT $%d OFFSET $%d`,
-		whereClause.String(),
+		whereClause,
+		order, order,
 		argIndex,
 		argIndex+1,
 	)
@@ -172,6 +297,7 @@ func (r *AuditLogRepository) Query(ctx context.Context, orgID string, query mode
 			&log.Details,
 			&log.IPAddress,
 			&log.UserAgent,
+			&log.ImpersonatedBy,
 			&log.CreatedAt,
 		)
 		if err != nil {
@@ -187,6 +313,20 @@ func (r *AuditLogRepository) Query(ctx context.Context, orgID string, query mode
 	return logs, nil
 }
 
+// Count returns how many audit logs match a query's filters and time range, ignoring its limit and
+// offset
+func (r *AuditLogRepository) Count(ctx context.Context, orgID string, query models.AuditLogQuery) (int, error) {
+	whereClause, args := auditLogFilter(orgID, query)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM audit_logs "+whereClause, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count audit logs: %w", err)
+	}
+
+	return count, nil
+}
+
 // Delete deletes an audit log entry by its ID
 func (r *AuditLogRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM audit_logs WHERE id = $1`
@@ -4,11 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"cloudweave/internal/models"
 )
 
+// ErrNoActiveCloudCredentials is returned when an organization has no active credentials for a
+// provider
+var ErrNoActiveCloudCredentials = errors.New("no active credentials found")
+
 type CloudCredentialsRepository struct {
 	db *sql.DB
 }
@@ -107,7 +112,7 @@ func (r *CloudCredentialsRepository) GetActiveByProvider(ctx context.Context, or
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("no active credentials found for provider %s", provider)
+			return nil, fmt.Errorf("%w for provider %s", ErrNoActiveCloudCredentials, provider)
 		}
 		return nil, fmt.Errorf("failed to get active credentials: %w", err)
 	}
@@ -0,0 +1,321 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ComplianceReportRepository handles compliance report data operations
+type ComplianceReportRepository struct {
+	db *sql.DB
+}
+
+// NewComplianceReportRepository creates a new compliance report repository
+func NewComplianceReportRepository(db *sql.DB) *ComplianceReportRepository {
+	return &ComplianceReportRepository{db: db}
+}
+
+// complianceReportColumns are the columns scanComplianceReport reads, in order
+const complianceReportColumns = `id, organization_id, user_id, schedule_id, name, description, type, frameworks, period,
+		status, data, generated_at, delivered_at, delivery_error, created_at, updated_at`
+
+// Create stores a compliance report
+func (r *ComplianceReportRepository) Create(ctx context.Context, report *models.ComplianceReport) error {
+	periodJSON, err := json.Marshal(report.Period)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report period: %w", err)
+	}
+	dataJSON, err := json.Marshal(report.Data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report data: %w", err)
+	}
+
+	frameworks := make([]string, len(report.Frameworks))
+	for i, framework := range report.Frameworks {
+		frameworks[i] = string(framework)
+	}
+
+	query := `
+		INSERT INTO compliance_reports (id, organization_id, user_id, schedule_id, name, description, type, frameworks,
+			period, status, data, generated_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)`
+
+	_, err = r.db.ExecContext(ctx, query,
+		report.ID, report.OrganizationID, report.UserID, report.ScheduleID, report.Name, report.Description,
+		report.Type, pq.Array(frameworks), periodJSON, report.Status, dataJSON, report.GeneratedAt,
+		report.CreatedAt, report.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create compliance report: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves one of an organization's compliance reports, or nil if it does not exist
+func (r *ComplianceReportRepository) GetByID(ctx context.Context, organizationID, reportID string) (*models.ComplianceReport, error) {
+	query := `SELECT ` + complianceReportColumns + ` FROM compliance_reports WHERE id = $1 AND organization_id = $2`
+
+	report, err := scanComplianceReport(r.db.QueryRowContext(ctx, query, reportID, organizationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get compliance report: %w", err)
+	}
+
+	return report, nil
+}
+
+// List retrieves an organization's compliance reports, newest first, without their data
+func (r *ComplianceReportRepository) List(ctx context.Context, organizationID string, limit, offset int) ([]*models.ComplianceReport, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM compliance_reports WHERE organization_id = $1`, organizationID).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count compliance reports: %w", err)
+	}
+
+	// Report data can be large and is only needed to render a report, so it is left out of lists
+	query := `
+		SELECT id, organization_id, user_id, schedule_id, name, description, type, frameworks, period,
+			status, NULL, generated_at, delivered_at, delivery_error, created_at, updated_at
+		FROM compliance_reports
+		WHERE organization_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, organizationID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list compliance reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*models.ComplianceReport
+	for rows.Next() {
+		report, err := scanComplianceReport(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan compliance report row: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating compliance report rows: %w", err)
+	}
+
+	return reports, total, nil
+}
+
+// UpdateDelivery records the outcome of emailing a report. A nil deliveryError means it was sent.
+func (r *ComplianceReportRepository) UpdateDelivery(ctx context.Context, reportID string, deliveredAt *time.Time, deliveryError *string) error {
+	query := `
+		UPDATE compliance_reports
+		SET delivered_at = $2, delivery_error = $3, updated_at = NOW()
+		WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, reportID, deliveredAt, deliveryError); err != nil {
+		return fmt.Errorf("failed to update compliance report delivery: %w", err)
+	}
+
+	return nil
+}
+
+// complianceReportScanner is satisfied by both *sql.Row and *sql.Rows
+type complianceReportScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanComplianceReport scans a row selected with complianceReportColumns
+func scanComplianceReport(row complianceReportScanner) (*models.ComplianceReport, error) {
+	report := &models.ComplianceReport{}
+	var frameworks []string
+	var periodJSON, dataJSON []byte
+
+	err := row.Scan(
+		&report.ID, &report.OrganizationID, &report.UserID, &report.ScheduleID, &report.Name, &report.Description,
+		&report.Type, pq.Array(&frameworks), &periodJSON, &report.Status, &dataJSON, &report.GeneratedAt,
+		&report.DeliveredAt, &report.DeliveryError, &report.CreatedAt, &report.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, framework := range frameworks {
+		report.Frameworks = append(report.Frameworks, models.ComplianceFramework(framework))
+	}
+	if err := json.Unmarshal(periodJSON, &report.Period); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal report period: %w", err)
+	}
+	if len(dataJSON) > 0 {
+		if err := json.Unmarshal(dataJSON, &report.Data); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal report data: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+// ComplianceReportScheduleRepository handles compliance report schedule data operations
+type ComplianceReportScheduleRepository struct {
+	db *sql.DB
+}
+
+// NewComplianceReportScheduleRepository creates a new compliance report schedule repository
+func NewComplianceReportScheduleRepository(db *sql.DB) *ComplianceReportScheduleRepository {
+	return &ComplianceReportScheduleRepository{db: db}
+}
+
+// complianceReportScheduleColumns are the columns scanComplianceReportSchedule reads, in order
+const complianceReportScheduleColumns = `id, organization_id, framework_id, frequency, recipients, enabled, next_run_at,
+		last_run_at, created_by, created_at, updated_at`
+
+// Create stores a compliance report schedule
+func (r *ComplianceReportScheduleRepository) Create(ctx context.Context, schedule *models.ComplianceReportSchedule) error {
+	query := `
+		INSERT INTO compliance_report_schedules (id, organization_id, framework_id, frequency, recipients, enabled,
+			next_run_at, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		schedule.ID, schedule.OrganizationID, schedule.FrameworkID, schedule.Frequency, pq.Array(schedule.Recipients),
+		schedule.Enabled, schedule.NextRunAt, schedule.CreatedBy, schedule.CreatedAt, schedule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create compliance report schedule: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves one of an organization's compliance report schedules, or nil if it does not exist
+func (r *ComplianceReportScheduleRepository) GetByID(ctx context.Context, organizationID, scheduleID string) (*models.ComplianceReportSchedule, error) {
+	query := `SELECT ` + complianceReportScheduleColumns + ` FROM compliance_report_schedules WHERE id = $1 AND organization_id = $2`
+
+	schedule, err := scanComplianceReportSchedule(r.db.QueryRowContext(ctx, query, scheduleID, organizationID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get compliance report schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+// List retrieves an organization's compliance report schedules
+func (r *ComplianceReportScheduleRepository) List(ctx context.Context, organizationID string) ([]*models.ComplianceReportSchedule, error) {
+	query := `
+		SELECT ` + complianceReportScheduleColumns + `
+		FROM compliance_report_schedules
+		WHERE organization_id = $1
+		ORDER BY created_at`
+
+	return r.list(ctx, query, organizationID)
+}
+
+// ListDue retrieves enabled schedules across all organizations whose next run is at or before a time
+func (r *ComplianceReportScheduleRepository) ListDue(ctx context.Context, now time.Time) ([]*models.ComplianceReportSchedule, error) {
+	query := `
+		SELECT ` + complianceReportScheduleColumns + `
+		FROM compliance_report_schedules
+		WHERE enabled AND next_run_at <= $1
+		ORDER BY next_run_at`
+
+	return r.list(ctx, query, now)
+}
+
+func (r *ComplianceReportScheduleRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.ComplianceReportSchedule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compliance report schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var schedules []*models.ComplianceReportSchedule
+	for rows.Next() {
+		schedule, err := scanComplianceReportSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan compliance report schedule row: %w", err)
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating compliance report schedule rows: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// Update updates a compliance report schedule's frequency, recipients, enabled flag and next run
+func (r *ComplianceReportScheduleRepository) Update(ctx context.Context, schedule *models.ComplianceReportSchedule) error {
+	query := `
+		UPDATE compliance_report_schedules
+		SET frequency = $3, recipients = $4, enabled = $5, next_run_at = $6, updated_at = $7
+		WHERE id = $1 AND organization_id = $2`
+
+	_, err := r.db.ExecContext(ctx, query,
+		schedule.ID, schedule.OrganizationID, schedule.Frequency, pq.Array(schedule.Recipients),
+		schedule.Enabled, schedule.NextRunAt, schedule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update compliance report schedule: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimRun moves a schedule's next run from the run it is due for to the following one. It
+// reports false when another server has already claimed the run, so each run happens once.
+func (r *ComplianceReportScheduleRepository) ClaimRun(ctx context.Context, scheduleID string, dueAt, nextRunAt time.Time) (bool, error) {
+	query := `
+		UPDATE compliance_report_schedules
+		SET next_run_at = $3, last_run_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND next_run_at = $2 AND enabled`
+
+	result, err := r.db.ExecContext(ctx, query, scheduleID, dueAt, nextRunAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim compliance report run: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to claim compliance report run: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// Delete deletes one of an organization's compliance report schedules. Reports it generated are kept.
+func (r *ComplianceReportScheduleRepository) Delete(ctx context.Context, organizationID, scheduleID string) error {
+	query := `DELETE FROM compliance_report_schedules WHERE id = $1 AND organization_id = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, scheduleID, organizationID); err != nil {
+		return fmt.Errorf("failed to delete compliance report schedule: %w", err)
+	}
+
+	return nil
+}
+
+// complianceReportScheduleScanner is satisfied by both *sql.Row and *sql.Rows
+type complianceReportScheduleScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanComplianceReportSchedule scans a row selected with complianceReportScheduleColumns
+func scanComplianceReportSchedule(row complianceReportScheduleScanner) (*models.ComplianceReportSchedule, error) {
+	schedule := &models.ComplianceReportSchedule{}
+	err := row.Scan(
+		&schedule.ID, &schedule.OrganizationID, &schedule.FrameworkID, &schedule.Frequency,
+		pq.Array(&schedule.Recipients), &schedule.Enabled, &schedule.NextRunAt, &schedule.LastRunAt,
+		&schedule.CreatedBy, &schedule.CreatedAt, &schedule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return schedule, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"cloudweave/internal/models"
 
@@ -170,6 +171,38 @@ func (r *ComplianceControlRepository) Create(ctx context.Context, control *model
 	return err
 }
 
+// CreateManyTx inserts controls within a transaction, skipping any whose control ID already exists
+// in the framework. It returns the number of controls inserted.
+func (r *ComplianceControlRepository) CreateManyTx(ctx context.Context, tx *sql.Tx, controls []*models.ComplianceControl) (int, error) {
+	query := `
+		INSERT INTO compliance_controls (id, framework_id, control_id, title, description, category, subcategory,
+			status, severity, automated_check, check_query, evidence, remediation, owner, due_date,
+			last_checked, next_check, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+		ON CONFLICT (framework_id, control_id) DO NOTHING`
+
+	inserted := 0
+	for _, control := range controls {
+		result, err := tx.ExecContext(ctx, query,
+			control.ID, control.FrameworkID, control.ControlID, control.Title, control.Description,
+			control.Category, control.Subcategory, control.Status, control.Severity,
+			control.AutomatedCheck, control.CheckQuery, pq.Array(control.Evidence),
+			control.Remediation, control.Owner, control.DueDate, control.LastChecked,
+			control.NextCheck, control.CreatedAt, control.UpdatedAt)
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert control %s: %w", control.ControlID, err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		inserted += int(rowsAffected)
+	}
+
+	return inserted, nil
+}
+
 // GetByID retrieves a compliance control by ID
 func (r *ComplianceControlRepository) GetByID(ctx context.Context, controlID string) (*models.ComplianceControl, error) {
 	query := `
@@ -437,6 +470,54 @@ func (r *ComplianceAssessmentRepository) List(ctx context.Context, organizationI
 	return assessments, total, nil
 }
 
+// ListCompletedSince retrieves an organization's assessments completed since a time, oldest first,
+// optionally limited to one framework type
+func (r *ComplianceAssessmentRepository) ListCompletedSince(ctx context.Context, organizationID string, since time.Time, framework string) ([]*models.ComplianceAssessment, error) {
+	query := `
+		SELECT a.id, a.organization_id, a.framework_id, a.user_id, a.name, a.description,
+			a.status, a.score, a.max_score, a.started_at, a.completed_at, a.due_date, a.summary, a.created_at, a.updated_at
+		FROM compliance_assessments a
+		JOIN compliance_frameworks f ON f.id = a.framework_id
+		WHERE a.organization_id = $1 AND a.completed_at IS NOT NULL AND a.completed_at >= $2
+			AND ($3 = '' OR f.framework = $3)
+		ORDER BY a.completed_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, organizationID, since, framework)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list completed assessments: %w", err)
+	}
+	defer rows.Close()
+
+	var assessments []*models.ComplianceAssessment
+	for rows.Next() {
+		var assessment models.ComplianceAssessment
+		var summaryJSON []byte
+
+		err := rows.Scan(
+			&assessment.ID, &assessment.OrganizationID, &assessment.FrameworkID, &assessment.UserID,
+			&assessment.Name, &assessment.Description, &assessment.Status, &assessment.Score,
+			&assessment.MaxScore, &assessment.StartedAt, &assessment.CompletedAt, &assessment.DueDate,
+			&summaryJSON, &assessment.CreatedAt, &assessment.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan assessment row: %w", err)
+		}
+
+		if summaryJSON != nil {
+			if err := json.Unmarshal(summaryJSON, &assessment.Summary); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal summary: %w", err)
+			}
+		}
+
+		assessments = append(assessments, &assessment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating assessment rows: %w", err)
+	}
+
+	return assessments, nil
+}
+
 // Update updates a compliance assessment
 func (r *ComplianceAssessmentRepository) Update(ctx context.Context, assessment *models.ComplianceAssessment) error {
 	var summaryJSON []byte
@@ -450,11 +531,11 @@ func (r *ComplianceAssessmentRepository) Update(ctx context.Context, assessment
 
 	query := `
 		UPDATE compliance_assessments
-		SET status = $1, score = $2, started_at = $3, completed_at = $4, summary = $5, updated_at = $6
-		WHERE id = $7 AND organization_id = $8`
+		SET status = $1, score = $2, max_score = $3, started_at = $4, completed_at = $5, summary = $6, updated_at = $7
+		WHERE id = $8 AND organization_id = $9`
 
 	_, err = r.db.ExecContext(ctx, query,
-		assessment.Status, assessment.Score, assessment.StartedAt, assessment.CompletedAt,
+		assessment.Status, assessment.Score, assessment.MaxScore, assessment.StartedAt, assessment.CompletedAt,
 		summaryJSON, assessment.UpdatedAt, assessment.ID, assessment.OrganizationID)
 
 	return err
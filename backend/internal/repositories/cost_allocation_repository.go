@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"cloudweave/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// CostAllocationRepository handles organization cost allocation settings
+type CostAllocationRepository struct {
+	db *sql.DB
+}
+
+// NewCostAllocationRepository creates a new cost allocation repository
+func NewCostAllocationRepository(db *sql.DB) *CostAllocationRepository {
+	return &CostAllocationRepository{db: db}
+}
+
+// Get retrieves an organization's cost allocation settings, or nil if it has none
+func (r *CostAllocationRepository) Get(ctx context.Context, orgID string) (*models.CostAllocationSettings, error) {
+	query := `
+		SELECT organization_id, dimensions, updated_by, created_at, updated_at
+		FROM cost_allocation_settings
+		WHERE organization_id = $1`
+
+	settings := &models.CostAllocationSettings{}
+	err := r.db.QueryRowContext(ctx, query, orgID).Scan(
+		&settings.OrganizationID,
+		pq.Array(&settings.Dimensions),
+		&settings.UpdatedBy,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get cost allocation settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Save creates or replaces an organization's cost allocation settings
+func (r *CostAllocationRepository) Save(ctx context.Context, settings *models.CostAllocationSettings) error {
+	query := `
+		INSERT INTO cost_allocation_settings (organization_id, dimensions, updated_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id) DO UPDATE
+		SET dimensions = EXCLUDED.dimensions,
+		    updated_by = EXCLUDED.updated_by
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		settings.OrganizationID, pq.Array(settings.Dimensions), settings.UpdatedBy,
+	).Scan(&settings.CreatedAt, &settings.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save cost allocation settings: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// CostSnapshotRepository handles daily cost snapshot data operations
+type CostSnapshotRepository struct {
+	db *sql.DB
+}
+
+// NewCostSnapshotRepository creates a new cost snapshot repository
+func NewCostSnapshotRepository(db *sql.DB) *CostSnapshotRepository {
+	return &CostSnapshotRepository{db: db}
+}
+
+// Save creates or replaces an organization's cost snapshot for its day
+func (r *CostSnapshotRepository) Save(ctx context.Context, snapshot *models.CostSnapshot) error {
+	query := `
+		INSERT INTO cost_snapshots (organization_id, daily_cost, captured_on)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (organization_id, captured_on) DO UPDATE
+		SET daily_cost = EXCLUDED.daily_cost`
+
+	_, err := r.db.ExecContext(ctx, query, snapshot.OrganizationID, snapshot.DailyCost, snapshot.CapturedOn)
+	if err != nil {
+		return fmt.Errorf("failed to save cost snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// ListBetween retrieves an organization's cost snapshots captured on or after from and before to,
+// oldest first
+func (r *CostSnapshotRepository) ListBetween(ctx context.Context, orgID string, from, to time.Time) ([]*models.CostSnapshot, error) {
+	query := `
+		SELECT organization_id, daily_cost, captured_on
+		FROM cost_snapshots
+		WHERE organization_id = $1 AND captured_on >= $2 AND captured_on < $3
+		ORDER BY captured_on`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cost snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*models.CostSnapshot
+	for rows.Next() {
+		snapshot := &models.CostSnapshot{}
+		if err := rows.Scan(&snapshot.OrganizationID, &snapshot.DailyCost, &snapshot.CapturedOn); err != nil {
+			return nil, fmt.Errorf("failed to scan cost snapshot row: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating cost snapshot rows: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// SaveResource creates or replaces a resource's cost snapshot for its day
+func (r *CostSnapshotRepository) SaveResource(ctx context.Context, snapshot *models.ResourceCostSnapshot) error {
+	query := `
+		INSERT INTO resource_cost_snapshots (infrastructure_id, daily_cost, cpu_utilization, memory_utilization, captured_on)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (infrastructure_id, captured_on) DO UPDATE
+		SET daily_cost = EXCLUDED.daily_cost,
+		    cpu_utilization = EXCLUDED.cpu_utilization,
+		    memory_utilization = EXCLUDED.memory_utilization`
+
+	_, err := r.db.ExecContext(ctx, query, snapshot.InfrastructureID, snapshot.DailyCost, snapshot.CPUUtilization, snapshot.MemoryUtilization, snapshot.CapturedOn)
+	if err != nil {
+		return fmt.Errorf("failed to save resource cost snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// ListResourceBetween retrieves a resource's cost snapshots captured on or after from and before
+// to, oldest first
+func (r *CostSnapshotRepository) ListResourceBetween(ctx context.Context, infrastructureID string, from, to time.Time) ([]*models.ResourceCostSnapshot, error) {
+	query := `
+		SELECT infrastructure_id, daily_cost, cpu_utilization, memory_utilization, captured_on
+		FROM resource_cost_snapshots
+		WHERE infrastructure_id = $1 AND captured_on >= $2 AND captured_on < $3
+		ORDER BY captured_on`
+
+	rows, err := r.db.QueryContext(ctx, query, infrastructureID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource cost snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []*models.ResourceCostSnapshot
+	for rows.Next() {
+		snapshot := &models.ResourceCostSnapshot{}
+		if err := rows.Scan(&snapshot.InfrastructureID, &snapshot.DailyCost, &snapshot.CPUUtilization, &snapshot.MemoryUtilization, &snapshot.CapturedOn); err != nil {
+			return nil, fmt.Errorf("failed to scan resource cost snapshot row: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating resource cost snapshot rows: %w", err)
+	}
+
+	return snapshots, nil
+}
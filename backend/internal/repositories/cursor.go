@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded or does not point at a row
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the position of the last row of a page in keyset pagination
+type Cursor struct {
+	Time time.Time
+	ID   string
+}
+
+// EncodeCursor returns the opaque cursor for the page that follows a row
+func EncodeCursor(t time.Time, id string) string {
+	raw := strconv.FormatInt(t.UnixNano(), 10) + ":" + id
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor parses an opaque cursor returned by EncodeCursor. Rows are keyed by UUID, so a
+// cursor whose ID is not one is rejected here rather than failing the query.
+func DecodeCursor(cursor string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{Time: time.Unix(0, unixNano).UTC(), ID: id}, nil
+}
+
+// paginate appends ordering and pagination to a query that ends in its WHERE clause. With a cursor
+// it continues after the cursor's row by keyset on (timeColumn, id), newest first; otherwise it pages
+// by offset in orderBy.
+func paginate(query string, args []interface{}, cursor, timeColumn, orderBy string, limit, offset int) (string, []interface{}, error) {
+	argIndex := len(args) + 1
+
+	if cursor == "" {
+		query += fmt.Sprintf(" ORDER BY %s LIMIT $%d OFFSET $%d", orderBy, argIndex, argIndex+1)
+		return query, append(args, limit, offset), nil
+	}
+
+	position, err := DecodeCursor(cursor)
+	if err != nil {
+		return "", nil, err
+	}
+
+	query += fmt.Sprintf(" AND (%s, id) < ($%d, $%d) ORDER BY %s DESC, id DESC LIMIT $%d",
+		timeColumn, argIndex, argIndex+1, timeColumn, argIndex+2)
+	return query, append(args, position.Time, position.ID, limit), nil
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestDecodeCursorRoundTrip(t *testing.T) {
+	id := uuid.New().String()
+	created := time.Date(2025, 3, 14, 9, 26, 53, 589793000, time.UTC)
+
+	position, err := DecodeCursor(EncodeCursor(created, id))
+	if err != nil {
+		t.Fatalf("DecodeCursor returned an error: %v", err)
+	}
+	if position.ID != id || !position.Time.Equal(created) {
+		t.Errorf("cursor = %+v, want %s at %s", position, id, created)
+	}
+}
+
+func TestDecodeCursorRejectsMalformedCursors(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	cursors := map[string]string{
+		"not base64":   "%%%",
+		"no separator": encode("1700000000000000000"),
+		"bad time":     encode("yesterday:" + uuid.New().String()),
+		"empty id":     encode("1700000000000000000:"),
+		"id not uuid":  encode("1700000000000000000:not-a-uuid"),
+	}
+
+	for name, cursor := range cursors {
+		if _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: DecodeCursor error = %v, want ErrInvalidCursor", name, err)
+		}
+	}
+}
@@ -161,22 +161,38 @@ func (r *DemoDataRepository) DeleteByUserAndType(ctx context.Context, userID, da
 	return nil
 }
 
-// DeleteExpired deletes expired demo data
-func (r *DemoDataRepository) DeleteExpired(ctx context.Context) error {
-	query := `DELETE FROM demo_data WHERE expires_at IS NOT NULL AND expires_at < NOW()`
+// DeleteExpired deletes demo data past its expiry and returns the IDs of the users it belonged to
+func (r *DemoDataRepository) DeleteExpired(ctx context.Context) ([]string, error) {
+	query := `
+		DELETE FROM demo_data
+		WHERE expires_at IS NOT NULL AND expires_at < NOW()
+		RETURNING user_id
+	`
 
-	result, err := r.db.ExecContext(ctx, query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to delete expired demo data: %w", err)
+		return nil, fmt.Errorf("failed to clean up expired demo data: %w", err)
 	}
+	defer rows.Close()
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	seen := make(map[string]bool)
+	var userIDs []string
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("failed to scan expired demo data row: %w", err)
+		}
+		if !seen[userID] {
+			seen[userID] = true
+			userIDs = append(userIDs, userID)
+		}
 	}
 
-	fmt.Printf("Deleted %d expired demo data entries\n", rowsAffected)
-	return nil
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating expired demo data rows: %w", err)
+	}
+
+	return userIDs, nil
 }
 
 // Update updates demo data
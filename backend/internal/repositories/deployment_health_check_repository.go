@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"cloudweave/internal/models"
+)
+
+// DeploymentHealthCheckRepository handles deployment health check data operations
+type DeploymentHealthCheckRepository struct {
+	db *sql.DB
+}
+
+// NewDeploymentHealthCheckRepository creates a new deployment health check repository
+func NewDeploymentHealthCheckRepository(db *sql.DB) *DeploymentHealthCheckRepository {
+	return &DeploymentHealthCheckRepository{db: db}
+}
+
+// Create records the result of a health check
+func (r *DeploymentHealthCheckRepository) Create(ctx context.Context, check *models.DeploymentHealthCheck) error {
+	query := `
+		INSERT INTO deployment_health_checks (id, deployment_id, url, healthy, status_code, latency_ms, error, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	_, err := r.db.ExecContext(ctx, query,
+		check.ID, check.DeploymentID, check.URL, check.Healthy, check.StatusCode,
+		check.LatencyMs, check.Error, check.CheckedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create deployment health check: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDeployment retrieves the most recent health checks of a deployment, newest first
+func (r *DeploymentHealthCheckRepository) ListByDeployment(ctx context.Context, deploymentID string, limit int) ([]*models.DeploymentHealthCheck, error) {
+	query := `
+		SELECT id, deployment_id, url, healthy, status_code, latency_ms, error, checked_at
+		FROM deployment_health_checks
+		WHERE deployment_id = $1
+		ORDER BY checked_at DESC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, deploymentID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment health checks: %w", err)
+	}
+	defer rows.Close()
+
+	checks := []*models.DeploymentHealthCheck{}
+	for rows.Next() {
+		check := &models.DeploymentHealthCheck{}
+		err := rows.Scan(
+			&check.ID,
+			&check.DeploymentID,
+			&check.URL,
+			&check.Healthy,
+			&check.StatusCode,
+			&check.LatencyMs,
+			&check.Error,
+			&check.CheckedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deployment health check: %w", err)
+		}
+		checks = append(checks, check)
+	}
+
+	return checks, rows.Err()
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// DeploymentLockRepository handles deployment lock data operations
+type DeploymentLockRepository struct {
+	db *sql.DB
+}
+
+// NewDeploymentLockRepository creates a new deployment lock repository
+func NewDeploymentLockRepository(db *sql.DB) *DeploymentLockRepository {
+	return &DeploymentLockRepository{db: db}
+}
+
+// Acquire takes the lock of an application and environment for a deployment. It reports false
+// when the lock is held by another deployment and has not expired; an expired lock is taken over.
+func (r *DeploymentLockRepository) Acquire(ctx context.Context, lock *models.DeploymentLock) (bool, error) {
+	query := `
+		INSERT INTO deployment_locks (organization_id, application, environment, deployment_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (organization_id, application, environment) DO UPDATE
+		SET deployment_id = EXCLUDED.deployment_id, acquired_at = NOW(), expires_at = EXCLUDED.expires_at
+		WHERE deployment_locks.expires_at <= NOW() OR deployment_locks.deployment_id = EXCLUDED.deployment_id
+		RETURNING acquired_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		lock.OrganizationID, lock.Application, lock.Environment, lock.DeploymentID, lock.ExpiresAt,
+	).Scan(&lock.AcquiredAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire deployment lock: %w", err)
+	}
+
+	return true, nil
+}
+
+// Get retrieves the unexpired lock of an application and environment
+func (r *DeploymentLockRepository) Get(ctx context.Context, orgID, application, environment string) (*models.DeploymentLock, error) {
+	query := `
+		SELECT organization_id, application, environment, deployment_id, acquired_at, expires_at
+		FROM deployment_locks
+		WHERE organization_id = $1 AND application = $2 AND environment = $3 AND expires_at > NOW()`
+
+	var lock models.DeploymentLock
+	err := r.db.QueryRowContext(ctx, query, orgID, application, environment).Scan(
+		&lock.OrganizationID, &lock.Application, &lock.Environment, &lock.DeploymentID,
+		&lock.AcquiredAt, &lock.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("deployment lock not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get deployment lock: %w", err)
+	}
+
+	return &lock, nil
+}
+
+// Renew extends the lock held by a deployment, if it still holds one
+func (r *DeploymentLockRepository) Renew(ctx context.Context, deploymentID string, expiresAt time.Time) error {
+	query := `UPDATE deployment_locks SET expires_at = $2 WHERE deployment_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, deploymentID, expiresAt); err != nil {
+		return fmt.Errorf("failed to renew deployment lock: %w", err)
+	}
+
+	return nil
+}
+
+// Release frees the lock held by a deployment, if it still holds one
+func (r *DeploymentLockRepository) Release(ctx context.Context, deploymentID string) error {
+	query := `DELETE FROM deployment_locks WHERE deployment_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, deploymentID); err != nil {
+		return fmt.Errorf("failed to release deployment lock: %w", err)
+	}
+
+	return nil
+}
@@ -13,6 +13,9 @@ import (
 
 type DeploymentRepository struct {
 	db *sql.DB
+
+	// replica serves list and query reads when set; see readDB
+	replica *sql.DB
 }
 
 func NewDeploymentRepository(db *sql.DB) *DeploymentRepository {
@@ -23,8 +26,8 @@ func NewDeploymentRepository(db *sql.DB) *DeploymentRepository {
 func (r *DeploymentRepository) Create(ctx context.Context, deployment *models.Deployment) error {
 	query := `
 		INSERT INTO deployments (id, organization_id, name, application, version, environment, 
-		                        status, progress, configuration, created_by)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		                        status, progress, configuration, created_by, pipeline_id, strategy, strategy_state, scheduled_for)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 		RETURNING created_at, updated_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -38,13 +41,17 @@ func (r *DeploymentRepository) Create(ctx context.Context, deployment *models.De
 		deployment.Progress,
 		deployment.Configuration,
 		deployment.CreatedBy,
+		deployment.PipelineID,
+		deployment.Strategy,
+		deployment.StrategyState,
+		deployment.ScheduledFor,
 	).Scan(&deployment.CreatedAt, &deployment.UpdatedAt)
 
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			switch pqErr.Code {
 			case "23503": // foreign_key_violation
-				return fmt.Errorf("invalid organization_id or created_by user_id")
+				return fmt.Errorf("invalid organization_id, created_by user_id or pipeline_id")
 			}
 		}
 		return fmt.Errorf("failed to create deployment: %w", err)
@@ -58,7 +65,7 @@ func (r *DeploymentRepository) GetByID(ctx context.Context, id string) (*models.
 	deployment := &models.Deployment{}
 	query := `
 		SELECT id, organization_id, name, application, version, environment, status, 
-		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at
+		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at, pipeline_id, strategy, strategy_state, scheduled_for
 		FROM deployments 
 		WHERE id = $1`
 
@@ -77,6 +84,10 @@ func (r *DeploymentRepository) GetByID(ctx context.Context, id string) (*models.
 		&deployment.CreatedBy,
 		&deployment.CreatedAt,
 		&deployment.UpdatedAt,
+		&deployment.PipelineID,
+		&deployment.Strategy,
+		&deployment.StrategyState,
+		&deployment.ScheduledFor,
 	)
 
 	if err != nil {
@@ -94,7 +105,7 @@ func (r *DeploymentRepository) Update(ctx context.Context, deployment *models.De
 	query := `
 		UPDATE deployments 
 		SET name = $2, application = $3, version = $4, environment = $5, status = $6,
-		    progress = $7, configuration = $8, started_at = $9, completed_at = $10, updated_at = NOW()
+		    progress = $7, configuration = $8, started_at = $9, completed_at = $10, strategy_state = $11, updated_at = NOW()
 		WHERE id = $1
 		RETURNING updated_at`
 
@@ -109,6 +120,7 @@ func (r *DeploymentRepository) Update(ctx context.Context, deployment *models.De
 		deployment.Configuration,
 		deployment.StartedAt,
 		deployment.CompletedAt,
+		deployment.StrategyState,
 	).Scan(&deployment.UpdatedAt)
 
 	if err != nil {
@@ -188,21 +200,19 @@ func (r *DeploymentRepository) List(ctx context.Context, orgID string, params Li
 
 	query := fmt.Sprintf(`
 		SELECT id, organization_id, name, application, version, environment, status, 
-		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at
+		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at, pipeline_id, strategy, strategy_state, scheduled_for
 		FROM deployments 
-		%s
-		ORDER BY %s %s
-		LIMIT $%d OFFSET $%d`,
+		%s`,
 		whereClause.String(),
-		params.SortBy,
-		params.Order,
-		argIndex,
-		argIndex+1,
 	)
 
-	args = append(args, params.Limit, params.Offset)
+	orderBy := fmt.Sprintf("%s %s, id %s", params.SortBy, params.Order, params.Order)
+	query, args, err := paginate(query, args, params.Cursor, "created_at", orderBy, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list deployments: %w", err)
 	}
@@ -226,6 +236,10 @@ func (r *DeploymentRepository) List(ctx context.Context, orgID string, params Li
 			&deployment.CreatedBy,
 			&deployment.CreatedAt,
 			&deployment.UpdatedAt,
+			&deployment.PipelineID,
+			&deployment.Strategy,
+			&deployment.StrategyState,
+			&deployment.ScheduledFor,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan deployment row: %w", err)
@@ -246,13 +260,16 @@ func (r *DeploymentRepository) ListByEnvironment(ctx context.Context, orgID, env
 
 	query := `
 		SELECT id, organization_id, name, application, version, environment, status, 
-		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at
+		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at, pipeline_id, strategy, strategy_state, scheduled_for
 		FROM deployments 
-		WHERE organization_id = $1 AND environment = $2
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4`
+		WHERE organization_id = $1 AND environment = $2`
+
+	query, args, err := paginate(query, []interface{}{orgID, environment}, params.Cursor, "created_at", "created_at DESC, id DESC", params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, orgID, environment, params.Limit, params.Offset)
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list deployments by environment: %w", err)
 	}
@@ -276,6 +293,10 @@ func (r *DeploymentRepository) ListByEnvironment(ctx context.Context, orgID, env
 			&deployment.CreatedBy,
 			&deployment.CreatedAt,
 			&deployment.UpdatedAt,
+			&deployment.PipelineID,
+			&deployment.Strategy,
+			&deployment.StrategyState,
+			&deployment.ScheduledFor,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan deployment row: %w", err)
@@ -292,13 +313,16 @@ func (r *DeploymentRepository) ListByStatus(ctx context.Context, orgID, status s
 
 	query := `
 		SELECT id, organization_id, name, application, version, environment, status, 
-		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at
+		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at, pipeline_id, strategy, strategy_state, scheduled_for
 		FROM deployments 
-		WHERE organization_id = $1 AND status = $2
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4`
+		WHERE organization_id = $1 AND status = $2`
 
-	rows, err := r.db.QueryContext(ctx, query, orgID, status, params.Limit, params.Offset)
+	query, args, err := paginate(query, []interface{}{orgID, status}, params.Cursor, "created_at", "created_at DESC, id DESC", params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list deployments by status: %w", err)
 	}
@@ -322,6 +346,10 @@ func (r *DeploymentRepository) ListByStatus(ctx context.Context, orgID, status s
 			&deployment.CreatedBy,
 			&deployment.CreatedAt,
 			&deployment.UpdatedAt,
+			&deployment.PipelineID,
+			&deployment.Strategy,
+			&deployment.StrategyState,
+			&deployment.ScheduledFor,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan deployment row: %w", err)
@@ -332,6 +360,128 @@ func (r *DeploymentRepository) ListByStatus(ctx context.Context, orgID, status s
 	return deployments, nil
 }
 
+// ListLatestByApplication retrieves the most recent deployment of each application in each environment
+func (r *DeploymentRepository) ListLatestByApplication(ctx context.Context, orgID string) ([]*models.Deployment, error) {
+	query := `
+		SELECT DISTINCT ON (environment, application)
+		       id, organization_id, name, application, version, environment, status,
+		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at, pipeline_id, strategy, strategy_state, scheduled_for
+		FROM deployments
+		WHERE organization_id = $1
+		ORDER BY environment, application, created_at DESC`
+
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list latest deployments: %w", err)
+	}
+	defer rows.Close()
+
+	var deployments []*models.Deployment
+	for rows.Next() {
+		deployment := &models.Deployment{}
+		err := rows.Scan(
+			&deployment.ID,
+			&deployment.OrganizationID,
+			&deployment.Name,
+			&deployment.Application,
+			&deployment.Version,
+			&deployment.Environment,
+			&deployment.Status,
+			&deployment.Progress,
+			&deployment.Configuration,
+			&deployment.StartedAt,
+			&deployment.CompletedAt,
+			&deployment.CreatedBy,
+			&deployment.CreatedAt,
+			&deployment.UpdatedAt,
+			&deployment.PipelineID,
+			&deployment.Strategy,
+			&deployment.StrategyState,
+			&deployment.ScheduledFor,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deployment row: %w", err)
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating deployment rows: %w", err)
+	}
+
+	return deployments, nil
+}
+
+// ListScheduled retrieves the scheduled deployments of an organization, or of every organization
+// when orgID is empty, in the order they are due
+func (r *DeploymentRepository) ListScheduled(ctx context.Context, orgID string) ([]*models.Deployment, error) {
+	query := `
+		SELECT id, organization_id, name, application, version, environment, status,
+		       progress, configuration, started_at, completed_at, created_by, created_at, updated_at, pipeline_id, strategy, strategy_state, scheduled_for
+		FROM deployments
+		WHERE status = $1 AND ($2 = '' OR organization_id::text = $2)
+		ORDER BY scheduled_for ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, models.DeploymentStatusScheduled, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled deployments: %w", err)
+	}
+	defer rows.Close()
+
+	deployments := []*models.Deployment{}
+	for rows.Next() {
+		deployment := &models.Deployment{}
+		err := rows.Scan(
+			&deployment.ID,
+			&deployment.OrganizationID,
+			&deployment.Name,
+			&deployment.Application,
+			&deployment.Version,
+			&deployment.Environment,
+			&deployment.Status,
+			&deployment.Progress,
+			&deployment.Configuration,
+			&deployment.StartedAt,
+			&deployment.CompletedAt,
+			&deployment.CreatedBy,
+			&deployment.CreatedAt,
+			&deployment.UpdatedAt,
+			&deployment.PipelineID,
+			&deployment.Strategy,
+			&deployment.StrategyState,
+			&deployment.ScheduledFor,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deployment row: %w", err)
+		}
+		deployments = append(deployments, deployment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating deployment rows: %w", err)
+	}
+
+	return deployments, nil
+}
+
+// TransitionStatus moves a deployment from one status to another. It reports false, without
+// changing anything, when the deployment is no longer in the expected status.
+func (r *DeploymentRepository) TransitionStatus(ctx context.Context, id, from, to string) (bool, error) {
+	query := `UPDATE deployments SET status = $3, updated_at = NOW() WHERE id = $1 AND status = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, from, to)
+	if err != nil {
+		return false, fmt.Errorf("failed to update deployment status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
 // UpdateStatus updates the status of a deployment
 func (r *DeploymentRepository) UpdateStatus(ctx context.Context, id, status string) error {
 	query := `UPDATE deployments SET status = $2, updated_at = NOW() WHERE id = $1`
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"cloudweave/internal/models"
+)
+
+// DeploymentRollbackRepository handles deployment rollback history data operations
+type DeploymentRollbackRepository struct {
+	db *sql.DB
+}
+
+// NewDeploymentRollbackRepository creates a new deployment rollback repository
+func NewDeploymentRollbackRepository(db *sql.DB) *DeploymentRollbackRepository {
+	return &DeploymentRollbackRepository{db: db}
+}
+
+// Create records a new deployment rollback
+func (r *DeploymentRollbackRepository) Create(ctx context.Context, rollback *models.DeploymentRollback) error {
+	var safetyCheckJSON []byte
+	if rollback.SafetyCheck != nil {
+		var err error
+		if safetyCheckJSON, err = json.Marshal(rollback.SafetyCheck); err != nil {
+			return fmt.Errorf("failed to marshal rollback safety check: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO deployment_rollbacks (id, organization_id, deployment_id, rollback_deployment_id,
+		                                  from_version, to_version, trigger_type, reason, metric_name,
+		                                  metric_value, threshold, initiated_by, safety_check)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		rollback.ID, rollback.OrganizationID, rollback.DeploymentID, rollback.RollbackDeploymentID,
+		rollback.FromVersion, rollback.ToVersion, rollback.Trigger, rollback.Reason, rollback.MetricName,
+		rollback.MetricValue, rollback.Threshold, rollback.InitiatedBy, safetyCheckJSON,
+	).Scan(&rollback.CreatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create deployment rollback: %w", err)
+	}
+
+	return nil
+}
+
+// ListByDeployment retrieves the rollbacks of a deployment, including refused ones, and the
+// rollback that created it if it is a rollback deployment, oldest first
+func (r *DeploymentRollbackRepository) ListByDeployment(ctx context.Context, deploymentID string) ([]*models.DeploymentRollback, error) {
+	query := `
+		SELECT id, organization_id, deployment_id, rollback_deployment_id, from_version, to_version,
+		       trigger_type, reason, metric_name, metric_value, threshold, initiated_by, safety_check,
+		       created_at
+		FROM deployment_rollbacks
+		WHERE deployment_id = $1 OR rollback_deployment_id = $1
+		ORDER BY created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, deploymentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployment rollbacks: %w", err)
+	}
+	defer rows.Close()
+
+	rollbacks := []*models.DeploymentRollback{}
+	for rows.Next() {
+		rollback := &models.DeploymentRollback{}
+		var safetyCheckJSON []byte
+		err := rows.Scan(
+			&rollback.ID,
+			&rollback.OrganizationID,
+			&rollback.DeploymentID,
+			&rollback.RollbackDeploymentID,
+			&rollback.FromVersion,
+			&rollback.ToVersion,
+			&rollback.Trigger,
+			&rollback.Reason,
+			&rollback.MetricName,
+			&rollback.MetricValue,
+			&rollback.Threshold,
+			&rollback.InitiatedBy,
+			&safetyCheckJSON,
+			&rollback.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deployment rollback: %w", err)
+		}
+		if len(safetyCheckJSON) > 0 {
+			rollback.SafetyCheck = &models.RollbackSafetyCheck{}
+			if err := json.Unmarshal(safetyCheckJSON, rollback.SafetyCheck); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal rollback safety check: %w", err)
+			}
+		}
+		rollbacks = append(rollbacks, rollback)
+	}
+
+	return rollbacks, rows.Err()
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"cloudweave/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// EnvironmentRepository handles custom deployment environment data operations
+type EnvironmentRepository struct {
+	db *sql.DB
+}
+
+// NewEnvironmentRepository creates a new environment repository
+func NewEnvironmentRepository(db *sql.DB) *EnvironmentRepository {
+	return &EnvironmentRepository{db: db}
+}
+
+// Create creates a new custom environment
+func (r *EnvironmentRepository) Create(ctx context.Context, environment *models.Environment) error {
+	query := `
+		INSERT INTO environments (id, organization_id, key, name, description)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		environment.ID, environment.OrganizationID, environment.Key, environment.Name, environment.Description,
+	).Scan(&environment.CreatedAt)
+
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("environment with key %s already exists", environment.Key)
+		}
+		return fmt.Errorf("failed to create environment: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves an organization's custom environments
+func (r *EnvironmentRepository) List(ctx context.Context, orgID string) ([]*models.Environment, error) {
+	query := `
+		SELECT id, organization_id, key, name, description, created_at
+		FROM environments
+		WHERE organization_id = $1
+		ORDER BY name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list environments: %w", err)
+	}
+	defer rows.Close()
+
+	var environments []*models.Environment
+	for rows.Next() {
+		environment := &models.Environment{}
+		err := rows.Scan(
+			&environment.ID,
+			&environment.OrganizationID,
+			&environment.Key,
+			&environment.Name,
+			&environment.Description,
+			&environment.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan environment row: %w", err)
+		}
+		environments = append(environments, environment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating environment rows: %w", err)
+	}
+
+	return environments, nil
+}
+
+// Delete deletes a custom environment within an organization
+func (r *EnvironmentRepository) Delete(ctx context.Context, id, orgID string) error {
+	query := `DELETE FROM environments WHERE id = $1 AND organization_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete environment: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("environment with id %s not found", id)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// IdempotencyKeyRepository handles idempotency key data operations
+type IdempotencyKeyRepository struct {
+	db *sql.DB
+}
+
+// NewIdempotencyKeyRepository creates a new idempotency key repository
+func NewIdempotencyKeyRepository(db *sql.DB) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: db}
+}
+
+// Reserve claims a key for a new request. It reports false when the key is already held by an
+// unexpired request; an expired key is taken over.
+func (r *IdempotencyKeyRepository) Reserve(ctx context.Context, orgID, key, requestHash string, expiresAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (organization_id, key, request_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = 0, content_type = '',
+		    response_body = NULL, created_at = NOW(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+		RETURNING key`
+
+	var reserved string
+	err := r.db.QueryRowContext(ctx, query, orgID, key, requestHash, expiresAt).Scan(&reserved)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	return true, nil
+}
+
+// Get retrieves an unexpired idempotency key
+func (r *IdempotencyKeyRepository) Get(ctx context.Context, orgID, key string) (*models.IdempotencyKey, error) {
+	query := `
+		SELECT organization_id, key, request_hash, status_code, content_type, response_body, created_at, expires_at
+		FROM idempotency_keys
+		WHERE organization_id = $1 AND key = $2 AND expires_at > NOW()`
+
+	var record models.IdempotencyKey
+	err := r.db.QueryRowContext(ctx, query, orgID, key).Scan(
+		&record.OrganizationID, &record.Key, &record.RequestHash, &record.StatusCode,
+		&record.ContentType, &record.ResponseBody, &record.CreatedAt, &record.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("idempotency key not found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	return &record, nil
+}
+
+// Complete stores the response produced for a reserved key
+func (r *IdempotencyKeyRepository) Complete(ctx context.Context, orgID, key string, statusCode int, contentType string, body []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, response_body = $5
+		WHERE organization_id = $1 AND key = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, orgID, key, statusCode, contentType, body); err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an idempotency key
+func (r *IdempotencyKeyRepository) Delete(ctx context.Context, orgID, key string) error {
+	query := `DELETE FROM idempotency_keys WHERE organization_id = $1 AND key = $2`
+
+	if _, err := r.db.ExecContext(ctx, query, orgID, key); err != nil {
+		return fmt.Errorf("failed to delete idempotency key: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteExpired removes every expired idempotency key and returns how many were removed
+func (r *IdempotencyKeyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	query := `DELETE FROM idempotency_keys WHERE expires_at <= NOW()`
+
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	return result.RowsAffected()
+}
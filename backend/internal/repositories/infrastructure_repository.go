@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"cloudweave/internal/models"
 
@@ -14,6 +15,9 @@ import (
 
 type InfrastructureRepository struct {
 	db *sql.DB
+
+	// replica serves list and query reads when set; see readDB
+	replica *sql.DB
 }
 
 func NewInfrastructureRepository(db *sql.DB) *InfrastructureRepository {
@@ -82,7 +86,7 @@ func (r *InfrastructureRepository) GetByID(ctx context.Context, id string) (*mod
 		SELECT id, organization_id, name, type, provider, region, status, 
 		       specifications, cost_info, tags, external_id, created_at, updated_at
 		FROM infrastructure 
-		WHERE id = $1`
+		WHERE id = $1 AND deleted_at IS NULL`
 
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&infra.ID,
@@ -145,7 +149,7 @@ func (r *InfrastructureRepository) Update(ctx context.Context, infra *models.Inf
 		UPDATE infrastructure 
 		SET name = $2, type = $3, provider = $4, region = $5, status = $6,
 		    specifications = $7, cost_info = $8, tags = $9, external_id = $10, updated_at = NOW()
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING updated_at`
 
 	err = r.db.QueryRowContext(ctx, query,
@@ -171,9 +175,10 @@ func (r *InfrastructureRepository) Update(ctx context.Context, infra *models.Inf
 	return nil
 }
 
-// Delete deletes an infrastructure resource by its ID
+// Delete soft-deletes an infrastructure resource by its ID. The row is kept until it is purged
+// so it can be restored.
 func (r *InfrastructureRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM infrastructure WHERE id = $1`
+	query := `UPDATE infrastructure SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
@@ -192,6 +197,136 @@ func (r *InfrastructureRepository) Delete(ctx context.Context, id string) error
 	return nil
 }
 
+// Restore undeletes an organization's infrastructure resource that was soft-deleted after deletedSince
+func (r *InfrastructureRepository) Restore(ctx context.Context, id, orgID string, deletedSince time.Time) error {
+	query := `
+		UPDATE infrastructure
+		SET deleted_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NOT NULL AND deleted_at > $3`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID, deletedSince)
+	if err != nil {
+		return fmt.Errorf("failed to restore infrastructure: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("deleted infrastructure resource with id %s not found", id)
+	}
+
+	return nil
+}
+
+// ReplaceTags adds tags to an organization's infrastructure resource in one statement, first dropping
+// existing tags whose key is in keys
+func (r *InfrastructureRepository) ReplaceTags(ctx context.Context, id, orgID string, keys, tags []string) error {
+	query := `
+		UPDATE infrastructure
+		SET tags = (
+			SELECT COALESCE(jsonb_agg(tag ORDER BY tag), '[]'::jsonb)
+			FROM (
+				SELECT tag FROM jsonb_array_elements_text(COALESCE(tags, '[]'::jsonb)) AS tag
+				WHERE split_part(tag, '=', 1) <> ALL($3::text[])
+				UNION
+				SELECT unnest($4::text[])
+			) AS merged(tag)
+		), updated_at = NOW()
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL`
+
+	return r.updateTags(ctx, query, id, orgID, pq.Array(keys), pq.Array(tags))
+}
+
+// RemoveTags removes the tags matching exact, and every tag whose key is in keys, from an
+// organization's infrastructure resource
+func (r *InfrastructureRepository) RemoveTags(ctx context.Context, id, orgID string, exact, keys []string) error {
+	query := `
+		UPDATE infrastructure
+		SET tags = (
+			SELECT COALESCE(jsonb_agg(tag ORDER BY tag), '[]'::jsonb)
+			FROM jsonb_array_elements_text(COALESCE(tags, '[]'::jsonb)) AS tag
+			WHERE tag <> ALL($3::text[]) AND split_part(tag, '=', 1) <> ALL($4::text[])
+		), updated_at = NOW()
+		WHERE id = $1 AND organization_id = $2 AND deleted_at IS NULL`
+
+	return r.updateTags(ctx, query, id, orgID, pq.Array(exact), pq.Array(keys))
+}
+
+// updateTags runs a tag update, reporting sql.ErrNoRows when the resource does not exist
+func (r *InfrastructureRepository) updateTags(ctx context.Context, query, id, orgID string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, append([]interface{}{id, orgID}, args...)...)
+	if err != nil {
+		return fmt.Errorf("failed to update infrastructure tags: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("infrastructure resource with id %s not found: %w", id, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+// appendTagFilter restricts a query to resources carrying every one of tags
+func appendTagFilter(query string, args []interface{}, tags []string) (string, []interface{}) {
+	if len(tags) == 0 {
+		return query, args
+	}
+
+	tagsJSON, _ := json.Marshal(tags)
+	args = append(args, string(tagsJSON))
+	return query + fmt.Sprintf(" AND tags @> $%d::jsonb", len(args)), args
+}
+
+// PurgeDeleted permanently removes infrastructure resources soft-deleted before deletedBefore
+func (r *InfrastructureRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	query := `DELETE FROM infrastructure WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted infrastructure: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// FailStalePending moves resources created before createdBefore that are still pending without an
+// external ID to error, and returns their IDs
+func (r *InfrastructureRepository) FailStalePending(ctx context.Context, createdBefore time.Time) ([]string, error) {
+	query := `
+		UPDATE infrastructure SET status = $1, updated_at = NOW()
+		WHERE status = $2 AND external_id IS NULL AND created_at < $3 AND deleted_at IS NULL
+		RETURNING id`
+
+	rows, err := r.db.QueryContext(ctx, query, models.InfraStatusError, models.InfraStatusPending, createdBefore)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fail stale pending infrastructure: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan infrastructure id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating infrastructure rows: %w", err)
+	}
+
+	return ids, nil
+}
+
 // List retrieves infrastructure resources for an organization with pagination and filtering
 func (r *InfrastructureRepository) List(ctx context.Context, orgID string, params ListParams) ([]*models.Infrastructure, error) {
 	params.Validate()
@@ -205,6 +340,11 @@ func (r *InfrastructureRepository) List(ctx context.Context, orgID string, param
 	args = append(args, orgID)
 	argIndex++
 
+	// Soft-deleted resources are hidden unless requested
+	if !params.IncludeDeleted {
+		whereClause.WriteString(" AND deleted_at IS NULL")
+	}
+
 	// Add search filter if provided
 	if params.Search != "" {
 		whereClause.WriteString(" AND (name ILIKE $")
@@ -233,23 +373,23 @@ func (r *InfrastructureRepository) List(ctx context.Context, orgID string, param
 		params.SortBy = "created_at"
 	}
 
+	where, args := appendTagFilter(whereClause.String(), args, params.Tags)
+
 	query := fmt.Sprintf(`
 		SELECT id, organization_id, name, type, provider, region, status, 
-		       specifications, cost_info, tags, external_id, created_at, updated_at
+		       specifications, cost_info, tags, external_id, created_at, updated_at, deleted_at
 		FROM infrastructure 
-		%s
-		ORDER BY %s %s
-		LIMIT $%d OFFSET $%d`,
-		whereClause.String(),
-		params.SortBy,
-		params.Order,
-		argIndex,
-		argIndex+1,
+		%s`,
+		where,
 	)
 
-	args = append(args, params.Limit, params.Offset)
+	orderBy := fmt.Sprintf("%s %s, id %s", params.SortBy, params.Order, params.Order)
+	query, args, err := paginate(query, args, params.Cursor, "created_at", orderBy, params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list infrastructure: %w", err)
 	}
@@ -274,6 +414,7 @@ func (r *InfrastructureRepository) List(ctx context.Context, orgID string, param
 			&infra.ExternalID,
 			&infra.CreatedAt,
 			&infra.UpdatedAt,
+			&infra.DeletedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan infrastructure row: %w", err)
@@ -310,11 +451,15 @@ func (r *InfrastructureRepository) ListByProvider(ctx context.Context, orgID, pr
 		SELECT id, organization_id, name, type, provider, region, status, 
 		       specifications, cost_info, tags, external_id, created_at, updated_at
 		FROM infrastructure 
-		WHERE organization_id = $1 AND provider = $2
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4`
+		WHERE organization_id = $1 AND provider = $2 AND deleted_at IS NULL`
+	query, args := appendTagFilter(query, []interface{}{orgID, provider}, params.Tags)
 
-	rows, err := r.db.QueryContext(ctx, query, orgID, provider, params.Limit, params.Offset)
+	query, args, err := paginate(query, args, params.Cursor, "created_at", "created_at DESC, id DESC", params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list infrastructure by provider: %w", err)
 	}
@@ -355,11 +500,15 @@ func (r *InfrastructureRepository) ListByStatus(ctx context.Context, orgID, stat
 		SELECT id, organization_id, name, type, provider, region, status, 
 		       specifications, cost_info, tags, external_id, created_at, updated_at
 		FROM infrastructure 
-		WHERE organization_id = $1 AND status = $2
-		ORDER BY created_at DESC
-		LIMIT $3 OFFSET $4`
+		WHERE organization_id = $1 AND status = $2 AND deleted_at IS NULL`
+	query, args := appendTagFilter(query, []interface{}{orgID, status}, params.Tags)
 
-	rows, err := r.db.QueryContext(ctx, query, orgID, status, params.Limit, params.Offset)
+	query, args, err := paginate(query, args, params.Cursor, "created_at", "created_at DESC, id DESC", params.Limit, params.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list infrastructure by status: %w", err)
 	}
@@ -394,7 +543,7 @@ func (r *InfrastructureRepository) ListByStatus(ctx context.Context, orgID, stat
 
 // UpdateStatus updates the status of an infrastructure resource
 func (r *InfrastructureRepository) UpdateStatus(ctx context.Context, id, status string) error {
-	query := `UPDATE infrastructure SET status = $2, updated_at = NOW() WHERE id = $1`
+	query := `UPDATE infrastructure SET status = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
 
 	result, err := r.db.ExecContext(ctx, query, id, status)
 	if err != nil {
@@ -413,6 +562,23 @@ func (r *InfrastructureRepository) UpdateStatus(ctx context.Context, id, status
 	return nil
 }
 
+// ExistsByExternalID reports whether an organization has recorded a provider resource, including
+// soft-deleted records that can still be restored
+func (r *InfrastructureRepository) ExistsByExternalID(ctx context.Context, orgID, provider, externalID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM infrastructure
+			WHERE organization_id = $1 AND provider = $2 AND external_id = $3
+		)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, orgID, provider, externalID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check infrastructure external_id: %w", err)
+	}
+
+	return exists, nil
+}
+
 // GetByExternalID retrieves an infrastructure resource by its external ID
 func (r *InfrastructureRepository) GetByExternalID(ctx context.Context, externalID string) (*models.Infrastructure, error) {
 	infra := &models.Infrastructure{}
@@ -420,7 +586,7 @@ func (r *InfrastructureRepository) GetByExternalID(ctx context.Context, external
 		SELECT id, organization_id, name, type, provider, region, status, 
 		       specifications, cost_info, tags, external_id, created_at, updated_at
 		FROM infrastructure 
-		WHERE external_id = $1`
+		WHERE external_id = $1 AND deleted_at IS NULL`
 
 	err := r.db.QueryRowContext(ctx, query, externalID).Scan(
 		&infra.ID,
@@ -447,3 +613,151 @@ func (r *InfrastructureRepository) GetByExternalID(ctx context.Context, external
 
 	return infra, nil
 }
+
+// GetVersion returns the number of an organization's infrastructure resources and when the most
+// recent one changed, which together identify the current state of its infrastructure
+func (r *InfrastructureRepository) GetVersion(ctx context.Context, orgID string) (int, *time.Time, error) {
+	query := `
+		SELECT COUNT(*), MAX(updated_at)
+		FROM infrastructure
+		WHERE organization_id = $1 AND deleted_at IS NULL`
+
+	var count int
+	var lastChanged *time.Time
+	if err := r.db.QueryRowContext(ctx, query, orgID).Scan(&count, &lastChanged); err != nil {
+		return 0, nil, fmt.Errorf("failed to get infrastructure version: %w", err)
+	}
+
+	return count, lastChanged, nil
+}
+
+// ListStatusHistory retrieves the status changes of an organization's infrastructure resources
+// since a time, grouped by resource and oldest first. Each resource's history also includes the
+// last change before since, so its status at the start of the period is known.
+func (r *InfrastructureRepository) ListStatusHistory(ctx context.Context, orgID string, since time.Time) (map[string][]*models.InfrastructureStatusChange, error) {
+	query := `
+		SELECT h.infrastructure_id, h.status, h.changed_at
+		FROM infrastructure_status_history h
+		JOIN infrastructure i ON i.id = h.infrastructure_id
+		WHERE i.organization_id = $1 AND i.deleted_at IS NULL
+		  AND h.changed_at >= COALESCE((
+		      SELECT MAX(p.changed_at)
+		      FROM infrastructure_status_history p
+		      WHERE p.infrastructure_id = h.infrastructure_id AND p.changed_at <= $2
+		  ), $2)
+		ORDER BY h.infrastructure_id, h.changed_at`
+
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, orgID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list infrastructure status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make(map[string][]*models.InfrastructureStatusChange)
+	for rows.Next() {
+		change := &models.InfrastructureStatusChange{}
+		if err := rows.Scan(&change.InfrastructureID, &change.Status, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan infrastructure status history row: %w", err)
+		}
+		history[change.InfrastructureID] = append(history[change.InfrastructureID], change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating infrastructure status history rows: %w", err)
+	}
+
+	return history, nil
+}
+
+// RecordChange adds an entry made by CloudWeave rather than by editing the resource, such as a
+// provider sync result, to a resource's change log. Edits are recorded by a database trigger.
+func (r *InfrastructureRepository) RecordChange(ctx context.Context, id, changeType string, changes map[string]models.InfrastructureFieldChange, message *string) error {
+	if changes == nil {
+		changes = map[string]models.InfrastructureFieldChange{}
+	}
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal infrastructure change: %w", err)
+	}
+
+	query := `
+		INSERT INTO infrastructure_changes (infrastructure_id, organization_id, change_type, changes, message)
+		SELECT id, organization_id, $2, $3, $4 FROM infrastructure WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, changeType, string(changesJSON), message); err != nil {
+		return fmt.Errorf("failed to record infrastructure change: %w", err)
+	}
+
+	return nil
+}
+
+// ListChanges retrieves a resource's change log, newest first
+func (r *InfrastructureRepository) ListChanges(ctx context.Context, id string, limit, offset int) ([]*models.InfrastructureChange, error) {
+	query := `
+		SELECT c.id, c.infrastructure_id, i.name, i.provider, c.change_type, c.changes, c.message, c.changed_at
+		FROM infrastructure_changes c
+		JOIN infrastructure i ON i.id = c.infrastructure_id
+		WHERE c.infrastructure_id = $1
+		ORDER BY c.changed_at DESC, c.id
+		LIMIT $2 OFFSET $3`
+
+	return r.queryChanges(ctx, query, id, limit, offset)
+}
+
+// GetLatestChange retrieves a resource's most recent change log entry of a type, or nil if it has none
+func (r *InfrastructureRepository) GetLatestChange(ctx context.Context, id, changeType string) (*models.InfrastructureChange, error) {
+	query := `
+		SELECT c.id, c.infrastructure_id, i.name, i.provider, c.change_type, c.changes, c.message, c.changed_at
+		FROM infrastructure_changes c
+		JOIN infrastructure i ON i.id = c.infrastructure_id
+		WHERE c.infrastructure_id = $1 AND c.change_type = $2
+		ORDER BY c.changed_at DESC, c.id
+		LIMIT 1`
+
+	changes, err := r.queryChanges(ctx, query, id, changeType)
+	if err != nil || len(changes) == 0 {
+		return nil, err
+	}
+	return changes[0], nil
+}
+
+// ListRecentChanges retrieves the latest changes to an organization's infrastructure, newest first
+func (r *InfrastructureRepository) ListRecentChanges(ctx context.Context, orgID string, limit int) ([]*models.InfrastructureChange, error) {
+	query := `
+		SELECT c.id, c.infrastructure_id, i.name, i.provider, c.change_type, c.changes, c.message, c.changed_at
+		FROM infrastructure_changes c
+		JOIN infrastructure i ON i.id = c.infrastructure_id
+		WHERE c.organization_id = $1
+		ORDER BY c.changed_at DESC, c.id
+		LIMIT $2`
+
+	return r.queryChanges(ctx, query, orgID, limit)
+}
+
+func (r *InfrastructureRepository) queryChanges(ctx context.Context, query string, args ...interface{}) ([]*models.InfrastructureChange, error) {
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list infrastructure changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*models.InfrastructureChange
+	for rows.Next() {
+		change := &models.InfrastructureChange{}
+		var changesJSON string
+		if err := rows.Scan(&change.ID, &change.InfrastructureID, &change.ResourceName, &change.Provider,
+			&change.ChangeType, &changesJSON, &change.Message, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan infrastructure change row: %w", err)
+		}
+		if err := json.Unmarshal([]byte(changesJSON), &change.Changes); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal infrastructure change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating infrastructure change rows: %w", err)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"cloudweave/internal/models"
+)
+
+// InfrastructureSnapshotRepository handles infrastructure snapshot data operations
+type InfrastructureSnapshotRepository struct {
+	db *sql.DB
+}
+
+// NewInfrastructureSnapshotRepository creates a new infrastructure snapshot repository
+func NewInfrastructureSnapshotRepository(db *sql.DB) *InfrastructureSnapshotRepository {
+	return &InfrastructureSnapshotRepository{db: db}
+}
+
+const infrastructureSnapshotColumns = `id, organization_id, infrastructure_id, provider, region, external_id, name,
+		       status, size_gb, metadata, created_by, created_at, updated_at`
+
+// Create records a new snapshot
+func (r *InfrastructureSnapshotRepository) Create(ctx context.Context, snapshot *models.InfrastructureSnapshot) error {
+	metadataJSON, err := json.Marshal(snapshot.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO infrastructure_snapshots (id, organization_id, infrastructure_id, provider, region,
+		                                      external_id, name, status, size_gb, metadata, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at, updated_at`
+
+	err = r.db.QueryRowContext(ctx, query,
+		snapshot.ID, snapshot.OrganizationID, snapshot.InfrastructureID, snapshot.Provider, snapshot.Region,
+		snapshot.ExternalID, snapshot.Name, snapshot.Status, snapshot.SizeGB, metadataJSON, snapshot.CreatedBy,
+	).Scan(&snapshot.CreatedAt, &snapshot.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create infrastructure snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a snapshot of an organization, returning sql.ErrNoRows when there is none
+func (r *InfrastructureSnapshotRepository) GetByID(ctx context.Context, orgID, id string) (*models.InfrastructureSnapshot, error) {
+	query := `
+		SELECT ` + infrastructureSnapshotColumns + `
+		FROM infrastructure_snapshots
+		WHERE id = $1 AND organization_id = $2`
+
+	snapshot, err := scanInfrastructureSnapshot(r.db.QueryRowContext(ctx, query, id, orgID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get infrastructure snapshot: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+// ListByInfrastructure retrieves the snapshots taken of a resource, newest first
+func (r *InfrastructureSnapshotRepository) ListByInfrastructure(ctx context.Context, orgID, infrastructureID string) ([]*models.InfrastructureSnapshot, error) {
+	query := `
+		SELECT ` + infrastructureSnapshotColumns + `
+		FROM infrastructure_snapshots
+		WHERE infrastructure_id = $1 AND organization_id = $2
+		ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, infrastructureID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list infrastructure snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	snapshots := []*models.InfrastructureSnapshot{}
+	for rows.Next() {
+		snapshot, err := scanInfrastructureSnapshot(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan infrastructure snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
+
+// UpdateStatus records a snapshot's status and size as its provider last reported them
+func (r *InfrastructureSnapshotRepository) UpdateStatus(ctx context.Context, id, status string, sizeGB *float64) error {
+	query := `
+		UPDATE infrastructure_snapshots
+		SET status = $2, size_gb = COALESCE($3, size_gb), updated_at = NOW()
+		WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, status, sizeGB); err != nil {
+		return fmt.Errorf("failed to update infrastructure snapshot status: %w", err)
+	}
+
+	return nil
+}
+
+// infrastructureSnapshotScanner is satisfied by both *sql.Row and *sql.Rows
+type infrastructureSnapshotScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanInfrastructureSnapshot scans a row selected with infrastructureSnapshotColumns
+func scanInfrastructureSnapshot(row infrastructureSnapshotScanner) (*models.InfrastructureSnapshot, error) {
+	snapshot := &models.InfrastructureSnapshot{}
+	var metadataJSON []byte
+	err := row.Scan(
+		&snapshot.ID,
+		&snapshot.OrganizationID,
+		&snapshot.InfrastructureID,
+		&snapshot.Provider,
+		&snapshot.Region,
+		&snapshot.ExternalID,
+		&snapshot.Name,
+		&snapshot.Status,
+		&snapshot.SizeGB,
+		&metadataJSON,
+		&snapshot.CreatedBy,
+		&snapshot.CreatedAt,
+		&snapshot.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &snapshot.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal snapshot metadata: %w", err)
+		}
+	}
+
+	return snapshot, nil
+}
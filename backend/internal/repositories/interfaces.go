@@ -13,8 +13,15 @@ type UserRepositoryInterface interface {
 	Create(ctx context.Context, user *models.User) error
 	GetByID(ctx context.Context, id string) (*models.User, error)
 	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetBySSOProviderSubject(ctx context.Context, provider, subject string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	UpdateLastLogin(ctx context.Context, userID string) error
+	UpdateSSOInfo(ctx context.Context, userID, provider, subject string) error
+	GetMFA(ctx context.Context, userID string) (*models.UserMFA, error)
+	UpdateMFA(ctx context.Context, mfa *models.UserMFA) error
+	UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error)
+	RecordMFAFailure(ctx context.Context, userID string, maxAttempts int, lockout time.Duration) (bool, error)
+	ResetMFAFailures(ctx context.Context, userID string) error
 	UpdatePassword(ctx context.Context, userID, passwordHash string) error
 	UpdatePreferences(ctx context.Context, userID string, preferences map[string]interface{}) error
 	UpdateDemoSettings(ctx context.Context, userID string, demoMode bool, demoScenario string) error
@@ -34,6 +41,8 @@ type OrganizationRepositoryInterface interface {
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, params ListParams) ([]*models.Organization, error)
 	SlugExists(ctx context.Context, slug string) (bool, error)
+	ListUserMemberships(ctx context.Context, userID string) ([]*models.OrganizationMembership, error)
+	GetUserMembership(ctx context.Context, userID, organizationID string) (*models.OrganizationMembership, error)
 }
 
 // InfrastructureRepositoryInterface defines the contract for infrastructure data operations
@@ -42,11 +51,52 @@ type InfrastructureRepositoryInterface interface {
 	GetByID(ctx context.Context, id string) (*models.Infrastructure, error)
 	Update(ctx context.Context, infra *models.Infrastructure) error
 	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id, orgID string, deletedSince time.Time) error
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int64, error)
+	FailStalePending(ctx context.Context, createdBefore time.Time) ([]string, error)
 	List(ctx context.Context, orgID string, params ListParams) ([]*models.Infrastructure, error)
 	ListByProvider(ctx context.Context, orgID, provider string, params ListParams) ([]*models.Infrastructure, error)
 	ListByStatus(ctx context.Context, orgID, status string, params ListParams) ([]*models.Infrastructure, error)
 	UpdateStatus(ctx context.Context, id, status string) error
 	GetByExternalID(ctx context.Context, externalID string) (*models.Infrastructure, error)
+	GetVersion(ctx context.Context, orgID string) (int, *time.Time, error)
+	ReplaceTags(ctx context.Context, id, orgID string, keys, tags []string) error
+	ExistsByExternalID(ctx context.Context, orgID, provider, externalID string) (bool, error)
+	RemoveTags(ctx context.Context, id, orgID string, exact, keys []string) error
+	ListStatusHistory(ctx context.Context, orgID string, since time.Time) (map[string][]*models.InfrastructureStatusChange, error)
+	RecordChange(ctx context.Context, id, changeType string, changes map[string]models.InfrastructureFieldChange, message *string) error
+	ListChanges(ctx context.Context, id string, limit, offset int) ([]*models.InfrastructureChange, error)
+	GetLatestChange(ctx context.Context, id, changeType string) (*models.InfrastructureChange, error)
+	ListRecentChanges(ctx context.Context, orgID string, limit int) ([]*models.InfrastructureChange, error)
+}
+
+// StatSnapshotRepositoryInterface defines the contract for statistic snapshot data operations
+type StatSnapshotRepositoryInterface interface {
+	Save(ctx context.Context, snapshot *models.StatSnapshot) error
+	GetLatestOnOrBefore(ctx context.Context, orgID string, day time.Time) (map[string]float64, error)
+	GetLatestDayOnOrBefore(ctx context.Context, orgID string, day time.Time) (*time.Time, error)
+}
+
+// CostSnapshotRepositoryInterface defines the contract for daily cost snapshot data operations
+type CostSnapshotRepositoryInterface interface {
+	Save(ctx context.Context, snapshot *models.CostSnapshot) error
+	ListBetween(ctx context.Context, orgID string, from, to time.Time) ([]*models.CostSnapshot, error)
+	SaveResource(ctx context.Context, snapshot *models.ResourceCostSnapshot) error
+	ListResourceBetween(ctx context.Context, infrastructureID string, from, to time.Time) ([]*models.ResourceCostSnapshot, error)
+}
+
+// IdempotencyKeyRepositoryInterface defines the contract for idempotency key data operations
+type IdempotencyKeyRepositoryInterface interface {
+	Reserve(ctx context.Context, orgID, key, requestHash string, expiresAt time.Time) (bool, error)
+	Get(ctx context.Context, orgID, key string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, orgID, key string, statusCode int, contentType string, body []byte) error
+	Delete(ctx context.Context, orgID, key string) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// SearchRepositoryInterface defines the contract for full-text search across resources
+type SearchRepositoryInterface interface {
+	Search(ctx context.Context, orgID, resultType string, terms []string, limit int) ([]*models.SearchResult, error)
 }
 
 // DeploymentRepositoryInterface defines the contract for deployment data operations
@@ -60,6 +110,53 @@ type DeploymentRepositoryInterface interface {
 	ListByStatus(ctx context.Context, orgID, status string, params ListParams) ([]*models.Deployment, error)
 	UpdateStatus(ctx context.Context, id, status string) error
 	UpdateProgress(ctx context.Context, id string, progress int) error
+	ListLatestByApplication(ctx context.Context, orgID string) ([]*models.Deployment, error)
+	ListScheduled(ctx context.Context, orgID string) ([]*models.Deployment, error)
+	TransitionStatus(ctx context.Context, id, from, to string) (bool, error)
+}
+
+// DeploymentRollbackRepositoryInterface defines the contract for deployment rollback history data operations
+type DeploymentRollbackRepositoryInterface interface {
+	Create(ctx context.Context, rollback *models.DeploymentRollback) error
+	ListByDeployment(ctx context.Context, deploymentID string) ([]*models.DeploymentRollback, error)
+}
+
+// InfrastructureSnapshotRepositoryInterface defines the contract for infrastructure snapshot data operations
+type InfrastructureSnapshotRepositoryInterface interface {
+	Create(ctx context.Context, snapshot *models.InfrastructureSnapshot) error
+	GetByID(ctx context.Context, orgID, id string) (*models.InfrastructureSnapshot, error)
+	ListByInfrastructure(ctx context.Context, orgID, infrastructureID string) ([]*models.InfrastructureSnapshot, error)
+	UpdateStatus(ctx context.Context, id, status string, sizeGB *float64) error
+}
+
+// DeploymentHealthCheckRepositoryInterface defines the contract for deployment health check data operations
+type DeploymentHealthCheckRepositoryInterface interface {
+	Create(ctx context.Context, check *models.DeploymentHealthCheck) error
+	ListByDeployment(ctx context.Context, deploymentID string, limit int) ([]*models.DeploymentHealthCheck, error)
+}
+
+// DeploymentLockRepositoryInterface defines the contract for deployment lock data operations
+type DeploymentLockRepositoryInterface interface {
+	Acquire(ctx context.Context, lock *models.DeploymentLock) (bool, error)
+	Get(ctx context.Context, orgID, application, environment string) (*models.DeploymentLock, error)
+	Renew(ctx context.Context, deploymentID string, expiresAt time.Time) error
+	Release(ctx context.Context, deploymentID string) error
+}
+
+// EnvironmentRepositoryInterface defines the contract for custom environment data operations
+type EnvironmentRepositoryInterface interface {
+	Create(ctx context.Context, environment *models.Environment) error
+	List(ctx context.Context, orgID string) ([]*models.Environment, error)
+	Delete(ctx context.Context, id, orgID string) error
+}
+
+// PipelineRepositoryInterface defines the contract for pipeline data operations
+type PipelineRepositoryInterface interface {
+	Create(ctx context.Context, pipeline *models.Pipeline) error
+	GetByID(ctx context.Context, id, orgID string) (*models.Pipeline, error)
+	List(ctx context.Context, orgID string, params ListParams) ([]*models.Pipeline, error)
+	Update(ctx context.Context, pipeline *models.Pipeline) error
+	Delete(ctx context.Context, id, orgID string) error
 }
 
 // MetricRepositoryInterface defines the contract for metric data operations
@@ -71,6 +168,11 @@ type MetricRepositoryInterface interface {
 	Delete(ctx context.Context, id string) error
 	DeleteOlderThan(ctx context.Context, cutoffTime string) error
 	GetLatestByResource(ctx context.Context, resourceID, metricName string) (*models.Metric, error)
+	RollupHourly(ctx context.Context, since, until time.Time) (int64, error)
+	RollupDaily(ctx context.Context, since, until time.Time) (int64, error)
+	GetLatestRollupBucket(ctx context.Context, resolution string) (*time.Time, error)
+	QueryRollups(ctx context.Context, resolution, resourceID string, startTime, endTime time.Time) ([]*models.MetricRollup, error)
+	AggregateHourly(ctx context.Context, resourceID string, startTime, endTime time.Time) ([]*models.MetricRollup, error)
 }
 
 // AlertRepositoryInterface defines the contract for alert data operations
@@ -83,13 +185,85 @@ type AlertRepositoryInterface interface {
 	Query(ctx context.Context, orgID string, query models.AlertQuery) ([]*models.Alert, error)
 	Acknowledge(ctx context.Context, id, userID string) error
 	ListUnacknowledged(ctx context.Context, orgID string, params ListParams) ([]*models.Alert, error)
+	ListForUpdateTx(ctx context.Context, tx *sql.Tx, orgID string, ids []string) ([]*models.Alert, error)
+	UpdateAcknowledgementTx(ctx context.Context, tx *sql.Tx, alert *models.Alert) error
+	ListEnvironmentOutages(ctx context.Context, orgID string, since time.Time) ([]*models.EnvironmentOutage, error)
+}
+
+// AlertEventRepositoryInterface defines the contract for alert event data operations
+type AlertEventRepositoryInterface interface {
+	Create(ctx context.Context, event *models.AlertEvent) error
+	CreateTx(ctx context.Context, tx *sql.Tx, event *models.AlertEvent) error
+	ListByAlert(ctx context.Context, alertID string) ([]*models.AlertEvent, error)
+}
+
+// NotificationChannelRepositoryInterface defines the contract for notification channel and
+// delivery data operations
+type NotificationChannelRepositoryInterface interface {
+	Create(ctx context.Context, channel *models.NotificationChannel) error
+	GetByID(ctx context.Context, id, orgID string) (*models.NotificationChannel, error)
+	List(ctx context.Context, orgID string) ([]*models.NotificationChannel, error)
+	ListEnabled(ctx context.Context, orgID string) ([]*models.NotificationChannel, error)
+	Update(ctx context.Context, channel *models.NotificationChannel) error
+	Delete(ctx context.Context, id, orgID string) error
+	CreateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
+	UpdateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error
+	ListDeliveries(ctx context.Context, orgID, alertID string, limit int) ([]*models.NotificationDelivery, error)
+}
+
+// WebhookRepositoryInterface defines the contract for webhook and webhook delivery data operations
+type WebhookRepositoryInterface interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByID(ctx context.Context, id, orgID string) (*models.Webhook, error)
+	List(ctx context.Context, orgID string) ([]*models.Webhook, error)
+	ListEnabled(ctx context.Context, orgID string) ([]*models.Webhook, error)
+	Delete(ctx context.Context, id, orgID string) error
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, orgID, webhookID, status string, limit int) ([]*models.WebhookDelivery, error)
+}
+
+// CostAllocationRepositoryInterface defines the contract for cost allocation settings data operations
+type CostAllocationRepositoryInterface interface {
+	Get(ctx context.Context, orgID string) (*models.CostAllocationSettings, error)
+	Save(ctx context.Context, settings *models.CostAllocationSettings) error
+}
+
+// RetentionRepositoryInterface defines the contract for data retention settings and purge operations
+type RetentionRepositoryInterface interface {
+	Get(ctx context.Context, orgID string) (*models.RetentionSettings, error)
+	Save(ctx context.Context, settings *models.RetentionSettings) error
+	CountExpiredMetrics(ctx context.Context, resolution, orgID string, cutoff time.Time) (int64, error)
+	DeleteExpiredMetrics(ctx context.Context, resolution string, defaultDays int, notAfter time.Time, limit int) (int64, error)
+	CountExpiredAlerts(ctx context.Context, orgID string, cutoff time.Time) (int64, error)
+	DeleteExpiredAlerts(ctx context.Context, defaultDays, limit int) (int64, error)
+}
+
+// QuotaRepositoryInterface defines the contract for organization quota data operations
+type QuotaRepositoryInterface interface {
+	Get(ctx context.Context, orgID string) (*models.OrganizationQuota, error)
+	Save(ctx context.Context, quota *models.OrganizationQuota) error
+	Delete(ctx context.Context, orgID string) error
+}
+
+// AlertRuleRepositoryInterface defines the contract for alert rule data operations
+type AlertRuleRepositoryInterface interface {
+	Create(ctx context.Context, rule *models.AlertRule) error
+	List(ctx context.Context, orgID string) ([]*models.AlertRule, error)
+	ListEnabledForResource(ctx context.Context, orgID, resourceType string) ([]*models.AlertRule, error)
+	Delete(ctx context.Context, id, orgID string) error
+	GetState(ctx context.Context, ruleID, resourceID string) (*models.AlertRuleState, error)
+	SaveState(ctx context.Context, state *models.AlertRuleState) error
+	DeleteState(ctx context.Context, ruleID, resourceID string) error
 }
 
 // AuditLogRepositoryInterface defines the contract for audit log data operations
 type AuditLogRepositoryInterface interface {
 	Create(ctx context.Context, log *models.AuditLog) error
+	CreateBatch(ctx context.Context, logs []*models.AuditLog) error
 	GetByID(ctx context.Context, id string) (*models.AuditLog, error)
 	Query(ctx context.Context, orgID string, query models.AuditLogQuery) ([]*models.AuditLog, error)
+	Count(ctx context.Context, orgID string, query models.AuditLogQuery) (int, error)
 	Delete(ctx context.Context, id string) error
 	DeleteOlderThan(ctx context.Context, cutoffTime string) error
 	GetActionSummary(ctx context.Context, orgID string, startTime, endTime time.Time) (map[string]int, error)
@@ -104,16 +278,42 @@ type SecurityScanRepositoryInterface interface {
 	Delete(ctx context.Context, id string) error
 	List(ctx context.Context, orgID string, limit, offset int) ([]*models.SecurityScan, int, error)
 	GetRecentCount(ctx context.Context, orgID string, days int) (int, error)
+	GetLatestForDeploymentVersion(ctx context.Context, orgID, application, version string) (*models.SecurityScan, error)
+}
+
+// JobRepositoryInterface defines the contract for background job data operations
+type JobRepositoryInterface interface {
+	Create(ctx context.Context, job *models.Job) (bool, error)
+	GetByID(ctx context.Context, orgID, id string) (*models.Job, error)
+	GetIncomplete(ctx context.Context, orgID, jobType string) (*models.Job, error)
+	ClaimNext(ctx context.Context, workerID string, lease time.Duration) (*models.Job, error)
+	RenewLease(ctx context.Context, id, workerID string, lease time.Duration) (bool, error)
+	UpdateProgress(ctx context.Context, id string, progress int) error
+	Complete(ctx context.Context, id string, result map[string]interface{}) error
+	Fail(ctx context.Context, id, message string) error
+	RequeueExpired(ctx context.Context, maxAttempts int) (requeued, failed int64, err error)
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SBOMRepositoryInterface defines the contract for deployment SBOM data operations
+type SBOMRepositoryInterface interface {
+	GetByDeployment(ctx context.Context, deploymentID string, format models.SBOMFormat) (*models.DeploymentSBOM, error)
+	Create(ctx context.Context, sbom *models.DeploymentSBOM) error
 }
 
 // VulnerabilityRepositoryInterface defines the contract for vulnerability data operations
 type VulnerabilityRepositoryInterface interface {
 	Create(ctx context.Context, vulnerability *models.Vulnerability) error
 	GetByID(ctx context.Context, orgID, id string) (*models.Vulnerability, error)
+	RecordFinding(ctx context.Context, vulnerability *models.Vulnerability) (*models.VulnerabilityStatus, error)
+	ResolveUnseen(ctx context.Context, scan *models.SecurityScan, resolvedAt time.Time, reason string) (int64, error)
+	RecordStatusChange(ctx context.Context, change *models.VulnerabilityStatusChange) error
+	GetStatusChanges(ctx context.Context, orgID, vulnerabilityID string) ([]*models.VulnerabilityStatusChange, error)
 	Update(ctx context.Context, vulnerability *models.Vulnerability) error
 	Delete(ctx context.Context, id string) error
 	Query(ctx context.Context, orgID string, query models.VulnerabilityQuery) ([]*models.Vulnerability, int, error)
 	GetCountsBySeverity(ctx context.Context, orgID string) (map[models.VulnerabilitySeverity]int, error)
+	GetScanCountsBySeverity(ctx context.Context, orgID, scanID string, status *models.VulnerabilityStatus) (map[models.VulnerabilitySeverity]int, error)
 	GetCountsByStatus(ctx context.Context, orgID string) (map[models.VulnerabilityStatus]int, error)
 }
 
@@ -129,6 +329,7 @@ type ComplianceFrameworkRepositoryInterface interface {
 // ComplianceControlRepositoryInterface defines the contract for compliance control data operations
 type ComplianceControlRepositoryInterface interface {
 	Create(ctx context.Context, control *models.ComplianceControl) error
+	CreateManyTx(ctx context.Context, tx *sql.Tx, controls []*models.ComplianceControl) (int, error)
 	GetByID(ctx context.Context, controlID string) (*models.ComplianceControl, error)
 	ListByFramework(ctx context.Context, frameworkID string, limit, offset int) ([]*models.ComplianceControl, int, error)
 	Update(ctx context.Context, control *models.ComplianceControl) error
@@ -141,9 +342,29 @@ type ComplianceAssessmentRepositoryInterface interface {
 	Create(ctx context.Context, assessment *models.ComplianceAssessment) error
 	GetByID(ctx context.Context, organizationID, assessmentID string) (*models.ComplianceAssessment, error)
 	List(ctx context.Context, organizationID string, limit, offset int) ([]*models.ComplianceAssessment, int, error)
+	ListCompletedSince(ctx context.Context, organizationID string, since time.Time, framework string) ([]*models.ComplianceAssessment, error)
 	Update(ctx context.Context, assessment *models.ComplianceAssessment) error
 }
 
+// ComplianceReportRepositoryInterface defines the contract for compliance report data operations
+type ComplianceReportRepositoryInterface interface {
+	Create(ctx context.Context, report *models.ComplianceReport) error
+	GetByID(ctx context.Context, organizationID, reportID string) (*models.ComplianceReport, error)
+	List(ctx context.Context, organizationID string, limit, offset int) ([]*models.ComplianceReport, int, error)
+	UpdateDelivery(ctx context.Context, reportID string, deliveredAt *time.Time, deliveryError *string) error
+}
+
+// ComplianceReportScheduleRepositoryInterface defines the contract for compliance report schedule data operations
+type ComplianceReportScheduleRepositoryInterface interface {
+	Create(ctx context.Context, schedule *models.ComplianceReportSchedule) error
+	GetByID(ctx context.Context, organizationID, scheduleID string) (*models.ComplianceReportSchedule, error)
+	List(ctx context.Context, organizationID string) ([]*models.ComplianceReportSchedule, error)
+	ListDue(ctx context.Context, now time.Time) ([]*models.ComplianceReportSchedule, error)
+	Update(ctx context.Context, schedule *models.ComplianceReportSchedule) error
+	ClaimRun(ctx context.Context, scheduleID string, dueAt, nextRunAt time.Time) (bool, error)
+	Delete(ctx context.Context, organizationID, scheduleID string) error
+}
+
 // RoleRepositoryInterface defines the contract for role data operations
 type RoleRepositoryInterface interface {
 	Create(ctx context.Context, role *models.Role) error
@@ -159,15 +380,17 @@ type RoleRepositoryInterface interface {
 type UserRoleRepositoryInterface interface {
 	AssignRole(ctx context.Context, userRole *models.UserRole) error
 	RemoveRole(ctx context.Context, userID, roleID, organizationID string) error
-	GetUserRoles(ctx context.Context, userID, organizationID string) ([]*models.UserRole, error)
+	GetUserRoles(ctx context.Context, userID, organizationID string, includeExpired bool) ([]*models.UserRole, error)
 	GetRoleUsers(ctx context.Context, roleID, organizationID string) ([]*models.UserRole, error)
 	IsUserInRole(ctx context.Context, userID, roleID, organizationID string) (bool, error)
 	GetUserPermissions(ctx context.Context, userID, organizationID string) (*models.UserPermissions, error)
+	DeactivateExpired(ctx context.Context) ([]*models.UserRole, error)
 }
 
 // ResourcePermissionRepositoryInterface defines the contract for resource permission operations
 type ResourcePermissionRepositoryInterface interface {
 	Grant(ctx context.Context, permission *models.ResourcePermission) error
+	GetByID(ctx context.Context, organizationID, id string) (*models.ResourcePermission, error)
 	Revoke(ctx context.Context, userID, resourceType, resourceID, organizationID string) error
 	GetUserResourcePermissions(ctx context.Context, userID, organizationID string) ([]*models.ResourcePermission, error)
 	GetResourcePermissions(ctx context.Context, resourceType, resourceID, organizationID string) ([]*models.ResourcePermission, error)
@@ -209,6 +432,13 @@ type ListParams struct {
 	SortBy string
 	Order  string // "asc" or "desc"
 	Search string
+	// IncludeDeleted includes soft-deleted rows in repositories that soft-delete
+	IncludeDeleted bool
+	// Cursor, when set, pages newest first by keyset on (created_at, id) instead of by Offset,
+	// ignoring SortBy and Order
+	Cursor string
+	// Tags, when set, restricts repositories that support tagging to rows carrying every tag
+	Tags []string
 }
 
 // DefaultListParams returns default list parameters
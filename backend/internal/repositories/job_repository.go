@@ -0,0 +1,256 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// jobColumns are the columns scanJob reads, in order
+const jobColumns = `id, organization_id, user_id, type, status, exclusive, payload, progress, result, error,
+		attempts, created_at, started_at, completed_at, updated_at`
+
+// JobRepository handles background job data operations
+type JobRepository struct {
+	db *sql.DB
+}
+
+// NewJobRepository creates a new job repository
+func NewJobRepository(db *sql.DB) *JobRepository {
+	return &JobRepository{db: db}
+}
+
+// Create stores a queued job. It reports false, without storing the job, when the job is
+// exclusive and the organization already has an incomplete job of its type.
+func (r *JobRepository) Create(ctx context.Context, job *models.Job) (bool, error) {
+	payloadJSON, err := json.Marshal(job.Payload)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	query := `
+		INSERT INTO jobs (id, organization_id, user_id, type, status, exclusive, payload, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8)
+		ON CONFLICT (organization_id, type) WHERE exclusive AND status IN ('queued', 'running') DO NOTHING`
+
+	result, err := r.db.ExecContext(ctx, query,
+		job.ID, job.OrganizationID, job.UserID, job.Type, job.Status, job.Exclusive, payloadJSON, job.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to create job: %w", err)
+	}
+	job.UpdatedAt = job.CreatedAt
+	return rows > 0, nil
+}
+
+// GetByID retrieves one of an organization's jobs, or nil if it does not exist
+func (r *JobRepository) GetByID(ctx context.Context, orgID, id string) (*models.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1 AND organization_id = $2`
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, id, orgID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+
+	return job, nil
+}
+
+// GetIncomplete retrieves an organization's queued or running job of a type, or nil if it has none
+func (r *JobRepository) GetIncomplete(ctx context.Context, orgID, jobType string) (*models.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE organization_id = $1 AND type = $2 AND status IN ('queued', 'running')
+		ORDER BY created_at
+		LIMIT 1`
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, orgID, jobType))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get incomplete job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ClaimNext marks the oldest queued job running under a lease held by the worker and returns it,
+// or nil if no job is queued. Jobs claimed by other workers are skipped rather than waited on.
+func (r *JobRepository) ClaimNext(ctx context.Context, workerID string, lease time.Duration) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, started_at = NOW(), updated_at = NOW(),
+		    worker_id = $1, lease_expires_at = NOW() + make_interval(secs => $2)
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE status = 'queued'
+			ORDER BY created_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING ` + jobColumns
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, workerID, lease.Seconds()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+
+	return job, nil
+}
+
+// RenewLease extends the lease a worker holds on a running job. It reports false when the worker
+// no longer holds the lease, such as when the job was requeued after the lease expired.
+func (r *JobRepository) RenewLease(ctx context.Context, id, workerID string, lease time.Duration) (bool, error) {
+	query := `
+		UPDATE jobs
+		SET lease_expires_at = NOW() + make_interval(secs => $3), updated_at = NOW()
+		WHERE id = $1 AND worker_id = $2 AND status = 'running'`
+
+	result, err := r.db.ExecContext(ctx, query, id, workerID, lease.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to renew job lease: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to renew job lease: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// UpdateProgress records how far a running job has got, as a percentage
+func (r *JobRepository) UpdateProgress(ctx context.Context, id string, progress int) error {
+	query := `UPDATE jobs SET progress = $2, updated_at = NOW() WHERE id = $1 AND status = 'running'`
+
+	if _, err := r.db.ExecContext(ctx, query, id, progress); err != nil {
+		return fmt.Errorf("failed to update job progress: %w", err)
+	}
+
+	return nil
+}
+
+// Complete marks a running job completed with its result
+func (r *JobRepository) Complete(ctx context.Context, id string, result map[string]interface{}) error {
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job result: %w", err)
+	}
+
+	query := `
+		UPDATE jobs
+		SET status = 'completed', progress = 100, result = $2, completed_at = NOW(), updated_at = NOW()
+		WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, resultJSON); err != nil {
+		return fmt.Errorf("failed to complete job: %w", err)
+	}
+
+	return nil
+}
+
+// Fail marks a job failed with the reason
+func (r *JobRepository) Fail(ctx context.Context, id, message string) error {
+	query := `
+		UPDATE jobs
+		SET status = 'failed', error = $2, completed_at = NOW(), updated_at = NOW()
+		WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, id, message); err != nil {
+		return fmt.Errorf("failed to fail job: %w", err)
+	}
+
+	return nil
+}
+
+// RequeueExpired returns running jobs whose lease has expired, because the worker running them
+// stopped, to the queue. Jobs that have already been attempted maxAttempts times are failed
+// instead, so a job that brings the server down is not retried forever.
+func (r *JobRepository) RequeueExpired(ctx context.Context, maxAttempts int) (requeued, failed int64, err error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = 'failed', error = 'interrupted too many times', completed_at = NOW(), updated_at = NOW(),
+		    worker_id = NULL, lease_expires_at = NULL
+		WHERE status = 'running' AND attempts >= $1
+		  AND (lease_expires_at IS NULL OR lease_expires_at < NOW())`, maxAttempts)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to fail interrupted jobs: %w", err)
+	}
+	if failed, err = result.RowsAffected(); err != nil {
+		return 0, 0, fmt.Errorf("failed to fail interrupted jobs: %w", err)
+	}
+
+	result, err = r.db.ExecContext(ctx, `
+		UPDATE jobs
+		SET status = 'queued', progress = 0, started_at = NULL, updated_at = NOW(),
+		    worker_id = NULL, lease_expires_at = NULL
+		WHERE status = 'running' AND (lease_expires_at IS NULL OR lease_expires_at < NOW())`)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to requeue interrupted jobs: %w", err)
+	}
+	if requeued, err = result.RowsAffected(); err != nil {
+		return 0, 0, fmt.Errorf("failed to requeue interrupted jobs: %w", err)
+	}
+
+	return requeued, failed, nil
+}
+
+// DeleteFinishedBefore removes completed and failed jobs that finished before a time and returns
+// how many were removed
+func (r *JobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := `DELETE FROM jobs WHERE status IN ('completed', 'failed') AND completed_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete finished jobs: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// jobScanner is satisfied by both *sql.Row and *sql.Rows
+type jobScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanJob scans a row selected with jobColumns
+func scanJob(row jobScanner) (*models.Job, error) {
+	job := &models.Job{}
+	var payloadJSON, resultJSON []byte
+	err := row.Scan(
+		&job.ID, &job.OrganizationID, &job.UserID, &job.Type, &job.Status, &job.Exclusive,
+		&payloadJSON, &job.Progress, &resultJSON, &job.Error,
+		&job.Attempts, &job.CreatedAt, &job.StartedAt, &job.CompletedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payloadJSON) > 0 {
+		if err := json.Unmarshal(payloadJSON, &job.Payload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job payload: %w", err)
+		}
+	}
+	if len(resultJSON) > 0 {
+		if err := json.Unmarshal(resultJSON, &job.Result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal job result: %w", err)
+		}
+	}
+
+	return job, nil
+}
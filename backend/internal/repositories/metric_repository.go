@@ -12,6 +12,9 @@ import (
 
 type MetricRepository struct {
 	db *sql.DB
+
+	// replica serves list and query reads when set; see readDB
+	replica *sql.DB
 }
 
 func NewMetricRepository(db *sql.DB) *MetricRepository {
@@ -164,17 +167,17 @@ func (r *MetricRepository) Query(ctx context.Context, query models.MetricQuery)
 	sqlQuery := fmt.Sprintf(`
 		SELECT id, resource_id, resource_type, metric_name, value, unit, tags, timestamp, created_at
 		FROM metrics 
-		%s
-		ORDER BY timestamp DESC
-		LIMIT $%d OFFSET $%d`,
+		%s`,
 		whereClause.String(),
-		argIndex,
-		argIndex+1,
 	)
 
-	args = append(args, limit, offset)
+	// Metrics are read in time order, so cursors key on the sample timestamp rather than created_at
+	sqlQuery, args, err := paginate(sqlQuery, args, query.Cursor, "timestamp", "timestamp DESC, id DESC", limit, offset)
+	if err != nil {
+		return nil, err
+	}
 
-	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query metrics: %w", err)
 	}
@@ -307,7 +310,7 @@ func (r *MetricRepository) GetAggregatedMetrics(ctx context.Context, resourceID,
 		GROUP BY time_bucket
 		ORDER BY time_bucket`, interval)
 
-	rows, err := r.db.QueryContext(ctx, query, resourceID, metricName, startTime, endTime)
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, resourceID, metricName, startTime, endTime)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get aggregated metrics: %w", err)
 	}
@@ -335,3 +338,146 @@ func (r *MetricRepository) GetAggregatedMetrics(ctx context.Context, resourceID,
 
 	return results, nil
 }
+
+// metricRollupTables maps rollup resolutions to the table storing them
+var metricRollupTables = map[string]string{
+	models.MetricResolutionHourly: "metrics_hourly",
+	models.MetricResolutionDaily:  "metrics_daily",
+}
+
+// RollupHourly aggregates raw metrics in [since, until) into hourly buckets. Buckets that
+// were already rolled up are recomputed, so overlapping windows are safe.
+func (r *MetricRepository) RollupHourly(ctx context.Context, since, until time.Time) (int64, error) {
+	query := `
+		INSERT INTO metrics_hourly (resource_id, resource_type, metric_name, unit, bucket,
+		                            min_value, max_value, avg_value, sample_count)
+		SELECT resource_id, resource_type, metric_name, MAX(unit), date_trunc('hour', timestamp),
+		       MIN(value), MAX(value), AVG(value), COUNT(*)
+		FROM metrics
+		WHERE resource_id IS NOT NULL AND timestamp >= $1 AND timestamp < $2
+		GROUP BY resource_id, resource_type, metric_name, date_trunc('hour', timestamp)
+		ON CONFLICT (resource_id, metric_name, bucket) DO UPDATE
+		SET min_value = EXCLUDED.min_value, max_value = EXCLUDED.max_value,
+		    avg_value = EXCLUDED.avg_value, sample_count = EXCLUDED.sample_count`
+
+	result, err := r.db.ExecContext(ctx, query, since, until)
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll up hourly metrics: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// RollupDaily aggregates hourly rollups in [since, until) into daily buckets. Daily buckets are
+// built from the hourly table so they stay correct after raw points are deleted.
+func (r *MetricRepository) RollupDaily(ctx context.Context, since, until time.Time) (int64, error) {
+	query := `
+		INSERT INTO metrics_daily (resource_id, resource_type, metric_name, unit, bucket,
+		                           min_value, max_value, avg_value, sample_count)
+		SELECT resource_id, resource_type, metric_name, MAX(unit), date_trunc('day', bucket),
+		       MIN(min_value), MAX(max_value), SUM(avg_value * sample_count) / SUM(sample_count), SUM(sample_count)
+		FROM metrics_hourly
+		WHERE bucket >= $1 AND bucket < $2
+		GROUP BY resource_id, resource_type, metric_name, date_trunc('day', bucket)
+		ON CONFLICT (resource_id, metric_name, bucket) DO UPDATE
+		SET min_value = EXCLUDED.min_value, max_value = EXCLUDED.max_value,
+		    avg_value = EXCLUDED.avg_value, sample_count = EXCLUDED.sample_count`
+
+	result, err := r.db.ExecContext(ctx, query, since, until)
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll up daily metrics: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// GetLatestRollupBucket returns the most recent bucket rolled up at a resolution, or nil if
+// nothing has been rolled up yet
+func (r *MetricRepository) GetLatestRollupBucket(ctx context.Context, resolution string) (*time.Time, error) {
+	table, ok := metricRollupTables[resolution]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metric resolution %s", resolution)
+	}
+
+	var bucket sql.NullTime
+	if err := r.db.QueryRowContext(ctx, `SELECT MAX(bucket) FROM `+table).Scan(&bucket); err != nil {
+		return nil, fmt.Errorf("failed to get latest rollup bucket: %w", err)
+	}
+
+	if !bucket.Valid {
+		return nil, nil
+	}
+	return &bucket.Time, nil
+}
+
+// QueryRollups retrieves a resource's rollups at a resolution within a time range, newest first
+func (r *MetricRepository) QueryRollups(ctx context.Context, resolution, resourceID string, startTime, endTime time.Time) ([]*models.MetricRollup, error) {
+	table, ok := metricRollupTables[resolution]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metric resolution %s", resolution)
+	}
+
+	query := `
+		SELECT id, resource_id, resource_type, metric_name, COALESCE(unit, ''), bucket,
+		       min_value, max_value, avg_value, sample_count
+		FROM ` + table + `
+		WHERE resource_id = $1 AND bucket >= $2 AND bucket <= $3
+		ORDER BY bucket DESC`
+
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, resourceID, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metric rollups: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMetricRollups(rows)
+}
+
+// AggregateHourly aggregates a resource's raw metrics within a time range into hourly buckets,
+// newest first, without storing them. It covers hours the rollup job has not reached yet.
+func (r *MetricRepository) AggregateHourly(ctx context.Context, resourceID string, startTime, endTime time.Time) ([]*models.MetricRollup, error) {
+	query := `
+		SELECT '', resource_id, resource_type, metric_name, COALESCE(MAX(unit), ''), date_trunc('hour', timestamp) AS bucket,
+		       MIN(value), MAX(value), AVG(value), COUNT(*)
+		FROM metrics
+		WHERE resource_id = $1 AND timestamp >= $2 AND timestamp <= $3
+		GROUP BY resource_id, resource_type, metric_name, date_trunc('hour', timestamp)
+		ORDER BY bucket DESC`
+
+	rows, err := readDB(ctx, r.db, r.replica).QueryContext(ctx, query, resourceID, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate hourly metrics: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMetricRollups(rows)
+}
+
+func scanMetricRollups(rows *sql.Rows) ([]*models.MetricRollup, error) {
+	var rollups []*models.MetricRollup
+	for rows.Next() {
+		rollup := &models.MetricRollup{}
+		err := rows.Scan(
+			&rollup.ID,
+			&rollup.ResourceID,
+			&rollup.ResourceType,
+			&rollup.MetricName,
+			&rollup.Unit,
+			&rollup.Bucket,
+			&rollup.Min,
+			&rollup.Max,
+			&rollup.Avg,
+			&rollup.Count,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan metric rollup row: %w", err)
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating metric rollup rows: %w", err)
+	}
+
+	return rollups, nil
+}
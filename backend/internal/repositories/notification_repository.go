@@ -0,0 +1,233 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"cloudweave/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// NotificationChannelRepository handles notification channel and delivery data operations
+type NotificationChannelRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationChannelRepository creates a new notification channel repository
+func NewNotificationChannelRepository(db *sql.DB) *NotificationChannelRepository {
+	return &NotificationChannelRepository{db: db}
+}
+
+const notificationChannelColumns = `id, organization_id, name, type, config, severities, enabled, created_by, created_at, updated_at`
+
+// Create creates a new notification channel
+func (r *NotificationChannelRepository) Create(ctx context.Context, channel *models.NotificationChannel) error {
+	query := `
+		INSERT INTO notification_channels (id, organization_id, name, type, config, severities, enabled, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		channel.ID, channel.OrganizationID, channel.Name, channel.Type, channel.Config,
+		pq.Array(channel.Severities), channel.Enabled, channel.CreatedBy,
+	).Scan(&channel.CreatedAt, &channel.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create notification channel: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a notification channel within an organization
+func (r *NotificationChannelRepository) GetByID(ctx context.Context, id, orgID string) (*models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + ` FROM notification_channels WHERE id = $1 AND organization_id = $2`
+
+	channels, err := r.query(ctx, query, id, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("notification channel with id %s not found", id)
+	}
+
+	return channels[0], nil
+}
+
+// List retrieves an organization's notification channels
+func (r *NotificationChannelRepository) List(ctx context.Context, orgID string) ([]*models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + ` FROM notification_channels WHERE organization_id = $1 ORDER BY name ASC`
+
+	return r.query(ctx, query, orgID)
+}
+
+// ListEnabled retrieves an organization's enabled notification channels
+func (r *NotificationChannelRepository) ListEnabled(ctx context.Context, orgID string) ([]*models.NotificationChannel, error) {
+	query := `SELECT ` + notificationChannelColumns + ` FROM notification_channels WHERE organization_id = $1 AND enabled = true`
+
+	return r.query(ctx, query, orgID)
+}
+
+// Update updates a notification channel within its organization
+func (r *NotificationChannelRepository) Update(ctx context.Context, channel *models.NotificationChannel) error {
+	query := `
+		UPDATE notification_channels
+		SET name = $3, config = $4, severities = $5, enabled = $6
+		WHERE id = $1 AND organization_id = $2
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		channel.ID, channel.OrganizationID, channel.Name, channel.Config, pq.Array(channel.Severities), channel.Enabled,
+	).Scan(&channel.UpdatedAt)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("notification channel with id %s not found", channel.ID)
+		}
+		return fmt.Errorf("failed to update notification channel: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a notification channel within an organization
+func (r *NotificationChannelRepository) Delete(ctx context.Context, id, orgID string) error {
+	query := `DELETE FROM notification_channels WHERE id = $1 AND organization_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification channel with id %s not found", id)
+	}
+
+	return nil
+}
+
+// CreateDelivery records a new notification delivery
+func (r *NotificationChannelRepository) CreateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	query := `
+		INSERT INTO notification_deliveries (id, organization_id, channel_id, alert_id, status, attempts)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		delivery.ID, delivery.OrganizationID, delivery.ChannelID, delivery.AlertID, delivery.Status, delivery.Attempts,
+	).Scan(&delivery.CreatedAt, &delivery.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create notification delivery: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt
+func (r *NotificationChannelRepository) UpdateDelivery(ctx context.Context, delivery *models.NotificationDelivery) error {
+	query := `
+		UPDATE notification_deliveries
+		SET status = $2, attempts = $3, last_error = $4, sent_at = $5
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.LastError, delivery.SentAt,
+	).Scan(&delivery.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to update notification delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries retrieves an organization's most recent notification deliveries, optionally
+// for a single alert
+func (r *NotificationChannelRepository) ListDeliveries(ctx context.Context, orgID, alertID string, limit int) ([]*models.NotificationDelivery, error) {
+	query := `
+		SELECT id, organization_id, channel_id, alert_id, status, attempts, last_error, sent_at, created_at, updated_at
+		FROM notification_deliveries
+		WHERE organization_id = $1 AND ($2 = '' OR alert_id::text = $2)
+		ORDER BY created_at DESC
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, alertID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*models.NotificationDelivery{}
+	for rows.Next() {
+		delivery := &models.NotificationDelivery{}
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.OrganizationID,
+			&delivery.ChannelID,
+			&delivery.AlertID,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.LastError,
+			&delivery.SentAt,
+			&delivery.CreatedAt,
+			&delivery.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification delivery row: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification delivery rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// query runs a query selecting notificationChannelColumns and scans the results
+func (r *NotificationChannelRepository) query(ctx context.Context, query string, args ...interface{}) ([]*models.NotificationChannel, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notification channels: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []*models.NotificationChannel{}
+	for rows.Next() {
+		channel := &models.NotificationChannel{}
+		var severities pq.StringArray
+		err := rows.Scan(
+			&channel.ID,
+			&channel.OrganizationID,
+			&channel.Name,
+			&channel.Type,
+			&channel.Config,
+			&severities,
+			&channel.Enabled,
+			&channel.CreatedBy,
+			&channel.CreatedAt,
+			&channel.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan notification channel row: %w", err)
+		}
+		channel.Severities = []string(severities)
+		channels = append(channels, channel)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification channel rows: %w", err)
+	}
+
+	return channels, nil
+}
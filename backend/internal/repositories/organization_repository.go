@@ -258,6 +258,55 @@ func (r *OrganizationRepository) SlugExists(ctx context.Context, slug string) (b
 	return exists, nil
 }
 
+// userMembershipsQuery selects the organizations a user belongs to: their home organization, with
+// their own role, and the organizations they were added to as members
+const userMembershipsQuery = `
+	SELECT o.id, o.name, o.slug, m.role, m.is_home, m.created_at
+	FROM (
+		SELECT organization_id, role, true AS is_home, created_at
+		FROM users WHERE id = $1 AND organization_id IS NOT NULL
+		UNION ALL
+		SELECT om.organization_id, om.role, false, om.created_at
+		FROM organization_memberships om
+		JOIN users u ON u.id = om.user_id
+		WHERE om.user_id = $1 AND om.organization_id IS DISTINCT FROM u.organization_id
+	) m
+	JOIN organizations o ON o.id = m.organization_id`
+
+// ListUserMemberships retrieves the organizations a user belongs to, home organization first
+func (r *OrganizationRepository) ListUserMemberships(ctx context.Context, userID string) ([]*models.OrganizationMembership, error) {
+	rows, err := r.db.QueryContext(ctx, userMembershipsQuery+` ORDER BY m.is_home DESC, o.name`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization memberships: %w", err)
+	}
+	defer rows.Close()
+
+	memberships := []*models.OrganizationMembership{}
+	for rows.Next() {
+		var membership models.OrganizationMembership
+		if err := rows.Scan(&membership.OrganizationID, &membership.OrganizationName, &membership.OrganizationSlug,
+			&membership.Role, &membership.IsHome, &membership.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan organization membership: %w", err)
+		}
+		memberships = append(memberships, &membership)
+	}
+
+	return memberships, rows.Err()
+}
+
+// GetUserMembership retrieves a user's membership of an organization
+func (r *OrganizationRepository) GetUserMembership(ctx context.Context, userID, organizationID string) (*models.OrganizationMembership, error) {
+	var membership models.OrganizationMembership
+	err := r.db.QueryRowContext(ctx, userMembershipsQuery+` WHERE m.organization_id = $2`, userID, organizationID).Scan(
+		&membership.OrganizationID, &membership.OrganizationName, &membership.OrganizationSlug,
+		&membership.Role, &membership.IsHome, &membership.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization membership: %w", err)
+	}
+
+	return &membership, nil
+}
+
 // GenerateSlug generates a URL-friendly slug from an organization name
 func GenerateSlug(name string) string {
 	// Convert to lowercase
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"cloudweave/internal/models"
+)
+
+// PipelineRepository handles pipeline data operations
+type PipelineRepository struct {
+	db *sql.DB
+}
+
+// NewPipelineRepository creates a new pipeline repository
+func NewPipelineRepository(db *sql.DB) *PipelineRepository {
+	return &PipelineRepository{db: db}
+}
+
+const pipelineColumns = `id, organization_id, name, repository, branch, status, stages,
+		       last_run, next_run, created_by, created_at, updated_at`
+
+// Create creates a new pipeline
+func (r *PipelineRepository) Create(ctx context.Context, pipeline *models.Pipeline) error {
+	stagesJSON, err := json.Marshal(pipeline.Stages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stages: %w", err)
+	}
+
+	query := `
+		INSERT INTO pipelines (id, organization_id, name, repository, branch, status, stages, next_run, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at, updated_at`
+
+	err = r.db.QueryRowContext(ctx, query,
+		pipeline.ID, pipeline.OrganizationID, pipeline.Name, pipeline.Repository, pipeline.Branch,
+		pipeline.Status, stagesJSON, pipeline.NextRun, pipeline.CreatedBy,
+	).Scan(&pipeline.CreatedAt, &pipeline.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create pipeline: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a pipeline by ID within an organization
+func (r *PipelineRepository) GetByID(ctx context.Context, id, orgID string) (*models.Pipeline, error) {
+	query := `SELECT ` + pipelineColumns + ` FROM pipelines WHERE id = $1 AND organization_id = $2`
+
+	pipeline, err := scanPipeline(r.db.QueryRowContext(ctx, query, id, orgID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("pipeline with id %s not found", id)
+		}
+		return nil, fmt.Errorf("failed to get pipeline by id: %w", err)
+	}
+
+	return pipeline, nil
+}
+
+// List retrieves an organization's pipelines with pagination
+func (r *PipelineRepository) List(ctx context.Context, orgID string, params ListParams) ([]*models.Pipeline, error) {
+	params.Validate()
+
+	query := `
+		SELECT ` + pipelineColumns + `
+		FROM pipelines
+		WHERE organization_id = $1
+		ORDER BY name ASC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, params.Limit, params.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pipelines: %w", err)
+	}
+	defer rows.Close()
+
+	var pipelines []*models.Pipeline
+	for rows.Next() {
+		pipeline, err := scanPipeline(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan pipeline row: %w", err)
+		}
+		pipelines = append(pipelines, pipeline)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pipeline rows: %w", err)
+	}
+
+	return pipelines, nil
+}
+
+// Update updates an existing pipeline, including its stage run state
+func (r *PipelineRepository) Update(ctx context.Context, pipeline *models.Pipeline) error {
+	stagesJSON, err := json.Marshal(pipeline.Stages)
+	if err != nil {
+		return fmt.Errorf("failed to marshal stages: %w", err)
+	}
+
+	query := `
+		UPDATE pipelines
+		SET name = $3, repository = $4, branch = $5, status = $6, stages = $7,
+		    last_run = $8, next_run = $9, updated_at = NOW()
+		WHERE id = $1 AND organization_id = $2
+		RETURNING updated_at`
+
+	err = r.db.QueryRowContext(ctx, query,
+		pipeline.ID, pipeline.OrganizationID, pipeline.Name, pipeline.Repository, pipeline.Branch,
+		pipeline.Status, stagesJSON, pipeline.LastRun, pipeline.NextRun,
+	).Scan(&pipeline.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("pipeline with id %s not found", pipeline.ID)
+		}
+		return fmt.Errorf("failed to update pipeline: %w", err)
+	}
+
+	return nil
+}
+
+// Delete deletes a pipeline within an organization
+func (r *PipelineRepository) Delete(ctx context.Context, id, orgID string) error {
+	query := `DELETE FROM pipelines WHERE id = $1 AND organization_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete pipeline: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("pipeline with id %s not found", id)
+	}
+
+	return nil
+}
+
+// pipelineScanner is satisfied by both *sql.Row and *sql.Rows
+type pipelineScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanPipeline scans a row selected with pipelineColumns
+func scanPipeline(row pipelineScanner) (*models.Pipeline, error) {
+	var pipeline models.Pipeline
+	var stagesJSON []byte
+
+	err := row.Scan(
+		&pipeline.ID, &pipeline.OrganizationID, &pipeline.Name, &pipeline.Repository, &pipeline.Branch,
+		&pipeline.Status, &stagesJSON, &pipeline.LastRun, &pipeline.NextRun, &pipeline.CreatedBy,
+		&pipeline.CreatedAt, &pipeline.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	pipeline.Stages = []models.PipelineStage{}
+	if len(stagesJSON) > 0 {
+		if err := json.Unmarshal(stagesJSON, &pipeline.Stages); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal stages: %w", err)
+		}
+	}
+
+	return &pipeline, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"cloudweave/internal/models"
+)
+
+// QuotaRepository handles organization quota data operations
+type QuotaRepository struct {
+	db *sql.DB
+}
+
+// NewQuotaRepository creates a new quota repository
+func NewQuotaRepository(db *sql.DB) *QuotaRepository {
+	return &QuotaRepository{db: db}
+}
+
+// Get retrieves an organization's quota, or nil if it has none
+func (r *QuotaRepository) Get(ctx context.Context, orgID string) (*models.OrganizationQuota, error) {
+	query := `
+		SELECT organization_id, max_resources, max_monthly_budget, updated_by, created_at, updated_at
+		FROM organization_quotas
+		WHERE organization_id = $1`
+
+	quota := &models.OrganizationQuota{}
+	var maxResourcesJSON []byte
+	err := r.db.QueryRowContext(ctx, query, orgID).Scan(
+		&quota.OrganizationID,
+		&maxResourcesJSON,
+		&quota.MaxMonthlyBudget,
+		&quota.UpdatedBy,
+		&quota.CreatedAt,
+		&quota.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get organization quota: %w", err)
+	}
+
+	if err := json.Unmarshal(maxResourcesJSON, &quota.MaxResources); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal max_resources: %w", err)
+	}
+
+	return quota, nil
+}
+
+// Save creates or replaces an organization's quota
+func (r *QuotaRepository) Save(ctx context.Context, quota *models.OrganizationQuota) error {
+	maxResources := quota.MaxResources
+	if maxResources == nil {
+		maxResources = map[string]int{}
+	}
+	maxResourcesJSON, err := json.Marshal(maxResources)
+	if err != nil {
+		return fmt.Errorf("failed to marshal max_resources: %w", err)
+	}
+
+	query := `
+		INSERT INTO organization_quotas (organization_id, max_resources, max_monthly_budget, updated_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id) DO UPDATE
+		SET max_resources = EXCLUDED.max_resources,
+		    max_monthly_budget = EXCLUDED.max_monthly_budget,
+		    updated_by = EXCLUDED.updated_by
+		RETURNING created_at, updated_at`
+
+	err = r.db.QueryRowContext(ctx, query,
+		quota.OrganizationID, string(maxResourcesJSON), quota.MaxMonthlyBudget, quota.UpdatedBy,
+	).Scan(&quota.CreatedAt, &quota.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save organization quota: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an organization's quota, lifting all of its limits
+func (r *QuotaRepository) Delete(ctx context.Context, orgID string) error {
+	query := `DELETE FROM organization_quotas WHERE organization_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, orgID); err != nil {
+		return fmt.Errorf("failed to delete organization quota: %w", err)
+	}
+
+	return nil
+}
@@ -30,12 +30,12 @@ func (r *RoleRepository) Create(ctx context.Context, role *models.Role) error {
 	}
 
 	query := `
-		INSERT INTO roles (id, organization_id, name, description, is_system, permissions, metadata, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+		INSERT INTO roles (id, organization_id, name, description, is_system, parent_role_id, permissions, metadata, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
 
 	_, err = r.db.ExecContext(ctx, query,
 		role.ID, role.OrganizationID, role.Name, role.Description, role.IsSystem,
-		pq.Array(role.Permissions), metadataJSON, role.CreatedAt, role.UpdatedAt)
+		role.ParentRoleID, pq.Array(role.Permissions), metadataJSON, role.CreatedAt, role.UpdatedAt)
 
 	if err != nil {
 		return fmt.Errorf("failed to create role: %w", err)
@@ -47,7 +47,7 @@ func (r *RoleRepository) Create(ctx context.Context, role *models.Role) error {
 // GetByID retrieves a role by ID
 func (r *RoleRepository) GetByID(ctx context.Context, organizationID, roleID string) (*models.Role, error) {
 	query := `
-		SELECT id, organization_id, name, description, is_system, permissions, metadata, created_at, updated_at
+		SELECT id, organization_id, name, description, is_system, parent_role_id, permissions, metadata, created_at, updated_at
 		FROM roles
 		WHERE id = $1 AND (organization_id = $2 OR is_system = true)`
 
@@ -56,7 +56,7 @@ func (r *RoleRepository) GetByID(ctx context.Context, organizationID, roleID str
 
 	err := r.db.QueryRowContext(ctx, query, roleID, organizationID).Scan(
 		&role.ID, &role.OrganizationID, &role.Name, &role.Description, &role.IsSystem,
-		pq.Array(&role.Permissions), &metadataJSON, &role.CreatedAt, &role.UpdatedAt)
+		&role.ParentRoleID, pq.Array(&role.Permissions), &metadataJSON, &role.CreatedAt, &role.UpdatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -75,7 +75,7 @@ func (r *RoleRepository) GetByID(ctx context.Context, organizationID, roleID str
 // GetByName retrieves a role by name
 func (r *RoleRepository) GetByName(ctx context.Context, organizationID, name string) (*models.Role, error) {
 	query := `
-		SELECT id, organization_id, name, description, is_system, permissions, metadata, created_at, updated_at
+		SELECT id, organization_id, name, description, is_system, parent_role_id, permissions, metadata, created_at, updated_at
 		FROM roles
 		WHERE name = $1 AND (organization_id = $2 OR is_system = true)`
 
@@ -84,7 +84,7 @@ func (r *RoleRepository) GetByName(ctx context.Context, organizationID, name str
 
 	err := r.db.QueryRowContext(ctx, query, name, organizationID).Scan(
 		&role.ID, &role.OrganizationID, &role.Name, &role.Description, &role.IsSystem,
-		pq.Array(&role.Permissions), &metadataJSON, &role.CreatedAt, &role.UpdatedAt)
+		&role.ParentRoleID, pq.Array(&role.Permissions), &metadataJSON, &role.CreatedAt, &role.UpdatedAt)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -112,7 +112,7 @@ func (r *RoleRepository) List(ctx context.Context, organizationID string, limit,
 
 	// Get roles
 	query := `
-		SELECT id, organization_id, name, description, is_system, permissions, metadata, created_at, updated_at
+		SELECT id, organization_id, name, description, is_system, parent_role_id, permissions, metadata, created_at, updated_at
 		FROM roles
 		WHERE organization_id = $1 OR is_system = true
 		ORDER BY is_system DESC, name ASC
@@ -131,7 +131,7 @@ func (r *RoleRepository) List(ctx context.Context, organizationID string, limit,
 
 		err := rows.Scan(
 			&role.ID, &role.OrganizationID, &role.Name, &role.Description, &role.IsSystem,
-			pq.Array(&role.Permissions), &metadataJSON, &role.CreatedAt, &role.UpdatedAt)
+			&role.ParentRoleID, pq.Array(&role.Permissions), &metadataJSON, &role.CreatedAt, &role.UpdatedAt)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan role: %w", err)
 		}
@@ -155,11 +155,11 @@ func (r *RoleRepository) Update(ctx context.Context, role *models.Role) error {
 
 	query := `
 		UPDATE roles
-		SET name = $1, description = $2, permissions = $3, metadata = $4, updated_at = $5
-		WHERE id = $6 AND organization_id = $7`
+		SET name = $1, description = $2, parent_role_id = $3, permissions = $4, metadata = $5, updated_at = $6
+		WHERE id = $7 AND organization_id = $8`
 
 	result, err := r.db.ExecContext(ctx, query,
-		role.Name, role.Description, pq.Array(role.Permissions), metadataJSON, role.UpdatedAt,
+		role.Name, role.Description, role.ParentRoleID, pq.Array(role.Permissions), metadataJSON, role.UpdatedAt,
 		role.ID, role.OrganizationID)
 
 	if err != nil {
@@ -202,7 +202,7 @@ func (r *RoleRepository) Delete(ctx context.Context, organizationID, roleID stri
 // ListSystemRoles retrieves all system roles
 func (r *RoleRepository) ListSystemRoles(ctx context.Context) ([]*models.Role, error) {
 	query := `
-		SELECT id, organization_id, name, description, is_system, permissions, metadata, created_at, updated_at
+		SELECT id, organization_id, name, description, is_system, parent_role_id, permissions, metadata, created_at, updated_at
 		FROM roles
 		WHERE is_system = true
 		ORDER BY name ASC`
@@ -220,7 +220,7 @@ func (r *RoleRepository) ListSystemRoles(ctx context.Context) ([]*models.Role, e
 
 		err := rows.Scan(
 			&role.ID, &role.OrganizationID, &role.Name, &role.Description, &role.IsSystem,
-			pq.Array(&role.Permissions), &metadataJSON, &role.CreatedAt, &role.UpdatedAt)
+			&role.ParentRoleID, pq.Array(&role.Permissions), &metadataJSON, &role.CreatedAt, &role.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan role: %w", err)
 		}
@@ -285,27 +285,34 @@ func (ur *UserRoleRepository) RemoveRole(ctx context.Context, userID, roleID, or
 	return nil
 }
 
-// GetUserRoles retrieves all roles for a user
-func (ur *UserRoleRepository) GetUserRoles(ctx context.Context, userID, organizationID string) ([]*models.UserRole, error) {
+// GetUserRoles retrieves a user's active role assignments with the name and permissions of each
+// role. With includeExpired, assignments whose expiry has passed are included too; removed
+// assignments never are.
+func (ur *UserRoleRepository) GetUserRoles(ctx context.Context, userID, organizationID string, includeExpired bool) ([]*models.UserRole, error) {
 	query := `
-		SELECT ur.id, ur.user_id, ur.role_id, ur.organization_id, ur.assigned_by, ur.assigned_at, ur.expires_at, ur.is_active
+		SELECT ur.id, ur.user_id, ur.role_id, ur.organization_id, ur.assigned_by, ur.assigned_at, ur.expires_at, ur.is_active,
+		       r.name, r.permissions
 		FROM user_roles ur
-		WHERE ur.user_id = $1 AND ur.organization_id = $2 AND ur.is_active = true
-		AND (ur.expires_at IS NULL OR ur.expires_at > NOW())`
+		JOIN roles r ON r.id = ur.role_id
+		WHERE ur.user_id = $1 AND ur.organization_id = $2
+		AND ((ur.is_active = true AND (ur.expires_at IS NULL OR ur.expires_at > NOW()))
+		     OR ($3 AND ur.expires_at IS NOT NULL AND ur.expires_at <= NOW()))
+		ORDER BY r.name ASC`
 
-	rows, err := ur.db.QueryContext(ctx, query, userID, organizationID)
+	rows, err := ur.db.QueryContext(ctx, query, userID, organizationID, includeExpired)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query user roles: %w", err)
 	}
 	defer rows.Close()
 
-	var userRoles []*models.UserRole
+	userRoles := []*models.UserRole{}
 	for rows.Next() {
 		var userRole models.UserRole
 
 		err := rows.Scan(
 			&userRole.ID, &userRole.UserID, &userRole.RoleID, &userRole.OrganizationID,
-			&userRole.AssignedBy, &userRole.AssignedAt, &userRole.ExpiresAt, &userRole.IsActive)
+			&userRole.AssignedBy, &userRole.AssignedAt, &userRole.ExpiresAt, &userRole.IsActive,
+			&userRole.RoleName, pq.Array(&userRole.Permissions))
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user role: %w", err)
 		}
@@ -365,11 +372,43 @@ func (ur *UserRoleRepository) IsUserInRole(ctx context.Context, userID, roleID,
 	return exists, nil
 }
 
+// DeactivateExpired marks active role assignments past their expiry as inactive and
+// returns the assignments that were deactivated
+func (ur *UserRoleRepository) DeactivateExpired(ctx context.Context) ([]*models.UserRole, error) {
+	query := `
+		UPDATE user_roles
+		SET is_active = false
+		WHERE is_active = true AND expires_at IS NOT NULL AND expires_at <= NOW()
+		RETURNING id, user_id, role_id, organization_id, assigned_by, assigned_at, expires_at, is_active`
+
+	rows, err := ur.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deactivate expired user roles: %w", err)
+	}
+	defer rows.Close()
+
+	var userRoles []*models.UserRole
+	for rows.Next() {
+		var userRole models.UserRole
+
+		err := rows.Scan(
+			&userRole.ID, &userRole.UserID, &userRole.RoleID, &userRole.OrganizationID,
+			&userRole.AssignedBy, &userRole.AssignedAt, &userRole.ExpiresAt, &userRole.IsActive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user role: %w", err)
+		}
+
+		userRoles = append(userRoles, &userRole)
+	}
+
+	return userRoles, nil
+}
+
 // GetUserPermissions retrieves effective permissions for a user
 func (ur *UserRoleRepository) GetUserPermissions(ctx context.Context, userID, organizationID string) (*models.UserPermissions, error) {
 	// Get user roles with role details
 	query := `
-		SELECT r.id, r.name, r.permissions, r.is_system
+		SELECT r.id, r.name, r.permissions, r.is_system, r.parent_role_id
 		FROM user_roles ur
 		JOIN roles r ON ur.role_id = r.id
 		WHERE ur.user_id = $1 AND ur.organization_id = $2 AND ur.is_active = true
@@ -389,7 +428,7 @@ func (ur *UserRoleRepository) GetUserPermissions(ctx context.Context, userID, or
 		var role models.Role
 		var permissions []string
 
-		err := rows.Scan(&role.ID, &role.Name, pq.Array(&permissions), &role.IsSystem)
+		err := rows.Scan(&role.ID, &role.Name, pq.Array(&permissions), &role.IsSystem, &role.ParentRoleID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan role: %w", err)
 		}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// openTestDB connects to the migrated database named by TEST_DATABASE_URL, skipping the test when
+// it is not set
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	return db
+}
+
+func TestGetUserPermissionsExcludesExpiredRoles(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	suffix := time.Now().Format("20060102150405.000000000")
+
+	var orgID, userID, roleID string
+	if err := db.QueryRowContext(ctx,
+		`INSERT INTO organizations (name, slug) VALUES ($1, $1) RETURNING id`,
+		"rbac-expiry-"+suffix).Scan(&orgID); err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM organizations WHERE id = $1`, orgID) })
+
+	if err := db.QueryRowContext(ctx,
+		`INSERT INTO users (email, name, password_hash, organization_id) VALUES ($1, 'Expired Admin', 'x', $2) RETURNING id`,
+		"rbac-expiry-"+suffix+"@example.com", orgID).Scan(&userID); err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM users WHERE id = $1`, userID) })
+
+	if err := db.QueryRowContext(ctx,
+		`INSERT INTO roles (organization_id, name, permissions) VALUES ($1, 'Temporary Admin', $2) RETURNING id`,
+		orgID, pq.Array([]string{models.PermissionAdminFull})).Scan(&roleID); err != nil {
+		t.Fatalf("failed to create role: %v", err)
+	}
+
+	repo := NewUserRoleRepository(db)
+	expiresAt := time.Now().Add(-time.Hour)
+	if err := repo.AssignRole(ctx, &models.UserRole{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		RoleID:         roleID,
+		OrganizationID: orgID,
+		AssignedBy:     userID,
+		AssignedAt:     expiresAt.Add(-time.Hour),
+		ExpiresAt:      &expiresAt,
+		IsActive:       true,
+	}); err != nil {
+		t.Fatalf("failed to assign role: %v", err)
+	}
+
+	permissions, err := repo.GetUserPermissions(ctx, userID, orgID)
+	if err != nil {
+		t.Fatalf("GetUserPermissions returned an error: %v", err)
+	}
+	if permissions.IsAdmin {
+		t.Error("expired role still grants admin")
+	}
+	if len(permissions.Permissions) != 0 {
+		t.Errorf("expired role still grants permissions %v", permissions.Permissions)
+	}
+	if len(permissions.Roles) != 0 {
+		t.Errorf("expired role still listed: %v", permissions.Roles)
+	}
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+)
+
+// primaryReadsKey marks a context whose reads must go to the primary database
+type primaryReadsKey struct{}
+
+// WithPrimary returns a context whose reads go to the primary database even when a read replica
+// is configured, for reads that must see a write made just before, e.g. right after a create
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryReadsKey{}, true)
+}
+
+// readDB returns the database a list or query read should use: the replica if one is configured
+// and the context does not require the primary. Single-record lookups and writes always use the
+// primary.
+func readDB(ctx context.Context, primary, replica *sql.DB) *sql.DB {
+	if replica == nil {
+		return primary
+	}
+	if forced, _ := ctx.Value(primaryReadsKey{}).(bool); forced {
+		return primary
+	}
+	return replica
+}
@@ -9,64 +9,116 @@ import (
 // RepositoryManager provides centralized access to all repositories and transaction management
 type RepositoryManager struct {
 	// Repositories
-	User                 UserRepositoryInterface
-	Organization         OrganizationRepositoryInterface
-	Infrastructure       InfrastructureRepositoryInterface
-	Deployment           DeploymentRepositoryInterface
-	Metric               MetricRepositoryInterface
-	Alert                AlertRepositoryInterface
-	AuditLog             AuditLogRepositoryInterface
-	SecurityScan         SecurityScanRepositoryInterface
-	Vulnerability        VulnerabilityRepositoryInterface
-	ComplianceFramework  ComplianceFrameworkRepositoryInterface
-	ComplianceControl    ComplianceControlRepositoryInterface
-	ComplianceAssessment ComplianceAssessmentRepositoryInterface
-	Role                 RoleRepositoryInterface
-	UserRole             UserRoleRepositoryInterface
-	ResourcePermission   ResourcePermissionRepositoryInterface
-	APIKey               APIKeyRepositoryInterface
-	Session              SessionRepositoryInterface
-	CloudCredentials     *CloudCredentialsRepository
-	DemoData             *DemoDataRepository
+	User                  UserRepositoryInterface
+	Organization          OrganizationRepositoryInterface
+	Infrastructure        InfrastructureRepositoryInterface
+	InfraSnapshot         InfrastructureSnapshotRepositoryInterface
+	Deployment            DeploymentRepositoryInterface
+	DeploymentRollback    DeploymentRollbackRepositoryInterface
+	DeploymentLock        DeploymentLockRepositoryInterface
+	DeploymentHealthCheck DeploymentHealthCheckRepositoryInterface
+	Pipeline              PipelineRepositoryInterface
+	Environment           EnvironmentRepositoryInterface
+	Metric                MetricRepositoryInterface
+	Alert                 AlertRepositoryInterface
+	AlertRule             AlertRuleRepositoryInterface
+	AlertEvent            AlertEventRepositoryInterface
+	NotificationChannel   NotificationChannelRepositoryInterface
+	Webhook               WebhookRepositoryInterface
+	Quota                 QuotaRepositoryInterface
+	CostAllocation        CostAllocationRepositoryInterface
+	Retention             RetentionRepositoryInterface
+	StatSnapshot          StatSnapshotRepositoryInterface
+	CostSnapshot          CostSnapshotRepositoryInterface
+	Search                SearchRepositoryInterface
+	IdempotencyKey        IdempotencyKeyRepositoryInterface
+	AuditLog              AuditLogRepositoryInterface
+	SecurityScan          SecurityScanRepositoryInterface
+	Vulnerability         VulnerabilityRepositoryInterface
+	SBOM                  SBOMRepositoryInterface
+	Job                   JobRepositoryInterface
+	ComplianceFramework   ComplianceFrameworkRepositoryInterface
+	ComplianceControl     ComplianceControlRepositoryInterface
+	ComplianceAssessment  ComplianceAssessmentRepositoryInterface
+	ComplianceReport      ComplianceReportRepositoryInterface
+	ComplianceSchedule    ComplianceReportScheduleRepositoryInterface
+	Role                  RoleRepositoryInterface
+	UserRole              UserRoleRepositoryInterface
+	ResourcePermission    ResourcePermissionRepositoryInterface
+	APIKey                APIKeyRepositoryInterface
+	Session               SessionRepositoryInterface
+	CloudCredentials      *CloudCredentialsRepository
+	DemoData              *DemoDataRepository
 
 	// Transaction manager
 	Transaction TransactionManager
 
-	// Database connection
-	db *sql.DB
+	// Database connection, and the optional read replica
+	db      *sql.DB
+	replica *sql.DB
 }
 
 // NewRepositoryManager creates a new repository manager with all repositories initialized
 func NewRepositoryManager(db *sql.DB) *RepositoryManager {
+	return NewRepositoryManagerWithReplica(db, nil)
+}
+
+// NewRepositoryManagerWithReplica creates a repository manager whose list and query reads of
+// infrastructure, deployments, alerts and metrics go to a read replica. Writes, single-record
+// lookups and reads made with a WithPrimary context use the primary, as does everything when
+// replica is nil.
+func NewRepositoryManagerWithReplica(db, replica *sql.DB) *RepositoryManager {
 	// Create sqlx.DB wrapper for repositories that need it
 	sqlxDB := sqlx.NewDb(db, "postgres")
 	return &RepositoryManager{
 		// Initialize repositories
-		User:                 NewUserRepository(db),
-		Organization:         NewOrganizationRepository(db),
-		Infrastructure:       NewInfrastructureRepository(db),
-		Deployment:           NewDeploymentRepository(db),
-		Metric:               NewMetricRepository(db),
-		Alert:                NewAlertRepository(db),
-		AuditLog:             NewAuditLogRepository(db),
-		SecurityScan:         NewSecurityScanRepository(db),
-		Vulnerability:        NewVulnerabilityRepository(db),
-		ComplianceFramework:  NewComplianceFrameworkRepository(db),
-		ComplianceControl:    NewComplianceControlRepository(db),
-		ComplianceAssessment: NewComplianceAssessmentRepository(db),
-		Role:                 NewRoleRepository(db),
-		UserRole:             NewUserRoleRepository(db),
-		ResourcePermission:   nil, // TODO: Implement ResourcePermissionRepository
-		APIKey:               nil, // TODO: Implement APIKeyRepository
-		Session:              nil, // TODO: Implement SessionRepository
-		CloudCredentials:     NewCloudCredentialsRepository(db),
-		DemoData:             NewDemoDataRepository(sqlxDB),
+		User:                  NewUserRepository(db),
+		Organization:          NewOrganizationRepository(db),
+		Infrastructure:        &InfrastructureRepository{db: db, replica: replica},
+		InfraSnapshot:         NewInfrastructureSnapshotRepository(db),
+		Deployment:            &DeploymentRepository{db: db, replica: replica},
+		DeploymentRollback:    NewDeploymentRollbackRepository(db),
+		DeploymentLock:        NewDeploymentLockRepository(db),
+		DeploymentHealthCheck: NewDeploymentHealthCheckRepository(db),
+		Pipeline:              NewPipelineRepository(db),
+		Environment:           NewEnvironmentRepository(db),
+		Metric:                &MetricRepository{db: db, replica: replica},
+		Alert:                 &AlertRepository{db: db, replica: replica},
+		AlertRule:             NewAlertRuleRepository(db),
+		AlertEvent:            NewAlertEventRepository(db),
+		NotificationChannel:   NewNotificationChannelRepository(db),
+		Webhook:               NewWebhookRepository(db),
+		Quota:                 NewQuotaRepository(db),
+		CostAllocation:        NewCostAllocationRepository(db),
+		Retention:             NewRetentionRepository(db),
+		StatSnapshot:          NewStatSnapshotRepository(db),
+		CostSnapshot:          NewCostSnapshotRepository(db),
+		Search:                NewSearchRepository(db),
+		IdempotencyKey:        NewIdempotencyKeyRepository(db),
+		AuditLog:              NewAuditLogRepository(db),
+		SecurityScan:          NewSecurityScanRepository(db),
+		Vulnerability:         NewVulnerabilityRepository(db),
+		SBOM:                  NewSBOMRepository(db),
+		Job:                   NewJobRepository(db),
+		ComplianceFramework:   NewComplianceFrameworkRepository(db),
+		ComplianceControl:     NewComplianceControlRepository(db),
+		ComplianceAssessment:  NewComplianceAssessmentRepository(db),
+		ComplianceReport:      NewComplianceReportRepository(db),
+		ComplianceSchedule:    NewComplianceReportScheduleRepository(db),
+		Role:                  NewRoleRepository(db),
+		UserRole:              NewUserRoleRepository(db),
+		ResourcePermission:    NewResourcePermissionRepository(db),
+		APIKey:                NewAPIKeyRepository(db),
+		Session:               nil, // TODO: Implement SessionRepository
+		CloudCredentials:      NewCloudCredentialsRepository(db),
+		DemoData:              NewDemoDataRepository(sqlxDB),
 
 		// Initialize transaction manager
 		Transaction: NewTransactionManager(db),
 
-		// Store database connection
-		db: db,
+		// Store database connections
+		db:      db,
+		replica: replica,
 	}
 }
 
@@ -83,6 +135,9 @@ func (rm *RepositoryManager) Health() error {
 
 // Close closes all database connections
 func (rm *RepositoryManager) Close() error {
+	if rm.replica != nil {
+		rm.replica.Close()
+	}
 	return rm.db.Close()
 }
 
@@ -90,3 +145,11 @@ func (rm *RepositoryManager) Close() error {
 func (rm *RepositoryManager) Stats() sql.DBStats {
 	return rm.db.Stats()
 }
+
+// ReplicaStats returns read replica connection statistics, and false if no replica is configured
+func (rm *RepositoryManager) ReplicaStats() (sql.DBStats, bool) {
+	if rm.replica == nil {
+		return sql.DBStats{}, false
+	}
+	return rm.replica.Stats(), true
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"cloudweave/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ResourcePermissionRepository handles permissions granted on individual resources
+type ResourcePermissionRepository struct {
+	db *sql.DB
+}
+
+// NewResourcePermissionRepository creates a new resource permission repository
+func NewResourcePermissionRepository(db *sql.DB) *ResourcePermissionRepository {
+	return &ResourcePermissionRepository{db: db}
+}
+
+const resourcePermissionColumns = `id, user_id, resource_type, resource_id, organization_id, permissions,
+		       granted_by, granted_at, expires_at, metadata`
+
+// Grant grants permissions on a resource to a user. A user holds one grant per resource, so
+// granting again adds the new permissions to the existing grant and replaces its expiry.
+func (r *ResourcePermissionRepository) Grant(ctx context.Context, permission *models.ResourcePermission) error {
+	metadataJSON, err := json.Marshal(permission.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+		INSERT INTO resource_permissions (id, user_id, resource_type, resource_id, organization_id,
+		                                  permissions, granted_by, granted_at, expires_at, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (user_id, resource_type, resource_id, organization_id)
+		DO UPDATE SET permissions = ARRAY(SELECT DISTINCT unnest(resource_permissions.permissions || EXCLUDED.permissions)),
+		              granted_by = EXCLUDED.granted_by, granted_at = EXCLUDED.granted_at,
+		              expires_at = EXCLUDED.expires_at, metadata = EXCLUDED.metadata
+		RETURNING id, permissions`
+
+	err = r.db.QueryRowContext(ctx, query,
+		permission.ID, permission.UserID, permission.ResourceType, permission.ResourceID, permission.OrganizationID,
+		pq.Array(permission.Permissions), permission.GrantedBy, permission.GrantedAt, permission.ExpiresAt, metadataJSON,
+	).Scan(&permission.ID, pq.Array(&permission.Permissions))
+	if err != nil {
+		return fmt.Errorf("failed to grant resource permission: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a resource permission grant by ID
+func (r *ResourcePermissionRepository) GetByID(ctx context.Context, organizationID, id string) (*models.ResourcePermission, error) {
+	query := `SELECT ` + resourcePermissionColumns + ` FROM resource_permissions WHERE id = $1 AND organization_id = $2`
+
+	permission, err := scanResourcePermission(r.db.QueryRowContext(ctx, query, id, organizationID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource permission: %w", err)
+	}
+
+	return permission, nil
+}
+
+// Revoke removes a user's grant on a resource
+func (r *ResourcePermissionRepository) Revoke(ctx context.Context, userID, resourceType, resourceID, organizationID string) error {
+	query := `
+		DELETE FROM resource_permissions
+		WHERE user_id = $1 AND resource_type = $2 AND resource_id = $3 AND organization_id = $4`
+
+	result, err := r.db.ExecContext(ctx, query, userID, resourceType, resourceID, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke resource permission: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("resource permission not found")
+	}
+
+	return nil
+}
+
+// GetUserResourcePermissions retrieves the unexpired grants held by a user
+func (r *ResourcePermissionRepository) GetUserResourcePermissions(ctx context.Context, userID, organizationID string) ([]*models.ResourcePermission, error) {
+	query := `
+		SELECT ` + resourcePermissionColumns + `
+		FROM resource_permissions
+		WHERE user_id = $1 AND organization_id = $2 AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY resource_type, resource_id`
+
+	return r.list(ctx, query, userID, organizationID)
+}
+
+// GetResourcePermissions retrieves the unexpired grants on a resource
+func (r *ResourcePermissionRepository) GetResourcePermissions(ctx context.Context, resourceType, resourceID, organizationID string) ([]*models.ResourcePermission, error) {
+	query := `
+		SELECT ` + resourcePermissionColumns + `
+		FROM resource_permissions
+		WHERE resource_type = $1 AND resource_id = $2 AND organization_id = $3
+		AND (expires_at IS NULL OR expires_at > NOW())
+		ORDER BY granted_at`
+
+	return r.list(ctx, query, resourceType, resourceID, organizationID)
+}
+
+// HasPermission reports whether a user holds an unexpired grant on a resource that includes the
+// permission, directly or through "*" or "<resource>:*"
+func (r *ResourcePermissionRepository) HasPermission(ctx context.Context, userID, resourceType, resourceID, permission, organizationID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM resource_permissions
+			WHERE user_id = $1 AND resource_type = $2 AND resource_id = $3 AND organization_id = $5
+			AND (expires_at IS NULL OR expires_at > NOW())
+			AND ($4 = ANY(permissions) OR '*' = ANY(permissions) OR split_part($4, ':', 1) || ':*' = ANY(permissions))
+		)`
+
+	var allowed bool
+	if err := r.db.QueryRowContext(ctx, query, userID, resourceType, resourceID, permission, organizationID).Scan(&allowed); err != nil {
+		return false, fmt.Errorf("failed to check resource permission: %w", err)
+	}
+
+	return allowed, nil
+}
+
+func (r *ResourcePermissionRepository) list(ctx context.Context, query string, args ...interface{}) ([]*models.ResourcePermission, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query resource permissions: %w", err)
+	}
+	defer rows.Close()
+
+	permissions := []*models.ResourcePermission{}
+	for rows.Next() {
+		permission, err := scanResourcePermission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan resource permission: %w", err)
+		}
+		permissions = append(permissions, permission)
+	}
+
+	return permissions, rows.Err()
+}
+
+func scanResourcePermission(row apiKeyScanner) (*models.ResourcePermission, error) {
+	var permission models.ResourcePermission
+	var grantedBy sql.NullString
+	var metadataJSON []byte
+
+	err := row.Scan(
+		&permission.ID, &permission.UserID, &permission.ResourceType, &permission.ResourceID, &permission.OrganizationID,
+		pq.Array(&permission.Permissions), &grantedBy, &permission.GrantedAt, &permission.ExpiresAt, &metadataJSON)
+	if err != nil {
+		return nil, err
+	}
+	permission.GrantedBy = grantedBy.String
+
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &permission.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		}
+	}
+
+	return &permission, nil
+}
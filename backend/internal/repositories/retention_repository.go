@@ -0,0 +1,179 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// retentionMetricTables describes, for each metric resolution, its table, the column its age is
+// measured by and the retention settings column that applies to it
+var retentionMetricTables = map[string]struct{ table, timeColumn, daysColumn string }{
+	models.MetricResolutionRaw:    {"metrics", "timestamp", "raw_metrics_days"},
+	models.MetricResolutionHourly: {"metrics_hourly", "bucket", "hourly_metrics_days"},
+	models.MetricResolutionDaily:  {"metrics_daily", "bucket", "daily_metrics_days"},
+}
+
+// RetentionRepository handles organization data retention settings and the purge of data past
+// its retention
+type RetentionRepository struct {
+	db *sql.DB
+}
+
+// NewRetentionRepository creates a new retention repository
+func NewRetentionRepository(db *sql.DB) *RetentionRepository {
+	return &RetentionRepository{db: db}
+}
+
+// Get retrieves an organization's retention settings, or nil if it has none
+func (r *RetentionRepository) Get(ctx context.Context, orgID string) (*models.RetentionSettings, error) {
+	query := `
+		SELECT organization_id, raw_metrics_days, hourly_metrics_days, daily_metrics_days,
+		       resolved_alerts_days, updated_by, created_at, updated_at
+		FROM data_retention_settings
+		WHERE organization_id = $1`
+
+	settings := &models.RetentionSettings{}
+	err := r.db.QueryRowContext(ctx, query, orgID).Scan(
+		&settings.OrganizationID,
+		&settings.RawMetricsDays,
+		&settings.HourlyMetricsDays,
+		&settings.DailyMetricsDays,
+		&settings.ResolvedAlertsDays,
+		&settings.UpdatedBy,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get retention settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// Save creates or replaces an organization's retention settings
+func (r *RetentionRepository) Save(ctx context.Context, settings *models.RetentionSettings) error {
+	query := `
+		INSERT INTO data_retention_settings (organization_id, raw_metrics_days, hourly_metrics_days,
+		                                     daily_metrics_days, resolved_alerts_days, updated_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (organization_id) DO UPDATE
+		SET raw_metrics_days = EXCLUDED.raw_metrics_days,
+		    hourly_metrics_days = EXCLUDED.hourly_metrics_days,
+		    daily_metrics_days = EXCLUDED.daily_metrics_days,
+		    resolved_alerts_days = EXCLUDED.resolved_alerts_days,
+		    updated_by = EXCLUDED.updated_by
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		settings.OrganizationID, settings.RawMetricsDays, settings.HourlyMetricsDays,
+		settings.DailyMetricsDays, settings.ResolvedAlertsDays, settings.UpdatedBy,
+	).Scan(&settings.CreatedAt, &settings.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to save retention settings: %w", err)
+	}
+
+	return nil
+}
+
+// CountExpiredMetrics counts an organization's metrics at a resolution recorded before the cutoff
+func (r *RetentionRepository) CountExpiredMetrics(ctx context.Context, resolution, orgID string, cutoff time.Time) (int64, error) {
+	target, ok := retentionMetricTables[resolution]
+	if !ok {
+		return 0, fmt.Errorf("unsupported metric resolution %s", resolution)
+	}
+
+	query := `
+		SELECT COUNT(*)
+		FROM ` + target.table + ` m
+		JOIN infrastructure i ON i.id = m.resource_id
+		WHERE i.organization_id = $1 AND m.` + target.timeColumn + ` < $2`
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, orgID, cutoff).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count expired %s metrics: %w", resolution, err)
+	}
+
+	return count, nil
+}
+
+// DeleteExpiredMetrics deletes up to limit metrics at a resolution that are older than their
+// organization's retention, or defaultDays for organizations without settings and metrics of
+// resources that no longer exist. Nothing recorded at or after notAfter is deleted.
+func (r *RetentionRepository) DeleteExpiredMetrics(ctx context.Context, resolution string, defaultDays int, notAfter time.Time, limit int) (int64, error) {
+	target, ok := retentionMetricTables[resolution]
+	if !ok {
+		return 0, fmt.Errorf("unsupported metric resolution %s", resolution)
+	}
+
+	query := `
+		DELETE FROM ` + target.table + `
+		WHERE id IN (
+			SELECT m.id
+			FROM ` + target.table + ` m
+			LEFT JOIN infrastructure i ON i.id = m.resource_id
+			LEFT JOIN data_retention_settings s ON s.organization_id = i.organization_id
+			WHERE m.` + target.timeColumn + ` < $1
+			  AND m.` + target.timeColumn + ` < NOW() - make_interval(days => COALESCE(s.` + target.daysColumn + `, $2))
+			LIMIT $3
+		)`
+
+	result, err := r.db.ExecContext(ctx, query, notAfter, defaultDays, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired %s metrics: %w", resolution, err)
+	}
+
+	return result.RowsAffected()
+}
+
+// resolvedAlertsQuery selects alerts whose latest event resolved them, with the time they were
+// resolved
+const resolvedAlertsQuery = `
+	FROM alerts a
+	JOIN LATERAL (
+		SELECT e.event_type, e.created_at
+		FROM alert_events e
+		WHERE e.alert_id = a.id
+		ORDER BY e.created_at DESC
+		LIMIT 1
+	) latest ON latest.event_type = '` + models.AlertEventResolved + `'`
+
+// CountExpiredAlerts counts an organization's alerts resolved before the cutoff
+func (r *RetentionRepository) CountExpiredAlerts(ctx context.Context, orgID string, cutoff time.Time) (int64, error) {
+	query := `SELECT COUNT(*) ` + resolvedAlertsQuery + `
+		WHERE a.organization_id = $1 AND latest.created_at < $2`
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, query, orgID, cutoff).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count expired alerts: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteExpiredAlerts deletes up to limit alerts resolved longer ago than their organization's
+// retention, or defaultDays for organizations without settings. Their events are deleted with them.
+func (r *RetentionRepository) DeleteExpiredAlerts(ctx context.Context, defaultDays, limit int) (int64, error) {
+	query := `
+		DELETE FROM alerts
+		WHERE id IN (
+			SELECT a.id ` + resolvedAlertsQuery + `
+			LEFT JOIN data_retention_settings s ON s.organization_id = a.organization_id
+			WHERE latest.created_at < NOW() - make_interval(days => COALESCE(s.resolved_alerts_days, $1))
+			LIMIT $2
+		)`
+
+	result, err := r.db.ExecContext(ctx, query, defaultDays, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired alerts: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,75 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"cloudweave/internal/models"
+)
+
+// SBOMRepository handles the software bills of materials stored for deployments
+type SBOMRepository struct {
+	db *sql.DB
+}
+
+// NewSBOMRepository creates a new SBOM repository
+func NewSBOMRepository(db *sql.DB) *SBOMRepository {
+	return &SBOMRepository{db: db}
+}
+
+// GetByDeployment retrieves a deployment's SBOM in a format, or nil if none has been stored
+func (r *SBOMRepository) GetByDeployment(ctx context.Context, deploymentID string, format models.SBOMFormat) (*models.DeploymentSBOM, error) {
+	query := `
+		SELECT id, deployment_id, organization_id, format, artifact, document, generated_at
+		FROM deployment_sboms
+		WHERE deployment_id = $1 AND format = $2`
+
+	sbom := &models.DeploymentSBOM{}
+	err := r.db.QueryRowContext(ctx, query, deploymentID, format).Scan(
+		&sbom.ID,
+		&sbom.DeploymentID,
+		&sbom.OrganizationID,
+		&sbom.Format,
+		&sbom.Artifact,
+		&sbom.Document,
+		&sbom.GeneratedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get deployment SBOM: %w", err)
+	}
+
+	return sbom, nil
+}
+
+// Create stores a deployment's SBOM. A deployment's artifact does not change, so if an SBOM in
+// the same format was stored concurrently, that one is kept and sbom is set to it.
+func (r *SBOMRepository) Create(ctx context.Context, sbom *models.DeploymentSBOM) error {
+	query := `
+		INSERT INTO deployment_sboms (id, deployment_id, organization_id, format, artifact, document, generated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (deployment_id, format) DO NOTHING`
+
+	result, err := r.db.ExecContext(ctx, query,
+		sbom.ID, sbom.DeploymentID, sbom.OrganizationID, sbom.Format, sbom.Artifact,
+		string(sbom.Document), sbom.GeneratedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create deployment SBOM: %w", err)
+	}
+
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		existing, err := r.GetByDeployment(ctx, sbom.DeploymentID, sbom.Format)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			*sbom = *existing
+		}
+	}
+
+	return nil
+}
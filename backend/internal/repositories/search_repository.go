@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"cloudweave/internal/models"
+)
+
+// searchQueries select each searchable table's matches as (id, title, subtitle, status, rank, updated_at).
+// $1 is the organization ID, $2 the tsquery and $3 the result limit.
+var searchQueries = map[string]string{
+	models.SearchTypeInfrastructure: `
+		SELECT id, name, provider || ' ' || type || ' in ' || region, status,
+			ts_rank(search_vector, to_tsquery('simple', $2)) AS rank, updated_at
+		FROM infrastructure
+		WHERE organization_id = $1 AND deleted_at IS NULL AND search_vector @@ to_tsquery('simple', $2)
+		ORDER BY rank DESC, updated_at DESC
+		LIMIT $3`,
+	models.SearchTypeDeployment: `
+		SELECT id, name, application || ' ' || version || ' to ' || environment, status,
+			ts_rank(search_vector, to_tsquery('simple', $2)) AS rank, updated_at
+		FROM deployments
+		WHERE organization_id = $1 AND search_vector @@ to_tsquery('simple', $2)
+		ORDER BY rank DESC, updated_at DESC
+		LIMIT $3`,
+	models.SearchTypeAlert: `
+		SELECT id, title, severity || ' ' || type || ' alert',
+			CASE WHEN acknowledged THEN 'acknowledged' ELSE 'active' END,
+			ts_rank(search_vector, to_tsquery('simple', $2)) AS rank, updated_at
+		FROM alerts
+		WHERE organization_id = $1 AND search_vector @@ to_tsquery('simple', $2)
+		ORDER BY rank DESC, updated_at DESC
+		LIMIT $3`,
+}
+
+// searchLinks maps result types to the API path of the matched resource
+var searchLinks = map[string]string{
+	models.SearchTypeInfrastructure: "/api/v1/infrastructure/",
+	models.SearchTypeDeployment:     "/api/v1/deployments/",
+	models.SearchTypeAlert:          "/api/v1/alerts/",
+}
+
+// SearchRepository handles full-text search across an organization's resources
+type SearchRepository struct {
+	db *sql.DB
+}
+
+// NewSearchRepository creates a new search repository
+func NewSearchRepository(db *sql.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// Search returns up to limit resources of one type matching the search terms, best ranked first.
+// Every term must match, and the last term also matches as a prefix so results appear while typing.
+func (r *SearchRepository) Search(ctx context.Context, orgID, resultType string, terms []string, limit int) ([]*models.SearchResult, error) {
+	query, ok := searchQueries[resultType]
+	if !ok {
+		return nil, fmt.Errorf("unknown search type %q", resultType)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, searchTSQuery(terms), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search %s: %w", resultType, err)
+	}
+	defer rows.Close()
+
+	results := []*models.SearchResult{}
+	for rows.Next() {
+		result := &models.SearchResult{Type: resultType}
+		err := rows.Scan(
+			&result.ID,
+			&result.Title,
+			&result.Subtitle,
+			&result.Status,
+			&result.Rank,
+			&result.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search result row: %w", err)
+		}
+		result.Link = searchLinks[resultType] + result.ID
+		results = append(results, result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating search result rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// searchTSQuery builds a tsquery requiring every term, with the last one matched as a prefix.
+// Terms must only contain letters and digits so they cannot inject tsquery operators.
+func searchTSQuery(terms []string) string {
+	lexemes := make([]string, len(terms))
+	for i, term := range terms {
+		lexemes[i] = strings.ToLower(term)
+	}
+	lexemes[len(lexemes)-1] += ":*"
+	return strings.Join(lexemes, " & ")
+}
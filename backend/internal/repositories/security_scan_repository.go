@@ -4,12 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"cloudweave/internal/models"
 )
 
+// ErrSecurityScanNotFound is returned when a security scan does not exist in the organization
+var ErrSecurityScanNotFound = errors.New("security scan not found")
+
 // SecurityScanRepository handles security scan data operations
 type SecurityScanRepository struct {
 	db *sql.DB
@@ -81,7 +85,7 @@ func (r *SecurityScanRepository) GetByID(ctx context.Context, orgID, id string)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("security scan not found")
+			return nil, ErrSecurityScanNotFound
 		}
 		return nil, fmt.Errorf("failed to get security scan: %w", err)
 	}
@@ -245,3 +249,49 @@ func (r *SecurityScanRepository) GetRecentCount(ctx context.Context, orgID strin
 
 	return count, nil
 }
+
+// GetLatestForDeploymentVersion retrieves the latest completed scan of any deployment of a version
+// of an application, or nil if no deployment of the version has been scanned
+func (r *SecurityScanRepository) GetLatestForDeploymentVersion(ctx context.Context, orgID, application, version string) (*models.SecurityScan, error) {
+	query := `
+		SELECT s.id, s.organization_id, s.user_id, s.name, s.type, s.status, s.target_type, s.target_id,
+			   s.target_name, s.configuration, s.progress, s.started_at, s.completed_at, s.duration,
+			   s.error_message, s.summary, s.created_at, s.updated_at
+		FROM security_scans s
+		JOIN deployments d ON d.id::text = s.target_id
+		WHERE s.organization_id = $1 AND s.target_type = 'deployment' AND s.status = $2
+			AND d.organization_id = $1 AND d.application = $3 AND d.version = $4
+		ORDER BY s.completed_at DESC
+		LIMIT 1
+	`
+
+	scan := &models.SecurityScan{}
+	var configJSON, summaryJSON []byte
+
+	err := r.db.QueryRowContext(ctx, query, orgID, models.ScanStatusCompleted, application, version).Scan(
+		&scan.ID, &scan.OrganizationID, &scan.UserID, &scan.Name, &scan.Type, &scan.Status,
+		&scan.TargetType, &scan.TargetID, &scan.TargetName, &configJSON, &scan.Progress,
+		&scan.StartedAt, &scan.CompletedAt, &scan.Duration, &scan.ErrorMessage, &summaryJSON,
+		&scan.CreatedAt, &scan.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get latest deployment scan: %w", err)
+	}
+
+	if len(configJSON) > 0 {
+		if err := json.Unmarshal(configJSON, &scan.Configuration); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal configuration: %w", err)
+		}
+	}
+	if len(summaryJSON) > 0 {
+		scan.Summary = &models.ScanSummary{}
+		if err := json.Unmarshal(summaryJSON, scan.Summary); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal summary: %w", err)
+		}
+	}
+
+	return scan, nil
+}
@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// StatSnapshotRepository handles statistic snapshot data operations
+type StatSnapshotRepository struct {
+	db *sql.DB
+}
+
+// NewStatSnapshotRepository creates a new stat snapshot repository
+func NewStatSnapshotRepository(db *sql.DB) *StatSnapshotRepository {
+	return &StatSnapshotRepository{db: db}
+}
+
+// Save creates or replaces the snapshot of a statistic for its day
+func (r *StatSnapshotRepository) Save(ctx context.Context, snapshot *models.StatSnapshot) error {
+	query := `
+		INSERT INTO stat_snapshots (organization_id, metric, value, captured_on)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (organization_id, metric, captured_on) DO UPDATE
+		SET value = EXCLUDED.value`
+
+	_, err := r.db.ExecContext(ctx, query, snapshot.OrganizationID, snapshot.Metric, snapshot.Value, snapshot.CapturedOn)
+	if err != nil {
+		return fmt.Errorf("failed to save stat snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestOnOrBefore retrieves the most recent value of each of an organization's statistics
+// captured on or before a day
+func (r *StatSnapshotRepository) GetLatestOnOrBefore(ctx context.Context, orgID string, day time.Time) (map[string]float64, error) {
+	query := `
+		SELECT DISTINCT ON (metric) metric, value
+		FROM stat_snapshots
+		WHERE organization_id = $1 AND captured_on <= $2
+		ORDER BY metric, captured_on DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stat snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	values := make(map[string]float64)
+	for rows.Next() {
+		var metric string
+		var value float64
+		if err := rows.Scan(&metric, &value); err != nil {
+			return nil, fmt.Errorf("failed to scan stat snapshot row: %w", err)
+		}
+		values[metric] = value
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating stat snapshot rows: %w", err)
+	}
+
+	return values, nil
+}
+
+// GetLatestDayOnOrBefore returns the most recent day on or before a day that any of an
+// organization's statistics were captured, or nil if there is none
+func (r *StatSnapshotRepository) GetLatestDayOnOrBefore(ctx context.Context, orgID string, day time.Time) (*time.Time, error) {
+	query := `
+		SELECT MAX(captured_on)
+		FROM stat_snapshots
+		WHERE organization_id = $1 AND captured_on <= $2`
+
+	var latest sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, orgID, day).Scan(&latest); err != nil {
+		return nil, fmt.Errorf("failed to get latest stat snapshot day: %w", err)
+	}
+	if !latest.Valid {
+		return nil, nil
+	}
+
+	return &latest.Time, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"cloudweave/internal/models"
 
@@ -22,8 +23,8 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 // Create creates a new user in the database
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO users (id, email, name, password_hash, organization_id)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO users (id, email, name, password_hash, organization_id, email_verified)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING created_at, updated_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -32,6 +33,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 		user.Name,
 		user.PasswordHash,
 		user.OrganizationID,
+		user.EmailVerified,
 	).Scan(&user.CreatedAt, &user.UpdatedAt)
 
 	if err != nil {
@@ -54,7 +56,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 	user := &models.User{}
 	query := `
 		SELECT id, email, password_hash, name, organization_id, 
-		       created_at, updated_at
+		       COALESCE(email_verified, false), created_at, updated_at
 		FROM users
 		WHERE id = $1`
 
@@ -64,6 +66,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 		&user.PasswordHash,
 		&user.Name,
 		&user.OrganizationID,
+		&user.EmailVerified,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -72,7 +75,6 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 	if err == nil {
 		user.Preferences = make(map[string]interface{})
 		user.Role = "user" // Default role
-		user.EmailVerified = false // Default value
 		user.OnboardingCompleted = false
 		user.DemoMode = true // Default to demo mode for existing users
 		user.DemoScenario = "startup"
@@ -97,7 +99,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 	user := &models.User{}
 	query := `
 		SELECT id, email, password_hash, name, organization_id, 
-		       created_at, updated_at
+		       COALESCE(email_verified, false), created_at, updated_at
 		FROM users
 		WHERE email = $1`
 
@@ -107,6 +109,7 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 		&user.PasswordHash,
 		&user.Name,
 		&user.OrganizationID,
+		&user.EmailVerified,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -115,7 +118,6 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 	if err == nil {
 		user.Preferences = make(map[string]interface{})
 		user.Role = "user" // Default role
-		user.EmailVerified = false // Default value
 		user.OnboardingCompleted = false
 		user.DemoMode = true // Default to demo mode for existing users
 		user.DemoScenario = "startup"
@@ -139,6 +141,49 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 	return user, nil
 }
 
+// GetBySSOProviderSubject retrieves a user by their SSO provider and subject
+func (r *UserRepository) GetBySSOProviderSubject(ctx context.Context, provider, subject string) (*models.User, error) {
+	user := &models.User{}
+	query := `
+		SELECT id, email, password_hash, name, organization_id,
+		       sso_provider, sso_subject, COALESCE(email_verified, false), created_at, updated_at
+		FROM users
+		WHERE sso_provider = $1 AND sso_subject = $2`
+
+	var passwordHash sql.NullString
+	err := r.db.QueryRowContext(ctx, query, provider, subject).Scan(
+		&user.ID,
+		&user.Email,
+		&passwordHash,
+		&user.Name,
+		&user.OrganizationID,
+		&user.SSOProvider,
+		&user.SSOSubject,
+		&user.EmailVerified,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user with SSO provider %s and subject %s not found", provider, subject)
+		}
+		return nil, fmt.Errorf("failed to get user by SSO provider: %w", err)
+	}
+
+	// Set default values for compatibility
+	user.PasswordHash = passwordHash.String
+	user.Preferences = make(map[string]interface{})
+	user.Role = "user" // Default role
+	user.OnboardingCompleted = false
+	user.DemoMode = true // Default to demo mode for existing users
+	user.DemoScenario = "startup"
+	user.IsActive = true
+	user.LastLoginAt = nil
+
+	return user, nil
+}
+
 // Update updates an existing user
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
@@ -184,6 +229,129 @@ func (r *UserRepository) UpdateLastLogin(ctx context.Context, userID string) err
 	return nil
 }
 
+// UpdateSSOInfo links the user to an SSO provider and subject
+func (r *UserRepository) UpdateSSOInfo(ctx context.Context, userID, provider, subject string) error {
+	query := `UPDATE users SET sso_provider = $2, sso_subject = $3, updated_at = NOW() WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, userID, provider, subject)
+	if err != nil {
+		return fmt.Errorf("failed to update SSO info: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user with id %s not found", userID)
+	}
+
+	return nil
+}
+
+// GetMFA retrieves the two-factor authentication settings for a user
+func (r *UserRepository) GetMFA(ctx context.Context, userID string) (*models.UserMFA, error) {
+	mfa := &models.UserMFA{}
+	query := `
+		SELECT id, COALESCE(mfa_enabled, false), COALESCE(mfa_secret, ''), mfa_backup_codes,
+		       mfa_failed_attempts, mfa_locked_until
+		FROM users
+		WHERE id = $1`
+
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&mfa.UserID,
+		&mfa.Enabled,
+		&mfa.Secret,
+		pq.Array(&mfa.BackupCodes),
+		&mfa.FailedAttempts,
+		&mfa.LockedUntil,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("user with id %s not found", userID)
+		}
+		return nil, fmt.Errorf("failed to get MFA settings: %w", err)
+	}
+
+	return mfa, nil
+}
+
+// UpdateMFA stores the user's two-factor authentication settings
+func (r *UserRepository) UpdateMFA(ctx context.Context, mfa *models.UserMFA) error {
+	query := `
+		UPDATE users
+		SET mfa_enabled = $2, mfa_secret = NULLIF($3, ''), mfa_backup_codes = $4, updated_at = NOW()
+		WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, mfa.UserID, mfa.Enabled, mfa.Secret, pq.Array(mfa.BackupCodes))
+	if err != nil {
+		return fmt.Errorf("failed to update MFA settings: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user with id %s not found", mfa.UserID)
+	}
+
+	return nil
+}
+
+// UseTOTPStep records the time step of a TOTP code the user just entered. It reports false when
+// a code of that step or a later one was already accepted, so each code is accepted only once.
+func (r *UserRepository) UseTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	query := `
+		UPDATE users
+		SET mfa_last_totp_step = $2
+		WHERE id = $1 AND (mfa_last_totp_step IS NULL OR mfa_last_totp_step < $2)`
+
+	result, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("failed to record TOTP step: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// RecordMFAFailure counts an invalid MFA code. Reaching maxAttempts locks MFA verification for
+// lockout and starts the count again; it reports whether verification is now locked.
+func (r *UserRepository) RecordMFAFailure(ctx context.Context, userID string, maxAttempts int, lockout time.Duration) (bool, error) {
+	query := `
+		UPDATE users
+		SET mfa_failed_attempts = CASE WHEN mfa_failed_attempts + 1 >= $2 THEN 0 ELSE mfa_failed_attempts + 1 END,
+		    mfa_locked_until = CASE WHEN mfa_failed_attempts + 1 >= $2 THEN NOW() + $3 * INTERVAL '1 second' ELSE mfa_locked_until END
+		WHERE id = $1
+		RETURNING mfa_locked_until IS NOT NULL AND mfa_locked_until > NOW()`
+
+	var locked bool
+	if err := r.db.QueryRowContext(ctx, query, userID, maxAttempts, lockout.Seconds()).Scan(&locked); err != nil {
+		return false, fmt.Errorf("failed to record MFA failure: %w", err)
+	}
+
+	return locked, nil
+}
+
+// ResetMFAFailures clears the user's invalid MFA code count after a valid code
+func (r *UserRepository) ResetMFAFailures(ctx context.Context, userID string) error {
+	query := `UPDATE users SET mfa_failed_attempts = 0, mfa_locked_until = NULL WHERE id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to reset MFA failures: %w", err)
+	}
+
+	return nil
+}
+
 // UpdatePassword updates the user's password hash
 func (r *UserRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
 	query := `UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1`
@@ -205,6 +373,48 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID, passwordHas
 	return nil
 }
 
+// MarkEmailVerified marks the user's email address as verified
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, userID string) error {
+	query := `UPDATE users SET email_verified = true, updated_at = NOW() WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("failed to mark email verified: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("user with id %s not found", userID)
+	}
+
+	return nil
+}
+
+// ClaimVerificationEmail records that a verification email is being sent to an unverified user,
+// returning false when the user is already verified or one was sent within the interval
+func (r *UserRepository) ClaimVerificationEmail(ctx context.Context, userID string, interval time.Duration) (bool, error) {
+	query := `
+		UPDATE users SET email_verification_sent_at = NOW()
+		WHERE id = $1 AND NOT COALESCE(email_verified, false)
+		AND (email_verification_sent_at IS NULL OR email_verification_sent_at <= NOW() - $2 * INTERVAL '1 second')`
+
+	result, err := r.db.ExecContext(ctx, query, userID, interval.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to record verification email: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
 // UpdatePreferences updates the user's preferences (stored in memory only for compatibility)
 func (r *UserRepository) UpdatePreferences(ctx context.Context, userID string, preferences map[string]interface{}) error {
 	// Since preferences column doesn't exist in the current schema, 
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"cloudweave/internal/models"
 
@@ -25,19 +26,20 @@ func NewVulnerabilityRepository(db *sql.DB) *VulnerabilityRepository {
 func (r *VulnerabilityRepository) Create(ctx context.Context, vulnerability *models.Vulnerability) error {
 	query := `
 		INSERT INTO vulnerabilities (
-			id, organization_id, scan_id, title, description, severity, status, cve_id, cvss_score,
-			resource_type, resource_id, resource_name, recommendation, reference_links, tags,
-			first_detected, last_seen, resolved_at, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			id, organization_id, scan_id, fingerprint, title, description, severity, status, cve_id, cvss_score,
+			resource_type, resource_id, resource_name, location, recommendation, reference_links, tags,
+			first_detected, last_seen, scan_count, resolved_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
-		vulnerability.ID, vulnerability.OrganizationID, vulnerability.ScanID, vulnerability.Title,
-		vulnerability.Description, vulnerability.Severity, vulnerability.Status, vulnerability.CVEID,
-		vulnerability.CVSSScore, vulnerability.ResourceType, vulnerability.ResourceID,
-		vulnerability.ResourceName, vulnerability.Recommendation, pq.Array(vulnerability.References),
-		pq.Array(vulnerability.Tags), vulnerability.FirstDetected, vulnerability.LastSeen,
-		vulnerability.ResolvedAt, vulnerability.CreatedAt, vulnerability.UpdatedAt,
+		vulnerability.ID, vulnerability.OrganizationID, vulnerability.ScanID, vulnerability.Fingerprint,
+		vulnerability.Title, vulnerability.Description, vulnerability.Severity, vulnerability.Status,
+		vulnerability.CVEID, vulnerability.CVSSScore, vulnerability.ResourceType, vulnerability.ResourceID,
+		vulnerability.ResourceName, vulnerability.Location, vulnerability.Recommendation,
+		pq.Array(vulnerability.References), pq.Array(vulnerability.Tags), vulnerability.FirstDetected,
+		vulnerability.LastSeen, max(vulnerability.ScanCount, 1), vulnerability.ResolvedAt,
+		vulnerability.CreatedAt, vulnerability.UpdatedAt,
 	)
 
 	if err != nil {
@@ -50,9 +52,9 @@ func (r *VulnerabilityRepository) Create(ctx context.Context, vulnerability *mod
 // GetByID retrieves a vulnerability by ID
 func (r *VulnerabilityRepository) GetByID(ctx context.Context, orgID, id string) (*models.Vulnerability, error) {
 	query := `
-		SELECT id, organization_id, scan_id, title, description, severity, status, cve_id, cvss_score,
-			   resource_type, resource_id, resource_name, recommendation, reference_links, tags,
-			   first_detected, last_seen, resolved_at, created_at, updated_at
+		SELECT id, organization_id, scan_id, fingerprint, title, description, severity, status, cve_id, cvss_score,
+			   resource_type, resource_id, resource_name, location, recommendation, reference_links, tags,
+			   first_detected, last_seen, scan_count, resolved_at, created_at, updated_at
 		FROM vulnerabilities
 		WHERE id = $1 AND organization_id = $2
 	`
@@ -61,12 +63,13 @@ func (r *VulnerabilityRepository) GetByID(ctx context.Context, orgID, id string)
 
 	vulnerability := &models.Vulnerability{}
 	err := row.Scan(
-		&vulnerability.ID, &vulnerability.OrganizationID, &vulnerability.ScanID, &vulnerability.Title,
-		&vulnerability.Description, &vulnerability.Severity, &vulnerability.Status, &vulnerability.CVEID,
-		&vulnerability.CVSSScore, &vulnerability.ResourceType, &vulnerability.ResourceID,
-		&vulnerability.ResourceName, &vulnerability.Recommendation, pq.Array(&vulnerability.References),
-		pq.Array(&vulnerability.Tags), &vulnerability.FirstDetected, &vulnerability.LastSeen,
-		&vulnerability.ResolvedAt, &vulnerability.CreatedAt, &vulnerability.UpdatedAt,
+		&vulnerability.ID, &vulnerability.OrganizationID, &vulnerability.ScanID, &vulnerability.Fingerprint,
+		&vulnerability.Title, &vulnerability.Description, &vulnerability.Severity, &vulnerability.Status,
+		&vulnerability.CVEID, &vulnerability.CVSSScore, &vulnerability.ResourceType, &vulnerability.ResourceID,
+		&vulnerability.ResourceName, &vulnerability.Location, &vulnerability.Recommendation,
+		pq.Array(&vulnerability.References), pq.Array(&vulnerability.Tags), &vulnerability.FirstDetected,
+		&vulnerability.LastSeen, &vulnerability.ScanCount, &vulnerability.ResolvedAt,
+		&vulnerability.CreatedAt, &vulnerability.UpdatedAt,
 	)
 
 	if err != nil {
@@ -79,6 +82,152 @@ func (r *VulnerabilityRepository) GetByID(ctx context.Context, orgID, id string)
 	return vulnerability, nil
 }
 
+// RecordFinding stores a finding a scan found. A finding with the same fingerprint is updated in
+// place instead: its latest scan, last seen time and scan count move on, and it is reopened if it
+// had been fixed or resolved. The vulnerability's ID, status and history fields are set to the
+// stored finding's, and the status it had before is returned, or nil if the finding is new.
+func (r *VulnerabilityRepository) RecordFinding(ctx context.Context, vulnerability *models.Vulnerability) (*models.VulnerabilityStatus, error) {
+	query := `
+		WITH previous AS (
+			SELECT status FROM vulnerabilities WHERE organization_id = $2 AND fingerprint = $4
+		)
+		INSERT INTO vulnerabilities (
+			id, organization_id, scan_id, fingerprint, title, description, severity, status, cve_id, cvss_score,
+			resource_type, resource_id, resource_name, location, recommendation, reference_links, tags,
+			first_detected, last_seen, scan_count, resolved_at, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, 1, NULL, $20, $21)
+		ON CONFLICT (organization_id, fingerprint) DO UPDATE SET
+			scan_id = EXCLUDED.scan_id,
+			title = EXCLUDED.title,
+			description = EXCLUDED.description,
+			severity = EXCLUDED.severity,
+			cve_id = EXCLUDED.cve_id,
+			cvss_score = EXCLUDED.cvss_score,
+			resource_name = EXCLUDED.resource_name,
+			reference_links = EXCLUDED.reference_links,
+			last_seen = EXCLUDED.last_seen,
+			scan_count = vulnerabilities.scan_count + 1,
+			status = CASE WHEN vulnerabilities.status IN ('fixed', 'resolved') THEN 'open' ELSE vulnerabilities.status END,
+			resolved_at = CASE WHEN vulnerabilities.status IN ('fixed', 'resolved') THEN NULL ELSE vulnerabilities.resolved_at END,
+			updated_at = EXCLUDED.updated_at
+		RETURNING id, status, recommendation, tags, first_detected, scan_count, resolved_at, created_at,
+			(SELECT status FROM previous)
+	`
+
+	var previous *models.VulnerabilityStatus
+	err := r.db.QueryRowContext(ctx, query,
+		vulnerability.ID, vulnerability.OrganizationID, vulnerability.ScanID, vulnerability.Fingerprint,
+		vulnerability.Title, vulnerability.Description, vulnerability.Severity, vulnerability.Status,
+		vulnerability.CVEID, vulnerability.CVSSScore, vulnerability.ResourceType, vulnerability.ResourceID,
+		vulnerability.ResourceName, vulnerability.Location, vulnerability.Recommendation,
+		pq.Array(vulnerability.References), pq.Array(vulnerability.Tags), vulnerability.FirstDetected,
+		vulnerability.LastSeen, vulnerability.CreatedAt, vulnerability.UpdatedAt,
+	).Scan(
+		&vulnerability.ID, &vulnerability.Status, &vulnerability.Recommendation, pq.Array(&vulnerability.Tags),
+		&vulnerability.FirstDetected, &vulnerability.ScanCount, &vulnerability.ResolvedAt,
+		&vulnerability.CreatedAt, &previous,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record finding: %w", err)
+	}
+
+	return previous, nil
+}
+
+// ResolveUnseen resolves the active findings of earlier scans of a scan's target that the scan
+// did not find again, recording each change as made by the scan. It returns how many were
+// resolved.
+func (r *VulnerabilityRepository) ResolveUnseen(ctx context.Context, scan *models.SecurityScan, resolvedAt time.Time, reason string) (int64, error) {
+	query := `
+		WITH unseen AS (
+			SELECT v.id, v.status
+			FROM vulnerabilities v
+			JOIN security_scans s ON s.id = v.scan_id
+			WHERE v.organization_id = $1 AND s.type = $2 AND s.target_type = $3 AND s.target_id = $4
+				AND v.scan_id <> $5 AND v.last_seen < $6
+				AND v.status IN ('open', 'acknowledged', 'in_progress')
+			FOR UPDATE OF v
+		), resolved AS (
+			UPDATE vulnerabilities v
+			SET status = 'resolved', resolved_at = $7, updated_at = $7
+			FROM unseen
+			WHERE v.id = unseen.id
+			RETURNING v.id, unseen.status AS from_status
+		)
+		INSERT INTO vulnerability_status_changes (vulnerability_id, organization_id, from_status, to_status, scan_id, reason, changed_at)
+		SELECT id, $1, from_status, 'resolved', $5, $8, $7 FROM resolved
+	`
+
+	startedAt := resolvedAt
+	if scan.StartedAt != nil {
+		startedAt = *scan.StartedAt
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		scan.OrganizationID, scan.Type, scan.TargetType, scan.TargetID, scan.ID, startedAt, resolvedAt, reason,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve unseen findings: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// RecordStatusChange records a finding's move from one status to another
+func (r *VulnerabilityRepository) RecordStatusChange(ctx context.Context, change *models.VulnerabilityStatusChange) error {
+	query := `
+		INSERT INTO vulnerability_status_changes (
+			id, vulnerability_id, organization_id, from_status, to_status, changed_by, scan_id, reason, changed_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		change.ID, change.VulnerabilityID, change.OrganizationID, change.FromStatus, change.ToStatus,
+		change.ChangedBy, change.ScanID, change.Reason, change.ChangedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record vulnerability status change: %w", err)
+	}
+
+	return nil
+}
+
+// GetStatusChanges retrieves a finding's status changes, newest first
+func (r *VulnerabilityRepository) GetStatusChanges(ctx context.Context, orgID, vulnerabilityID string) ([]*models.VulnerabilityStatusChange, error) {
+	query := `
+		SELECT id, vulnerability_id, organization_id, from_status, to_status, changed_by, scan_id,
+			   COALESCE(reason, ''), changed_at
+		FROM vulnerability_status_changes
+		WHERE organization_id = $1 AND vulnerability_id = $2
+		ORDER BY changed_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, vulnerabilityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vulnerability status changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []*models.VulnerabilityStatusChange{}
+	for rows.Next() {
+		change := &models.VulnerabilityStatusChange{}
+		err := rows.Scan(
+			&change.ID, &change.VulnerabilityID, &change.OrganizationID, &change.FromStatus,
+			&change.ToStatus, &change.ChangedBy, &change.ScanID, &change.Reason, &change.ChangedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return changes, nil
+}
+
 // Update updates a vulnerability
 func (r *VulnerabilityRepository) Update(ctx context.Context, vulnerability *models.Vulnerability) error {
 	query := `
@@ -150,6 +299,12 @@ func (r *VulnerabilityRepository) Query(ctx context.Context, orgID string, query
 		argIndex++
 	}
 
+	if len(query.Statuses) > 0 {
+		whereConditions = append(whereConditions, fmt.Sprintf("status = ANY($%d)", argIndex))
+		args = append(args, pq.Array(query.Statuses))
+		argIndex++
+	}
+
 	if query.ResourceType != nil {
 		whereConditions = append(whereConditions, fmt.Sprintf("resource_type = $%d", argIndex))
 		args = append(args, *query.ResourceType)
@@ -202,9 +357,9 @@ func (r *VulnerabilityRepository) Query(ctx context.Context, orgID string, query
 
 	// Get vulnerabilities
 	selectQuery := fmt.Sprintf(`
-		SELECT id, organization_id, scan_id, title, description, severity, status, cve_id, cvss_score,
-			   resource_type, resource_id, resource_name, recommendation, reference_links, tags,
-			   first_detected, last_seen, resolved_at, created_at, updated_at
+		SELECT id, organization_id, scan_id, fingerprint, title, description, severity, status, cve_id, cvss_score,
+			   resource_type, resource_id, resource_name, location, recommendation, reference_links, tags,
+			   first_detected, last_seen, scan_count, resolved_at, created_at, updated_at
 		FROM vulnerabilities
 		WHERE %s
 		ORDER BY created_at DESC
@@ -223,12 +378,13 @@ func (r *VulnerabilityRepository) Query(ctx context.Context, orgID string, query
 	for rows.Next() {
 		vulnerability := &models.Vulnerability{}
 		err := rows.Scan(
-			&vulnerability.ID, &vulnerability.OrganizationID, &vulnerability.ScanID, &vulnerability.Title,
-			&vulnerability.Description, &vulnerability.Severity, &vulnerability.Status, &vulnerability.CVEID,
-			&vulnerability.CVSSScore, &vulnerability.ResourceType, &vulnerability.ResourceID,
-			&vulnerability.ResourceName, &vulnerability.Recommendation, pq.Array(&vulnerability.References),
-			pq.Array(&vulnerability.Tags), &vulnerability.FirstDetected, &vulnerability.LastSeen,
-			&vulnerability.ResolvedAt, &vulnerability.CreatedAt, &vulnerability.UpdatedAt,
+			&vulnerability.ID, &vulnerability.OrganizationID, &vulnerability.ScanID, &vulnerability.Fingerprint,
+			&vulnerability.Title, &vulnerability.Description, &vulnerability.Severity, &vulnerability.Status,
+			&vulnerability.CVEID, &vulnerability.CVSSScore, &vulnerability.ResourceType, &vulnerability.ResourceID,
+			&vulnerability.ResourceName, &vulnerability.Location, &vulnerability.Recommendation,
+			pq.Array(&vulnerability.References), pq.Array(&vulnerability.Tags), &vulnerability.FirstDetected,
+			&vulnerability.LastSeen, &vulnerability.ScanCount, &vulnerability.ResolvedAt,
+			&vulnerability.CreatedAt, &vulnerability.UpdatedAt,
 		)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan row: %w", err)
@@ -277,6 +433,40 @@ func (r *VulnerabilityRepository) GetCountsBySeverity(ctx context.Context, orgID
 	return counts, nil
 }
 
+// GetScanCountsBySeverity gets the counts of a scan's vulnerabilities grouped by severity,
+// optionally only those with the given status
+func (r *VulnerabilityRepository) GetScanCountsBySeverity(ctx context.Context, orgID, scanID string, status *models.VulnerabilityStatus) (map[models.VulnerabilitySeverity]int, error) {
+	query := `
+		SELECT severity, COUNT(*)
+		FROM vulnerabilities
+		WHERE organization_id = $1 AND scan_id = $2 AND ($3::text IS NULL OR status = $3)
+		GROUP BY severity
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, scanID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan severity counts: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[models.VulnerabilitySeverity]int)
+	for rows.Next() {
+		var severity models.VulnerabilitySeverity
+		var count int
+		err := rows.Scan(&severity, &count)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		counts[severity] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return counts, nil
+}
+
 // GetCountsByStatus gets vulnerability counts grouped by status
 func (r *VulnerabilityRepository) GetCountsByStatus(ctx context.Context, orgID string) (map[models.VulnerabilityStatus]int, error) {
 	query := `
@@ -0,0 +1,217 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"cloudweave/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// WebhookRepository handles webhook and webhook delivery data operations
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `id, organization_id, url, secret, events, enabled, created_by, created_at, updated_at`
+
+const webhookDeliveryColumns = `id, organization_id, webhook_id, event_id, event_type, payload, status, attempts, response_status, last_error, delivered_at, created_at, updated_at`
+
+// Create creates a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, organization_id, url, secret, events, enabled, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		webhook.ID, webhook.OrganizationID, webhook.URL, webhook.Secret,
+		pq.Array(webhook.Events), webhook.Enabled, webhook.CreatedBy,
+	).Scan(&webhook.CreatedAt, &webhook.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a webhook within an organization
+func (r *WebhookRepository) GetByID(ctx context.Context, id, orgID string) (*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1 AND organization_id = $2`
+
+	webhooks, err := r.query(ctx, query, id, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, fmt.Errorf("webhook with id %s not found", id)
+	}
+
+	return webhooks[0], nil
+}
+
+// List retrieves an organization's webhooks
+func (r *WebhookRepository) List(ctx context.Context, orgID string) ([]*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE organization_id = $1 ORDER BY created_at ASC`
+
+	return r.query(ctx, query, orgID)
+}
+
+// ListEnabled retrieves an organization's enabled webhooks
+func (r *WebhookRepository) ListEnabled(ctx context.Context, orgID string) ([]*models.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE organization_id = $1 AND enabled = true`
+
+	return r.query(ctx, query, orgID)
+}
+
+// Delete deletes a webhook within an organization
+func (r *WebhookRepository) Delete(ctx context.Context, id, orgID string) error {
+	query := `DELETE FROM webhooks WHERE id = $1 AND organization_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, id, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook with id %s not found", id)
+	}
+
+	return nil
+}
+
+// CreateDelivery records a new webhook delivery
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (id, organization_id, webhook_id, event_id, event_type, payload, status, attempts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		delivery.ID, delivery.OrganizationID, delivery.WebhookID, delivery.EventID, delivery.EventType,
+		[]byte(delivery.Payload), delivery.Status, delivery.Attempts,
+	).Scan(&delivery.CreatedAt, &delivery.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDelivery stores the outcome of a delivery attempt
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_status = $4, last_error = $5, delivered_at = $6
+		WHERE id = $1
+		RETURNING updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError, delivery.DeliveredAt,
+	).Scan(&delivery.UpdatedAt)
+
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeliveries retrieves a webhook's most recent deliveries, optionally only those with a status
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, orgID, webhookID, status string, limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE organization_id = $1 AND webhook_id = $2 AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, orgID, webhookID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		delivery := &models.WebhookDelivery{}
+		var payload []byte
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.OrganizationID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.ResponseStatus,
+			&delivery.LastError,
+			&delivery.DeliveredAt,
+			&delivery.CreatedAt,
+			&delivery.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery row: %w", err)
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook delivery rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// query runs a query selecting webhookColumns and scans the results
+func (r *WebhookRepository) query(ctx context.Context, query string, args ...interface{}) ([]*models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []*models.Webhook{}
+	for rows.Next() {
+		webhook := &models.Webhook{}
+		var events pq.StringArray
+		err := rows.Scan(
+			&webhook.ID,
+			&webhook.OrganizationID,
+			&webhook.URL,
+			&webhook.Secret,
+			&events,
+			&webhook.Enabled,
+			&webhook.CreatedBy,
+			&webhook.CreatedAt,
+			&webhook.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook row: %w", err)
+		}
+		webhook.Events = []string(events)
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook rows: %w", err)
+	}
+
+	return webhooks, nil
+}
@@ -2,16 +2,31 @@ package services
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"cloudweave/internal/models"
 	"cloudweave/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Alert errors
+var (
+	ErrAlertNotFound      = errors.New("alert not found")
+	ErrInvalidAlertStatus = errors.New("invalid alert status")
+	ErrInvalidBulkRequest = errors.New("invalid bulk alert request")
 )
 
+// maxBulkAlerts is the most alerts one bulk acknowledge or resolve changes
+const maxBulkAlerts = 500
+
 // AlertService handles alert creation, management, and notifications
 type AlertService struct {
 	repoManager *repositories.RepositoryManager
+	notifier    *NotificationService
 }
 
 // NewAlertService creates a new alert service
@@ -21,6 +36,11 @@ func NewAlertService(repoManager *repositories.RepositoryManager) *AlertService
 	}
 }
 
+// SetNotificationService sets the service that delivers new alerts to notification channels
+func (s *AlertService) SetNotificationService(notifier *NotificationService) {
+	s.notifier = notifier
+}
+
 // CreateAlert creates a new alert
 func (s *AlertService) CreateAlert(ctx context.Context, alert *models.Alert) error {
 	// Validate alert
@@ -36,6 +56,10 @@ func (s *AlertService) CreateAlert(ctx context.Context, alert *models.Alert) err
 		alert.Severity = "info" // Default severity
 	}
 
+	if alert.ID == "" {
+		alert.ID = uuid.New().String()
+	}
+
 	// Alert doesn't have a Status field, it uses Acknowledged instead
 
 	// Set timestamps
@@ -44,7 +68,37 @@ func (s *AlertService) CreateAlert(ctx context.Context, alert *models.Alert) err
 	alert.UpdatedAt = now
 
 	// Store in database
-	return s.repoManager.Alert.Create(ctx, alert)
+	if err := s.repoManager.Alert.Create(ctx, alert); err != nil {
+		return err
+	}
+
+	if err := s.recordAlertEvent(ctx, alert, models.AlertEventCreated, nil); err != nil {
+		return err
+	}
+
+	// Deliver notifications in the background so alert creation never waits on slow channels
+	if s.notifier != nil {
+		fired := *alert
+		go s.notifier.NotifyAlert(context.Background(), &fired)
+	}
+
+	return nil
+}
+
+// GetAlertDetail retrieves an alert and its state-change history. Alerts belonging to another
+// organization are reported as not found.
+func (s *AlertService) GetAlertDetail(ctx context.Context, orgID, alertID string) (*models.AlertDetail, error) {
+	alert, err := s.repoManager.Alert.GetByID(ctx, alertID)
+	if err != nil || alert.OrganizationID != orgID {
+		return nil, ErrAlertNotFound
+	}
+
+	history, err := s.repoManager.AlertEvent.ListByAlert(ctx, alertID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get alert history: %w", err)
+	}
+
+	return &models.AlertDetail{Alert: *alert, History: history}, nil
 }
 
 // GetAlerts retrieves alerts with filtering options
@@ -66,33 +120,53 @@ func (s *AlertService) GetAlerts(ctx context.Context, orgID string, filters Aler
 	return result, nil
 }
 
-// UpdateAlertStatus updates the status of an alert
-func (s *AlertService) UpdateAlertStatus(ctx context.Context, alertID string, status string) error {
+// UpdateAlertStatus updates the status of one of an organization's alerts and records the change in its history
+func (s *AlertService) UpdateAlertStatus(ctx context.Context, orgID, alertID string, status string, userID string) error {
+	var eventType string
+	switch status {
+	case "acknowledged":
+		eventType = models.AlertEventAcknowledged
+	case "resolved":
+		eventType = models.AlertEventResolved
+	case "active":
+		eventType = models.AlertEventReopened
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidAlertStatus, status)
+	}
+
 	alert, err := s.repoManager.Alert.GetByID(ctx, alertID)
-	if err != nil {
-		return fmt.Errorf("failed to get alert: %w", err)
+	if err != nil || alert.OrganizationID != orgID {
+		return ErrAlertNotFound
 	}
 
-	// Update acknowledgment status based on status
-	if status == "acknowledged" {
-		now := time.Now()
-		alert.Acknowledged = true
-		alert.AcknowledgedAt = &now
-	} else if status == "active" {
+	// Update acknowledgment status based on status; resolved alerts are no longer active
+	now := time.Now()
+	if status == "active" {
 		alert.Acknowledged = false
+		alert.AcknowledgedBy = nil
 		alert.AcknowledgedAt = nil
+	} else if !alert.Acknowledged {
+		alert.Acknowledged = true
+		alert.AcknowledgedAt = &now
+		if userID != "" {
+			alert.AcknowledgedBy = &userID
+		}
 	}
 
-	alert.UpdatedAt = time.Now()
+	alert.UpdatedAt = now
+
+	if err := s.repoManager.Alert.Update(ctx, alert); err != nil {
+		return err
+	}
 
-	return s.repoManager.Alert.Update(ctx, alert)
+	return s.recordAlertEvent(ctx, alert, eventType, &userID)
 }
 
-// AcknowledgeAlert acknowledges an alert
-func (s *AlertService) AcknowledgeAlert(ctx context.Context, alertID string, userID string) error {
+// AcknowledgeAlert acknowledges one of an organization's alerts
+func (s *AlertService) AcknowledgeAlert(ctx context.Context, orgID, alertID string, userID string) error {
 	alert, err := s.repoManager.Alert.GetByID(ctx, alertID)
-	if err != nil {
-		return fmt.Errorf("failed to get alert: %w", err)
+	if err != nil || alert.OrganizationID != orgID {
+		return ErrAlertNotFound
 	}
 
 	now := time.Now()
@@ -101,7 +175,170 @@ func (s *AlertService) AcknowledgeAlert(ctx context.Context, alertID string, use
 	alert.AcknowledgedAt = &now
 	alert.UpdatedAt = now
 
-	return s.repoManager.Alert.Update(ctx, alert)
+	if err := s.repoManager.Alert.Update(ctx, alert); err != nil {
+		return err
+	}
+
+	return s.recordAlertEvent(ctx, alert, models.AlertEventAcknowledged, &userID)
+}
+
+// BulkAcknowledgeAlerts acknowledges many of an organization's alerts in one transaction,
+// recording each change in the alert's history. Alerts already acknowledged are left unchanged.
+// A filter without an acknowledged criterion only selects unacknowledged alerts.
+func (s *AlertService) BulkAcknowledgeAlerts(ctx context.Context, orgID, userID string, req *models.BulkAlertRequest) ([]models.BulkAlertResult, error) {
+	return s.bulkUpdateAlerts(ctx, orgID, userID, req, models.AlertEventAcknowledged)
+}
+
+// BulkResolveAlerts resolves many of an organization's alerts in one transaction, acknowledging
+// those not yet acknowledged and recording each change in the alert's history
+func (s *AlertService) BulkResolveAlerts(ctx context.Context, orgID, userID string, req *models.BulkAlertRequest) ([]models.BulkAlertResult, error) {
+	return s.bulkUpdateAlerts(ctx, orgID, userID, req, models.AlertEventResolved)
+}
+
+// bulkUpdateAlerts applies an acknowledged or resolved transition to the selected alerts, locking
+// them for the transaction so concurrent changes cannot interleave. Alerts that do not exist or
+// belong to another organization are reported as not found.
+func (s *AlertService) bulkUpdateAlerts(ctx context.Context, orgID, userID string, req *models.BulkAlertRequest, eventType string) ([]models.BulkAlertResult, error) {
+	ids, err := s.bulkAlertIDs(ctx, orgID, req, eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.BulkAlertResult, len(ids))
+	resultIndex := make(map[string]int, len(ids))
+	var lookup []string
+	for i, id := range ids {
+		results[i] = models.BulkAlertResult{AlertID: id, Status: models.BulkAlertNotFound}
+		if parsed, err := uuid.Parse(id); err == nil {
+			resultIndex[parsed.String()] = i
+			lookup = append(lookup, parsed.String())
+		}
+	}
+	if len(lookup) == 0 {
+		return results, nil
+	}
+
+	var actorID *string
+	if userID != "" {
+		actorID = &userID
+	}
+
+	err = s.repoManager.Transaction.WithTransaction(ctx, func(tx *sql.Tx) error {
+		alerts, err := s.repoManager.Alert.ListForUpdateTx(ctx, tx, orgID, lookup)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, alert := range alerts {
+			result := &results[resultIndex[alert.ID]]
+			if alert.Acknowledged && eventType == models.AlertEventAcknowledged {
+				result.Status = models.BulkAlertUnchanged
+				continue
+			}
+
+			if !alert.Acknowledged {
+				alert.Acknowledged = true
+				alert.AcknowledgedBy = actorID
+				alert.AcknowledgedAt = &now
+				if err := s.repoManager.Alert.UpdateAcknowledgementTx(ctx, tx, alert); err != nil {
+					return err
+				}
+			}
+
+			event := &models.AlertEvent{
+				ID:             uuid.New().String(),
+				AlertID:        alert.ID,
+				OrganizationID: alert.OrganizationID,
+				EventType:      eventType,
+				ActorID:        actorID,
+			}
+			if err := s.repoManager.AlertEvent.CreateTx(ctx, tx, event); err != nil {
+				return fmt.Errorf("failed to record alert %s event: %w", eventType, err)
+			}
+
+			result.Status = models.BulkAlertAcknowledged
+			if eventType == models.AlertEventResolved {
+				result.Status = models.BulkAlertResolved
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update alerts: %w", err)
+	}
+
+	return results, nil
+}
+
+// bulkAlertIDs returns the IDs a bulk request selects, without duplicates: the listed IDs, or
+// those of the organization's alerts matching the filter, newest first
+func (s *AlertService) bulkAlertIDs(ctx context.Context, orgID string, req *models.BulkAlertRequest, eventType string) ([]string, error) {
+	if len(req.AlertIDs) > 0 && req.Filter != nil {
+		return nil, fmt.Errorf("%w: select alerts by ID or by filter, not both", ErrInvalidBulkRequest)
+	}
+
+	if len(req.AlertIDs) > 0 {
+		if len(req.AlertIDs) > maxBulkAlerts {
+			return nil, fmt.Errorf("%w: at most %d alerts can be changed at once", ErrInvalidBulkRequest, maxBulkAlerts)
+		}
+		seen := make(map[string]bool, len(req.AlertIDs))
+		var ids []string
+		for _, id := range req.AlertIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	if req.Filter == nil {
+		return nil, fmt.Errorf("%w: alertIds or filter is required", ErrInvalidBulkRequest)
+	}
+
+	filter := *req.Filter
+	if filter.Type == nil && filter.Severity == nil && filter.ResourceID == nil && filter.ResourceType == nil {
+		return nil, fmt.Errorf("%w: the filter needs a type, severity, resourceId or resourceType", ErrInvalidBulkRequest)
+	}
+	if filter.Acknowledged == nil && eventType == models.AlertEventAcknowledged {
+		unacknowledged := false
+		filter.Acknowledged = &unacknowledged
+	}
+	filter.Limit = maxBulkAlerts
+	filter.Offset = 0
+
+	alerts, err := s.repoManager.Alert.Query(ctx, orgID, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+	}
+	return ids, nil
+}
+
+// recordAlertEvent appends a state change to an alert's history
+func (s *AlertService) recordAlertEvent(ctx context.Context, alert *models.Alert, eventType string, actorID *string) error {
+	if actorID != nil && *actorID == "" {
+		actorID = nil
+	}
+
+	event := &models.AlertEvent{
+		ID:             uuid.New().String(),
+		AlertID:        alert.ID,
+		OrganizationID: alert.OrganizationID,
+		EventType:      eventType,
+		ActorID:        actorID,
+	}
+
+	if err := s.repoManager.AlertEvent.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record alert %s event: %w", eventType, err)
+	}
+
+	return nil
 }
 
 // GetActiveAlerts retrieves active alerts for an organization
@@ -168,29 +405,47 @@ func (s *AlertService) GetAlertSummary(ctx context.Context, orgID string) (*Aler
 	return summary, nil
 }
 
-// CreateAlertRule creates a new alert rule
-func (s *AlertService) CreateAlertRule(ctx context.Context, rule *AlertRule) error {
+// CreateAlertRule validates and stores a new alert rule
+func (s *AlertService) CreateAlertRule(ctx context.Context, rule *models.AlertRule) error {
 	// Validate rule
 	if rule.Name == "" {
 		return fmt.Errorf("rule name is required")
 	}
 
-	if rule.Condition == "" {
-		return fmt.Errorf("rule condition is required")
+	if rule.MetricName == "" {
+		return fmt.Errorf("rule metric name is required")
+	}
+
+	switch rule.Operator {
+	case models.AlertOperatorGreaterThan, models.AlertOperatorGreaterThanOrEqual,
+		models.AlertOperatorLessThan, models.AlertOperatorLessThanOrEqual, models.AlertOperatorEqual:
+	default:
+		return fmt.Errorf("unsupported rule operator %s", rule.Operator)
+	}
+
+	if rule.DurationSeconds < 0 {
+		return fmt.Errorf("rule duration cannot be negative")
 	}
 
 	if rule.Severity == "" {
-		rule.Severity = "warning" // Default severity
+		rule.Severity = models.AlertSeverityWarning // Default severity
 	}
 
-	// Set timestamps
-	now := time.Now()
-	rule.CreatedAt = now
-	rule.UpdatedAt = now
+	if rule.ID == "" {
+		rule.ID = uuid.New().String()
+	}
 
-	// Store in database (this would require an AlertRule model and repository)
-	// For now, we'll just return success
-	return nil
+	return s.repoManager.AlertRule.Create(ctx, rule)
+}
+
+// GetAlertRules retrieves an organization's alert rules
+func (s *AlertService) GetAlertRules(ctx context.Context, orgID string) ([]*models.AlertRule, error) {
+	return s.repoManager.AlertRule.List(ctx, orgID)
+}
+
+// DeleteAlertRule deletes an alert rule
+func (s *AlertService) DeleteAlertRule(ctx context.Context, ruleID, orgID string) error {
+	return s.repoManager.AlertRule.Delete(ctx, ruleID, orgID)
 }
 
 // AlertFilters represents filters for alert queries
@@ -217,84 +472,3 @@ type AlertSummary struct {
 	AlertsByType       map[string]int `json:"alertsByType"`
 	AlertsBySeverity   map[string]int `json:"alertsBySeverity"`
 }
-
-// AlertRule represents an alert rule configuration
-type AlertRule struct {
-	ID           string                 `json:"id"`
-	Name         string                 `json:"name"`
-	Description  string                 `json:"description"`
-	Condition    string                 `json:"condition"`
-	Severity     string                 `json:"severity"`
-	Message      string                 `json:"message"`
-	ResourceType string                 `json:"resourceType"`
-	Provider     string                 `json:"provider"`
-	Enabled      bool                   `json:"enabled"`
-	Parameters   map[string]interface{} `json:"parameters"`
-	CreatedAt    time.Time              `json:"createdAt"`
-	UpdatedAt    time.Time              `json:"updatedAt"`
-}
-
-// NotificationService handles alert notifications
-type NotificationService struct {
-	alertService *AlertService
-}
-
-// NewNotificationService creates a new notification service
-func NewNotificationService(alertService *AlertService) *NotificationService {
-	return &NotificationService{
-		alertService: alertService,
-	}
-}
-
-// SendNotification sends a notification for an alert
-func (s *NotificationService) SendNotification(ctx context.Context, alert *models.Alert, channels []string) error {
-	// This would integrate with various notification channels:
-	// - Email
-	// - Slack
-	// - Teams
-	// - PagerDuty
-	// - Webhook
-
-	for _, channel := range channels {
-		switch channel {
-		case "email":
-			if err := s.sendEmailNotification(ctx, alert); err != nil {
-				fmt.Printf("Failed to send email notification: %v\n", err)
-			}
-		case "slack":
-			if err := s.sendSlackNotification(ctx, alert); err != nil {
-				fmt.Printf("Failed to send Slack notification: %v\n", err)
-			}
-		case "webhook":
-			if err := s.sendWebhookNotification(ctx, alert); err != nil {
-				fmt.Printf("Failed to send webhook notification: %v\n", err)
-			}
-		}
-	}
-
-	return nil
-}
-
-// sendEmailNotification sends an email notification
-func (s *NotificationService) sendEmailNotification(ctx context.Context, alert *models.Alert) error {
-	// This would integrate with an email service like SendGrid, AWS SES, etc.
-	// For now, we'll just log the notification
-	fmt.Printf("Email notification sent for alert %s: %s\n", alert.ID, alert.Message)
-	return nil
-}
-
-// sendSlackNotification sends a Slack notification
-func (s *NotificationService) sendSlackNotification(ctx context.Context, alert *models.Alert) error {
-	// This would integrate with Slack API
-	// For now, we'll just log the notification
-	fmt.Printf("Slack notification sent for alert %s: %s\n", alert.ID, alert.Message)
-	return nil
-}
-
-// sendWebhookNotification sends a webhook notification
-func (s *NotificationService) sendWebhookNotification(ctx context.Context, alert *models.Alert) error {
-	// This would send a POST request to a configured webhook URL
-	// For now, we'll just log the notification
-	fmt.Printf("Webhook notification sent for alert %s: %s\n", alert.ID, alert.Message)
-	return nil
-}
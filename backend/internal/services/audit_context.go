@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"net"
+
+	"cloudweave/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditContext describes where an audited request came from and, when it was made while
+// impersonating a user, the support user behind it
+type AuditContext struct {
+	IPAddress      string
+	UserAgent      string
+	ImpersonatedBy string
+}
+
+type auditContextKey struct{}
+
+// WithAuditContext returns a copy of ctx carrying the audit context
+func WithAuditContext(ctx context.Context, auditCtx *AuditContext) context.Context {
+	return context.WithValue(ctx, auditContextKey{}, auditCtx)
+}
+
+// AuditContextFromContext returns the audit context stored in ctx. Gin contexts are resolved
+// through their request context.
+func AuditContextFromContext(ctx context.Context) *AuditContext {
+	if ctx == nil {
+		return nil
+	}
+	if gc, ok := ctx.(*gin.Context); ok {
+		if gc.Request == nil {
+			return nil
+		}
+		ctx = gc.Request.Context()
+	}
+
+	auditCtx, _ := ctx.Value(auditContextKey{}).(*AuditContext)
+	return auditCtx
+}
+
+// apply copies the IP address, user agent and impersonator onto an audit log entry
+func (a *AuditContext) apply(logEntry *models.AuditLog) {
+	if a == nil {
+		return
+	}
+
+	if a.UserAgent != "" {
+		userAgent := a.UserAgent
+		logEntry.UserAgent = &userAgent
+	}
+	if ip := net.ParseIP(a.IPAddress); ip != nil {
+		logEntry.IPAddress = &ip
+	}
+	if a.ImpersonatedBy != "" {
+		impersonatedBy := a.ImpersonatedBy
+		logEntry.ImpersonatedBy = &impersonatedBy
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordingAuditRepo keeps the audit logs written to it
+type recordingAuditRepo struct {
+	repositories.AuditLogRepositoryInterface
+
+	logs []*models.AuditLog
+}
+
+func (r *recordingAuditRepo) CreateBatch(ctx context.Context, logs []*models.AuditLog) error {
+	r.logs = append(r.logs, logs...)
+	return nil
+}
+
+func TestRecordCapturesRequestOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &recordingAuditRepo{}
+	writer := NewAuditWriter(repo, 10)
+	auditService := NewAuditService(repo, writer)
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/roles", nil)
+	c.Request.RemoteAddr = "198.51.100.20:40000"
+	c.Request.Header.Set("User-Agent", "audit-test/2.0")
+	c.Set("organizationId", "org-1")
+
+	if err := auditService.Record(c, "role_created", "role", "role-1", nil); err != nil {
+		t.Fatalf("Record returned an error: %v", err)
+	}
+	if err := writer.Flush(context.Background()); err != nil {
+		t.Fatalf("failed to flush audit logs: %v", err)
+	}
+
+	if len(repo.logs) != 1 {
+		t.Fatalf("wrote %d audit logs, want 1", len(repo.logs))
+	}
+	entry := repo.logs[0]
+	if entry.IPAddress == nil || entry.IPAddress.String() != "198.51.100.20" {
+		t.Errorf("audit log IP address = %v, want 198.51.100.20", entry.IPAddress)
+	}
+	if entry.UserAgent == nil || *entry.UserAgent != "audit-test/2.0" {
+		t.Errorf("audit log user agent = %v, want audit-test/2.0", entry.UserAgent)
+	}
+}
+
+func TestAuditContextApply(t *testing.T) {
+	auditCtx := &AuditContext{IPAddress: "2001:db8::1", UserAgent: "curl/8.0", ImpersonatedBy: "support-1"}
+
+	entry := &models.AuditLog{}
+	auditCtx.apply(entry)
+
+	if entry.IPAddress == nil || entry.IPAddress.String() != "2001:db8::1" {
+		t.Errorf("IP address = %v, want 2001:db8::1", entry.IPAddress)
+	}
+	if entry.UserAgent == nil || *entry.UserAgent != "curl/8.0" {
+		t.Errorf("user agent = %v, want curl/8.0", entry.UserAgent)
+	}
+	if entry.ImpersonatedBy == nil || *entry.ImpersonatedBy != "support-1" {
+		t.Errorf("impersonated by = %v, want support-1", entry.ImpersonatedBy)
+	}
+}
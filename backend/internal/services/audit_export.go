@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// Audit log export formats
+const (
+	AuditExportJSON = "json"
+	AuditExportCSV  = "csv"
+	AuditExportPDF  = "pdf"
+)
+
+// auditExportBatchSize is how many audit logs are loaded per query while exporting
+const auditExportBatchSize = 500
+
+// auditCSVColumns are the columns written by the CSV export
+var auditCSVColumns = []string{"ID", "Timestamp", "User ID", "Action", "Resource Type", "Resource ID", "IP Address", "User Agent", "Details"}
+
+// auditPDFColumns are the columns that fit across a page of the PDF export
+var auditPDFColumns = []string{"Timestamp", "User", "Action", "Resource Type", "Resource ID", "IP Address"}
+
+// EachAuditLog calls fn for every audit log matching the query, loading them in batches so large
+// exports are not held in memory. Batches continue from the last log loaded rather than by offset,
+// so each query stays cheap however deep into the export it is.
+func (s *AuditService) EachAuditLog(ctx context.Context, orgID string, query models.AuditLogQuery, fn func(*models.AuditLog) error) error {
+	query.Limit = auditExportBatchSize
+	query.Offset = 0
+	query.Cursor = ""
+
+	for {
+		logs, err := s.repo.Query(ctx, orgID, query)
+		if err != nil {
+			return err
+		}
+
+		for _, log := range logs {
+			if err := fn(log); err != nil {
+				return err
+			}
+		}
+
+		if len(logs) < auditExportBatchSize {
+			return nil
+		}
+		last := logs[len(logs)-1]
+		query.Cursor = repositories.EncodeCursor(last.CreatedAt, last.ID)
+	}
+}
+
+// ExportJSON streams matching audit logs to w as a JSON array
+func (s *AuditService) ExportJSON(ctx context.Context, w io.Writer, orgID string, query models.AuditLogQuery) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	first := true
+	err := s.EachAuditLog(ctx, orgID, query, func(log *models.AuditLog) error {
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		data, err := json.Marshal(log)
+		if err != nil {
+			return fmt.Errorf("failed to marshal audit log: %w", err)
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+// ExportCSV streams matching audit logs to w as CSV, flushing after every batch
+func (s *AuditService) ExportCSV(ctx context.Context, w io.Writer, orgID string, query models.AuditLogQuery) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(auditCSVColumns); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	written := 0
+	err := s.EachAuditLog(ctx, orgID, query, func(log *models.AuditLog) error {
+		if err := writer.Write(auditCSVRow(log)); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+
+		written++
+		if written%auditExportBatchSize == 0 {
+			writer.Flush()
+			if flusher, ok := w.(interface{ Flush() }); ok {
+				flusher.Flush()
+			}
+		}
+		return writer.Error()
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportPDF renders matching audit logs to w as a paginated PDF report headed with the
+// organization name and date range
+func (s *AuditService) ExportPDF(ctx context.Context, w io.Writer, orgName, orgID string, query models.AuditLogQuery) error {
+	report := newPDFReport(w, []string{
+		orgName,
+		"Audit Log Report",
+		fmt.Sprintf("%s to %s", query.StartTime.Format("2006-01-02"), query.EndTime.Format("2006-01-02")),
+	}, auditPDFColumns, []float64{40, 150, 250, 340, 420, 500})
+
+	if err := report.begin(); err != nil {
+		return err
+	}
+
+	err := s.EachAuditLog(ctx, orgID, query, func(log *models.AuditLog) error {
+		return report.addRow(auditPDFRow(log))
+	})
+	if err != nil {
+		return err
+	}
+
+	return report.finish()
+}
+
+// auditCSVRow formats an audit log as the CSV export columns
+func auditCSVRow(log *models.AuditLog) []string {
+	var userAgent, details string
+	if log.UserAgent != nil {
+		userAgent = *log.UserAgent
+	}
+	if log.Details != nil {
+		details = fmt.Sprintf("%v", log.Details)
+	}
+
+	return append([]string{log.ID}, append(auditPDFRow(log), userAgent, details)...)
+}
+
+// auditPDFRow formats an audit log as the PDF export columns
+func auditPDFRow(log *models.AuditLog) []string {
+	var userID, resourceType, resourceID, ipAddress string
+
+	if log.UserID != nil {
+		userID = *log.UserID
+	}
+	if log.ResourceType != nil {
+		resourceType = *log.ResourceType
+	}
+	if log.ResourceID != nil {
+		resourceID = *log.ResourceID
+	}
+	if log.IPAddress != nil {
+		ipAddress = log.IPAddress.String()
+	}
+
+	return []string{
+		log.CreatedAt.Format(time.RFC3339),
+		userID,
+		log.Action,
+		resourceType,
+		resourceID,
+		ipAddress,
+	}
+}
+
+// PDF page layout, in points on a US Letter page
+const (
+	pdfPageWidth    = 612
+	pdfPageHeight   = 792
+	pdfMarginTop    = 740
+	pdfMarginBottom = 50
+	pdfLineHeight   = 12
+	pdfFontSize     = 8
+	// pdfCharWidth approximates the width of a Helvetica character at pdfFontSize, used to truncate cells
+	pdfCharWidth = 4.2
+)
+
+// pdfReport writes a simple text table as a PDF. Pages are written as soon as they fill up so
+// only the current page is held in memory.
+type pdfReport struct {
+	w       *countingWriter
+	header  []string
+	columns []string
+	colX    []float64
+
+	offsets  map[int]int64
+	nextObj  int
+	pageObjs []int
+	page     strings.Builder
+	y        float64
+}
+
+// countingWriter tracks the byte offset needed for the PDF cross-reference table
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Object numbers 1-3 are reserved for the catalog, page tree and font
+const (
+	pdfCatalogObj = 1
+	pdfPagesObj   = 2
+	pdfFontObj    = 3
+)
+
+func newPDFReport(w io.Writer, header, columns []string, colX []float64) *pdfReport {
+	return &pdfReport{
+		w:       &countingWriter{w: w},
+		header:  header,
+		columns: columns,
+		colX:    colX,
+		offsets: make(map[int]int64),
+		nextObj: pdfFontObj + 1,
+	}
+}
+
+func (r *pdfReport) begin() error {
+	if _, err := io.WriteString(r.w, "%PDF-1.4\n"); err != nil {
+		return err
+	}
+	if err := r.writeObject(pdfFontObj, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>"); err != nil {
+		return err
+	}
+	r.startPage()
+	return nil
+}
+
+func (r *pdfReport) startPage() {
+	r.page.Reset()
+	r.y = pdfMarginTop
+
+	for i, line := range r.header {
+		size := pdfFontSize + 2
+		if i == 0 {
+			size = pdfFontSize + 6
+		}
+		r.text(r.colX[0], r.y, size, line)
+		r.y -= float64(size) + 4
+	}
+	r.text(pdfPageWidth-100, pdfMarginTop, pdfFontSize, fmt.Sprintf("Page %d", len(r.pageObjs)+1))
+
+	r.y -= pdfLineHeight / 2
+	r.row(r.columns)
+	r.page.WriteString(fmt.Sprintf("%.1f %.1f m %.1f %.1f l S\n", r.colX[0], r.y+pdfLineHeight-3, float64(pdfPageWidth-40), r.y+pdfLineHeight-3))
+}
+
+func (r *pdfReport) addRow(cells []string) error {
+	if r.y < pdfMarginBottom {
+		if err := r.flushPage(); err != nil {
+			return err
+		}
+		r.startPage()
+	}
+
+	r.row(cells)
+	return nil
+}
+
+func (r *pdfReport) row(cells []string) {
+	for i, cell := range cells {
+		if i >= len(r.colX) {
+			break
+		}
+
+		width := float64(pdfPageWidth - 40)
+		if i+1 < len(r.colX) {
+			width = r.colX[i+1]
+		}
+		r.text(r.colX[i], r.y, pdfFontSize, truncatePDFText(cell, int((width-r.colX[i])/pdfCharWidth)))
+	}
+	r.y -= pdfLineHeight
+}
+
+func (r *pdfReport) text(x, y float64, size int, value string) {
+	r.page.WriteString(fmt.Sprintf("BT /F1 %d Tf %.1f %.1f Td (%s) Tj ET\n", size, x, y, escapePDFText(value)))
+}
+
+// flushPage writes the current page's content stream and page object
+func (r *pdfReport) flushPage() error {
+	content := r.page.String()
+
+	contentObj := r.nextObj
+	pageObj := r.nextObj + 1
+	r.nextObj += 2
+
+	if err := r.writeObject(contentObj, fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content)); err != nil {
+		return err
+	}
+
+	page := fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+		pdfPagesObj, pdfPageWidth, pdfPageHeight, pdfFontObj, contentObj)
+	if err := r.writeObject(pageObj, page); err != nil {
+		return err
+	}
+
+	r.pageObjs = append(r.pageObjs, pageObj)
+	return nil
+}
+
+// finish writes the last page, the page tree, catalog and cross-reference table
+func (r *pdfReport) finish() error {
+	if err := r.flushPage(); err != nil {
+		return err
+	}
+
+	kids := make([]string, len(r.pageObjs))
+	for i, obj := range r.pageObjs {
+		kids[i] = fmt.Sprintf("%d 0 R", obj)
+	}
+	if err := r.writeObject(pdfPagesObj, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))); err != nil {
+		return err
+	}
+	if err := r.writeObject(pdfCatalogObj, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pdfPagesObj)); err != nil {
+		return err
+	}
+
+	xrefOffset := r.w.n
+	var xref strings.Builder
+	xref.WriteString(fmt.Sprintf("xref\n0 %d\n0000000000 65535 f \n", r.nextObj))
+	for obj := 1; obj < r.nextObj; obj++ {
+		xref.WriteString(fmt.Sprintf("%010d 00000 n \n", r.offsets[obj]))
+	}
+	xref.WriteString(fmt.Sprintf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", r.nextObj, pdfCatalogObj, xrefOffset))
+
+	_, err := io.WriteString(r.w, xref.String())
+	return err
+}
+
+func (r *pdfReport) writeObject(obj int, body string) error {
+	r.offsets[obj] = r.w.n
+	_, err := fmt.Fprintf(r.w, "%d 0 obj\n%s\nendobj\n", obj, body)
+	return err
+}
+
+// escapePDFText escapes a string for a PDF literal, replacing characters outside printable ASCII
+func escapePDFText(value string) string {
+	var b strings.Builder
+	for _, ch := range value {
+		switch {
+		case ch == '(' || ch == ')' || ch == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(ch)
+		case ch < 32 || ch > 126:
+			b.WriteRune('?')
+		default:
+			b.WriteRune(ch)
+		}
+	}
+	return b.String()
+}
+
+// truncatePDFText shortens a cell so it does not run into the next column
+func truncatePDFText(value string, max int) string {
+	if max < 4 || len(value) <= max {
+		return value
+	}
+	return value[:max-3] + "..."
+}
@@ -2,7 +2,6 @@ package services
 
 import (
 	"context"
-	"net"
 	"time"
 
 	"cloudweave/internal/models"
@@ -14,18 +13,20 @@ import (
 
 // AuditService provides methods for creating audit logs.
 type AuditService struct {
-	repo repositories.AuditLogRepositoryInterface
+	repo   repositories.AuditLogRepositoryInterface
+	writer *AuditWriter
 }
 
-// NewAuditService creates a new AuditService.
-func NewAuditService(repo repositories.AuditLogRepositoryInterface) *AuditService {
-	return &AuditService{repo: repo}
+// NewAuditService creates a new AuditService. Recorded entries are written in batches by writer.
+func NewAuditService(repo repositories.AuditLogRepositoryInterface, writer *AuditWriter) *AuditService {
+	return &AuditService{repo: repo, writer: writer}
 }
 
 // Record creates a new audit log entry.
 func (s *AuditService) Record(ctx context.Context, action string, resourceType string, resourceID string, details map[string]interface{}) error {
-	var userID, orgID, userAgent, ipAddress string
+	var userID, orgID string
 
+	auditCtx := AuditContextFromContext(ctx)
 	if gc, ok := ctx.(*gin.Context); ok {
 		if id, exists := gc.Get("userID"); exists {
 			userID, _ = id.(string)
@@ -33,8 +34,10 @@ func (s *AuditService) Record(ctx context.Context, action string, resourceType s
 		if id, exists := gc.Get("organizationId"); exists {
 			orgID, _ = id.(string)
 		}
-		userAgent = gc.Request.UserAgent()
-		ipAddress = gc.ClientIP()
+		// Routes outside the audit middleware still record where the request came from
+		if auditCtx == nil {
+			auditCtx = &AuditContext{IPAddress: gc.ClientIP(), UserAgent: gc.Request.UserAgent(), ImpersonatedBy: gc.GetString("impersonatedBy")}
+		}
 	}
 
 	id, err := uuid.NewRandom()
@@ -61,14 +64,10 @@ func (s *AuditService) Record(ctx context.Context, action string, resourceType s
 	if details != nil {
 		logEntry.Details = details
 	}
-	if userAgent != "" {
-		logEntry.UserAgent = &userAgent
-	}
-	if ip := net.ParseIP(ipAddress); ip != nil {
-		logEntry.IPAddress = &ip
-	}
+	auditCtx.apply(logEntry)
 
-	return s.repo.Create(ctx, logEntry)
+	s.writer.Write(logEntry)
+	return nil
 }
 
 // Query retrieves audit logs based on query parameters.
@@ -76,6 +75,11 @@ func (s *AuditService) Query(ctx context.Context, orgID string, query models.Aud
 	return s.repo.Query(ctx, orgID, query)
 }
 
+// Count returns how many audit logs match a query, ignoring its limit and offset.
+func (s *AuditService) Count(ctx context.Context, orgID string, query models.AuditLogQuery) (int, error) {
+	return s.repo.Count(ctx, orgID, query)
+}
+
 // GetActionSummary retrieves a summary of actions performed within a time range.
 func (s *AuditService) GetActionSummary(ctx context.Context, orgID string, startTime, endTime time.Time) (map[string]int, error) {
 	return s.repo.GetActionSummary(ctx, orgID, startTime, endTime)
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Default audit writer thresholds
+const (
+	DefaultAuditBatchSize     = 100
+	DefaultAuditFlushInterval = 5 * time.Second
+)
+
+// maxBufferedAuditLogs bounds the buffer so audit events cannot exhaust memory while the database
+// is unavailable
+const maxBufferedAuditLogs = 10000
+
+// AuditWriter buffers audit log entries and writes them to the database in batches, flushing
+// when the batch size is reached or the flush interval elapses
+type AuditWriter struct {
+	repo      repositories.AuditLogRepositoryInterface
+	batchSize int
+
+	mu      sync.Mutex
+	buffer  []*models.AuditLog
+	flushMu sync.Mutex
+	full    chan struct{}
+}
+
+// NewAuditWriter creates a new audit writer
+func NewAuditWriter(repo repositories.AuditLogRepositoryInterface, batchSize int) *AuditWriter {
+	if batchSize <= 0 {
+		batchSize = DefaultAuditBatchSize
+	}
+
+	return &AuditWriter{
+		repo:      repo,
+		batchSize: batchSize,
+		full:      make(chan struct{}, 1),
+	}
+}
+
+// Write queues an audit log entry to be written with the next batch
+func (w *AuditWriter) Write(logEntry *models.AuditLog) {
+	if logEntry.ID == "" {
+		logEntry.ID = uuid.New().String()
+	}
+	if logEntry.CreatedAt.IsZero() {
+		logEntry.CreatedAt = time.Now()
+	}
+
+	w.mu.Lock()
+	if len(w.buffer) >= maxBufferedAuditLogs {
+		w.mu.Unlock()
+		log.Printf("Audit log buffer full, dropping %s event", logEntry.Action)
+		return
+	}
+	w.buffer = append(w.buffer, logEntry)
+	full := len(w.buffer) >= w.batchSize
+	w.mu.Unlock()
+
+	if full {
+		select {
+		case w.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush writes every buffered audit log entry. A batch that fails to write is retried one entry at
+// a time, so entries that can never be written are dropped without holding up the rest. Entries
+// that fail for other reasons, such as the database being unavailable, are returned to the buffer
+// so the next flush retries them.
+func (w *AuditWriter) Flush(ctx context.Context) error {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	w.mu.Lock()
+	pending := w.buffer
+	w.buffer = nil
+	w.mu.Unlock()
+
+	for start := 0; start < len(pending); start += w.batchSize {
+		end := start + w.batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+
+		if err := w.repo.CreateBatch(ctx, pending[start:end]); err != nil {
+			written, err := w.writeEach(ctx, pending[start:end])
+			if err != nil {
+				w.requeue(pending[start+written:])
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeEach writes entries one at a time, logging and dropping those that can never be written.
+// It stops at any other failure and returns how many entries it got through.
+func (w *AuditWriter) writeEach(ctx context.Context, logs []*models.AuditLog) (int, error) {
+	for i, logEntry := range logs {
+		err := w.repo.CreateBatch(ctx, logs[i:i+1])
+		if errors.Is(err, repositories.ErrInvalidAuditLog) {
+			log.Printf("Dropping audit log %s (%s in organization %s) that cannot be written: %v",
+				logEntry.ID, logEntry.Action, logEntry.OrganizationID, err)
+			continue
+		}
+		if err != nil {
+			return i, err
+		}
+	}
+
+	return len(logs), nil
+}
+
+// requeue puts unwritten entries back ahead of anything buffered since the flush started
+func (w *AuditWriter) requeue(logs []*models.AuditLog) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	buffer := append(append([]*models.AuditLog{}, logs...), w.buffer...)
+	if len(buffer) > maxBufferedAuditLogs {
+		log.Printf("Audit log buffer full, dropping %d events", len(buffer)-maxBufferedAuditLogs)
+		buffer = buffer[len(buffer)-maxBufferedAuditLogs:]
+	}
+	w.buffer = buffer
+}
+
+// Start flushes buffered audit logs every interval, or sooner once a full batch is waiting,
+// until the context is cancelled. Callers should Flush after cancelling to write what remains.
+func (w *AuditWriter) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-w.full:
+		}
+
+		if err := w.Flush(ctx); err != nil {
+			log.Printf("Failed to flush audit logs: %v", err)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// failingAuditRepo rejects batches containing an entry with a rejected action, and fails every
+// write while unavailable
+type failingAuditRepo struct {
+	repositories.AuditLogRepositoryInterface
+
+	reject      string
+	unavailable bool
+	logs        []*models.AuditLog
+}
+
+func (r *failingAuditRepo) CreateBatch(ctx context.Context, logs []*models.AuditLog) error {
+	if r.unavailable {
+		return errors.New("connection refused")
+	}
+	for _, logEntry := range logs {
+		if logEntry.Action == r.reject {
+			return repositories.ErrInvalidAuditLog
+		}
+	}
+	r.logs = append(r.logs, logs...)
+	return nil
+}
+
+func TestFlushDropsEntriesThatCannotBeWritten(t *testing.T) {
+	repo := &failingAuditRepo{reject: "poison"}
+	writer := NewAuditWriter(repo, 10)
+
+	writer.Write(&models.AuditLog{Action: "first"})
+	writer.Write(&models.AuditLog{Action: "poison"})
+	writer.Write(&models.AuditLog{Action: "second"})
+
+	if err := writer.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush returned an error: %v", err)
+	}
+
+	if len(repo.logs) != 2 || repo.logs[0].Action != "first" || repo.logs[1].Action != "second" {
+		t.Errorf("wrote %v, want the first and second entries", repo.logs)
+	}
+	if len(writer.buffer) != 0 {
+		t.Errorf("%d entries left buffered, want none", len(writer.buffer))
+	}
+}
+
+func TestFlushKeepsEntriesWhileDatabaseUnavailable(t *testing.T) {
+	repo := &failingAuditRepo{unavailable: true}
+	writer := NewAuditWriter(repo, 10)
+
+	writer.Write(&models.AuditLog{Action: "first"})
+	writer.Write(&models.AuditLog{Action: "second"})
+
+	if err := writer.Flush(context.Background()); err == nil {
+		t.Fatal("Flush succeeded while the database was unavailable")
+	}
+	if len(writer.buffer) != 2 {
+		t.Fatalf("%d entries left buffered, want 2", len(writer.buffer))
+	}
+
+	repo.unavailable = false
+	if err := writer.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush returned an error: %v", err)
+	}
+	if len(repo.logs) != 2 {
+		t.Errorf("wrote %d entries, want 2", len(repo.logs))
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
@@ -18,6 +19,11 @@ type AuthService struct {
 	jwtService       *JWTService
 	passwordService  *PasswordService
 	blacklistService *TokenBlacklistService
+	mfaService       *MFAService
+	notifications    *NotificationService
+	verificationURL  string
+	resetTokens      *PasswordResetTokenService
+	passwordResetURL string
 }
 
 func NewAuthService(
@@ -26,6 +32,7 @@ func NewAuthService(
 	jwtService *JWTService,
 	passwordService *PasswordService,
 	blacklistService *TokenBlacklistService,
+	mfaService *MFAService,
 ) *AuthService {
 	return &AuthService{
 		userRepo:         userRepo,
@@ -33,6 +40,7 @@ func NewAuthService(
 		jwtService:       jwtService,
 		passwordService:  passwordService,
 		blacklistService: blacklistService,
+		mfaService:       mfaService,
 	}
 }
 
@@ -58,6 +66,67 @@ func (s *AuthService) Login(ctx context.Context, req models.LoginRequest) (*mode
 
 	fmt.Printf("DEBUG: Password verification successful for user %s\n", user.Email)
 
+	// Users with MFA enabled must exchange an MFA token and code for their tokens
+	if s.mfaService != nil {
+		mfaEnabled, err := s.mfaService.IsEnabled(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check MFA status: %w", err)
+		}
+		if mfaEnabled {
+			mfaToken, err := s.jwtService.GenerateMFAToken(user.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate MFA token: %w", err)
+			}
+
+			user.PasswordHash = ""
+			return &models.LoginResponse{
+				Success:     true,
+				User:        *user,
+				MFARequired: true,
+				MFAToken:    mfaToken,
+			}, nil
+		}
+	}
+
+	return s.completeLogin(ctx, user)
+}
+
+// VerifyMFALogin completes a login for a user with MFA enabled
+func (s *AuthService) VerifyMFALogin(ctx context.Context, req models.MFAVerifyLoginRequest) (*models.LoginResponse, error) {
+	if s.mfaService == nil {
+		return nil, fmt.Errorf("MFA is not available")
+	}
+
+	claims, err := s.jwtService.ValidateMFAToken(ctx, req.MFAToken)
+	if err != nil {
+		return nil, fmt.Errorf("invalid or expired MFA token: %w", err)
+	}
+
+	if err := s.mfaService.VerifyCode(ctx, claims.UserID, req.Code); err != nil {
+		// Once verification locks, the password has to be entered again too
+		if errors.Is(err, ErrMFALocked) {
+			if err := s.jwtService.ConsumeMFAToken(ctx, claims, "mfa_locked"); err != nil && !errors.Is(err, ErrInvalidToken) {
+				fmt.Printf("Failed to revoke MFA token of user %s: %v\n", claims.UserID, err)
+			}
+		}
+		return nil, err
+	}
+
+	// The token completes one login only
+	if err := s.jwtService.ConsumeMFAToken(ctx, claims, "mfa_used"); err != nil {
+		return nil, fmt.Errorf("invalid or expired MFA token: %w", err)
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	return s.completeLogin(ctx, user)
+}
+
+// completeLogin records the login and issues access and refresh tokens
+func (s *AuthService) completeLogin(ctx context.Context, user *models.User) (*models.LoginResponse, error) {
 	// Update last login timestamp
 	if err := s.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
 		// Log error but don't fail the login
@@ -94,7 +163,7 @@ func (s *AuthService) Register(ctx context.Context, req models.RegisterRequest)
 	}
 
 	// Validate password strength
-	if err := s.passwordService.IsValidPassword(req.Password); err != nil {
+	if err := s.passwordService.ValidatePassword(ctx, req.Password); err != nil {
 		return nil, fmt.Errorf("password validation failed: %w", err)
 	}
 
@@ -165,6 +234,7 @@ func (s *AuthService) Register(ctx context.Context, req models.RegisterRequest)
 	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
+	s.sendWelcomeVerification(*user)
 
 	// Generate JWT tokens
 	accessToken, err := s.jwtService.GenerateAccessToken(*user)
@@ -188,11 +258,15 @@ func (s *AuthService) Register(ctx context.Context, req models.RegisterRequest)
 	}, nil
 }
 
-// RefreshToken generates new access and refresh tokens
+// RefreshToken generates new access and refresh tokens. The presented refresh token is rotated,
+// and presenting an already rotated token revokes its whole token family.
 func (s *AuthService) RefreshToken(ctx context.Context, req models.RefreshTokenRequest) (*models.RefreshTokenResponse, error) {
 	// Validate refresh token
 	claims, err := s.jwtService.ValidateRefreshToken(ctx, req.RefreshToken)
 	if err != nil {
+		if errors.Is(err, ErrTokenReused) {
+			return nil, fmt.Errorf("refresh token reuse detected, login required: %w", err)
+		}
 		return nil, fmt.Errorf("invalid or expired refresh token: %w", err)
 	}
 
@@ -201,24 +275,22 @@ func (s *AuthService) RefreshToken(ctx context.Context, req models.RefreshTokenR
 	if err != nil {
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
+	s.scopeToOrganization(ctx, user, claims.OrganizationID)
 
-	// Blacklist the old refresh token
-	if err := s.jwtService.BlacklistRefreshToken(ctx, req.RefreshToken, "token_refresh"); err != nil {
-		// Log error but don't fail the refresh
-		fmt.Printf("Failed to blacklist old refresh token: %v\n", err)
+	// Invalidate the old refresh token and issue its replacement
+	newRefreshToken, err := s.jwtService.RotateRefreshToken(ctx, claims)
+	if err != nil {
+		if errors.Is(err, ErrTokenReused) {
+			return nil, fmt.Errorf("refresh token reuse detected, login required: %w", err)
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
 	}
 
-	// Generate new tokens
 	newAccessToken, err := s.jwtService.GenerateAccessToken(*user)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate new access token: %w", err)
 	}
 
-	newRefreshToken, err := s.jwtService.GenerateRefreshToken(user.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate new refresh token: %w", err)
-	}
-
 	return &models.RefreshTokenResponse{
 		Success:      true,
 		Token:        newAccessToken,
@@ -253,7 +325,7 @@ func (s *AuthService) ChangePassword(ctx context.Context, userID, currentPasswor
 	}
 
 	// Validate new password
-	if err := s.passwordService.IsValidPassword(newPassword); err != nil {
+	if err := s.passwordService.ValidatePassword(ctx, newPassword); err != nil {
 		return fmt.Errorf("new password validation failed: %w", err)
 	}
 
@@ -292,11 +364,7 @@ func (s *AuthService) Logout(ctx context.Context, accessToken, refreshToken stri
 
 // LogoutAllDevices invalidates all tokens for a user
 func (s *AuthService) LogoutAllDevices(ctx context.Context, userID string) error {
-	if s.blacklistService == nil {
-		return fmt.Errorf("blacklist service not available")
-	}
-
-	return s.blacklistService.BlacklistAllUserTokens(ctx, userID, "logout_all_devices")
+	return s.jwtService.RevokeUserTokens(ctx, userID, "logout_all_devices")
 }
 
 // UpdateUserPreferences updates a user's preferences
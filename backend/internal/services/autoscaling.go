@@ -0,0 +1,418 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+var (
+	// ErrScalingExecutionDisabled is returned when scaling is requested without execution being enabled
+	ErrScalingExecutionDisabled = errors.New("scaling execution is not enabled")
+
+	// ErrScalingUnsupported is returned when a resource's provider or type cannot be resized
+	ErrScalingUnsupported = errors.New("resource cannot be scaled")
+
+	// ErrInvalidScalingSize is returned when a resource cannot be scaled to the requested size
+	ErrInvalidScalingSize = errors.New("invalid scaling size")
+
+	// ErrScalingInProgress is returned when a resource is already being scaled
+	ErrScalingInProgress = errors.New("resource is already being scaled")
+
+	// ErrNoScalingToRollback is returned when a resource has no scaling that can be rolled back
+	ErrNoScalingToRollback = errors.New("no scaling to roll back")
+)
+
+const (
+	// DefaultScalingWindow is how far back sustained utilization is looked for
+	DefaultScalingWindow = 24 * time.Hour
+
+	// scaleUpThreshold and scaleDownThreshold are the CPU or memory utilization percentages a
+	// resource must stay above or below to be scaled
+	scaleUpThreshold   = 80.0
+	scaleDownThreshold = 20.0
+
+	// sustainedFraction is the share of a window's samples that must cross a threshold
+	sustainedFraction = 0.8
+
+	// minScalingSamples is the fewest samples a window needs before a resource is judged
+	minScalingSamples = 6
+
+	// scalingTimeout bounds resizing a resource, which may have to stop and start it
+	scalingTimeout = 15 * time.Minute
+)
+
+// Scaling directions
+const (
+	ScaleUp   = "up"
+	ScaleDown = "down"
+)
+
+// scalableSpec is the specification key holding a resource type's size, and the size it is
+// created with when the key is not set
+type scalableSpec struct {
+	key         string
+	defaultSize string
+}
+
+// scalableSpecs holds the scalable resource types of each provider
+var scalableSpecs = map[string]map[string]scalableSpec{
+	models.ProviderAWS: {
+		models.InfraTypeServer:   {key: "instance_type", defaultSize: "t3.micro"},
+		models.InfraTypeDatabase: {key: "db_instance_class", defaultSize: "db.t3.micro"},
+	},
+	models.ProviderAzure: {
+		models.InfraTypeServer: {key: "vm_size", defaultSize: "Standard_B2s"},
+	},
+	models.ProviderGCP: {
+		models.InfraTypeServer: {key: "machine_type", defaultSize: "e2-medium"},
+	},
+}
+
+// scalingSizeLadders lists instance sizes smallest first. A resource is only ever scaled to the
+// next size in its own ladder.
+var scalingSizeLadders = [][]string{
+	{"t3.nano", "t3.micro", "t3.small", "t3.medium", "t3.large", "t3.xlarge", "t3.2xlarge"},
+	{"m5.large", "m5.xlarge", "m5.2xlarge", "m5.4xlarge", "m5.8xlarge"},
+	{"db.t3.micro", "db.t3.small", "db.t3.medium", "db.t3.large", "db.t3.xlarge", "db.t3.2xlarge"},
+	{"Standard_B1s", "Standard_B1ms", "Standard_B2s", "Standard_B2ms", "Standard_B4ms", "Standard_B8ms"},
+	{"Standard_D2s_v3", "Standard_D4s_v3", "Standard_D8s_v3", "Standard_D16s_v3"},
+	{"e2-micro", "e2-small", "e2-medium", "e2-standard-2", "e2-standard-4", "e2-standard-8"},
+}
+
+// ResourceResizer is implemented by cloud providers that can change the size of their resources
+type ResourceResizer interface {
+	ResizeResource(ctx context.Context, infra *models.Infrastructure, size string) error
+}
+
+// ScalingRecommendation suggests moving a resource to the next size up or down after its
+// utilization stayed above or below a threshold for a window
+type ScalingRecommendation struct {
+	ResourceID      string    `json:"resourceId"`
+	ResourceName    string    `json:"resourceName"`
+	ResourceType    string    `json:"resourceType"`
+	Provider        string    `json:"provider"`
+	Direction       string    `json:"direction"`
+	SpecKey         string    `json:"specKey"`
+	CurrentSize     string    `json:"currentSize"`
+	RecommendedSize string    `json:"recommendedSize"`
+	Reason          string    `json:"reason"`
+	AverageCPU      float64   `json:"averageCpu"`
+	AverageMemory   *float64  `json:"averageMemory,omitempty"`
+	Samples         int       `json:"samples"`
+	Window          string    `json:"window"`
+	Executable      bool      `json:"executable"`
+	GeneratedAt     time.Time `json:"generatedAt"`
+}
+
+// ScalingOperation describes a resize that was started
+type ScalingOperation struct {
+	ResourceID string `json:"resourceId"`
+	SpecKey    string `json:"specKey"`
+	FromSize   string `json:"fromSize"`
+	ToSize     string `json:"toSize"`
+	Rollback   bool   `json:"rollback"`
+	Status     string `json:"status"`
+}
+
+// SetScalingExecution enables carrying out scaling recommendations. Recommendations are made
+// either way.
+func (s *MetricsService) SetScalingExecution(enabled bool) {
+	s.scalingExecution = enabled
+}
+
+// GetScalingRecommendations recommends resizing the organization's resources whose CPU or memory
+// utilization stayed high or low over the window
+func (s *MetricsService) GetScalingRecommendations(ctx context.Context, orgID string, window time.Duration) ([]ScalingRecommendation, error) {
+	infrastructures, err := s.repoManager.Infrastructure.List(ctx, orgID, repositories.ListParams{
+		Limit:  1000,
+		Offset: 0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	recommendations := []ScalingRecommendation{}
+	for _, infra := range infrastructures {
+		if infra.ExternalID == nil || infra.Status != models.InfraStatusRunning {
+			continue
+		}
+		spec, ok := scalableSpecs[infra.Provider][infra.Type]
+		if !ok {
+			continue
+		}
+
+		metrics, err := s.GetResourceMetrics(ctx, infra.ID, window)
+		if err != nil {
+			return nil, err
+		}
+
+		if recommendation := s.recommendScaling(infra, spec, metrics, window); recommendation != nil {
+			recommendations = append(recommendations, *recommendation)
+		}
+	}
+
+	return recommendations, nil
+}
+
+// recommendScaling judges a resource's utilization samples over the window. CPU or memory staying
+// high scales up; both staying low scales down.
+func (s *MetricsService) recommendScaling(infra *models.Infrastructure, spec scalableSpec, metrics []MetricData, window time.Duration) *ScalingRecommendation {
+	var cpu, memory []float64
+	for _, metric := range metrics {
+		switch metric.MetricName {
+		case "cpu_utilization":
+			cpu = append(cpu, metric.Value)
+		case "memory_utilization":
+			memory = append(memory, metric.Value)
+		}
+	}
+	if len(cpu) < minScalingSamples {
+		return nil
+	}
+
+	above := func(value float64) bool { return value >= scaleUpThreshold }
+	below := func(value float64) bool { return value <= scaleDownThreshold }
+
+	var direction, reason string
+	switch {
+	case sustainedUtilization(cpu, above):
+		direction, reason = ScaleUp, fmt.Sprintf("CPU utilization stayed at or above %.0f%%", scaleUpThreshold)
+	case len(memory) >= minScalingSamples && sustainedUtilization(memory, above):
+		direction, reason = ScaleUp, fmt.Sprintf("Memory utilization stayed at or above %.0f%%", scaleUpThreshold)
+	case sustainedUtilization(cpu, below) && (len(memory) < minScalingSamples || sustainedUtilization(memory, below)):
+		direction, reason = ScaleDown, fmt.Sprintf("Utilization stayed at or below %.0f%%", scaleDownThreshold)
+	default:
+		return nil
+	}
+
+	current := resourceSize(infra, spec)
+	target, ok := nextSize(current, direction)
+	if !ok {
+		return nil
+	}
+
+	recommendation := &ScalingRecommendation{
+		ResourceID:      infra.ID,
+		ResourceName:    infra.Name,
+		ResourceType:    infra.Type,
+		Provider:        infra.Provider,
+		Direction:       direction,
+		SpecKey:         spec.key,
+		CurrentSize:     current,
+		RecommendedSize: target,
+		Reason:          fmt.Sprintf("%s for %.0f%% of the last %s", reason, sustainedFraction*100, window),
+		AverageCPU:      averageUtilization(cpu),
+		Samples:         len(cpu),
+		Window:          window.String(),
+		Executable:      s.scalingExecution && s.resizer(infra) != nil,
+		GeneratedAt:     time.Now(),
+	}
+	if len(memory) > 0 {
+		averageMemory := averageUtilization(memory)
+		recommendation.AverageMemory = &averageMemory
+	}
+
+	return recommendation
+}
+
+// ScaleResource resizes one of the organization's resources to the next size up or down in the
+// background. The outcome is recorded in the resource's change log.
+func (s *MetricsService) ScaleResource(ctx context.Context, orgID, id, size string) (*ScalingOperation, error) {
+	infra, spec, err := s.scalableResource(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	current := resourceSize(infra, spec)
+	up, _ := nextSize(current, ScaleUp)
+	down, _ := nextSize(current, ScaleDown)
+	if size == "" || (size != up && size != down) {
+		return nil, fmt.Errorf("%w: %s can only be scaled to the next size up or down from %s", ErrInvalidScalingSize, id, current)
+	}
+
+	return s.startScaling(infra, spec, current, size, false)
+}
+
+// RollbackScaling returns a resource to the size it had before it was last scaled, provided it
+// has not been resized since
+func (s *MetricsService) RollbackScaling(ctx context.Context, orgID, id string) (*ScalingOperation, error) {
+	infra, spec, err := s.scalableResource(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := s.repoManager.Infrastructure.GetLatestChange(ctx, id, models.InfraChangeScaled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get last scaling: %w", err)
+	}
+	if last == nil {
+		return nil, fmt.Errorf("%w: %s has never been scaled", ErrNoScalingToRollback, id)
+	}
+
+	change, ok := last.Changes[spec.key]
+	previous, _ := change.Before.(string)
+	scaledTo, _ := change.After.(string)
+	current := resourceSize(infra, spec)
+	if !ok || previous == "" || scaledTo != current {
+		return nil, fmt.Errorf("%w: %s was resized since it was last scaled", ErrNoScalingToRollback, id)
+	}
+
+	return s.startScaling(infra, spec, current, previous, true)
+}
+
+// scalableResource looks up one of the organization's resources that can be resized
+func (s *MetricsService) scalableResource(ctx context.Context, orgID, id string) (*models.Infrastructure, scalableSpec, error) {
+	if !s.scalingExecution {
+		return nil, scalableSpec{}, ErrScalingExecutionDisabled
+	}
+
+	infra, err := s.repoManager.Infrastructure.GetByID(ctx, id)
+	if err != nil || infra.OrganizationID != orgID {
+		return nil, scalableSpec{}, ErrInfrastructureNotFound
+	}
+
+	spec, ok := scalableSpecs[infra.Provider][infra.Type]
+	if !ok || infra.ExternalID == nil || s.resizer(infra) == nil {
+		return nil, scalableSpec{}, fmt.Errorf("%w: %s %s resources cannot be resized", ErrScalingUnsupported, infra.Provider, infra.Type)
+	}
+
+	return infra, spec, nil
+}
+
+// startScaling claims the resource and resizes it in the background
+func (s *MetricsService) startScaling(infra *models.Infrastructure, spec scalableSpec, from, to string, rollback bool) (*ScalingOperation, error) {
+	if _, busy := s.scaling.LoadOrStore(infra.ID, struct{}{}); busy {
+		return nil, fmt.Errorf("%w: %s", ErrScalingInProgress, infra.ID)
+	}
+
+	// Resize without the request context so resizing outlives the request
+	go s.resize(infra, spec, from, to, rollback)
+
+	return &ScalingOperation{
+		ResourceID: infra.ID,
+		SpecKey:    spec.key,
+		FromSize:   from,
+		ToSize:     to,
+		Rollback:   rollback,
+		Status:     "in_progress",
+	}, nil
+}
+
+// resize changes the resource's size with its provider, then records the new size in its
+// specifications and the resize in its change log
+func (s *MetricsService) resize(infra *models.Infrastructure, spec scalableSpec, from, to string, rollback bool) {
+	defer s.scaling.Delete(infra.ID)
+	ctx := context.Background()
+
+	changes := map[string]models.InfrastructureFieldChange{
+		spec.key: {Before: from, After: to},
+	}
+	message := fmt.Sprintf("Scaled from %s to %s", from, to)
+	if rollback {
+		message = fmt.Sprintf("Rolled back from %s to %s", from, to)
+	}
+
+	err := callProvider(ctx, scalingTimeout, func(ctx context.Context) error {
+		resizer, err := s.accountResizer(ctx, infra)
+		if err != nil {
+			return err
+		}
+		return resizer.ResizeResource(ctx, infra, to)
+	})
+	if err != nil {
+		log.Printf("Failed to scale infrastructure %s to %s: %v", infra.ID, to, err)
+		failure := fmt.Sprintf("%s failed: %v", message, err)
+		if err := s.repoManager.Infrastructure.RecordChange(ctx, infra.ID, models.InfraChangeScaleFailed, changes, &failure); err != nil {
+			log.Printf("Failed to record scaling of infrastructure %s: %v", infra.ID, err)
+		}
+		return
+	}
+
+	if infra.Specifications == nil {
+		infra.Specifications = make(map[string]interface{})
+	}
+	infra.Specifications[spec.key] = to
+	if err := s.repoManager.Infrastructure.Update(ctx, infra); err != nil {
+		log.Printf("Failed to update infrastructure %s with its new size: %v", infra.ID, err)
+	}
+
+	if err := s.repoManager.Infrastructure.RecordChange(ctx, infra.ID, models.InfraChangeScaled, changes, &message); err != nil {
+		log.Printf("Failed to record scaling of infrastructure %s: %v", infra.ID, err)
+	}
+}
+
+// resizer returns the platform's provider for the resource if it can resize resources, which
+// tells whether the resource can be resized at all
+func (s *MetricsService) resizer(infra *models.Infrastructure) ResourceResizer {
+	resizer, _ := s.providers[infra.Provider].(ResourceResizer)
+	return resizer
+}
+
+// accountResizer returns the provider that resizes the resource in its organization's account
+func (s *MetricsService) accountResizer(ctx context.Context, infra *models.Infrastructure) (ResourceResizer, error) {
+	provider, err := s.awsAccounts.providerFor(ctx, infra.OrganizationID, infra.Provider, s.providers)
+	if err != nil {
+		return nil, err
+	}
+
+	resizer, ok := provider.(ResourceResizer)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s %s resources cannot be resized", ErrScalingUnsupported, infra.Provider, infra.Type)
+	}
+	return resizer, nil
+}
+
+// resourceSize returns the size a resource was created or last scaled with
+func resourceSize(infra *models.Infrastructure, spec scalableSpec) string {
+	if size, ok := infra.Specifications[spec.key].(string); ok && size != "" {
+		return size
+	}
+	return spec.defaultSize
+}
+
+// nextSize returns the size next to size in its ladder in the given direction
+func nextSize(size, direction string) (string, bool) {
+	for _, ladder := range scalingSizeLadders {
+		for i, candidate := range ladder {
+			if candidate != size {
+				continue
+			}
+			switch {
+			case direction == ScaleUp && i+1 < len(ladder):
+				return ladder[i+1], true
+			case direction == ScaleDown && i > 0:
+				return ladder[i-1], true
+			}
+			return "", false
+		}
+	}
+	return "", false
+}
+
+// sustainedUtilization reports whether enough of the samples meet the condition
+func sustainedUtilization(samples []float64, condition func(float64) bool) bool {
+	met := 0
+	for _, sample := range samples {
+		if condition(sample) {
+			met++
+		}
+	}
+	return float64(met) >= float64(len(samples))*sustainedFraction
+}
+
+func averageUtilization(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, sample := range samples {
+		total += sample
+	}
+	return total / float64(len(samples))
+}
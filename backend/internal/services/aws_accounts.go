@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// awsAssumeRoleExpiryWindow is how long before they expire assumed role credentials are renewed,
+// so requests are not signed with credentials that expire in flight
+const awsAssumeRoleExpiryWindow = 5 * time.Minute
+
+// awsAccount is the provider built from one version of an organization's AWS credentials
+type awsAccount struct {
+	updatedAt time.Time
+	provider  *RealAWSProvider
+}
+
+// AWSAccounts provides AWS providers for the accounts organizations manage through their active
+// AWS credentials. IAM roles are assumed through STS with the platform's own credentials, passing
+// the role's external ID, and access keys are used directly. A provider is kept per credential
+// until the credential changes, and the temporary credentials of an assumed role are reused until
+// shortly before they expire.
+type AWSAccounts struct {
+	credRepo *repositories.CloudCredentialsRepository
+	base     aws.Config
+
+	mu       sync.Mutex
+	accounts map[string]awsAccount
+}
+
+// NewAWSAccounts creates AWS accounts that assume roles with the credentials from the environment
+func NewAWSAccounts(ctx context.Context, credRepo *repositories.CloudCredentialsRepository) (*AWSAccounts, error) {
+	base, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &AWSAccounts{
+		credRepo: credRepo,
+		base:     base,
+		accounts: make(map[string]awsAccount),
+	}, nil
+}
+
+// Provider returns the provider for the AWS account of an organization's active AWS credentials,
+// or nil when the organization has none that can call AWS and its resources are managed with the
+// platform's own account
+func (a *AWSAccounts) Provider(ctx context.Context, orgID string) (*RealAWSProvider, error) {
+	cred, err := a.credRepo.GetActiveByProvider(ctx, orgID, models.ProviderAWS)
+	if errors.Is(err, repositories.ErrNoActiveCloudCredentials) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cred.CredentialType != models.CredentialTypeIAMRole && cred.CredentialType != models.CredentialTypeAccessKey {
+		return nil, nil
+	}
+
+	a.mu.Lock()
+	account, ok := a.accounts[cred.ID]
+	a.mu.Unlock()
+	if ok && account.updatedAt.Equal(cred.UpdatedAt) {
+		return account.provider, nil
+	}
+
+	credentialsProvider, err := awsCredentialsProvider(a.base, "cloudweave-"+orgID, cred.CredentialType, cred.Credentials)
+	if err != nil {
+		return nil, fmt.Errorf("invalid AWS credentials %s: %w", cred.ID, err)
+	}
+
+	region := credentialString(cred.Credentials, "region")
+	if region == "" {
+		region = "us-east-1"
+	}
+	provider, err := newRealAWSProvider(ctx, region, config.WithCredentialsProvider(credentialsProvider))
+	if err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	a.accounts[cred.ID] = awsAccount{updatedAt: cred.UpdatedAt, provider: provider}
+	a.mu.Unlock()
+
+	return provider, nil
+}
+
+// providersFor returns the providers that manage an organization's resources: the platform's
+// providers, with AWS replaced by the account of the organization's credentials when it has any.
+// Without accounts, a nil AWSAccounts, the platform's providers are returned.
+func (a *AWSAccounts) providersFor(ctx context.Context, orgID string, providers map[string]CloudProvider) (map[string]CloudProvider, error) {
+	if a == nil {
+		return providers, nil
+	}
+	if _, ok := providers[models.ProviderAWS]; !ok {
+		return providers, nil
+	}
+
+	account, err := a.Provider(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the organization's AWS account: %w", err)
+	}
+	if account == nil {
+		return providers, nil
+	}
+
+	orgProviders := make(map[string]CloudProvider, len(providers))
+	for name, provider := range providers {
+		orgProviders[name] = provider
+	}
+	orgProviders[models.ProviderAWS] = account
+	return orgProviders, nil
+}
+
+// providerFor returns the provider that manages an organization's resources with a cloud
+// provider, as providersFor, or nil when the provider is not configured
+func (a *AWSAccounts) providerFor(ctx context.Context, orgID, providerName string, providers map[string]CloudProvider) (CloudProvider, error) {
+	provider, exists := providers[providerName]
+	if !exists || providerName != models.ProviderAWS || a == nil {
+		return provider, nil
+	}
+
+	account, err := a.Provider(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the organization's AWS account: %w", err)
+	}
+	if account != nil {
+		return account, nil
+	}
+	return provider, nil
+}
+
+// awsCredentialsProvider returns the credentials requests are signed with for AWS cloud
+// credentials. IAM roles are assumed with the base config's credentials, in sessions named
+// sessionName, and their temporary credentials cached until near expiry.
+func awsCredentialsProvider(base aws.Config, sessionName, credentialType string, creds map[string]interface{}) (aws.CredentialsProvider, error) {
+	switch credentialType {
+	case models.CredentialTypeAccessKey:
+		accessKeyID := credentialString(creds, "accessKeyId", "access_key_id")
+		secretAccessKey := credentialString(creds, "secretAccessKey", "secret_access_key")
+		if accessKeyID == "" || secretAccessKey == "" {
+			return nil, fmt.Errorf("accessKeyId and secretAccessKey are required")
+		}
+		return credentials.NewStaticCredentialsProvider(
+			accessKeyID, secretAccessKey, credentialString(creds, "sessionToken", "session_token")), nil
+
+	case models.CredentialTypeIAMRole:
+		roleARN := credentialString(creds, "roleArn", "role_arn")
+		if roleARN == "" {
+			return nil, fmt.Errorf("roleArn is required")
+		}
+		externalID := credentialString(creds, "externalId", "external_id")
+
+		assumeRole := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(base), roleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = sessionName
+			if externalID != "" {
+				o.ExternalID = aws.String(externalID)
+			}
+		})
+		return aws.NewCredentialsCache(assumeRole, func(o *aws.CredentialsCacheOptions) {
+			o.ExpiryWindow = awsAssumeRoleExpiryWindow
+		}), nil
+
+	default:
+		return nil, fmt.Errorf("credential type %s cannot be used to call AWS", credentialType)
+	}
+}
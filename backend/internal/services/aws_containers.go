@@ -0,0 +1,378 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"cloudweave/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cwtypes "github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	ecstypes "github.com/aws/aws-sdk-go-v2/service/ecs/types"
+)
+
+const (
+	// ecsClusterName is the ECS cluster container resources run in, created on first use
+	ecsClusterName = "cloudweave"
+
+	// ecsContainerName is the name of the single container of a container resource's task
+	ecsContainerName = "app"
+
+	// Fargate list prices in us-east-1, per vCPU and per GB of memory
+	fargateVCPUHourlyCost = 0.04048
+	fargateGBHourlyCost   = 0.004445
+)
+
+// fargateTaskSizes are the CPU units and memory ranges, in MB, Fargate tasks can be sized with
+var fargateTaskSizes = []struct {
+	cpu       int
+	minMemory int
+	maxMemory int
+}{
+	{256, 512, 2048},
+	{512, 1024, 4096},
+	{1024, 2048, 8192},
+	{2048, 4096, 16384},
+	{4096, 8192, 30720},
+}
+
+// fargateTaskSize picks the smallest Fargate task size with at least the vCPUs and memory a
+// container asks for
+func fargateTaskSize(cpu float64, memoryMB int) (cpuUnits, memory int) {
+	for _, size := range fargateTaskSizes {
+		if float64(size.cpu) < cpu*1024 {
+			continue
+		}
+		memory := (memoryMB + 1023) / 1024 * 1024
+		if size.cpu == 256 && memoryMB <= 512 {
+			memory = 512
+		}
+		memory = max(memory, size.minMemory)
+		if memory <= size.maxMemory {
+			return size.cpu, memory
+		}
+	}
+	last := fargateTaskSizes[len(fargateTaskSizes)-1]
+	return last.cpu, last.maxMemory
+}
+
+// isECSServiceARN reports whether an AWS external ID is the ARN of an ECS service
+func isECSServiceARN(externalID string) bool {
+	return strings.HasPrefix(externalID, "arn:aws:ecs:")
+}
+
+// parseECSServiceARN splits an ECS service ARN, arn:aws:ecs:<region>:<account>:service/<cluster>/<name>,
+// into its cluster and service names
+func parseECSServiceARN(serviceARN string) (cluster, service string, err error) {
+	_, resource, found := strings.Cut(serviceARN, ":service/")
+	cluster, service, ok := strings.Cut(resource, "/")
+	if !found || !ok || cluster == "" || service == "" {
+		return "", "", fmt.Errorf("invalid ECS service ARN: %s", serviceARN)
+	}
+	return cluster, service, nil
+}
+
+// createECSService runs a container resource's image as a Fargate service of one task in the
+// default VPC, with a public IP so images can be pulled from public registries
+func (p *RealAWSProvider) createECSService(ctx context.Context, infra *models.Infrastructure) (string, error) {
+	spec := parseContainerSpec(infra.Specifications)
+	serviceName := containerServiceName(infra)
+
+	// CreateCluster returns the existing cluster when it already exists
+	_, err := p.ecsClient.CreateCluster(ctx, &ecs.CreateClusterInput{
+		ClusterName: aws.String(ecsClusterName),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create ECS cluster: %w", err)
+	}
+
+	subnets, err := p.defaultSubnetIDs(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	taskDefinition, err := p.ecsClient.RegisterTaskDefinition(ctx, ecsTaskDefinitionInput(infra, serviceName, spec))
+	if err != nil {
+		return "", fmt.Errorf("failed to register ECS task definition: %w", err)
+	}
+
+	result, err := p.ecsClient.CreateService(ctx, &ecs.CreateServiceInput{
+		Cluster:        aws.String(ecsClusterName),
+		ServiceName:    aws.String(serviceName),
+		TaskDefinition: taskDefinition.TaskDefinition.TaskDefinitionArn,
+		DesiredCount:   aws.Int32(1),
+		LaunchType:     ecstypes.LaunchTypeFargate,
+		NetworkConfiguration: &ecstypes.NetworkConfiguration{
+			AwsvpcConfiguration: &ecstypes.AwsVpcConfiguration{
+				Subnets:        subnets,
+				AssignPublicIp: ecstypes.AssignPublicIpEnabled,
+			},
+		},
+		PropagateTags: ecstypes.PropagateTagsService,
+		Tags:          ecsTags(infra),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create ECS service: %w", err)
+	}
+
+	return aws.ToString(result.Service.ServiceArn), nil
+}
+
+// ecsTaskDefinitionInput describes the Fargate task of a container resource's service
+func ecsTaskDefinitionInput(infra *models.Infrastructure, family string, spec containerSpec) *ecs.RegisterTaskDefinitionInput {
+	cpuUnits, memory := fargateTaskSize(spec.CPU, spec.MemoryMB)
+
+	container := ecstypes.ContainerDefinition{
+		Name:      aws.String(ecsContainerName),
+		Image:     aws.String(spec.Image),
+		Essential: aws.Bool(true),
+	}
+	for _, name := range sortedKeys(spec.Env) {
+		container.Environment = append(container.Environment, ecstypes.KeyValuePair{
+			Name:  aws.String(name),
+			Value: aws.String(spec.Env[name]),
+		})
+	}
+	if spec.Port > 0 {
+		container.PortMappings = []ecstypes.PortMapping{{
+			ContainerPort: aws.Int32(int32(spec.Port)),
+			Protocol:      ecstypes.TransportProtocolTcp,
+		}}
+	}
+
+	return &ecs.RegisterTaskDefinitionInput{
+		Family:                  aws.String(family),
+		NetworkMode:             ecstypes.NetworkModeAwsvpc,
+		RequiresCompatibilities: []ecstypes.Compatibility{ecstypes.CompatibilityFargate},
+		Cpu:                     aws.String(strconv.Itoa(cpuUnits)),
+		Memory:                  aws.String(strconv.Itoa(memory)),
+		ContainerDefinitions:    []ecstypes.ContainerDefinition{container},
+		Tags:                    ecsTags(infra),
+	}
+}
+
+// ecsTags are the tags container resources' ECS services and task definitions are created with
+func ecsTags(infra *models.Infrastructure) []ecstypes.Tag {
+	return []ecstypes.Tag{
+		{Key: aws.String("Name"), Value: aws.String(infra.Name)},
+		{Key: aws.String("CloudWeave-ID"), Value: aws.String(infra.ID)},
+		{Key: aws.String("CloudWeave-Managed"), Value: aws.String("true")},
+	}
+}
+
+// defaultSubnetIDs lists the default subnets of the region's default VPC
+func (p *RealAWSProvider) defaultSubnetIDs(ctx context.Context) ([]string, error) {
+	result, err := p.ec2Client.DescribeSubnets(ctx, &ec2.DescribeSubnetsInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("default-for-az"), Values: []string{"true"}},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe subnets: %w", err)
+	}
+
+	subnets := make([]string, 0, len(result.Subnets))
+	for _, subnet := range result.Subnets {
+		subnets = append(subnets, aws.ToString(subnet.SubnetId))
+	}
+	if len(subnets) == 0 {
+		return nil, fmt.Errorf("%w: the region has no default VPC subnets to run containers in", ErrCreateRejected)
+	}
+	return subnets, nil
+}
+
+// validateECSService checks that a container resource's service name is valid and not already
+// taken in the cluster
+func (p *RealAWSProvider) validateECSService(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	serviceName := containerServiceName(infra)
+	if err := validateContainerName(serviceName); err != nil {
+		return nil, err
+	}
+
+	result, err := p.ecsClient.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(ecsClusterName),
+		Services: []string{serviceName},
+	})
+	if err != nil && !isCloudResourceNotFound(err) {
+		return nil, fmt.Errorf("failed to validate ECS service: %w", err)
+	}
+	if result != nil {
+		for _, service := range result.Services {
+			if aws.ToString(service.Status) != "INACTIVE" {
+				return nil, fmt.Errorf("%w: ECS service %s already exists", ErrCreateRejected, serviceName)
+			}
+		}
+	}
+
+	spec := parseContainerSpec(infra.Specifications)
+	cpuUnits, memory := fargateTaskSize(spec.CPU, spec.MemoryMB)
+	plan := newCreatePlan(infra, serviceName, map[string]interface{}{
+		"image":  spec.Image,
+		"cpu":    float64(cpuUnits) / 1024,
+		"memory": memory,
+	}, 0)
+	plan.setCostBreakdown([]CostComponent{
+		hourlyCostComponent(CostCategoryCompute, fmt.Sprintf("Fargate task, %g vCPU and %d MB", float64(cpuUnits)/1024, memory), fargateHourlyCost(cpuUnits, memory)),
+	})
+	return plan, nil
+}
+
+// fargateHourlyCost is the list price of running a Fargate task of a size for an hour
+func fargateHourlyCost(cpuUnits, memory int) float64 {
+	return float64(cpuUnits)/1024*fargateVCPUHourlyCost + float64(memory)/1024*fargateGBHourlyCost
+}
+
+// describeECSService looks up an ECS service by its ARN, returning nil when it does not exist
+func (p *RealAWSProvider) describeECSService(ctx context.Context, serviceARN string) (*ecstypes.Service, error) {
+	cluster, _, err := parseECSServiceARN(serviceARN)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := p.ecsClient.DescribeServices(ctx, &ecs.DescribeServicesInput{
+		Cluster:  aws.String(cluster),
+		Services: []string{serviceARN},
+	})
+	if err != nil {
+		if isCloudResourceNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to describe ECS service: %w", err)
+	}
+	if len(result.Services) == 0 {
+		return nil, nil
+	}
+	return &result.Services[0], nil
+}
+
+// ecsServiceStatus maps an ECS service's status and task counts to an infrastructure status
+func ecsServiceStatus(service *ecstypes.Service) string {
+	if service == nil || aws.ToString(service.Status) != "ACTIVE" {
+		return models.InfraStatusTerminated
+	}
+	switch {
+	case service.DesiredCount == 0:
+		return models.InfraStatusStopped
+	case service.RunningCount >= service.DesiredCount:
+		return models.InfraStatusRunning
+	default:
+		return models.InfraStatusPending
+	}
+}
+
+// getECSServiceStatus gets ECS service status
+func (p *RealAWSProvider) getECSServiceStatus(ctx context.Context, serviceARN string) (string, error) {
+	service, err := p.describeECSService(ctx, serviceARN)
+	if err != nil {
+		return models.InfraStatusError, err
+	}
+	return ecsServiceStatus(service), nil
+}
+
+// getECSServiceDetails gets detailed ECS service information, including the image and size of
+// its task definition
+func (p *RealAWSProvider) getECSServiceDetails(ctx context.Context, serviceARN string) (map[string]interface{}, error) {
+	service, err := p.describeECSService(ctx, serviceARN)
+	if err != nil {
+		return nil, err
+	}
+	if service == nil {
+		return nil, fmt.Errorf("%w: ECS service %s", ErrCloudResourceNotFound, serviceARN)
+	}
+
+	result, err := p.ecsClient.DescribeTaskDefinition(ctx, &ecs.DescribeTaskDefinitionInput{
+		TaskDefinition: service.TaskDefinition,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe ECS task definition: %w", err)
+	}
+	taskDefinition := result.TaskDefinition
+
+	specs := map[string]interface{}{
+		"service_name":    aws.ToString(service.ServiceName),
+		"cluster":         ecsClusterName,
+		"task_definition": aws.ToString(service.TaskDefinition),
+		"launch_type":     string(service.LaunchType),
+		"desired_count":   service.DesiredCount,
+		"running_count":   service.RunningCount,
+		"pending_count":   service.PendingCount,
+	}
+	if len(taskDefinition.ContainerDefinitions) > 0 {
+		specs["image"] = aws.ToString(taskDefinition.ContainerDefinitions[0].Image)
+	}
+
+	cpuUnits, _ := strconv.Atoi(aws.ToString(taskDefinition.Cpu))
+	memory, _ := strconv.Atoi(aws.ToString(taskDefinition.Memory))
+	hourlyCost := fargateHourlyCost(cpuUnits, memory) * float64(service.DesiredCount)
+
+	return map[string]interface{}{
+		"status":         ecsServiceStatus(service),
+		"specifications": specs,
+		"costInfo":       fixedCostInfo(hourlyCost),
+	}, nil
+}
+
+// tagECSService adds tags to an ECS service
+func (p *RealAWSProvider) tagECSService(ctx context.Context, serviceARN string, tags map[string]string) error {
+	ecsTags := make([]ecstypes.Tag, 0, len(tags))
+	for key, value := range tags {
+		ecsTags = append(ecsTags, ecstypes.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+
+	_, err := p.ecsClient.TagResource(ctx, &ecs.TagResourceInput{
+		ResourceArn: aws.String(serviceARN),
+		Tags:        ecsTags,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag ECS service: %w", err)
+	}
+	return nil
+}
+
+// deleteECSService deletes an ECS service, stopping its tasks, and deregisters its task definition
+func (p *RealAWSProvider) deleteECSService(ctx context.Context, serviceARN string) error {
+	cluster, _, err := parseECSServiceARN(serviceARN)
+	if err != nil {
+		return err
+	}
+
+	result, err := p.ecsClient.DeleteService(ctx, &ecs.DeleteServiceInput{
+		Cluster: aws.String(cluster),
+		Service: aws.String(serviceARN),
+		Force:   aws.Bool(true),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete ECS service: %w", err)
+	}
+
+	_, err = p.ecsClient.DeregisterTaskDefinition(ctx, &ecs.DeregisterTaskDefinitionInput{
+		TaskDefinition: result.Service.TaskDefinition,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to deregister ECS task definition: %w", err)
+	}
+
+	return nil
+}
+
+// ecsMetricQueries are the metrics reported for an ECS service
+func ecsMetricQueries(serviceARN string) []awsMetricQuery {
+	cluster, service, err := parseECSServiceARN(serviceARN)
+	if err != nil {
+		return nil
+	}
+
+	dimensions := []cwtypes.Dimension{
+		{Name: aws.String("ClusterName"), Value: aws.String(cluster)},
+		{Name: aws.String("ServiceName"), Value: aws.String(service)},
+	}
+	return []awsMetricQuery{
+		{serviceARN, "cpu_utilization", "AWS/ECS", "CPUUtilization", dimensions, cwtypes.StatisticAverage},
+		{serviceARN, "memory_utilization", "AWS/ECS", "MemoryUtilization", dimensions, cwtypes.StatisticAverage},
+	}
+}
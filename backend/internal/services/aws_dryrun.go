@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/smithy-go"
+)
+
+var (
+	// rdsIdentifierPattern matches valid RDS instance identifiers
+	rdsIdentifierPattern = regexp.MustCompile(`^[a-z][a-z0-9-]{0,62}$`)
+
+	// s3BucketNamePattern matches valid S3 bucket names
+	s3BucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+)
+
+// ValidateCreate checks that AWS would create the resource, without creating it. EC2 launches are
+// checked with a dry run of RunInstances; RDS, S3 and ECS have no dry run, so names are checked instead.
+func (p *RealAWSProvider) ValidateCreate(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	p = p.forInfra(infra)
+
+	switch infra.Type {
+	case models.InfraTypeServer:
+		return p.validateEC2Instance(ctx, infra)
+	case models.InfraTypeDatabase:
+		return p.validateRDSInstance(ctx, infra)
+	case models.InfraTypeStorage:
+		return p.validateS3Bucket(infra)
+	case models.InfraTypeContainer:
+		return p.validateECSService(ctx, infra)
+	default:
+		return nil, fmt.Errorf("%w: unsupported infrastructure type: %s", ErrCreateRejected, infra.Type)
+	}
+}
+
+// validateEC2Instance dry-runs the launch of a server resource's EC2 instance. EC2 reports a dry
+// run that would have succeeded as the DryRunOperation error.
+func (p *RealAWSProvider) validateEC2Instance(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	input := ec2RunInstancesInput(infra)
+	input.DryRun = aws.Bool(true)
+
+	_, err := p.ec2Client.RunInstances(ctx, input)
+	var apiErr smithy.APIError
+	switch {
+	case err == nil, errors.As(err, &apiErr) && apiErr.ErrorCode() == "DryRunOperation":
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "UnauthorizedOperation":
+		return nil, fmt.Errorf("%w: not permitted to launch EC2 instances", ErrCreateRejected)
+	case errors.As(err, &apiErr) && apiErr.ErrorFault() == smithy.FaultClient && !isThrottlingError(err):
+		return nil, fmt.Errorf("%w: %s: %s", ErrCreateRejected, apiErr.ErrorCode(), apiErr.ErrorMessage())
+	default:
+		return nil, fmt.Errorf("failed to validate EC2 instance: %w", err)
+	}
+
+	instanceType := string(input.InstanceType)
+	specs := map[string]interface{}{
+		"instance_type": instanceType,
+		"ami_id":        aws.ToString(input.ImageId),
+	}
+	if input.KeyName != nil {
+		specs["key_name"] = *input.KeyName
+	}
+	if len(input.SecurityGroups) > 0 {
+		specs["security_groups"] = input.SecurityGroups
+	}
+
+	plan := newCreatePlan(infra, infra.Name, specs, 0)
+	plan.setCostBreakdown(ec2CostBreakdown(instanceType, p.getEC2HourlyCost(instanceType), ebsGP2MonthlyCostPerGB))
+	return plan, nil
+}
+
+// validateRDSInstance checks that a database resource's instance identifier is valid and not
+// already taken
+func (p *RealAWSProvider) validateRDSInstance(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	identifier := rdsInstanceIdentifier(infra)
+	if !rdsIdentifierPattern.MatchString(identifier) {
+		return nil, fmt.Errorf("%w: %q is not a valid RDS instance identifier", ErrCreateRejected, identifier)
+	}
+
+	_, err := p.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(identifier),
+	})
+	if err == nil {
+		return nil, fmt.Errorf("%w: RDS instance %s already exists", ErrCreateRejected, identifier)
+	}
+	if !isCloudResourceNotFound(err) {
+		return nil, fmt.Errorf("failed to validate RDS instance: %w", err)
+	}
+
+	// Mirror the defaults createRDSInstance applies
+	instanceClass := "db.t3.micro"
+	if class, ok := infra.Specifications["db_instance_class"].(string); ok {
+		instanceClass = class
+	}
+	engine := "mysql"
+	if eng, ok := infra.Specifications["engine"].(string); ok {
+		engine = eng
+	}
+	allocatedStorage := 20
+	if storage, ok := infra.Specifications["allocated_storage"].(float64); ok {
+		allocatedStorage = int(storage)
+	}
+
+	plan := newCreatePlan(infra, identifier, map[string]interface{}{
+		"db_instance_class": instanceClass,
+		"engine":            engine,
+		"allocated_storage": allocatedStorage,
+	}, 0)
+	plan.setCostBreakdown(rdsCostBreakdown(instanceClass, allocatedStorage, p.getRDSHourlyCost(instanceClass), rdsGP2MonthlyCostPerGB))
+	return plan, nil
+}
+
+// validateS3Bucket checks that a storage resource's bucket name is valid. Bucket names carry their
+// creation time, so they do not collide with existing buckets.
+func (p *RealAWSProvider) validateS3Bucket(infra *models.Infrastructure) (*CreatePlan, error) {
+	bucketName := s3BucketName(infra, time.Now())
+	if !s3BucketNamePattern.MatchString(bucketName) {
+		return nil, fmt.Errorf("%w: %q is not a valid S3 bucket name", ErrCreateRejected, bucketName)
+	}
+
+	plan := newCreatePlan(infra, bucketName, map[string]interface{}{}, 0)
+	plan.Warnings = append(plan.Warnings, usageBilledWarning)
+	return plan, nil
+}
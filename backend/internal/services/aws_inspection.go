@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloudweave/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// adminPorts are ports that should not be reachable from the whole internet
+var adminPorts = map[int32]string{
+	22:   "SSH",
+	3389: "RDP",
+}
+
+// InspectConfiguration checks an EC2 instance, RDS instance or S3 bucket's live configuration
+// against AWS security best practices
+func (p *RealAWSProvider) InspectConfiguration(ctx context.Context, infra *models.Infrastructure) ([]Misconfiguration, error) {
+	p = p.forInfra(infra)
+
+	if infra.ExternalID == nil {
+		return nil, ErrInfrastructureNotManaged
+	}
+
+	switch infra.Type {
+	case models.InfraTypeServer:
+		return p.inspectEC2Instance(ctx, *infra.ExternalID)
+	case models.InfraTypeDatabase:
+		return p.inspectRDSInstance(ctx, *infra.ExternalID)
+	case models.InfraTypeStorage:
+		return p.inspectS3Bucket(ctx, *infra.ExternalID)
+	default:
+		return nil, fmt.Errorf("%w: unsupported infrastructure type: %s", ErrScanUnsupported, infra.Type)
+	}
+}
+
+// inspectEC2Instance checks an instance's exposure, metadata service, security groups and volumes
+func (p *RealAWSProvider) inspectEC2Instance(ctx context.Context, instanceID string) ([]Misconfiguration, error) {
+	result, err := p.ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe EC2 instance: %w", err)
+	}
+	if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
+		return nil, fmt.Errorf("%w: instance %s", ErrCloudResourceNotFound, instanceID)
+	}
+	instance := result.Reservations[0].Instances[0]
+
+	var findings []Misconfiguration
+
+	if instance.PublicIpAddress != nil {
+		findings = append(findings, Misconfiguration{
+			RuleID:         "aws-ec2-public-ip",
+			Title:          "EC2 instance has a public IP address",
+			Description:    fmt.Sprintf("Instance %s is reachable from the internet at %s", instanceID, *instance.PublicIpAddress),
+			Severity:       models.VulnSeverityMedium,
+			Recommendation: "Place the instance in a private subnet behind a load balancer or bastion host",
+			References:     []string{"https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/using-instance-addressing.html"},
+		})
+	}
+
+	if instance.MetadataOptions == nil || instance.MetadataOptions.HttpTokens != ec2types.HttpTokensStateRequired {
+		findings = append(findings, Misconfiguration{
+			RuleID:         "aws-ec2-imdsv1",
+			Title:          "EC2 instance allows IMDSv1",
+			Description:    fmt.Sprintf("Instance %s does not require session tokens for the instance metadata service", instanceID),
+			Severity:       models.VulnSeverityMedium,
+			Recommendation: "Require IMDSv2 by setting the instance's metadata HttpTokens option to required",
+			References:     []string{"https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/configuring-instance-metadata-service.html"},
+		})
+	}
+
+	var groupIDs []string
+	for _, group := range instance.SecurityGroups {
+		groupIDs = append(groupIDs, aws.ToString(group.GroupId))
+	}
+	if len(groupIDs) > 0 {
+		groups, err := p.ec2Client.DescribeSecurityGroups(ctx, &ec2.DescribeSecurityGroupsInput{
+			GroupIds: groupIDs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe security groups: %w", err)
+		}
+		for _, group := range groups.SecurityGroups {
+			findings = append(findings, inspectSecurityGroup(group)...)
+		}
+	}
+
+	var volumeIDs []string
+	for _, mapping := range instance.BlockDeviceMappings {
+		if mapping.Ebs != nil && mapping.Ebs.VolumeId != nil {
+			volumeIDs = append(volumeIDs, *mapping.Ebs.VolumeId)
+		}
+	}
+	if len(volumeIDs) > 0 {
+		volumes, err := p.ec2Client.DescribeVolumes(ctx, &ec2.DescribeVolumesInput{
+			VolumeIds: volumeIDs,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe EBS volumes: %w", err)
+		}
+		for _, volume := range volumes.Volumes {
+			if !aws.ToBool(volume.Encrypted) {
+				findings = append(findings, Misconfiguration{
+					RuleID:         "aws-ebs-unencrypted",
+					Title:          "Unencrypted EBS Volume",
+					Description:    fmt.Sprintf("EBS volume %s attached to instance %s is not encrypted at rest", aws.ToString(volume.VolumeId), instanceID),
+					Severity:       models.VulnSeverityHigh,
+					Location:       aws.ToString(volume.VolumeId),
+					Recommendation: "Enable EBS encryption for data at rest",
+					References:     []string{"https://docs.aws.amazon.com/AWSEC2/latest/UserGuide/EBSEncryption.html"},
+				})
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// inspectSecurityGroup reports ingress rules that open administrative ports to the whole internet
+func inspectSecurityGroup(group ec2types.SecurityGroup) []Misconfiguration {
+	var findings []Misconfiguration
+	for _, permission := range group.IpPermissions {
+		openToInternet := false
+		for _, ipRange := range permission.IpRanges {
+			if aws.ToString(ipRange.CidrIp) == "0.0.0.0/0" {
+				openToInternet = true
+			}
+		}
+		for _, ipRange := range permission.Ipv6Ranges {
+			if aws.ToString(ipRange.CidrIpv6) == "::/0" {
+				openToInternet = true
+			}
+		}
+		if !openToInternet {
+			continue
+		}
+
+		allProtocols := aws.ToString(permission.IpProtocol) == "-1"
+		for port, service := range adminPorts {
+			inRange := permission.FromPort != nil && permission.ToPort != nil &&
+				*permission.FromPort <= port && port <= *permission.ToPort
+			if !allProtocols && !inRange {
+				continue
+			}
+
+			findings = append(findings, Misconfiguration{
+				RuleID:         "aws-sg-open-admin-port",
+				Title:          "Overly Permissive Security Group",
+				Description:    fmt.Sprintf("Security group %s allows %s (port %d) from anywhere on the internet", aws.ToString(group.GroupId), service, port),
+				Severity:       models.VulnSeverityHigh,
+				Location:       fmt.Sprintf("%s:%d", aws.ToString(group.GroupId), port),
+				Recommendation: "Restrict security group rules to specific IP ranges",
+				References:     []string{"https://docs.aws.amazon.com/vpc/latest/userguide/VPC_SecurityGroups.html"},
+			})
+		}
+	}
+	return findings
+}
+
+// inspectRDSInstance checks a database's exposure, encryption, backups and deletion protection
+func (p *RealAWSProvider) inspectRDSInstance(ctx context.Context, dbInstanceID string) ([]Misconfiguration, error) {
+	result, err := p.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe RDS instance: %w", err)
+	}
+	if len(result.DBInstances) == 0 {
+		return nil, fmt.Errorf("%w: database %s", ErrCloudResourceNotFound, dbInstanceID)
+	}
+	db := result.DBInstances[0]
+
+	var findings []Misconfiguration
+
+	if aws.ToBool(db.PubliclyAccessible) {
+		findings = append(findings, Misconfiguration{
+			RuleID:         "aws-rds-public",
+			Title:          "Database Publicly Accessible",
+			Description:    fmt.Sprintf("RDS instance %s has a publicly resolvable endpoint", dbInstanceID),
+			Severity:       models.VulnSeverityHigh,
+			Recommendation: "Disable public accessibility and reach the database from within its VPC",
+			References:     []string{"https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_VPC.WorkingWithRDSInstanceinaVPC.html"},
+		})
+	}
+
+	if !aws.ToBool(db.StorageEncrypted) {
+		findings = append(findings, Misconfiguration{
+			RuleID:         "aws-rds-unencrypted",
+			Title:          "Database Not Encrypted",
+			Description:    fmt.Sprintf("RDS instance %s is not encrypted at rest", dbInstanceID),
+			Severity:       models.VulnSeverityHigh,
+			Recommendation: "Enable encryption at rest for RDS instance",
+			References:     []string{"https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/Overview.Encryption.html"},
+		})
+	}
+
+	if aws.ToInt32(db.BackupRetentionPeriod) == 0 {
+		findings = append(findings, Misconfiguration{
+			RuleID:         "aws-rds-no-backups",
+			Title:          "Database Backups Disabled",
+			Description:    fmt.Sprintf("RDS instance %s does not keep automated backups", dbInstanceID),
+			Severity:       models.VulnSeverityMedium,
+			Recommendation: "Set a backup retention period of at least 7 days",
+			References:     []string{"https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_WorkingWithAutomatedBackups.html"},
+		})
+	}
+
+	if !aws.ToBool(db.DeletionProtection) {
+		findings = append(findings, Misconfiguration{
+			RuleID:         "aws-rds-no-deletion-protection",
+			Title:          "Database Deletion Protection Disabled",
+			Description:    fmt.Sprintf("RDS instance %s can be deleted without first disabling deletion protection", dbInstanceID),
+			Severity:       models.VulnSeverityLow,
+			Recommendation: "Enable deletion protection for production databases",
+			References:     []string{"https://docs.aws.amazon.com/AmazonRDS/latest/UserGuide/USER_DeleteInstance.html"},
+		})
+	}
+
+	return findings, nil
+}
+
+// inspectS3Bucket checks a bucket's public access block and versioning
+func (p *RealAWSProvider) inspectS3Bucket(ctx context.Context, bucketName string) ([]Misconfiguration, error) {
+	var findings []Misconfiguration
+
+	blocked := false
+	publicAccess, err := p.s3Client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{
+		Bucket: aws.String(bucketName),
+	})
+	var apiErr smithy.APIError
+	switch {
+	case err == nil:
+		if config := publicAccess.PublicAccessBlockConfiguration; config != nil {
+			blocked = aws.ToBool(config.BlockPublicAcls) && aws.ToBool(config.IgnorePublicAcls) &&
+				aws.ToBool(config.BlockPublicPolicy) && aws.ToBool(config.RestrictPublicBuckets)
+		}
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchPublicAccessBlockConfiguration":
+	default:
+		return nil, fmt.Errorf("failed to get S3 public access block: %w", err)
+	}
+	if !blocked {
+		findings = append(findings, Misconfiguration{
+			RuleID:         "aws-s3-public-access",
+			Title:          "S3 Bucket Public Access Not Blocked",
+			Description:    fmt.Sprintf("Bucket %s does not block all public access, so ACLs or policies can make it public", bucketName),
+			Severity:       models.VulnSeverityHigh,
+			Recommendation: "Turn on all four S3 Block Public Access settings for the bucket",
+			References:     []string{"https://docs.aws.amazon.com/AmazonS3/latest/userguide/access-control-block-public-access.html"},
+		})
+	}
+
+	versioning, err := p.s3Client.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get S3 bucket versioning: %w", err)
+	}
+	if versioning.Status != s3types.BucketVersioningStatusEnabled {
+		findings = append(findings, Misconfiguration{
+			RuleID:         "aws-s3-no-versioning",
+			Title:          "S3 Bucket Versioning Disabled",
+			Description:    fmt.Sprintf("Bucket %s does not keep previous versions of overwritten or deleted objects", bucketName),
+			Severity:       models.VulnSeverityLow,
+			Recommendation: "Enable versioning to recover from accidental or malicious deletes",
+			References:     []string{"https://docs.aws.amazon.com/AmazonS3/latest/userguide/Versioning.html"},
+		})
+	}
+
+	return findings, nil
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+)
+
+const (
+	// awsMetricsWindow is how far back resource metrics are read
+	awsMetricsWindow = time.Hour
+
+	// awsMetricsPeriod is the granularity of the metrics read, in seconds
+	awsMetricsPeriod = 300
+
+	// awsMaxMetricQueries is the most queries GetMetricData accepts in one request
+	awsMaxMetricQueries = 500
+)
+
+// awsMetricQuery is one CloudWatch metric of one resource, reported under key
+type awsMetricQuery struct {
+	externalID string
+	key        string
+	namespace  string
+	metricName string
+	dimensions []types.Dimension
+	stat       types.Statistic
+}
+
+// BatchMetricsProvider is implemented by cloud providers that can fetch the current metrics of
+// many resources at once, more cheaply than calling GetResourceMetrics for each
+type BatchMetricsProvider interface {
+	GetResourcesMetrics(ctx context.Context, externalIDs []string) (map[string]map[string]interface{}, error)
+}
+
+// providerRegion identifies the resources of one provider in one region
+type providerRegion struct {
+	provider string
+	region   string
+}
+
+// batchResourceMetrics fetches the metrics of the resources whose providers implement
+// BatchMetricsProvider with one call per provider and region, keyed by infrastructure ID.
+// Resources of other providers, or whose batch failed, are left out for the caller to fetch one
+// by one.
+func batchResourceMetrics(ctx context.Context, providers map[string]CloudProvider, infrastructures []*models.Infrastructure) map[string]map[string]interface{} {
+	externalIDs := make(map[providerRegion][]string)
+	for _, infra := range infrastructures {
+		if infra.ExternalID == nil {
+			continue
+		}
+		if _, ok := providers[infra.Provider].(BatchMetricsProvider); ok {
+			key := providerRegion{infra.Provider, infra.Region}
+			externalIDs[key] = append(externalIDs[key], *infra.ExternalID)
+		}
+	}
+
+	byExternalID := make(map[string]map[string]map[string]interface{}, len(externalIDs))
+	for key, ids := range externalIDs {
+		metrics, err := providerValue(withResourceRegion(ctx, key.region), func(ctx context.Context) (map[string]map[string]interface{}, error) {
+			return providers[key.provider].(BatchMetricsProvider).GetResourcesMetrics(ctx, ids)
+		})
+		if err != nil {
+			log.Printf("Failed to get %s metrics in bulk: %v", key.provider, err)
+			continue
+		}
+		if byExternalID[key.provider] == nil {
+			byExternalID[key.provider] = make(map[string]map[string]interface{}, len(metrics))
+		}
+		for externalID, value := range metrics {
+			byExternalID[key.provider][externalID] = value
+		}
+	}
+
+	results := make(map[string]map[string]interface{})
+	for _, infra := range infrastructures {
+		if infra.ExternalID == nil {
+			continue
+		}
+		if metrics, ok := byExternalID[infra.Provider][*infra.ExternalID]; ok {
+			results[infra.ID] = metrics
+		}
+	}
+	return results
+}
+
+// GetResourcesMetrics retrieves the CloudWatch metrics of many AWS resources, keyed by external ID,
+// with one GetMetricData request per 500 metrics of a region instead of one request per metric.
+// Each resource's metrics are read from its region's CloudWatch. Each resource's map has the same
+// keys GetResourceMetrics returns; metrics without datapoints in the last hour are left out.
+func (p *RealAWSProvider) GetResourcesMetrics(ctx context.Context, externalIDs []string) (map[string]map[string]interface{}, error) {
+	endTime := time.Now()
+	results := make(map[string]map[string]interface{}, len(externalIDs))
+
+	queries := make(map[string][]awsMetricQuery)
+	for _, externalID := range externalIDs {
+		region := awsResourceRegion(ctx, externalID)
+		if strings.HasPrefix(externalID, "i-") {
+			queries[region] = append(queries[region], ec2MetricQueries(externalID)...)
+		} else if isECSServiceARN(externalID) {
+			queries[region] = append(queries[region], ecsMetricQueries(externalID)...)
+		} else if strings.Contains(externalID, "cloudweave-") {
+			metrics, err := p.getS3Metrics(ctx, externalID)
+			if err != nil {
+				return nil, err
+			}
+			results[externalID] = metrics
+			continue
+		} else {
+			queries[region] = append(queries[region], rdsMetricQueries(externalID)...)
+		}
+		results[externalID] = map[string]interface{}{
+			"timestamp": endTime.Unix(),
+		}
+	}
+
+	for region, regionQueries := range queries {
+		regional := p.inRegion(region)
+		for start := 0; start < len(regionQueries); start += awsMaxMetricQueries {
+			batch := regionQueries[start:min(start+awsMaxMetricQueries, len(regionQueries))]
+			values, err := regional.getMetricData(ctx, batch, endTime.Add(-awsMetricsWindow), endTime)
+			if err != nil {
+				return nil, err
+			}
+			for i, query := range batch {
+				if value, ok := values[i]; ok {
+					results[query.externalID][query.key] = value
+				}
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// getMetricData fetches the latest value of each query between startTime and endTime, keyed by
+// the query's index, following NextToken until every page is read
+func (p *RealAWSProvider) getMetricData(ctx context.Context, queries []awsMetricQuery, startTime, endTime time.Time) (map[int]float64, error) {
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(startTime),
+		EndTime:   aws.Time(endTime),
+		ScanBy:    types.ScanByTimestampDescending,
+	}
+	for i, query := range queries {
+		input.MetricDataQueries = append(input.MetricDataQueries, types.MetricDataQuery{
+			Id: aws.String(fmt.Sprintf("m%d", i)),
+			MetricStat: &types.MetricStat{
+				Metric: &types.Metric{
+					Namespace:  aws.String(query.namespace),
+					MetricName: aws.String(query.metricName),
+					Dimensions: query.dimensions,
+				},
+				Period: aws.Int32(awsMetricsPeriod),
+				Stat:   aws.String(string(query.stat)),
+			},
+		})
+	}
+
+	values := make(map[int]float64)
+	paginator := cloudwatch.NewGetMetricDataPaginator(p.cwClient, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get CloudWatch metric data: %w", err)
+		}
+		for _, result := range page.MetricDataResults {
+			var index int
+			if _, err := fmt.Sscanf(aws.ToString(result.Id), "m%d", &index); err != nil {
+				continue
+			}
+			// Values are newest first, and a query's later pages only hold older values
+			if _, seen := values[index]; !seen && len(result.Values) > 0 {
+				values[index] = result.Values[0]
+			}
+		}
+	}
+
+	return values, nil
+}
+
+// awsDimensions is the single dimension identifying a resource's metrics
+func awsDimensions(name, value string) []types.Dimension {
+	return []types.Dimension{{Name: aws.String(name), Value: aws.String(value)}}
+}
+
+// ec2MetricQueries are the metrics reported for an EC2 instance
+func ec2MetricQueries(instanceID string) []awsMetricQuery {
+	dimensions := awsDimensions("InstanceId", instanceID)
+	return []awsMetricQuery{
+		{instanceID, "cpu_utilization", "AWS/EC2", "CPUUtilization", dimensions, types.StatisticAverage},
+		{instanceID, "network_in", "AWS/EC2", "NetworkIn", dimensions, types.StatisticSum},
+	}
+}
+
+// rdsMetricQueries are the metrics reported for an RDS instance
+func rdsMetricQueries(dbInstanceID string) []awsMetricQuery {
+	return []awsMetricQuery{
+		{dbInstanceID, "cpu_utilization", "AWS/RDS", "CPUUtilization", awsDimensions("DBInstanceIdentifier", dbInstanceID), types.StatisticAverage},
+	}
+}
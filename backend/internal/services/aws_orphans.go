@@ -0,0 +1,317 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+const (
+	// awsElasticIPMonthlyCost is the charge for an Elastic IP address that is not associated
+	awsElasticIPMonthlyCost = 0.005 * 730
+
+	// awsS3StandardGBMonthlyCost is the S3 Standard storage price per GB-month
+	awsS3StandardGBMonthlyCost = 0.023
+
+	// awsIdleBucketPeriod is how long a bucket must go without requests to count as idle
+	awsIdleBucketPeriod = 30 * 24 * time.Hour
+
+	// awsBucketMetricsFilterID is the request metrics configuration that covers a whole bucket
+	awsBucketMetricsFilterID = "EntireBucket"
+)
+
+// awsEBSGBMonthlyCosts are the EBS storage prices per GB-month of each volume type
+var awsEBSGBMonthlyCosts = map[ec2types.VolumeType]float64{
+	ec2types.VolumeTypeGp2:      0.10,
+	ec2types.VolumeTypeGp3:      0.08,
+	ec2types.VolumeTypeIo1:      0.125,
+	ec2types.VolumeTypeIo2:      0.125,
+	ec2types.VolumeTypeSt1:      0.045,
+	ec2types.VolumeTypeSc1:      0.015,
+	ec2types.VolumeTypeStandard: 0.05,
+}
+
+// ListOrphanedResources finds EBS volumes that are not attached, Elastic IPs that are not
+// associated, stopped instances still paying for their volumes, and S3 buckets with no requests
+// in the last 30 days
+func (p *RealAWSProvider) ListOrphanedResources(ctx context.Context) ([]OrphanedResource, error) {
+	var orphans []OrphanedResource
+
+	volumes, err := p.listUnattachedVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orphans = append(orphans, volumes...)
+
+	addresses, err := p.listUnassociatedAddresses(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orphans = append(orphans, addresses...)
+
+	instances, err := p.listStoppedInstances(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orphans = append(orphans, instances...)
+
+	buckets, err := p.listIdleBuckets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orphans = append(orphans, buckets...)
+
+	return orphans, nil
+}
+
+// listUnattachedVolumes finds EBS volumes that are not attached to any instance
+func (p *RealAWSProvider) listUnattachedVolumes(ctx context.Context) ([]OrphanedResource, error) {
+	var orphans []OrphanedResource
+
+	paginator := ec2.NewDescribeVolumesPaginator(p.ec2Client, &ec2.DescribeVolumesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("status"), Values: []string{string(ec2types.VolumeStateAvailable)}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe EBS volumes: %w", err)
+		}
+		for _, volume := range page.Volumes {
+			orphans = append(orphans, OrphanedResource{
+				Kind:         OrphanUnattachedVolume,
+				ExternalID:   aws.ToString(volume.VolumeId),
+				Region:       p.cfg.Region,
+				Reason:       fmt.Sprintf("an unattached %d GB %s volume", aws.ToInt32(volume.Size), volume.VolumeType),
+				MonthlyWaste: ebsMonthlyCost(volume),
+				Tags:         ec2TagMap(volume.Tags),
+			})
+		}
+	}
+
+	return orphans, nil
+}
+
+// listUnassociatedAddresses finds Elastic IPs that are not associated with an instance or interface
+func (p *RealAWSProvider) listUnassociatedAddresses(ctx context.Context) ([]OrphanedResource, error) {
+	result, err := p.ec2Client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe Elastic IPs: %w", err)
+	}
+
+	var orphans []OrphanedResource
+	for _, address := range result.Addresses {
+		if address.AssociationId != nil {
+			continue
+		}
+		orphans = append(orphans, OrphanedResource{
+			Kind:         OrphanUnassociatedIP,
+			ExternalID:   aws.ToString(address.AllocationId),
+			Region:       p.cfg.Region,
+			Reason:       fmt.Sprintf("an Elastic IP (%s) not associated with anything", aws.ToString(address.PublicIp)),
+			MonthlyWaste: awsElasticIPMonthlyCost,
+			Tags:         ec2TagMap(address.Tags),
+		})
+	}
+
+	return orphans, nil
+}
+
+// listStoppedInstances finds stopped EC2 instances, whose attached volumes are still billed
+func (p *RealAWSProvider) listStoppedInstances(ctx context.Context) ([]OrphanedResource, error) {
+	stopped := make(map[string]ec2types.Instance)
+	var instanceIDs []string
+
+	paginator := ec2.NewDescribeInstancesPaginator(p.ec2Client, &ec2.DescribeInstancesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("instance-state-name"), Values: []string{string(ec2types.InstanceStateNameStopped)}},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe stopped EC2 instances: %w", err)
+		}
+		for _, reservation := range page.Reservations {
+			for _, instance := range reservation.Instances {
+				id := aws.ToString(instance.InstanceId)
+				stopped[id] = instance
+				instanceIDs = append(instanceIDs, id)
+			}
+		}
+	}
+	if len(instanceIDs) == 0 {
+		return nil, nil
+	}
+
+	storageCost := make(map[string]float64)
+	storageGB := make(map[string]int32)
+	volumes := ec2.NewDescribeVolumesPaginator(p.ec2Client, &ec2.DescribeVolumesInput{
+		Filters: []ec2types.Filter{
+			{Name: aws.String("attachment.instance-id"), Values: instanceIDs},
+		},
+	})
+	for volumes.HasMorePages() {
+		page, err := volumes.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe EBS volumes: %w", err)
+		}
+		for _, volume := range page.Volumes {
+			for _, attachment := range volume.Attachments {
+				id := aws.ToString(attachment.InstanceId)
+				storageCost[id] += ebsMonthlyCost(volume)
+				storageGB[id] += aws.ToInt32(volume.Size)
+			}
+		}
+	}
+
+	var orphans []OrphanedResource
+	for _, id := range instanceIDs {
+		if storageCost[id] == 0 {
+			continue
+		}
+		orphans = append(orphans, OrphanedResource{
+			Kind:         OrphanStoppedInstance,
+			ExternalID:   id,
+			Region:       p.cfg.Region,
+			Reason:       fmt.Sprintf("stopped but still paying for %d GB of attached volumes", storageGB[id]),
+			MonthlyWaste: storageCost[id],
+			Tags:         ec2TagMap(stopped[id].Tags),
+		})
+	}
+
+	return orphans, nil
+}
+
+// listIdleBuckets finds S3 buckets that received no requests over the idle period. Only buckets
+// with a whole-bucket request metrics configuration can be checked, since S3 does not otherwise
+// record when a bucket was last accessed.
+func (p *RealAWSProvider) listIdleBuckets(ctx context.Context) ([]OrphanedResource, error) {
+	result, err := p.s3Client.ListBuckets(ctx, &s3.ListBucketsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list S3 buckets: %w", err)
+	}
+
+	endTime := time.Now()
+	startTime := endTime.Add(-awsIdleBucketPeriod)
+
+	var orphans []OrphanedResource
+	for _, bucket := range result.Buckets {
+		name := aws.ToString(bucket.Name)
+		if bucket.CreationDate != nil && bucket.CreationDate.After(startTime) {
+			continue
+		}
+
+		_, err := p.s3Client.GetBucketMetricsConfiguration(ctx, &s3.GetBucketMetricsConfigurationInput{
+			Bucket: aws.String(name),
+			Id:     aws.String(awsBucketMetricsFilterID),
+		})
+		if err != nil {
+			var apiErr smithy.APIError
+			if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchConfiguration" {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get metrics configuration of S3 bucket %s: %w", name, err)
+		}
+
+		requests, err := p.cwClient.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+			Namespace:  aws.String("AWS/S3"),
+			MetricName: aws.String("AllRequests"),
+			Dimensions: []types.Dimension{
+				{Name: aws.String("BucketName"), Value: aws.String(name)},
+				{Name: aws.String("FilterId"), Value: aws.String(awsBucketMetricsFilterID)},
+			},
+			StartTime:  aws.Time(startTime),
+			EndTime:    aws.Time(endTime),
+			Period:     aws.Int32(86400),
+			Statistics: []types.Statistic{types.StatisticSum},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get request metrics of S3 bucket %s: %w", name, err)
+		}
+		total := 0.0
+		for _, datapoint := range requests.Datapoints {
+			total += aws.ToFloat64(datapoint.Sum)
+		}
+		if total > 0 {
+			continue
+		}
+
+		sizeBytes, err := p.bucketSizeBytes(ctx, name, endTime)
+		if err != nil {
+			return nil, err
+		}
+		sizeGB := sizeBytes / (1 << 30)
+
+		orphans = append(orphans, OrphanedResource{
+			Kind:         OrphanIdleBucket,
+			ExternalID:   name,
+			Region:       p.cfg.Region,
+			Reason:       fmt.Sprintf("a %.1f GB bucket with no requests in %d days", sizeGB, int(awsIdleBucketPeriod.Hours()/24)),
+			MonthlyWaste: sizeGB * awsS3StandardGBMonthlyCost,
+		})
+	}
+
+	return orphans, nil
+}
+
+// bucketSizeBytes returns the most recent daily Standard storage size CloudWatch reported for a bucket
+func (p *RealAWSProvider) bucketSizeBytes(ctx context.Context, bucket string, endTime time.Time) (float64, error) {
+	result, err := p.cwClient.GetMetricStatistics(ctx, &cloudwatch.GetMetricStatisticsInput{
+		Namespace:  aws.String("AWS/S3"),
+		MetricName: aws.String("BucketSizeBytes"),
+		Dimensions: []types.Dimension{
+			{Name: aws.String("BucketName"), Value: aws.String(bucket)},
+			{Name: aws.String("StorageType"), Value: aws.String("StandardStorage")},
+		},
+		StartTime:  aws.Time(endTime.Add(-3 * 24 * time.Hour)),
+		EndTime:    aws.Time(endTime),
+		Period:     aws.Int32(86400),
+		Statistics: []types.Statistic{types.StatisticAverage},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get size of S3 bucket %s: %w", bucket, err)
+	}
+
+	var latest *time.Time
+	size := 0.0
+	for _, datapoint := range result.Datapoints {
+		if latest == nil || datapoint.Timestamp.After(*latest) {
+			latest = datapoint.Timestamp
+			size = aws.ToFloat64(datapoint.Average)
+		}
+	}
+
+	return size, nil
+}
+
+// ebsMonthlyCost is the monthly storage charge of an EBS volume
+func ebsMonthlyCost(volume ec2types.Volume) float64 {
+	perGB, ok := awsEBSGBMonthlyCosts[volume.VolumeType]
+	if !ok {
+		perGB = awsEBSGBMonthlyCosts[ec2types.VolumeTypeGp2]
+	}
+	return float64(aws.ToInt32(volume.Size)) * perGB
+}
+
+// ec2TagMap converts EC2 tags to a map
+func ec2TagMap(tags []ec2types.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		result[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return result
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"cloudweave/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/pricing"
+	pricingtypes "github.com/aws/aws-sdk-go-v2/service/pricing/types"
+)
+
+// rdsPricingEngines maps RDS engine identifiers to the database engine names of the Pricing API
+var rdsPricingEngines = map[string]string{
+	"postgres":          "PostgreSQL",
+	"mysql":             "MySQL",
+	"mariadb":           "MariaDB",
+	"aurora-postgresql": "Aurora PostgreSQL",
+	"aurora-mysql":      "Aurora MySQL",
+}
+
+// List prices of the General Purpose (gp2) storage AWS resources are created with, used when the
+// Pricing API cannot be reached
+const (
+	ebsGP2MonthlyCostPerGB = 0.10
+	rdsGP2MonthlyCostPerGB = 0.115
+
+	// ec2RootVolumeGB is the size of the root volume of the AMI servers are launched from
+	ec2RootVolumeGB = 8
+)
+
+// awsPriceListItem is the part of a Pricing API price list entry needed to price resources
+type awsPriceListItem struct {
+	Terms struct {
+		OnDemand map[string]awsPriceTerm `json:"OnDemand"`
+		Reserved map[string]awsPriceTerm `json:"Reserved"`
+	} `json:"terms"`
+}
+
+type awsPriceTerm struct {
+	TermAttributes struct {
+		LeaseContractLength string `json:"LeaseContractLength"`
+		OfferingClass       string `json:"OfferingClass"`
+		PurchaseOption      string `json:"PurchaseOption"`
+	} `json:"termAttributes"`
+	PriceDimensions map[string]struct {
+		Unit         string            `json:"unit"`
+		PricePerUnit map[string]string `json:"pricePerUnit"`
+	} `json:"priceDimensions"`
+}
+
+// GetReservedPricing looks up the on-demand price and 1-year standard reservation offers of an
+// EC2 or RDS resource's instance type in its region with the AWS Pricing API
+func (p *RealAWSProvider) GetReservedPricing(ctx context.Context, infra *models.Infrastructure) (*ReservedPricing, error) {
+	var serviceCode string
+	var filters map[string]string
+
+	switch infra.Type {
+	case models.InfraTypeServer:
+		instanceType, _ := infra.Specifications["instance_type"].(string)
+		if instanceType == "" {
+			return nil, fmt.Errorf("%w: resource %s has no instance type", ErrReservedPricingUnavailable, infra.ID)
+		}
+		serviceCode = "AmazonEC2"
+		filters = ec2InstancePriceFilters(infra.Region, instanceType)
+	case models.InfraTypeDatabase:
+		instanceClass, _ := infra.Specifications["db_instance_class"].(string)
+		engine, _ := infra.Specifications["engine"].(string)
+		pricingEngine, ok := rdsPricingEngines[engine]
+		if instanceClass == "" || !ok {
+			return nil, fmt.Errorf("%w: resource %s has no supported instance class and engine", ErrReservedPricingUnavailable, infra.ID)
+		}
+		serviceCode = "AmazonRDS"
+		filters = rdsInstancePriceFilters(infra.Region, instanceClass, pricingEngine)
+	default:
+		return nil, fmt.Errorf("%w: %s resources cannot be reserved", ErrReservedPricingUnavailable, infra.Type)
+	}
+
+	items, err := p.getPriceList(ctx, serviceCode, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		reserved := &ReservedPricing{}
+		for _, term := range item.Terms.OnDemand {
+			_, reserved.OnDemandHourly = term.prices()
+		}
+		for _, term := range item.Terms.Reserved {
+			attributes := term.TermAttributes
+			if attributes.LeaseContractLength != "1yr" || attributes.OfferingClass != "standard" {
+				continue
+			}
+			upfront, hourly := term.prices()
+			reserved.Offers = append(reserved.Offers, ReservationOffer{
+				Term:            attributes.LeaseContractLength,
+				PaymentOption:   attributes.PurchaseOption,
+				UpfrontCost:     upfront,
+				RecurringHourly: hourly,
+			})
+		}
+
+		if reserved.OnDemandHourly > 0 && len(reserved.Offers) > 0 {
+			return reserved, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: no %s prices for %s in %s", ErrReservedPricingUnavailable, serviceCode, filters["instanceType"], infra.Region)
+}
+
+// EstimateCreateCost prices a planned EC2 or RDS resource's instance and storage with the current
+// on-demand prices of the AWS Pricing API. S3 buckets are billed by usage, so cannot be priced.
+func (p *RealAWSProvider) EstimateCreateCost(ctx context.Context, plan *CreatePlan) ([]CostComponent, error) {
+	switch plan.Type {
+	case models.InfraTypeServer:
+		instanceType, _ := plan.Specifications["instance_type"].(string)
+		hourly, err := p.getOnDemandPrice(ctx, "AmazonEC2", "Hrs", ec2InstancePriceFilters(plan.Region, instanceType))
+		if err != nil {
+			return nil, err
+		}
+		storagePerGB, err := p.getOnDemandPrice(ctx, "AmazonEC2", "GB-Mo", map[string]string{
+			"regionCode":    plan.Region,
+			"productFamily": "Storage",
+			"volumeApiName": "gp2",
+		})
+		if err != nil {
+			return nil, err
+		}
+		return ec2CostBreakdown(instanceType, hourly, storagePerGB), nil
+	case models.InfraTypeDatabase:
+		instanceClass, _ := plan.Specifications["db_instance_class"].(string)
+		engine, _ := plan.Specifications["engine"].(string)
+		allocatedStorage, _ := plan.Specifications["allocated_storage"].(int)
+		pricingEngine, ok := rdsPricingEngines[engine]
+		if !ok {
+			return nil, fmt.Errorf("%w: RDS engine %s", ErrPricingUnavailable, engine)
+		}
+		hourly, err := p.getOnDemandPrice(ctx, "AmazonRDS", "Hrs", rdsInstancePriceFilters(plan.Region, instanceClass, pricingEngine))
+		if err != nil {
+			return nil, err
+		}
+		storagePerGB, err := p.getOnDemandPrice(ctx, "AmazonRDS", "GB-Mo", map[string]string{
+			"regionCode":       plan.Region,
+			"productFamily":    "Database Storage",
+			"volumeType":       "General Purpose",
+			"databaseEngine":   pricingEngine,
+			"deploymentOption": "Single-AZ",
+		})
+		if err != nil {
+			return nil, err
+		}
+		return rdsCostBreakdown(instanceClass, allocatedStorage, hourly, storagePerGB), nil
+	default:
+		return nil, fmt.Errorf("%w: %s resources are billed by usage", ErrPricingUnavailable, plan.Type)
+	}
+}
+
+// ec2CostBreakdown is the cost of an EC2 instance and its root volume
+func ec2CostBreakdown(instanceType string, hourly, storagePerGB float64) []CostComponent {
+	return []CostComponent{
+		hourlyCostComponent(CostCategoryCompute, fmt.Sprintf("EC2 %s instance", instanceType), hourly),
+		monthlyCostComponent(CostCategoryStorage, fmt.Sprintf("%d GB gp2 root volume", ec2RootVolumeGB), ec2RootVolumeGB*storagePerGB),
+	}
+}
+
+// rdsCostBreakdown is the cost of an RDS instance and its allocated storage
+func rdsCostBreakdown(instanceClass string, allocatedStorage int, hourly, storagePerGB float64) []CostComponent {
+	return []CostComponent{
+		hourlyCostComponent(CostCategoryCompute, fmt.Sprintf("RDS %s instance", instanceClass), hourly),
+		monthlyCostComponent(CostCategoryStorage, fmt.Sprintf("%d GB gp2 storage", allocatedStorage), float64(allocatedStorage)*storagePerGB),
+	}
+}
+
+// ec2InstancePriceFilters select the price of a Linux EC2 instance type on shared hardware
+func ec2InstancePriceFilters(region, instanceType string) map[string]string {
+	return map[string]string{
+		"regionCode":      region,
+		"instanceType":    instanceType,
+		"operatingSystem": "Linux",
+		"tenancy":         "Shared",
+		"preInstalledSw":  "NA",
+		"capacitystatus":  "Used",
+	}
+}
+
+// rdsInstancePriceFilters select the price of a single-AZ RDS instance class running an engine
+func rdsInstancePriceFilters(region, instanceClass, pricingEngine string) map[string]string {
+	return map[string]string{
+		"regionCode":       region,
+		"instanceType":     instanceClass,
+		"databaseEngine":   pricingEngine,
+		"deploymentOption": "Single-AZ",
+	}
+}
+
+// getOnDemandPrice returns the on-demand USD price per unit of the first product of a service
+// matching filters
+func (p *RealAWSProvider) getOnDemandPrice(ctx context.Context, serviceCode, unit string, filters map[string]string) (float64, error) {
+	items, err := p.getPriceList(ctx, serviceCode, filters)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, item := range items {
+		for _, term := range item.Terms.OnDemand {
+			if price := term.unitPrice(unit); price > 0 {
+				return price, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("%w: no %s on-demand prices per %s matching %v", ErrPricingUnavailable, serviceCode, unit, filters)
+}
+
+// getPriceList looks up the price list entries of a service's products matching filters
+func (p *RealAWSProvider) getPriceList(ctx context.Context, serviceCode string, filters map[string]string) ([]awsPriceListItem, error) {
+	input := &pricing.GetProductsInput{
+		ServiceCode: aws.String(serviceCode),
+		MaxResults:  aws.Int32(10),
+	}
+	for field, value := range filters {
+		input.Filters = append(input.Filters, pricingtypes.Filter{
+			Type:  pricingtypes.FilterTypeTermMatch,
+			Field: aws.String(field),
+			Value: aws.String(value),
+		})
+	}
+
+	result, err := p.pricingClient.GetProducts(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AWS pricing: %w", err)
+	}
+
+	items := make([]awsPriceListItem, 0, len(result.PriceList))
+	for _, entry := range result.PriceList {
+		var item awsPriceListItem
+		if err := json.Unmarshal([]byte(entry), &item); err != nil {
+			return nil, fmt.Errorf("failed to parse AWS price list: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// unitPrice sums a price term's USD prices per unit
+func (t awsPriceTerm) unitPrice(unit string) float64 {
+	var total float64
+	for _, dimension := range t.PriceDimensions {
+		if dimension.Unit != unit {
+			continue
+		}
+		price, err := strconv.ParseFloat(dimension.PricePerUnit["USD"], 64)
+		if err == nil {
+			total += price
+		}
+	}
+	return total
+}
+
+// prices sums a price term's one-time and hourly USD prices
+func (t awsPriceTerm) prices() (upfront, hourly float64) {
+	for _, dimension := range t.PriceDimensions {
+		price, err := strconv.ParseFloat(dimension.PricePerUnit["USD"], 64)
+		if err != nil {
+			continue
+		}
+		switch dimension.Unit {
+		case "Quantity":
+			upfront += price
+		case "Hrs":
+			hourly += price
+		}
+	}
+	return upfront, hourly
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -11,48 +12,71 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
-	"github.com/aws/aws-sdk-go-v2/service/cloudwatch/types"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
 	"github.com/aws/aws-sdk-go-v2/service/pricing"
 	"github.com/aws/aws-sdk-go-v2/service/rds"
 	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
 )
 
 // RealAWSProvider implements CloudProvider for Amazon Web Services using AWS SDK
 type RealAWSProvider struct {
 	cfg           aws.Config
 	ec2Client     *ec2.Client
+	ecsClient     *ecs.Client
 	rdsClient     *rds.Client
 	s3Client      *s3.Client
 	cwClient      *cloudwatch.Client
 	pricingClient *pricing.Client
+	regional      *awsRegionalProviders
 }
 
 // NewRealAWSProvider creates a new AWS provider with real AWS SDK integration
 func NewRealAWSProvider(ctx context.Context) (*RealAWSProvider, error) {
+	// Default region, can be overridden
+	return newRealAWSProvider(ctx, "us-east-1")
+}
+
+// newRealAWSProvider creates an AWS provider for a region with the credentials from the
+// environment, unless options override them. Each provider has its own throttle, since AWS limits
+// requests per account.
+func newRealAWSProvider(ctx context.Context, region string, optFns ...func(*config.LoadOptions) error) (*RealAWSProvider, error) {
+	throttle := NewProviderThrottle(awsRequestsPerSecond, awsRequestBurst, DefaultProviderRetryConfig())
+
 	// Load AWS configuration from environment/credentials
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithRegion("us-east-1"), // Default region, can be overridden
-	)
+	cfg, err := config.LoadDefaultConfig(ctx, append([]func(*config.LoadOptions) error{
+		config.WithRegion(region),
+		config.WithRetryer(throttle.awsRetryer),
+		config.WithAPIOptions([]func(*middleware.Stack) error{throttle.awsRateLimit}),
+	}, optFns...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
-	return &RealAWSProvider{
+	// The Price List API is only served from a few regions, us-east-1 among them
+	pricingClient := pricing.NewFromConfig(cfg, func(o *pricing.Options) { o.Region = "us-east-1" })
+
+	provider := &RealAWSProvider{
 		cfg:           cfg,
 		ec2Client:     ec2.NewFromConfig(cfg),
+		ecsClient:     ecs.NewFromConfig(cfg),
 		rdsClient:     rds.NewFromConfig(cfg),
 		s3Client:      s3.NewFromConfig(cfg),
 		cwClient:      cloudwatch.NewFromConfig(cfg),
-		pricingClient: pricing.NewFromConfig(cfg),
-	}, nil
+		pricingClient: pricingClient,
+	}
+	provider.regional = &awsRegionalProviders{providers: map[string]*RealAWSProvider{region: provider}}
+	return provider, nil
 }
 
-// CreateResource creates infrastructure resources in AWS
+// CreateResource creates infrastructure resources in AWS, in the resource's region
 func (p *RealAWSProvider) CreateResource(ctx context.Context, infra *models.Infrastructure) (string, error) {
+	p = p.forInfra(infra)
 	switch infra.Type {
 	case models.InfraTypeServer:
 		return p.createEC2Instance(ctx, infra)
@@ -60,6 +84,8 @@ func (p *RealAWSProvider) CreateResource(ctx context.Context, infra *models.Infr
 		return p.createRDSInstance(ctx, infra)
 	case models.InfraTypeStorage:
 		return p.createS3Bucket(ctx, infra)
+	case models.InfraTypeContainer:
+		return p.createECSService(ctx, infra)
 	default:
 		return "", fmt.Errorf("unsupported infrastructure type: %s", infra.Type)
 	}
@@ -67,6 +93,21 @@ func (p *RealAWSProvider) CreateResource(ctx context.Context, infra *models.Infr
 
 // createEC2Instance creates an EC2 instance
 func (p *RealAWSProvider) createEC2Instance(ctx context.Context, infra *models.Infrastructure) (string, error) {
+	result, err := p.ec2Client.RunInstances(ctx, ec2RunInstancesInput(infra))
+	if err != nil {
+		return "", fmt.Errorf("failed to create EC2 instance: %w", err)
+	}
+
+	if len(result.Instances) == 0 {
+		return "", fmt.Errorf("no instances created")
+	}
+
+	instanceID := *result.Instances[0].InstanceId
+	return instanceID, nil
+}
+
+// ec2RunInstancesInput builds the request that launches a server resource's EC2 instance
+func ec2RunInstancesInput(infra *models.Infrastructure) *ec2.RunInstancesInput {
 	// Extract specifications
 	instanceType := "t3.micro" // Default
 	if specs, ok := infra.Specifications["instance_type"].(string); ok {
@@ -113,6 +154,16 @@ func (p *RealAWSProvider) createEC2Instance(ctx context.Context, infra *models.I
 					},
 				},
 			},
+			{
+				// Tag volumes too, so they can be traced back to the instance once detached
+				ResourceType: ec2types.ResourceTypeVolume,
+				Tags: []ec2types.Tag{
+					{
+						Key:   aws.String("CloudWeave-ID"),
+						Value: aws.String(infra.ID),
+					},
+				},
+			},
 		},
 	}
 
@@ -124,17 +175,7 @@ func (p *RealAWSProvider) createEC2Instance(ctx context.Context, infra *models.I
 		input.SecurityGroups = securityGroups
 	}
 
-	result, err := p.ec2Client.RunInstances(ctx, input)
-	if err != nil {
-		return "", fmt.Errorf("failed to create EC2 instance: %w", err)
-	}
-
-	if len(result.Instances) == 0 {
-		return "", fmt.Errorf("no instances created")
-	}
-
-	instanceID := *result.Instances[0].InstanceId
-	return instanceID, nil
+	return input
 }
 
 // createRDSInstance creates an RDS database instance
@@ -155,10 +196,7 @@ func (p *RealAWSProvider) createRDSInstance(ctx context.Context, infra *models.I
 		allocatedStorage = int32(storage)
 	}
 
-	dbName := strings.ReplaceAll(strings.ToLower(infra.Name), "-", "")
-	if len(dbName) > 63 {
-		dbName = dbName[:63]
-	}
+	dbName := rdsInstanceIdentifier(infra)
 
 	input := &rds.CreateDBInstanceInput{
 		DBInstanceIdentifier: aws.String(dbName),
@@ -191,10 +229,18 @@ func (p *RealAWSProvider) createRDSInstance(ctx context.Context, infra *models.I
 	return *result.DBInstance.DBInstanceIdentifier, nil
 }
 
+// rdsInstanceIdentifier derives the RDS instance identifier of a database resource from its name
+func rdsInstanceIdentifier(infra *models.Infrastructure) string {
+	dbName := strings.ReplaceAll(strings.ToLower(infra.Name), "-", "")
+	if len(dbName) > 63 {
+		dbName = dbName[:63]
+	}
+	return dbName
+}
+
 // createS3Bucket creates an S3 bucket
 func (p *RealAWSProvider) createS3Bucket(ctx context.Context, infra *models.Infrastructure) (string, error) {
-	bucketName := strings.ToLower(fmt.Sprintf("cloudweave-%s-%d",
-		strings.ReplaceAll(infra.Name, " ", "-"), time.Now().Unix()))
+	bucketName := s3BucketName(infra, time.Now())
 
 	input := &s3.CreateBucketInput{
 		Bucket: aws.String(bucketName),
@@ -240,11 +286,35 @@ func (p *RealAWSProvider) createS3Bucket(ctx context.Context, infra *models.Infr
 	return bucketName, nil
 }
 
+// s3BucketName derives the name of a storage resource's S3 bucket, made unique by its creation time
+func s3BucketName(infra *models.Infrastructure, createdAt time.Time) string {
+	return strings.ToLower(fmt.Sprintf("cloudweave-%s-%d",
+		strings.ReplaceAll(infra.Name, " ", "-"), createdAt.Unix()))
+}
+
+// ListRegions lists the regions enabled for the AWS account
+func (p *RealAWSProvider) ListRegions(ctx context.Context) ([]string, error) {
+	result, err := p.ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe regions: %w", err)
+	}
+
+	regions := make([]string, 0, len(result.Regions))
+	for _, region := range result.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
+	}
+	return regions, nil
+}
+
 // GetResourceStatus gets the current status from AWS
 func (p *RealAWSProvider) GetResourceStatus(ctx context.Context, externalID string) (string, error) {
+	p = p.forResource(ctx, externalID)
+
 	// Determine resource type based on external ID format
 	if strings.HasPrefix(externalID, "i-") {
 		return p.getEC2InstanceStatus(ctx, externalID)
+	} else if isECSServiceARN(externalID) {
+		return p.getECSServiceStatus(ctx, externalID)
 	} else if strings.Contains(externalID, "cloudweave-") && !strings.HasPrefix(externalID, "i-") {
 		// Likely an S3 bucket
 		return p.getS3BucketStatus(ctx, externalID)
@@ -327,100 +397,11 @@ func (p *RealAWSProvider) getS3BucketStatus(ctx context.Context, bucketName stri
 
 // GetResourceMetrics retrieves CloudWatch metrics for AWS resources
 func (p *RealAWSProvider) GetResourceMetrics(ctx context.Context, externalID string) (map[string]interface{}, error) {
-	if strings.HasPrefix(externalID, "i-") {
-		return p.getEC2Metrics(ctx, externalID)
-	} else if strings.Contains(externalID, "cloudweave-") && !strings.HasPrefix(externalID, "i-") {
-		return p.getS3Metrics(ctx, externalID)
-	} else {
-		return p.getRDSMetrics(ctx, externalID)
-	}
-}
-
-// getEC2Metrics gets CloudWatch metrics for EC2 instances
-func (p *RealAWSProvider) getEC2Metrics(ctx context.Context, instanceID string) (map[string]interface{}, error) {
-	endTime := time.Now()
-	startTime := endTime.Add(-1 * time.Hour)
-
-	metrics := map[string]interface{}{
-		"timestamp": endTime.Unix(),
-	}
-
-	// CPU Utilization
-	cpuInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/EC2"),
-		MetricName: aws.String("CPUUtilization"),
-		Dimensions: []types.Dimension{
-			{
-				Name:  aws.String("InstanceId"),
-				Value: aws.String(instanceID),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(300), // 5 minutes
-		Statistics: []types.Statistic{types.StatisticAverage},
-	}
-
-	cpuResult, err := p.cwClient.GetMetricStatistics(ctx, cpuInput)
-	if err == nil && len(cpuResult.Datapoints) > 0 {
-		metrics["cpu_utilization"] = *cpuResult.Datapoints[len(cpuResult.Datapoints)-1].Average
-	}
-
-	// Network In
-	netInInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/EC2"),
-		MetricName: aws.String("NetworkIn"),
-		Dimensions: []types.Dimension{
-			{
-				Name:  aws.String("InstanceId"),
-				Value: aws.String(instanceID),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(300),
-		Statistics: []types.Statistic{types.StatisticSum},
-	}
-
-	netInResult, err := p.cwClient.GetMetricStatistics(ctx, netInInput)
-	if err == nil && len(netInResult.Datapoints) > 0 {
-		metrics["network_in"] = *netInResult.Datapoints[len(netInResult.Datapoints)-1].Sum
-	}
-
-	return metrics, nil
-}
-
-// getRDSMetrics gets CloudWatch metrics for RDS instances
-func (p *RealAWSProvider) getRDSMetrics(ctx context.Context, dbInstanceID string) (map[string]interface{}, error) {
-	endTime := time.Now()
-	startTime := endTime.Add(-1 * time.Hour)
-
-	metrics := map[string]interface{}{
-		"timestamp": endTime.Unix(),
-	}
-
-	// CPU Utilization
-	cpuInput := &cloudwatch.GetMetricStatisticsInput{
-		Namespace:  aws.String("AWS/RDS"),
-		MetricName: aws.String("CPUUtilization"),
-		Dimensions: []types.Dimension{
-			{
-				Name:  aws.String("DBInstanceIdentifier"),
-				Value: aws.String(dbInstanceID),
-			},
-		},
-		StartTime:  aws.Time(startTime),
-		EndTime:    aws.Time(endTime),
-		Period:     aws.Int32(300),
-		Statistics: []types.Statistic{types.StatisticAverage},
-	}
-
-	cpuResult, err := p.cwClient.GetMetricStatistics(ctx, cpuInput)
-	if err == nil && len(cpuResult.Datapoints) > 0 {
-		metrics["cpu_utilization"] = *cpuResult.Datapoints[len(cpuResult.Datapoints)-1].Average
+	results, err := p.GetResourcesMetrics(ctx, []string{externalID})
+	if err != nil {
+		return nil, err
 	}
-
-	return metrics, nil
+	return results[externalID], nil
 }
 
 // getS3Metrics gets CloudWatch metrics for S3 buckets
@@ -438,8 +419,12 @@ func (p *RealAWSProvider) getS3Metrics(ctx context.Context, bucketName string) (
 
 // GetResourceDetails gets detailed information about AWS resources
 func (p *RealAWSProvider) GetResourceDetails(ctx context.Context, externalID string) (map[string]interface{}, error) {
+	p = p.forResource(ctx, externalID)
+
 	if strings.HasPrefix(externalID, "i-") {
 		return p.getEC2Details(ctx, externalID)
+	} else if isECSServiceARN(externalID) {
+		return p.getECSServiceDetails(ctx, externalID)
 	} else if strings.Contains(externalID, "cloudweave-") && !strings.HasPrefix(externalID, "i-") {
 		return p.getS3Details(ctx, externalID)
 	} else {
@@ -459,7 +444,7 @@ func (p *RealAWSProvider) getEC2Details(ctx context.Context, instanceID string)
 	}
 
 	if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
-		return nil, fmt.Errorf("instance not found")
+		return nil, fmt.Errorf("%w: instance %s", ErrCloudResourceNotFound, instanceID)
 	}
 
 	instance := result.Reservations[0].Instances[0]
@@ -485,12 +470,7 @@ func (p *RealAWSProvider) getEC2Details(ctx context.Context, instanceID string)
 			"public_ip":         aws.ToString(instance.PublicIpAddress),
 			"private_ip":        aws.ToString(instance.PrivateIpAddress),
 		},
-		"costInfo": map[string]interface{}{
-			"currency": "USD",
-			// Cost calculation would be more complex in reality
-			"hourly_cost":  p.getEC2HourlyCost(string(instance.InstanceType)),
-			"monthly_cost": p.getEC2HourlyCost(string(instance.InstanceType)) * 24 * 30,
-		},
+		"costInfo": fixedCostInfo(p.getEC2HourlyCost(string(instance.InstanceType))),
 	}
 
 	return details, nil
@@ -508,7 +488,7 @@ func (p *RealAWSProvider) getRDSDetails(ctx context.Context, dbInstanceID string
 	}
 
 	if len(result.DBInstances) == 0 {
-		return nil, fmt.Errorf("RDS instance not found")
+		return nil, fmt.Errorf("%w: RDS instance %s", ErrCloudResourceNotFound, dbInstanceID)
 	}
 
 	dbInstance := result.DBInstances[0]
@@ -534,17 +514,14 @@ func (p *RealAWSProvider) getRDSDetails(ctx context.Context, dbInstanceID string
 			"endpoint":          aws.ToString(dbInstance.Endpoint.Address),
 			"port":              *dbInstance.Endpoint.Port,
 		},
-		"costInfo": map[string]interface{}{
-			"currency":     "USD",
-			"hourly_cost":  p.getRDSHourlyCost(*dbInstance.DBInstanceClass),
-			"monthly_cost": p.getRDSHourlyCost(*dbInstance.DBInstanceClass) * 24 * 30,
-		},
+		"costInfo": fixedCostInfo(p.getRDSHourlyCost(*dbInstance.DBInstanceClass)),
 	}
 
 	return details, nil
 }
 
-// getS3Details gets detailed S3 bucket information
+// getS3Details gets detailed S3 bucket information, estimating its cost from the size CloudWatch
+// last reported, or as empty when it has reported none
 func (p *RealAWSProvider) getS3Details(ctx context.Context, bucketName string) (map[string]interface{}, error) {
 	// Check if bucket exists
 	_, err := p.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
@@ -554,25 +531,220 @@ func (p *RealAWSProvider) getS3Details(ctx context.Context, bucketName string) (
 		return nil, fmt.Errorf("failed to access S3 bucket: %w", err)
 	}
 
+	sizeBytes, _ := p.bucketSizeBytes(ctx, bucketName, time.Now())
+
 	details := map[string]interface{}{
 		"status": models.InfraStatusRunning,
 		"specifications": map[string]interface{}{
 			"bucket_name": bucketName,
 			"region":      p.cfg.Region,
 		},
-		"costInfo": map[string]interface{}{
-			"currency":     "USD",
-			"monthly_cost": 0.023, // Basic S3 storage cost per GB
-		},
+		"costInfo": usageCostInfo(costUnitGBMonth, awsS3StandardGBMonthlyCost, sizeBytes/(1<<30)),
 	}
 
 	return details, nil
 }
 
+// TagResource adds tags to an AWS resource, keeping its existing tags
+func (p *RealAWSProvider) TagResource(ctx context.Context, externalID string, tags map[string]string) error {
+	p = p.forResource(ctx, externalID)
+
+	if strings.HasPrefix(externalID, "i-") {
+		ec2Tags := make([]ec2types.Tag, 0, len(tags))
+		for key, value := range tags {
+			ec2Tags = append(ec2Tags, ec2types.Tag{Key: aws.String(key), Value: aws.String(value)})
+		}
+		_, err := p.ec2Client.CreateTags(ctx, &ec2.CreateTagsInput{
+			Resources: []string{externalID},
+			Tags:      ec2Tags,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to tag EC2 instance: %w", err)
+		}
+		return nil
+	}
+
+	if isECSServiceARN(externalID) {
+		return p.tagECSService(ctx, externalID, tags)
+	}
+
+	if strings.Contains(externalID, "cloudweave-") {
+		return p.tagS3Bucket(ctx, externalID, tags)
+	}
+
+	instanceARN, err := p.rdsInstanceARN(ctx, externalID)
+	if err != nil {
+		return err
+	}
+
+	rdsTags := make([]rdstypes.Tag, 0, len(tags))
+	for key, value := range tags {
+		rdsTags = append(rdsTags, rdstypes.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	_, err = p.rdsClient.AddTagsToResource(ctx, &rds.AddTagsToResourceInput{
+		ResourceName: instanceARN,
+		Tags:         rdsTags,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag RDS instance: %w", err)
+	}
+	return nil
+}
+
+// tagS3Bucket merges tags into a bucket's tag set, which S3 only replaces as a whole
+func (p *RealAWSProvider) tagS3Bucket(ctx context.Context, bucketName string, tags map[string]string) error {
+	merged := make(map[string]string)
+	existing, err := p.s3Client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: aws.String(bucketName)})
+	if err == nil {
+		for _, tag := range existing.TagSet {
+			merged[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+	}
+	for key, value := range tags {
+		merged[key] = value
+	}
+
+	tagSet := make([]s3types.Tag, 0, len(merged))
+	for key, value := range merged {
+		tagSet = append(tagSet, s3types.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	_, err = p.s3Client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+		Bucket:  aws.String(bucketName),
+		Tagging: &s3types.Tagging{TagSet: tagSet},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to tag S3 bucket: %w", err)
+	}
+	return nil
+}
+
+// UpdateTags sets and removes tags on an AWS resource. Tags are set and removed in separate calls,
+// so either can fail alone.
+func (p *RealAWSProvider) UpdateTags(ctx context.Context, externalID string, tags TagChanges) error {
+	p = p.forResource(ctx, externalID)
+
+	updateErr := &TagUpdateError{}
+	if len(tags.Set) > 0 {
+		if err := p.TagResource(ctx, externalID, tags.Set); err != nil {
+			updateErr.fail(models.TagSyncActionSet, tags.sortedKeys(), err)
+		}
+	}
+	if len(tags.Remove) > 0 {
+		if err := p.untagResource(ctx, externalID, tags.Remove); err != nil {
+			updateErr.fail(models.TagSyncActionRemove, tags.Remove, err)
+		}
+	}
+	return updateErr.errOrNil()
+}
+
+// untagResource removes the tags with keys from an AWS resource
+func (p *RealAWSProvider) untagResource(ctx context.Context, externalID string, keys []string) error {
+	if strings.HasPrefix(externalID, "i-") {
+		ec2Tags := make([]ec2types.Tag, len(keys))
+		for i, key := range keys {
+			ec2Tags[i] = ec2types.Tag{Key: aws.String(key)}
+		}
+		_, err := p.ec2Client.DeleteTags(ctx, &ec2.DeleteTagsInput{
+			Resources: []string{externalID},
+			Tags:      ec2Tags,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to untag EC2 instance: %w", err)
+		}
+		return nil
+	}
+
+	if isECSServiceARN(externalID) {
+		_, err := p.ecsClient.UntagResource(ctx, &ecs.UntagResourceInput{
+			ResourceArn: aws.String(externalID),
+			TagKeys:     keys,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to untag ECS service: %w", err)
+		}
+		return nil
+	}
+
+	if strings.Contains(externalID, "cloudweave-") {
+		return p.untagS3Bucket(ctx, externalID, keys)
+	}
+
+	instanceARN, err := p.rdsInstanceARN(ctx, externalID)
+	if err != nil {
+		return err
+	}
+	_, err = p.rdsClient.RemoveTagsFromResource(ctx, &rds.RemoveTagsFromResourceInput{
+		ResourceName: instanceARN,
+		TagKeys:      keys,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to untag RDS instance: %w", err)
+	}
+	return nil
+}
+
+// untagS3Bucket removes tags from a bucket's tag set, deleting the tag set once it is empty
+func (p *RealAWSProvider) untagS3Bucket(ctx context.Context, bucketName string, keys []string) error {
+	existing, err := p.s3Client.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: aws.String(bucketName)})
+	if err != nil {
+		// A bucket without tags has no tags to remove
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+			return nil
+		}
+		return fmt.Errorf("failed to get S3 bucket tags: %w", err)
+	}
+
+	removed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		removed[key] = true
+	}
+	tagSet := make([]s3types.Tag, 0, len(existing.TagSet))
+	for _, tag := range existing.TagSet {
+		if !removed[aws.ToString(tag.Key)] {
+			tagSet = append(tagSet, tag)
+		}
+	}
+	if len(tagSet) == len(existing.TagSet) {
+		return nil
+	}
+
+	if len(tagSet) == 0 {
+		_, err = p.s3Client.DeleteBucketTagging(ctx, &s3.DeleteBucketTaggingInput{Bucket: aws.String(bucketName)})
+	} else {
+		_, err = p.s3Client.PutBucketTagging(ctx, &s3.PutBucketTaggingInput{
+			Bucket:  aws.String(bucketName),
+			Tagging: &s3types.Tagging{TagSet: tagSet},
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to untag S3 bucket: %w", err)
+	}
+	return nil
+}
+
+// rdsInstanceARN returns the ARN of an RDS instance, which RDS tags it by
+func (p *RealAWSProvider) rdsInstanceARN(ctx context.Context, dbInstanceID string) (*string, error) {
+	result, err := p.rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe RDS instance: %w", err)
+	}
+	if len(result.DBInstances) == 0 {
+		return nil, fmt.Errorf("%w: RDS instance %s", ErrCloudResourceNotFound, dbInstanceID)
+	}
+	return result.DBInstances[0].DBInstanceArn, nil
+}
+
 // DeleteResource deletes AWS resources
 func (p *RealAWSProvider) DeleteResource(ctx context.Context, externalID string) error {
+	p = p.forResource(ctx, externalID)
+
 	if strings.HasPrefix(externalID, "i-") {
 		return p.deleteEC2Instance(ctx, externalID)
+	} else if isECSServiceARN(externalID) {
+		return p.deleteECSService(ctx, externalID)
 	} else if strings.Contains(externalID, "cloudweave-") && !strings.HasPrefix(externalID, "i-") {
 		return p.deleteS3Bucket(ctx, externalID)
 	} else {
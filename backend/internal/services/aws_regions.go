@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"sync"
+
+	"cloudweave/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// awsRegionalProviders holds the providers of an AWS account, one per region it has been called
+// in, keyed by region
+type awsRegionalProviders struct {
+	mu        sync.Mutex
+	providers map[string]*RealAWSProvider
+}
+
+// inRegion returns the provider whose regional clients call a region's endpoints, creating it on
+// first use. It shares the account's credentials, throttle and pricing client. An empty region
+// returns p.
+func (p *RealAWSProvider) inRegion(region string) *RealAWSProvider {
+	if region == "" || region == p.cfg.Region {
+		return p
+	}
+
+	p.regional.mu.Lock()
+	defer p.regional.mu.Unlock()
+	if provider, ok := p.regional.providers[region]; ok {
+		return provider
+	}
+
+	cfg := p.cfg.Copy()
+	cfg.Region = region
+	provider := &RealAWSProvider{
+		cfg:           cfg,
+		ec2Client:     ec2.NewFromConfig(cfg),
+		ecsClient:     ecs.NewFromConfig(cfg),
+		rdsClient:     rds.NewFromConfig(cfg),
+		s3Client:      s3.NewFromConfig(cfg),
+		cwClient:      cloudwatch.NewFromConfig(cfg),
+		pricingClient: p.pricingClient,
+		regional:      p.regional,
+	}
+	p.regional.providers[region] = provider
+	return provider
+}
+
+// forInfra returns the provider for an infrastructure resource's region
+func (p *RealAWSProvider) forInfra(infra *models.Infrastructure) *RealAWSProvider {
+	return p.inRegion(infra.Region)
+}
+
+// forResource returns the provider for the region of the resource an external ID names: the
+// region of an ARN, else the region set on ctx with withResourceRegion, else p's own region
+func (p *RealAWSProvider) forResource(ctx context.Context, externalID string) *RealAWSProvider {
+	return p.inRegion(awsResourceRegion(ctx, externalID))
+}
+
+// awsResourceRegion returns the region of the resource an external ID names, or "" when neither
+// the ID nor ctx tells
+func awsResourceRegion(ctx context.Context, externalID string) string {
+	if arn.IsARN(externalID) {
+		if parsed, err := arn.Parse(externalID); err == nil && parsed.Region != "" {
+			return parsed.Region
+		}
+	}
+	return resourceRegion(ctx)
+}
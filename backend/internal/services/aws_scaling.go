@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"cloudweave/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+)
+
+// ResizeResource changes the instance type of an EC2 instance or the instance class of an RDS
+// instance. A running EC2 instance has to be stopped for the change and is started again.
+func (p *RealAWSProvider) ResizeResource(ctx context.Context, infra *models.Infrastructure, size string) error {
+	p = p.forInfra(infra)
+
+	if infra.ExternalID == nil {
+		return ErrInfrastructureNotManaged
+	}
+
+	switch infra.Type {
+	case models.InfraTypeServer:
+		return p.resizeEC2Instance(ctx, *infra.ExternalID, size)
+	case models.InfraTypeDatabase:
+		return p.resizeRDSInstance(ctx, *infra.ExternalID, size)
+	default:
+		return fmt.Errorf("%w: unsupported infrastructure type: %s", ErrScalingUnsupported, infra.Type)
+	}
+}
+
+// resizeEC2Instance changes an EC2 instance's type, stopping it first if it is running
+func (p *RealAWSProvider) resizeEC2Instance(ctx context.Context, instanceID, instanceType string) error {
+	describeInput := &ec2.DescribeInstancesInput{
+		InstanceIds: []string{instanceID},
+	}
+
+	result, err := p.ec2Client.DescribeInstances(ctx, describeInput)
+	if err != nil {
+		return fmt.Errorf("failed to describe EC2 instance: %w", err)
+	}
+	if len(result.Reservations) == 0 || len(result.Reservations[0].Instances) == 0 {
+		return fmt.Errorf("%w: instance %s", ErrCloudResourceNotFound, instanceID)
+	}
+
+	instance := result.Reservations[0].Instances[0]
+	wasRunning := instance.State != nil && instance.State.Name == ec2types.InstanceStateNameRunning
+
+	if wasRunning {
+		if _, err := p.ec2Client.StopInstances(ctx, &ec2.StopInstancesInput{
+			InstanceIds: []string{instanceID},
+		}); err != nil {
+			return fmt.Errorf("failed to stop EC2 instance: %w", err)
+		}
+
+		if err := ec2.NewInstanceStoppedWaiter(p.ec2Client).Wait(ctx, describeInput, scalingTimeout); err != nil {
+			return fmt.Errorf("failed to wait for EC2 instance to stop: %w", err)
+		}
+	}
+
+	_, err = p.ec2Client.ModifyInstanceAttribute(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId: aws.String(instanceID),
+		InstanceType: &ec2types.AttributeValue{
+			Value: aws.String(instanceType),
+		},
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to change EC2 instance type: %w", err)
+	}
+
+	// Start the instance again even if the change failed, so it is not left stopped
+	if wasRunning {
+		if _, startErr := p.ec2Client.StartInstances(ctx, &ec2.StartInstancesInput{
+			InstanceIds: []string{instanceID},
+		}); startErr != nil {
+			if err != nil {
+				log.Printf("Failed to restart EC2 instance %s: %v", instanceID, startErr)
+				return err
+			}
+			return fmt.Errorf("failed to start EC2 instance: %w", startErr)
+		}
+	}
+
+	return err
+}
+
+// resizeRDSInstance changes an RDS instance's class. The change is applied immediately rather than
+// in the next maintenance window.
+func (p *RealAWSProvider) resizeRDSInstance(ctx context.Context, dbInstanceID, instanceClass string) error {
+	input := &rds.ModifyDBInstanceInput{
+		DBInstanceIdentifier: aws.String(dbInstanceID),
+		DBInstanceClass:      aws.String(instanceClass),
+		ApplyImmediately:     aws.Bool(true),
+	}
+
+	if _, err := p.rdsClient.ModifyDBInstance(ctx, input); err != nil {
+		return fmt.Errorf("failed to change RDS instance class: %w", err)
+	}
+
+	return nil
+}
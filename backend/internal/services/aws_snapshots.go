@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"cloudweave/internal/models"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	rdstypes "github.com/aws/aws-sdk-go-v2/service/rds/types"
+)
+
+// rdsSnapshotStatuses maps RDS snapshot statuses to snapshot statuses. Others, such as
+// "copying" or "pending", are still being created.
+var rdsSnapshotStatuses = map[string]string{
+	"available": models.SnapshotStatusAvailable,
+	"failed":    models.SnapshotStatusFailed,
+	"deleting":  models.SnapshotStatusDeleted,
+	"deleted":   models.SnapshotStatusDeleted,
+}
+
+// CreateSnapshot starts a manual snapshot of an RDS instance
+func (p *RealAWSProvider) CreateSnapshot(ctx context.Context, infra *models.Infrastructure, name string) (*ProviderSnapshot, error) {
+	p = p.forInfra(infra)
+
+	if infra.Type != models.InfraTypeDatabase {
+		return nil, fmt.Errorf("%w: %s resources cannot be snapshotted", ErrSnapshotsUnsupported, infra.Type)
+	}
+	if infra.ExternalID == nil {
+		return nil, ErrInfrastructureNotManaged
+	}
+
+	result, err := p.rdsClient.CreateDBSnapshot(ctx, &rds.CreateDBSnapshotInput{
+		DBInstanceIdentifier: infra.ExternalID,
+		DBSnapshotIdentifier: aws.String(snapshotIdentifier(name)),
+		Tags: []rdstypes.Tag{
+			{
+				Key:   aws.String("Name"),
+				Value: aws.String(name),
+			},
+			{
+				Key:   aws.String("CloudWeave-ID"),
+				Value: aws.String(infra.ID),
+			},
+			{
+				Key:   aws.String("CloudWeave-Managed"),
+				Value: aws.String("true"),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create RDS snapshot: %w", err)
+	}
+
+	snapshot := rdsProviderSnapshot(*result.DBSnapshot)
+	return &snapshot, nil
+}
+
+// ListSnapshots lists the manual snapshots of an RDS instance
+func (p *RealAWSProvider) ListSnapshots(ctx context.Context, infra *models.Infrastructure) ([]ProviderSnapshot, error) {
+	p = p.forInfra(infra)
+
+	if infra.Type != models.InfraTypeDatabase {
+		return nil, fmt.Errorf("%w: %s resources cannot be snapshotted", ErrSnapshotsUnsupported, infra.Type)
+	}
+	if infra.ExternalID == nil {
+		return nil, ErrInfrastructureNotManaged
+	}
+
+	var snapshots []ProviderSnapshot
+	paginator := rds.NewDescribeDBSnapshotsPaginator(p.rdsClient, &rds.DescribeDBSnapshotsInput{
+		DBInstanceIdentifier: infra.ExternalID,
+		SnapshotType:         aws.String("manual"),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe RDS snapshots: %w", err)
+		}
+		for _, dbSnapshot := range page.DBSnapshots {
+			snapshots = append(snapshots, rdsProviderSnapshot(dbSnapshot))
+		}
+	}
+
+	return snapshots, nil
+}
+
+// RestoreFromSnapshot creates a new RDS instance for the target resource from a snapshot. The
+// instance keeps the snapshot's engine and storage, with the source's instance class unless the
+// target's specifications name another.
+func (p *RealAWSProvider) RestoreFromSnapshot(ctx context.Context, source, target *models.Infrastructure, snapshotID string) (string, error) {
+	p = p.forInfra(target)
+
+	if target.Type != models.InfraTypeDatabase {
+		return "", fmt.Errorf("%w: %s resources cannot be restored from snapshots", ErrSnapshotsUnsupported, target.Type)
+	}
+
+	input := &rds.RestoreDBInstanceFromDBSnapshotInput{
+		DBInstanceIdentifier: aws.String(rdsInstanceIdentifier(target)),
+		DBSnapshotIdentifier: aws.String(snapshotID),
+		Tags: []rdstypes.Tag{
+			{
+				Key:   aws.String("Name"),
+				Value: aws.String(target.Name),
+			},
+			{
+				Key:   aws.String("CloudWeave-ID"),
+				Value: aws.String(target.ID),
+			},
+			{
+				Key:   aws.String("CloudWeave-Managed"),
+				Value: aws.String("true"),
+			},
+			{
+				Key:   aws.String("CloudWeave-Restored-From"),
+				Value: aws.String(snapshotID),
+			},
+		},
+	}
+	if class, ok := target.Specifications["db_instance_class"].(string); ok {
+		input.DBInstanceClass = aws.String(class)
+	}
+
+	result, err := p.rdsClient.RestoreDBInstanceFromDBSnapshot(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to restore RDS instance from snapshot %s of %s: %w", snapshotID, source.Name, err)
+	}
+
+	return *result.DBInstance.DBInstanceIdentifier, nil
+}
+
+// rdsProviderSnapshot converts an RDS snapshot
+func rdsProviderSnapshot(dbSnapshot rdstypes.DBSnapshot) ProviderSnapshot {
+	status := models.SnapshotStatusCreating
+	if mapped, ok := rdsSnapshotStatuses[aws.ToString(dbSnapshot.Status)]; ok {
+		status = mapped
+	}
+
+	snapshot := ProviderSnapshot{
+		ExternalID: aws.ToString(dbSnapshot.DBSnapshotIdentifier),
+		Status:     status,
+		Metadata: map[string]interface{}{
+			"arn":            aws.ToString(dbSnapshot.DBSnapshotArn),
+			"engine":         aws.ToString(dbSnapshot.Engine),
+			"engine_version": aws.ToString(dbSnapshot.EngineVersion),
+			"encrypted":      aws.ToBool(dbSnapshot.Encrypted),
+		},
+	}
+	if dbSnapshot.AllocatedStorage != nil {
+		sizeGB := float64(*dbSnapshot.AllocatedStorage)
+		snapshot.SizeGB = &sizeGB
+	}
+	if dbSnapshot.PercentProgress != nil {
+		snapshot.Metadata["percent_progress"] = *dbSnapshot.PercentProgress
+	}
+	if dbSnapshot.SnapshotCreateTime != nil {
+		snapshot.Metadata["snapshot_created_at"] = *dbSnapshot.SnapshotCreateTime
+	}
+	return snapshot
+}
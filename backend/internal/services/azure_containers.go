@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerinstance/armcontainerinstance/v2"
+)
+
+// Azure Container Instances list prices for Linux containers, per vCPU and per GB of memory
+const (
+	aciVCPUHourlyCost = 0.0486
+	aciGBHourlyCost   = 0.0054
+)
+
+// aciContainerName is the name of the single container of a container resource's container group
+const aciContainerName = "app"
+
+// isContainerGroupID reports whether an Azure external ID is the ID of a container group
+func isContainerGroupID(externalID string) bool {
+	return strings.Contains(externalID, "/containerGroups/")
+}
+
+// createContainerGroup runs a container resource's image as a container group that is restarted
+// whenever it exits, with a public IP when the container listens on a port
+func (p *RealAzureProvider) createContainerGroup(ctx context.Context, infra *models.Infrastructure) (string, error) {
+	spec := parseContainerSpec(infra.Specifications)
+	groupName := containerServiceName(infra)
+
+	container := &armcontainerinstance.Container{
+		Name: to.Ptr(aciContainerName),
+		Properties: &armcontainerinstance.ContainerProperties{
+			Image: to.Ptr(spec.Image),
+			Resources: &armcontainerinstance.ResourceRequirements{
+				Requests: &armcontainerinstance.ResourceRequests{
+					CPU:        to.Ptr(spec.CPU),
+					MemoryInGB: to.Ptr(aciMemoryGB(spec.MemoryMB)),
+				},
+			},
+		},
+	}
+	for _, name := range sortedKeys(spec.Env) {
+		container.Properties.EnvironmentVariables = append(container.Properties.EnvironmentVariables, &armcontainerinstance.EnvironmentVariable{
+			Name:  to.Ptr(name),
+			Value: to.Ptr(spec.Env[name]),
+		})
+	}
+
+	group := armcontainerinstance.ContainerGroup{
+		Location: to.Ptr(p.location),
+		Properties: &armcontainerinstance.ContainerGroupPropertiesProperties{
+			Containers:    []*armcontainerinstance.Container{container},
+			OSType:        to.Ptr(armcontainerinstance.OperatingSystemTypesLinux),
+			RestartPolicy: to.Ptr(armcontainerinstance.ContainerGroupRestartPolicyAlways),
+		},
+		Tags: map[string]*string{
+			"cloudweave-id":      to.Ptr(infra.ID),
+			"cloudweave-managed": to.Ptr("true"),
+		},
+	}
+	if spec.Port > 0 {
+		container.Properties.Ports = []*armcontainerinstance.ContainerPort{{
+			Port:     to.Ptr(int32(spec.Port)),
+			Protocol: to.Ptr(armcontainerinstance.ContainerNetworkProtocolTCP),
+		}}
+		group.Properties.IPAddress = &armcontainerinstance.IPAddress{
+			Type: to.Ptr(armcontainerinstance.ContainerGroupIPAddressTypePublic),
+			Ports: []*armcontainerinstance.Port{{
+				Port:     to.Ptr(int32(spec.Port)),
+				Protocol: to.Ptr(armcontainerinstance.ContainerGroupNetworkProtocolTCP),
+			}},
+		}
+	}
+
+	poller, err := p.containerClient.BeginCreateOrUpdate(ctx, p.resourceGroup, groupName, group, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create container group: %w", err)
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for container group creation: %w", err)
+	}
+
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.ContainerInstance/containerGroups/%s",
+		p.subscriptionID, p.resourceGroup, groupName), nil
+}
+
+// aciMemoryGB converts memory in MB to the GB Azure Container Instances sizes containers in, which
+// must be a multiple of 0.1 GB
+func aciMemoryGB(memoryMB int) float64 {
+	return math.Ceil(float64(memoryMB)/1024*10) / 10
+}
+
+// validateContainerGroup checks that no container group in the resource group has the container
+// resource's name, since creating over it would replace it
+func (p *RealAzureProvider) validateContainerGroup(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	groupName := containerServiceName(infra)
+	if err := validateContainerName(groupName); err != nil {
+		return nil, err
+	}
+
+	_, err := p.containerClient.Get(ctx, p.resourceGroup, groupName, nil)
+	if err == nil {
+		return nil, fmt.Errorf("%w: container group %s already exists", ErrCreateRejected, groupName)
+	}
+	if !isCloudResourceNotFound(err) {
+		return nil, fmt.Errorf("failed to validate container group: %w", err)
+	}
+
+	spec := parseContainerSpec(infra.Specifications)
+	memoryGB := aciMemoryGB(spec.MemoryMB)
+	plan := newCreatePlan(infra, groupName, map[string]interface{}{
+		"image":  spec.Image,
+		"cpu":    spec.CPU,
+		"memory": spec.MemoryMB,
+	}, 0)
+	plan.setCostBreakdown([]CostComponent{
+		hourlyCostComponent(CostCategoryCompute, fmt.Sprintf("Container group, %g vCPU and %g GB", spec.CPU, memoryGB), aciHourlyCost(spec.CPU, memoryGB)),
+	})
+	return plan, nil
+}
+
+// aciHourlyCost is the list price of running a Linux container of a size for an hour
+func aciHourlyCost(cpu, memoryGB float64) float64 {
+	return cpu*aciVCPUHourlyCost + memoryGB*aciGBHourlyCost
+}
+
+// getContainerGroup gets a container group by its external ID
+func (p *RealAzureProvider) getContainerGroup(ctx context.Context, externalID string) (*armcontainerinstance.ContainerGroup, error) {
+	parts := strings.Split(externalID, "/")
+	if len(parts) < 9 {
+		return nil, fmt.Errorf("invalid external ID format")
+	}
+	groupName := parts[len(parts)-1]
+
+	result, err := p.containerClient.Get(ctx, p.resourceGroup, groupName, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get container group: %w", err)
+	}
+	return &result.ContainerGroup, nil
+}
+
+// containerGroupStatus maps a container group's provisioning and instance state to an
+// infrastructure status
+func containerGroupStatus(group *armcontainerinstance.ContainerGroup) string {
+	properties := group.Properties
+	if properties == nil || properties.ProvisioningState == nil {
+		return models.InfraStatusPending
+	}
+
+	switch *properties.ProvisioningState {
+	case "Succeeded":
+	case "Failed":
+		return models.InfraStatusError
+	default:
+		return models.InfraStatusPending
+	}
+
+	if properties.InstanceView == nil || properties.InstanceView.State == nil {
+		return models.InfraStatusPending
+	}
+	switch *properties.InstanceView.State {
+	case "Running":
+		return models.InfraStatusRunning
+	case "Stopped":
+		return models.InfraStatusStopped
+	case "Failed":
+		return models.InfraStatusError
+	default:
+		return models.InfraStatusPending
+	}
+}
+
+// getContainerGroupStatus gets Container Group status
+func (p *RealAzureProvider) getContainerGroupStatus(ctx context.Context, externalID string) (string, error) {
+	group, err := p.getContainerGroup(ctx, externalID)
+	if err != nil {
+		if isCloudResourceNotFound(err) {
+			return models.InfraStatusTerminated, nil
+		}
+		return models.InfraStatusError, err
+	}
+	return containerGroupStatus(group), nil
+}
+
+// getContainerGroupDetails gets detailed Container Group information
+func (p *RealAzureProvider) getContainerGroupDetails(ctx context.Context, externalID string) (map[string]interface{}, error) {
+	group, err := p.getContainerGroup(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	specs := map[string]interface{}{
+		"container_group": ptrValue(group.Name),
+		"location":        ptrValue(group.Location),
+	}
+	var hourlyCost float64
+	if container := firstContainer(group); container != nil {
+		specs["image"] = ptrValue(container.Properties.Image)
+		if resources := container.Properties.Resources; resources != nil && resources.Requests != nil {
+			cpu, memoryGB := ptrValue(resources.Requests.CPU), ptrValue(resources.Requests.MemoryInGB)
+			specs["cpu"] = cpu
+			specs["memory_gb"] = memoryGB
+			hourlyCost = aciHourlyCost(cpu, memoryGB)
+		}
+	}
+	if group.Properties.IPAddress != nil {
+		specs["public_ip"] = ptrValue(group.Properties.IPAddress.IP)
+	}
+
+	return map[string]interface{}{
+		"status":         containerGroupStatus(group),
+		"specifications": specs,
+		"costInfo":       fixedCostInfo(hourlyCost),
+	}, nil
+}
+
+// getContainerGroupMetrics reports the resources a container group runs with and how often its
+// container has restarted, from the container group's instance view
+func (p *RealAzureProvider) getContainerGroupMetrics(ctx context.Context, externalID string) (map[string]interface{}, error) {
+	group, err := p.getContainerGroup(ctx, externalID)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := map[string]interface{}{
+		"timestamp": time.Now().Unix(),
+	}
+	if container := firstContainer(group); container != nil {
+		if resources := container.Properties.Resources; resources != nil && resources.Requests != nil {
+			metrics["cpu_requested"] = ptrValue(resources.Requests.CPU)
+			metrics["memory_requested_gb"] = ptrValue(resources.Requests.MemoryInGB)
+		}
+		if view := container.Properties.InstanceView; view != nil && view.RestartCount != nil {
+			metrics["restart_count"] = *view.RestartCount
+		}
+	}
+	return metrics, nil
+}
+
+// ptrValue returns the value an Azure SDK pointer field points to, or the zero value when it is unset
+func ptrValue[T any](p *T) T {
+	var value T
+	if p != nil {
+		value = *p
+	}
+	return value
+}
+
+// firstContainer returns the container of a container group CloudWeave created, or nil
+func firstContainer(group *armcontainerinstance.ContainerGroup) *armcontainerinstance.Container {
+	if group.Properties == nil || len(group.Properties.Containers) == 0 {
+		return nil
+	}
+	container := group.Properties.Containers[0]
+	if container == nil || container.Properties == nil {
+		return nil
+	}
+	return container
+}
+
+// deleteContainerGroup deletes a Container Group, stopping its container
+func (p *RealAzureProvider) deleteContainerGroup(ctx context.Context, externalID string) error {
+	parts := strings.Split(externalID, "/")
+	if len(parts) < 9 {
+		return fmt.Errorf("invalid external ID format")
+	}
+	groupName := parts[len(parts)-1]
+
+	poller, err := p.containerClient.BeginDelete(ctx, p.resourceGroup, groupName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete container group: %w", err)
+	}
+
+	_, err = poller.PollUntilDone(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to wait for container group deletion: %w", err)
+	}
+
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerinstance/armcontainerinstance/v2"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
@@ -25,11 +26,18 @@ type RealAzureProvider struct {
 	credential     *azidentity.DefaultAzureCredential
 
 	// Clients
-	vmClient       *armcompute.VirtualMachinesClient
-	networkClient  *armnetwork.VirtualNetworksClient
-	sqlClient      *armsql.ServersClient
-	resourceClient *armresources.ResourceGroupsClient
-	blobClient     *azblob.Client
+	vmClient        *armcompute.VirtualMachinesClient
+	networkClient   *armnetwork.VirtualNetworksClient
+	sqlClient       *armsql.ServersClient
+	databaseClient  *armsql.DatabasesClient
+	containerClient *armcontainerinstance.ContainerGroupsClient
+	resourceClient  *armresources.ResourceGroupsClient
+	providersClient *armresources.ProvidersClient
+	tagsClient      *armresources.TagsClient
+	blobClient      *azblob.Client
+
+	// Orphaned resource detection is not implemented for Azure yet
+	orphanDetectionUnsupported
 }
 
 // NewRealAzureProvider creates a new Azure provider with real Azure SDK integration
@@ -39,30 +47,58 @@ func NewRealAzureProvider(ctx context.Context, subscriptionID, resourceGroup, lo
 		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
 	}
 
+	// All clients share one throttle, since Azure Resource Manager limits the whole subscription
+	throttle := NewProviderThrottle(azureRequestsPerSecond, azureRequestBurst, DefaultProviderRetryConfig())
+	clientOptions := throttle.azureClientOptions()
+
 	// Initialize Virtual Machine client
-	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, credential, nil)
+	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, credential, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create VM client: %w", err)
 	}
 
 	// Initialize Network client
-	networkClient, err := armnetwork.NewVirtualNetworksClient(subscriptionID, credential, nil)
+	networkClient, err := armnetwork.NewVirtualNetworksClient(subscriptionID, credential, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create network client: %w", err)
 	}
 
 	// Initialize SQL client
-	sqlClient, err := armsql.NewServersClient(subscriptionID, credential, nil)
+	sqlClient, err := armsql.NewServersClient(subscriptionID, credential, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create SQL client: %w", err)
 	}
 
+	// Initialize SQL Database client, which copies databases for snapshots
+	databaseClient, err := armsql.NewDatabasesClient(subscriptionID, credential, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL database client: %w", err)
+	}
+
+	// Initialize Container Instances client
+	containerClient, err := armcontainerinstance.NewContainerGroupsClient(subscriptionID, credential, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container client: %w", err)
+	}
+
 	// Initialize Resource Group client
-	resourceClient, err := armresources.NewResourceGroupsClient(subscriptionID, credential, nil)
+	resourceClient, err := armresources.NewResourceGroupsClient(subscriptionID, credential, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource group client: %w", err)
 	}
 
+	// Initialize Resource Provider client, which lists the regions resources can be created in
+	providersClient, err := armresources.NewProvidersClient(subscriptionID, credential, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource provider client: %w", err)
+	}
+
+	// Initialize Tags client, which updates the tags of any resource
+	tagsClient, err := armresources.NewTagsClient(subscriptionID, credential, clientOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tags client: %w", err)
+	}
+
 	// Initialize Blob Storage client (using connection string for simplicity)
 	// In production, you'd use managed identity or service principal
 	blobClient, err := azblob.NewClientFromConnectionString("", nil)
@@ -72,18 +108,48 @@ func NewRealAzureProvider(ctx context.Context, subscriptionID, resourceGroup, lo
 	}
 
 	return &RealAzureProvider{
-		subscriptionID: subscriptionID,
-		resourceGroup:  resourceGroup,
-		location:       location,
-		credential:     credential,
-		vmClient:       vmClient,
-		networkClient:  networkClient,
-		sqlClient:      sqlClient,
-		resourceClient: resourceClient,
-		blobClient:     blobClient,
+		subscriptionID:  subscriptionID,
+		resourceGroup:   resourceGroup,
+		location:        location,
+		credential:      credential,
+		vmClient:        vmClient,
+		networkClient:   networkClient,
+		sqlClient:       sqlClient,
+		databaseClient:  databaseClient,
+		containerClient: containerClient,
+		resourceClient:  resourceClient,
+		providersClient: providersClient,
+		tagsClient:      tagsClient,
+		blobClient:      blobClient,
 	}, nil
 }
 
+// ListRegions lists the regions virtual machines can run in for the subscription
+func (p *RealAzureProvider) ListRegions(ctx context.Context) ([]string, error) {
+	resp, err := p.providersClient.Get(ctx, "Microsoft.Compute", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the Microsoft.Compute resource provider: %w", err)
+	}
+	return azureVirtualMachineLocations(resp.Provider), nil
+}
+
+// azureVirtualMachineLocations returns the regions, as location names such as "westeurope", that
+// the Microsoft.Compute resource provider offers virtual machines in
+func azureVirtualMachineLocations(provider armresources.Provider) []string {
+	var locations []string
+	for _, resourceType := range provider.ResourceTypes {
+		if resourceType.ResourceType == nil || *resourceType.ResourceType != "virtualMachines" {
+			continue
+		}
+		for _, location := range resourceType.Locations {
+			if location != nil {
+				locations = append(locations, strings.ToLower(strings.ReplaceAll(*location, " ", "")))
+			}
+		}
+	}
+	return locations
+}
+
 // CreateResource creates infrastructure resources in Azure
 func (p *RealAzureProvider) CreateResource(ctx context.Context, infra *models.Infrastructure) (string, error) {
 	switch infra.Type {
@@ -93,6 +159,8 @@ func (p *RealAzureProvider) CreateResource(ctx context.Context, infra *models.In
 		return p.createSQLDatabase(ctx, infra)
 	case models.InfraTypeStorage:
 		return p.createStorageAccount(ctx, infra)
+	case models.InfraTypeContainer:
+		return p.createContainerGroup(ctx, infra)
 	default:
 		return "", fmt.Errorf("unsupported infrastructure type: %s", infra.Type)
 	}
@@ -227,9 +295,87 @@ func (p *RealAzureProvider) createStorageAccount(ctx context.Context, infra *mod
 		p.subscriptionID, p.resourceGroup, storageAccountName), nil
 }
 
+// ValidateCreate checks that Azure would create the resource, without creating it. Creating a VM
+// or SQL server over an existing one updates it in place, so names already in use are rejected.
+func (p *RealAzureProvider) ValidateCreate(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	if _, err := p.resourceClient.Get(ctx, p.resourceGroup, nil); err != nil {
+		if isCloudResourceNotFound(err) {
+			return nil, fmt.Errorf("%w: resource group %s does not exist", ErrCreateRejected, p.resourceGroup)
+		}
+		return nil, fmt.Errorf("failed to get resource group: %w", err)
+	}
+
+	switch infra.Type {
+	case models.InfraTypeServer:
+		return p.validateVirtualMachine(ctx, infra)
+	case models.InfraTypeDatabase:
+		return p.validateSQLDatabase(ctx, infra)
+	case models.InfraTypeStorage:
+		storageAccountName := fmt.Sprintf("st%s%d",
+			strings.ToLower(strings.ReplaceAll(infra.Name, " ", "")), time.Now().Unix())
+		plan := newCreatePlan(infra, storageAccountName, map[string]interface{}{}, 0)
+		plan.Warnings = append(plan.Warnings, usageBilledWarning)
+		return plan, nil
+	case models.InfraTypeContainer:
+		return p.validateContainerGroup(ctx, infra)
+	default:
+		return nil, fmt.Errorf("%w: unsupported infrastructure type: %s", ErrCreateRejected, infra.Type)
+	}
+}
+
+// validateVirtualMachine checks that no VM in the resource group has the server resource's name
+func (p *RealAzureProvider) validateVirtualMachine(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	_, err := p.vmClient.Get(ctx, p.resourceGroup, infra.Name, nil)
+	if err == nil {
+		return nil, fmt.Errorf("%w: virtual machine %s already exists", ErrCreateRejected, infra.Name)
+	}
+	if !isCloudResourceNotFound(err) {
+		return nil, fmt.Errorf("failed to validate VM: %w", err)
+	}
+
+	vmSize := "Standard_B2s" // Default
+	if specs, ok := infra.Specifications["vm_size"].(string); ok {
+		vmSize = specs
+	}
+
+	return newCreatePlan(infra, infra.Name, map[string]interface{}{
+		"vm_size": vmSize,
+	}, p.getVMHourlyCost(vmSize)), nil
+}
+
+// validateSQLDatabase checks with Azure that the database resource's SQL server name is available
+func (p *RealAzureProvider) validateSQLDatabase(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	serverName := fmt.Sprintf("sql-%s", strings.ToLower(infra.Name))
+
+	result, err := p.sqlClient.CheckNameAvailability(ctx, armsql.CheckNameAvailabilityRequest{
+		Name: to.Ptr(serverName),
+		Type: to.Ptr("Microsoft.Sql/servers"),
+	}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check SQL server name: %w", err)
+	}
+	if result.Available == nil || !*result.Available {
+		message := "name is not available"
+		if result.Message != nil {
+			message = *result.Message
+		}
+		return nil, fmt.Errorf("%w: SQL server %s: %s", ErrCreateRejected, serverName, message)
+	}
+
+	specs := map[string]interface{}{}
+	skuName, _ := infra.Specifications["sku_name"].(string)
+	if skuName != "" {
+		specs["sku_name"] = skuName
+	}
+
+	return newCreatePlan(infra, serverName, specs, p.getSQLHourlyCost(skuName)), nil
+}
+
 // GetResourceStatus gets the current status from Azure
 func (p *RealAzureProvider) GetResourceStatus(ctx context.Context, externalID string) (string, error) {
-	if strings.Contains(externalID, "/virtualMachines/") {
+	if isContainerGroupID(externalID) {
+		return p.getContainerGroupStatus(ctx, externalID)
+	} else if strings.Contains(externalID, "/virtualMachines/") {
 		return p.getVirtualMachineStatus(ctx, externalID)
 	} else if strings.Contains(externalID, "/servers/") {
 		return p.getSQLServerStatus(ctx, externalID)
@@ -310,6 +456,10 @@ func (p *RealAzureProvider) getStorageAccountStatus(ctx context.Context, externa
 
 // GetResourceMetrics retrieves metrics for Azure resources
 func (p *RealAzureProvider) GetResourceMetrics(ctx context.Context, externalID string) (map[string]interface{}, error) {
+	if isContainerGroupID(externalID) {
+		return p.getContainerGroupMetrics(ctx, externalID)
+	}
+
 	// For now, return simulated metrics
 	// In a real implementation, this would use Azure Monitor API
 	return map[string]interface{}{
@@ -323,7 +473,9 @@ func (p *RealAzureProvider) GetResourceMetrics(ctx context.Context, externalID s
 
 // GetResourceDetails gets detailed information about Azure resources
 func (p *RealAzureProvider) GetResourceDetails(ctx context.Context, externalID string) (map[string]interface{}, error) {
-	if strings.Contains(externalID, "/virtualMachines/") {
+	if isContainerGroupID(externalID) {
+		return p.getContainerGroupDetails(ctx, externalID)
+	} else if strings.Contains(externalID, "/virtualMachines/") {
 		return p.getVirtualMachineDetails(ctx, externalID)
 	} else if strings.Contains(externalID, "/servers/") {
 		return p.getSQLServerDetails(ctx, externalID)
@@ -366,11 +518,7 @@ func (p *RealAzureProvider) getVirtualMachineDetails(ctx context.Context, extern
 			"location":       *vm.Location,
 			"resource_group": p.resourceGroup,
 		},
-		"costInfo": map[string]interface{}{
-			"currency":     "USD",
-			"hourly_cost":  p.getVMHourlyCost(string(*vm.Properties.HardwareProfile.VMSize)),
-			"monthly_cost": p.getVMHourlyCost(string(*vm.Properties.HardwareProfile.VMSize)) * 24 * 30,
-		},
+		"costInfo": fixedCostInfo(p.getVMHourlyCost(string(*vm.Properties.HardwareProfile.VMSize))),
 	}
 
 	return details, nil
@@ -407,16 +555,15 @@ func (p *RealAzureProvider) getSQLServerDetails(ctx context.Context, externalID
 			"location":       *server.Location,
 			"resource_group": p.resourceGroup,
 		},
-		"costInfo": map[string]interface{}{
-			"currency":     "USD",
-			"hourly_cost":  p.getSQLHourlyCost("Basic"),
-			"monthly_cost": p.getSQLHourlyCost("Basic") * 24 * 30,
-		},
+		"costInfo": fixedCostInfo(p.getSQLHourlyCost("Basic")),
 	}
 
 	return details, nil
 }
 
+// azureBlobGBMonthlyCost is the Standard LRS hot blob storage price per GB-month
+const azureBlobGBMonthlyCost = 0.0184
+
 // getStorageAccountDetails gets detailed Storage Account information
 func (p *RealAzureProvider) getStorageAccountDetails(ctx context.Context, externalID string) (map[string]interface{}, error) {
 	// For demo purposes, return simulated details
@@ -427,10 +574,7 @@ func (p *RealAzureProvider) getStorageAccountDetails(ctx context.Context, extern
 			"location":       p.location,
 			"resource_group": p.resourceGroup,
 		},
-		"costInfo": map[string]interface{}{
-			"currency":     "USD",
-			"monthly_cost": 0.0184, // Basic Azure Storage cost per GB
-		},
+		"costInfo": usageCostInfo(costUnitGBMonth, azureBlobGBMonthlyCost, 0),
 	}
 
 	return details, nil
@@ -438,7 +582,9 @@ func (p *RealAzureProvider) getStorageAccountDetails(ctx context.Context, extern
 
 // DeleteResource deletes Azure resources
 func (p *RealAzureProvider) DeleteResource(ctx context.Context, externalID string) error {
-	if strings.Contains(externalID, "/virtualMachines/") {
+	if isContainerGroupID(externalID) {
+		return p.deleteContainerGroup(ctx, externalID)
+	} else if strings.Contains(externalID, "/virtualMachines/") {
 		return p.deleteVirtualMachine(ctx, externalID)
 	} else if strings.Contains(externalID, "/servers/") {
 		return p.deleteSQLServer(ctx, externalID)
@@ -447,6 +593,106 @@ func (p *RealAzureProvider) DeleteResource(ctx context.Context, externalID strin
 	}
 }
 
+// TagResource adds tags to an Azure Virtual Machine, keeping its existing tags. Other Azure resource
+// types are not tagged yet.
+func (p *RealAzureProvider) TagResource(ctx context.Context, externalID string, tags map[string]string) error {
+	if !strings.Contains(externalID, "/virtualMachines/") {
+		return nil
+	}
+
+	parts := strings.Split(externalID, "/")
+	if len(parts) < 9 {
+		return fmt.Errorf("invalid external ID format")
+	}
+	vmName := parts[len(parts)-1]
+
+	vm, err := p.vmClient.Get(ctx, p.resourceGroup, vmName, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get VM: %w", err)
+	}
+
+	merged := make(map[string]*string, len(vm.Tags)+len(tags))
+	for key, value := range vm.Tags {
+		merged[key] = value
+	}
+	for key, value := range tags {
+		merged[key] = to.Ptr(value)
+	}
+
+	poller, err := p.vmClient.BeginUpdate(ctx, p.resourceGroup, vmName, armcompute.VirtualMachineUpdate{Tags: merged}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to tag VM: %w", err)
+	}
+
+	if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+		return fmt.Errorf("failed to wait for VM tagging: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateTags merges tags into an Azure resource and deletes the tags with the removed keys. Tags
+// are merged and deleted in separate calls, so either can fail alone.
+func (p *RealAzureProvider) UpdateTags(ctx context.Context, externalID string, tags TagChanges) error {
+	updateErr := &TagUpdateError{}
+
+	if len(tags.Set) > 0 {
+		merged := make(map[string]*string, len(tags.Set))
+		for key, value := range tags.Set {
+			merged[key] = to.Ptr(value)
+		}
+		_, err := p.tagsClient.UpdateAtScope(ctx, externalID, armresources.TagsPatchResource{
+			Operation:  to.Ptr(armresources.TagsPatchOperationMerge),
+			Properties: &armresources.Tags{Tags: merged},
+		}, nil)
+		if err != nil {
+			updateErr.fail(models.TagSyncActionSet, tags.sortedKeys(), fmt.Errorf("failed to tag resource: %w", err))
+		}
+	}
+
+	if len(tags.Remove) > 0 {
+		if err := p.untagResource(ctx, externalID, tags.Remove); err != nil {
+			updateErr.fail(models.TagSyncActionRemove, tags.Remove, err)
+		}
+	}
+
+	return updateErr.errOrNil()
+}
+
+// untagResource deletes the tags with keys from an Azure resource. Deleting tags matches names and
+// values, so the resource's current values are looked up first.
+func (p *RealAzureProvider) untagResource(ctx context.Context, externalID string, keys []string) error {
+	current, err := p.tagsClient.GetAtScope(ctx, externalID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get resource tags: %w", err)
+	}
+	if current.Properties == nil {
+		return nil
+	}
+
+	removed := make(map[string]*string, len(keys))
+	for _, key := range keys {
+		for name, value := range current.Properties.Tags {
+			// Azure tag names are case-insensitive
+			if strings.EqualFold(name, key) {
+				removed[name] = value
+			}
+		}
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	_, err = p.tagsClient.UpdateAtScope(ctx, externalID, armresources.TagsPatchResource{
+		Operation:  to.Ptr(armresources.TagsPatchOperationDelete),
+		Properties: &armresources.Tags{Tags: removed},
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to untag resource: %w", err)
+	}
+	return nil
+}
+
 // deleteVirtualMachine deletes a Virtual Machine
 func (p *RealAzureProvider) deleteVirtualMachine(ctx context.Context, externalID string) error {
 	parts := strings.Split(externalID, "/")
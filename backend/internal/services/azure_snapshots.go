@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"cloudweave/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
+)
+
+// Tags marking the database copies that make up an Azure SQL snapshot
+const (
+	azureSnapshotTag       = "cloudweave-snapshot"
+	azureSnapshotSourceTag = "cloudweave-snapshot-of"
+)
+
+// azureMaxDatabaseNameLength is the longest name an Azure SQL database can have
+const azureMaxDatabaseNameLength = 128
+
+// CreateSnapshot snapshots the databases of an Azure SQL server. Azure SQL has no manual
+// snapshots, so each database is copied, transactionally consistent, to a database on the same
+// server tagged with the snapshot. The copies are still being made when this returns.
+func (p *RealAzureProvider) CreateSnapshot(ctx context.Context, infra *models.Infrastructure, name string) (*ProviderSnapshot, error) {
+	if infra.Type != models.InfraTypeDatabase {
+		return nil, fmt.Errorf("%w: %s resources cannot be snapshotted", ErrSnapshotsUnsupported, infra.Type)
+	}
+	if infra.ExternalID == nil {
+		return nil, ErrInfrastructureNotManaged
+	}
+	serverName, err := azureSQLServerName(*infra.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	databases, err := p.listSQLDatabases(ctx, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshotID := snapshotIdentifier(name)
+	var copied []string
+	for _, database := range databases {
+		if database.Name == nil || *database.Name == "master" || database.Tags[azureSnapshotTag] != nil {
+			continue
+		}
+
+		copyName := fmt.Sprintf("%s-%s", *database.Name, snapshotID)
+		if len(copyName) > azureMaxDatabaseNameLength {
+			copyName = copyName[:azureMaxDatabaseNameLength]
+		}
+		_, err := p.databaseClient.BeginCreateOrUpdate(ctx, p.resourceGroup, serverName, copyName, armsql.Database{
+			Location: database.Location,
+			Properties: &armsql.DatabaseProperties{
+				CreateMode:       to.Ptr(armsql.CreateModeCopy),
+				SourceDatabaseID: database.ID,
+			},
+			Tags: map[string]*string{
+				azureSnapshotTag:       to.Ptr(snapshotID),
+				azureSnapshotSourceTag: database.Name,
+				"cloudweave-id":        to.Ptr(infra.ID),
+				"cloudweave-managed":   to.Ptr("true"),
+			},
+		}, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy SQL database %s: %w", *database.Name, err)
+		}
+		copied = append(copied, *database.Name)
+	}
+	if len(copied) == 0 {
+		return nil, fmt.Errorf("SQL server %s has no databases to snapshot", serverName)
+	}
+
+	return &ProviderSnapshot{
+		ExternalID: snapshotID,
+		Status:     models.SnapshotStatusCreating,
+		Metadata: map[string]interface{}{
+			"server":    serverName,
+			"databases": copied,
+		},
+	}, nil
+}
+
+// ListSnapshots lists the snapshots of an Azure SQL server from the database copies on it. A
+// snapshot is available once all of its copies are online.
+func (p *RealAzureProvider) ListSnapshots(ctx context.Context, infra *models.Infrastructure) ([]ProviderSnapshot, error) {
+	if infra.Type != models.InfraTypeDatabase {
+		return nil, fmt.Errorf("%w: %s resources cannot be snapshotted", ErrSnapshotsUnsupported, infra.Type)
+	}
+	if infra.ExternalID == nil {
+		return nil, ErrInfrastructureNotManaged
+	}
+	serverName, err := azureSQLServerName(*infra.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+
+	databases, err := p.listSQLDatabases(ctx, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []ProviderSnapshot
+	index := make(map[string]int)
+	for _, database := range databases {
+		snapshotID := database.Tags[azureSnapshotTag]
+		if snapshotID == nil {
+			continue
+		}
+
+		i, ok := index[*snapshotID]
+		if !ok {
+			i = len(snapshots)
+			index[*snapshotID] = i
+			snapshots = append(snapshots, ProviderSnapshot{
+				ExternalID: *snapshotID,
+				Status:     models.SnapshotStatusAvailable,
+				Metadata:   map[string]interface{}{"server": serverName},
+			})
+		}
+
+		if database.Properties == nil || database.Properties.Status == nil {
+			continue
+		}
+		switch *database.Properties.Status {
+		case armsql.DatabaseStatusCopying, armsql.DatabaseStatusCreating:
+			if snapshots[i].Status == models.SnapshotStatusAvailable {
+				snapshots[i].Status = models.SnapshotStatusCreating
+			}
+		case armsql.DatabaseStatusOnline:
+		default:
+			snapshots[i].Status = models.SnapshotStatusFailed
+		}
+	}
+
+	return snapshots, nil
+}
+
+// RestoreFromSnapshot creates an Azure SQL server for the target resource and copies the
+// databases of a snapshot of the source server to it under their original names
+func (p *RealAzureProvider) RestoreFromSnapshot(ctx context.Context, source, target *models.Infrastructure, snapshotID string) (string, error) {
+	if target.Type != models.InfraTypeDatabase {
+		return "", fmt.Errorf("%w: %s resources cannot be restored from snapshots", ErrSnapshotsUnsupported, target.Type)
+	}
+	if source.ExternalID == nil {
+		return "", ErrInfrastructureNotManaged
+	}
+	sourceServer, err := azureSQLServerName(*source.ExternalID)
+	if err != nil {
+		return "", err
+	}
+
+	databases, err := p.listSQLDatabases(ctx, sourceServer)
+	if err != nil {
+		return "", err
+	}
+	var copies []*armsql.Database
+	for _, database := range databases {
+		if tag := database.Tags[azureSnapshotTag]; tag != nil && *tag == snapshotID && database.Tags[azureSnapshotSourceTag] != nil {
+			copies = append(copies, database)
+		}
+	}
+	if len(copies) == 0 {
+		return "", fmt.Errorf("%w: snapshot %s of SQL server %s", ErrCloudResourceNotFound, snapshotID, sourceServer)
+	}
+
+	externalID, err := p.createSQLDatabase(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	targetServer, err := azureSQLServerName(externalID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, database := range copies {
+		databaseName := *database.Tags[azureSnapshotSourceTag]
+		poller, err := p.databaseClient.BeginCreateOrUpdate(ctx, p.resourceGroup, targetServer, databaseName, armsql.Database{
+			Location: to.Ptr(p.location),
+			Properties: &armsql.DatabaseProperties{
+				CreateMode:       to.Ptr(armsql.CreateModeCopy),
+				SourceDatabaseID: database.ID,
+			},
+			Tags: map[string]*string{
+				"cloudweave-id":            to.Ptr(target.ID),
+				"cloudweave-managed":       to.Ptr("true"),
+				"cloudweave-restored-from": to.Ptr(snapshotID),
+			},
+		}, nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to restore SQL database %s: %w", databaseName, err)
+		}
+		if _, err := poller.PollUntilDone(ctx, nil); err != nil {
+			return "", fmt.Errorf("failed to wait for SQL database %s to be restored: %w", databaseName, err)
+		}
+	}
+
+	return externalID, nil
+}
+
+// listSQLDatabases lists the databases of an Azure SQL server
+func (p *RealAzureProvider) listSQLDatabases(ctx context.Context, serverName string) ([]*armsql.Database, error) {
+	var databases []*armsql.Database
+	pager := p.databaseClient.NewListByServerPager(p.resourceGroup, serverName, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list SQL databases: %w", err)
+		}
+		databases = append(databases, page.Value...)
+	}
+	return databases, nil
+}
+
+// azureSQLServerName returns the server name of an Azure SQL server resource ID
+func azureSQLServerName(externalID string) (string, error) {
+	parts := strings.Split(externalID, "/")
+	if len(parts) < 9 || !strings.Contains(externalID, "/servers/") {
+		return "", fmt.Errorf("invalid external ID format")
+	}
+	return parts[len(parts)-1], nil
+}
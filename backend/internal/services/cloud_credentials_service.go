@@ -100,6 +100,46 @@ func (s *CloudCredentialsService) SetupAWSAccessKey(ctx context.Context, organiz
 	return cloudCred, nil
 }
 
+// SetupAWSIAMRole sets up an IAM role in another AWS account for CloudWeave to assume, with the
+// external ID the role's trust policy requires
+func (s *CloudCredentialsService) SetupAWSIAMRole(ctx context.Context, organizationID string, req models.AWSIAMRoleRequest) (*models.CloudCredentials, error) {
+	// Verify organization exists
+	_, err := s.orgRepo.GetByID(ctx, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("organization not found: %w", err)
+	}
+
+	credentials := map[string]interface{}{
+		"role_arn": req.RoleArn,
+		"region":   req.Region,
+		"type":     "iam_role",
+	}
+	if req.ExternalID != "" {
+		credentials["external_id"] = req.ExternalID
+	}
+
+	cloudCred := &models.CloudCredentials{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		Provider:       models.ProviderAWS,
+		CredentialType: models.CredentialTypeIAMRole,
+		Credentials:    credentials,
+		IsActive:       true,
+	}
+
+	// Deactivate any existing AWS IAM role credentials for this organization
+	if err := s.credRepo.DeactivateByType(ctx, organizationID, models.ProviderAWS, models.CredentialTypeIAMRole); err != nil {
+		return nil, fmt.Errorf("failed to deactivate existing credentials: %w", err)
+	}
+
+	// Create new credentials
+	if err := s.credRepo.Create(ctx, cloudCred); err != nil {
+		return nil, fmt.Errorf("failed to create cloud credentials: %w", err)
+	}
+
+	return cloudCred, nil
+}
+
 // GetActiveAWSCredentials gets the active AWS credentials for an organization
 func (s *CloudCredentialsService) GetActiveAWSCredentials(ctx context.Context, organizationID string) (*models.CloudCredentials, error) {
 	return s.credRepo.GetActiveByProvider(ctx, organizationID, models.ProviderAWS)
@@ -139,15 +179,17 @@ func (s *CloudCredentialsService) TestAWSConnection(ctx context.Context, organiz
 		// This is a simplified version - in production you'd want proper AWS STS integration
 		return fmt.Errorf("root credential testing not yet implemented - please use access keys for now")
 		
-	case models.CredentialTypeAccessKey:
-		// Test with access keys - simplified for now
-		// In production, you would create an AWS session and test it
+	case models.CredentialTypeAccessKey, models.CredentialTypeIAMRole:
+		validation, err := NewCloudCredentialsValidator().Validate(ctx, models.ProviderAWS, cred.CredentialType, cred.Credentials)
+		if err != nil {
+			return fmt.Errorf("AWS connection test failed: %w", err)
+		}
+		if !validation.Valid {
+			return fmt.Errorf("AWS connection test failed: %s", validation.Message)
+		}
 		return nil
-		
+
 	default:
 		return fmt.Errorf("unsupported credential type: %s", cred.CredentialType)
 	}
-
-	// Connection test successful
-	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,430 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/sql/armsql"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatch"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	"google.golang.org/api/cloudresourcemanager/v3"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+// credentialValidationTimeout bounds the total time spent probing a provider
+const credentialValidationTimeout = 30 * time.Second
+
+// awsProbeImageID is the image the RunInstances dry run launches: the public SSM parameter for the
+// latest Amazon Linux 2023 AMI, which EC2 resolves to the AMI of whichever region is probed
+const awsProbeImageID = "resolve:ssm:/aws/service/ami-amazon-linux-latest/al2023-ami-kernel-default-x86_64"
+
+// probeOutcome is the result of a single permission probe
+type probeOutcome int
+
+const (
+	probeGranted probeOutcome = iota
+	probeDenied
+	probeUnverified
+)
+
+// permissionProbe describes one provider API call used to check a permission CloudWeave needs
+type permissionProbe struct {
+	permission string
+	service    string
+	limitation string
+	run        func(ctx context.Context) error
+}
+
+// CloudCredentialsValidator tests cloud credentials against the provider APIs and reports
+// which regions, services and permissions they grant
+type CloudCredentialsValidator struct{}
+
+// NewCloudCredentialsValidator creates a new cloud credentials validator
+func NewCloudCredentialsValidator() *CloudCredentialsValidator {
+	return &CloudCredentialsValidator{}
+}
+
+// Validate connects to the provider with the given credentials. An error is returned when the
+// credentials are malformed or rejected; missing permissions are reported as limitations.
+func (v *CloudCredentialsValidator) Validate(ctx context.Context, provider, credentialType string, creds map[string]interface{}) (*models.CloudCredentialsValidation, error) {
+	ctx, cancel := context.WithTimeout(ctx, credentialValidationTimeout)
+	defer cancel()
+
+	switch provider {
+	case models.ProviderAWS:
+		return v.validateAWS(ctx, credentialType, creds)
+	case models.ProviderAzure:
+		return v.validateAzure(ctx, creds)
+	case models.ProviderGCP:
+		return v.validateGCP(ctx, creds)
+	default:
+		return nil, fmt.Errorf("unsupported cloud provider: %s", provider)
+	}
+}
+
+// validateAWS checks AWS access keys, or an IAM role assumed with the platform's own
+// credentials, using DescribeRegions and EC2 dry-run calls
+func (v *CloudCredentialsValidator) validateAWS(ctx context.Context, credentialType string, creds map[string]interface{}) (*models.CloudCredentialsValidation, error) {
+	if credentialType != models.CredentialTypeAccessKey && credentialType != models.CredentialTypeIAMRole {
+		return nil, fmt.Errorf("credential type %s cannot be tested, use access keys or an IAM role", credentialType)
+	}
+
+	region := credentialString(creds, "region")
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	base, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	credentialsProvider, err := awsCredentialsProvider(base, "cloudweave-validation", credentialType, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := base.Copy()
+	cfg.Credentials = credentialsProvider
+
+	ec2Client := ec2.NewFromConfig(cfg)
+	rdsClient := rds.NewFromConfig(cfg)
+	s3Client := s3.NewFromConfig(cfg)
+	cwClient := cloudwatch.NewFromConfig(cfg)
+
+	// DescribeRegions is allowed for any authenticated principal, so a failure here means
+	// the keys themselves were rejected
+	regionsOut, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, fmt.Errorf("AWS rejected the credentials: %w", err)
+	}
+
+	result := newCloudCredentialsValidation()
+	for _, r := range regionsOut.Regions {
+		result.Regions = append(result.Regions, aws.ToString(r.RegionName))
+	}
+
+	probes := []permissionProbe{
+		{
+			permission: "ec2:DescribeInstances",
+			service:    "ec2",
+			limitation: "Cannot list EC2 instances; server resources cannot be synced",
+			run: func(ctx context.Context) error {
+				_, err := ec2Client.DescribeInstances(ctx, &ec2.DescribeInstancesInput{DryRun: aws.Bool(true)})
+				return err
+			},
+		},
+		{
+			permission: "ec2:RunInstances",
+			service:    "ec2",
+			limitation: "Cannot create EC2 instances; server provisioning is unavailable",
+			run: func(ctx context.Context) error {
+				_, err := ec2Client.RunInstances(ctx, &ec2.RunInstancesInput{
+					DryRun:       aws.Bool(true),
+					ImageId:      aws.String(awsProbeImageID),
+					InstanceType: ec2types.InstanceTypeT3Micro,
+					MinCount:     aws.Int32(1),
+					MaxCount:     aws.Int32(1),
+				})
+				return err
+			},
+		},
+		{
+			permission: "rds:DescribeDBInstances",
+			service:    "rds",
+			limitation: "Cannot list RDS instances; database resources cannot be synced",
+			run: func(ctx context.Context) error {
+				_, err := rdsClient.DescribeDBInstances(ctx, &rds.DescribeDBInstancesInput{MaxRecords: aws.Int32(20)})
+				return err
+			},
+		},
+		{
+			permission: "s3:ListAllMyBuckets",
+			service:    "s3",
+			limitation: "Cannot list S3 buckets; storage resources cannot be synced",
+			run: func(ctx context.Context) error {
+				_, err := s3Client.ListBuckets(ctx, &s3.ListBucketsInput{})
+				return err
+			},
+		},
+		{
+			permission: "cloudwatch:ListMetrics",
+			service:    "cloudwatch",
+			limitation: "Cannot read CloudWatch metrics; monitoring data will be unavailable",
+			run: func(ctx context.Context) error {
+				_, err := cwClient.ListMetrics(ctx, &cloudwatch.ListMetricsInput{Namespace: aws.String("AWS/EC2")})
+				return err
+			},
+		},
+	}
+
+	runPermissionProbes(ctx, result, probes, classifyAWSProbeError)
+	result.Message = "Connection successful"
+	return result, nil
+}
+
+// classifyAWSProbeError interprets dry-run and access denied responses from AWS
+func classifyAWSProbeError(err error) probeOutcome {
+	if err == nil {
+		return probeGranted
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		code := apiErr.ErrorCode()
+		switch {
+		case code == "DryRunOperation":
+			return probeGranted
+		case code == "UnauthorizedOperation", strings.Contains(code, "AccessDenied"), code == "AuthorizationError":
+			return probeDenied
+		}
+	}
+
+	return probeUnverified
+}
+
+// validateAzure checks a service principal against the subscription it was configured for
+func (v *CloudCredentialsValidator) validateAzure(ctx context.Context, creds map[string]interface{}) (*models.CloudCredentialsValidation, error) {
+	tenantID := credentialString(creds, "tenantId", "tenant_id")
+	clientID := credentialString(creds, "clientId", "client_id")
+	clientSecret := credentialString(creds, "clientSecret", "client_secret")
+	subscriptionID := credentialString(creds, "subscriptionId", "subscription_id")
+	if tenantID == "" || clientID == "" || clientSecret == "" || subscriptionID == "" {
+		return nil, fmt.Errorf("tenantId, clientId, clientSecret and subscriptionId are required")
+	}
+
+	credential, err := azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+
+	resourceGroupsClient, err := armresources.NewResourceGroupsClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource group client: %w", err)
+	}
+	providersClient, err := armresources.NewProvidersClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create providers client: %w", err)
+	}
+	vmClient, err := armcompute.NewVirtualMachinesClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create VM client: %w", err)
+	}
+	sqlClient, err := armsql.NewServersClient(subscriptionID, credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SQL client: %w", err)
+	}
+
+	// Listing resource groups requires a valid token scoped to the subscription
+	if _, err := resourceGroupsClient.NewListPager(nil).NextPage(ctx); err != nil {
+		return nil, fmt.Errorf("Azure rejected the credentials: %w", err)
+	}
+
+	result := newCloudCredentialsValidation()
+	result.Permissions = append(result.Permissions, "Microsoft.Resources/subscriptions/resourceGroups/read")
+
+	// Report resource providers that are not registered, and take regions from where VMs can run
+	for _, namespace := range []string{"Microsoft.Compute", "Microsoft.Network", "Microsoft.Sql", "Microsoft.Storage"} {
+		resp, err := providersClient.Get(ctx, namespace, nil)
+		if err != nil {
+			result.Limitations = append(result.Limitations, fmt.Sprintf("Unable to read resource provider %s: %v", namespace, err))
+			continue
+		}
+
+		if resp.RegistrationState == nil || *resp.RegistrationState != "Registered" {
+			result.Limitations = append(result.Limitations, fmt.Sprintf("Resource provider %s is not registered in this subscription", namespace))
+			continue
+		}
+		result.Services = appendUnique(result.Services, namespace)
+
+		if namespace == "Microsoft.Compute" {
+			result.Regions = append(result.Regions, azureVirtualMachineLocations(resp.Provider)...)
+		}
+	}
+
+	probes := []permissionProbe{
+		{
+			permission: "Microsoft.Compute/virtualMachines/read",
+			limitation: "Cannot list virtual machines; server resources cannot be synced",
+			run: func(ctx context.Context) error {
+				_, err := vmClient.NewListAllPager(nil).NextPage(ctx)
+				return err
+			},
+		},
+		{
+			permission: "Microsoft.Sql/servers/read",
+			limitation: "Cannot list SQL servers; database resources cannot be synced",
+			run: func(ctx context.Context) error {
+				_, err := sqlClient.NewListPager(nil).NextPage(ctx)
+				return err
+			},
+		},
+	}
+
+	runPermissionProbes(ctx, result, probes, classifyAzureProbeError)
+	result.Message = "Connection successful"
+	return result, nil
+}
+
+// classifyAzureProbeError treats 401/403 responses from ARM as missing permissions
+func classifyAzureProbeError(err error) probeOutcome {
+	if err == nil {
+		return probeGranted
+	}
+
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) {
+		if respErr.StatusCode == http.StatusForbidden || respErr.StatusCode == http.StatusUnauthorized {
+			return probeDenied
+		}
+	}
+
+	return probeUnverified
+}
+
+// gcpRequiredPermissions are the IAM permissions CloudWeave uses in a GCP project
+var gcpRequiredPermissions = map[string]string{
+	"compute.instances.list":     "Cannot list Compute Engine instances; server resources cannot be synced",
+	"compute.instances.create":   "Cannot create Compute Engine instances; server provisioning is unavailable",
+	"storage.buckets.list":       "Cannot list Cloud Storage buckets; storage resources cannot be synced",
+	"storage.buckets.create":     "Cannot create Cloud Storage buckets; storage provisioning is unavailable",
+	"cloudsql.instances.list":    "Cannot list Cloud SQL instances; database resources cannot be synced",
+	"monitoring.timeSeries.list": "Cannot read Cloud Monitoring metrics; monitoring data will be unavailable",
+}
+
+// validateGCP checks a service account key using the Compute regions list and IAM TestIamPermissions
+func (v *CloudCredentialsValidator) validateGCP(ctx context.Context, creds map[string]interface{}) (*models.CloudCredentialsValidation, error) {
+	projectID := credentialString(creds, "projectId", "project_id")
+	serviceAccountKey := credentialString(creds, "serviceAccountKey", "service_account_key")
+	if projectID == "" || serviceAccountKey == "" {
+		return nil, fmt.Errorf("projectId and serviceAccountKey are required")
+	}
+
+	clientOption := option.WithCredentialsJSON([]byte(serviceAccountKey))
+
+	computeService, err := compute.NewService(ctx, clientOption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute client: %w", err)
+	}
+
+	regions, err := computeService.Regions.List(projectID).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("GCP rejected the credentials: %w", err)
+	}
+
+	result := newCloudCredentialsValidation()
+	for _, region := range regions.Items {
+		result.Regions = append(result.Regions, region.Name)
+	}
+
+	requested := make([]string, 0, len(gcpRequiredPermissions))
+	for permission := range gcpRequiredPermissions {
+		requested = append(requested, permission)
+	}
+	sort.Strings(requested)
+
+	rmService, err := cloudresourcemanager.NewService(ctx, clientOption)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource manager client: %w", err)
+	}
+
+	resp, err := rmService.Projects.TestIamPermissions("projects/"+projectID, &cloudresourcemanager.TestIamPermissionsRequest{
+		Permissions: requested,
+	}).Context(ctx).Do()
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden {
+			result.Limitations = append(result.Limitations, "Cloud Resource Manager API is disabled or inaccessible; permissions could not be verified")
+		} else {
+			result.Limitations = append(result.Limitations, fmt.Sprintf("Unable to verify permissions: %v", err))
+		}
+		result.Services = appendUnique(result.Services, "compute")
+		result.Message = "Connection successful"
+		return result, nil
+	}
+
+	granted := make(map[string]bool, len(resp.Permissions))
+	for _, permission := range resp.Permissions {
+		granted[permission] = true
+	}
+
+	for _, permission := range requested {
+		if !granted[permission] {
+			result.Limitations = append(result.Limitations, gcpRequiredPermissions[permission])
+			continue
+		}
+		result.Permissions = append(result.Permissions, permission)
+		result.Services = appendUnique(result.Services, strings.SplitN(permission, ".", 2)[0])
+	}
+
+	result.Message = "Connection successful"
+	return result, nil
+}
+
+// runPermissionProbes executes each probe and records the granted permissions, reachable
+// services and limitations on the result
+func runPermissionProbes(ctx context.Context, result *models.CloudCredentialsValidation, probes []permissionProbe, classify func(error) probeOutcome) {
+	for _, probe := range probes {
+		err := probe.run(ctx)
+		switch classify(err) {
+		case probeGranted:
+			result.Permissions = append(result.Permissions, probe.permission)
+			if probe.service != "" {
+				result.Services = appendUnique(result.Services, probe.service)
+			}
+		case probeDenied:
+			result.Limitations = append(result.Limitations, fmt.Sprintf("%s (%s denied)", probe.limitation, probe.permission))
+		default:
+			result.Limitations = append(result.Limitations, fmt.Sprintf("Unable to verify %s: %v", probe.permission, err))
+		}
+	}
+}
+
+// newCloudCredentialsValidation returns a successful result with empty, non-nil lists
+func newCloudCredentialsValidation() *models.CloudCredentialsValidation {
+	return &models.CloudCredentialsValidation{
+		Valid:       true,
+		Permissions: []string{},
+		Limitations: []string{},
+		Regions:     []string{},
+		Services:    []string{},
+	}
+}
+
+// credentialString returns the first non-empty string value found under the given keys
+func credentialString(creds map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := creds[key].(string); ok && value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// appendUnique appends value to values if it is not already present
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
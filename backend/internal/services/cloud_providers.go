@@ -10,7 +10,9 @@ import (
 )
 
 // AWSProvider implements CloudProvider for Amazon Web Services
-type AWSProvider struct{}
+type AWSProvider struct {
+	orphanDetectionUnsupported
+}
 
 func NewAWSProvider() *AWSProvider {
 	return &AWSProvider{}
@@ -27,6 +29,11 @@ func (p *AWSProvider) CreateResource(ctx context.Context, infra *models.Infrastr
 	return externalID, nil
 }
 
+func (p *AWSProvider) ValidateCreate(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	// Simulate an AWS dry run
+	return newCreatePlan(infra, infra.Name, infra.Specifications, 0.0416), nil
+}
+
 func (p *AWSProvider) GetResourceStatus(ctx context.Context, externalID string) (string, error) {
 	// Simulate status check
 	statuses := []string{models.InfraStatusRunning, models.InfraStatusStopped, models.InfraStatusPending}
@@ -56,11 +63,7 @@ func (p *AWSProvider) GetResourceDetails(ctx context.Context, externalID string)
 			"subnet_id":       "subnet-87654321",
 			"security_groups": []string{"sg-11111111", "sg-22222222"},
 		},
-		"costInfo": map[string]interface{}{
-			"hourly_cost":  0.0416,
-			"monthly_cost": 30.0,
-			"currency":     "USD",
-		},
+		"costInfo": fixedCostInfo(0.0416),
 	}, nil
 }
 
@@ -71,7 +74,9 @@ func (p *AWSProvider) DeleteResource(ctx context.Context, externalID string) err
 }
 
 // GCPProvider implements CloudProvider for Google Cloud Platform
-type GCPProvider struct{}
+type GCPProvider struct {
+	orphanDetectionUnsupported
+}
 
 func NewGCPProvider() *GCPProvider {
 	return &GCPProvider{}
@@ -84,6 +89,10 @@ func (p *GCPProvider) CreateResource(ctx context.Context, infra *models.Infrastr
 	return externalID, nil
 }
 
+func (p *GCPProvider) ValidateCreate(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	return newCreatePlan(infra, infra.Name, infra.Specifications, 0.0335), nil
+}
+
 func (p *GCPProvider) GetResourceStatus(ctx context.Context, externalID string) (string, error) {
 	statuses := []string{models.InfraStatusRunning, models.InfraStatusStopped, models.InfraStatusPending}
 	return statuses[rand.Intn(len(statuses))], nil
@@ -111,11 +120,7 @@ func (p *GCPProvider) GetResourceDetails(ctx context.Context, externalID string)
 			"network":      "default",
 			"subnetwork":   "default",
 		},
-		"costInfo": map[string]interface{}{
-			"hourly_cost":  0.0335,
-			"monthly_cost": 24.21,
-			"currency":     "USD",
-		},
+		"costInfo": fixedCostInfo(0.0335),
 	}, nil
 }
 
@@ -125,7 +130,9 @@ func (p *GCPProvider) DeleteResource(ctx context.Context, externalID string) err
 }
 
 // AzureProvider implements CloudProvider for Microsoft Azure
-type AzureProvider struct{}
+type AzureProvider struct {
+	orphanDetectionUnsupported
+}
 
 func NewAzureProvider() *AzureProvider {
 	return &AzureProvider{}
@@ -138,6 +145,10 @@ func (p *AzureProvider) CreateResource(ctx context.Context, infra *models.Infras
 	return externalID, nil
 }
 
+func (p *AzureProvider) ValidateCreate(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	return newCreatePlan(infra, infra.Name, infra.Specifications, 0.0416), nil
+}
+
 func (p *AzureProvider) GetResourceStatus(ctx context.Context, externalID string) (string, error) {
 	statuses := []string{models.InfraStatusRunning, models.InfraStatusStopped, models.InfraStatusPending}
 	return statuses[rand.Intn(len(statuses))], nil
@@ -166,11 +177,7 @@ func (p *AzureProvider) GetResourceDetails(ctx context.Context, externalID strin
 			"subnet":                 "subnet-default",
 			"network_security_group": "nsg-cloudweave",
 		},
-		"costInfo": map[string]interface{}{
-			"hourly_cost":  0.0416,
-			"monthly_cost": 30.0,
-			"currency":     "USD",
-		},
+		"costInfo": fixedCostInfo(0.0416),
 	}, nil
 }
 
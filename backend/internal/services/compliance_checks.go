@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// Data sources an automated control's check query can evaluate. A check query has the form
+// "<source>.<field> <operator> <value>", for example:
+//
+//	infrastructure.encryption.enabled == true
+//	vulnerabilities.critical == 0
+//	security_scans.days_since_last <= 7
+const (
+	checkSourceInfrastructure  = "infrastructure"
+	checkSourceVulnerabilities = "vulnerabilities"
+	checkSourceSecurityScans   = "security_scans"
+)
+
+// checkOperators lists the supported comparison operators, longest first so parsing prefers ">=" over ">"
+var checkOperators = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// controlCheck is a parsed check query
+type controlCheck struct {
+	source   string
+	field    string
+	operator string
+	value    string
+}
+
+// controlCheckResult is the outcome of running a control's check
+type controlCheckResult struct {
+	status   models.ComplianceControlStatus
+	evidence []string
+}
+
+// parseControlCheck parses a check query
+func parseControlCheck(query string) (*controlCheck, error) {
+	query = strings.TrimSpace(query)
+	for _, operator := range checkOperators {
+		index := strings.Index(query, " "+operator+" ")
+		if index < 0 {
+			continue
+		}
+
+		path := strings.TrimSpace(query[:index])
+		value := strings.Trim(strings.TrimSpace(query[index+len(operator)+2:]), `"'`)
+
+		source, field, ok := strings.Cut(path, ".")
+		if !ok || field == "" {
+			return nil, fmt.Errorf("check query %q must reference <source>.<field>", query)
+		}
+
+		switch source {
+		case checkSourceInfrastructure, checkSourceVulnerabilities, checkSourceSecurityScans:
+		default:
+			return nil, fmt.Errorf("unknown check source %q", source)
+		}
+
+		return &controlCheck{source: source, field: field, operator: operator, value: value}, nil
+	}
+
+	return nil, fmt.Errorf("check query %q has no supported operator", query)
+}
+
+// runControlCheck evaluates an automated control's check query against the organization's data
+func (s *ComplianceService) runControlCheck(ctx context.Context, organizationID string, control *models.ComplianceControl) (*controlCheckResult, error) {
+	if control.CheckQuery == nil || strings.TrimSpace(*control.CheckQuery) == "" {
+		return &controlCheckResult{
+			status:   models.ControlStatusWarning,
+			evidence: []string{"Automated control has no check query"},
+		}, nil
+	}
+
+	check, err := parseControlCheck(*control.CheckQuery)
+	if err != nil {
+		return &controlCheckResult{
+			status:   models.ControlStatusWarning,
+			evidence: []string{fmt.Sprintf("Invalid check query: %v", err)},
+		}, nil
+	}
+
+	switch check.source {
+	case checkSourceInfrastructure:
+		return s.checkInfrastructure(ctx, organizationID, check)
+	case checkSourceVulnerabilities:
+		return s.checkVulnerabilities(ctx, organizationID, check)
+	default:
+		return s.checkSecurityScans(ctx, organizationID, check)
+	}
+}
+
+// checkInfrastructure compares a specification value on every infrastructure resource. The control
+// passes when every resource matches, warns when only some do and fails when none do.
+func (s *ComplianceService) checkInfrastructure(ctx context.Context, organizationID string, check *controlCheck) (*controlCheckResult, error) {
+	params := repositories.DefaultListParams()
+	var total int
+	var failing []string
+
+	for {
+		resources, err := s.infraRepo.List(ctx, organizationID, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list infrastructure: %w", err)
+		}
+
+		for _, resource := range resources {
+			total++
+			actual, ok := lookupConfigValue(resource.Specifications, check.field)
+			if !ok || !compareCheckValue(actual, check.operator, check.value) {
+				failing = append(failing, resource.Name)
+			}
+		}
+
+		if len(resources) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+
+	if total == 0 {
+		return &controlCheckResult{
+			status:   models.ControlStatusWarning,
+			evidence: []string{"No infrastructure resources to evaluate"},
+		}, nil
+	}
+
+	evidence := []string{fmt.Sprintf("%d of %d resources satisfy %s %s %s", total-len(failing), total, check.field, check.operator, check.value)}
+	for _, name := range failing {
+		evidence = append(evidence, fmt.Sprintf("Non-compliant resource: %s", name))
+	}
+
+	switch len(failing) {
+	case 0:
+		return &controlCheckResult{status: models.ControlStatusPassed, evidence: evidence}, nil
+	case total:
+		return &controlCheckResult{status: models.ControlStatusFailed, evidence: evidence}, nil
+	default:
+		return &controlCheckResult{status: models.ControlStatusWarning, evidence: evidence}, nil
+	}
+}
+
+// checkVulnerabilities compares the number of open vulnerabilities, optionally of one severity
+// ("vulnerabilities.open" counts every severity)
+func (s *ComplianceService) checkVulnerabilities(ctx context.Context, organizationID string, check *controlCheck) (*controlCheckResult, error) {
+	status := models.VulnStatusOpen
+	query := models.VulnerabilityQuery{Status: &status, Limit: 1}
+
+	if check.field != "open" {
+		severity := models.VulnerabilitySeverity(check.field)
+		switch severity {
+		case models.VulnSeverityCritical, models.VulnSeverityHigh, models.VulnSeverityMedium, models.VulnSeverityLow, models.VulnSeverityInfo:
+		default:
+			return &controlCheckResult{
+				status:   models.ControlStatusWarning,
+				evidence: []string{fmt.Sprintf("Invalid check query: unknown vulnerability severity %q", check.field)},
+			}, nil
+		}
+		query.Severity = &severity
+	}
+
+	_, count, err := s.vulnRepo.Query(ctx, organizationID, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count vulnerabilities: %w", err)
+	}
+
+	description := "open"
+	if query.Severity != nil {
+		description = fmt.Sprintf("open %s", check.field)
+	}
+
+	result := &controlCheckResult{
+		status:   models.ControlStatusFailed,
+		evidence: []string{fmt.Sprintf("%d %s vulnerabilities", count, description)},
+	}
+	if compareCheckValue(count, check.operator, check.value) {
+		result.status = models.ControlStatusPassed
+	}
+	return result, nil
+}
+
+// checkSecurityScans compares how many days ago the most recent security scan completed
+func (s *ComplianceService) checkSecurityScans(ctx context.Context, organizationID string, check *controlCheck) (*controlCheckResult, error) {
+	if check.field != "days_since_last" {
+		return &controlCheckResult{
+			status:   models.ControlStatusWarning,
+			evidence: []string{fmt.Sprintf("Invalid check query: unknown security scan field %q", check.field)},
+		}, nil
+	}
+
+	scans, _, err := s.scanRepo.List(ctx, organizationID, 50, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list security scans: %w", err)
+	}
+
+	var lastCompleted *time.Time
+	for _, scan := range scans {
+		if scan.Status != models.ScanStatusCompleted || scan.CompletedAt == nil {
+			continue
+		}
+		if lastCompleted == nil || scan.CompletedAt.After(*lastCompleted) {
+			lastCompleted = scan.CompletedAt
+		}
+	}
+
+	if lastCompleted == nil {
+		return &controlCheckResult{
+			status:   models.ControlStatusFailed,
+			evidence: []string{"No completed security scans found"},
+		}, nil
+	}
+
+	days := time.Since(*lastCompleted).Hours() / 24
+	result := &controlCheckResult{
+		status:   models.ControlStatusFailed,
+		evidence: []string{fmt.Sprintf("Last security scan completed %s (%.1f days ago)", lastCompleted.Format(time.RFC3339), days)},
+	}
+	if compareCheckValue(days, check.operator, check.value) {
+		result.status = models.ControlStatusPassed
+	}
+	return result, nil
+}
+
+// lookupConfigValue reads a dot-separated path from nested configuration
+func lookupConfigValue(config map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = config
+	for _, key := range strings.Split(path, ".") {
+		values, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = values[key]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// compareCheckValue compares an actual value with the expected value from a check query. Numbers
+// are compared numerically; anything else only supports equality.
+func compareCheckValue(actual interface{}, operator, expected string) bool {
+	if actualNumber, ok := checkNumber(actual); ok {
+		if expectedNumber, err := strconv.ParseFloat(expected, 64); err == nil {
+			switch operator {
+			case "==":
+				return actualNumber == expectedNumber
+			case "!=":
+				return actualNumber != expectedNumber
+			case ">":
+				return actualNumber > expectedNumber
+			case ">=":
+				return actualNumber >= expectedNumber
+			case "<":
+				return actualNumber < expectedNumber
+			case "<=":
+				return actualNumber <= expectedNumber
+			}
+		}
+	}
+
+	actualString := fmt.Sprintf("%v", actual)
+	switch operator {
+	case "==":
+		return strings.EqualFold(actualString, expected)
+	case "!=":
+		return !strings.EqualFold(actualString, expected)
+	}
+	return false
+}
+
+// checkNumber converts numeric values from configuration or counts to float64
+func checkNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		number, err := strconv.ParseFloat(v, 64)
+		return number, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,589 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrComplianceReportNotFound is returned when a report does not exist or belongs to another organization
+	ErrComplianceReportNotFound = errors.New("compliance report not found")
+
+	// ErrReportScheduleNotFound is returned when a report schedule does not exist or belongs to another organization
+	ErrReportScheduleNotFound = errors.New("compliance report schedule not found")
+
+	// ErrInvalidReportSchedule is returned when a report schedule's framework or frequency is not valid
+	ErrInvalidReportSchedule = errors.New("invalid compliance report schedule")
+
+	// ErrInvalidReportFormat is returned when a report is downloaded in an unsupported format
+	ErrInvalidReportFormat = errors.New("invalid compliance report format")
+)
+
+// Compliance report download formats
+const (
+	ComplianceReportPDF  = "pdf"
+	ComplianceReportJSON = "json"
+)
+
+// complianceReportSnapshot is the state of a framework stored with a report when it is
+// generated. Reports are rendered from it alone, so they do not change as controls do.
+type complianceReportSnapshot struct {
+	Organization string                       `json:"organization"`
+	Framework    complianceReportFramework    `json:"framework"`
+	Score        *float64                     `json:"score"`
+	Statistics   complianceReportStatistics   `json:"statistics"`
+	Controls     []complianceReportControl    `json:"controls"`
+	Assessments  []complianceReportAssessment `json:"assessments"`
+	GeneratedAt  time.Time                    `json:"generatedAt"`
+}
+
+type complianceReportFramework struct {
+	ID        string                     `json:"id"`
+	Framework models.ComplianceFramework `json:"framework"`
+	Name      string                     `json:"name"`
+	Version   string                     `json:"version"`
+}
+
+type complianceReportStatistics struct {
+	TotalControls     int                                    `json:"totalControls"`
+	StatusBreakdown   map[models.ComplianceControlStatus]int `json:"statusBreakdown"`
+	SeverityBreakdown map[models.ComplianceSeverity]int      `json:"severityBreakdown"`
+}
+
+type complianceReportControl struct {
+	ControlID      string                         `json:"controlId"`
+	Title          string                         `json:"title"`
+	Category       string                         `json:"category"`
+	Severity       models.ComplianceSeverity      `json:"severity"`
+	Status         models.ComplianceControlStatus `json:"status"`
+	AutomatedCheck bool                           `json:"automatedCheck"`
+	Evidence       []string                       `json:"evidence"`
+	Owner          *string                        `json:"owner"`
+	LastChecked    *time.Time                     `json:"lastChecked"`
+}
+
+type complianceReportAssessment struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Score          float64   `json:"score"`
+	CompletedAt    time.Time `json:"completedAt"`
+	PassedControls int       `json:"passedControls"`
+	FailedControls int       `json:"failedControls"`
+}
+
+// SetNotificationService sets the service scheduled compliance reports are emailed with
+func (s *ComplianceService) SetNotificationService(notifications *NotificationService) {
+	s.notifications = notifications
+}
+
+// Report Schedules
+
+// CreateReportSchedule schedules monthly or quarterly reports on one of the organization's
+// frameworks. The first report is generated when the current month or quarter ends.
+func (s *ComplianceService) CreateReportSchedule(ctx context.Context, orgID, userID string, req *models.CreateComplianceReportScheduleRequest) (*models.ComplianceReportSchedule, error) {
+	if _, err := s.frameworkRepo.GetByID(ctx, orgID, req.FrameworkID); err != nil {
+		return nil, fmt.Errorf("%w: framework %s not found", ErrInvalidReportSchedule, req.FrameworkID)
+	}
+
+	now := time.Now()
+	schedule := &models.ComplianceReportSchedule{
+		ID:             uuid.New().String(),
+		OrganizationID: orgID,
+		FrameworkID:    req.FrameworkID,
+		Frequency:      req.Frequency,
+		Recipients:     req.Recipients,
+		Enabled:        true,
+		NextRunAt:      nextComplianceReportRun(req.Frequency, now),
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if req.Enabled != nil {
+		schedule.Enabled = *req.Enabled
+	}
+	if userID != "" {
+		schedule.CreatedBy = &userID
+	}
+
+	if err := s.scheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	s.logAuditEvent(ctx, orgID, userID, "compliance_report_schedule_created",
+		fmt.Sprintf("Scheduled %s compliance reports", schedule.Frequency), schedule.ID)
+
+	return schedule, nil
+}
+
+// ListReportSchedules retrieves the organization's compliance report schedules
+func (s *ComplianceService) ListReportSchedules(ctx context.Context, orgID string) ([]*models.ComplianceReportSchedule, error) {
+	return s.scheduleRepo.List(ctx, orgID)
+}
+
+// UpdateReportSchedule changes a report schedule's frequency, recipients or whether it runs.
+// Changing the frequency, or enabling a schedule whose run has passed, moves its next run to the
+// end of the current period.
+func (s *ComplianceService) UpdateReportSchedule(ctx context.Context, orgID, userID, scheduleID string, req *models.UpdateComplianceReportScheduleRequest) (*models.ComplianceReportSchedule, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, orgID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, ErrReportScheduleNotFound
+	}
+
+	now := time.Now()
+	if req.Frequency != nil && *req.Frequency != schedule.Frequency {
+		schedule.Frequency = *req.Frequency
+		schedule.NextRunAt = nextComplianceReportRun(schedule.Frequency, now)
+	}
+	if req.Recipients != nil {
+		schedule.Recipients = *req.Recipients
+	}
+	if req.Enabled != nil {
+		if *req.Enabled && !schedule.Enabled && schedule.NextRunAt.Before(now) {
+			schedule.NextRunAt = nextComplianceReportRun(schedule.Frequency, now)
+		}
+		schedule.Enabled = *req.Enabled
+	}
+	schedule.UpdatedAt = now
+
+	if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+		return nil, err
+	}
+
+	s.logAuditEvent(ctx, orgID, userID, "compliance_report_schedule_updated",
+		fmt.Sprintf("Updated %s compliance report schedule", schedule.Frequency), schedule.ID)
+
+	return schedule, nil
+}
+
+// DeleteReportSchedule stops a report schedule. Reports it already generated are kept.
+func (s *ComplianceService) DeleteReportSchedule(ctx context.Context, orgID, userID, scheduleID string) error {
+	schedule, err := s.scheduleRepo.GetByID(ctx, orgID, scheduleID)
+	if err != nil {
+		return err
+	}
+	if schedule == nil {
+		return ErrReportScheduleNotFound
+	}
+
+	if err := s.scheduleRepo.Delete(ctx, orgID, scheduleID); err != nil {
+		return err
+	}
+
+	s.logAuditEvent(ctx, orgID, userID, "compliance_report_schedule_deleted",
+		fmt.Sprintf("Deleted %s compliance report schedule", schedule.Frequency), schedule.ID)
+
+	return nil
+}
+
+// nextComplianceReportRun returns the end of the calendar month or quarter containing a time, in
+// UTC, which is when the report on that period is generated
+func nextComplianceReportRun(frequency string, after time.Time) time.Time {
+	after = after.UTC()
+	month := after.Month()
+	if frequency == models.ComplianceReportQuarterly {
+		month = (month-1)/3*3 + 1
+	}
+
+	start := time.Date(after.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	return start.AddDate(0, complianceReportMonths(frequency), 0)
+}
+
+// complianceReportMonths is how many months a report of a frequency covers
+func complianceReportMonths(frequency string) int {
+	if frequency == models.ComplianceReportQuarterly {
+		return 3
+	}
+	return 1
+}
+
+// complianceReportPeriod is the month or quarter ending at a report run
+func complianceReportPeriod(frequency string, end time.Time) models.ReportPeriod {
+	return models.ReportPeriod{
+		StartDate: end.AddDate(0, -complianceReportMonths(frequency), 0),
+		EndDate:   end,
+	}
+}
+
+// complianceReportPeriodLabel names a report period, e.g. "March 2026" or "Q1 2026"
+func complianceReportPeriodLabel(frequency string, period models.ReportPeriod) string {
+	start := period.StartDate.UTC()
+	if frequency == models.ComplianceReportQuarterly {
+		return fmt.Sprintf("Q%d %d", (int(start.Month())-1)/3+1, start.Year())
+	}
+	return start.Format("January 2006")
+}
+
+// Scheduled Reports
+
+// StartComplianceReportScheduler generates and emails the reports of schedules that have come due
+// every interval until ctx is cancelled
+func (s *ComplianceService) StartComplianceReportScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RunDueReportSchedules(ctx); err != nil {
+				log.Printf("Scheduled compliance reports failed: %v", err)
+			}
+		}
+	}
+}
+
+// RunDueReportSchedules generates and emails the report of every schedule whose run has come. Each
+// run is claimed before the report is generated, so servers running the scheduler together do not
+// send a report twice. A schedule whose runs were missed while no server was running reports on the
+// period that ended at its first missed run, then waits for the end of the current period.
+func (s *ComplianceService) RunDueReportSchedules(ctx context.Context) error {
+	now := time.Now()
+	schedules, err := s.scheduleRepo.ListDue(ctx, now)
+	if err != nil {
+		return err
+	}
+
+	for _, schedule := range schedules {
+		claimed, err := s.scheduleRepo.ClaimRun(ctx, schedule.ID, schedule.NextRunAt, nextComplianceReportRun(schedule.Frequency, now))
+		if err != nil {
+			log.Printf("Failed to claim compliance report schedule %s: %v", schedule.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		period := complianceReportPeriod(schedule.Frequency, schedule.NextRunAt)
+		report, err := s.generateScheduledReport(ctx, schedule, period)
+		if err != nil {
+			log.Printf("Failed to generate compliance report for schedule %s: %v", schedule.ID, err)
+			continue
+		}
+
+		s.deliverReport(ctx, schedule, report)
+	}
+
+	return nil
+}
+
+// generateScheduledReport snapshots a schedule's framework and the assessments completed on it in
+// a period, and stores the snapshot as a report
+func (s *ComplianceService) generateScheduledReport(ctx context.Context, schedule *models.ComplianceReportSchedule, period models.ReportPeriod) (*models.ComplianceReport, error) {
+	framework, err := s.frameworkRepo.GetByID(ctx, schedule.OrganizationID, schedule.FrameworkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get framework: %w", err)
+	}
+
+	snapshot, err := s.snapshotFramework(ctx, framework, period)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := snapshotData(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	label := complianceReportPeriodLabel(schedule.Frequency, period)
+	report := &models.ComplianceReport{
+		ID:             uuid.New().String(),
+		OrganizationID: schedule.OrganizationID,
+		UserID:         schedule.CreatedBy,
+		ScheduleID:     &schedule.ID,
+		Name:           fmt.Sprintf("%s compliance report, %s", framework.Name, label),
+		Description:    fmt.Sprintf("Scheduled %s report on %s for %s", schedule.Frequency, framework.Name, label),
+		Type:           models.ComplianceReportTypeScheduled,
+		Frameworks:     []models.ComplianceFramework{framework.Framework},
+		Period:         period,
+		Status:         models.ComplianceReportStatusCompleted,
+		Data:           data,
+		GeneratedAt:    &snapshot.GeneratedAt,
+		CreatedAt:      snapshot.GeneratedAt,
+		UpdatedAt:      snapshot.GeneratedAt,
+	}
+
+	if err := s.reportRepo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// snapshotFramework records a framework's controls as they are now and the assessments of it
+// completed in a period
+func (s *ComplianceService) snapshotFramework(ctx context.Context, framework *models.ComplianceFrameworkConfig, period models.ReportPeriod) (*complianceReportSnapshot, error) {
+	snapshot := &complianceReportSnapshot{
+		Framework: complianceReportFramework{
+			ID:        framework.ID,
+			Framework: framework.Framework,
+			Name:      framework.Name,
+			Version:   framework.Version,
+		},
+		Statistics: complianceReportStatistics{
+			StatusBreakdown:   make(map[models.ComplianceControlStatus]int),
+			SeverityBreakdown: make(map[models.ComplianceSeverity]int),
+		},
+		Controls:    []complianceReportControl{},
+		Assessments: []complianceReportAssessment{},
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	if org, err := s.orgRepo.GetByID(ctx, framework.OrganizationID); err == nil && org != nil {
+		snapshot.Organization = org.Name
+	}
+
+	controls, err := s.listAllControls(ctx, framework.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(controls, func(i, j int) bool {
+		return controls[i].ControlID < controls[j].ControlID
+	})
+	for _, control := range controls {
+		snapshot.Controls = append(snapshot.Controls, complianceReportControl{
+			ControlID:      control.ControlID,
+			Title:          control.Title,
+			Category:       control.Category,
+			Severity:       control.Severity,
+			Status:         control.Status,
+			AutomatedCheck: control.AutomatedCheck,
+			Evidence:       control.Evidence,
+			Owner:          control.Owner,
+			LastChecked:    control.LastChecked,
+		})
+		snapshot.Statistics.TotalControls++
+		snapshot.Statistics.StatusBreakdown[control.Status]++
+		snapshot.Statistics.SeverityBreakdown[control.Severity]++
+	}
+
+	assessments, err := s.assessmentRepo.ListCompletedSince(ctx, framework.OrganizationID, period.StartDate, string(framework.Framework))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list completed assessments: %w", err)
+	}
+	for _, assessment := range assessments {
+		if assessment.FrameworkID != framework.ID || !assessment.CompletedAt.Before(period.EndDate) {
+			continue
+		}
+
+		entry := complianceReportAssessment{
+			ID:          assessment.ID,
+			Name:        assessment.Name,
+			Score:       assessment.Score,
+			CompletedAt: *assessment.CompletedAt,
+		}
+		if assessment.Summary != nil {
+			entry.PassedControls = assessment.Summary.PassedControls
+			entry.FailedControls = assessment.Summary.FailedControls
+		}
+		snapshot.Assessments = append(snapshot.Assessments, entry)
+
+		// Assessments are ordered by completion, so the score is the period's last
+		score := assessment.Score
+		snapshot.Score = &score
+	}
+
+	return snapshot, nil
+}
+
+// snapshotData converts a snapshot to the JSON object stored as a report's data
+func snapshotData(snapshot *complianceReportSnapshot) (map[string]interface{}, error) {
+	encoded, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report snapshot: %w", err)
+	}
+
+	var data map[string]interface{}
+	if err := json.Unmarshal(encoded, &data); err != nil {
+		return nil, fmt.Errorf("failed to encode report snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// reportSnapshot reads the snapshot stored as a report's data
+func reportSnapshot(report *models.ComplianceReport) (*complianceReportSnapshot, error) {
+	encoded, err := json.Marshal(report.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode report snapshot: %w", err)
+	}
+
+	snapshot := &complianceReportSnapshot{}
+	if err := json.Unmarshal(encoded, snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode report snapshot: %w", err)
+	}
+	return snapshot, nil
+}
+
+// deliverReport emails a report as a PDF to its schedule's recipients and records the outcome.
+// Failed deliveries are not retried; the report can still be downloaded.
+func (s *ComplianceService) deliverReport(ctx context.Context, schedule *models.ComplianceReportSchedule, report *models.ComplianceReport) {
+	err := s.emailReport(schedule.Recipients, report)
+
+	var deliveredAt *time.Time
+	var deliveryError *string
+	if err != nil {
+		message := err.Error()
+		deliveryError = &message
+		log.Printf("Failed to email compliance report %s: %v", report.ID, err)
+	} else {
+		now := time.Now()
+		deliveredAt = &now
+	}
+
+	if err := s.reportRepo.UpdateDelivery(ctx, report.ID, deliveredAt, deliveryError); err != nil {
+		log.Printf("Failed to record delivery of compliance report %s: %v", report.ID, err)
+	}
+}
+
+// emailReport sends a report summary with the report attached as a PDF
+func (s *ComplianceService) emailReport(recipients []string, report *models.ComplianceReport) error {
+	if s.notifications == nil {
+		return fmt.Errorf("notifications are not configured")
+	}
+
+	snapshot, err := reportSnapshot(report)
+	if err != nil {
+		return err
+	}
+
+	file, err := s.RenderReport(report, ComplianceReportPDF)
+	if err != nil {
+		return err
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s\r\n\r\n", report.Description)
+	if snapshot.Score != nil {
+		fmt.Fprintf(&body, "Score: %.2f%%\r\n", *snapshot.Score)
+	} else {
+		body.WriteString("Score: no assessments completed in this period\r\n")
+	}
+	fmt.Fprintf(&body, "Controls: %d\r\n", snapshot.Statistics.TotalControls)
+	for _, status := range sortedKeys(snapshot.Statistics.StatusBreakdown) {
+		fmt.Fprintf(&body, "  %s: %d\r\n", status, snapshot.Statistics.StatusBreakdown[status])
+	}
+	fmt.Fprintf(&body, "Assessments completed: %d\r\n\r\n", len(snapshot.Assessments))
+	body.WriteString("The full report is attached, and can be downloaded again from CloudWeave.\r\n")
+
+	return s.notifications.SendEmailWithAttachments(recipients, report.Name, body.String(), EmailAttachment{
+		Filename:    file.Filename,
+		ContentType: file.ContentType,
+		Content:     file.Content,
+	})
+}
+
+// Reports
+
+// ListReports retrieves the organization's generated compliance reports, newest first, without
+// their data
+func (s *ComplianceService) ListReports(ctx context.Context, orgID string, limit, offset int) ([]*models.ComplianceReport, int, error) {
+	return s.reportRepo.List(ctx, orgID, limit, offset)
+}
+
+// GetReport retrieves one of the organization's compliance reports with the state it captured
+func (s *ComplianceService) GetReport(ctx context.Context, orgID, reportID string) (*models.ComplianceReport, error) {
+	report, err := s.reportRepo.GetByID(ctx, orgID, reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, ErrComplianceReportNotFound
+	}
+	return report, nil
+}
+
+// RenderReport renders a report from the snapshot it stored, as a PDF or as JSON
+func (s *ComplianceService) RenderReport(report *models.ComplianceReport, format string) (*JobFile, error) {
+	filename := fmt.Sprintf("compliance_report_%s_%s.%s",
+		strings.Join(frameworkNames(report.Frameworks), "_"), report.Period.StartDate.UTC().Format("2006-01-02"), format)
+
+	switch format {
+	case ComplianceReportJSON:
+		content, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode report: %w", err)
+		}
+		return &JobFile{Filename: filename, ContentType: "application/json", Content: content}, nil
+	case ComplianceReportPDF:
+		content, err := renderComplianceReportPDF(report)
+		if err != nil {
+			return nil, err
+		}
+		return &JobFile{Filename: filename, ContentType: "application/pdf", Content: content}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrInvalidReportFormat, format)
+	}
+}
+
+// renderComplianceReportPDF renders a report's controls as a PDF table headed with its framework,
+// period and score
+func renderComplianceReportPDF(report *models.ComplianceReport) ([]byte, error) {
+	snapshot, err := reportSnapshot(report)
+	if err != nil {
+		return nil, err
+	}
+
+	score := "Score: no assessments completed in this period"
+	if snapshot.Score != nil {
+		score = fmt.Sprintf("Score: %.2f%% from %d assessments", *snapshot.Score, len(snapshot.Assessments))
+	}
+
+	var buf bytes.Buffer
+	pdf := newPDFReport(&buf, []string{
+		snapshot.Organization,
+		report.Name,
+		fmt.Sprintf("%s to %s, generated %s",
+			report.Period.StartDate.UTC().Format("2006-01-02"),
+			report.Period.EndDate.UTC().Add(-time.Second).Format("2006-01-02"),
+			snapshot.GeneratedAt.Format(time.RFC3339)),
+		score,
+	}, []string{"Control", "Title", "Category", "Severity", "Status", "Last checked"}, []float64{40, 110, 300, 390, 450, 510})
+
+	if err := pdf.begin(); err != nil {
+		return nil, err
+	}
+	for _, control := range snapshot.Controls {
+		lastChecked := "never"
+		if control.LastChecked != nil {
+			lastChecked = control.LastChecked.UTC().Format("2006-01-02")
+		}
+		err := pdf.addRow([]string{
+			control.ControlID,
+			control.Title,
+			control.Category,
+			string(control.Severity),
+			string(control.Status),
+			lastChecked,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := pdf.finish(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// frameworkNames converts frameworks to their names
+func frameworkNames(frameworks []models.ComplianceFramework) []string {
+	names := make([]string, len(frameworks))
+	for i, framework := range frameworks {
+		names[i] = string(framework)
+	}
+	return names
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrUnknownComplianceTemplate is returned when seeding controls from a template that does not exist
+var ErrUnknownComplianceTemplate = errors.New("unknown compliance template")
+
+// ComplianceControlTemplate describes a standard control in a framework's catalog
+type ComplianceControlTemplate struct {
+	ControlID   string
+	Title       string
+	Description string
+	Category    string
+	Severity    models.ComplianceSeverity
+	Remediation string
+	// CheckQuery makes the control automated; see compliance_checks.go for the query format
+	CheckQuery string
+}
+
+// complianceTemplates holds the standard control catalog for each supported framework
+var complianceTemplates = map[models.ComplianceFramework][]ComplianceControlTemplate{
+	models.FrameworkSOC2: {
+		{ControlID: "CC1.1", Title: "Commitment to integrity and ethical values", Category: "Control Environment", Severity: models.ComplianceSeverityMedium,
+			Description: "The entity demonstrates a commitment to integrity and ethical values.",
+			Remediation: "Publish a code of conduct and require employees to acknowledge it annually."},
+		{ControlID: "CC2.1", Title: "Quality information for internal control", Category: "Communication and Information", Severity: models.ComplianceSeverityLow,
+			Description: "The entity obtains or generates and uses relevant, quality information to support the functioning of internal control.",
+			Remediation: "Maintain an inventory of systems and data flows that support internal control."},
+		{ControlID: "CC3.1", Title: "Risk assessment objectives", Category: "Risk Assessment", Severity: models.ComplianceSeverityMedium,
+			Description: "The entity specifies objectives with sufficient clarity to enable the identification and assessment of risks.",
+			Remediation: "Perform and document an annual risk assessment with defined objectives."},
+		{ControlID: "CC4.1", Title: "Ongoing monitoring of controls", Category: "Monitoring Activities", Severity: models.ComplianceSeverityMedium,
+			Description: "The entity selects, develops, and performs ongoing evaluations to ascertain whether controls are present and functioning.",
+			Remediation: "Schedule recurring compliance assessments and review their results."},
+		{ControlID: "CC5.1", Title: "Control activities to mitigate risk", Category: "Control Activities", Severity: models.ComplianceSeverityMedium,
+			Description: "The entity selects and develops control activities that contribute to the mitigation of risks to acceptable levels.",
+			Remediation: "Map identified risks to the controls that mitigate them."},
+		{ControlID: "CC6.1", Title: "Logical access security", Category: "Logical and Physical Access", Severity: models.ComplianceSeverityHigh,
+			Description: "The entity implements logical access security software, infrastructure, and architectures over protected information assets.",
+			Remediation: "Enforce role-based access control and multi-factor authentication for all users."},
+		{ControlID: "CC6.2", Title: "User registration and authorization", Category: "Logical and Physical Access", Severity: models.ComplianceSeverityHigh,
+			Description: "Prior to issuing system credentials, the entity registers and authorizes new internal and external users.",
+			Remediation: "Require documented approval before granting access and review access quarterly."},
+		{ControlID: "CC6.7", Title: "Encryption of data in transit and at rest", Category: "Logical and Physical Access", Severity: models.ComplianceSeverityCritical,
+			Description: "The entity restricts the transmission, movement, and removal of information and protects it during transmission and storage.",
+			Remediation: "Enable encryption on all storage and database resources.",
+			CheckQuery:  "infrastructure.encryption.enabled == true"},
+		{ControlID: "CC7.1", Title: "Vulnerability detection", Category: "System Operations", Severity: models.ComplianceSeverityCritical,
+			Description: "The entity uses detection and monitoring procedures to identify changes that introduce new vulnerabilities.",
+			Remediation: "Remediate all open critical vulnerabilities.",
+			CheckQuery:  "vulnerabilities.critical == 0"},
+		{ControlID: "CC7.2", Title: "Security monitoring", Category: "System Operations", Severity: models.ComplianceSeverityHigh,
+			Description: "The entity monitors system components for anomalies indicative of malicious acts, natural disasters, and errors.",
+			Remediation: "Run security scans at least weekly.",
+			CheckQuery:  "security_scans.days_since_last <= 7"},
+		{ControlID: "CC8.1", Title: "Change management", Category: "Change Management", Severity: models.ComplianceSeverityHigh,
+			Description: "The entity authorizes, designs, develops, tests, approves, and implements changes to infrastructure and software.",
+			Remediation: "Require peer review and approval for all production deployments."},
+		{ControlID: "A1.2", Title: "Backup and recovery", Category: "Availability", Severity: models.ComplianceSeverityHigh,
+			Description: "The entity implements backup processes and recovery infrastructure to meet its availability objectives.",
+			Remediation: "Enable automated backups on all data stores.",
+			CheckQuery:  "infrastructure.backup.enabled == true"},
+	},
+	models.FrameworkISO27001: {
+		{ControlID: "A.5.1", Title: "Policies for information security", Category: "Organizational Controls", Severity: models.ComplianceSeverityMedium,
+			Description: "Information security policy and topic-specific policies shall be defined, approved, published and reviewed.",
+			Remediation: "Publish an approved information security policy and review it annually."},
+		{ControlID: "A.5.9", Title: "Inventory of information and assets", Category: "Organizational Controls", Severity: models.ComplianceSeverityMedium,
+			Description: "An inventory of information and other associated assets, including owners, shall be developed and maintained.",
+			Remediation: "Maintain an asset inventory with an assigned owner for every resource."},
+		{ControlID: "A.5.15", Title: "Access control", Category: "Organizational Controls", Severity: models.ComplianceSeverityHigh,
+			Description: "Rules to control physical and logical access to information and assets shall be established and implemented.",
+			Remediation: "Define access control rules and enforce them through role-based access control."},
+		{ControlID: "A.5.24", Title: "Incident management planning", Category: "Organizational Controls", Severity: models.ComplianceSeverityHigh,
+			Description: "The organization shall plan and prepare for managing information security incidents.",
+			Remediation: "Document an incident response plan and test it at least annually."},
+		{ControlID: "A.6.3", Title: "Information security awareness and training", Category: "People Controls", Severity: models.ComplianceSeverityLow,
+			Description: "Personnel shall receive appropriate information security awareness, education and training.",
+			Remediation: "Run security awareness training for all staff annually."},
+		{ControlID: "A.7.1", Title: "Physical security perimeters", Category: "Physical Controls", Severity: models.ComplianceSeverityMedium,
+			Description: "Security perimeters shall be defined and used to protect areas that contain information and assets.",
+			Remediation: "Obtain physical security attestations from hosting providers."},
+		{ControlID: "A.8.5", Title: "Secure authentication", Category: "Technological Controls", Severity: models.ComplianceSeverityHigh,
+			Description: "Secure authentication technologies and procedures shall be implemented.",
+			Remediation: "Require multi-factor authentication for all user accounts."},
+		{ControlID: "A.8.8", Title: "Management of technical vulnerabilities", Category: "Technological Controls", Severity: models.ComplianceSeverityCritical,
+			Description: "Information about technical vulnerabilities shall be obtained and appropriate measures taken.",
+			Remediation: "Remediate open critical and high vulnerabilities.",
+			CheckQuery:  "vulnerabilities.critical == 0"},
+		{ControlID: "A.8.13", Title: "Information backup", Category: "Technological Controls", Severity: models.ComplianceSeverityHigh,
+			Description: "Backup copies of information, software and systems shall be maintained and regularly tested.",
+			Remediation: "Enable automated backups on all data stores.",
+			CheckQuery:  "infrastructure.backup.enabled == true"},
+		{ControlID: "A.8.16", Title: "Monitoring activities", Category: "Technological Controls", Severity: models.ComplianceSeverityHigh,
+			Description: "Networks, systems and applications shall be monitored for anomalous behaviour.",
+			Remediation: "Run security scans at least weekly.",
+			CheckQuery:  "security_scans.days_since_last <= 7"},
+		{ControlID: "A.8.24", Title: "Use of cryptography", Category: "Technological Controls", Severity: models.ComplianceSeverityCritical,
+			Description: "Rules for the effective use of cryptography, including key management, shall be defined and implemented.",
+			Remediation: "Enable encryption on all storage and database resources.",
+			CheckQuery:  "infrastructure.encryption.enabled == true"},
+		{ControlID: "A.8.32", Title: "Change management", Category: "Technological Controls", Severity: models.ComplianceSeverityMedium,
+			Description: "Changes to information processing facilities and information systems shall be subject to change management procedures.",
+			Remediation: "Require approval for production deployments and record every change."},
+	},
+	models.FrameworkGDPR: {
+		{ControlID: "Art.5", Title: "Principles of processing personal data", Category: "Principles", Severity: models.ComplianceSeverityHigh,
+			Description: "Personal data shall be processed lawfully, fairly and transparently, and limited to what is necessary.",
+			Remediation: "Document the purpose and lawful basis for each category of personal data processed."},
+		{ControlID: "Art.6", Title: "Lawfulness of processing", Category: "Principles", Severity: models.ComplianceSeverityHigh,
+			Description: "Processing shall be lawful only if at least one of the lawful bases applies.",
+			Remediation: "Record the lawful basis for every processing activity."},
+		{ControlID: "Art.7", Title: "Conditions for consent", Category: "Principles", Severity: models.ComplianceSeverityMedium,
+			Description: "Where processing is based on consent, the controller shall be able to demonstrate that consent was given.",
+			Remediation: "Store consent records and allow consent to be withdrawn as easily as it was given."},
+		{ControlID: "Art.15", Title: "Right of access", Category: "Data Subject Rights", Severity: models.ComplianceSeverityMedium,
+			Description: "Data subjects have the right to obtain confirmation of and access to their personal data.",
+			Remediation: "Provide a process to fulfil subject access requests within one month."},
+		{ControlID: "Art.17", Title: "Right to erasure", Category: "Data Subject Rights", Severity: models.ComplianceSeverityMedium,
+			Description: "Data subjects have the right to obtain the erasure of their personal data without undue delay.",
+			Remediation: "Implement a verified deletion process for personal data."},
+		{ControlID: "Art.25", Title: "Data protection by design and by default", Category: "Controller Obligations", Severity: models.ComplianceSeverityMedium,
+			Description: "The controller shall implement appropriate measures designed to implement data protection principles.",
+			Remediation: "Include a privacy review in the design of new features."},
+		{ControlID: "Art.30", Title: "Records of processing activities", Category: "Controller Obligations", Severity: models.ComplianceSeverityLow,
+			Description: "Each controller shall maintain a record of processing activities under its responsibility.",
+			Remediation: "Maintain a register of processing activities and review it annually."},
+		{ControlID: "Art.32", Title: "Security of processing", Category: "Security", Severity: models.ComplianceSeverityCritical,
+			Description: "The controller shall implement appropriate technical measures, including encryption of personal data.",
+			Remediation: "Enable encryption on all storage and database resources.",
+			CheckQuery:  "infrastructure.encryption.enabled == true"},
+		{ControlID: "Art.32.1d", Title: "Regular testing of security measures", Category: "Security", Severity: models.ComplianceSeverityHigh,
+			Description: "A process for regularly testing and evaluating the effectiveness of technical measures shall be in place.",
+			Remediation: "Run security scans at least monthly.",
+			CheckQuery:  "security_scans.days_since_last <= 30"},
+		{ControlID: "Art.33", Title: "Breach notification", Category: "Security", Severity: models.ComplianceSeverityHigh,
+			Description: "Personal data breaches shall be notified to the supervisory authority within 72 hours.",
+			Remediation: "Document a breach notification procedure with clear ownership."},
+		{ControlID: "Art.35", Title: "Data protection impact assessment", Category: "Controller Obligations", Severity: models.ComplianceSeverityMedium,
+			Description: "High-risk processing requires an assessment of the impact on the protection of personal data.",
+			Remediation: "Perform a DPIA before starting high-risk processing."},
+	},
+}
+
+// ComplianceTemplateNames lists the frameworks that have a control template
+func ComplianceTemplateNames() []string {
+	names := make([]string, 0, len(complianceTemplates))
+	for framework := range complianceTemplates {
+		names = append(names, string(framework))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SeedFrameworkControls inserts the standard control catalog for a template into a framework.
+// Controls that already exist are skipped, so seeding the same framework again is a no-op. When
+// template is empty the framework's own type is used. It returns the number of controls added.
+func (s *ComplianceService) SeedFrameworkControls(ctx context.Context, organizationID, frameworkID, template, userID string) (int, error) {
+	framework, err := s.frameworkRepo.GetByID(ctx, organizationID, frameworkID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get framework: %w", err)
+	}
+
+	if template == "" {
+		template = string(framework.Framework)
+	}
+	templates, ok := complianceTemplates[models.ComplianceFramework(template)]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownComplianceTemplate, template)
+	}
+
+	now := time.Now()
+	controls := make([]*models.ComplianceControl, 0, len(templates))
+	for _, t := range templates {
+		control := &models.ComplianceControl{
+			ID:          uuid.New().String(),
+			FrameworkID: framework.ID,
+			ControlID:   t.ControlID,
+			Title:       t.Title,
+			Description: t.Description,
+			Category:    t.Category,
+			Status:      models.ControlStatusManual,
+			Severity:    t.Severity,
+			Evidence:    []string{},
+			Remediation: t.Remediation,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if t.CheckQuery != "" {
+			checkQuery := t.CheckQuery
+			control.AutomatedCheck = true
+			control.CheckQuery = &checkQuery
+			control.Status = models.ComplianceControlStatusPending
+		}
+		controls = append(controls, control)
+	}
+
+	var inserted int
+	err = s.txManager.WithTransaction(ctx, func(tx *sql.Tx) error {
+		inserted, err = s.controlRepo.CreateManyTx(ctx, tx, controls)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to seed controls: %w", err)
+	}
+
+	s.logAuditEvent(ctx, organizationID, userID, "compliance_controls_seeded",
+		fmt.Sprintf("Seeded %d %s controls into framework: %s", inserted, template, framework.Name), framework.ID)
+
+	return inserted, nil
+}
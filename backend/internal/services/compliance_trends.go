@@ -0,0 +1,171 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// ErrInvalidTrendRange is returned when a compliance trend range is not supported
+var ErrInvalidTrendRange = errors.New("invalid trend range")
+
+// DefaultComplianceTrendRange is the trend range used when none is given
+const DefaultComplianceTrendRange = "90d"
+
+// complianceTrendRanges maps the supported trend ranges to their length
+var complianceTrendRanges = map[string]time.Duration{
+	"7d":   7 * 24 * time.Hour,
+	"30d":  30 * 24 * time.Hour,
+	"90d":  90 * 24 * time.Hour,
+	"180d": 180 * 24 * time.Hour,
+	"1y":   365 * 24 * time.Hour,
+}
+
+// dailyTrendRangeLimit is the longest range reported one point per day; longer ranges use weeks
+const dailyTrendRangeLimit = 30 * 24 * time.Hour
+
+// complianceTrendBucket accumulates the assessments completed within one trend point
+type complianceTrendBucket struct {
+	date           time.Time
+	scores         map[models.ComplianceFramework][]float64
+	controlsPassed int
+	controlsFailed int
+}
+
+// GetComplianceTrends returns historical compliance scores from completed assessments, one point
+// per day or week depending on the range. Only frameworks with assessments in the range appear.
+func (s *ComplianceService) GetComplianceTrends(ctx context.Context, organizationID, framework, trendRange string) ([]models.ComplianceTrendPoint, error) {
+	if trendRange == "" {
+		trendRange = DefaultComplianceTrendRange
+	}
+	length, ok := complianceTrendRanges[trendRange]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTrendRange, trendRange)
+	}
+
+	assessments, err := s.assessmentRepo.ListCompletedSince(ctx, organizationID, time.Now().Add(-length), framework)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list completed assessments: %w", err)
+	}
+
+	frameworkTypes, err := s.frameworkTypes(ctx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[time.Time]*complianceTrendBucket)
+	for _, assessment := range assessments {
+		date := trendBucketStart(*assessment.CompletedAt, length)
+		bucket, ok := buckets[date]
+		if !ok {
+			bucket = &complianceTrendBucket{
+				date:   date,
+				scores: make(map[models.ComplianceFramework][]float64),
+			}
+			buckets[date] = bucket
+		}
+
+		frameworkType := frameworkTypes[assessment.FrameworkID]
+		bucket.scores[frameworkType] = append(bucket.scores[frameworkType], assessment.Score)
+		if assessment.Summary != nil {
+			bucket.controlsPassed += assessment.Summary.PassedControls
+			bucket.controlsFailed += assessment.Summary.FailedControls
+		}
+	}
+
+	points := make([]models.ComplianceTrendPoint, 0, len(buckets))
+	for _, bucket := range buckets {
+		point := models.ComplianceTrendPoint{
+			Date:            bucket.date,
+			FrameworkScores: make(map[models.ComplianceFramework]float64),
+			ControlsPassed:  bucket.controlsPassed,
+			ControlsFailed:  bucket.controlsFailed,
+		}
+
+		var total float64
+		for frameworkType, scores := range bucket.scores {
+			point.FrameworkScores[frameworkType] = averageScore(scores)
+			total += point.FrameworkScores[frameworkType]
+		}
+		point.OverallScore = total / float64(len(point.FrameworkScores))
+
+		points = append(points, point)
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return points[i].Date.Before(points[j].Date)
+	})
+
+	return points, nil
+}
+
+// complianceScoreWindow is how far back assessments count towards the current compliance score
+const complianceScoreWindow = 365 * 24 * time.Hour
+
+// GetCurrentComplianceScore returns the average score of each framework's most recent completed
+// assessment. It reports false when the organization has no recent completed assessments.
+func (s *ComplianceService) GetCurrentComplianceScore(ctx context.Context, organizationID string) (float64, bool, error) {
+	assessments, err := s.assessmentRepo.ListCompletedSince(ctx, organizationID, time.Now().Add(-complianceScoreWindow), "")
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to list completed assessments: %w", err)
+	}
+
+	// Assessments are ordered by completion, so later ones replace earlier ones
+	latest := make(map[string]float64)
+	for _, assessment := range assessments {
+		latest[assessment.FrameworkID] = assessment.Score
+	}
+	if len(latest) == 0 {
+		return 0, false, nil
+	}
+
+	scores := make([]float64, 0, len(latest))
+	for _, score := range latest {
+		scores = append(scores, score)
+	}
+	return averageScore(scores), true, nil
+}
+
+// frameworkTypes maps an organization's framework IDs to their framework type
+func (s *ComplianceService) frameworkTypes(ctx context.Context, organizationID string) (map[string]models.ComplianceFramework, error) {
+	const pageSize = 100
+
+	types := make(map[string]models.ComplianceFramework)
+	for offset := 0; ; offset += pageSize {
+		frameworks, _, err := s.frameworkRepo.List(ctx, organizationID, pageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list frameworks: %w", err)
+		}
+		for _, framework := range frameworks {
+			types[framework.ID] = framework.Framework
+		}
+
+		if len(frameworks) < pageSize {
+			return types, nil
+		}
+	}
+}
+
+// trendBucketStart truncates a time to the start of its day, or of its week (Monday) for long ranges
+func trendBucketStart(t time.Time, length time.Duration) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if length <= dailyTrendRangeLimit {
+		return day
+	}
+
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+func averageScore(scores []float64) float64 {
+	var sum float64
+	for _, score := range scores {
+		sum += score
+	}
+	return sum / float64(len(scores))
+}
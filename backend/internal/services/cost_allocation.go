@@ -0,0 +1,137 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloudweave/internal/models"
+)
+
+// ErrInvalidAllocationDimensions is returned when cost allocation dimensions are not valid tag keys
+var ErrInvalidAllocationDimensions = errors.New("invalid cost allocation dimensions")
+
+const (
+	// UnallocatedGroup groups the cost of resources missing a dimension's tag
+	UnallocatedGroup = "unallocated"
+
+	// ProjectAllocationDimension is the dimension whose groups are also reported as projects
+	ProjectAllocationDimension = "project"
+
+	// maxAllocationDimensions is the most dimensions an organization can allocate costs by
+	maxAllocationDimensions = 10
+)
+
+// DefaultCostAllocationDimensions are the dimensions of organizations that have not chosen their own
+var DefaultCostAllocationDimensions = []string{"cost-center", "team", "project"}
+
+// DimensionAllocation is the cost allocated to each value of an allocation dimension's tag.
+// Resources without the tag are grouped under UnallocatedGroup.
+type DimensionAllocation struct {
+	TagKey                string                     `json:"tagKey"`
+	Groups                map[string]AllocationGroup `json:"groups"`
+	UnallocatedCost       float64                    `json:"unallocatedCost"`
+	UnallocatedPercentage float64                    `json:"unallocatedPercentage"`
+}
+
+// AllocationGroup is the cost of the resources sharing a value of an allocation dimension's tag
+type AllocationGroup struct {
+	Value      string   `json:"value"`
+	TotalCost  float64  `json:"totalCost"`
+	Percentage float64  `json:"percentage"`
+	Resources  []string `json:"resources"`
+}
+
+// add allocates a resource's cost to the group of its value for the dimension's tag
+func (d *DimensionAllocation) add(tags map[string]string, resourceID string, monthlyCost float64) {
+	value, ok := tags[d.TagKey]
+	if !ok || value == "" {
+		value = UnallocatedGroup
+	}
+
+	group := d.Groups[value]
+	group.Value = value
+	group.TotalCost += monthlyCost
+	group.Resources = append(group.Resources, resourceID)
+	d.Groups[value] = group
+}
+
+// finalize computes each group's share of the total cost
+func (d *DimensionAllocation) finalize(totalCost float64) {
+	d.UnallocatedCost = d.Groups[UnallocatedGroup].TotalCost
+	if totalCost <= 0 {
+		return
+	}
+
+	for value, group := range d.Groups {
+		group.Percentage = group.TotalCost / totalCost * 100
+		d.Groups[value] = group
+	}
+	d.UnallocatedPercentage = d.UnallocatedCost / totalCost * 100
+}
+
+// GetCostAllocationSettings retrieves an organization's cost allocation settings, with the default
+// dimensions if it has not chosen its own
+func (s *CostManagementService) GetCostAllocationSettings(ctx context.Context, orgID string) (*models.CostAllocationSettings, error) {
+	settings, err := s.repoManager.CostAllocation.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = &models.CostAllocationSettings{
+			OrganizationID: orgID,
+			Dimensions:     append([]string(nil), DefaultCostAllocationDimensions...),
+		}
+	}
+	return settings, nil
+}
+
+// UpdateCostAllocationSettings replaces the tag keys an organization's costs are allocated by.
+// Keys are normalized like tag keys and duplicates are dropped.
+func (s *CostManagementService) UpdateCostAllocationSettings(ctx context.Context, orgID, userID string, req *models.UpdateCostAllocationSettingsRequest) (*models.CostAllocationSettings, error) {
+	dimensions, err := normalizeAllocationDimensions(req.Dimensions)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := &models.CostAllocationSettings{
+		OrganizationID: orgID,
+		Dimensions:     dimensions,
+	}
+	if userID != "" {
+		settings.UpdatedBy = &userID
+	}
+
+	if err := s.repoManager.CostAllocation.Save(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func normalizeAllocationDimensions(dimensions []string) ([]string, error) {
+	if len(dimensions) == 0 {
+		return nil, fmt.Errorf("%w: at least one dimension is required", ErrInvalidAllocationDimensions)
+	}
+
+	normalized := make([]string, 0, len(dimensions))
+	seen := make(map[string]bool, len(dimensions))
+	for _, dimension := range dimensions {
+		tag, err := NormalizeTag(dimension)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidAllocationDimensions, err)
+		}
+		key, value := ParseTag(tag)
+		if value != "" {
+			return nil, fmt.Errorf("%w: %q must be a tag key without a value", ErrInvalidAllocationDimensions, dimension)
+		}
+		if !seen[key] {
+			seen[key] = true
+			normalized = append(normalized, key)
+		}
+	}
+
+	if len(normalized) > maxAllocationDimensions {
+		return nil, fmt.Errorf("%w: at most %d dimensions are allowed", ErrInvalidAllocationDimensions, maxAllocationDimensions)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,347 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// ErrInvalidExportRange is returned when a cost export date range is empty or too long
+var ErrInvalidExportRange = errors.New("invalid export date range")
+
+// Cost export formats
+const (
+	CostExportFormatFOCUS = "focus"
+	CostExportFormatCSV   = "csv"
+)
+
+// maxCostExportDays is the longest date range a cost export may cover
+const maxCostExportDays = 366
+
+// focusColumns are the FOCUS 1.0 columns emitted by the focus export, followed by CloudWeave's
+// own columns with the x_ prefix the specification reserves for custom columns
+var focusColumns = []string{
+	"BilledCost", "BillingAccountId", "BillingAccountName", "BillingCurrency",
+	"BillingPeriodEnd", "BillingPeriodStart", "ChargeCategory", "ChargeClass",
+	"ChargeDescription", "ChargeFrequency", "ChargePeriodEnd", "ChargePeriodStart",
+	"ConsumedQuantity", "ConsumedUnit", "ContractedCost", "ContractedUnitPrice",
+	"EffectiveCost", "InvoiceIssuerName", "ListCost", "ListUnitPrice",
+	"PricingQuantity", "PricingUnit", "ProviderName", "PublisherName",
+	"RegionId", "RegionName", "ResourceId", "ResourceName", "ResourceType",
+	"ServiceCategory", "ServiceName", "SubAccountId", "SubAccountName", "Tags",
+	"x_CloudWeaveResourceId", "x_CloudWeaveResourceType",
+}
+
+// flatCostColumns are the columns of the simple csv export
+var flatCostColumns = []string{
+	"date", "resource_id", "resource_name", "external_id", "provider", "service", "type",
+	"region", "hours", "hourly_cost", "cost", "currency", "tags",
+}
+
+// focusProviderNames maps providers to their FOCUS provider names
+var focusProviderNames = map[string]string{
+	models.ProviderAWS:   "AWS",
+	models.ProviderAzure: "Microsoft",
+	models.ProviderGCP:   "Google Cloud",
+}
+
+// focusServiceCategories maps resource types to FOCUS service categories
+var focusServiceCategories = map[string]string{
+	models.InfraTypeServer:    "Compute",
+	models.InfraTypeDatabase:  "Databases",
+	models.InfraTypeStorage:   "Storage",
+	models.InfraTypeNetwork:   "Networking",
+	models.InfraTypeContainer: "Compute",
+}
+
+// focusResourceTypes maps resource types to FOCUS display names
+var focusResourceTypes = map[string]string{
+	models.InfraTypeServer:    "Virtual Machine",
+	models.InfraTypeDatabase:  "Database Instance",
+	models.InfraTypeStorage:   "Storage Bucket",
+	models.InfraTypeNetwork:   "Virtual Network",
+	models.InfraTypeContainer: "Container",
+}
+
+// costServiceNames maps each provider's resource types to the name of the cloud service billing them
+var costServiceNames = map[string]map[string]string{
+	models.ProviderAWS: {
+		models.InfraTypeServer:    "Amazon Elastic Compute Cloud",
+		models.InfraTypeDatabase:  "Amazon Relational Database Service",
+		models.InfraTypeStorage:   "Amazon Simple Storage Service",
+		models.InfraTypeNetwork:   "Amazon Virtual Private Cloud",
+		models.InfraTypeContainer: "Amazon Elastic Container Service",
+	},
+	models.ProviderAzure: {
+		models.InfraTypeServer:    "Virtual Machines",
+		models.InfraTypeDatabase:  "Azure SQL Database",
+		models.InfraTypeStorage:   "Storage",
+		models.InfraTypeNetwork:   "Virtual Network",
+		models.InfraTypeContainer: "Azure Container Instances",
+	},
+	models.ProviderGCP: {
+		models.InfraTypeServer:    "Compute Engine",
+		models.InfraTypeDatabase:  "Cloud SQL",
+		models.InfraTypeStorage:   "Cloud Storage",
+		models.InfraTypeNetwork:   "Virtual Private Cloud",
+		models.InfraTypeContainer: "Cloud Run",
+	},
+}
+
+// costExportRow is one resource's cost for one day
+type costExportRow struct {
+	infra      *models.Infrastructure
+	day        time.Time
+	hours      float64
+	hourlyCost float64
+}
+
+func (r *costExportRow) cost() float64 {
+	return r.hours * r.hourlyCost
+}
+
+// ExportCosts writes an organization's daily cost per resource between two days, inclusive, as
+// FOCUS or flat CSV. Costs come from each resource's recorded hourly cost and the hours its
+// status history shows it running, so the export only reads CloudWeave's records. Rows are
+// written a day at a time, so large ranges are streamed rather than built in memory.
+func (s *CostManagementService) ExportCosts(ctx context.Context, orgID, format string, from, to time.Time, w io.Writer) error {
+	if format != CostExportFormatFOCUS && format != CostExportFormatCSV {
+		return fmt.Errorf("%w: %s", ErrUnsupportedExportFormat, format)
+	}
+	from, to = statDay(from), statDay(to).AddDate(0, 0, 1)
+	if !to.After(from) || to.Sub(from) > maxCostExportDays*24*time.Hour {
+		return fmt.Errorf("%w: the range must cover 1 to %d days", ErrInvalidExportRange, maxCostExportDays)
+	}
+
+	organization, err := s.repoManager.Organization.GetByID(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	var infrastructures []*models.Infrastructure
+	params := repositories.DefaultListParams()
+	params.SortBy = "name"
+	params.Order = "asc"
+	for {
+		page, err := s.repoManager.Infrastructure.List(ctx, orgID, params)
+		if err != nil {
+			return fmt.Errorf("failed to list infrastructure: %w", err)
+		}
+		infrastructures = append(infrastructures, page...)
+
+		if len(page) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+
+	history, err := s.repoManager.Infrastructure.ListStatusHistory(ctx, orgID, from)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	header := flatCostColumns
+	if format == CostExportFormatFOCUS {
+		header = focusColumns
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		for _, infra := range infrastructures {
+			row := &costExportRow{
+				infra:      infra,
+				day:        day,
+				hours:      runningHours(infra, history[infra.ID], day, day.AddDate(0, 0, 1)),
+				hourlyCost: recordedHourlyCost(infra),
+			}
+			if row.hours == 0 || row.hourlyCost == 0 {
+				continue
+			}
+
+			record := flatCostRecord(row)
+			if format == CostExportFormatFOCUS {
+				record = focusCostRecord(row, organization)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// focusCostRecord maps a cost row to the FOCUS columns
+func focusCostRecord(row *costExportRow, organization *models.Organization) []string {
+	infra := row.infra
+	billingStart := time.Date(row.day.Year(), row.day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	cost := exportDecimal(row.cost())
+	unitPrice := exportDecimal(row.hourlyCost)
+	hours := exportDecimal(row.hours)
+	provider := focusProviderNames[infra.Provider]
+	service := costServiceNames[infra.Provider][infra.Type]
+
+	values := map[string]string{
+		"BilledCost":               cost,
+		"BillingAccountId":         organization.ID,
+		"BillingAccountName":       organization.Name,
+		"BillingCurrency":          "USD",
+		"BillingPeriodEnd":         focusTime(billingStart.AddDate(0, 1, 0)),
+		"BillingPeriodStart":       focusTime(billingStart),
+		"ChargeCategory":           "Usage",
+		"ChargeDescription":        fmt.Sprintf("%s %s usage", service, infra.Type),
+		"ChargeFrequency":          "Usage-Based",
+		"ChargePeriodEnd":          focusTime(row.day.AddDate(0, 0, 1)),
+		"ChargePeriodStart":        focusTime(row.day),
+		"ConsumedQuantity":         hours,
+		"ConsumedUnit":             "Hours",
+		"ContractedCost":           cost,
+		"ContractedUnitPrice":      unitPrice,
+		"EffectiveCost":            cost,
+		"InvoiceIssuerName":        provider,
+		"ListCost":                 cost,
+		"ListUnitPrice":            unitPrice,
+		"PricingQuantity":          hours,
+		"PricingUnit":              "Hours",
+		"ProviderName":             provider,
+		"PublisherName":            provider,
+		"RegionId":                 infra.Region,
+		"RegionName":               infra.Region,
+		"ResourceId":               exportResourceID(infra),
+		"ResourceName":             infra.Name,
+		"ResourceType":             focusResourceTypes[infra.Type],
+		"ServiceCategory":          focusServiceCategories[infra.Type],
+		"ServiceName":              service,
+		"SubAccountId":             organization.ID,
+		"SubAccountName":           organization.Name,
+		"Tags":                     focusTags(infra.Tags),
+		"x_CloudWeaveResourceId":   infra.ID,
+		"x_CloudWeaveResourceType": infra.Type,
+	}
+
+	// Columns without a value, such as ChargeClass for regular charges, are left empty (null)
+	record := make([]string, len(focusColumns))
+	for i, column := range focusColumns {
+		record[i] = values[column]
+	}
+	return record
+}
+
+// flatCostRecord maps a cost row to the simple csv columns
+func flatCostRecord(row *costExportRow) []string {
+	infra := row.infra
+	externalID := ""
+	if infra.ExternalID != nil {
+		externalID = *infra.ExternalID
+	}
+
+	return []string{
+		row.day.Format("2006-01-02"),
+		infra.ID,
+		infra.Name,
+		externalID,
+		infra.Provider,
+		costServiceNames[infra.Provider][infra.Type],
+		infra.Type,
+		infra.Region,
+		exportDecimal(row.hours),
+		exportDecimal(row.hourlyCost),
+		exportDecimal(row.cost()),
+		"USD",
+		strings.Join(infra.Tags, ";"),
+	}
+}
+
+// runningHours returns how many hours of a period a resource was running, from its status
+// changes oldest first. Before its first recorded change the resource is assumed to have had that
+// change's status since it was created, and with no history its current status is assumed.
+func runningHours(infra *models.Infrastructure, changes []*models.InfrastructureStatusChange, from, to time.Time) float64 {
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+
+	status := infra.Status
+	if len(changes) > 0 {
+		status = changes[0].Status
+	}
+
+	var running time.Duration
+	countRunning := func(status string, start, end time.Time) {
+		if status != models.InfraStatusRunning {
+			return
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			running += end.Sub(start)
+		}
+	}
+
+	start := infra.CreatedAt
+	for _, change := range changes {
+		countRunning(status, start, change.ChangedAt)
+		status, start = change.Status, change.ChangedAt
+	}
+	countRunning(status, start, to)
+
+	return running.Hours()
+}
+
+// recordedHourlyCost returns the hourly cost stored with a resource
+func recordedHourlyCost(infra *models.Infrastructure) float64 {
+	hourly, _ := costInfoCosts(infra.CostInfo)
+	return hourly
+}
+
+// exportResourceID identifies a resource by its provider ID, falling back to its CloudWeave ID
+func exportResourceID(infra *models.Infrastructure) string {
+	if infra.ExternalID != nil && *infra.ExternalID != "" {
+		return *infra.ExternalID
+	}
+	return infra.ID
+}
+
+// focusTags encodes "key=value" tags as the JSON object FOCUS expects
+func focusTags(tags []string) string {
+	if len(tags) == 0 {
+		return ""
+	}
+	object := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value := ParseTag(tag)
+		object[key] = value
+	}
+	encoded, _ := json.Marshal(object)
+	return string(encoded)
+}
+
+// focusTime formats a time as the ISO 8601 UTC timestamp FOCUS uses
+func focusTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// exportDecimal formats a number without exponent notation
+func exportDecimal(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
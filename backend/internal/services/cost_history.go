@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// ErrInvalidCostHistoryRange is returned when a cost history range is not supported
+var ErrInvalidCostHistoryRange = errors.New("invalid cost history range")
+
+// DefaultCostHistoryRange is the cost history range used when none is given
+const DefaultCostHistoryRange = "30d"
+
+// costHistoryRanges maps the supported cost history ranges to their length in days
+var costHistoryRanges = map[string]int{
+	"7d":  7,
+	"30d": 30,
+	"90d": 90,
+	"1y":  365,
+}
+
+// minCorrelationPoints is the fewest datapoints a cost/usage correlation is computed from
+const minCorrelationPoints = 3
+
+// ResourceCostPoint is a resource's cost and average usage on one day
+type ResourceCostPoint struct {
+	Date              time.Time `json:"date"`
+	Cost              float64   `json:"cost"`
+	CPUUtilization    float64   `json:"cpuUtilization"`
+	MemoryUtilization float64   `json:"memoryUtilization"`
+}
+
+// CostUsageCorrelation holds the Pearson correlation of a resource's daily cost with its usage,
+// from -1 to 1. A value is null when there are too few datapoints or either series is flat.
+type CostUsageCorrelation struct {
+	CPU    *float64 `json:"cpu"`
+	Memory *float64 `json:"memory"`
+}
+
+// ResourceCostHistory is a resource's daily cost over a range
+type ResourceCostHistory struct {
+	ResourceID  string               `json:"resourceId"`
+	Range       string               `json:"range"`
+	Points      []ResourceCostPoint  `json:"points"`
+	Correlation CostUsageCorrelation `json:"correlation"`
+}
+
+// GetResourceCostHistory returns a resource's daily cost from its cost snapshots, with how closely
+// cost tracked CPU and memory usage. Resources with no snapshots yet get an empty series.
+func (s *CostManagementService) GetResourceCostHistory(ctx context.Context, infra *models.Infrastructure, historyRange string) (*ResourceCostHistory, error) {
+	if historyRange == "" {
+		historyRange = DefaultCostHistoryRange
+	}
+	days, ok := costHistoryRanges[historyRange]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCostHistoryRange, historyRange)
+	}
+
+	to := statDay(time.Now()).AddDate(0, 0, 1)
+	snapshots, err := s.repoManager.CostSnapshot.ListResourceBetween(ctx, infra.ID, to.AddDate(0, 0, -days), to)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &ResourceCostHistory{
+		ResourceID: infra.ID,
+		Range:      historyRange,
+		Points:     make([]ResourceCostPoint, 0, len(snapshots)),
+	}
+	costs := make([]float64, 0, len(snapshots))
+	cpu := make([]float64, 0, len(snapshots))
+	memory := make([]float64, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		history.Points = append(history.Points, ResourceCostPoint{
+			Date:              snapshot.CapturedOn,
+			Cost:              snapshot.DailyCost,
+			CPUUtilization:    snapshot.CPUUtilization,
+			MemoryUtilization: snapshot.MemoryUtilization,
+		})
+		costs = append(costs, snapshot.DailyCost)
+		cpu = append(cpu, snapshot.CPUUtilization)
+		memory = append(memory, snapshot.MemoryUtilization)
+	}
+
+	history.Correlation.CPU = pearsonCorrelation(costs, cpu)
+	history.Correlation.Memory = pearsonCorrelation(costs, memory)
+
+	return history, nil
+}
+
+// pearsonCorrelation returns the correlation coefficient of two equally long series, or nil when
+// it is undefined
+func pearsonCorrelation(xs, ys []float64) *float64 {
+	n := len(xs)
+	if n < minCorrelationPoints || n != len(ys) {
+		return nil
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(n)
+	meanY /= float64(n)
+
+	var covariance, varianceX, varianceY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return nil
+	}
+
+	correlation := math.Round(covariance/math.Sqrt(varianceX*varianceY)*1000) / 1000
+	return &correlation
+}
@@ -0,0 +1,54 @@
+package services
+
+// Cost models of the cost info providers report with resource details
+const (
+	// CostModelFixed is a resource billed for every hour it is provisioned, e.g. an instance
+	CostModelFixed = "fixed"
+
+	// CostModelUsage is a resource billed for what it uses, e.g. the GB a bucket stores, whose
+	// costs are estimated from its current usage
+	CostModelUsage = "usage"
+)
+
+// costUnitGBMonth is the unit of storage billed per GB stored for a month
+const costUnitGBMonth = "GB-month"
+
+// fixedCostInfo is the cost info of a resource billed hourly while it is provisioned
+func fixedCostInfo(hourlyCost float64) map[string]interface{} {
+	return map[string]interface{}{
+		"currency":     "USD",
+		"cost_model":   CostModelFixed,
+		"hourly_cost":  hourlyCost,
+		"monthly_cost": hourlyCost * hoursPerMonth,
+	}
+}
+
+// usageCostInfo is the cost info of a resource billed per unit of usage a month, estimated from
+// the units it currently uses, e.g. GB stored
+func usageCostInfo(unit string, unitPrice, usage float64) map[string]interface{} {
+	monthlyCost := unitPrice * usage
+	return map[string]interface{}{
+		"currency":     "USD",
+		"cost_model":   CostModelUsage,
+		"hourly_cost":  monthlyCost / hoursPerMonth,
+		"monthly_cost": monthlyCost,
+		"unit":         unit,
+		"unit_price":   unitPrice,
+		"usage":        usage,
+	}
+}
+
+// costInfoCosts returns the hourly and monthly costs of a resource's cost info. Cost info recorded
+// with only one of them, as some providers reported it before it was normalized, has the other
+// derived from it.
+func costInfoCosts(costInfo map[string]interface{}) (hourly, monthly float64) {
+	hourly, hasHourly := costInfo["hourly_cost"].(float64)
+	monthly, hasMonthly := costInfo["monthly_cost"].(float64)
+	switch {
+	case hasHourly && !hasMonthly:
+		monthly = hourly * hoursPerMonth
+	case hasMonthly && !hasHourly:
+		hourly = monthly / hoursPerMonth
+	}
+	return hourly, monthly
+}
@@ -3,23 +3,60 @@ package services
 import (
 	"context"
 	"fmt"
-	"strings"
+	"log"
+	"math"
 	"time"
 
+	"cloudweave/internal/models"
 	"cloudweave/internal/repositories"
 )
 
+const (
+	// DefaultCostAnomalyThreshold is how many standard deviations today's spend may differ from
+	// the baseline before a cost anomaly alert is raised
+	DefaultCostAnomalyThreshold = 3.0
+
+	// costAnomalyBaselineDays is the number of trailing days the spend baseline is computed over
+	costAnomalyBaselineDays = 30
+
+	// costAnomalyMinSamples is the fewest daily snapshots a baseline needs to be meaningful
+	costAnomalyMinSamples = 7
+)
+
 // CostManagementService handles cost tracking, allocation, and optimization
 type CostManagementService struct {
-	repoManager *repositories.RepositoryManager
-	providers   map[string]CloudProvider
+	repoManager      *repositories.RepositoryManager
+	providers        map[string]CloudProvider
+	awsAccounts      *AWSAccounts
+	resourceCache    *ResourceCache
+	anomalyThreshold float64
 }
 
 // NewCostManagementService creates a new cost management service
 func NewCostManagementService(repoManager *repositories.RepositoryManager, providers map[string]CloudProvider) *CostManagementService {
 	return &CostManagementService{
-		repoManager: repoManager,
-		providers:   providers,
+		repoManager:      repoManager,
+		providers:        providers,
+		anomalyThreshold: DefaultCostAnomalyThreshold,
+	}
+}
+
+// SetAWSAccounts sets where the AWS accounts organizations manage through their own credentials
+// come from, so the costs of their resources are read from those accounts
+func (s *CostManagementService) SetAWSAccounts(accounts *AWSAccounts) {
+	s.awsAccounts = accounts
+}
+
+// SetResourceCache sets the cache that provider resource details and metrics are read through
+func (s *CostManagementService) SetResourceCache(cache *ResourceCache) {
+	s.resourceCache = cache
+}
+
+// SetAnomalyThreshold sets how many standard deviations from the baseline today's spend may be
+// before a cost anomaly alert is raised. Non-positive values keep the current threshold.
+func (s *CostManagementService) SetAnomalyThreshold(stdDevs float64) {
+	if stdDevs > 0 {
+		s.anomalyThreshold = stdDevs
 	}
 }
 
@@ -39,6 +76,7 @@ type ResourceCost struct {
 	ResourceName string            `json:"resourceName"`
 	ResourceType string            `json:"resourceType"`
 	Provider     string            `json:"provider"`
+	CostModel    string            `json:"costModel,omitempty"`
 	HourlyCost   float64           `json:"hourlyCost"`
 	DailyCost    float64           `json:"dailyCost"`
 	MonthlyCost  float64           `json:"monthlyCost"`
@@ -63,11 +101,13 @@ type CostTrend struct {
 
 // CostRecommendation represents cost optimization recommendations
 type CostRecommendation struct {
-	Type             string  `json:"type"`
-	Description      string  `json:"description"`
-	PotentialSavings float64 `json:"potentialSavings"`
-	Priority         string  `json:"priority"`
-	Action           string  `json:"action"`
+	Type             string               `json:"type"`
+	Description      string               `json:"description"`
+	PotentialSavings float64              `json:"potentialSavings"`
+	Priority         string               `json:"priority"`
+	Action           string               `json:"action"`
+	Purchase         *ReservationPurchase `json:"purchase,omitempty"`
+	Orphan           *OrphanedResource    `json:"orphan,omitempty"`
 }
 
 // GetCostBreakdown retrieves detailed cost breakdown for an organization
@@ -90,25 +130,44 @@ func (s *CostManagementService) GetCostBreakdown(ctx context.Context, orgID stri
 		Recommendations: []CostRecommendation{},
 	}
 
+	providers, err := s.awsAccounts.providersFor(ctx, orgID, s.providers)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fetch usage metrics in bulk from providers that support it
+	externalIDs := make(map[providerRegion][]string)
+	for _, infra := range infrastructures {
+		if infra.ExternalID != nil {
+			key := providerRegion{infra.Provider, infra.Region}
+			externalIDs[key] = append(externalIDs[key], *infra.ExternalID)
+		}
+	}
+	for key, ids := range externalIDs {
+		if provider, exists := providers[key.provider]; exists {
+			s.resourceCache.PrefetchMetrics(withResourceRegion(ctx, key.region), key.provider, provider, ids)
+		}
+	}
+
 	// Calculate costs for each resource
 	for _, infra := range infrastructures {
 		if infra.ExternalID == nil {
 			continue
 		}
 
-		provider, exists := s.providers[infra.Provider]
+		provider, exists := providers[infra.Provider]
 		if !exists {
 			continue
 		}
 
 		// Get resource details including cost information
-		details, err := provider.GetResourceDetails(ctx, *infra.ExternalID)
+		details, err := s.resourceCache.Details(withResourceRegion(ctx, infra.Region), infra.Provider, provider, *infra.ExternalID)
 		if err != nil {
 			continue
 		}
 
 		// Get current metrics for usage calculation
-		metrics, err := provider.GetResourceMetrics(ctx, *infra.ExternalID)
+		metrics, err := s.resourceCache.Metrics(withResourceRegion(ctx, infra.Region), infra.Provider, provider, *infra.ExternalID)
 		if err != nil {
 			metrics = map[string]interface{}{}
 		}
@@ -118,11 +177,8 @@ func (s *CostManagementService) GetCostBreakdown(ctx context.Context, orgID stri
 		if !ok {
 			continue
 		}
-
-		hourlyCost := 0.0
-		if hc, ok := costInfo["hourly_cost"].(float64); ok {
-			hourlyCost = hc
-		}
+		hourlyCost, monthlyCost := costInfoCosts(costInfo)
+		costModel, _ := costInfo["cost_model"].(string)
 
 		// Calculate usage metrics
 		usage := ResourceUsage{}
@@ -139,9 +195,10 @@ func (s *CostManagementService) GetCostBreakdown(ctx context.Context, orgID stri
 			ResourceName: infra.Name,
 			ResourceType: infra.Type,
 			Provider:     infra.Provider,
+			CostModel:    costModel,
 			HourlyCost:   hourlyCost,
 			DailyCost:    hourlyCost * 24,
-			MonthlyCost:  hourlyCost * 24 * 30,
+			MonthlyCost:  monthlyCost,
 			Tags:         s.convertTags(infra.Tags),
 			Usage:        usage,
 		}
@@ -161,14 +218,29 @@ func (s *CostManagementService) GetCostBreakdown(ctx context.Context, orgID stri
 
 // GetCostByTags retrieves cost breakdown by tags
 func (s *CostManagementService) GetCostByTags(ctx context.Context, orgID string, tags map[string]string) (map[string]float64, error) {
+	filterTags := make([]string, 0, len(tags))
+	for key, value := range tags {
+		filterTags = append(filterTags, key+"="+value)
+	}
+	filterTags, err := NormalizeTags(filterTags)
+	if err != nil {
+		return nil, err
+	}
+
 	infrastructures, err := s.repoManager.Infrastructure.List(ctx, orgID, repositories.ListParams{
 		Limit:  1000,
 		Offset: 0,
+		Tags:   filterTags,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
 	}
 
+	providers, err := s.awsAccounts.providersFor(ctx, orgID, s.providers)
+	if err != nil {
+		return nil, err
+	}
+
 	costByTags := make(map[string]float64)
 
 	for _, infra := range infrastructures {
@@ -176,17 +248,12 @@ func (s *CostManagementService) GetCostByTags(ctx context.Context, orgID string,
 			continue
 		}
 
-		// Check if infrastructure matches the requested tags
-		if !s.matchesTags(infra.Tags, tags) {
-			continue
-		}
-
-		provider, exists := s.providers[infra.Provider]
+		provider, exists := providers[infra.Provider]
 		if !exists {
 			continue
 		}
 
-		details, err := provider.GetResourceDetails(ctx, *infra.ExternalID)
+		details, err := s.resourceCache.Details(withResourceRegion(ctx, infra.Region), infra.Provider, provider, *infra.ExternalID)
 		if err != nil {
 			continue
 		}
@@ -196,12 +263,7 @@ func (s *CostManagementService) GetCostByTags(ctx context.Context, orgID string,
 			continue
 		}
 
-		hourlyCost := 0.0
-		if hc, ok := costInfo["hourly_cost"].(float64); ok {
-			hourlyCost = hc
-		}
-
-		monthlyCost := hourlyCost * 24 * 30
+		_, monthlyCost := costInfoCosts(costInfo)
 
 		// Group by tag values
 		for _, tag := range infra.Tags {
@@ -252,9 +314,142 @@ func (s *CostManagementService) GetBudgetAlerts(ctx context.Context, orgID strin
 		})
 	}
 
+	// Check whether today's spend deviates from the trailing baseline
+	anomaly, err := s.detectCostAnomaly(ctx, orgID, projectedDailySpend(breakdown))
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect cost anomaly: %w", err)
+	}
+	if anomaly != nil {
+		alerts = append(alerts, *anomaly)
+	}
+
 	return alerts, nil
 }
 
+// detectCostAnomaly compares today's projected daily spend with the mean and standard deviation
+// of the trailing daily cost snapshots, returning a cost_anomaly alert when it deviates by more
+// than the configured number of standard deviations. No alert is raised until enough history has
+// been recorded, or when the history is flat.
+func (s *CostManagementService) detectCostAnomaly(ctx context.Context, orgID string, dailySpend float64) (*BudgetAlert, error) {
+	today := statDay(time.Now())
+	snapshots, err := s.repoManager.CostSnapshot.ListBetween(ctx, orgID, today.AddDate(0, 0, -costAnomalyBaselineDays), today)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) < costAnomalyMinSamples {
+		return nil, nil
+	}
+
+	mean := 0.0
+	for _, snapshot := range snapshots {
+		mean += snapshot.DailyCost
+	}
+	mean /= float64(len(snapshots))
+
+	variance := 0.0
+	for _, snapshot := range snapshots {
+		variance += (snapshot.DailyCost - mean) * (snapshot.DailyCost - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(snapshots)))
+	if stdDev == 0 {
+		return nil, nil
+	}
+
+	deviation := (dailySpend - mean) / stdDev
+	if math.Abs(deviation) <= s.anomalyThreshold {
+		return nil, nil
+	}
+
+	direction, severity := "above", "high"
+	if deviation < 0 {
+		direction, severity = "below", "medium"
+	}
+
+	return &BudgetAlert{
+		Type:        "cost_anomaly",
+		Message:     fmt.Sprintf("Projected daily spend of $%.2f is %.1f standard deviations %s the %d-day baseline of $%.2f", dailySpend, math.Abs(deviation), direction, costAnomalyBaselineDays, mean),
+		Severity:    severity,
+		CurrentCost: dailySpend,
+		Timestamp:   time.Now(),
+		Anomaly: &CostAnomaly{
+			DailySpend: dailySpend,
+			Baseline:   mean,
+			StdDev:     stdDev,
+			Deviation:  deviation,
+			Threshold:  s.anomalyThreshold,
+			SampleDays: len(snapshots),
+		},
+	}, nil
+}
+
+// CaptureCostSnapshots records today's projected daily spend for every organization and each of
+// its resources
+func (s *CostManagementService) CaptureCostSnapshots(ctx context.Context) error {
+	params := repositories.DefaultListParams()
+	params.Limit = 100
+
+	for {
+		organizations, err := s.repoManager.Organization.List(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to list organizations: %w", err)
+		}
+
+		for _, organization := range organizations {
+			breakdown, err := s.GetCostBreakdown(ctx, organization.ID, "daily")
+			if err != nil {
+				log.Printf("Failed to capture cost snapshot for organization %s: %v", organization.ID, err)
+				continue
+			}
+
+			today := statDay(time.Now())
+			snapshot := &models.CostSnapshot{
+				OrganizationID: organization.ID,
+				DailyCost:      projectedDailySpend(breakdown),
+				CapturedOn:     today,
+			}
+			if err := s.repoManager.CostSnapshot.Save(ctx, snapshot); err != nil {
+				log.Printf("Failed to capture cost snapshot for organization %s: %v", organization.ID, err)
+			}
+
+			for _, resource := range breakdown.Breakdown {
+				resourceSnapshot := &models.ResourceCostSnapshot{
+					InfrastructureID:  resource.ResourceID,
+					DailyCost:         resource.DailyCost,
+					CPUUtilization:    resource.Usage.CPUUtilization,
+					MemoryUtilization: resource.Usage.MemoryUtilization,
+					CapturedOn:        today,
+				}
+				if err := s.repoManager.CostSnapshot.SaveResource(ctx, resourceSnapshot); err != nil {
+					log.Printf("Failed to capture cost snapshot for resource %s: %v", resource.ResourceID, err)
+				}
+			}
+		}
+
+		if len(organizations) < params.Limit {
+			return nil
+		}
+		params.Offset += params.Limit
+	}
+}
+
+// StartCostSnapshots periodically records today's projected daily spend until ctx is cancelled.
+// Snapshots are kept per day, so later captures on the same day replace earlier ones.
+func (s *CostManagementService) StartCostSnapshots(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.CaptureCostSnapshots(ctx); err != nil {
+				log.Printf("Cost snapshot capture failed: %v", err)
+			}
+		}
+	}
+}
+
 // GetCostForecast generates cost forecast for the next 30 days
 func (s *CostManagementService) GetCostForecast(ctx context.Context, orgID string) ([]CostForecast, error) {
 	// Get current cost breakdown
@@ -312,8 +507,15 @@ func (s *CostManagementService) GetRealTimeCostMonitoring(ctx context.Context, o
 	return realTimeData, nil
 }
 
-// GetCostAllocationByTags provides detailed cost allocation by tags/projects
+// GetCostAllocationByTags provides detailed cost allocation by tags and by each of the
+// organization's allocation dimensions. Resources without a dimension's tag are grouped as
+// unallocated for that dimension.
 func (s *CostManagementService) GetCostAllocationByTags(ctx context.Context, orgID string) (*CostAllocationData, error) {
+	settings, err := s.GetCostAllocationSettings(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cost allocation settings: %w", err)
+	}
+
 	// Get all infrastructure for the organization
 	infrastructures, err := s.repoManager.Infrastructure.List(ctx, orgID, repositories.ListParams{
 		Limit:  1000,
@@ -323,11 +525,23 @@ func (s *CostManagementService) GetCostAllocationByTags(ctx context.Context, org
 		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
 	}
 
+	providers, err := s.awsAccounts.providersFor(ctx, orgID, s.providers)
+	if err != nil {
+		return nil, err
+	}
+
 	allocationData := &CostAllocationData{
 		TotalCost:       0,
 		AllocationByTag: make(map[string]TagAllocation),
+		Dimensions:      make([]DimensionAllocation, len(settings.Dimensions)),
 		Projects:        make(map[string]ProjectAllocation),
 	}
+	for i, dimension := range settings.Dimensions {
+		allocationData.Dimensions[i] = DimensionAllocation{
+			TagKey: dimension,
+			Groups: make(map[string]AllocationGroup),
+		}
+	}
 
 	// Process each infrastructure resource
 	for _, infra := range infrastructures {
@@ -335,13 +549,13 @@ func (s *CostManagementService) GetCostAllocationByTags(ctx context.Context, org
 			continue
 		}
 
-		provider, exists := s.providers[infra.Provider]
+		provider, exists := providers[infra.Provider]
 		if !exists {
 			continue
 		}
 
 		// Get resource details including cost information
-		details, err := provider.GetResourceDetails(ctx, *infra.ExternalID)
+		details, err := s.resourceCache.Details(withResourceRegion(ctx, infra.Region), infra.Provider, provider, *infra.ExternalID)
 		if err != nil {
 			continue
 		}
@@ -352,48 +566,43 @@ func (s *CostManagementService) GetCostAllocationByTags(ctx context.Context, org
 			continue
 		}
 
-		hourlyCost := 0.0
-		if hc, ok := costInfo["hourly_cost"].(float64); ok {
-			hourlyCost = hc
-		}
-
-		monthlyCost := hourlyCost * 24 * 30
+		_, monthlyCost := costInfoCosts(costInfo)
 		allocationData.TotalCost += monthlyCost
 
 		// Process tags for allocation
-		for _, tag := range infra.Tags {
-			// Parse tag in format "key=value"
-			parts := strings.Split(tag, "=")
-			if len(parts) == 2 {
-				key := parts[0]
-				value := parts[1]
-
-				// Add to tag allocation
-				if existing, exists := allocationData.AllocationByTag[key]; exists {
-					existing.TotalCost += monthlyCost
-					existing.Resources = append(existing.Resources, infra.ID)
-					allocationData.AllocationByTag[key] = existing
-				} else {
-					allocationData.AllocationByTag[key] = TagAllocation{
-						TagName:   key,
-						TotalCost: monthlyCost,
-						Resources: []string{infra.ID},
-					}
+		tags := s.convertTags(infra.Tags)
+		for key := range tags {
+			if existing, exists := allocationData.AllocationByTag[key]; exists {
+				existing.TotalCost += monthlyCost
+				existing.Resources = append(existing.Resources, infra.ID)
+				allocationData.AllocationByTag[key] = existing
+			} else {
+				allocationData.AllocationByTag[key] = TagAllocation{
+					TagName:   key,
+					TotalCost: monthlyCost,
+					Resources: []string{infra.ID},
 				}
+			}
+		}
+
+		for i := range allocationData.Dimensions {
+			allocationData.Dimensions[i].add(tags, infra.ID, monthlyCost)
+		}
+	}
+
+	for i := range allocationData.Dimensions {
+		dimension := &allocationData.Dimensions[i]
+		dimension.finalize(allocationData.TotalCost)
 
-				// Add to project allocation (using environment tag as project)
-				if key == "environment" {
-					if existing, exists := allocationData.Projects[value]; exists {
-						existing.TotalCost += monthlyCost
-						existing.Resources = append(existing.Resources, infra.ID)
-						allocationData.Projects[value] = existing
-					} else {
-						allocationData.Projects[value] = ProjectAllocation{
-							ProjectName: value,
-							TotalCost:   monthlyCost,
-							Resources:   []string{infra.ID},
-						}
-					}
+		if dimension.TagKey == ProjectAllocationDimension {
+			for value, group := range dimension.Groups {
+				if value == UnallocatedGroup {
+					continue
+				}
+				allocationData.Projects[value] = ProjectAllocation{
+					ProjectName: value,
+					TotalCost:   group.TotalCost,
+					Resources:   group.Resources,
 				}
 			}
 		}
@@ -454,28 +663,42 @@ func (s *CostManagementService) GetCostOptimizationRecommendations(ctx context.C
 		})
 	}
 
-	// Add reserved instance recommendations
-	if totalCost > 1000 {
-		recommendations = append(recommendations, CostRecommendation{
-			Type:             "reserved_instances",
-			Description:      "Consider purchasing Reserved Instances for stable workloads to reduce costs by up to 60%",
-			PotentialSavings: totalCost * 0.25, // 25% potential savings
-			Priority:         "high",
-			Action:           "Analyze usage patterns and purchase Reserved Instances for predictable workloads",
-		})
+	// Add reserved instance recommendations for resources that run continuously
+	reservations, err := s.reservationRecommendations(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze reservation coverage: %w", err)
+	}
+	recommendations = append(recommendations, reservations...)
+
+	// Add cleanup recommendations for idle and orphaned resources
+	orphans, err := s.orphanedResourceRecommendations(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect orphaned resources: %w", err)
 	}
+	recommendations = append(recommendations, orphans...)
 
 	return recommendations, nil
 }
 
 // BudgetAlert represents a budget-related alert
 type BudgetAlert struct {
-	Type        string    `json:"type"`
-	Message     string    `json:"message"`
-	Severity    string    `json:"severity"`
-	CurrentCost float64   `json:"currentCost"`
-	Budget      float64   `json:"budget"`
-	Timestamp   time.Time `json:"timestamp"`
+	Type        string       `json:"type"`
+	Message     string       `json:"message"`
+	Severity    string       `json:"severity"`
+	CurrentCost float64      `json:"currentCost"`
+	Budget      float64      `json:"budget"`
+	Anomaly     *CostAnomaly `json:"anomaly,omitempty"`
+	Timestamp   time.Time    `json:"timestamp"`
+}
+
+// CostAnomaly explains why a cost_anomaly alert fired
+type CostAnomaly struct {
+	DailySpend float64 `json:"dailySpend"`
+	Baseline   float64 `json:"baseline"`
+	StdDev     float64 `json:"stdDev"`
+	Deviation  float64 `json:"deviation"`
+	Threshold  float64 `json:"threshold"`
+	SampleDays int     `json:"sampleDays"`
 }
 
 // CostForecast represents a cost forecast for a specific date
@@ -496,10 +719,12 @@ type RealTimeCostData struct {
 	LastUpdated     time.Time     `json:"lastUpdated"`
 }
 
-// CostAllocationData represents cost allocation by tags and projects
+// CostAllocationData represents cost allocation by tags, by the organization's allocation
+// dimensions, and by project, from the project dimension when the organization allocates by it
 type CostAllocationData struct {
 	TotalCost       float64                      `json:"totalCost"`
 	AllocationByTag map[string]TagAllocation     `json:"allocationByTag"`
+	Dimensions      []DimensionAllocation        `json:"dimensions"`
 	Projects        map[string]ProjectAllocation `json:"projects"`
 }
 
@@ -518,32 +743,23 @@ type ProjectAllocation struct {
 }
 
 // Helper functions
+func projectedDailySpend(breakdown *CostBreakdown) float64 {
+	total := 0.0
+	for _, resource := range breakdown.Breakdown {
+		total += resource.DailyCost
+	}
+	return total
+}
+
 func (s *CostManagementService) convertTags(tags []string) map[string]string {
 	result := make(map[string]string)
 	for _, tag := range tags {
-		// Assume tags are in format "key=value"
-		// For simplicity, we'll just use the tag as both key and value
-		result[tag] = tag
+		key, value := ParseTag(tag)
+		result[key] = value
 	}
 	return result
 }
 
-func (s *CostManagementService) matchesTags(resourceTags []string, filterTags map[string]string) bool {
-	if len(filterTags) == 0 {
-		return true
-	}
-
-	resourceTagMap := s.convertTags(resourceTags)
-
-	for key, value := range filterTags {
-		if resourceValue, exists := resourceTagMap[key]; !exists || resourceValue != value {
-			return false
-		}
-	}
-
-	return true
-}
-
 func (s *CostManagementService) generateCostTrends(ctx context.Context, orgID string, days int) []CostTrend {
 	var trends []CostTrend
 	currentDate := time.Now()
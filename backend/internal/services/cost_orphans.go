@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"cloudweave/internal/repositories"
+)
+
+// Kinds of orphaned resources
+const (
+	OrphanUnattachedVolume = "unattached_volume"
+	OrphanUnassociatedIP   = "unassociated_ip"
+	OrphanStoppedInstance  = "stopped_instance"
+	OrphanIdleBucket       = "idle_bucket"
+)
+
+// OrphanedResource is a cloud resource that keeps incurring cost without being used
+type OrphanedResource struct {
+	Kind         string            `json:"kind"`
+	ExternalID   string            `json:"externalId"`
+	Region       string            `json:"region"`
+	Reason       string            `json:"reason"`
+	MonthlyWaste float64           `json:"monthlyWaste"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+// orphanDetectionUnsupported is embedded by cloud providers that cannot enumerate orphaned
+// resources yet, so they report none
+type orphanDetectionUnsupported struct{}
+
+// ListOrphanedResources reports no orphaned resources
+func (orphanDetectionUnsupported) ListOrphanedResources(ctx context.Context) ([]OrphanedResource, error) {
+	return nil, nil
+}
+
+// orphanedResourceRecommendations recommends removing orphaned resources found at the providers
+// that belong to an organization, either because they are its infrastructure or because they are
+// tagged with the ID of its infrastructure
+func (s *CostManagementService) orphanedResourceRecommendations(ctx context.Context, orgID string) ([]CostRecommendation, error) {
+	infrastructures, err := s.repoManager.Infrastructure.List(ctx, orgID, repositories.ListParams{
+		Limit:  1000,
+		Offset: 0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	owned := make(map[string]map[string]bool)
+	for _, infra := range infrastructures {
+		if owned[infra.Provider] == nil {
+			owned[infra.Provider] = make(map[string]bool)
+		}
+		owned[infra.Provider][infra.ID] = true
+		if infra.ExternalID != nil {
+			owned[infra.Provider][*infra.ExternalID] = true
+		}
+	}
+
+	providers, err := s.awsAccounts.providersFor(ctx, orgID, s.providers)
+	if err != nil {
+		return nil, err
+	}
+
+	var recommendations []CostRecommendation
+	for _, name := range sortedKeys(owned) {
+		provider, exists := providers[name]
+		if !exists {
+			continue
+		}
+
+		orphans, err := providerValue(ctx, provider.ListOrphanedResources)
+		if err != nil {
+			log.Printf("Failed to list orphaned %s resources: %v", name, err)
+			continue
+		}
+
+		for _, orphan := range orphans {
+			if !owned[name][orphan.ExternalID] && !owned[name][orphan.Tags[InfrastructureIDTagKey]] && !owned[name][orphan.Tags["CloudWeave-ID"]] {
+				continue
+			}
+
+			recommendations = append(recommendations, CostRecommendation{
+				Type:             "orphaned_resource",
+				Description:      fmt.Sprintf("%s %s in %s is %s, wasting $%.2f per month", name, orphan.ExternalID, orphan.Region, orphan.Reason, orphan.MonthlyWaste),
+				PotentialSavings: orphan.MonthlyWaste,
+				Priority:         "high",
+				Action:           orphanAction(orphan.Kind),
+				Orphan:           &orphan,
+			})
+		}
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].PotentialSavings > recommendations[j].PotentialSavings
+	})
+
+	return recommendations, nil
+}
+
+// orphanAction describes how to clean up an orphaned resource of a kind
+func orphanAction(kind string) string {
+	switch kind {
+	case OrphanUnattachedVolume:
+		return "Snapshot the volume if its data is needed, then delete it"
+	case OrphanUnassociatedIP:
+		return "Release the unused IP address"
+	case OrphanStoppedInstance:
+		return "Terminate the instance, or snapshot and delete its volumes if it is no longer needed"
+	case OrphanIdleBucket:
+		return "Archive the bucket to a colder storage class or delete it"
+	default:
+		return "Review and remove the unused resource"
+	}
+}
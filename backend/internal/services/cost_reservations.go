@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// ErrReservedPricingUnavailable is returned when a resource's reservation pricing cannot be found
+var ErrReservedPricingUnavailable = errors.New("reserved pricing unavailable")
+
+const (
+	// reservationLookback is how far back status history must show a resource running continuously
+	reservationLookback = 30 * 24 * time.Hour
+
+	// reservationMinUptime is the fraction of the lookback a resource must have been running
+	reservationMinUptime = 0.95
+
+	// reservationMaxStops is the most times a resource may have stopped during the lookback
+	reservationMaxStops = 2
+
+	hoursPerYear  = 8760
+	hoursPerMonth = hoursPerYear / 12
+)
+
+// ReservationOffer is the price of reserving one instance for a term
+type ReservationOffer struct {
+	Term            string  `json:"term"`
+	PaymentOption   string  `json:"paymentOption"`
+	UpfrontCost     float64 `json:"upfrontCost"`
+	RecurringHourly float64 `json:"recurringHourly"`
+}
+
+// EffectiveHourly spreads the upfront cost of the offer over a year of hours
+func (o ReservationOffer) EffectiveHourly() float64 {
+	return o.UpfrontCost/hoursPerYear + o.RecurringHourly
+}
+
+// ReservedPricing is the on-demand price of an instance type and the 1-year reservations offered for it
+type ReservedPricing struct {
+	OnDemandHourly float64
+	Offers         []ReservationOffer
+}
+
+// ReservedPricingProvider is implemented by cloud providers that can price reservations of their resources
+type ReservedPricingProvider interface {
+	GetReservedPricing(ctx context.Context, infra *models.Infrastructure) (*ReservedPricing, error)
+}
+
+// ReservationPurchase is a specific reservation recommended for resources that run continuously
+type ReservationPurchase struct {
+	Provider        string   `json:"provider"`
+	Region          string   `json:"region"`
+	ResourceType    string   `json:"resourceType"`
+	InstanceType    string   `json:"instanceType"`
+	InstanceFamily  string   `json:"instanceFamily"`
+	Quantity        int      `json:"quantity"`
+	Term            string   `json:"term"`
+	PaymentOption   string   `json:"paymentOption"`
+	OnDemandHourly  float64  `json:"onDemandHourly"`
+	EffectiveHourly float64  `json:"effectiveHourly"`
+	UpfrontCost     float64  `json:"upfrontCost"`
+	AnnualSavings   float64  `json:"annualSavings"`
+	BreakEvenMonths float64  `json:"breakEvenMonths"`
+	ResourceIDs     []string `json:"resourceIds"`
+}
+
+// reservationGroup is a set of continuously running resources that one reservation purchase covers
+type reservationGroup struct {
+	provider     string
+	region       string
+	resourceType string
+	instanceType string
+	resources    []*models.Infrastructure
+}
+
+// reservationRecommendations recommends 1-year reservations for resources whose status history
+// shows them running continuously over the lookback, priced with the provider's reservation
+// offers. Resources that stopped frequently, or whose history does not cover the lookback, are
+// skipped.
+func (s *CostManagementService) reservationRecommendations(ctx context.Context, orgID string) ([]CostRecommendation, error) {
+	infrastructures, err := s.repoManager.Infrastructure.ListByStatus(ctx, orgID, models.InfraStatusRunning, repositories.ListParams{
+		Limit:  1000,
+		Offset: 0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	now := time.Now()
+	since := now.Add(-reservationLookback)
+	history, err := s.repoManager.Infrastructure.ListStatusHistory(ctx, orgID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string]*reservationGroup)
+	for _, infra := range infrastructures {
+		if _, ok := s.providers[infra.Provider].(ReservedPricingProvider); !ok {
+			continue
+		}
+		instanceType := reservationInstanceType(infra)
+		if instanceType == "" {
+			continue
+		}
+
+		uptime, stops, covered := reservationUptime(history[infra.ID], since, now)
+		if !covered || uptime < reservationMinUptime || stops > reservationMaxStops {
+			continue
+		}
+
+		engine, _ := infra.Specifications["engine"].(string)
+		key := strings.Join([]string{infra.Provider, infra.Region, infra.Type, instanceType, engine}, "|")
+		group, exists := groups[key]
+		if !exists {
+			group = &reservationGroup{
+				provider:     infra.Provider,
+				region:       infra.Region,
+				resourceType: infra.Type,
+				instanceType: instanceType,
+			}
+			groups[key] = group
+		}
+		group.resources = append(group.resources, infra)
+	}
+
+	var recommendations []CostRecommendation
+	for _, key := range sortedKeys(groups) {
+		group := groups[key]
+		pricer := s.providers[group.provider].(ReservedPricingProvider)
+
+		pricing, err := providerValue(ctx, func(ctx context.Context) (*ReservedPricing, error) {
+			return pricer.GetReservedPricing(ctx, group.resources[0])
+		})
+		if err != nil {
+			log.Printf("Failed to get reserved pricing for %s %s in %s: %v", group.provider, group.instanceType, group.region, err)
+			continue
+		}
+
+		purchase, ok := bestReservation(group, pricing)
+		if !ok {
+			continue
+		}
+
+		monthlySavings := purchase.AnnualSavings / 12
+		priority := "medium"
+		if monthlySavings >= 100 {
+			priority = "high"
+		}
+
+		recommendations = append(recommendations, CostRecommendation{
+			Type: "reserved_instances",
+			Description: fmt.Sprintf("%d %s %s (%s family) in %s ran continuously for the last %d days",
+				purchase.Quantity, purchase.InstanceType, group.resourceType, purchase.InstanceFamily, purchase.Region, int(reservationLookback.Hours()/24)),
+			PotentialSavings: monthlySavings,
+			Priority:         priority,
+			Action: fmt.Sprintf("Purchase %d x %s %s %s reservation for %s in %s; saves $%.2f per year and breaks even after %.1f months",
+				purchase.Quantity, purchase.Term, purchase.PaymentOption, purchase.InstanceType, group.provider, purchase.Region, purchase.AnnualSavings, purchase.BreakEvenMonths),
+			Purchase: purchase,
+		})
+	}
+
+	return recommendations, nil
+}
+
+// bestReservation picks the offer saving the most over on-demand pricing for a group, reporting
+// false when no offer is cheaper than running on demand
+func bestReservation(group *reservationGroup, pricing *ReservedPricing) (*ReservationPurchase, bool) {
+	var best *ReservationPurchase
+	for _, offer := range pricing.Offers {
+		effective := offer.EffectiveHourly()
+		savingsPerInstance := (pricing.OnDemandHourly - effective) * hoursPerYear
+		if savingsPerInstance <= 0 {
+			continue
+		}
+
+		quantity := len(group.resources)
+		if best != nil && savingsPerInstance*float64(quantity) <= best.AnnualSavings {
+			continue
+		}
+
+		resourceIDs := make([]string, 0, quantity)
+		for _, infra := range group.resources {
+			resourceIDs = append(resourceIDs, infra.ID)
+		}
+
+		best = &ReservationPurchase{
+			Provider:        group.provider,
+			Region:          group.region,
+			ResourceType:    group.resourceType,
+			InstanceType:    group.instanceType,
+			InstanceFamily:  instanceFamily(group.instanceType),
+			Quantity:        quantity,
+			Term:            offer.Term,
+			PaymentOption:   offer.PaymentOption,
+			OnDemandHourly:  pricing.OnDemandHourly,
+			EffectiveHourly: effective,
+			UpfrontCost:     offer.UpfrontCost * float64(quantity),
+			AnnualSavings:   savingsPerInstance * float64(quantity),
+			// A year of the reservation costs as much as this many months on demand
+			BreakEvenMonths: effective * hoursPerYear / pricing.OnDemandHourly / hoursPerMonth,
+			ResourceIDs:     resourceIDs,
+		}
+	}
+
+	return best, best != nil
+}
+
+// reservationUptime measures the fraction of a period a resource was running and how many times
+// it stopped, from its status changes oldest first. covered is false when the history does not
+// reach back to the start of the period.
+func reservationUptime(changes []*models.InfrastructureStatusChange, since, until time.Time) (uptime float64, stops int, covered bool) {
+	if len(changes) == 0 || changes[0].ChangedAt.After(since) {
+		return 0, 0, false
+	}
+
+	var running time.Duration
+	for i, change := range changes {
+		start := change.ChangedAt
+		if start.Before(since) {
+			start = since
+		}
+		end := until
+		if i+1 < len(changes) {
+			end = changes[i+1].ChangedAt
+		}
+
+		if change.Status == models.InfraStatusRunning {
+			running += end.Sub(start)
+		} else if i > 0 && changes[i-1].Status == models.InfraStatusRunning {
+			stops++
+		}
+	}
+
+	return running.Seconds() / until.Sub(since).Seconds(), stops, true
+}
+
+// reservationInstanceType returns the instance size a resource's reservation would be bought for
+func reservationInstanceType(infra *models.Infrastructure) string {
+	for _, key := range []string{"instance_type", "db_instance_class", "vm_size", "machine_type"} {
+		if instanceType, ok := infra.Specifications[key].(string); ok && instanceType != "" {
+			return instanceType
+		}
+	}
+	return ""
+}
+
+// instanceFamily strips the size from an instance type, e.g. t3 for t3.large and db.r5 for db.r5.xlarge
+func instanceFamily(instanceType string) string {
+	if i := strings.LastIndex(instanceType, "."); i > 0 {
+		return instanceType[:i]
+	}
+	return instanceType
+}
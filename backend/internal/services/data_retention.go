@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// ErrInvalidRetentionSettings is returned when retention settings would purge rollups before the
+// data they summarize
+var ErrInvalidRetentionSettings = errors.New("invalid retention settings")
+
+// Default retention in days, for organizations that have not chosen their own
+const (
+	DefaultRawMetricsRetentionDays     = 7
+	DefaultHourlyMetricsRetentionDays  = 90
+	DefaultDailyMetricsRetentionDays   = 730
+	DefaultResolvedAlertsRetentionDays = 90
+)
+
+// retentionPurgeBatchSize is how many rows one purge statement deletes, so no statement holds
+// its locks for long
+const retentionPurgeBatchSize = 5000
+
+// RetentionService keeps metrics and resolved alerts for as long as each organization's retention
+// settings say, and purges them after
+type RetentionService struct {
+	repoManager *repositories.RepositoryManager
+	defaults    models.RetentionSettings
+}
+
+// NewRetentionService creates a new retention service with the default retention
+func NewRetentionService(repoManager *repositories.RepositoryManager) *RetentionService {
+	return &RetentionService{
+		repoManager: repoManager,
+		defaults: models.RetentionSettings{
+			RawMetricsDays:     DefaultRawMetricsRetentionDays,
+			HourlyMetricsDays:  DefaultHourlyMetricsRetentionDays,
+			DailyMetricsDays:   DefaultDailyMetricsRetentionDays,
+			ResolvedAlertsDays: DefaultResolvedAlertsRetentionDays,
+			IsDefault:          true,
+		},
+	}
+}
+
+// SetDefaultRetention sets the retention of organizations that have not chosen their own. Each
+// duration is rounded up to whole days, and rollups are kept at least as long as raw metrics.
+func (s *RetentionService) SetDefaultRetention(rawMetrics, hourlyMetrics, dailyMetrics, resolvedAlerts time.Duration) {
+	if days := retentionDays(rawMetrics); days > 0 {
+		s.defaults.RawMetricsDays = days
+	}
+	if days := retentionDays(hourlyMetrics); days > 0 {
+		s.defaults.HourlyMetricsDays = days
+	}
+	if days := retentionDays(dailyMetrics); days > 0 {
+		s.defaults.DailyMetricsDays = days
+	}
+	if days := retentionDays(resolvedAlerts); days > 0 {
+		s.defaults.ResolvedAlertsDays = days
+	}
+
+	s.defaults.HourlyMetricsDays = max(s.defaults.HourlyMetricsDays, s.defaults.RawMetricsDays)
+	s.defaults.DailyMetricsDays = max(s.defaults.DailyMetricsDays, s.defaults.HourlyMetricsDays)
+}
+
+// GetRetentionSettings retrieves an organization's retention settings, with the default retention
+// if it has not chosen its own
+func (s *RetentionService) GetRetentionSettings(ctx context.Context, orgID string) (*models.RetentionSettings, error) {
+	settings, err := s.repoManager.Retention.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		defaults := s.defaults
+		defaults.OrganizationID = orgID
+		settings = &defaults
+	}
+	return settings, nil
+}
+
+// UpdateRetentionSettings replaces an organization's retention settings
+func (s *RetentionService) UpdateRetentionSettings(ctx context.Context, orgID, userID string, req *models.UpdateRetentionSettingsRequest) (*models.RetentionSettings, error) {
+	if req.RawMetricsDays > req.HourlyMetricsDays || req.HourlyMetricsDays > req.DailyMetricsDays {
+		return nil, fmt.Errorf("%w: hourly rollups must be kept at least as long as raw metrics, and daily rollups at least as long as hourly rollups", ErrInvalidRetentionSettings)
+	}
+
+	settings := &models.RetentionSettings{
+		OrganizationID:     orgID,
+		RawMetricsDays:     req.RawMetricsDays,
+		HourlyMetricsDays:  req.HourlyMetricsDays,
+		DailyMetricsDays:   req.DailyMetricsDays,
+		ResolvedAlertsDays: req.ResolvedAlertsDays,
+	}
+	if userID != "" {
+		settings.UpdatedBy = &userID
+	}
+
+	if err := s.repoManager.Retention.Save(ctx, settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// PreviewPurge counts the organization's records the retention purge would delete if it ran now,
+// without deleting anything
+func (s *RetentionService) PreviewPurge(ctx context.Context, orgID string) (*models.RetentionPreview, error) {
+	settings, err := s.GetRetentionSettings(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	preview := &models.RetentionPreview{GeneratedAt: now}
+
+	watermarks, err := s.rollupWatermarks(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := []struct {
+		resolution string
+		days       int
+		count      *int64
+	}{
+		{models.MetricResolutionRaw, settings.RawMetricsDays, &preview.RawMetrics},
+		{models.MetricResolutionHourly, settings.HourlyMetricsDays, &preview.HourlyMetrics},
+		{models.MetricResolutionDaily, settings.DailyMetricsDays, &preview.DailyMetrics},
+	}
+	for _, c := range counts {
+		watermark := watermarks[c.resolution]
+		if watermark == nil {
+			continue
+		}
+
+		cutoff := now.AddDate(0, 0, -c.days)
+		if watermark.Before(cutoff) {
+			cutoff = *watermark
+		}
+
+		if *c.count, err = s.repoManager.Retention.CountExpiredMetrics(ctx, c.resolution, orgID, cutoff); err != nil {
+			return nil, err
+		}
+	}
+
+	preview.ResolvedAlerts, err = s.repoManager.Retention.CountExpiredAlerts(ctx, orgID, now.AddDate(0, 0, -settings.ResolvedAlertsDays))
+	if err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+// PurgeExpired deletes metrics and resolved alerts past their organization's retention, in
+// batches. Raw metrics and hourly rollups are kept until they have been rolled up further, so a
+// purge never loses data the rollups do not hold yet.
+func (s *RetentionService) PurgeExpired(ctx context.Context) error {
+	watermarks, err := s.rollupWatermarks(ctx, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	defaultDays := map[string]int{
+		models.MetricResolutionRaw:    s.defaults.RawMetricsDays,
+		models.MetricResolutionHourly: s.defaults.HourlyMetricsDays,
+		models.MetricResolutionDaily:  s.defaults.DailyMetricsDays,
+	}
+	for _, resolution := range []string{models.MetricResolutionRaw, models.MetricResolutionHourly, models.MetricResolutionDaily} {
+		watermark := watermarks[resolution]
+		if watermark == nil {
+			continue
+		}
+
+		deleted, err := purgeInBatches(ctx, func(limit int) (int64, error) {
+			return s.repoManager.Retention.DeleteExpiredMetrics(ctx, resolution, defaultDays[resolution], *watermark, limit)
+		})
+		if deleted > 0 {
+			log.Printf("Purged %d expired %s metrics", deleted, resolution)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	deleted, err := purgeInBatches(ctx, func(limit int) (int64, error) {
+		return s.repoManager.Retention.DeleteExpiredAlerts(ctx, s.defaults.ResolvedAlertsDays, limit)
+	})
+	if deleted > 0 {
+		log.Printf("Purged %d expired resolved alerts", deleted)
+	}
+	return err
+}
+
+// StartRetentionPurge periodically purges data past its retention until ctx is cancelled
+func (s *RetentionService) StartRetentionPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.PurgeExpired(ctx); err != nil {
+				log.Printf("Retention purge failed: %v", err)
+			}
+		}
+	}
+}
+
+// rollupWatermarks returns, for each metric resolution, the time before which its data may be
+// purged: raw metrics up to the latest hourly bucket, which is recomputed on every rollup, and
+// hourly rollups up to the latest daily bucket. A resolution whose data has not been rolled up
+// yet has no watermark.
+func (s *RetentionService) rollupWatermarks(ctx context.Context, now time.Time) (map[string]*time.Time, error) {
+	latestHourly, err := s.repoManager.Metric.GetLatestRollupBucket(ctx, models.MetricResolutionHourly)
+	if err != nil {
+		return nil, err
+	}
+	latestDaily, err := s.repoManager.Metric.GetLatestRollupBucket(ctx, models.MetricResolutionDaily)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]*time.Time{
+		models.MetricResolutionRaw:    latestHourly,
+		models.MetricResolutionHourly: latestDaily,
+		models.MetricResolutionDaily:  &now,
+	}, nil
+}
+
+// purgeInBatches runs a batched delete until a batch comes back short, returning how many rows
+// were deleted in total. Each batch is its own statement, so a purge can stop between batches
+// and pick up where it left off on the next run.
+func purgeInBatches(ctx context.Context, deleteBatch func(limit int) (int64, error)) (int64, error) {
+	var total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		deleted, err := deleteBatch(retentionPurgeBatchSize)
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+		if deleted < retentionPurgeBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// retentionDays rounds a retention duration up to whole days
+func retentionDays(d time.Duration) int {
+	const day = 24 * time.Hour
+	return int((d + day - 1) / day)
+}
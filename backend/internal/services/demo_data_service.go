@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"math"
 	"math/rand"
+	"sort"
 	"time"
 
 	"cloudweave/internal/models"
@@ -20,8 +23,15 @@ type DemoDataService struct {
 	metricRepo       repositories.MetricRepositoryInterface
 	alertRepo        repositories.AlertRepositoryInterface
 	demoDataRepo     *repositories.DemoDataRepository
+	metricsRange     time.Duration
 }
 
+// DefaultDemoMetricsRange is how much metric history is generated for a new demo data set
+const DefaultDemoMetricsRange = 30 * 24 * time.Hour
+
+// maxDemoMetricPoints caps the points generated per metric series so long ranges stay small
+const maxDemoMetricPoints = 2000
+
 func NewDemoDataService(
 	userRepo repositories.UserRepositoryInterface,
 	infraRepo repositories.InfrastructureRepositoryInterface,
@@ -37,6 +47,14 @@ func NewDemoDataService(
 		metricRepo:     metricRepo,
 		alertRepo:      alertRepo,
 		demoDataRepo:   demoDataRepo,
+		metricsRange:   DefaultDemoMetricsRange,
+	}
+}
+
+// SetMetricsRange changes how much metric history is generated for new demo data sets
+func (s *DemoDataService) SetMetricsRange(metricsRange time.Duration) {
+	if metricsRange > 0 {
+		s.metricsRange = metricsRange
 	}
 }
 
@@ -106,18 +124,24 @@ func (s *DemoDataService) GetDemoDeployments(ctx context.Context, userID string)
 	return deployments, nil
 }
 
-// GetDemoMetrics retrieves demo metrics data
+// GetDemoMetrics retrieves demo metrics data. Parts of the requested range the stored data does
+// not cover are generated on demand.
 func (s *DemoDataService) GetDemoMetrics(ctx context.Context, userID string, timeRange TimeRange) ([]*models.DemoMetric, error) {
-	data, err := s.GetDemoData(ctx, userID, "metrics")
+	demoData, err := s.demoDataRepo.GetByUserAndType(ctx, userID, "metrics")
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to get demo data: %w", err)
 	}
 
 	var metrics []*models.DemoMetric
-	if err := s.unmarshalData(data, &metrics); err != nil {
+	if err := s.unmarshalData(demoData.Data, &metrics); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal metrics data: %w", err)
 	}
 
+	metrics = append(metrics, s.generateUncoveredMetrics(userID, demoData.Scenario, metrics, timeRange)...)
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Timestamp.After(metrics[j].Timestamp)
+	})
+
 	// Filter metrics by time range if specified
 	if !timeRange.Start.IsZero() || !timeRange.End.IsZero() {
 		filtered := make([]*models.DemoMetric, 0)
@@ -133,6 +157,42 @@ func (s *DemoDataService) GetDemoMetrics(ctx context.Context, userID string, tim
 	return metrics, nil
 }
 
+// generateUncoveredMetrics generates metrics for the parts of the requested range that fall
+// before the oldest or after the newest stored point. An open-ended range extends to now.
+func (s *DemoDataService) generateUncoveredMetrics(userID string, scenario models.DemoScenario, stored []*models.DemoMetric, timeRange TimeRange) []*models.DemoMetric {
+	end := timeRange.End
+	if end.IsZero() || end.After(time.Now()) {
+		end = time.Now()
+	}
+
+	if len(stored) == 0 {
+		if timeRange.Start.IsZero() {
+			return nil
+		}
+		return s.generateMetrics(userID, scenario, TimeRange{Start: timeRange.Start, End: end})
+	}
+
+	oldest, newest := stored[0].Timestamp, stored[0].Timestamp
+	for _, metric := range stored {
+		if metric.Timestamp.Before(oldest) {
+			oldest = metric.Timestamp
+		}
+		if metric.Timestamp.After(newest) {
+			newest = metric.Timestamp
+		}
+	}
+
+	var generated []*models.DemoMetric
+	if !timeRange.Start.IsZero() && timeRange.Start.Before(oldest) {
+		generated = append(generated, s.generateMetrics(userID, scenario, TimeRange{Start: timeRange.Start, End: oldest.Add(-time.Nanosecond)})...)
+	}
+	if end.Sub(newest) >= time.Hour {
+		generated = append(generated, s.generateMetrics(userID, scenario, TimeRange{Start: newest.Add(time.Hour), End: end})...)
+	}
+
+	return generated
+}
+
 // GetDemoAlerts retrieves demo alert data
 func (s *DemoDataService) GetDemoAlerts(ctx context.Context, userID string) ([]*models.DemoAlert, error) {
 	data, err := s.GetDemoData(ctx, userID, "alerts")
@@ -177,6 +237,57 @@ func (s *DemoDataService) ClearDemoData(ctx context.Context, userID string) erro
 	return nil
 }
 
+// CleanupExpired purges expired demo data and takes the owning users out of demo mode once none
+// of their demo data remains. It returns the number of users transitioned out.
+func (s *DemoDataService) CleanupExpired(ctx context.Context) (int, error) {
+	userIDs, err := s.demoDataRepo.DeleteExpired(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	transitioned := 0
+	for _, userID := range userIDs {
+		remaining, err := s.demoDataRepo.GetByUser(ctx, userID)
+		if err != nil {
+			log.Printf("Failed to check remaining demo data for user %s: %v", userID, err)
+			continue
+		}
+		if len(remaining) > 0 {
+			continue
+		}
+
+		if err := s.userRepo.UpdateDemoSettings(ctx, userID, false, ""); err != nil {
+			log.Printf("Failed to reset demo settings for user %s: %v", userID, err)
+			continue
+		}
+		transitioned++
+	}
+
+	return transitioned, nil
+}
+
+// StartExpiryCleanup periodically purges expired demo data until ctx is cancelled
+func (s *DemoDataService) StartExpiryCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.CleanupExpired(ctx)
+			if err != nil {
+				log.Printf("Demo data expiry cleanup failed: %v", err)
+				continue
+			}
+			if count > 0 {
+				log.Printf("Transitioned %d users out of expired demo mode", count)
+			}
+		}
+	}
+}
+
 // TransitionToReal transitions a user from demo mode to real data
 func (s *DemoDataService) TransitionToReal(ctx context.Context, userID string, keepSettings bool) error {
 	// Clear demo data
@@ -210,30 +321,31 @@ func (s *DemoDataService) generateDemoDataSet(userID string, scenario models.Dem
 		GeneratedAt: now,
 		ExpiresAt:   &expiresAt,
 	}
+	metricsRange := TimeRange{Start: now.Add(-s.metricsRange), End: now}
 
 	switch scenario {
 	case models.DemoScenarioStartup:
 		dataSet.Infrastructure = s.generateStartupInfrastructure(userID)
 		dataSet.Deployments = s.generateStartupDeployments(userID)
-		dataSet.Metrics = s.generateStartupMetrics(userID)
+		dataSet.Metrics = s.generateStartupMetrics(userID, metricsRange)
 		dataSet.Alerts = s.generateStartupAlerts(userID)
 		dataSet.CostData = s.generateStartupCostData()
 	case models.DemoScenarioEnterprise:
 		dataSet.Infrastructure = s.generateEnterpriseInfrastructure(userID)
 		dataSet.Deployments = s.generateEnterpriseDeployments(userID)
-		dataSet.Metrics = s.generateEnterpriseMetrics(userID)
+		dataSet.Metrics = s.generateEnterpriseMetrics(userID, metricsRange)
 		dataSet.Alerts = s.generateEnterpriseAlerts(userID)
 		dataSet.CostData = s.generateEnterpriseCostData()
 	case models.DemoScenarioDevOps:
 		dataSet.Infrastructure = s.generateDevOpsInfrastructure(userID)
 		dataSet.Deployments = s.generateDevOpsDeployments(userID)
-		dataSet.Metrics = s.generateDevOpsMetrics(userID)
+		dataSet.Metrics = s.generateDevOpsMetrics(userID, metricsRange)
 		dataSet.Alerts = s.generateDevOpsAlerts(userID)
 		dataSet.CostData = s.generateDevOpsCostData()
 	case models.DemoScenarioMultiCloud:
 		dataSet.Infrastructure = s.generateMultiCloudInfrastructure(userID)
 		dataSet.Deployments = s.generateMultiCloudDeployments(userID)
-		dataSet.Metrics = s.generateMultiCloudMetrics(userID)
+		dataSet.Metrics = s.generateMultiCloudMetrics(userID, metricsRange)
 		dataSet.Alerts = s.generateMultiCloudAlerts(userID)
 		dataSet.CostData = s.generateMultiCloudCostData()
 	default:
@@ -331,7 +443,7 @@ func (s *DemoDataService) generateStartupInfrastructure(userID string) []*models
 					"hourlyRate":   0.0104,
 					"monthlyRate":  7.59,
 				},
-				Tags:       []string{"web", "frontend", "demo"},
+				Tags:       []string{"environment=demo", "role=web", "tier=frontend"},
 				ExternalID: &[]string{"i-1234567890abcdef0"}[0],
 				CreatedAt:  now.Add(-72 * time.Hour),
 				UpdatedAt:  now.Add(-1 * time.Hour),
@@ -363,7 +475,7 @@ func (s *DemoDataService) generateStartupInfrastructure(userID string) []*models
 					"hourlyRate":   0.017,
 					"monthlyRate":  12.41,
 				},
-				Tags:       []string{"database", "postgresql", "demo"},
+				Tags:       []string{"engine=postgresql", "environment=demo", "role=database"},
 				ExternalID: &[]string{"demo-db-instance-1"}[0],
 				CreatedAt:  now.Add(-48 * time.Hour),
 				UpdatedAt:  now.Add(-2 * time.Hour),
@@ -452,14 +564,13 @@ func (s *DemoDataService) generateStartupDeployments(userID string) []*models.De
 }
 
 // generateStartupMetrics generates metrics data for startup scenario
-func (s *DemoDataService) generateStartupMetrics(userID string) []*models.DemoMetric {
-	now := time.Now()
+func (s *DemoDataService) generateStartupMetrics(userID string, timeRange TimeRange) []*models.DemoMetric {
 	metrics := make([]*models.DemoMetric, 0)
+	interval := demoMetricInterval(timeRange)
 
-	// Generate CPU metrics for the last 24 hours
-	for i := 0; i < 24; i++ {
-		timestamp := now.Add(-time.Duration(i) * time.Hour)
-		cpuValue := 15.0 + rand.Float64()*20.0 // 15-35% CPU usage
+	// Generate CPU and memory metrics covering the time range, newest first
+	for timestamp := timeRange.End; !timestamp.Before(timeRange.Start); timestamp = timestamp.Add(-interval) {
+		cpuValue := demoSeasonalValue(timestamp, 25.0, 10.0, 4.0) // ~15-35% CPU usage
 
 		metrics = append(metrics, &models.DemoMetric{
 			Metric: &models.Metric{
@@ -486,7 +597,7 @@ func (s *DemoDataService) generateStartupMetrics(userID string) []*models.DemoMe
 		})
 
 		// Memory metrics
-		memoryValue := 45.0 + rand.Float64()*25.0 // 45-70% memory usage
+		memoryValue := demoSeasonalValue(timestamp, 57.0, 8.0, 3.0) // ~45-70% memory usage
 		metrics = append(metrics, &models.DemoMetric{
 			Metric: &models.Metric{
 				ID:           uuid.New().String(),
@@ -515,6 +626,46 @@ func (s *DemoDataService) generateStartupMetrics(userID string) []*models.DemoMe
 	return metrics
 }
 
+// generateMetrics generates a scenario's metrics for a time range
+func (s *DemoDataService) generateMetrics(userID string, scenario models.DemoScenario, timeRange TimeRange) []*models.DemoMetric {
+	switch scenario {
+	case models.DemoScenarioEnterprise:
+		return s.generateEnterpriseMetrics(userID, timeRange)
+	case models.DemoScenarioDevOps:
+		return s.generateDevOpsMetrics(userID, timeRange)
+	case models.DemoScenarioMultiCloud:
+		return s.generateMultiCloudMetrics(userID, timeRange)
+	default:
+		return s.generateStartupMetrics(userID, timeRange)
+	}
+}
+
+// demoMetricInterval spaces points hourly, widening the spacing for long ranges so a series
+// never exceeds maxDemoMetricPoints
+func demoMetricInterval(timeRange TimeRange) time.Duration {
+	interval := timeRange.End.Sub(timeRange.Start) / maxDemoMetricPoints
+	if interval < time.Hour {
+		return time.Hour
+	}
+	return interval.Truncate(time.Hour)
+}
+
+// demoSeasonalValue produces a realistic usage value: busiest mid-afternoon UTC, quietest in the
+// early morning, lighter on weekends, with some random noise
+func demoSeasonalValue(timestamp time.Time, base, dailyAmplitude, noise float64) float64 {
+	t := timestamp.UTC()
+	hour := float64(t.Hour()) + float64(t.Minute())/60
+	daily := math.Sin(2 * math.Pi * (hour - 8) / 24)
+
+	weekly := 1.0
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		weekly = 0.7
+	}
+
+	value := base*weekly + dailyAmplitude*daily*weekly + (rand.Float64()*2-1)*noise
+	return math.Max(0, math.Min(100, value))
+}
+
 // generateStartupAlerts generates alert data for startup scenario
 func (s *DemoDataService) generateStartupAlerts(userID string) []*models.DemoAlert {
 	now := time.Now()
@@ -654,8 +805,8 @@ func (s *DemoDataService) generateEnterpriseDeployments(userID string) []*models
 	return s.generateStartupDeployments(userID) // Placeholder
 }
 
-func (s *DemoDataService) generateEnterpriseMetrics(userID string) []*models.DemoMetric {
-	return s.generateStartupMetrics(userID) // Placeholder
+func (s *DemoDataService) generateEnterpriseMetrics(userID string, timeRange TimeRange) []*models.DemoMetric {
+	return s.generateStartupMetrics(userID, timeRange) // Placeholder
 }
 
 func (s *DemoDataService) generateEnterpriseAlerts(userID string) []*models.DemoAlert {
@@ -678,8 +829,8 @@ func (s *DemoDataService) generateDevOpsDeployments(userID string) []*models.Dem
 	return s.generateStartupDeployments(userID) // Placeholder
 }
 
-func (s *DemoDataService) generateDevOpsMetrics(userID string) []*models.DemoMetric {
-	return s.generateStartupMetrics(userID) // Placeholder
+func (s *DemoDataService) generateDevOpsMetrics(userID string, timeRange TimeRange) []*models.DemoMetric {
+	return s.generateStartupMetrics(userID, timeRange) // Placeholder
 }
 
 func (s *DemoDataService) generateDevOpsAlerts(userID string) []*models.DemoAlert {
@@ -702,8 +853,8 @@ func (s *DemoDataService) generateMultiCloudDeployments(userID string) []*models
 	return s.generateStartupDeployments(userID) // Placeholder
 }
 
-func (s *DemoDataService) generateMultiCloudMetrics(userID string) []*models.DemoMetric {
-	return s.generateStartupMetrics(userID) // Placeholder
+func (s *DemoDataService) generateMultiCloudMetrics(userID string, timeRange TimeRange) []*models.DemoMetric {
+	return s.generateStartupMetrics(userID, timeRange) // Placeholder
 }
 
 func (s *DemoDataService) generateMultiCloudAlerts(userID string) []*models.DemoAlert {
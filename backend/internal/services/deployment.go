@@ -3,10 +3,16 @@ package services
 import (
 	"context"
 	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"cloudweave/internal/models"
 	"cloudweave/internal/repositories"
+
+	"github.com/google/uuid"
 )
 
 type DeploymentService struct {
@@ -14,29 +20,59 @@ type DeploymentService struct {
 	orchestrator *DeploymentOrchestrator
 	logger       *DeploymentLogger
 	wsService    *WebSocketService
+	auditWriter  *AuditWriter
+
+	// scheduled holds the timers that start scheduled deployments
+	scheduled     map[string]*time.Timer
+	scheduleMutex sync.Mutex
 }
 
-func NewDeploymentService(repoManager *repositories.RepositoryManager, wsService *WebSocketService) *DeploymentService {
+func NewDeploymentService(repoManager *repositories.RepositoryManager, wsService *WebSocketService, metricsService *MetricsService) *DeploymentService {
 	service := &DeploymentService{
 		repoManager:  repoManager,
-		orchestrator: NewDeploymentOrchestrator(repoManager),
-		logger:       NewDeploymentLogger(repoManager),
+		orchestrator: NewDeploymentOrchestrator(repoManager, wsService, metricsService),
+		logger:       NewDeploymentLogger(repoManager, wsService),
 		wsService:    wsService,
+		scheduled:    make(map[string]*time.Timer),
 	}
+	service.orchestrator.rollback = service.startAutomaticRollback
 
 	return service
 }
 
-// CreateDeployment creates and starts a new deployment
+// SetWebhookService sets the service that posts finished deployments to webhooks
+func (s *DeploymentService) SetWebhookService(webhooks *WebhookService) {
+	s.orchestrator.webhooks = webhooks
+}
+
+// CreateDeployment creates and starts a new deployment, or schedules it when it has a
+// scheduled time
 func (s *DeploymentService) CreateDeployment(ctx context.Context, deployment *models.Deployment) error {
+	if deployment.Strategy == "" {
+		deployment.Strategy = models.DeploymentStrategyRolling
+	}
+
+	if deployment.ScheduledFor != nil {
+		return s.createScheduledDeployment(ctx, deployment)
+	}
+
+	// Only one deployment may run for an application and environment at a time
+	if err := s.acquireDeploymentLock(ctx, deployment); err != nil {
+		return err
+	}
+
 	// Create deployment in database
 	if err := s.repoManager.Deployment.Create(ctx, deployment); err != nil {
+		s.orchestrator.releaseDeploymentLock(deployment.ID)
 		return fmt.Errorf("failed to create deployment: %w", err)
 	}
 
 	// Send WebSocket notification
 	if s.wsService != nil && deployment.CreatedBy != nil {
-		s.wsService.SendDeploymentStatus(*deployment.CreatedBy, deployment.ID, deployment.Status, deployment.Progress, "Deployment created successfully")
+		s.wsService.SendDeploymentStatus(deployment.OrganizationID, *deployment.CreatedBy, deployment.ID, deployment.Status, deployment.Progress, "Deployment created successfully")
+	}
+	if s.wsService != nil {
+		s.wsService.PublishDeploymentStatus(deployment)
 	}
 
 	// Start deployment orchestration in background
@@ -85,17 +121,82 @@ func (s *DeploymentService) GetDeploymentLogs(ctx context.Context, deploymentID
 	return s.logger.GetLogs(ctx, deploymentID)
 }
 
-// RollbackDeployment creates a rollback deployment
-func (s *DeploymentService) RollbackDeployment(ctx context.Context, deploymentID, targetVersion, reason string) (*models.Deployment, error) {
+// RollbackDeployment creates a rollback deployment and records the rollback in the original
+// deployment's history. A rollback to a version with unresolved critical vulnerabilities that the
+// original deployment's version fixed is refused, and recorded as refused, unless overridden with
+// a reason.
+func (s *DeploymentService) RollbackDeployment(ctx context.Context, deploymentID, targetVersion, reason, userID string, override bool) (*models.Deployment, error) {
+	if override && strings.TrimSpace(reason) == "" {
+		return nil, ErrRollbackOverrideReason
+	}
+
 	// Get original deployment
 	originalDeployment, err := s.repoManager.Deployment.GetByID(ctx, deploymentID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get original deployment: %w", err)
 	}
 
+	record := &models.DeploymentRollback{
+		Trigger: models.RollbackTriggerManual,
+		Reason:  reason,
+	}
+	if userID != "" {
+		record.InitiatedBy = &userID
+	}
+
+	check, err := s.checkRollbackSafety(ctx, originalDeployment, targetVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check rollback target: %w", err)
+	}
+	record.SafetyCheck = check
+	if len(check.Vulnerabilities) > 0 {
+		if !override {
+			if err := s.refuseRollback(ctx, originalDeployment, targetVersion, record); err != nil {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%w: %s", ErrRollbackReintroducesVulnerabilities, check.Message)
+		}
+		check.Decision = models.RollbackCheckOverridden
+		s.logger.LogWarning(ctx, originalDeployment.ID, fmt.Sprintf("Rollback safety check overridden for version %s", targetVersion), map[string]interface{}{
+			"reason":          reason,
+			"vulnerabilities": reintroducedVulnerabilityList(check.Vulnerabilities),
+		})
+	}
+
+	rollbackDeployment, err := s.startRollback(ctx, originalDeployment, targetVersion, record)
+	if err != nil {
+		return nil, err
+	}
+	s.auditRollback(ctx, originalDeployment, record)
+
+	// Update original deployment status
+	originalDeployment.Status = models.DeploymentStatusRollingBack
+	s.repoManager.Deployment.Update(ctx, originalDeployment)
+
+	return rollbackDeployment, nil
+}
+
+// GetDeploymentDetail retrieves a deployment with its rollback history
+func (s *DeploymentService) GetDeploymentDetail(ctx context.Context, deploymentID string) (*models.DeploymentDetail, error) {
+	deployment, err := s.repoManager.Deployment.GetByID(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	rollbacks, err := s.repoManager.DeploymentRollback.ListByDeployment(ctx, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.DeploymentDetail{Deployment: *deployment, Rollbacks: rollbacks}, nil
+}
+
+// startRollback creates and starts a deployment of targetVersion in place of the original and
+// records the rollback. The original deployment's status is left to the caller.
+func (s *DeploymentService) startRollback(ctx context.Context, originalDeployment *models.Deployment, targetVersion string, record *models.DeploymentRollback) (*models.Deployment, error) {
 	// Create rollback deployment
 	rollbackDeployment := &models.Deployment{
-		ID:             fmt.Sprintf("rollback-%s-%d", deploymentID, time.Now().Unix()),
+		ID:             uuid.New().String(),
 		OrganizationID: originalDeployment.OrganizationID,
 		Name:           fmt.Sprintf("Rollback: %s", originalDeployment.Name),
 		Application:    originalDeployment.Application,
@@ -103,32 +204,173 @@ func (s *DeploymentService) RollbackDeployment(ctx context.Context, deploymentID
 		Environment:    originalDeployment.Environment,
 		Status:         models.DeploymentStatusPending,
 		Progress:       0,
-		Configuration:  originalDeployment.Configuration,
+		Configuration:  make(map[string]interface{}, len(originalDeployment.Configuration)+1),
 		CreatedBy:      originalDeployment.CreatedBy,
+		PipelineID:     originalDeployment.PipelineID,
+		Strategy:       models.DeploymentStrategyRolling,
 	}
 
 	// Add rollback metadata to configuration
-	if rollbackDeployment.Configuration == nil {
-		rollbackDeployment.Configuration = make(map[string]interface{})
+	for key, value := range originalDeployment.Configuration {
+		rollbackDeployment.Configuration[key] = value
 	}
 	rollbackDeployment.Configuration["rollback"] = map[string]interface{}{
-		"originalDeploymentId": deploymentID,
-		"reason":               reason,
+		"originalDeploymentId": originalDeployment.ID,
+		"reason":               record.Reason,
 		"targetVersion":        targetVersion,
+		"trigger":              record.Trigger,
 	}
 
+	// The rollback supersedes the original deployment, so it takes over the target's lock
+	s.orchestrator.releaseDeploymentLock(originalDeployment.ID)
+
 	// Create rollback deployment
 	if err := s.CreateDeployment(ctx, rollbackDeployment); err != nil {
 		return nil, fmt.Errorf("failed to create rollback deployment: %w", err)
 	}
 
-	// Update original deployment status
-	originalDeployment.Status = models.DeploymentStatusRollingBack
-	s.repoManager.Deployment.Update(ctx, originalDeployment)
+	record.ID = uuid.New().String()
+	record.OrganizationID = originalDeployment.OrganizationID
+	record.DeploymentID = originalDeployment.ID
+	record.RollbackDeploymentID = &rollbackDeployment.ID
+	record.FromVersion = originalDeployment.Version
+	record.ToVersion = targetVersion
+	if err := s.repoManager.DeploymentRollback.Create(ctx, record); err != nil {
+		return nil, fmt.Errorf("failed to record rollback: %w", err)
+	}
 
 	return rollbackDeployment, nil
 }
 
+// environmentUptimeWindow is the trailing window environment uptime is calculated over
+const environmentUptimeWindow = 30 * 24 * time.Hour
+
+// GetEnvironmentHealth summarizes every environment the organization deploys to or has defined.
+// Services are the distinct applications deployed to an environment, status reflects the worst
+// latest deployment of those applications, and uptime is the share of the trailing window with
+// no open error or critical deployment alert.
+func (s *DeploymentService) GetEnvironmentHealth(ctx context.Context, orgID string) ([]*models.EnvironmentHealth, error) {
+	latest, err := s.repoManager.Deployment.ListLatestByApplication(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest deployments: %w", err)
+	}
+
+	custom, err := s.repoManager.Environment.List(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get custom environments: %w", err)
+	}
+
+	now := time.Now()
+	since := now.Add(-environmentUptimeWindow)
+	outages, err := s.repoManager.Alert.ListEnvironmentOutages(ctx, orgID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment outages: %w", err)
+	}
+
+	healthByEnv := make(map[string]*models.EnvironmentHealth)
+	var order []string
+	environmentHealth := func(key string) *models.EnvironmentHealth {
+		if health, ok := healthByEnv[key]; ok {
+			return health
+		}
+		name, ok := models.EnvironmentNames[key]
+		if !ok {
+			name = key
+		}
+		health := &models.EnvironmentHealth{
+			ID:     key,
+			Name:   name,
+			Status: models.EnvironmentStatusHealthy,
+		}
+		healthByEnv[key] = health
+		order = append(order, key)
+		return health
+	}
+
+	for _, env := range custom {
+		health := environmentHealth(env.Key)
+		health.Name = env.Name
+		health.Custom = true
+	}
+
+	for _, deployment := range latest {
+		health := environmentHealth(deployment.Environment)
+		health.ServicesCount++
+		if health.LastDeployment == nil || deployment.CreatedAt.After(*health.LastDeployment) {
+			createdAt := deployment.CreatedAt
+			health.LastDeployment = &createdAt
+		}
+		if status := environmentStatusFor(deployment.Status); environmentStatusRank[status] > environmentStatusRank[health.Status] {
+			health.Status = status
+		}
+	}
+
+	outagesByEnv := make(map[string][]*models.EnvironmentOutage)
+	for _, outage := range outages {
+		outagesByEnv[outage.Environment] = append(outagesByEnv[outage.Environment], outage)
+	}
+
+	result := make([]*models.EnvironmentHealth, 0, len(order))
+	for _, key := range order {
+		health := healthByEnv[key]
+		health.Uptime = environmentUptime(outagesByEnv[key], since, now)
+		result = append(result, health)
+	}
+
+	return result, nil
+}
+
+// environmentStatusRank orders environment statuses from best to worst
+var environmentStatusRank = map[string]int{
+	models.EnvironmentStatusHealthy: 0,
+	models.EnvironmentStatusWarning: 1,
+	models.EnvironmentStatusError:   2,
+}
+
+// environmentStatusFor maps a deployment status to the environment status it implies
+func environmentStatusFor(deploymentStatus string) string {
+	switch deploymentStatus {
+	case models.DeploymentStatusFailed:
+		return models.EnvironmentStatusError
+	case models.DeploymentStatusRollingBack, models.DeploymentStatusRolledBack, models.DeploymentStatusCancelled:
+		return models.EnvironmentStatusWarning
+	default:
+		return models.EnvironmentStatusHealthy
+	}
+}
+
+// environmentUptime returns the percentage of the window not covered by outages.
+// Outages are sorted by start time, and overlapping outages are only counted once.
+func environmentUptime(outages []*models.EnvironmentOutage, since, now time.Time) float64 {
+	var downtime time.Duration
+	covered := since
+
+	for _, outage := range outages {
+		start := outage.StartedAt
+		end := now
+		if outage.EndedAt != nil {
+			end = *outage.EndedAt
+		}
+
+		if start.Before(covered) {
+			start = covered
+		}
+		if end.After(now) {
+			end = now
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		downtime += end.Sub(start)
+		covered = end
+	}
+
+	window := now.Sub(since)
+	uptime := 100 * (1 - downtime.Seconds()/window.Seconds())
+	return math.Round(uptime*100) / 100
+}
+
 // CancelDeployment cancels a running deployment
 func (s *DeploymentService) CancelDeployment(ctx context.Context, deploymentID, reason string) error {
 	deployment, err := s.repoManager.Deployment.GetByID(ctx, deploymentID)
@@ -136,6 +378,10 @@ func (s *DeploymentService) CancelDeployment(ctx context.Context, deploymentID,
 		return fmt.Errorf("failed to get deployment: %w", err)
 	}
 
+	if deployment.Status == models.DeploymentStatusScheduled {
+		return s.cancelScheduledDeployment(ctx, deployment, reason)
+	}
+
 	if deployment.Status != models.DeploymentStatusRunning && deployment.Status != models.DeploymentStatusPending {
 		return fmt.Errorf("deployment cannot be cancelled in status: %s", deployment.Status)
 	}
@@ -150,7 +396,46 @@ func (s *DeploymentService) CancelDeployment(ctx context.Context, deploymentID,
 	now := time.Now()
 	deployment.CompletedAt = &now
 
-	return s.repoManager.Deployment.Update(ctx, deployment)
+	if err := s.repoManager.Deployment.Update(ctx, deployment); err != nil {
+		return err
+	}
+
+	s.orchestrator.releaseDeploymentLock(deployment.ID)
+	return nil
+}
+
+// StreamDeploymentLogs upgrades the request to a WebSocket that replays the deployment's logs
+// and then follows new lines until the deployment finishes
+func (s *DeploymentService) StreamDeploymentLogs(w http.ResponseWriter, r *http.Request, userID string, deployment *models.Deployment) error {
+	if s.wsService == nil {
+		return fmt.Errorf("log streaming is not available")
+	}
+
+	logs, err := s.logger.GetLogs(r.Context(), deployment.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get deployment logs: %w", err)
+	}
+
+	backlog := make([]*WebSocketMessage, 0, len(logs)+1)
+	for _, logLine := range logs {
+		backlog = append(backlog, NewDeploymentLogMessage(deployment.OrganizationID, deployment.ID, logLine))
+	}
+
+	// Deployments that already finished have nothing more to stream
+	if isDeploymentFinished(deployment.Status) {
+		backlog = append(backlog, NewDeploymentFinishedMessage(deployment.OrganizationID, deployment.ID, deployment.Status))
+	}
+
+	s.wsService.HandleDeploymentLogStream(w, r, userID, deployment.OrganizationID, deployment.ID, backlog)
+	return nil
+}
+
+// isDeploymentFinished reports whether a deployment status is terminal
+func isDeploymentFinished(status string) bool {
+	return status == models.DeploymentStatusCompleted ||
+		status == models.DeploymentStatusFailed ||
+		status == models.DeploymentStatusCancelled ||
+		status == models.DeploymentStatusRolledBack
 }
 
 // GetRealTimeStatus gets real-time deployment status
@@ -173,11 +458,18 @@ func (s *DeploymentService) GetRealTimeStatus(ctx context.Context, deploymentID
 	// Get recent logs
 	logs, _ := s.logger.GetRecentLogs(ctx, deploymentID, 10)
 
+	// Get recent health check results
+	healthChecks, err := s.repoManager.DeploymentHealthCheck.ListByDeployment(ctx, deploymentID, healthCheckHistoryLimit)
+	if err != nil {
+		healthChecks = []*models.DeploymentHealthCheck{}
+	}
+
 	return map[string]interface{}{
-		"deployment":  deployment,
-		"realTime":    orchestratorStatus,
-		"recentLogs":  logs,
-		"lastUpdated": time.Now(),
+		"deployment":   deployment,
+		"realTime":     orchestratorStatus,
+		"recentLogs":   logs,
+		"healthChecks": healthChecks,
+		"lastUpdated":  time.Now(),
 	}, nil
 }
 
@@ -195,10 +487,14 @@ type DeploymentLog struct {
 // DeploymentLogger handles deployment logging
 type DeploymentLogger struct {
 	repoManager *repositories.RepositoryManager
+	wsService   *WebSocketService
+
+	// orgIDs caches each deployment's organization, keyed by deployment ID
+	orgIDs sync.Map
 }
 
-func NewDeploymentLogger(repoManager *repositories.RepositoryManager) *DeploymentLogger {
-	return &DeploymentLogger{repoManager: repoManager}
+func NewDeploymentLogger(repoManager *repositories.RepositoryManager, wsService *WebSocketService) *DeploymentLogger {
+	return &DeploymentLogger{repoManager: repoManager, wsService: wsService}
 }
 
 func (dl *DeploymentLogger) LogInfo(ctx context.Context, deploymentID, message string, metadata map[string]interface{}) {
@@ -226,10 +522,12 @@ func (dl *DeploymentLogger) log(ctx context.Context, deploymentID, level, messag
 		Metadata:     metadata,
 	}
 
+	orgID := dl.organizationID(ctx, deploymentID)
+
 	// Store in audit log for now
 	auditLog := &models.AuditLog{
 		ID:             log.ID,
-		OrganizationID: "",  // Will be set by repository if needed
+		OrganizationID: orgID,
 		UserID:         nil, // System generated
 		Action:         fmt.Sprintf("deployment.%s", level),
 		ResourceType:   &[]string{"deployment"}[0],
@@ -244,6 +542,26 @@ func (dl *DeploymentLogger) log(ctx context.Context, deploymentID, level, messag
 	}
 
 	dl.repoManager.AuditLog.Create(ctx, auditLog)
+
+	// Stream to clients following this deployment
+	if dl.wsService != nil {
+		dl.wsService.PublishDeploymentLog(orgID, deploymentID, log)
+	}
+}
+
+// organizationID returns the organization a deployment belongs to
+func (dl *DeploymentLogger) organizationID(ctx context.Context, deploymentID string) string {
+	if orgID, ok := dl.orgIDs.Load(deploymentID); ok {
+		return orgID.(string)
+	}
+
+	deployment, err := dl.repoManager.Deployment.GetByID(ctx, deploymentID)
+	if err != nil {
+		return ""
+	}
+
+	dl.orgIDs.Store(deploymentID, deployment.OrganizationID)
+	return deployment.OrganizationID
 }
 
 func (dl *DeploymentLogger) GetLogs(ctx context.Context, deploymentID string) ([]DeploymentLog, error) {
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// errHealthCheckFailed is returned when a deployed application fails its health checks
+var errHealthCheckFailed = errors.New("health checks failed")
+
+// healthCheckHistoryLimit is how many recent health checks deployment status includes
+const healthCheckHistoryLimit = 20
+
+// healthCheckConfig returns the deployment's health check settings with defaults applied, or
+// nil if the deployment configures no health check
+func healthCheckConfig(deployment *models.Deployment) (*models.HealthCheckConfig, error) {
+	config := &models.HealthCheckConfig{}
+
+	switch value := deployment.Configuration["healthCheck"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if value == "" {
+			return nil, nil
+		}
+		config.Path = value
+	case map[string]interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid healthCheck configuration: %w", err)
+		}
+		if err := json.Unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("invalid healthCheck configuration: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("invalid healthCheck configuration: unsupported type %T", value)
+	}
+
+	if config.IntervalSeconds <= 0 {
+		config.IntervalSeconds = models.DefaultHealthCheckIntervalSeconds
+	}
+	if config.TimeoutSeconds <= 0 {
+		config.TimeoutSeconds = models.DefaultHealthCheckTimeoutSeconds
+	}
+	if config.SuccessThreshold <= 0 {
+		config.SuccessThreshold = models.DefaultHealthCheckSuccessThreshold
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = models.DefaultHealthCheckFailureThreshold
+	}
+
+	return config, nil
+}
+
+// healthCheckURL resolves the URL a health check probes. A full URL is used as is; a path is
+// joined to the deployment configuration's url entry.
+func healthCheckURL(deployment *models.Deployment, config *models.HealthCheckConfig) string {
+	for _, target := range []string{config.URL, config.Path} {
+		if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+			return target
+		}
+	}
+
+	baseURL, _ := deployment.Configuration["url"].(string)
+	if baseURL == "" || config.Path == "" {
+		return ""
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(config.Path, "/")
+}
+
+// runHealthChecks polls the deployed application's health check until it passes SuccessThreshold
+// checks in a row or has failed FailureThreshold checks in total
+func (do *DeploymentOrchestrator) runHealthChecks(execution *DeploymentExecution) error {
+	deployment := execution.Deployment
+	logger := execution.Logger
+
+	config, err := healthCheckConfig(deployment)
+	if err != nil {
+		return err
+	}
+	if config == nil {
+		return nil
+	}
+
+	url := healthCheckURL(deployment, config)
+	if url == "" {
+		logger.LogWarning(execution.Context, deployment.ID, "Health check has no URL to probe, skipping", map[string]interface{}{
+			"path": config.Path,
+		})
+		return nil
+	}
+
+	execution.CurrentStep = "health_checks"
+	logger.LogInfo(execution.Context, deployment.ID, "Running health checks", map[string]interface{}{
+		"url":              url,
+		"intervalSeconds":  config.IntervalSeconds,
+		"successThreshold": config.SuccessThreshold,
+		"failureThreshold": config.FailureThreshold,
+	})
+
+	client := &http.Client{Timeout: time.Duration(config.TimeoutSeconds) * time.Second}
+	interval := time.Duration(config.IntervalSeconds) * time.Second
+	successes, failures := 0, 0
+
+	for {
+		check := do.probeHealth(execution.Context, client, deployment.ID, url)
+		if execution.Context.Err() != nil {
+			return fmt.Errorf("health checks cancelled")
+		}
+		if err := do.repoManager.DeploymentHealthCheck.Create(context.Background(), check); err != nil {
+			logger.LogWarning(execution.Context, deployment.ID, "Failed to record health check", map[string]interface{}{
+				"error": err.Error(),
+			})
+		}
+
+		if check.Healthy {
+			successes++
+			if successes >= config.SuccessThreshold {
+				logger.LogInfo(execution.Context, deployment.ID, "Health checks passed", map[string]interface{}{
+					"url":      url,
+					"failures": failures,
+				})
+				return nil
+			}
+		} else {
+			successes = 0
+			failures++
+			logger.LogWarning(execution.Context, deployment.ID, fmt.Sprintf("Health check failed (%d of %d)", failures, config.FailureThreshold), map[string]interface{}{
+				"url":        url,
+				"statusCode": check.StatusCode,
+				"error":      check.Error,
+			})
+			if failures >= config.FailureThreshold {
+				return fmt.Errorf("%w: %s failed %d checks", errHealthCheckFailed, url, failures)
+			}
+		}
+
+		select {
+		case <-execution.Context.Done():
+			return fmt.Errorf("health checks cancelled")
+		case <-time.After(interval):
+		}
+	}
+}
+
+// probeHealth requests the health check URL once. Any 2xx or 3xx response is healthy.
+func (do *DeploymentOrchestrator) probeHealth(ctx context.Context, client *http.Client, deploymentID, url string) *models.DeploymentHealthCheck {
+	check := &models.DeploymentHealthCheck{
+		ID:           uuid.New().String(),
+		DeploymentID: deploymentID,
+		URL:          url,
+		CheckedAt:    time.Now(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err == nil {
+		var resp *http.Response
+		resp, err = client.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			check.StatusCode = &resp.StatusCode
+			check.Healthy = resp.StatusCode >= 200 && resp.StatusCode < 400
+		}
+	}
+	check.LatencyMs = time.Since(check.CheckedAt).Milliseconds()
+
+	if err != nil {
+		message := err.Error()
+		check.Error = &message
+	}
+	return check
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// ErrDeploymentInProgress is returned when another deployment of the same application is already
+// running in the environment
+var ErrDeploymentInProgress = errors.New("deployment in progress")
+
+// deploymentLockTTL is how long a deployment lock lasts without being renewed, so the target of
+// a deployment lost in a crash is freed again
+const deploymentLockTTL = 10 * time.Minute
+
+// acquireDeploymentLock makes the deployment the only one running for its application and
+// environment
+func (s *DeploymentService) acquireDeploymentLock(ctx context.Context, deployment *models.Deployment) error {
+	lock := &models.DeploymentLock{
+		OrganizationID: deployment.OrganizationID,
+		Application:    deployment.Application,
+		Environment:    deployment.Environment,
+		DeploymentID:   deployment.ID,
+		ExpiresAt:      time.Now().Add(deploymentLockTTL),
+	}
+
+	acquired, err := s.repoManager.DeploymentLock.Acquire(ctx, lock)
+	if err != nil {
+		return err
+	}
+	if acquired {
+		return nil
+	}
+
+	// The lock may have been released since, but the caller can simply retry
+	if holder, err := s.repoManager.DeploymentLock.Get(ctx, deployment.OrganizationID, deployment.Application, deployment.Environment); err == nil {
+		return fmt.Errorf("%w: deployment %s of %s to %s is still running", ErrDeploymentInProgress, holder.DeploymentID, deployment.Application, deployment.Environment)
+	}
+	return fmt.Errorf("%w: another deployment of %s to %s is still running", ErrDeploymentInProgress, deployment.Application, deployment.Environment)
+}
+
+// renewDeploymentLock keeps a running deployment's lock from expiring until stop is closed
+func (do *DeploymentOrchestrator) renewDeploymentLock(deploymentID string, stop <-chan struct{}) {
+	ticker := time.NewTicker(deploymentLockTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := do.repoManager.DeploymentLock.Renew(context.Background(), deploymentID, time.Now().Add(deploymentLockTTL)); err != nil {
+				log.Printf("Failed to renew lock of deployment %s: %v", deploymentID, err)
+			}
+		}
+	}
+}
+
+// releaseDeploymentLock lets the next deployment of a finished deployment's target start
+func (do *DeploymentOrchestrator) releaseDeploymentLock(deploymentID string) {
+	if err := do.repoManager.DeploymentLock.Release(context.Background(), deploymentID); err != nil {
+		log.Printf("Failed to release lock of deployment %s: %v", deploymentID, err)
+	}
+}
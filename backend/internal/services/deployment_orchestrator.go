@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -14,8 +15,14 @@ import (
 // DeploymentOrchestrator manages the deployment lifecycle
 type DeploymentOrchestrator struct {
 	repoManager       *repositories.RepositoryManager
+	wsService         *WebSocketService
+	metricsService    *MetricsService
 	activeDeployments map[string]*DeploymentExecution
 	mutex             sync.RWMutex
+	webhooks          *WebhookService
+
+	// rollback starts a rollback deployment and records it; set by DeploymentService
+	rollback func(ctx context.Context, deployment *models.Deployment, targetVersion string, record *models.DeploymentRollback) (*models.Deployment, error)
 }
 
 // DeploymentExecution tracks a running deployment
@@ -29,11 +36,17 @@ type DeploymentExecution struct {
 	CurrentStep string
 	StartTime   time.Time
 	Logger      *DeploymentLogger
+
+	// Pipeline is the pipeline whose stages track this deployment, if any
+	Pipeline      *models.Pipeline
+	PipelineStage int
 }
 
-func NewDeploymentOrchestrator(repoManager *repositories.RepositoryManager) *DeploymentOrchestrator {
+func NewDeploymentOrchestrator(repoManager *repositories.RepositoryManager, wsService *WebSocketService, metricsService *MetricsService) *DeploymentOrchestrator {
 	return &DeploymentOrchestrator{
 		repoManager:       repoManager,
+		wsService:         wsService,
+		metricsService:    metricsService,
 		activeDeployments: make(map[string]*DeploymentExecution),
 	}
 }
@@ -52,7 +65,7 @@ func (do *DeploymentOrchestrator) StartDeployment(ctx context.Context, deploymen
 		Progress:    0,
 		CurrentStep: "initializing",
 		StartTime:   time.Now(),
-		Logger:      NewDeploymentLogger(do.repoManager),
+		Logger:      NewDeploymentLogger(do.repoManager, do.wsService),
 	}
 
 	// Track active deployment
@@ -66,13 +79,20 @@ func (do *DeploymentOrchestrator) StartDeployment(ctx context.Context, deploymen
 	deployment.StartedAt = &now
 	do.repoManager.Deployment.Update(ctx, deployment)
 
+	do.startPipelineRun(ctx, execution)
+
 	// Start deployment process
 	go do.executeDeployment(execution)
 }
 
 // executeDeployment runs the actual deployment steps
 func (do *DeploymentOrchestrator) executeDeployment(execution *DeploymentExecution) {
+	stopRenewing := make(chan struct{})
+	go do.renewDeploymentLock(execution.ID, stopRenewing)
+
 	defer func() {
+		close(stopRenewing)
+
 		// Clean up active deployment tracking
 		do.mutex.Lock()
 		delete(do.activeDeployments, execution.ID)
@@ -86,17 +106,12 @@ func (do *DeploymentOrchestrator) executeDeployment(execution *DeploymentExecuti
 		"application": deployment.Application,
 		"version":     deployment.Version,
 		"environment": deployment.Environment,
+		"strategy":    deployment.Strategy,
 	})
 
-	// Deployment steps
-	steps := []DeploymentStep{
-		{Name: "validation", Duration: 2 * time.Second, Progress: 10},
-		{Name: "preparation", Duration: 3 * time.Second, Progress: 25},
-		{Name: "building", Duration: 10 * time.Second, Progress: 50},
-		{Name: "testing", Duration: 5 * time.Second, Progress: 70},
-		{Name: "deploying", Duration: 8 * time.Second, Progress: 90},
-		{Name: "verification", Duration: 3 * time.Second, Progress: 100},
-	}
+	// Deployment steps depend on the rollout strategy
+	steps := do.strategySteps(deployment)
+	initStrategyState(deployment, steps)
 
 	for _, step := range steps {
 		select {
@@ -106,6 +121,7 @@ func (do *DeploymentOrchestrator) executeDeployment(execution *DeploymentExecuti
 				"step": step.Name,
 			})
 			do.updateDeploymentStatus(deployment, models.DeploymentStatusCancelled, execution.Progress)
+			do.finishPipelineRun(execution, models.PipelineStatusCancelled)
 			return
 		default:
 			// Execute step
@@ -114,17 +130,48 @@ func (do *DeploymentOrchestrator) executeDeployment(execution *DeploymentExecuti
 					"step":  step.Name,
 					"error": err.Error(),
 				})
+				if errors.Is(err, errCanaryUnhealthy) {
+					do.rollbackCanary(execution)
+				}
 				do.updateDeploymentStatus(deployment, models.DeploymentStatusFailed, execution.Progress)
+				do.finishPipelineRun(execution, models.PipelineStatusFailed)
 				return
 			}
 		}
 	}
 
+	// The deployment only completes once the application reports healthy
+	if err := do.runHealthChecks(execution); err != nil {
+		if execution.Context.Err() != nil {
+			logger.LogWarning(execution.Context, deployment.ID, "Deployment cancelled", map[string]interface{}{
+				"step": execution.CurrentStep,
+			})
+			do.updateDeploymentStatus(deployment, models.DeploymentStatusCancelled, execution.Progress)
+			do.finishPipelineRun(execution, models.PipelineStatusCancelled)
+			return
+		}
+		logger.LogError(execution.Context, deployment.ID, fmt.Sprintf("Health checks failed: %s", err.Error()), map[string]interface{}{
+			"error": err.Error(),
+		})
+		do.updateDeploymentStatus(deployment, models.DeploymentStatusFailed, execution.Progress)
+		do.finishPipelineRun(execution, models.PipelineStatusFailed)
+		return
+	}
+
 	// Deployment completed successfully
 	logger.LogInfo(execution.Context, deployment.ID, "Deployment completed successfully", map[string]interface{}{
 		"duration": time.Since(execution.StartTime).String(),
 	})
+	if deployment.StrategyState.Phase != models.StrategyPhasePromoted && deployment.StrategyState.Phase != models.StrategyPhaseSwitched {
+		deployment.StrategyState.Phase = models.StrategyPhaseComplete
+	}
+	deployment.StrategyState.TrafficPercentage = 100
 	do.updateDeploymentStatus(deployment, models.DeploymentStatusCompleted, 100)
+	do.finishPipelineRun(execution, models.PipelineStatusSuccess)
+
+	if deployment.StrategyState.AutoRollback != nil {
+		go do.watchDeployment(deployment, execution.Logger)
+	}
 }
 
 // DeploymentStep represents a step in the deployment process
@@ -132,11 +179,23 @@ type DeploymentStep struct {
 	Name     string
 	Duration time.Duration
 	Progress int
+
+	// Run performs strategy-specific work once the step's progress is reached
+	Run func(execution *DeploymentExecution) error
 }
 
 // executeStep executes a single deployment step
-func (do *DeploymentOrchestrator) executeStep(execution *DeploymentExecution, step DeploymentStep) error {
+func (do *DeploymentOrchestrator) executeStep(execution *DeploymentExecution, step DeploymentStep) (err error) {
 	execution.CurrentStep = step.Name
+	setStrategyStepStatus(execution.Deployment, step.Name, models.PipelineStatusRunning, "")
+	defer func() {
+		if err != nil {
+			setStrategyStepStatus(execution.Deployment, step.Name, models.PipelineStatusFailed, err.Error())
+		} else {
+			setStrategyStepStatus(execution.Deployment, step.Name, models.PipelineStatusSuccess, "")
+		}
+	}()
+
 	execution.Logger.LogInfo(execution.Context, execution.ID, fmt.Sprintf("Starting step: %s", step.Name), map[string]interface{}{
 		"step": step.Name,
 	})
@@ -157,6 +216,8 @@ func (do *DeploymentOrchestrator) executeStep(execution *DeploymentExecution, st
 			deployment := execution.Deployment
 			deployment.Progress = execution.Progress
 			do.repoManager.Deployment.Update(execution.Context, deployment)
+
+			do.advancePipeline(execution)
 		}
 	}
 
@@ -165,6 +226,12 @@ func (do *DeploymentOrchestrator) executeStep(execution *DeploymentExecution, st
 		return fmt.Errorf("tests failed")
 	}
 
+	if step.Run != nil {
+		if err := step.Run(execution); err != nil {
+			return err
+		}
+	}
+
 	execution.Logger.LogInfo(execution.Context, execution.ID, fmt.Sprintf("Completed step: %s", step.Name), map[string]interface{}{
 		"step":     step.Name,
 		"progress": execution.Progress,
@@ -173,17 +240,137 @@ func (do *DeploymentOrchestrator) executeStep(execution *DeploymentExecution, st
 	return nil
 }
 
+// deploymentWebhookEvents maps the statuses a deployment finishes with to the webhook events posted
+var deploymentWebhookEvents = map[string]string{
+	models.DeploymentStatusCompleted:  models.WebhookEventDeploymentCompleted,
+	models.DeploymentStatusFailed:     models.WebhookEventDeploymentFailed,
+	models.DeploymentStatusCancelled:  models.WebhookEventDeploymentCancelled,
+	models.DeploymentStatusRolledBack: models.WebhookEventDeploymentRolledBack,
+}
+
 // updateDeploymentStatus updates the deployment status in the database
 func (do *DeploymentOrchestrator) updateDeploymentStatus(deployment *models.Deployment, status string, progress int) {
 	deployment.Status = status
 	deployment.Progress = progress
 
-	if status == models.DeploymentStatusCompleted || status == models.DeploymentStatusFailed || status == models.DeploymentStatusCancelled {
+	finished := isDeploymentFinished(status)
+	if finished {
 		now := time.Now()
 		deployment.CompletedAt = &now
 	}
 
 	do.repoManager.Deployment.Update(context.Background(), deployment)
+	if finished {
+		do.releaseDeploymentLock(deployment.ID)
+	}
+
+	if do.wsService != nil {
+		do.wsService.PublishDeploymentStatus(deployment)
+	}
+
+	// Let log followers know the stream is complete
+	if finished && do.wsService != nil {
+		do.wsService.PublishDeploymentFinished(deployment.OrganizationID, deployment.ID, status)
+	}
+
+	if event, ok := deploymentWebhookEvents[status]; ok {
+		do.webhooks.Emit(context.Background(), deployment.OrganizationID, event, deployment)
+	}
+}
+
+// startPipelineRun resets the deployment's pipeline, if it has one, for a new run
+func (do *DeploymentOrchestrator) startPipelineRun(ctx context.Context, execution *DeploymentExecution) {
+	deployment := execution.Deployment
+	if deployment.PipelineID == nil {
+		return
+	}
+
+	pipeline, err := do.repoManager.Pipeline.GetByID(ctx, *deployment.PipelineID, deployment.OrganizationID)
+	if err != nil {
+		execution.Logger.LogWarning(ctx, deployment.ID, "Failed to load pipeline", map[string]interface{}{
+			"pipelineId": *deployment.PipelineID,
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	pipeline.Status = models.PipelineStatusRunning
+	pipeline.LastRun = &now
+	for i := range pipeline.Stages {
+		pipeline.Stages[i].Status = models.PipelineStatusPending
+		pipeline.Stages[i].Duration = nil
+		pipeline.Stages[i].StartedAt = nil
+		pipeline.Stages[i].CompletedAt = nil
+	}
+
+	execution.Pipeline = pipeline
+	execution.PipelineStage = -1
+	do.advancePipeline(execution)
+}
+
+// advancePipeline moves the pipeline to the stage matching the deployment's progress.
+// Stages split the progress range evenly, so with 4 stages the second starts at 25%.
+func (do *DeploymentOrchestrator) advancePipeline(execution *DeploymentExecution) {
+	pipeline := execution.Pipeline
+	if pipeline == nil || len(pipeline.Stages) == 0 {
+		return
+	}
+
+	current := execution.Progress * len(pipeline.Stages) / 100
+	if current >= len(pipeline.Stages) {
+		current = len(pipeline.Stages) - 1
+	}
+	if current == execution.PipelineStage {
+		return
+	}
+
+	now := time.Now()
+	for i := 0; i < current; i++ {
+		if pipeline.Stages[i].Status != models.PipelineStatusSuccess {
+			completePipelineStage(&pipeline.Stages[i], models.PipelineStatusSuccess, now)
+		}
+	}
+
+	pipeline.Stages[current].Status = models.PipelineStatusRunning
+	pipeline.Stages[current].StartedAt = &now
+	execution.PipelineStage = current
+
+	do.repoManager.Pipeline.Update(context.Background(), pipeline)
+}
+
+// finishPipelineRun records the outcome of the deployment on its pipeline
+func (do *DeploymentOrchestrator) finishPipelineRun(execution *DeploymentExecution, status string) {
+	pipeline := execution.Pipeline
+	if pipeline == nil {
+		return
+	}
+
+	now := time.Now()
+	pipeline.Status = status
+	for i := range pipeline.Stages {
+		stage := &pipeline.Stages[i]
+		switch {
+		case status == models.PipelineStatusSuccess && stage.Status != models.PipelineStatusSuccess:
+			completePipelineStage(stage, models.PipelineStatusSuccess, now)
+		case status != models.PipelineStatusSuccess && i == execution.PipelineStage:
+			completePipelineStage(stage, status, now)
+		}
+	}
+
+	do.repoManager.Pipeline.Update(context.Background(), pipeline)
+}
+
+// completePipelineStage marks a stage finished and records how long it ran
+func completePipelineStage(stage *models.PipelineStage, status string, now time.Time) {
+	if stage.StartedAt == nil {
+		stage.StartedAt = &now
+	}
+	duration := int(now.Sub(*stage.StartedAt).Seconds())
+
+	stage.Status = status
+	stage.Duration = &duration
+	stage.CompletedAt = &now
 }
 
 // CancelDeployment cancels a running deployment
@@ -220,20 +407,24 @@ func (do *DeploymentOrchestrator) GetStatus(ctx context.Context, deploymentID st
 		}
 
 		return map[string]interface{}{
-			"status":      deployment.Status,
-			"progress":    deployment.Progress,
-			"currentStep": "completed",
-			"active":      false,
+			"status":        deployment.Status,
+			"progress":      deployment.Progress,
+			"currentStep":   "completed",
+			"active":        false,
+			"strategy":      deployment.Strategy,
+			"strategyState": deployment.StrategyState,
 		}, nil
 	}
 
 	return map[string]interface{}{
-		"status":      execution.Status,
-		"progress":    execution.Progress,
-		"currentStep": execution.CurrentStep,
-		"active":      true,
-		"startTime":   execution.StartTime,
-		"duration":    time.Since(execution.StartTime).String(),
+		"status":        execution.Status,
+		"progress":      execution.Progress,
+		"currentStep":   execution.CurrentStep,
+		"active":        true,
+		"startTime":     execution.StartTime,
+		"duration":      time.Since(execution.StartTime).String(),
+		"strategy":      execution.Deployment.Strategy,
+		"strategyState": execution.Deployment.StrategyState,
 	}, nil
 }
 
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// replicasConfigKey is the configuration key holding a deployment's replica count
+const replicasConfigKey = "replicas"
+
+// PreviewDeployment compares a deployment request against the version currently running for
+// the same application and environment without creating a deployment
+func (s *DeploymentService) PreviewDeployment(ctx context.Context, orgID string, req *models.CreateDeploymentRequest) (*models.DeploymentPreview, error) {
+	history, err := s.GetDeploymentHistory(ctx, orgID, req.Application, req.Environment, repositories.DefaultListParams())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment history: %w", err)
+	}
+
+	// History is newest first, so the first completed deployment is the one currently running
+	var baseline *models.Deployment
+	for _, deployment := range history {
+		if deployment.Status == models.DeploymentStatusCompleted {
+			baseline = deployment
+			break
+		}
+	}
+
+	var baselineConfig map[string]interface{}
+	preview := &models.DeploymentPreview{
+		Application:        req.Application,
+		Environment:        req.Environment,
+		BaselineDeployment: baseline,
+		Version: models.VersionChange{
+			To:      req.Version,
+			Changed: true,
+		},
+	}
+	if baseline != nil {
+		baselineConfig = baseline.Configuration
+		preview.Version.From = baseline.Version
+		preview.Version.Changed = baseline.Version != req.Version
+	}
+
+	preview.Configuration = diffConfiguration(baselineConfig, req.Configuration)
+	preview.Replicas = replicaChange(baselineConfig, req.Configuration)
+	preview.HasChanges = preview.Version.Changed ||
+		len(preview.Configuration.Added) > 0 ||
+		len(preview.Configuration.Removed) > 0 ||
+		len(preview.Configuration.Changed) > 0
+
+	return preview, nil
+}
+
+// diffConfiguration lists the keys added, removed and changed between two configurations
+func diffConfiguration(from, to map[string]interface{}) models.ConfigurationDiff {
+	diff := models.ConfigurationDiff{
+		Added:   make(map[string]interface{}),
+		Removed: make(map[string]interface{}),
+		Changed: []models.ConfigurationChange{},
+	}
+
+	before := make(map[string]interface{})
+	after := make(map[string]interface{})
+	flattenConfiguration("", from, before)
+	flattenConfiguration("", to, after)
+
+	for key, value := range after {
+		previous, ok := before[key]
+		if !ok {
+			diff.Added[key] = value
+			continue
+		}
+		if !reflect.DeepEqual(previous, value) {
+			diff.Changed = append(diff.Changed, models.ConfigurationChange{Key: key, From: previous, To: value})
+		}
+	}
+
+	for key, value := range before {
+		if _, ok := after[key]; !ok {
+			diff.Removed[key] = value
+		}
+	}
+
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Key < diff.Changed[j].Key
+	})
+
+	return diff
+}
+
+// flattenConfiguration writes nested configuration values into out keyed by dot-separated path
+func flattenConfiguration(prefix string, config map[string]interface{}, out map[string]interface{}) {
+	for key, value := range config {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			flattenConfiguration(path, nested, out)
+			continue
+		}
+		out[path] = value
+	}
+}
+
+// replicaChange reports the change in replica count, or nil when neither configuration sets one
+func replicaChange(from, to map[string]interface{}) *models.ReplicaChange {
+	before := configReplicas(from)
+	after := configReplicas(to)
+	if before == nil && after == nil {
+		return nil
+	}
+
+	change := &models.ReplicaChange{From: before, To: after}
+	if before != nil {
+		change.Delta -= *before
+	}
+	if after != nil {
+		change.Delta += *after
+	}
+	return change
+}
+
+// configReplicas reads the replica count from a configuration
+func configReplicas(config map[string]interface{}) *int {
+	var replicas int
+	switch value := config[replicasConfigKey].(type) {
+	case int:
+		replicas = value
+	case int64:
+		replicas = int(value)
+	case float64:
+		replicas = int(value)
+	default:
+		return nil
+	}
+	return &replicas
+}
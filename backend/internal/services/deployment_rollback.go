@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// autoRollbackCheckInterval is how often a completed deployment's health is checked during its
+// watch window
+const autoRollbackCheckInterval = 30 * time.Second
+
+// deploymentAlertResourceType is the resource type of alerts raised against deployments
+const deploymentAlertResourceType = "deployment"
+
+// autoRollbackConfig returns the deployment's auto-rollback settings with defaults applied
+func autoRollbackConfig(deployment *models.Deployment) *models.AutoRollbackConfig {
+	config := deployment.StrategyState.AutoRollback
+	if config.WatchSeconds == 0 {
+		config.WatchSeconds = models.DefaultAutoRollbackWatchSeconds
+	}
+	if config.MaxErrorRate == 0 {
+		config.MaxErrorRate = models.DefaultAutoRollbackMaxErrorRate
+	}
+
+	return config
+}
+
+// watchDeployment checks a completed deployment's health until its watch window ends and rolls
+// it back automatically on the first failed check. Watching stops early once the deployment is
+// no longer completed, e.g. after a manual rollback.
+func (do *DeploymentOrchestrator) watchDeployment(deployment *models.Deployment, logger *DeploymentLogger) {
+	ctx := context.Background()
+	config := autoRollbackConfig(deployment)
+	window := time.Duration(config.WatchSeconds) * time.Second
+
+	since := time.Now()
+	until := since.Add(window)
+	deployment.StrategyState.WatchUntil = &until
+	do.repoManager.Deployment.Update(ctx, deployment)
+
+	ticker := time.NewTicker(min(autoRollbackCheckInterval, window))
+	defer ticker.Stop()
+
+	for range ticker.C {
+		current, err := do.repoManager.Deployment.GetByID(ctx, deployment.ID)
+		if err != nil || current.Status != models.DeploymentStatusCompleted {
+			return
+		}
+
+		breach, err := do.checkDeploymentHealth(ctx, current, config, since)
+		if err != nil {
+			logger.LogWarning(ctx, current.ID, "Post-deployment health check failed", map[string]interface{}{
+				"error": err.Error(),
+			})
+		} else if breach != nil {
+			do.autoRollback(ctx, current, breach, logger)
+			return
+		}
+
+		if !time.Now().Before(until) {
+			logger.LogInfo(ctx, current.ID, "Post-deployment health watch passed", nil)
+			return
+		}
+	}
+}
+
+// checkDeploymentHealth returns the rollback a deployment needs when its error rate since it
+// completed exceeds the budget or an error or critical alert has fired against it, or nil if it
+// is healthy
+func (do *DeploymentOrchestrator) checkDeploymentHealth(ctx context.Context, deployment *models.Deployment, config *models.AutoRollbackConfig, since time.Time) (*models.DeploymentRollback, error) {
+	errorRate, samples, err := do.deploymentErrorRate(ctx, deployment.ID, time.Since(since))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get error rate: %w", err)
+	}
+	if samples > 0 && errorRate > config.MaxErrorRate {
+		metric := models.RollbackMetricErrorRate
+		threshold := config.MaxErrorRate
+		return &models.DeploymentRollback{
+			Reason:      fmt.Sprintf("error rate %.2f%% exceeded %.2f%% after deployment", errorRate, threshold),
+			MetricName:  &metric,
+			MetricValue: &errorRate,
+			Threshold:   &threshold,
+		}, nil
+	}
+
+	resourceID, resourceType := deployment.ID, deploymentAlertResourceType
+	alerts, err := do.repoManager.Alert.Query(ctx, deployment.OrganizationID, models.AlertQuery{
+		ResourceID:   &resourceID,
+		ResourceType: &resourceType,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment alerts: %w", err)
+	}
+	for _, alert := range alerts {
+		if alert.CreatedAt.Before(since) {
+			continue
+		}
+		if alert.Severity == models.AlertSeverityError || alert.Severity == models.AlertSeverityCritical {
+			metric := models.RollbackMetricAlert
+			return &models.DeploymentRollback{
+				Reason:     fmt.Sprintf("%s alert fired after deployment: %s", alert.Severity, alert.Title),
+				MetricName: &metric,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// autoRollback rolls an unhealthy deployment back to the previous successful version of its
+// application and marks it rolled back. Without a version to return to, it is marked failed.
+func (do *DeploymentOrchestrator) autoRollback(ctx context.Context, deployment *models.Deployment, record *models.DeploymentRollback, logger *DeploymentLogger) {
+	previous := do.previousSuccessfulDeployment(ctx, deployment)
+	if previous == nil || do.rollback == nil {
+		logger.LogError(ctx, deployment.ID, "Deployment is unhealthy and there is no previous successful version to roll back to", map[string]interface{}{
+			"reason": record.Reason,
+		})
+		do.updateDeploymentStatus(deployment, models.DeploymentStatusFailed, deployment.Progress)
+		return
+	}
+
+	record.Trigger = models.RollbackTriggerAutomatic
+	if _, err := do.rollback(ctx, deployment, previous.Version, record); err != nil {
+		logger.LogError(ctx, deployment.ID, "Automatic rollback failed", map[string]interface{}{
+			"reason": record.Reason,
+			"error":  err.Error(),
+		})
+		return
+	}
+
+	logger.LogWarning(ctx, deployment.ID, fmt.Sprintf("Rolled back automatically to version %s", previous.Version), map[string]interface{}{
+		"reason":        record.Reason,
+		"targetVersion": previous.Version,
+	})
+	deployment.StrategyState.Phase = models.StrategyPhaseRolledBack
+	deployment.StrategyState.TrafficPercentage = 0
+	do.updateDeploymentStatus(deployment, models.DeploymentStatusRolledBack, deployment.Progress)
+}
+
+// previousSuccessfulDeployment finds the latest completed deployment of another version of the
+// same application and environment made before the given one
+func (do *DeploymentOrchestrator) previousSuccessfulDeployment(ctx context.Context, deployment *models.Deployment) *models.Deployment {
+	recent, err := do.repoManager.Deployment.ListByEnvironment(ctx, deployment.OrganizationID, deployment.Environment, repositories.DefaultListParams())
+	if err != nil {
+		return nil
+	}
+
+	for _, previous := range recent {
+		if previous.ID != deployment.ID && previous.Application == deployment.Application &&
+			previous.Version != deployment.Version && previous.Status == models.DeploymentStatusCompleted &&
+			previous.CreatedAt.Before(deployment.CreatedAt) {
+			return previous
+		}
+	}
+	return nil
+}
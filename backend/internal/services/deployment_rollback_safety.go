@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrRollbackReintroducesVulnerabilities is returned when a rollback's target version has
+	// unresolved critical vulnerabilities that the current version fixed and the rollback was not
+	// overridden
+	ErrRollbackReintroducesVulnerabilities = errors.New("rollback reintroduces critical vulnerabilities")
+
+	// ErrRollbackOverrideReason is returned when a rollback safety check is overridden without a
+	// reason
+	ErrRollbackOverrideReason = errors.New("a reason is required to override the rollback safety check")
+)
+
+// maxRollbackCheckFindings caps the findings of each version compared by a rollback safety check
+const maxRollbackCheckFindings = 1000
+
+// SetAuditWriter sets the writer rollback decisions are audited through
+func (s *DeploymentService) SetAuditWriter(auditWriter *AuditWriter) {
+	s.auditWriter = auditWriter
+}
+
+// checkRollbackSafety compares the latest completed scans of the target version's deployments and
+// the deployment's version. Active critical findings of the target whose vulnerability the current
+// version no longer has are reintroduced by the rollback. The check passes when there are none
+// and is unverified when either version has not been scanned; callers decide what to do otherwise.
+func (s *DeploymentService) checkRollbackSafety(ctx context.Context, deployment *models.Deployment, targetVersion string) (*models.RollbackSafetyCheck, error) {
+	check := &models.RollbackSafetyCheck{CheckedAt: time.Now()}
+
+	targetScan, err := s.repoManager.SecurityScan.GetLatestForDeploymentVersion(ctx, deployment.OrganizationID, deployment.Application, targetVersion)
+	if err != nil {
+		return nil, err
+	}
+	currentScan, err := s.repoManager.SecurityScan.GetLatestForDeploymentVersion(ctx, deployment.OrganizationID, deployment.Application, deployment.Version)
+	if err != nil {
+		return nil, err
+	}
+	if targetScan == nil || currentScan == nil {
+		unscanned := targetVersion
+		if targetScan != nil {
+			unscanned = deployment.Version
+		}
+		check.Decision = models.RollbackCheckUnverified
+		check.Message = fmt.Sprintf("Version %s has not been scanned, so the rollback could not be checked for reintroduced vulnerabilities", unscanned)
+		return check, nil
+	}
+	check.TargetScanID = &targetScan.ID
+	check.CurrentScanID = &currentScan.ID
+
+	critical := models.VulnSeverityCritical
+	targetFindings, err := s.activeDeploymentFindings(ctx, deployment.OrganizationID, targetScan.TargetID, &critical)
+	if err != nil {
+		return nil, err
+	}
+	currentFindings, err := s.activeDeploymentFindings(ctx, deployment.OrganizationID, currentScan.TargetID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[string]bool, len(currentFindings))
+	for _, finding := range currentFindings {
+		current[vulnerabilityKey(finding)] = true
+	}
+	for _, finding := range targetFindings {
+		if current[vulnerabilityKey(finding)] {
+			continue
+		}
+		check.Vulnerabilities = append(check.Vulnerabilities, models.ReintroducedVulnerability{
+			ID:       finding.ID,
+			CVEID:    finding.CVEID,
+			Title:    finding.Title,
+			Location: finding.Location,
+		})
+	}
+
+	if len(check.Vulnerabilities) == 0 {
+		check.Decision = models.RollbackCheckPassed
+		check.Message = fmt.Sprintf("Version %s has no unresolved critical vulnerabilities that version %s fixed", targetVersion, deployment.Version)
+		return check, nil
+	}
+
+	check.Message = fmt.Sprintf("Version %s has %d unresolved critical vulnerabilities that version %s fixed: %s",
+		targetVersion, len(check.Vulnerabilities), deployment.Version, reintroducedVulnerabilityList(check.Vulnerabilities))
+	return check, nil
+}
+
+// activeDeploymentFindings retrieves the findings of a deployment's scans that still need
+// attention, optionally of one severity
+func (s *DeploymentService) activeDeploymentFindings(ctx context.Context, orgID, deploymentID string, severity *models.VulnerabilitySeverity) ([]*models.Vulnerability, error) {
+	resourceType := ScanTargetDeployment
+	findings, _, err := s.repoManager.Vulnerability.Query(ctx, orgID, models.VulnerabilityQuery{
+		Severity:     severity,
+		Statuses:     []models.VulnerabilityStatus{models.VulnStatusOpen, models.VulnStatusAcknowledged, models.VulnStatusInProgress},
+		ResourceType: &resourceType,
+		ResourceID:   &deploymentID,
+		Limit:        maxRollbackCheckFindings,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deployment findings: %w", err)
+	}
+	return findings, nil
+}
+
+// vulnerabilityKey identifies a vulnerability across deployments: by CVE, or by title for
+// findings without one
+func vulnerabilityKey(finding *models.Vulnerability) string {
+	if finding.CVEID != nil && *finding.CVEID != "" {
+		return *finding.CVEID
+	}
+	return finding.Title
+}
+
+// reintroducedVulnerabilityList names reintroduced vulnerabilities by CVE or title
+func reintroducedVulnerabilityList(vulnerabilities []models.ReintroducedVulnerability) string {
+	names := make([]string, 0, len(vulnerabilities))
+	for _, vulnerability := range vulnerabilities {
+		if vulnerability.CVEID != nil && *vulnerability.CVEID != "" {
+			names = append(names, *vulnerability.CVEID)
+		} else {
+			names = append(names, vulnerability.Title)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// refuseRollback records a rollback refused by its safety check in the deployment's history
+func (s *DeploymentService) refuseRollback(ctx context.Context, deployment *models.Deployment, targetVersion string, record *models.DeploymentRollback) error {
+	record.SafetyCheck.Decision = models.RollbackCheckRefused
+	record.ID = uuid.New().String()
+	record.OrganizationID = deployment.OrganizationID
+	record.DeploymentID = deployment.ID
+	record.FromVersion = deployment.Version
+	record.ToVersion = targetVersion
+	if err := s.repoManager.DeploymentRollback.Create(ctx, record); err != nil {
+		return fmt.Errorf("failed to record refused rollback: %w", err)
+	}
+
+	s.logger.LogWarning(ctx, deployment.ID, fmt.Sprintf("Rollback to version %s refused", targetVersion), map[string]interface{}{
+		"reason": record.SafetyCheck.Message,
+	})
+	s.auditRollback(ctx, deployment, record)
+	return nil
+}
+
+// startAutomaticRollback rolls an unhealthy deployment back. Restoring a healthy version comes
+// first, so a target version that reintroduces vulnerabilities is only warned about.
+func (s *DeploymentService) startAutomaticRollback(ctx context.Context, deployment *models.Deployment, targetVersion string, record *models.DeploymentRollback) (*models.Deployment, error) {
+	check, err := s.checkRollbackSafety(ctx, deployment, targetVersion)
+	if err != nil {
+		s.logger.LogWarning(ctx, deployment.ID, "Could not check the rollback target for reintroduced vulnerabilities", map[string]interface{}{
+			"error": err.Error(),
+		})
+	} else {
+		if len(check.Vulnerabilities) > 0 {
+			check.Decision = models.RollbackCheckWarned
+			s.logger.LogWarning(ctx, deployment.ID, "Rolling back to a version with known critical vulnerabilities", map[string]interface{}{
+				"targetVersion": targetVersion,
+				"reason":        check.Message,
+			})
+		}
+		record.SafetyCheck = check
+	}
+
+	rollbackDeployment, err := s.startRollback(ctx, deployment, targetVersion, record)
+	if err != nil {
+		return nil, err
+	}
+
+	s.auditRollback(ctx, deployment, record)
+	return rollbackDeployment, nil
+}
+
+// auditRollback records a rollback, or a refused one, and its safety check in the audit log
+func (s *DeploymentService) auditRollback(ctx context.Context, deployment *models.Deployment, record *models.DeploymentRollback) {
+	if s.auditWriter == nil {
+		return
+	}
+
+	action := "deployment_rolled_back"
+	details := map[string]interface{}{
+		"fromVersion": record.FromVersion,
+		"toVersion":   record.ToVersion,
+		"trigger":     record.Trigger,
+		"reason":      record.Reason,
+	}
+	if record.RollbackDeploymentID != nil {
+		details["rollbackDeploymentId"] = *record.RollbackDeploymentID
+	}
+	if check := record.SafetyCheck; check != nil {
+		if check.Decision == models.RollbackCheckRefused {
+			action = "deployment_rollback_refused"
+		}
+		details["safetyCheck"] = check.Decision
+		details["safetyCheckMessage"] = check.Message
+		details["overridden"] = check.Decision == models.RollbackCheckOverridden
+		if len(check.Vulnerabilities) > 0 {
+			details["vulnerabilities"] = reintroducedVulnerabilityList(check.Vulnerabilities)
+		}
+	}
+
+	resourceType := "deployment"
+	auditLog := &models.AuditLog{
+		ID:             uuid.New().String(),
+		OrganizationID: deployment.OrganizationID,
+		UserID:         record.InitiatedBy,
+		Action:         action,
+		ResourceType:   &resourceType,
+		ResourceID:     &deployment.ID,
+		Details:        details,
+		CreatedAt:      time.Now(),
+	}
+	AuditContextFromContext(ctx).apply(auditLog)
+
+	s.auditWriter.Write(auditLog)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrDeploymentNotFound is returned when a deployment does not exist in the organization
+	ErrDeploymentNotFound = errors.New("deployment not found")
+
+	// ErrNoDeploymentArtifact is returned when a deployment's configuration names no artifact
+	ErrNoDeploymentArtifact = errors.New("deployment has no artifact")
+)
+
+// ScanTargetDeployment is the target type of scans of a deployment's artifact
+const ScanTargetDeployment = "deployment"
+
+// ComponentScanner lists the components of container images as SBOMs and scans those SBOMs for
+// vulnerabilities, as well as scanning images directly
+type ComponentScanner interface {
+	Scanner
+	GenerateSBOM(ctx context.Context, image string, format models.SBOMFormat) ([]byte, error)
+	ScanSBOM(ctx context.Context, scan *models.SecurityScan, image string, document []byte) ([]*models.Vulnerability, error)
+}
+
+// SBOMService generates and stores a software bill of materials for each deployment's artifact,
+// the container image named by the deployment configuration's "image" entry. As a container
+// scanner, it scans deployments through their SBOM, so findings are in the components it lists.
+type SBOMService struct {
+	repoManager *repositories.RepositoryManager
+	scanner     ComponentScanner
+}
+
+// NewSBOMService creates a new SBOM service
+func NewSBOMService(repoManager *repositories.RepositoryManager, scanner ComponentScanner) *SBOMService {
+	return &SBOMService{
+		repoManager: repoManager,
+		scanner:     scanner,
+	}
+}
+
+// GetDeploymentSBOM retrieves a deployment's SBOM in a format, generating and storing it the
+// first time it is requested
+func (s *SBOMService) GetDeploymentSBOM(ctx context.Context, orgID, deploymentID string, format models.SBOMFormat) (*models.DeploymentSBOM, error) {
+	deployment, err := s.getDeployment(ctx, orgID, deploymentID)
+	if err != nil {
+		return nil, err
+	}
+	return s.deploymentSBOM(ctx, deployment, format)
+}
+
+// Scan scans a container image. Scans targeting a deployment scan its CycloneDX SBOM instead.
+func (s *SBOMService) Scan(ctx context.Context, scan *models.SecurityScan) ([]*models.Vulnerability, error) {
+	if scan.TargetType != ScanTargetDeployment {
+		return s.scanner.Scan(ctx, scan)
+	}
+
+	deployment, err := s.getDeployment(ctx, scan.OrganizationID, scan.TargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	sbom, err := s.deploymentSBOM(ctx, deployment, models.SBOMFormatCycloneDX)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.scanner.ScanSBOM(ctx, scan, sbom.Artifact, sbom.Document)
+}
+
+// deploymentSBOM retrieves the stored SBOM of a deployment, or generates and stores it
+func (s *SBOMService) deploymentSBOM(ctx context.Context, deployment *models.Deployment, format models.SBOMFormat) (*models.DeploymentSBOM, error) {
+	sbom, err := s.repoManager.SBOM.GetByDeployment(ctx, deployment.ID, format)
+	if err != nil || sbom != nil {
+		return sbom, err
+	}
+
+	image, ok := deployment.Configuration["image"].(string)
+	if !ok || image == "" {
+		return nil, fmt.Errorf("%w: configure the deployment's image", ErrNoDeploymentArtifact)
+	}
+
+	document, err := s.scanner.GenerateSBOM(ctx, image, format)
+	if err != nil {
+		return nil, err
+	}
+
+	sbom = &models.DeploymentSBOM{
+		ID:             uuid.New().String(),
+		DeploymentID:   deployment.ID,
+		OrganizationID: deployment.OrganizationID,
+		Format:         format,
+		Artifact:       image,
+		Document:       document,
+		GeneratedAt:    time.Now(),
+	}
+	if err := s.repoManager.SBOM.Create(ctx, sbom); err != nil {
+		return nil, err
+	}
+
+	return sbom, nil
+}
+
+// getDeployment retrieves a deployment of the organization
+func (s *SBOMService) getDeployment(ctx context.Context, orgID, deploymentID string) (*models.Deployment, error) {
+	deployment, err := s.repoManager.Deployment.GetByID(ctx, deploymentID)
+	if err != nil || deployment.OrganizationID != orgID {
+		return nil, fmt.Errorf("%w: %s", ErrDeploymentNotFound, deploymentID)
+	}
+	return deployment, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// ErrInvalidSchedule is returned when a deployment is scheduled for a time that has already passed
+var ErrInvalidSchedule = errors.New("invalid deployment schedule")
+
+// ListScheduledDeployments retrieves an organization's deployments that have yet to start, in
+// the order they are due
+func (s *DeploymentService) ListScheduledDeployments(ctx context.Context, orgID string) ([]*models.Deployment, error) {
+	return s.repoManager.Deployment.ListScheduled(ctx, orgID)
+}
+
+// LoadScheduledDeployments schedules every deployment still waiting to start, e.g. after a
+// restart. Deployments that became due while the server was down start right away.
+func (s *DeploymentService) LoadScheduledDeployments(ctx context.Context) error {
+	deployments, err := s.repoManager.Deployment.ListScheduled(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	for _, deployment := range deployments {
+		s.scheduleTimer(deployment)
+	}
+	if len(deployments) > 0 {
+		log.Printf("Loaded %d scheduled deployments", len(deployments))
+	}
+
+	return nil
+}
+
+// createScheduledDeployment stores a deployment to be started at its scheduled time
+func (s *DeploymentService) createScheduledDeployment(ctx context.Context, deployment *models.Deployment) error {
+	if !deployment.ScheduledFor.After(time.Now()) {
+		return fmt.Errorf("%w: scheduledFor must be in the future", ErrInvalidSchedule)
+	}
+
+	deployment.Status = models.DeploymentStatusScheduled
+	if err := s.repoManager.Deployment.Create(ctx, deployment); err != nil {
+		return fmt.Errorf("failed to create deployment: %w", err)
+	}
+
+	if s.wsService != nil {
+		s.wsService.PublishDeploymentStatus(deployment)
+	}
+
+	s.scheduleTimer(deployment)
+	return nil
+}
+
+// scheduleTimer starts a scheduled deployment once it is due
+func (s *DeploymentService) scheduleTimer(deployment *models.Deployment) {
+	s.scheduleMutex.Lock()
+	defer s.scheduleMutex.Unlock()
+
+	if _, exists := s.scheduled[deployment.ID]; exists {
+		return
+	}
+
+	deploymentID := deployment.ID
+	s.scheduled[deploymentID] = time.AfterFunc(time.Until(*deployment.ScheduledFor), func() {
+		s.startScheduledDeployment(deploymentID)
+	})
+}
+
+// stopTimer stops a scheduled deployment from starting
+func (s *DeploymentService) stopTimer(deploymentID string) {
+	s.scheduleMutex.Lock()
+	defer s.scheduleMutex.Unlock()
+
+	if timer, exists := s.scheduled[deploymentID]; exists {
+		timer.Stop()
+		delete(s.scheduled, deploymentID)
+	}
+}
+
+// startScheduledDeployment starts a deployment whose scheduled time has come. A deployment that
+// was cancelled or deleted in the meantime is skipped, and one whose target is busy fails.
+func (s *DeploymentService) startScheduledDeployment(deploymentID string) {
+	s.stopTimer(deploymentID)
+	ctx := context.Background()
+
+	// Claiming the deployment keeps a concurrent cancel, or another server, from racing the start
+	claimed, err := s.repoManager.Deployment.TransitionStatus(ctx, deploymentID, models.DeploymentStatusScheduled, models.DeploymentStatusPending)
+	if err != nil {
+		log.Printf("Failed to start scheduled deployment %s: %v", deploymentID, err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	deployment, err := s.repoManager.Deployment.GetByID(ctx, deploymentID)
+	if err != nil {
+		log.Printf("Failed to start scheduled deployment %s: %v", deploymentID, err)
+		return
+	}
+
+	if err := s.acquireDeploymentLock(ctx, deployment); err != nil {
+		s.logger.LogError(ctx, deployment.ID, "Scheduled deployment could not start", map[string]interface{}{
+			"error": err.Error(),
+		})
+		s.orchestrator.updateDeploymentStatus(deployment, models.DeploymentStatusFailed, deployment.Progress)
+		return
+	}
+
+	s.logger.LogInfo(ctx, deployment.ID, "Starting scheduled deployment", map[string]interface{}{
+		"scheduledFor": deployment.ScheduledFor,
+	})
+	if s.wsService != nil {
+		s.wsService.PublishDeploymentStatus(deployment)
+	}
+
+	go s.orchestrator.StartDeployment(ctx, deployment)
+}
+
+// cancelScheduledDeployment cancels a deployment that has not started yet
+func (s *DeploymentService) cancelScheduledDeployment(ctx context.Context, deployment *models.Deployment, reason string) error {
+	cancelled, err := s.repoManager.Deployment.TransitionStatus(ctx, deployment.ID, models.DeploymentStatusScheduled, models.DeploymentStatusCancelled)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return fmt.Errorf("deployment has already started")
+	}
+	s.stopTimer(deployment.ID)
+
+	s.logger.LogWarning(ctx, deployment.ID, "Scheduled deployment cancelled", map[string]interface{}{
+		"reason": reason,
+	})
+
+	deployment.Status = models.DeploymentStatusCancelled
+	now := time.Now()
+	deployment.CompletedAt = &now
+	if err := s.repoManager.Deployment.Update(ctx, deployment); err != nil {
+		return err
+	}
+
+	if s.wsService != nil {
+		s.wsService.PublishDeploymentStatus(deployment)
+	}
+	return nil
+}
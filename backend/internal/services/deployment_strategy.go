@@ -0,0 +1,255 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// canaryErrorRateMetric is the metric reported against a deployment that canary health checks
+// and auto-rollback watches evaluate
+const canaryErrorRateMetric = "error_rate"
+
+// errCanaryUnhealthy is returned when a canary exceeds its error rate budget
+var errCanaryUnhealthy = errors.New("canary health check failed")
+
+// strategySteps returns the orchestration steps for the deployment's rollout strategy.
+// Every strategy shares the build and test steps and differs in how traffic moves to the new version.
+func (do *DeploymentOrchestrator) strategySteps(deployment *models.Deployment) []DeploymentStep {
+	steps := []DeploymentStep{
+		{Name: "validation", Duration: 2 * time.Second, Progress: 10},
+		{Name: "preparation", Duration: 3 * time.Second, Progress: 25},
+		{Name: "building", Duration: 10 * time.Second, Progress: 50},
+		{Name: "testing", Duration: 5 * time.Second, Progress: 70},
+	}
+
+	switch deployment.Strategy {
+	case models.DeploymentStrategyRecreate:
+		return append(steps,
+			DeploymentStep{Name: "stopping", Duration: 3 * time.Second, Progress: 80},
+			DeploymentStep{Name: "deploying", Duration: 5 * time.Second, Progress: 90},
+			DeploymentStep{Name: "verification", Duration: 3 * time.Second, Progress: 100},
+		)
+	case models.DeploymentStrategyCanary:
+		return append(steps,
+			DeploymentStep{Name: "canary", Duration: 3 * time.Second, Progress: 80, Run: do.startCanary},
+			DeploymentStep{Name: "health_check", Duration: 2 * time.Second, Progress: 85, Run: do.checkCanaryHealth},
+			DeploymentStep{Name: "promoting", Duration: 5 * time.Second, Progress: 95, Run: do.promoteCanary},
+			DeploymentStep{Name: "verification", Duration: 2 * time.Second, Progress: 100},
+		)
+	case models.DeploymentStrategyBlueGreen:
+		return append(steps,
+			DeploymentStep{Name: "deploying", Duration: 6 * time.Second, Progress: 85, Run: do.deployIdleColor},
+			DeploymentStep{Name: "verification", Duration: 3 * time.Second, Progress: 95},
+			DeploymentStep{Name: "switching", Duration: 2 * time.Second, Progress: 100, Run: do.switchTraffic},
+		)
+	default:
+		return append(steps,
+			DeploymentStep{Name: "deploying", Duration: 8 * time.Second, Progress: 90},
+			DeploymentStep{Name: "verification", Duration: 3 * time.Second, Progress: 100},
+		)
+	}
+}
+
+// initStrategyState records the pending steps of a deployment before it starts
+func initStrategyState(deployment *models.Deployment, steps []DeploymentStep) {
+	state := &deployment.StrategyState
+	state.Phase = models.StrategyPhaseRollingOut
+	state.TrafficPercentage = 0
+	state.Steps = make([]models.DeploymentStrategyStep, 0, len(steps))
+	for _, step := range steps {
+		state.Steps = append(state.Steps, models.DeploymentStrategyStep{
+			Name:   step.Name,
+			Status: models.PipelineStatusPending,
+		})
+	}
+}
+
+// setStrategyStepStatus updates a step's recorded state
+func setStrategyStepStatus(deployment *models.Deployment, name, status, message string) {
+	now := time.Now()
+	for i := range deployment.StrategyState.Steps {
+		step := &deployment.StrategyState.Steps[i]
+		if step.Name != name {
+			continue
+		}
+
+		step.Status = status
+		step.Message = message
+		if status == models.PipelineStatusRunning {
+			step.StartedAt = &now
+		} else {
+			step.CompletedAt = &now
+		}
+		return
+	}
+}
+
+// canaryConfig returns the deployment's canary settings with defaults applied
+func canaryConfig(deployment *models.Deployment) *models.CanaryConfig {
+	config := deployment.StrategyState.Canary
+	if config == nil {
+		config = &models.CanaryConfig{}
+		deployment.StrategyState.Canary = config
+	}
+
+	if config.Percentage == 0 {
+		config.Percentage = models.DefaultCanaryPercentage
+	}
+	if config.HealthCheckSeconds == 0 {
+		config.HealthCheckSeconds = models.DefaultCanaryHealthCheckSeconds
+	}
+	if config.MaxErrorRate == 0 {
+		config.MaxErrorRate = models.DefaultCanaryMaxErrorRate
+	}
+
+	return config
+}
+
+// startCanary routes the configured share of traffic to the new version
+func (do *DeploymentOrchestrator) startCanary(execution *DeploymentExecution) error {
+	deployment := execution.Deployment
+	config := canaryConfig(deployment)
+
+	deployment.StrategyState.TrafficPercentage = config.Percentage
+	do.repoManager.Deployment.Update(execution.Context, deployment)
+
+	execution.Logger.LogInfo(execution.Context, deployment.ID, fmt.Sprintf("Canary receiving %d%% of traffic", config.Percentage), map[string]interface{}{
+		"trafficPercentage": config.Percentage,
+	})
+	return nil
+}
+
+// checkCanaryHealth waits out the health check window and compares the canary's error rate
+// against its budget
+func (do *DeploymentOrchestrator) checkCanaryHealth(execution *DeploymentExecution) error {
+	deployment := execution.Deployment
+	config := canaryConfig(deployment)
+	window := time.Duration(config.HealthCheckSeconds) * time.Second
+
+	until := time.Now().Add(window)
+	deployment.StrategyState.Phase = models.StrategyPhaseHealthCheck
+	deployment.StrategyState.HealthCheckUntil = &until
+	do.repoManager.Deployment.Update(execution.Context, deployment)
+
+	select {
+	case <-execution.Context.Done():
+		return fmt.Errorf("health check cancelled")
+	case <-time.After(window):
+	}
+
+	errorRate, samples, err := do.deploymentErrorRate(execution.Context, deployment.ID, window)
+	if err != nil {
+		return fmt.Errorf("failed to get canary error rate: %w", err)
+	}
+
+	if samples == 0 {
+		execution.Logger.LogWarning(execution.Context, deployment.ID, "No error rate metrics reported for canary, promoting", nil)
+		return nil
+	}
+
+	deployment.StrategyState.ErrorRate = &errorRate
+	do.repoManager.Deployment.Update(execution.Context, deployment)
+
+	if errorRate > config.MaxErrorRate {
+		return fmt.Errorf("%w: error rate %.2f%% exceeds %.2f%%", errCanaryUnhealthy, errorRate, config.MaxErrorRate)
+	}
+
+	return nil
+}
+
+// deploymentErrorRate averages the error rate reported for a deployment over the window
+func (do *DeploymentOrchestrator) deploymentErrorRate(ctx context.Context, deploymentID string, window time.Duration) (float64, int, error) {
+	if do.metricsService == nil {
+		return 0, 0, nil
+	}
+
+	metrics, err := do.metricsService.GetResourceMetrics(ctx, deploymentID, window)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var total float64
+	var samples int
+	for _, metric := range metrics {
+		if metric.MetricName == canaryErrorRateMetric {
+			total += metric.Value
+			samples++
+		}
+	}
+
+	if samples == 0 {
+		return 0, 0, nil
+	}
+	return total / float64(samples), samples, nil
+}
+
+// promoteCanary routes all traffic to the new version
+func (do *DeploymentOrchestrator) promoteCanary(execution *DeploymentExecution) error {
+	deployment := execution.Deployment
+	deployment.StrategyState.Phase = models.StrategyPhasePromoted
+	deployment.StrategyState.TrafficPercentage = 100
+	do.repoManager.Deployment.Update(execution.Context, deployment)
+
+	execution.Logger.LogInfo(execution.Context, deployment.ID, "Canary promoted", nil)
+	return nil
+}
+
+// rollbackCanary moves all traffic back to the previous version after a failed health check
+func (do *DeploymentOrchestrator) rollbackCanary(execution *DeploymentExecution) {
+	deployment := execution.Deployment
+	deployment.StrategyState.Phase = models.StrategyPhaseRolledBack
+	deployment.StrategyState.TrafficPercentage = 0
+
+	execution.Logger.LogWarning(execution.Context, deployment.ID, "Canary rolled back automatically", map[string]interface{}{
+		"errorRate": deployment.StrategyState.ErrorRate,
+	})
+}
+
+// deployIdleColor deploys the new version alongside the live one without sending it traffic
+func (do *DeploymentOrchestrator) deployIdleColor(execution *DeploymentExecution) error {
+	deployment := execution.Deployment
+	idle := "green"
+	if previous := do.previousActiveColor(execution); previous == "green" {
+		idle = "blue"
+	}
+
+	execution.Logger.LogInfo(execution.Context, deployment.ID, fmt.Sprintf("Deployed new version to %s environment", idle), map[string]interface{}{
+		"color": idle,
+	})
+	deployment.StrategyState.ActiveColor = idle
+	return nil
+}
+
+// previousActiveColor finds the color serving traffic from the last blue-green deployment of
+// the same application and environment
+func (do *DeploymentOrchestrator) previousActiveColor(execution *DeploymentExecution) string {
+	deployment := execution.Deployment
+	recent, err := do.repoManager.Deployment.ListByEnvironment(execution.Context, deployment.OrganizationID, deployment.Environment, repositories.DefaultListParams())
+	if err != nil {
+		return ""
+	}
+
+	for _, previous := range recent {
+		if previous.ID != deployment.ID && previous.Application == deployment.Application &&
+			previous.Strategy == models.DeploymentStrategyBlueGreen && previous.Status == models.DeploymentStatusCompleted {
+			return previous.StrategyState.ActiveColor
+		}
+	}
+	return ""
+}
+
+// switchTraffic points all traffic at the newly deployed color
+func (do *DeploymentOrchestrator) switchTraffic(execution *DeploymentExecution) error {
+	deployment := execution.Deployment
+	deployment.StrategyState.Phase = models.StrategyPhaseSwitched
+	deployment.StrategyState.TrafficPercentage = 100
+	do.repoManager.Deployment.Update(execution.Context, deployment)
+
+	execution.Logger.LogInfo(execution.Context, deployment.ID, fmt.Sprintf("Switched traffic to %s", deployment.StrategyState.ActiveColor), nil)
+	return nil
+}
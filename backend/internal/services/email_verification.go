@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// Email verification errors
+var (
+	ErrEmailAlreadyVerified         = errors.New("email address is already verified")
+	ErrVerificationEmailRateLimited = errors.New("a verification email was sent recently, please wait before requesting another")
+	ErrEmailVerificationDisabled    = errors.New("email verification is not configured")
+)
+
+// emailVerificationResendInterval is the minimum time between verification emails to one user
+const emailVerificationResendInterval = time.Minute
+
+// SetEmailVerification sets the service that mails verification links and the URL the links point
+// to. Without it, new users are not sent verification emails.
+func (s *AuthService) SetEmailVerification(notifications *NotificationService, verificationURL string) {
+	s.notifications = notifications
+	s.verificationURL = verificationURL
+}
+
+// VerifyEmail marks the user a verification token was issued for as verified. Tokens issued for
+// an address the user no longer has are rejected.
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) (*models.User, error) {
+	claims, err := s.jwtService.ValidateEmailVerificationToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	if user.Email != claims.Email {
+		return nil, ErrInvalidToken
+	}
+
+	if !user.EmailVerified {
+		if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		user.EmailVerified = true
+	}
+
+	user.PasswordHash = ""
+	return user, nil
+}
+
+// ResendVerificationEmail sends a new verification link to an unverified user, at most once per
+// emailVerificationResendInterval
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, userID string) error {
+	if s.notifications == nil {
+		return ErrEmailVerificationDisabled
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.sendVerificationEmail(ctx, user)
+}
+
+// sendVerificationEmail mails a verification link to the user unless one was sent recently
+func (s *AuthService) sendVerificationEmail(ctx context.Context, user *models.User) error {
+	claimed, err := s.userRepo.ClaimVerificationEmail(ctx, user.ID, emailVerificationResendInterval)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrVerificationEmailRateLimited
+	}
+
+	token, err := s.jwtService.GenerateEmailVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return fmt.Errorf("failed to generate verification token: %w", err)
+	}
+
+	link, err := url.Parse(s.verificationURL)
+	if err != nil {
+		return fmt.Errorf("invalid email verification URL: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	body := fmt.Sprintf("Hi %s,\r\n\r\nPlease confirm your email address for CloudWeave by opening this link:\r\n\r\n%s\r\n\r\n"+
+		"The link expires in %s. If you did not create a CloudWeave account, you can ignore this email.\r\n",
+		user.Name, link.String(), emailVerificationTokenLifetime)
+
+	return s.notifications.SendEmail([]string{user.Email}, "Verify your CloudWeave email address", body)
+}
+
+// sendWelcomeVerification sends a newly registered user their first verification email in the
+// background, so a slow mail server does not hold up registration
+func (s *AuthService) sendWelcomeVerification(user models.User) {
+	if s.notifications == nil {
+		return
+	}
+
+	go func() {
+		if err := s.sendVerificationEmail(context.Background(), &user); err != nil {
+			log.Printf("Failed to send verification email to user %s: %v", user.ID, err)
+		}
+	}()
+}
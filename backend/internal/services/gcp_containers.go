@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"google.golang.org/api/googleapi"
+	run "google.golang.org/api/run/v2"
+)
+
+const (
+	// cloudRunRegion is the region container resources' Cloud Run services are created in
+	cloudRunRegion = "us-central1"
+
+	// Cloud Run list prices with CPU always allocated, per vCPU and per GB of memory
+	cloudRunVCPUHourlyCost = 0.0648
+	cloudRunGBHourlyCost   = 0.0072
+)
+
+// cloudRunSizes are the vCPUs Cloud Run services can have with CPU always allocated, and the most
+// memory, in MB, each can be given
+var cloudRunSizes = []struct {
+	cpu       float64
+	maxMemory int
+}{
+	{1, 4096},
+	{2, 8192},
+	{4, 16384},
+}
+
+// cloudRunSize picks the smallest Cloud Run size with at least the vCPUs and memory a container
+// asks for. Services with 4 vCPUs need at least 2 GB of memory.
+func cloudRunSize(cpu float64, memoryMB int) (float64, int) {
+	for _, size := range cloudRunSizes {
+		if size.cpu < cpu || size.maxMemory < memoryMB {
+			continue
+		}
+		if size.cpu == 4 {
+			memoryMB = max(memoryMB, 2048)
+		}
+		return size.cpu, memoryMB
+	}
+	last := cloudRunSizes[len(cloudRunSizes)-1]
+	return last.cpu, last.maxMemory
+}
+
+// isCloudRunServiceID reports whether a GCP external ID is the name of a Cloud Run service,
+// projects/<project>/locations/<region>/services/<name>
+func isCloudRunServiceID(externalID string) bool {
+	return strings.Contains(externalID, "/services/")
+}
+
+// cloudRunService describes the Cloud Run service of a container resource: one instance that is
+// always running with CPU always allocated, so it is billed like ECS and Container Instances
+func cloudRunService(infra *models.Infrastructure) *run.GoogleCloudRunV2Service {
+	spec := parseContainerSpec(infra.Specifications)
+	cpu, memoryMB := cloudRunSize(spec.CPU, spec.MemoryMB)
+
+	container := &run.GoogleCloudRunV2Container{
+		Image: spec.Image,
+		Resources: &run.GoogleCloudRunV2ResourceRequirements{
+			Limits: map[string]string{
+				"cpu":    strconv.FormatFloat(cpu, 'f', -1, 64),
+				"memory": fmt.Sprintf("%dMi", memoryMB),
+			},
+			CpuIdle:         false,
+			ForceSendFields: []string{"CpuIdle"},
+		},
+	}
+	for _, name := range sortedKeys(spec.Env) {
+		container.Env = append(container.Env, &run.GoogleCloudRunV2EnvVar{Name: name, Value: spec.Env[name]})
+	}
+	if spec.Port > 0 {
+		container.Ports = []*run.GoogleCloudRunV2ContainerPort{{ContainerPort: int64(spec.Port)}}
+	}
+
+	return &run.GoogleCloudRunV2Service{
+		Labels: map[string]string{
+			"cloudweave-id":      strings.ToLower(infra.ID),
+			"cloudweave-managed": "true",
+		},
+		Template: &run.GoogleCloudRunV2RevisionTemplate{
+			Containers: []*run.GoogleCloudRunV2Container{container},
+			Scaling: &run.GoogleCloudRunV2RevisionScaling{
+				MinInstanceCount: 1,
+				MaxInstanceCount: 1,
+			},
+		},
+	}
+}
+
+// createCloudRunService runs a container resource's image as a Cloud Run service. The service is
+// returned while Cloud Run is still deploying it; its status is pending until the deploy finishes.
+func (p *RealGCPProvider) createCloudRunService(ctx context.Context, infra *models.Infrastructure) (string, error) {
+	serviceName := containerServiceName(infra)
+	parent := fmt.Sprintf("projects/%s/locations/%s", p.projectID, cloudRunRegion)
+
+	_, err := p.runService.Projects.Locations.Services.Create(parent, cloudRunService(infra)).
+		ServiceId(serviceName).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to create Cloud Run service: %w", err)
+	}
+
+	return fmt.Sprintf("%s/services/%s", parent, serviceName), nil
+}
+
+// validateCloudRunService asks Cloud Run to validate the container resource's service without
+// creating it, which also rejects names already taken in the region
+func (p *RealGCPProvider) validateCloudRunService(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	serviceName := containerServiceName(infra)
+	if err := validateContainerName(serviceName); err != nil {
+		return nil, err
+	}
+	parent := fmt.Sprintf("projects/%s/locations/%s", p.projectID, cloudRunRegion)
+
+	_, err := p.runService.Projects.Locations.Services.Create(parent, cloudRunService(infra)).
+		ServiceId(serviceName).
+		ValidateOnly(true).
+		Context(ctx).
+		Do()
+	if err != nil {
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && (apiErr.Code == http.StatusConflict || apiErr.Code == http.StatusBadRequest) {
+			return nil, fmt.Errorf("%w: %s", ErrCreateRejected, apiErr.Message)
+		}
+		return nil, fmt.Errorf("failed to validate Cloud Run service: %w", err)
+	}
+
+	spec := parseContainerSpec(infra.Specifications)
+	cpu, memoryMB := cloudRunSize(spec.CPU, spec.MemoryMB)
+	plan := newCreatePlan(infra, serviceName, map[string]interface{}{
+		"image":  spec.Image,
+		"cpu":    cpu,
+		"memory": memoryMB,
+		"region": cloudRunRegion,
+	}, 0)
+	plan.setCostBreakdown([]CostComponent{
+		hourlyCostComponent(CostCategoryCompute, fmt.Sprintf("Cloud Run instance, %g vCPU and %d MB", cpu, memoryMB), cloudRunHourlyCost(cpu, memoryMB)),
+	})
+	return plan, nil
+}
+
+// cloudRunHourlyCost is the list price of running a Cloud Run instance of a size for an hour
+func cloudRunHourlyCost(cpu float64, memoryMB int) float64 {
+	return cpu*cloudRunVCPUHourlyCost + float64(memoryMB)/1024*cloudRunGBHourlyCost
+}
+
+// cloudRunServiceStatus maps a Cloud Run service's terminal condition to an infrastructure status
+func cloudRunServiceStatus(service *run.GoogleCloudRunV2Service) string {
+	if service.Reconciling || service.TerminalCondition == nil {
+		return models.InfraStatusPending
+	}
+	switch service.TerminalCondition.State {
+	case "CONDITION_SUCCEEDED":
+		return models.InfraStatusRunning
+	case "CONDITION_FAILED":
+		return models.InfraStatusError
+	default:
+		return models.InfraStatusPending
+	}
+}
+
+// getCloudRunServiceStatus gets Cloud Run service status
+func (p *RealGCPProvider) getCloudRunServiceStatus(ctx context.Context, externalID string) (string, error) {
+	service, err := p.runService.Projects.Locations.Services.Get(externalID).Context(ctx).Do()
+	if err != nil {
+		if isCloudResourceNotFound(err) {
+			return models.InfraStatusTerminated, nil
+		}
+		return models.InfraStatusError, fmt.Errorf("failed to get Cloud Run service: %w", err)
+	}
+	return cloudRunServiceStatus(service), nil
+}
+
+// getCloudRunServiceDetails gets detailed Cloud Run service information
+func (p *RealGCPProvider) getCloudRunServiceDetails(ctx context.Context, externalID string) (map[string]interface{}, error) {
+	service, err := p.runService.Projects.Locations.Services.Get(externalID).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Cloud Run service: %w", err)
+	}
+
+	// Service names are projects/<project>/locations/<region>/services/<name>
+	parts := strings.Split(service.Name, "/")
+	specs := map[string]interface{}{
+		"service_name":    parts[len(parts)-1],
+		"region":          parts[min(3, len(parts)-1)],
+		"uri":             service.Uri,
+		"latest_revision": service.LatestReadyRevision,
+		"created":         service.CreateTime,
+	}
+	var hourlyCost float64
+	if service.Template != nil && len(service.Template.Containers) > 0 {
+		container := service.Template.Containers[0]
+		specs["image"] = container.Image
+		if container.Resources != nil {
+			cpu, _ := strconv.ParseFloat(container.Resources.Limits["cpu"], 64)
+			memoryMB, _ := strconv.Atoi(strings.TrimSuffix(container.Resources.Limits["memory"], "Mi"))
+			specs["cpu"] = cpu
+			specs["memory"] = memoryMB
+			hourlyCost = cloudRunHourlyCost(cpu, memoryMB)
+		}
+	}
+
+	return map[string]interface{}{
+		"status":         cloudRunServiceStatus(service),
+		"specifications": specs,
+		"costInfo":       fixedCostInfo(hourlyCost),
+	}, nil
+}
+
+// cloudRunMetrics are the Cloud Monitoring metrics reported for a Cloud Run service over the last
+// hour, with the aligner that sums or peaks each series
+var cloudRunMetrics = []struct {
+	name       string
+	metricType string
+	aligner    string
+}{
+	{"request_count", "run.googleapis.com/request_count", "ALIGN_SUM"},
+	{"instance_count", "run.googleapis.com/container/instance_count", "ALIGN_MAX"},
+}
+
+// getCloudRunServiceMetrics reads a Cloud Run service's request and instance counts over the last
+// hour from Cloud Monitoring
+func (p *RealGCPProvider) getCloudRunServiceMetrics(ctx context.Context, externalID string) (map[string]interface{}, error) {
+	serviceName := externalID[strings.LastIndex(externalID, "/")+1:]
+	end := time.Now()
+	start := end.Add(-time.Hour)
+
+	metrics := map[string]interface{}{
+		"timestamp": end.Unix(),
+	}
+	for _, metric := range cloudRunMetrics {
+		filter := fmt.Sprintf(`metric.type = %q AND resource.type = "cloud_run_revision" AND resource.labels.service_name = %q`,
+			metric.metricType, serviceName)
+
+		result, err := p.monitoringService.Projects.TimeSeries.List("projects/" + p.projectID).
+			Filter(filter).
+			IntervalStartTime(start.Format(time.RFC3339)).
+			IntervalEndTime(end.Format(time.RFC3339)).
+			AggregationAlignmentPeriod("3600s").
+			AggregationPerSeriesAligner(metric.aligner).
+			AggregationCrossSeriesReducer("REDUCE_SUM").
+			Context(ctx).
+			Do()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Cloud Run metric %s: %w", metric.name, err)
+		}
+
+		var value int64
+		for _, series := range result.TimeSeries {
+			for _, point := range series.Points {
+				if point.Value != nil && point.Value.Int64Value != nil {
+					value += *point.Value.Int64Value
+				}
+			}
+		}
+		metrics[metric.name] = value
+	}
+	return metrics, nil
+}
+
+// deleteCloudRunService deletes a Cloud Run service, stopping its instances
+func (p *RealGCPProvider) deleteCloudRunService(ctx context.Context, externalID string) error {
+	_, err := p.runService.Projects.Locations.Services.Delete(externalID).Context(ctx).Do()
+	if err != nil && !isCloudResourceNotFound(err) {
+		return fmt.Errorf("failed to delete Cloud Run service: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,12 +10,21 @@ import (
 	"cloudweave/internal/models"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/compute/v1"
+	monitoring "google.golang.org/api/monitoring/v3"
+	run "google.golang.org/api/run/v2"
 )
 
 // RealGCPProvider implements CloudProvider for Google Cloud Platform using GCP SDK
 type RealGCPProvider struct {
-	projectID     string
-	storageClient *storage.Client
+	projectID         string
+	storageClient     *storage.Client
+	runService        *run.Service
+	monitoringService *monitoring.Service
+	computeService    *compute.Service
+
+	// Orphaned resource detection is not implemented for GCP yet
+	orphanDetectionUnsupported
 }
 
 // NewRealGCPProvider creates a new GCP provider with real GCP SDK integration
@@ -25,12 +35,44 @@ func NewRealGCPProvider(ctx context.Context, projectID string) (*RealGCPProvider
 		return nil, fmt.Errorf("failed to create storage client: %w", err)
 	}
 
+	// Initialize Cloud Run and Cloud Monitoring clients
+	runService, err := run.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Run client: %w", err)
+	}
+	monitoringService, err := monitoring.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Monitoring client: %w", err)
+	}
+	computeService, err := compute.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Compute Engine client: %w", err)
+	}
+
 	return &RealGCPProvider{
-		projectID:     projectID,
-		storageClient: storageClient,
+		projectID:         projectID,
+		storageClient:     storageClient,
+		runService:        runService,
+		monitoringService: monitoringService,
+		computeService:    computeService,
 	}, nil
 }
 
+// ListRegions lists the Compute Engine regions available to the project
+func (p *RealGCPProvider) ListRegions(ctx context.Context) ([]string, error) {
+	var regions []string
+	err := p.computeService.Regions.List(p.projectID).Pages(ctx, func(page *compute.RegionList) error {
+		for _, region := range page.Items {
+			regions = append(regions, region.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list regions: %w", err)
+	}
+	return regions, nil
+}
+
 // CreateResource creates infrastructure resources in GCP
 func (p *RealGCPProvider) CreateResource(ctx context.Context, infra *models.Infrastructure) (string, error) {
 	switch infra.Type {
@@ -40,6 +82,8 @@ func (p *RealGCPProvider) CreateResource(ctx context.Context, infra *models.Infr
 		return p.createCloudSQLInstance(ctx, infra)
 	case models.InfraTypeStorage:
 		return p.createStorageBucket(ctx, infra)
+	case models.InfraTypeContainer:
+		return p.createCloudRunService(ctx, infra)
 	default:
 		return "", fmt.Errorf("unsupported infrastructure type: %s", infra.Type)
 	}
@@ -69,9 +113,61 @@ func (p *RealGCPProvider) createStorageBucket(ctx context.Context, infra *models
 	return bucketName, nil
 }
 
+// gcpUnvalidatedWarning notes that GCP was not asked whether it would create a resource
+const gcpUnvalidatedWarning = "Not validated with GCP; the resource was only checked against CloudWeave's rules"
+
+// ValidateCreate checks that GCP would create the resource, without creating it. Compute Engine
+// and Cloud SQL provisioning are placeholders, so only storage buckets and Cloud Run services are
+// checked with GCP.
+func (p *RealGCPProvider) ValidateCreate(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	switch infra.Type {
+	case models.InfraTypeServer:
+		machineType := "e2-medium" // Default
+		if specs, ok := infra.Specifications["machine_type"].(string); ok {
+			machineType = specs
+		}
+		plan := newCreatePlan(infra, infra.Name, map[string]interface{}{
+			"machine_type": machineType,
+		}, p.getComputeHourlyCost(machineType))
+		plan.Warnings = append(plan.Warnings, gcpUnvalidatedWarning)
+		return plan, nil
+	case models.InfraTypeDatabase:
+		plan := newCreatePlan(infra, infra.Name, map[string]interface{}{
+			"tier": "db-f1-micro",
+		}, p.getSQLHourlyCost("db-f1-micro"))
+		plan.Warnings = append(plan.Warnings, gcpUnvalidatedWarning)
+		return plan, nil
+	case models.InfraTypeStorage:
+		return p.validateStorageBucket(ctx, infra)
+	case models.InfraTypeContainer:
+		return p.validateCloudRunService(ctx, infra)
+	default:
+		return nil, fmt.Errorf("%w: unsupported infrastructure type: %s", ErrCreateRejected, infra.Type)
+	}
+}
+
+// validateStorageBucket checks that the storage resource's bucket does not exist yet
+func (p *RealGCPProvider) validateStorageBucket(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	bucketName := fmt.Sprintf("%s-%s", p.projectID, infra.Name)
+
+	_, err := p.storageClient.Bucket(bucketName).Attrs(ctx)
+	if err == nil {
+		return nil, fmt.Errorf("%w: storage bucket %s already exists", ErrCreateRejected, bucketName)
+	}
+	if !errors.Is(err, storage.ErrBucketNotExist) {
+		return nil, fmt.Errorf("failed to validate storage bucket: %w", err)
+	}
+
+	plan := newCreatePlan(infra, bucketName, map[string]interface{}{}, 0)
+	plan.Warnings = append(plan.Warnings, usageBilledWarning)
+	return plan, nil
+}
+
 // GetResourceStatus retrieves the status of a GCP resource
 func (p *RealGCPProvider) GetResourceStatus(ctx context.Context, externalID string) (string, error) {
-	if strings.Contains(externalID, "/instances/") && strings.Contains(externalID, "/zones/") {
+	if isCloudRunServiceID(externalID) {
+		return p.getCloudRunServiceStatus(ctx, externalID)
+	} else if strings.Contains(externalID, "/instances/") && strings.Contains(externalID, "/zones/") {
 		return p.getComputeInstanceStatus(ctx, externalID)
 	} else if strings.Contains(externalID, "/instances/") && !strings.Contains(externalID, "/zones/") {
 		return p.getCloudSQLInstanceStatus(ctx, externalID)
@@ -106,6 +202,10 @@ func (p *RealGCPProvider) getStorageBucketStatus(ctx context.Context, bucketName
 
 // GetResourceMetrics retrieves metrics for GCP resources
 func (p *RealGCPProvider) GetResourceMetrics(ctx context.Context, externalID string) (map[string]interface{}, error) {
+	if isCloudRunServiceID(externalID) {
+		return p.getCloudRunServiceMetrics(ctx, externalID)
+	}
+
 	// For now, return simulated metrics
 	// In a real implementation, this would use Cloud Monitoring API
 	return map[string]interface{}{
@@ -119,7 +219,9 @@ func (p *RealGCPProvider) GetResourceMetrics(ctx context.Context, externalID str
 
 // GetResourceDetails gets detailed information about GCP resources
 func (p *RealGCPProvider) GetResourceDetails(ctx context.Context, externalID string) (map[string]interface{}, error) {
-	if strings.Contains(externalID, "/instances/") && strings.Contains(externalID, "/zones/") {
+	if isCloudRunServiceID(externalID) {
+		return p.getCloudRunServiceDetails(ctx, externalID)
+	} else if strings.Contains(externalID, "/instances/") && strings.Contains(externalID, "/zones/") {
 		return p.getComputeInstanceDetails(ctx, externalID)
 	} else if strings.Contains(externalID, "/instances/") && !strings.Contains(externalID, "/zones/") {
 		return p.getCloudSQLInstanceDetails(ctx, externalID)
@@ -131,32 +233,40 @@ func (p *RealGCPProvider) GetResourceDetails(ctx context.Context, externalID str
 // getComputeInstanceDetails gets detailed Compute Engine instance information
 func (p *RealGCPProvider) getComputeInstanceDetails(ctx context.Context, externalID string) (map[string]interface{}, error) {
 	// Placeholder implementation for now
+	machineType := "e2-medium"
 	return map[string]interface{}{
 		"id":                 externalID,
 		"name":               "placeholder-instance",
-		"machine_type":       "e2-medium",
+		"machine_type":       machineType,
 		"zone":               "us-central1-a",
 		"status":             "RUNNING",
 		"cpu_platform":       "Intel Haswell",
 		"creation_timestamp": time.Now().Format(time.RFC3339),
+		"costInfo":           fixedCostInfo(p.getComputeHourlyCost(machineType)),
 	}, nil
 }
 
 // getCloudSQLInstanceDetails gets detailed Cloud SQL instance information
 func (p *RealGCPProvider) getCloudSQLInstanceDetails(ctx context.Context, externalID string) (map[string]interface{}, error) {
 	// Placeholder implementation for now
+	tier := "db-f1-micro"
 	return map[string]interface{}{
 		"id":                 externalID,
 		"name":               "placeholder-sql-instance",
 		"database_version":   "MYSQL_8_0",
 		"region":             "us-central1",
-		"tier":               "db-f1-micro",
+		"tier":               tier,
 		"state":              "RUNNABLE",
 		"creation_timestamp": time.Now().Format(time.RFC3339),
+		"costInfo":           fixedCostInfo(p.getSQLHourlyCost(tier)),
 	}, nil
 }
 
-// getStorageBucketDetails gets detailed Cloud Storage bucket information
+// gcsStandardGBMonthlyCost is the Cloud Storage Standard regional storage price per GB-month
+const gcsStandardGBMonthlyCost = 0.020
+
+// getStorageBucketDetails gets detailed Cloud Storage bucket information. Bucket attributes do not
+// include its size, so its cost info carries the storage price with no usage.
 func (p *RealGCPProvider) getStorageBucketDetails(ctx context.Context, bucketName string) (map[string]interface{}, error) {
 	bucket := p.storageClient.Bucket(bucketName)
 
@@ -171,12 +281,15 @@ func (p *RealGCPProvider) getStorageBucketDetails(ctx context.Context, bucketNam
 		"storage_class":      attrs.StorageClass,
 		"created":            attrs.Created.Format(time.RFC3339),
 		"versioning_enabled": attrs.VersioningEnabled,
+		"costInfo":           usageCostInfo(costUnitGBMonth, gcsStandardGBMonthlyCost, 0),
 	}, nil
 }
 
 // DeleteResource deletes a GCP resource
 func (p *RealGCPProvider) DeleteResource(ctx context.Context, externalID string) error {
-	if strings.Contains(externalID, "/instances/") && strings.Contains(externalID, "/zones/") {
+	if isCloudRunServiceID(externalID) {
+		return p.deleteCloudRunService(ctx, externalID)
+	} else if strings.Contains(externalID, "/instances/") && strings.Contains(externalID, "/zones/") {
 		return p.deleteComputeInstance(ctx, externalID)
 	} else if strings.Contains(externalID, "/instances/") && !strings.Contains(externalID, "/zones/") {
 		return p.deleteCloudSQLInstance(ctx, externalID)
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// ErrInvalidIdempotencyKey is returned when an idempotency key is too long to be stored
+var ErrInvalidIdempotencyKey = errors.New("invalid idempotency key")
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with a different request
+var ErrIdempotencyKeyReused = errors.New("idempotency key was used for a different request")
+
+// ErrIdempotencyKeyInProgress is returned when an idempotency key is sent again before the
+// original request has finished
+var ErrIdempotencyKeyInProgress = errors.New("a request with this idempotency key is still in progress")
+
+// Idempotency key limits
+const (
+	DefaultIdempotencyKeyTTL = 24 * time.Hour
+	maxIdempotencyKeyLength  = 255
+)
+
+// IdempotencyService lets clients safely retry resource-creating requests by replaying the
+// response of the first request made with the same key
+type IdempotencyService struct {
+	idempotencyRepo repositories.IdempotencyKeyRepositoryInterface
+	ttl             time.Duration
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService(idempotencyRepo repositories.IdempotencyKeyRepositoryInterface) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             DefaultIdempotencyKeyTTL,
+	}
+}
+
+// SetTTL sets how long an idempotency key and its response are kept
+func (s *IdempotencyService) SetTTL(ttl time.Duration) {
+	if ttl > 0 {
+		s.ttl = ttl
+	}
+}
+
+// IdempotencyRequestHash fingerprints a request so a reused key can be matched to the original
+func IdempotencyRequestHash(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Begin claims a key for a request. It returns nil when the request should be processed, or the
+// stored record when it repeats a completed request whose response should be replayed.
+func (s *IdempotencyService) Begin(ctx context.Context, orgID, key, requestHash string) (*models.IdempotencyKey, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, fmt.Errorf("%w: must be at most %d characters", ErrInvalidIdempotencyKey, maxIdempotencyKeyLength)
+	}
+
+	reserved, err := s.idempotencyRepo.Reserve(ctx, orgID, key, requestHash, time.Now().Add(s.ttl))
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	record, err := s.idempotencyRepo.Get(ctx, orgID, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	if record.StatusCode == 0 {
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	return record, nil
+}
+
+// Complete stores the response of a request so repeats of it can be replayed
+func (s *IdempotencyService) Complete(ctx context.Context, orgID, key string, statusCode int, contentType string, body []byte) error {
+	return s.idempotencyRepo.Complete(ctx, orgID, key, statusCode, contentType, body)
+}
+
+// Release frees a key whose request failed so that a retry is processed again
+func (s *IdempotencyService) Release(ctx context.Context, orgID, key string) error {
+	return s.idempotencyRepo.Delete(ctx, orgID, key)
+}
+
+// StartExpiryCleanup periodically removes expired idempotency keys until ctx is cancelled
+func (s *IdempotencyService) StartExpiryCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.idempotencyRepo.DeleteExpired(ctx)
+			if err != nil {
+				log.Printf("Idempotency key cleanup failed: %v", err)
+				continue
+			}
+			if count > 0 {
+				log.Printf("Removed %d expired idempotency keys", count)
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrImpersonationTargetNotFound is returned when the user to impersonate is not a member of
+	// the impersonator's organization
+	ErrImpersonationTargetNotFound = errors.New("user to impersonate not found")
+
+	// ErrImpersonationNotAllowed is returned when impersonating a user would act beyond the
+	// impersonator's own permissions
+	ErrImpersonationNotAllowed = errors.New("impersonation not allowed")
+
+	// ErrNotImpersonating is returned when ending impersonation with a token that does not
+	// impersonate anyone
+	ErrNotImpersonating = errors.New("token is not impersonating a user")
+)
+
+// ImpersonationService lets support users act as a user of their organization to reproduce what
+// the user sees. Impersonation tokens act with the target user's permissions only, in the
+// impersonator's organization, and carry the impersonator so everything done with them is audited
+// as theirs.
+type ImpersonationService struct {
+	userRepo    repositories.UserRepositoryInterface
+	orgRepo     repositories.OrganizationRepositoryInterface
+	jwtService  *JWTService
+	rbacService *RBACService
+	auditWriter *AuditWriter
+}
+
+// NewImpersonationService creates a new impersonation service
+func NewImpersonationService(
+	userRepo repositories.UserRepositoryInterface,
+	orgRepo repositories.OrganizationRepositoryInterface,
+	jwtService *JWTService,
+	rbacService *RBACService,
+	auditWriter *AuditWriter,
+) *ImpersonationService {
+	return &ImpersonationService{
+		userRepo:    userRepo,
+		orgRepo:     orgRepo,
+		jwtService:  jwtService,
+		rbacService: rbacService,
+		auditWriter: auditWriter,
+	}
+}
+
+// Start issues a short-lived token acting as a member of the impersonator's organization. Users
+// holding permissions the impersonator lacks can only be impersonated by organization admins, so
+// impersonation cannot be used to gain permissions.
+func (s *ImpersonationService) Start(ctx context.Context, impersonatorID, organizationID, targetID, reason string) (*models.ImpersonationSession, error) {
+	if targetID == impersonatorID {
+		return nil, fmt.Errorf("%w: users cannot impersonate themselves", ErrImpersonationNotAllowed)
+	}
+	if _, err := uuid.Parse(targetID); err != nil {
+		return nil, ErrImpersonationTargetNotFound
+	}
+
+	membership, err := s.orgRepo.GetUserMembership(ctx, targetID, organizationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrImpersonationTargetNotFound
+		}
+		return nil, err
+	}
+	user, err := s.userRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, ErrImpersonationTargetNotFound
+	}
+	user.OrganizationID = membership.OrganizationID
+	user.Role = membership.Role
+
+	if err := s.checkNoEscalation(ctx, impersonatorID, targetID, organizationID); err != nil {
+		return nil, err
+	}
+
+	token, tokenID, expiresAt, err := s.jwtService.GenerateImpersonationToken(*user, impersonatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate impersonation token: %w", err)
+	}
+
+	log.Printf("User %s started impersonating user %s in organization %s until %s", impersonatorID, targetID, organizationID, expiresAt.Format(time.RFC3339))
+	s.audit(ctx, models.ActionImpersonationStarted, impersonatorID, targetID, organizationID, map[string]interface{}{
+		"reason":           reason,
+		"impersonationJti": tokenID,
+		"expiresAt":        expiresAt,
+	})
+
+	return &models.ImpersonationSession{
+		Token:          token,
+		User:           *user,
+		OrganizationID: organizationID,
+		ImpersonatedBy: impersonatorID,
+		ExpiresAt:      expiresAt,
+	}, nil
+}
+
+// checkNoEscalation refuses impersonating a user granted a permission the impersonator does not
+// hold in the organization, organization-wide or on a resource, unless the impersonator is an
+// organization admin
+func (s *ImpersonationService) checkNoEscalation(ctx context.Context, impersonatorID, targetID, organizationID string) error {
+	impersonator, err := s.rbacService.GetUserPermissions(ctx, impersonatorID, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to get impersonator permissions: %w", err)
+	}
+	if impersonator.IsAdmin {
+		return nil
+	}
+
+	target, err := s.rbacService.GetUserPermissions(ctx, targetID, organizationID)
+	if err != nil {
+		return fmt.Errorf("failed to get user permissions: %w", err)
+	}
+	if target.IsAdmin {
+		return fmt.Errorf("%w: only admins can impersonate admins", ErrImpersonationNotAllowed)
+	}
+	for _, perm := range target.Permissions {
+		if !hasPermission(impersonator.Permissions, perm) {
+			return fmt.Errorf("%w: user has permission %s that the impersonator lacks", ErrImpersonationNotAllowed, perm)
+		}
+	}
+	for resourceType, resources := range target.ResourcePerms {
+		for resourceID, perms := range resources {
+			granted := append(append([]string(nil), impersonator.Permissions...), impersonator.ResourcePerms[resourceType][resourceID]...)
+			for _, perm := range perms {
+				if !hasPermission(granted, perm) {
+					return fmt.Errorf("%w: user has permission %s on %s %s that the impersonator lacks", ErrImpersonationNotAllowed, perm, resourceType, resourceID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// End revokes an impersonation token before it expires
+func (s *ImpersonationService) End(ctx context.Context, token string) error {
+	claims, err := s.jwtService.ParseToken(token)
+	if err != nil {
+		return fmt.Errorf("failed to parse token: %w", err)
+	}
+	if claims.ImpersonatedBy == "" {
+		return ErrNotImpersonating
+	}
+
+	if err := s.jwtService.BlacklistToken(ctx, token, "impersonation_ended"); err != nil {
+		return fmt.Errorf("failed to revoke impersonation token: %w", err)
+	}
+
+	log.Printf("User %s stopped impersonating user %s in organization %s", claims.ImpersonatedBy, claims.UserID, claims.OrganizationID)
+	s.audit(ctx, models.ActionImpersonationEnded, claims.ImpersonatedBy, claims.UserID, claims.OrganizationID, map[string]interface{}{
+		"impersonationJti": claims.TokenID,
+	})
+	return nil
+}
+
+// audit records an impersonation event against the impersonated user, attributed to the
+// impersonator
+func (s *ImpersonationService) audit(ctx context.Context, action, impersonatorID, targetID, organizationID string, details map[string]interface{}) {
+	if s.auditWriter == nil {
+		return
+	}
+
+	resourceType := "user"
+	auditLog := &models.AuditLog{
+		ID:             uuid.New().String(),
+		OrganizationID: organizationID,
+		UserID:         &targetID,
+		Action:         action,
+		ResourceType:   &resourceType,
+		ResourceID:     &targetID,
+		Details:        details,
+		CreatedAt:      time.Now(),
+	}
+	AuditContextFromContext(ctx).apply(auditLog)
+	auditLog.ImpersonatedBy = &impersonatorID
+
+	s.auditWriter.Write(auditLog)
+}
@@ -2,18 +2,38 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"os"
+	"sync"
 	"time"
 
 	"cloudweave/internal/models"
 	"cloudweave/internal/repositories"
 )
 
+// DefaultInfrastructureDeletedRetention is how long soft-deleted infrastructure can be restored
+const DefaultInfrastructureDeletedRetention = 30 * 24 * time.Hour
+
+// ErrInfrastructureNotRestorable is returned when a resource is not deleted or its restore window has passed
+var ErrInfrastructureNotRestorable = errors.New("infrastructure resource is not deleted or is past its restore window")
+
 type InfrastructureService struct {
 	repoManager      *repositories.RepositoryManager
 	cloudProviders   map[string]CloudProvider
 	metricsCollector *MetricsCollector
+	deletedRetention time.Duration
+	alertService     *AlertService
+	resourceCache    *ResourceCache
+	webhooks         *WebhookService
+	provisionTimeout time.Duration
+	jobs             *JobService
+	regions          *RegionResolver
+	awsAccounts      *AWSAccounts
+
+	// provisioning tracks background provisioning so shutdown can wait for it
+	provisioning sync.WaitGroup
 }
 
 func NewInfrastructureService(repoManager *repositories.RepositoryManager) *InfrastructureService {
@@ -21,6 +41,8 @@ func NewInfrastructureService(repoManager *repositories.RepositoryManager) *Infr
 		repoManager:      repoManager,
 		cloudProviders:   make(map[string]CloudProvider),
 		metricsCollector: NewMetricsCollector(repoManager),
+		deletedRetention: DefaultInfrastructureDeletedRetention,
+		provisionTimeout: DefaultProvisionTimeout,
 	}
 
 	// Initialize cloud providers with real implementations
@@ -57,41 +79,148 @@ func NewInfrastructureService(repoManager *repositories.RepositoryManager) *Infr
 		service.cloudProviders[models.ProviderAzure] = azureProvider
 	}
 
+	service.regions = NewRegionResolver(service.cloudProviders, DefaultRegionCacheTTL)
+
 	return service
 }
 
-// CreateInfrastructure creates infrastructure and provisions it with the cloud provider
+// SetAWSAccounts sets where the AWS accounts organizations manage through their own credentials
+// come from. Without it, every organization's AWS resources are managed with the platform's
+// account.
+func (s *InfrastructureService) SetAWSAccounts(accounts *AWSAccounts) {
+	s.awsAccounts = accounts
+}
+
+// providerFor returns the provider that manages an organization's resources with a cloud
+// provider: for AWS, the account of the organization's credentials when it has any
+func (s *InfrastructureService) providerFor(ctx context.Context, orgID, providerName string) (CloudProvider, error) {
+	provider, err := s.awsAccounts.providerFor(ctx, orgID, providerName, s.cloudProviders)
+	if err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedProvider, providerName)
+	}
+	return provider, nil
+}
+
+// ListRegions returns the regions resources can be created in with a provider
+func (s *InfrastructureService) ListRegions(ctx context.Context, provider string) []string {
+	return s.regions.AdvertisedRegions(ctx, provider)
+}
+
+// CreateInfrastructure validates and records new infrastructure and starts provisioning it with
+// the cloud provider in the background. The resource stays pending until provisioning finishes,
+// then moves to running, or to error if the provider fails or does not finish in time.
 func (s *InfrastructureService) CreateInfrastructure(ctx context.Context, infra *models.Infrastructure) error {
+	provider, err := s.prepareCreate(ctx, infra)
+	if err != nil {
+		return err
+	}
+
 	// Create in database first
 	if err := s.repoManager.Infrastructure.Create(ctx, infra); err != nil {
 		return fmt.Errorf("failed to create infrastructure in database: %w", err)
 	}
 
-	// Get cloud provider
-	provider, exists := s.cloudProviders[infra.Provider]
-	if !exists {
-		return fmt.Errorf("unsupported cloud provider: %s", infra.Provider)
+	// Provision without the request context so provisioning outlives the request
+	provisioned := *infra
+	s.startProvisioning(&provisioned, func(ctx context.Context) (string, error) {
+		return provider.CreateResource(ctx, &provisioned)
+	})
+
+	return nil
+}
+
+// startProvisioning provisions a resource in the background, tracked so shutdown can wait for it
+func (s *InfrastructureService) startProvisioning(infra *models.Infrastructure, create func(ctx context.Context) (string, error)) {
+	s.provisioning.Add(1)
+	go func() {
+		defer s.provisioning.Done()
+		s.provisionInfrastructure(infra, create)
+	}()
+}
+
+// WaitForProvisioning waits for background provisioning to finish, or for ctx to be done.
+// Resources still provisioning when it gives up stay pending and are failed by the pending
+// reconciliation once the provision timeout has passed.
+func (s *InfrastructureService) WaitForProvisioning(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.provisioning.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// StartPendingReconciliation fails resources left pending by provisioning that was interrupted,
+// such as by a restart, once at startup and then every interval until the context is cancelled.
+// Only resources pending for longer than the provision timeout are failed, since provisioning that
+// is still running, here or on another instance, finishes or fails within it.
+func (s *InfrastructureService) StartPendingReconciliation(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		failed, err := s.repoManager.Infrastructure.FailStalePending(ctx, time.Now().Add(-s.provisionTimeout))
+		if err != nil {
+			log.Printf("Pending infrastructure reconciliation failed: %v", err)
+		}
+		for _, id := range failed {
+			log.Printf("Infrastructure %s was left pending by interrupted provisioning, marked as error", id)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
 	}
+}
+
+// provisionInfrastructure creates the resource with the cloud provider through create, which
+// returns its external ID, and records the result
+func (s *InfrastructureService) provisionInfrastructure(infra *models.Infrastructure, create func(ctx context.Context) (string, error)) {
+	ctx := context.Background()
 
-	// Provision infrastructure with cloud provider
-	externalID, err := provider.CreateResource(ctx, infra)
+	var externalID string
+	err := callProvider(ctx, s.provisionTimeout, func(ctx context.Context) error {
+		var err error
+		externalID, err = create(ctx)
+		return err
+	})
 	if err != nil {
+		log.Printf("Failed to provision infrastructure %s: %v", infra.ID, err)
 		// Update status to error
 		s.repoManager.Infrastructure.UpdateStatus(ctx, infra.ID, models.InfraStatusError)
-		return fmt.Errorf("failed to provision infrastructure: %w", err)
+		return
 	}
 
 	// Update with external ID and running status
 	infra.ExternalID = &externalID
 	infra.Status = models.InfraStatusRunning
 	if err := s.repoManager.Infrastructure.Update(ctx, infra); err != nil {
-		return fmt.Errorf("failed to update infrastructure with external ID: %w", err)
+		log.Printf("Failed to update infrastructure %s with external ID: %v", infra.ID, err)
+		return
 	}
 
 	// Start metrics collection
 	go s.metricsCollector.StartCollection(ctx, infra)
 
-	return nil
+	s.PublishEvent(ctx, models.WebhookEventInfrastructureCreated, infra)
+}
+
+// SetProvisionTimeout sets how long the cloud provider may take to create a resource
+func (s *InfrastructureService) SetProvisionTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		s.provisionTimeout = timeout
+	}
 }
 
 // GetRealTimeStatus gets the current status from the cloud provider
@@ -100,12 +229,14 @@ func (s *InfrastructureService) GetRealTimeStatus(ctx context.Context, infra *mo
 		return infra.Status, nil
 	}
 
-	provider, exists := s.cloudProviders[infra.Provider]
-	if !exists {
-		return infra.Status, fmt.Errorf("unsupported cloud provider: %s", infra.Provider)
+	provider, err := s.providerFor(ctx, infra.OrganizationID, infra.Provider)
+	if err != nil {
+		return infra.Status, err
 	}
 
-	return provider.GetResourceStatus(ctx, *infra.ExternalID)
+	return providerValue(withResourceRegion(ctx, infra.Region), func(ctx context.Context) (string, error) {
+		return provider.GetResourceStatus(ctx, *infra.ExternalID)
+	})
 }
 
 // GetMetrics retrieves real-time metrics for infrastructure
@@ -114,12 +245,14 @@ func (s *InfrastructureService) GetMetrics(ctx context.Context, infra *models.In
 		return nil, fmt.Errorf("infrastructure has no external ID")
 	}
 
-	provider, exists := s.cloudProviders[infra.Provider]
-	if !exists {
-		return nil, fmt.Errorf("unsupported cloud provider: %s", infra.Provider)
+	provider, err := s.providerFor(ctx, infra.OrganizationID, infra.Provider)
+	if err != nil {
+		return nil, err
 	}
 
-	return provider.GetResourceMetrics(ctx, *infra.ExternalID)
+	return providerValue(withResourceRegion(ctx, infra.Region), func(ctx context.Context) (map[string]interface{}, error) {
+		return provider.GetResourceMetrics(ctx, *infra.ExternalID)
+	})
 }
 
 // SyncWithProvider syncs infrastructure state with cloud provider
@@ -128,14 +261,17 @@ func (s *InfrastructureService) SyncWithProvider(ctx context.Context, infra *mod
 		return infra, nil
 	}
 
-	provider, exists := s.cloudProviders[infra.Provider]
-	if !exists {
-		return nil, fmt.Errorf("unsupported cloud provider: %s", infra.Provider)
+	provider, err := s.providerFor(ctx, infra.OrganizationID, infra.Provider)
+	if err != nil {
+		return nil, err
 	}
 
 	// Get current state from provider
-	providerData, err := provider.GetResourceDetails(ctx, *infra.ExternalID)
+	providerData, err := providerValue(withResourceRegion(ctx, infra.Region), func(ctx context.Context) (map[string]interface{}, error) {
+		return provider.GetResourceDetails(ctx, *infra.ExternalID)
+	})
 	if err != nil {
+		s.recordSyncResult(ctx, infra, err)
 		return nil, fmt.Errorf("failed to get resource details from provider: %w", err)
 	}
 
@@ -154,31 +290,124 @@ func (s *InfrastructureService) SyncWithProvider(ctx context.Context, infra *mod
 	if err := s.repoManager.Infrastructure.Update(ctx, infra); err != nil {
 		return nil, fmt.Errorf("failed to update infrastructure: %w", err)
 	}
+	s.resourceCache.Invalidate(infra)
+	s.recordSyncResult(ctx, infra, nil)
+	s.PublishEvent(ctx, models.WebhookEventInfrastructureUpdated, infra)
 
 	return infra, nil
 }
 
+// recordSyncResult adds the outcome of syncing a resource with its provider to its change log.
+// Fields the sync changed are logged separately as an update.
+func (s *InfrastructureService) recordSyncResult(ctx context.Context, infra *models.Infrastructure, syncErr error) {
+	changeType := models.InfraChangeSynced
+	var message *string
+	if syncErr != nil {
+		changeType = models.InfraChangeSyncFailed
+		text := syncErr.Error()
+		message = &text
+	}
+
+	if err := s.repoManager.Infrastructure.RecordChange(ctx, infra.ID, changeType, nil, message); err != nil {
+		log.Printf("Failed to record sync of infrastructure %s: %v", infra.ID, err)
+	}
+}
+
 // DeleteFromProvider deletes infrastructure from cloud provider
 func (s *InfrastructureService) DeleteFromProvider(ctx context.Context, infra *models.Infrastructure) error {
 	if infra.ExternalID == nil {
 		return nil
 	}
 
-	provider, exists := s.cloudProviders[infra.Provider]
-	if !exists {
-		return fmt.Errorf("unsupported cloud provider: %s", infra.Provider)
+	provider, err := s.providerFor(ctx, infra.OrganizationID, infra.Provider)
+	if err != nil {
+		return err
+	}
+
+	err = callProvider(withResourceRegion(ctx, infra.Region), ProviderCallTimeout(), func(ctx context.Context) error {
+		return provider.DeleteResource(ctx, *infra.ExternalID)
+	})
+	if err != nil {
+		return err
 	}
+	s.resourceCache.Invalidate(infra)
+
+	return nil
+}
+
+// SetResourceCache sets the cache of provider data to invalidate when resources change
+func (s *InfrastructureService) SetResourceCache(cache *ResourceCache) {
+	s.resourceCache = cache
+}
+
+// InvalidateCachedResource drops cached provider data for a resource after it changes
+func (s *InfrastructureService) InvalidateCachedResource(infra *models.Infrastructure) {
+	s.resourceCache.Invalidate(infra)
+}
+
+// SetWebhookService sets the service that posts infrastructure lifecycle events to webhooks
+func (s *InfrastructureService) SetWebhookService(webhooks *WebhookService) {
+	s.webhooks = webhooks
+}
+
+// PublishEvent posts an infrastructure lifecycle event to the resource's organization's webhooks
+func (s *InfrastructureService) PublishEvent(ctx context.Context, eventType string, infra *models.Infrastructure) {
+	s.webhooks.Emit(ctx, infra.OrganizationID, eventType, infra)
+}
 
-	return provider.DeleteResource(ctx, *infra.ExternalID)
+// SetDeletedRetention sets how long soft-deleted infrastructure is kept before it is purged
+func (s *InfrastructureService) SetDeletedRetention(retention time.Duration) {
+	if retention > 0 {
+		s.deletedRetention = retention
+	}
+}
+
+// RestoreInfrastructure restores a soft-deleted infrastructure resource within the retention window
+func (s *InfrastructureService) RestoreInfrastructure(ctx context.Context, orgID, id string) (*models.Infrastructure, error) {
+	if err := s.repoManager.Infrastructure.Restore(ctx, id, orgID, time.Now().Add(-s.deletedRetention)); err != nil {
+		return nil, ErrInfrastructureNotRestorable
+	}
+
+	infra, err := s.repoManager.Infrastructure.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// A restored resource reappears, so integrators are told it was created again
+	s.PublishEvent(ctx, models.WebhookEventInfrastructureCreated, infra)
+
+	return infra, nil
+}
+
+// StartDeletedInfrastructurePurge permanently removes soft-deleted infrastructure past the retention
+// window every interval until the context is cancelled
+func (s *InfrastructureService) StartDeletedInfrastructurePurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.repoManager.Infrastructure.PurgeDeleted(ctx, time.Now().Add(-s.deletedRetention))
+			if err != nil {
+				log.Printf("Deleted infrastructure purge failed: %v", err)
+			} else if purged > 0 {
+				log.Printf("Purged %d deleted infrastructure resources", purged)
+			}
+		}
+	}
 }
 
 // CloudProvider interface for cloud provider abstraction
 type CloudProvider interface {
 	CreateResource(ctx context.Context, infra *models.Infrastructure) (string, error)
+	ValidateCreate(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error)
 	GetResourceStatus(ctx context.Context, externalID string) (string, error)
 	GetResourceMetrics(ctx context.Context, externalID string) (map[string]interface{}, error)
 	GetResourceDetails(ctx context.Context, externalID string) (map[string]interface{}, error)
 	DeleteResource(ctx context.Context, externalID string) error
+	ListOrphanedResources(ctx context.Context) ([]OrphanedResource, error)
 }
 
 // MetricsCollector handles real-time metrics collection
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"cloudweave/internal/models"
+)
+
+// Limits of container specifications, the narrowest that ECS Fargate, Azure Container Instances
+// and Cloud Run all accept
+const (
+	minContainerCPU      = 0.25
+	maxContainerCPU      = 4
+	minContainerMemoryMB = 128
+	maxContainerMemoryMB = 16384
+	maxContainerEnvVars  = 100
+)
+
+// Defaults of container specifications that are not set
+const (
+	defaultContainerCPU      = 0.25
+	defaultContainerMemoryMB = 512
+)
+
+var (
+	// containerImagePattern matches container image references, e.g. nginx:1.27,
+	// ghcr.io/org/app:v2 or registry:5000/app@sha256:<digest>
+	containerImagePattern = regexp.MustCompile(`^(?:[a-zA-Z0-9.-]+(?::[0-9]+)?/)?[a-z0-9]+(?:[._-][a-z0-9]+)*(?:/[a-z0-9]+(?:[._-][a-z0-9]+)*)*(?::[A-Za-z0-9_][A-Za-z0-9_.-]{0,127})?(?:@sha256:[a-f0-9]{64})?$`)
+
+	// containerEnvNamePattern matches environment variable names
+	containerEnvNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	// containerNamePattern matches names every provider accepts for a container service
+	containerNamePattern = regexp.MustCompile(`^[a-z]([a-z0-9-]{0,47}[a-z0-9])?$`)
+
+	// containerNameReplacer maps characters container service names cannot have to dashes
+	containerNameReplacer = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// containerSpecSchema is the specification schema of container resources, the same for every
+// provider: an image, the vCPUs and memory in MB it runs with, the port it listens on and its
+// environment variables
+var containerSpecSchema = SpecSchema{
+	"image":  {Type: SpecString, Required: true, Check: checkContainerImage},
+	"cpu":    {Type: SpecNumber, Check: checkContainerCPU},
+	"memory": {Type: SpecInteger, Check: checkContainerMemory},
+	"port":   {Type: SpecInteger, Check: checkContainerPort},
+	"env":    {Type: SpecStringMap, Check: checkContainerEnv},
+}
+
+// containerSpec is a container resource's specifications with defaults applied
+type containerSpec struct {
+	Image    string
+	CPU      float64
+	MemoryMB int
+	Port     int
+	Env      map[string]string
+}
+
+// parseContainerSpec reads a container resource's specifications, which ValidateSpecifications has
+// already checked. Port is zero when the container does not listen on one.
+func parseContainerSpec(specs map[string]interface{}) containerSpec {
+	spec := containerSpec{
+		CPU:      defaultContainerCPU,
+		MemoryMB: defaultContainerMemoryMB,
+		Env:      make(map[string]string),
+	}
+
+	spec.Image, _ = specs["image"].(string)
+	if cpu, ok := specs["cpu"].(float64); ok {
+		spec.CPU = cpu
+	}
+	if memory, ok := specs["memory"].(float64); ok {
+		spec.MemoryMB = int(memory)
+	}
+	if port, ok := specs["port"].(float64); ok {
+		spec.Port = int(port)
+	}
+	if env, ok := specs["env"].(map[string]interface{}); ok {
+		for name, value := range env {
+			spec.Env[name], _ = value.(string)
+		}
+	}
+
+	return spec
+}
+
+// containerServiceName derives the name of a container resource's service from its name. Names
+// that cannot be made valid are rejected by the providers' ValidateCreate.
+func containerServiceName(infra *models.Infrastructure) string {
+	name := containerNameReplacer.ReplaceAllString(strings.ToLower(infra.Name), "-")
+	name = strings.Trim(name, "-")
+	if len(name) > 49 {
+		name = strings.TrimRight(name[:49], "-")
+	}
+	return name
+}
+
+// validateContainerName rejects container service names a provider would not accept
+func validateContainerName(name string) error {
+	if !containerNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q is not a valid container service name", ErrCreateRejected, name)
+	}
+	return nil
+}
+
+// checkContainerImage rejects images that are not valid image references
+func checkContainerImage(value interface{}) error {
+	image := value.(string)
+	if !containerImagePattern.MatchString(image) {
+		return errors.New("must be a container image reference, e.g. nginx:1.27")
+	}
+	return nil
+}
+
+// checkContainerCPU rejects vCPU counts outside the container limits
+func checkContainerCPU(value interface{}) error {
+	cpu := value.(float64)
+	if cpu < minContainerCPU || cpu > maxContainerCPU {
+		return fmt.Errorf("must be between %g and %g vCPUs", float64(minContainerCPU), float64(maxContainerCPU))
+	}
+	return nil
+}
+
+// checkContainerMemory rejects memory sizes outside the container limits
+func checkContainerMemory(value interface{}) error {
+	memory := value.(float64)
+	if memory < minContainerMemoryMB || memory > maxContainerMemoryMB {
+		return fmt.Errorf("must be between %d and %d MB", minContainerMemoryMB, maxContainerMemoryMB)
+	}
+	return nil
+}
+
+// checkContainerPort rejects ports that are not valid TCP ports
+func checkContainerPort(value interface{}) error {
+	port := value.(float64)
+	if port < 1 || port > 65535 {
+		return errors.New("must be between 1 and 65535")
+	}
+	return nil
+}
+
+// checkContainerEnv rejects too many environment variables and invalid variable names
+func checkContainerEnv(value interface{}) error {
+	env := value.(map[string]interface{})
+	if len(env) > maxContainerEnvVars {
+		return fmt.Errorf("must have at most %d variables", maxContainerEnvVars)
+	}
+	for _, name := range sortedKeys(env) {
+		if !containerEnvNamePattern.MatchString(name) {
+			return fmt.Errorf("has an invalid variable name %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// ErrInfrastructureNotManaged is returned when a resource has no provider resource to compare with
+var ErrInfrastructureNotManaged = errors.New("infrastructure resource is not provisioned with a provider")
+
+// criticalDriftFields are the fields whose drift raises an alert during scheduled detection
+var criticalDriftFields = map[string]bool{
+	"status":                        true,
+	"specifications.instance_type":  true,
+	"specifications.instance_class": true,
+	"specifications.machine_type":   true,
+	"specifications.vm_size":        true,
+}
+
+// SetAlertService sets the alert service used to raise alerts for critical drift
+func (s *InfrastructureService) SetAlertService(alertService *AlertService) {
+	s.alertService = alertService
+}
+
+// DetectDrift compares a resource's recorded status and specifications with its current state at the
+// provider. Only specifications both recorded and reported by the provider are compared, since each
+// side holds fields the other does not track.
+func (s *InfrastructureService) DetectDrift(ctx context.Context, infra *models.Infrastructure) (*models.DriftReport, error) {
+	if infra.ExternalID == nil || *infra.ExternalID == "" {
+		return nil, ErrInfrastructureNotManaged
+	}
+
+	provider, err := s.providerFor(ctx, infra.OrganizationID, infra.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := providerValue(withResourceRegion(ctx, infra.Region), func(ctx context.Context) (map[string]interface{}, error) {
+		return provider.GetResourceDetails(ctx, *infra.ExternalID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get resource details from provider: %w", err)
+	}
+
+	report := &models.DriftReport{
+		InfrastructureID: infra.ID,
+		Name:             infra.Name,
+		Provider:         infra.Provider,
+		ExternalID:       *infra.ExternalID,
+		Items:            []models.DriftItem{},
+		CheckedAt:        time.Now(),
+	}
+
+	if status, ok := details["status"].(string); ok && status != infra.Status {
+		report.Items = append(report.Items, newDriftItem("status", infra.Status, status))
+	}
+
+	if actualSpecs, ok := details["specifications"].(map[string]interface{}); ok {
+		keys := make([]string, 0, len(actualSpecs))
+		for key := range actualSpecs {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			expected, recorded := infra.Specifications[key]
+			if !recorded || driftValuesEqual(expected, actualSpecs[key]) {
+				continue
+			}
+			report.Items = append(report.Items, newDriftItem("specifications."+key, expected, actualSpecs[key]))
+		}
+	}
+
+	report.Drifted = len(report.Items) > 0
+	return report, nil
+}
+
+// DetectOrganizationDrift checks every provisioned resource in every organization and raises an
+// alert for each resource with critical drift
+func (s *InfrastructureService) DetectOrganizationDrift(ctx context.Context) error {
+	orgParams := repositories.DefaultListParams()
+	orgParams.Limit = 100
+
+	for {
+		organizations, err := s.repoManager.Organization.List(ctx, orgParams)
+		if err != nil {
+			return fmt.Errorf("failed to list organizations: %w", err)
+		}
+
+		for _, organization := range organizations {
+			if err := s.detectDriftForOrganization(ctx, organization.ID); err != nil {
+				log.Printf("Drift detection failed for organization %s: %v", organization.ID, err)
+			}
+		}
+
+		if len(organizations) < orgParams.Limit {
+			return nil
+		}
+		orgParams.Offset += orgParams.Limit
+	}
+}
+
+// detectDriftForOrganization checks one organization's provisioned resources
+func (s *InfrastructureService) detectDriftForOrganization(ctx context.Context, orgID string) error {
+	params := repositories.DefaultListParams()
+
+	for {
+		resources, err := s.repoManager.Infrastructure.List(ctx, orgID, params)
+		if err != nil {
+			return fmt.Errorf("failed to list infrastructure: %w", err)
+		}
+
+		for _, infra := range resources {
+			if infra.ExternalID == nil {
+				continue
+			}
+
+			report, err := s.DetectDrift(ctx, infra)
+			if err != nil {
+				log.Printf("Drift detection failed for infrastructure %s: %v", infra.ID, err)
+				continue
+			}
+			if err := s.raiseDriftAlert(ctx, infra, report); err != nil {
+				log.Printf("Failed to raise drift alert for infrastructure %s: %v", infra.ID, err)
+			}
+		}
+
+		if len(resources) < params.Limit {
+			return nil
+		}
+		params.Offset += params.Limit
+	}
+}
+
+// raiseDriftAlert creates an alert for a report's critical drift, unless the resource already has an
+// unacknowledged drift alert
+func (s *InfrastructureService) raiseDriftAlert(ctx context.Context, infra *models.Infrastructure, report *models.DriftReport) error {
+	if s.alertService == nil {
+		return nil
+	}
+
+	var critical []string
+	for _, item := range report.Items {
+		if item.Critical {
+			critical = append(critical, fmt.Sprintf("%s is %v, expected %v", item.Field, item.Actual, item.Expected))
+		}
+	}
+	if len(critical) == 0 {
+		return nil
+	}
+
+	alertType := models.AlertTypeDrift
+	resourceType := "infrastructure"
+	acknowledged := false
+	existing, err := s.repoManager.Alert.Query(ctx, infra.OrganizationID, models.AlertQuery{
+		Type:         &alertType,
+		ResourceID:   &infra.ID,
+		ResourceType: &resourceType,
+		Acknowledged: &acknowledged,
+		Limit:        1,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to query drift alerts: %w", err)
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
+	return s.alertService.CreateAlert(ctx, &models.Alert{
+		OrganizationID: infra.OrganizationID,
+		Type:           models.AlertTypeDrift,
+		Severity:       models.AlertSeverityWarning,
+		Title:          fmt.Sprintf("Configuration drift on %s", infra.Name),
+		Message:        strings.Join(critical, "; "),
+		ResourceID:     &infra.ID,
+		ResourceType:   &resourceType,
+	})
+}
+
+// StartDriftDetection runs organization-wide drift detection every interval until the context is cancelled
+func (s *InfrastructureService) StartDriftDetection(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.DetectOrganizationDrift(ctx); err != nil {
+				log.Printf("Drift detection failed: %v", err)
+			}
+		}
+	}
+}
+
+// newDriftItem builds a drift item, marking fields that raise alerts as critical
+func newDriftItem(field string, expected, actual interface{}) models.DriftItem {
+	return models.DriftItem{
+		Field:    field,
+		Expected: expected,
+		Actual:   actual,
+		Critical: criticalDriftFields[field],
+	}
+}
+
+// driftValuesEqual compares recorded and reported values. Recorded values come back from JSON, so
+// numbers are compared by value rather than by type.
+func driftValuesEqual(expected, actual interface{}) bool {
+	if expectedNumber, ok := checkNumber(expected); ok {
+		if actualNumber, ok := checkNumber(actual); ok {
+			return expectedNumber == actualNumber
+		}
+	}
+	return fmt.Sprintf("%v", expected) == fmt.Sprintf("%v", actual)
+}
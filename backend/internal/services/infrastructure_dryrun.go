@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"cloudweave/internal/models"
+)
+
+// ErrCreateRejected is returned when a cloud provider reports that it would not create a resource
+// as requested
+var ErrCreateRejected = errors.New("cloud provider would reject the resource")
+
+// ErrPricingUnavailable is returned when a cloud provider has no price for a planned resource
+var ErrPricingUnavailable = errors.New("pricing unavailable")
+
+// usageBilledWarning notes that a resource's cost depends on how it is used
+const usageBilledWarning = "Billed by usage; the estimate excludes storage and request charges"
+
+// Cost categories of a plan's cost breakdown
+const (
+	CostCategoryCompute = "compute"
+	CostCategoryStorage = "storage"
+)
+
+// Sources of a plan's cost estimate
+const (
+	// CostSourceListPrice estimates are from the list prices CloudWeave ships with
+	CostSourceListPrice = "list_price"
+
+	// CostSourcePricingAPI estimates are from the cloud provider's current on-demand prices
+	CostSourcePricingAPI = "pricing_api"
+)
+
+// CostComponent is the estimated cost of one part of a planned resource, e.g. its instance or disk
+type CostComponent struct {
+	Category    string  `json:"category"`
+	Description string  `json:"description"`
+	HourlyCost  float64 `json:"hourlyCost"`
+	MonthlyCost float64 `json:"monthlyCost"`
+}
+
+// CostEstimator is implemented by cloud providers that can price a planned resource with their
+// current prices, rather than the list prices its plan is estimated with
+type CostEstimator interface {
+	EstimateCreateCost(ctx context.Context, plan *CreatePlan) ([]CostComponent, error)
+}
+
+// CreatePlan describes the resource a cloud provider would create for an infrastructure request
+type CreatePlan struct {
+	Provider             string                 `json:"provider"`
+	Type                 string                 `json:"type"`
+	Name                 string                 `json:"name"`
+	Region               string                 `json:"region"`
+	ResourceName         string                 `json:"resourceName"`
+	Specifications       map[string]interface{} `json:"specifications"`
+	EstimatedHourlyCost  float64                `json:"estimatedHourlyCost"`
+	EstimatedMonthlyCost float64                `json:"estimatedMonthlyCost"`
+	CostBreakdown        []CostComponent        `json:"costBreakdown"`
+	CostSource           string                 `json:"costSource"`
+	Currency             string                 `json:"currency"`
+	Warnings             []string               `json:"warnings,omitempty"`
+}
+
+// newCreatePlan describes a resource with the provider-side name and specifications it would be
+// created with. A non-zero hourly cost is estimated as the resource's compute cost.
+func newCreatePlan(infra *models.Infrastructure, resourceName string, specs map[string]interface{}, hourlyCost float64) *CreatePlan {
+	plan := &CreatePlan{
+		Provider:       infra.Provider,
+		Type:           infra.Type,
+		Name:           infra.Name,
+		Region:         infra.Region,
+		ResourceName:   resourceName,
+		Specifications: specs,
+		CostBreakdown:  []CostComponent{},
+		CostSource:     CostSourceListPrice,
+		Currency:       "USD",
+	}
+	if hourlyCost > 0 {
+		plan.setCostBreakdown([]CostComponent{
+			hourlyCostComponent(CostCategoryCompute, fmt.Sprintf("%s %s", infra.Provider, infra.Type), hourlyCost),
+		})
+	}
+	return plan
+}
+
+// setCostBreakdown replaces the plan's cost estimate with the total of its components
+func (p *CreatePlan) setCostBreakdown(components []CostComponent) {
+	p.CostBreakdown = components
+	p.EstimatedHourlyCost = 0
+	p.EstimatedMonthlyCost = 0
+	for _, component := range components {
+		p.EstimatedHourlyCost += component.HourlyCost
+		p.EstimatedMonthlyCost += component.MonthlyCost
+	}
+}
+
+// hourlyCostComponent is a cost component billed by the hour, e.g. a running instance
+func hourlyCostComponent(category, description string, hourlyCost float64) CostComponent {
+	return CostComponent{
+		Category:    category,
+		Description: description,
+		HourlyCost:  hourlyCost,
+		MonthlyCost: hourlyCost * hoursPerMonth,
+	}
+}
+
+// monthlyCostComponent is a cost component billed by the month, e.g. provisioned storage
+func monthlyCostComponent(category, description string, monthlyCost float64) CostComponent {
+	return CostComponent{
+		Category:    category,
+		Description: description,
+		HourlyCost:  monthlyCost / hoursPerMonth,
+		MonthlyCost: monthlyCost,
+	}
+}
+
+// PlanInfrastructure runs the checks CreateInfrastructure would and asks the cloud provider to
+// validate the resource without creating it. The plan's cost is estimated with the provider's
+// current prices when it can price the resource, and with list prices otherwise. Nothing is
+// stored or provisioned.
+func (s *InfrastructureService) PlanInfrastructure(ctx context.Context, infra *models.Infrastructure) (*CreatePlan, error) {
+	provider, err := s.prepareCreate(ctx, infra)
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := providerValue(ctx, func(ctx context.Context) (*CreatePlan, error) {
+		return provider.ValidateCreate(ctx, infra)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	estimator, ok := provider.(CostEstimator)
+	if !ok {
+		return plan, nil
+	}
+
+	components, err := providerValue(ctx, func(ctx context.Context) ([]CostComponent, error) {
+		return estimator.EstimateCreateCost(ctx, plan)
+	})
+	switch {
+	case err == nil:
+		plan.setCostBreakdown(components)
+		plan.CostSource = CostSourcePricingAPI
+	case errors.Is(err, ErrPricingUnavailable):
+		// Resources the provider cannot price up front keep their list price estimate
+	default:
+		log.Printf("Failed to price %s %s in %s: %v", infra.Provider, infra.Type, infra.Region, err)
+		plan.Warnings = append(plan.Warnings, "Current prices are unavailable; the estimate uses list prices")
+	}
+
+	return plan, nil
+}
+
+// prepareCreate validates a resource about to be created against its specification schema, the
+// organization's quota and its provider's regions, and returns the provider that would create it
+func (s *InfrastructureService) prepareCreate(ctx context.Context, infra *models.Infrastructure) (CloudProvider, error) {
+	if err := ValidateSpecifications(infra.Provider, infra.Type, infra.Specifications); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkQuota(ctx, infra); err != nil {
+		return nil, err
+	}
+
+	provider, err := s.providerFor(ctx, infra.OrganizationID, infra.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	region, err := s.regions.Resolve(ctx, infra.Provider, infra.Region)
+	if err != nil {
+		return nil, err
+	}
+	infra.Region = region
+
+	return provider, nil
+}
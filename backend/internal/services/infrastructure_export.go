@@ -0,0 +1,420 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// ErrUnsupportedExportFormat is returned when an export format is not supported
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+// Infrastructure export formats
+const (
+	ExportFormatTerraform      = "terraform"
+	ExportFormatCloudFormation = "cloudformation"
+)
+
+// InfrastructureExport is a generated infrastructure-as-code file
+type InfrastructureExport struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	// Skipped lists resources with no equivalent in the format
+	Skipped []string
+}
+
+// exportResource is a stored resource prepared for export
+type exportResource struct {
+	infra *models.Infrastructure
+	name  string
+	tags  map[string]string
+}
+
+// ExportInfrastructure generates infrastructure-as-code definitions for an organization's stored
+// resources. It only reads CloudWeave's records and never calls the cloud providers.
+func (s *InfrastructureService) ExportInfrastructure(ctx context.Context, orgID, format string) (*InfrastructureExport, error) {
+	if format != ExportFormatTerraform && format != ExportFormatCloudFormation {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedExportFormat, format)
+	}
+
+	var resources []*exportResource
+	params := repositories.DefaultListParams()
+	params.SortBy = "name"
+	params.Order = "asc"
+	for {
+		page, err := s.repoManager.Infrastructure.List(ctx, orgID, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list infrastructure: %w", err)
+		}
+		for _, infra := range page {
+			resources = append(resources, newExportResource(infra))
+		}
+
+		if len(page) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+
+	if format == ExportFormatCloudFormation {
+		return exportCloudFormation(resources)
+	}
+	return exportTerraform(resources), nil
+}
+
+// newExportResource derives a resource's export name from its "name" tag, falling back to its Name
+func newExportResource(infra *models.Infrastructure) *exportResource {
+	resource := &exportResource{
+		infra: infra,
+		name:  infra.Name,
+		tags:  map[string]string{"Name": infra.Name},
+	}
+	for _, tag := range infra.Tags {
+		key, value := ParseTag(tag)
+		if key == "name" && value != "" {
+			resource.name = value
+			continue
+		}
+		resource.tags[key] = value
+	}
+	return resource
+}
+
+// specString returns a string specification, or fallback when it is not set
+func (r *exportResource) specString(key, fallback string) string {
+	if value, ok := r.infra.Specifications[key].(string); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// specNumber returns a numeric specification, or fallback when it is not set
+func (r *exportResource) specNumber(key string, fallback float64) float64 {
+	if value, ok := checkNumber(r.infra.Specifications[key]); ok {
+		return value
+	}
+	return fallback
+}
+
+// Terraform
+
+// exportTerraform renders resources as Terraform configuration. Resources CloudWeave provisioned get
+// an import block so Terraform adopts them instead of creating duplicates.
+func exportTerraform(resources []*exportResource) *InfrastructureExport {
+	var body, imports strings.Builder
+	export := &InfrastructureExport{
+		Filename:    "cloudweave-infrastructure.tf",
+		ContentType: "text/plain; charset=utf-8",
+	}
+	usedNames := make(map[string]bool)
+	needsResourceGroup := false
+
+	for _, resource := range resources {
+		resourceType, attributes := terraformResource(resource)
+		if resourceType == "" {
+			export.Skipped = append(export.Skipped, resource.infra.Name)
+			continue
+		}
+		if strings.HasPrefix(resourceType, "azurerm_") {
+			needsResourceGroup = true
+		}
+
+		address := resourceType + "." + uniqueName(terraformIdentifier(resource.name), usedNames, "_")
+		fmt.Fprintf(&body, "\n# %s (%s %s in %s)\n", resource.infra.Name, resource.infra.Provider, resource.infra.Type, resource.infra.Region)
+		fmt.Fprintf(&body, "resource %q %q {\n", resourceType, strings.TrimPrefix(address, resourceType+"."))
+		body.WriteString(attributes)
+		body.WriteString("}\n")
+
+		if resource.infra.ExternalID != nil && *resource.infra.ExternalID != "" {
+			fmt.Fprintf(&imports, "\nimport {\n  to = %s\n  id = %s\n}\n", address, hclString(*resource.infra.ExternalID))
+		}
+	}
+
+	var out strings.Builder
+	out.WriteString("# Generated by CloudWeave from its stored infrastructure records.\n")
+	out.WriteString("# Review the configuration before applying it; attributes CloudWeave does not track use variables.\n")
+	if len(export.Skipped) > 0 {
+		fmt.Fprintf(&out, "# Skipped resources with no Terraform mapping: %s\n", strings.Join(export.Skipped, ", "))
+	}
+	if needsResourceGroup {
+		out.WriteString("\nvariable \"azure_resource_group_name\" {\n  type = string\n}\n")
+		out.WriteString("\nvariable \"azure_sql_server_id\" {\n  type    = string\n  default = null\n}\n")
+		out.WriteString("\nvariable \"azure_network_interface_id\" {\n  type    = string\n  default = null\n}\n")
+		out.WriteString("\nvariable \"azure_admin_ssh_public_key\" {\n  type    = string\n  default = null\n}\n")
+	}
+	out.WriteString(body.String())
+	out.WriteString(imports.String())
+
+	export.Content = []byte(out.String())
+	return export
+}
+
+// terraformResource maps a resource to its Terraform resource type and attribute block, or "" when
+// it has no mapping
+func terraformResource(r *exportResource) (string, string) {
+	var b strings.Builder
+	switch r.infra.Provider + "/" + r.infra.Type {
+	case models.ProviderAWS + "/" + models.InfraTypeServer:
+		fmt.Fprintf(&b, "  ami           = %s\n", hclString(r.specString("ami_id", "ami-0c02fb55956c7d316")))
+		fmt.Fprintf(&b, "  instance_type = %s\n", hclString(r.specString("instance_type", "t3.micro")))
+		if keyName := r.specString("key_name", ""); keyName != "" {
+			fmt.Fprintf(&b, "  key_name      = %s\n", hclString(keyName))
+		}
+		writeHCLTags(&b, "tags", r.tags)
+		return "aws_instance", b.String()
+
+	case models.ProviderAWS + "/" + models.InfraTypeDatabase:
+		fmt.Fprintf(&b, "  identifier          = %s\n", hclString(r.infra.Name))
+		fmt.Fprintf(&b, "  engine              = %s\n", hclString(r.specString("engine", "mysql")))
+		fmt.Fprintf(&b, "  instance_class      = %s\n", hclString(r.specString("db_instance_class", r.specString("instanceType", "db.t3.micro"))))
+		fmt.Fprintf(&b, "  allocated_storage   = %s\n", strconv.FormatFloat(r.specNumber("allocated_storage", r.specNumber("storage", 20)), 'f', -1, 64))
+		writeHCLTags(&b, "tags", r.tags)
+		return "aws_db_instance", b.String()
+
+	case models.ProviderAWS + "/" + models.InfraTypeStorage:
+		bucket := r.infra.Name
+		if r.infra.ExternalID != nil && *r.infra.ExternalID != "" {
+			bucket = *r.infra.ExternalID
+		}
+		fmt.Fprintf(&b, "  bucket = %s\n", hclString(bucket))
+		writeHCLTags(&b, "tags", r.tags)
+		return "aws_s3_bucket", b.String()
+
+	case models.ProviderAzure + "/" + models.InfraTypeServer:
+		fmt.Fprintf(&b, "  name                  = %s\n", hclString(r.infra.Name))
+		b.WriteString("  resource_group_name   = var.azure_resource_group_name\n")
+		fmt.Fprintf(&b, "  location              = %s\n", hclString(r.infra.Region))
+		fmt.Fprintf(&b, "  size                  = %s\n", hclString(r.specString("vm_size", "Standard_B2s")))
+		b.WriteString("  admin_username        = \"azureuser\"\n")
+		b.WriteString("  network_interface_ids = [var.azure_network_interface_id]\n\n")
+		b.WriteString("  admin_ssh_key {\n    username   = \"azureuser\"\n    public_key = var.azure_admin_ssh_public_key\n  }\n\n")
+		b.WriteString("  os_disk {\n    caching              = \"ReadWrite\"\n    storage_account_type = \"Standard_LRS\"\n  }\n\n")
+		b.WriteString("  source_image_reference {\n    publisher = \"Canonical\"\n    offer     = \"UbuntuServer\"\n    sku       = \"18.04-LTS\"\n    version   = \"latest\"\n  }\n")
+		writeHCLTags(&b, "tags", r.tags)
+		return "azurerm_linux_virtual_machine", b.String()
+
+	case models.ProviderAzure + "/" + models.InfraTypeDatabase:
+		fmt.Fprintf(&b, "  name      = %s\n", hclString(r.infra.Name))
+		b.WriteString("  server_id = var.azure_sql_server_id\n")
+		fmt.Fprintf(&b, "  sku_name  = %s\n", hclString(r.specString("sku_name", "S0")))
+		writeHCLTags(&b, "tags", r.tags)
+		return "azurerm_mssql_database", b.String()
+
+	case models.ProviderAzure + "/" + models.InfraTypeStorage:
+		fmt.Fprintf(&b, "  name                     = %s\n", hclString(azureStorageAccountName(r.infra.Name)))
+		b.WriteString("  resource_group_name      = var.azure_resource_group_name\n")
+		fmt.Fprintf(&b, "  location                 = %s\n", hclString(r.infra.Region))
+		fmt.Fprintf(&b, "  account_tier             = %s\n", hclString(r.specString("account_tier", "Standard")))
+		fmt.Fprintf(&b, "  account_replication_type = %s\n", hclString(r.specString("replication_type", "LRS")))
+		writeHCLTags(&b, "tags", r.tags)
+		return "azurerm_storage_account", b.String()
+	}
+
+	return "", ""
+}
+
+// writeHCLTags writes a map attribute with keys in sorted order
+func writeHCLTags(b *strings.Builder, attribute string, tags map[string]string) {
+	keys := sortedKeys(tags)
+	fmt.Fprintf(b, "\n  %s = {\n", attribute)
+	for _, key := range keys {
+		fmt.Fprintf(b, "    %s = %s\n", hclString(key), hclString(tags[key]))
+	}
+	b.WriteString("  }\n")
+}
+
+// hclString quotes a string for HCL, escaping template sequences
+func hclString(value string) string {
+	quoted := strconv.Quote(value)
+	quoted = strings.ReplaceAll(quoted, "${", "$${")
+	return strings.ReplaceAll(quoted, "%{", "%%{")
+}
+
+// terraformIdentifier converts a name to a Terraform identifier: lowercase letters, digits and underscores
+func terraformIdentifier(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else if b.Len() > 0 && !strings.HasSuffix(b.String(), "_") {
+			b.WriteRune('_')
+		}
+	}
+
+	identifier := strings.TrimSuffix(b.String(), "_")
+	if identifier == "" || unicode.IsDigit(rune(identifier[0])) {
+		identifier = "resource_" + identifier
+	}
+	return strings.TrimSuffix(identifier, "_")
+}
+
+// azureStorageAccountName converts a name to a valid storage account name: 3-24 lowercase letters and digits
+func azureStorageAccountName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+
+	account := b.String()
+	for len(account) < 3 {
+		account += "0"
+	}
+	if len(account) > 24 {
+		account = account[:24]
+	}
+	return account
+}
+
+// CloudFormation
+
+// exportCloudFormation renders AWS resources as a CloudFormation template. Resources on other
+// providers cannot be expressed in CloudFormation and are skipped.
+func exportCloudFormation(resources []*exportResource) (*InfrastructureExport, error) {
+	export := &InfrastructureExport{
+		Filename:    "cloudweave-infrastructure.template.json",
+		ContentType: "application/json",
+	}
+	usedNames := make(map[string]bool)
+	templateResources := make(map[string]interface{})
+
+	for _, resource := range resources {
+		resourceType, properties := cloudFormationResource(resource)
+		if resourceType == "" {
+			export.Skipped = append(export.Skipped, resource.infra.Name)
+			continue
+		}
+
+		logicalID := uniqueName(cloudFormationLogicalID(resource.name), usedNames, "")
+		templateResources[logicalID] = map[string]interface{}{
+			"Type":           resourceType,
+			"DeletionPolicy": "Retain",
+			"Properties":     properties,
+			"Metadata": map[string]interface{}{
+				"CloudWeave": map[string]interface{}{
+					"id":         resource.infra.ID,
+					"externalId": resource.infra.ExternalID,
+				},
+			},
+		}
+	}
+
+	description := "Generated by CloudWeave from its stored infrastructure records"
+	if len(export.Skipped) > 0 {
+		description += ". Skipped resources with no CloudFormation mapping: " + strings.Join(export.Skipped, ", ")
+	}
+
+	content, err := json.MarshalIndent(map[string]interface{}{
+		"AWSTemplateFormatVersion": "2010-09-09",
+		"Description":              description,
+		"Resources":                templateResources,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CloudFormation template: %w", err)
+	}
+
+	export.Content = content
+	return export, nil
+}
+
+// cloudFormationResource maps an AWS resource to its CloudFormation type and properties, or "" when
+// it has no mapping
+func cloudFormationResource(r *exportResource) (string, map[string]interface{}) {
+	if r.infra.Provider != models.ProviderAWS {
+		return "", nil
+	}
+
+	tags := make([]map[string]string, 0, len(r.tags))
+	for _, key := range sortedKeys(r.tags) {
+		tags = append(tags, map[string]string{"Key": key, "Value": r.tags[key]})
+	}
+
+	switch r.infra.Type {
+	case models.InfraTypeServer:
+		properties := map[string]interface{}{
+			"ImageId":      r.specString("ami_id", "ami-0c02fb55956c7d316"),
+			"InstanceType": r.specString("instance_type", "t3.micro"),
+			"Tags":         tags,
+		}
+		if keyName := r.specString("key_name", ""); keyName != "" {
+			properties["KeyName"] = keyName
+		}
+		return "AWS::EC2::Instance", properties
+
+	case models.InfraTypeDatabase:
+		return "AWS::RDS::DBInstance", map[string]interface{}{
+			"DBInstanceIdentifier": r.infra.Name,
+			"Engine":               r.specString("engine", "mysql"),
+			"DBInstanceClass":      r.specString("db_instance_class", r.specString("instanceType", "db.t3.micro")),
+			"AllocatedStorage":     strconv.FormatFloat(r.specNumber("allocated_storage", r.specNumber("storage", 20)), 'f', -1, 64),
+			"Tags":                 tags,
+		}
+
+	case models.InfraTypeStorage:
+		bucket := r.infra.Name
+		if r.infra.ExternalID != nil && *r.infra.ExternalID != "" {
+			bucket = *r.infra.ExternalID
+		}
+		return "AWS::S3::Bucket", map[string]interface{}{
+			"BucketName": bucket,
+			"Tags":       tags,
+		}
+	}
+
+	return "", nil
+}
+
+// cloudFormationLogicalID converts a name to an alphanumeric CamelCase logical ID
+func cloudFormationLogicalID(name string) string {
+	var b strings.Builder
+	upperNext := true
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z') && !(r >= 'A' && r <= 'Z') && !(r >= '0' && r <= '9') {
+			upperNext = true
+			continue
+		}
+		if upperNext {
+			r = unicode.ToUpper(r)
+			upperNext = false
+		}
+		b.WriteRune(r)
+	}
+
+	logicalID := b.String()
+	if logicalID == "" || unicode.IsDigit(rune(logicalID[0])) {
+		logicalID = "Resource" + logicalID
+	}
+	return logicalID
+}
+
+// uniqueName appends a counter to names already used
+func uniqueName(name string, used map[string]bool, separator string) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s%s%d", name, separator, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// sortedKeys returns a map's keys in sorted order
+func sortedKeys[K ~string, V any](values map[K]V) []K {
+	keys := make([]K, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+	return keys
+}
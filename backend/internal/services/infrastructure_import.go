@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"cloudweave/internal/models"
+
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/aws/smithy-go"
+	"github.com/google/uuid"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrCloudResourceNotFound is returned when a resource does not exist at the cloud provider
+var ErrCloudResourceNotFound = errors.New("cloud resource not found")
+
+// ErrInfrastructureAlreadyImported is returned when a cloud resource is already recorded in CloudWeave
+var ErrInfrastructureAlreadyImported = errors.New("cloud resource is already managed by CloudWeave")
+
+// ErrUnsupportedProvider is returned when a cloud provider is not configured
+var ErrUnsupportedProvider = errors.New("unsupported cloud provider")
+
+// Tags applied at the provider to resources CloudWeave manages
+const (
+	ManagedByTagKey        = "managed-by"
+	ManagedByTagValue      = "cloudweave"
+	InfrastructureIDTagKey = "cloudweave-id"
+)
+
+// notFoundErrorCodes are the AWS API error codes for resources that do not exist
+var notFoundErrorCodes = map[string]bool{
+	"InvalidInstanceID.NotFound":  true,
+	"InvalidInstanceID.Malformed": true,
+	"DBInstanceNotFound":          true,
+	"DBInstanceNotFoundFault":     true,
+	"NoSuchBucket":                true,
+	"NotFound":                    true,
+	"ClusterNotFoundException":    true,
+	"ServiceNotFoundException":    true,
+}
+
+// ResourceTagger is implemented by cloud providers that can tag their resources
+type ResourceTagger interface {
+	TagResource(ctx context.Context, externalID string, tags map[string]string) error
+}
+
+// ImportInfrastructure records an existing cloud resource in CloudWeave from its details at the
+// provider, then tags it at the provider as CloudWeave-managed
+func (s *InfrastructureService) ImportInfrastructure(ctx context.Context, orgID, providerName string, req *models.ImportInfrastructureRequest) (*models.Infrastructure, error) {
+	provider, err := s.providerFor(ctx, orgID, providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := NormalizeTags(append(req.Tags, ManagedByTagKey+"="+ManagedByTagValue))
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := s.repoManager.Infrastructure.ExistsByExternalID(ctx, orgID, providerName, req.ExternalID)
+	if err != nil {
+		return nil, err
+	}
+	if imported {
+		return nil, ErrInfrastructureAlreadyImported
+	}
+
+	details, err := providerValue(withResourceRegion(ctx, req.Region), func(ctx context.Context) (map[string]interface{}, error) {
+		return provider.GetResourceDetails(ctx, req.ExternalID)
+	})
+	if err != nil {
+		if isCloudResourceNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrCloudResourceNotFound, req.ExternalID)
+		}
+		return nil, fmt.Errorf("failed to get resource details from provider: %w", err)
+	}
+
+	specifications, _ := details["specifications"].(map[string]interface{})
+	if specifications == nil {
+		specifications = map[string]interface{}{}
+	}
+	costInfo, _ := details["costInfo"].(map[string]interface{})
+	if costInfo == nil {
+		costInfo = map[string]interface{}{}
+	}
+	status, _ := details["status"].(string)
+	if status == "" {
+		status = models.InfraStatusRunning
+	}
+
+	externalID := req.ExternalID
+	infra := &models.Infrastructure{
+		ID:             uuid.New().String(),
+		OrganizationID: orgID,
+		Name:           req.Name,
+		Type:           req.Type,
+		Provider:       providerName,
+		Region:         req.Region,
+		Status:         status,
+		Specifications: specifications,
+		CostInfo:       costInfo,
+		Tags:           tags,
+		ExternalID:     &externalID,
+	}
+	if infra.Name == "" {
+		infra.Name = externalID[strings.LastIndex(externalID, "/")+1:]
+	}
+	if infra.Type == "" {
+		infra.Type = importedResourceType(providerName, externalID)
+	}
+	if infra.Region == "" {
+		infra.Region = importedResourceRegion(specifications)
+	}
+
+	if err := s.repoManager.Infrastructure.Create(ctx, infra); err != nil {
+		return nil, fmt.Errorf("failed to create infrastructure in database: %w", err)
+	}
+
+	// The record is kept even if tagging fails; the tag only marks the resource for people browsing the provider
+	if tagger, ok := provider.(ResourceTagger); ok {
+		err := callProvider(withResourceRegion(ctx, infra.Region), ProviderCallTimeout(), func(ctx context.Context) error {
+			return tagger.TagResource(ctx, externalID, map[string]string{
+				ManagedByTagKey:        ManagedByTagValue,
+				InfrastructureIDTagKey: infra.ID,
+			})
+		})
+		if err != nil {
+			log.Printf("Failed to tag imported infrastructure %s at %s: %v", infra.ID, providerName, err)
+		}
+	}
+
+	go s.metricsCollector.StartCollection(context.Background(), infra)
+
+	s.PublishEvent(ctx, models.WebhookEventInfrastructureCreated, infra)
+
+	return infra, nil
+}
+
+// importedResourceType infers a resource's type from its external ID, following the same rules the
+// providers use to route requests
+func importedResourceType(provider, externalID string) string {
+	switch provider {
+	case models.ProviderAWS:
+		if strings.HasPrefix(externalID, "i-") {
+			return models.InfraTypeServer
+		} else if isECSServiceARN(externalID) {
+			return models.InfraTypeContainer
+		} else if strings.Contains(externalID, "cloudweave-") {
+			return models.InfraTypeStorage
+		}
+		return models.InfraTypeDatabase
+	case models.ProviderAzure:
+		if isContainerGroupID(externalID) {
+			return models.InfraTypeContainer
+		} else if strings.Contains(externalID, "/virtualMachines/") {
+			return models.InfraTypeServer
+		} else if strings.Contains(externalID, "/servers/") {
+			return models.InfraTypeDatabase
+		}
+		return models.InfraTypeStorage
+	case models.ProviderGCP:
+		if isCloudRunServiceID(externalID) {
+			return models.InfraTypeContainer
+		} else if strings.Contains(externalID, "/instances/") && strings.Contains(externalID, "/zones/") {
+			return models.InfraTypeServer
+		} else if strings.Contains(externalID, "/instances/") {
+			return models.InfraTypeDatabase
+		}
+		return models.InfraTypeStorage
+	}
+	return models.InfraTypeServer
+}
+
+// importedResourceRegion reads a resource's region from its provider specifications
+func importedResourceRegion(specifications map[string]interface{}) string {
+	for _, key := range []string{"region", "location"} {
+		if region, ok := specifications[key].(string); ok && region != "" {
+			return region
+		}
+	}
+	// Availability zones are the region plus a zone letter, e.g. us-east-1a
+	if zone, ok := specifications["availability_zone"].(string); ok && len(zone) > 1 {
+		return zone[:len(zone)-1]
+	}
+	return "unknown"
+}
+
+// isCloudResourceNotFound reports whether a provider error means the resource does not exist
+func isCloudResourceNotFound(err error) bool {
+	if errors.Is(err, ErrCloudResourceNotFound) || errors.Is(err, storage.ErrBucketNotExist) {
+		return true
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && notFoundErrorCodes[apiErr.ErrorCode()] {
+		return true
+	}
+
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) && googleErr.Code == http.StatusNotFound {
+		return true
+	}
+
+	var responseErr *azcore.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound
+}
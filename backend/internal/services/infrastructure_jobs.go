@@ -0,0 +1,201 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// ErrJobsUnavailable is returned when a background job is started before the job service is set
+var ErrJobsUnavailable = errors.New("background jobs are not available")
+
+// Infrastructure job types
+const (
+	JobTypeInfrastructureSync   = "infrastructure_sync"
+	JobTypeInfrastructureExport = "infrastructure_export"
+)
+
+// syncAllWorkers is how many resources a sync job syncs with their providers at once
+const syncAllWorkers = 8
+
+// SyncJobError is a resource a sync job could not sync
+type SyncJobError struct {
+	ResourceID   string `json:"resourceId"`
+	ResourceName string `json:"resourceName"`
+	Error        string `json:"error"`
+}
+
+// syncSummary counts the outcomes of a sync job as it runs
+type syncSummary struct {
+	mu        sync.Mutex
+	total     int
+	updated   int
+	unchanged int
+	errors    []SyncJobError
+}
+
+// SetJobService registers the infrastructure job types with the job service that runs them
+func (s *InfrastructureService) SetJobService(jobs *JobService) {
+	s.jobs = jobs
+	jobs.Register(JobTypeInfrastructureSync, JobType{Run: s.runSyncAllJob, Exclusive: true})
+	jobs.Register(JobTypeInfrastructureExport, JobType{Run: s.runExportJob})
+}
+
+// StartSyncAll queues a job that syncs every managed resource of the organization with its
+// provider, updating their statuses, specifications and costs. An organization has at most one
+// sync job queued or running.
+func (s *InfrastructureService) StartSyncAll(ctx context.Context, orgID, userID string) (*models.Job, error) {
+	if s.jobs == nil {
+		return nil, ErrJobsUnavailable
+	}
+	return s.jobs.Enqueue(ctx, orgID, userID, JobTypeInfrastructureSync, nil)
+}
+
+// StartExport queues a job that generates infrastructure-as-code definitions of the
+// organization's resources, downloadable from the job once it completes
+func (s *InfrastructureService) StartExport(ctx context.Context, orgID, userID, format string) (*models.Job, error) {
+	if format != ExportFormatTerraform && format != ExportFormatCloudFormation {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedExportFormat, format)
+	}
+	if s.jobs == nil {
+		return nil, ErrJobsUnavailable
+	}
+	return s.jobs.Enqueue(ctx, orgID, userID, JobTypeInfrastructureExport, map[string]interface{}{
+		"format": format,
+	})
+}
+
+// runSyncAllJob syncs the job organization's managed resources with a bounded pool of workers,
+// and returns how many were updated, unchanged or errored
+func (s *InfrastructureService) runSyncAllJob(ctx context.Context, job *models.Job, progress func(int)) (map[string]interface{}, error) {
+	resources, err := s.listManagedInfrastructure(ctx, job.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &syncSummary{total: len(resources), errors: []SyncJobError{}}
+	queue := make(chan *models.Infrastructure)
+	var wg sync.WaitGroup
+	for i := 0; i < min(syncAllWorkers, len(resources)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for infra := range queue {
+				changed, err := s.syncResource(ctx, infra)
+				progress(summary.record(infra, changed, err))
+			}
+		}()
+	}
+	for _, infra := range resources {
+		queue <- infra
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	errored := len(summary.errors)
+	log.Printf("Synced infrastructure of organization %s: %d updated, %d unchanged, %d errored",
+		job.OrganizationID, summary.updated, summary.unchanged, errored)
+	if summary.total > 0 && errored == summary.total {
+		return nil, fmt.Errorf("every resource failed to sync; first error: %s", summary.errors[0].Error)
+	}
+
+	return map[string]interface{}{
+		"total":     summary.total,
+		"updated":   summary.updated,
+		"unchanged": summary.unchanged,
+		"errored":   errored,
+		"errors":    summary.errors,
+	}, nil
+}
+
+// record counts the outcome of syncing one of the job's resources and returns the percentage of
+// resources synced so far
+func (s *syncSummary) record(infra *models.Infrastructure, changed bool, err error) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case err != nil:
+		s.errors = append(s.errors, SyncJobError{
+			ResourceID:   infra.ID,
+			ResourceName: infra.Name,
+			Error:        err.Error(),
+		})
+	case changed:
+		s.updated++
+	default:
+		s.unchanged++
+	}
+	return (s.updated + s.unchanged + len(s.errors)) * 100 / s.total
+}
+
+// runExportJob generates the export the job asks for and stores it as the job's file
+func (s *InfrastructureService) runExportJob(ctx context.Context, job *models.Job, progress func(int)) (map[string]interface{}, error) {
+	format, _ := job.Payload["format"].(string)
+	export, err := s.ExportInfrastructure(ctx, job.OrganizationID, format)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"format":  format,
+		"skipped": append([]string{}, export.Skipped...),
+	}
+	setJobFile(result, JobFile{
+		Filename:    export.Filename,
+		ContentType: export.ContentType,
+		Content:     export.Content,
+	})
+	return result, nil
+}
+
+// listManagedInfrastructure lists the organization's resources that are provisioned with a provider
+func (s *InfrastructureService) listManagedInfrastructure(ctx context.Context, orgID string) ([]*models.Infrastructure, error) {
+	var resources []*models.Infrastructure
+	params := repositories.DefaultListParams()
+	for {
+		page, err := s.repoManager.Infrastructure.List(ctx, orgID, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list infrastructure: %w", err)
+		}
+		for _, infra := range page {
+			if infra.ExternalID != nil {
+				resources = append(resources, infra)
+			}
+		}
+
+		if len(page) < params.Limit {
+			return resources, nil
+		}
+		params.Offset += params.Limit
+	}
+}
+
+// syncResource syncs a resource with its provider and reports whether its status, specifications
+// or costs changed
+func (s *InfrastructureService) syncResource(ctx context.Context, infra *models.Infrastructure) (bool, error) {
+	before := syncedFields(infra)
+	updated, err := s.SyncWithProvider(ctx, infra)
+	if err != nil {
+		return false, err
+	}
+	return !bytes.Equal(before, syncedFields(updated)), nil
+}
+
+// syncedFields encodes the fields a sync sets. Recorded values come back from JSON, so they are
+// compared as JSON rather than by type.
+func syncedFields(infra *models.Infrastructure) []byte {
+	encoded, _ := json.Marshal([]interface{}{infra.Status, infra.Specifications, infra.CostInfo})
+	return encoded
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// Quota errors
+var (
+	ErrQuotaExceeded = errors.New("organization quota exceeded")
+	ErrInvalidQuota  = errors.New("invalid quota")
+)
+
+// quotaResourceTypes are the resource types an organization quota can limit
+var quotaResourceTypes = []string{
+	models.InfraTypeServer,
+	models.InfraTypeDatabase,
+	models.InfraTypeStorage,
+	models.InfraTypeNetwork,
+	models.InfraTypeContainer,
+}
+
+// GetQuota retrieves an organization's quota. Organizations without one get an empty quota,
+// which limits nothing.
+func (s *InfrastructureService) GetQuota(ctx context.Context, orgID string) (*models.OrganizationQuota, error) {
+	quota, err := s.repoManager.Quota.Get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if quota == nil {
+		quota = &models.OrganizationQuota{OrganizationID: orgID, MaxResources: map[string]int{}}
+	}
+	return quota, nil
+}
+
+// UpdateQuota replaces an organization's quota. Lowering a limit below current usage is allowed;
+// it only blocks further creation.
+func (s *InfrastructureService) UpdateQuota(ctx context.Context, orgID, userID string, req *models.UpdateQuotaRequest) (*models.OrganizationQuota, error) {
+	for resourceType, limit := range req.MaxResources {
+		if !isQuotaResourceType(resourceType) {
+			return nil, fmt.Errorf("%w: unknown resource type %q", ErrInvalidQuota, resourceType)
+		}
+		if limit < 0 {
+			return nil, fmt.Errorf("%w: the limit for %s resources cannot be negative", ErrInvalidQuota, resourceType)
+		}
+	}
+	if req.MaxMonthlyBudget != nil && *req.MaxMonthlyBudget < 0 {
+		return nil, fmt.Errorf("%w: the monthly budget cannot be negative", ErrInvalidQuota)
+	}
+
+	quota := &models.OrganizationQuota{
+		OrganizationID:   orgID,
+		MaxResources:     req.MaxResources,
+		MaxMonthlyBudget: req.MaxMonthlyBudget,
+	}
+	if quota.MaxResources == nil {
+		quota.MaxResources = map[string]int{}
+	}
+	if userID != "" {
+		quota.UpdatedBy = &userID
+	}
+
+	if err := s.repoManager.Quota.Save(ctx, quota); err != nil {
+		return nil, err
+	}
+	return quota, nil
+}
+
+// DeleteQuota removes an organization's quota, lifting all of its limits
+func (s *InfrastructureService) DeleteQuota(ctx context.Context, orgID string) error {
+	return s.repoManager.Quota.Delete(ctx, orgID)
+}
+
+// GetQuotaUsage reports an organization's resources of each type and estimated monthly cost
+// against its quota
+func (s *InfrastructureService) GetQuotaUsage(ctx context.Context, orgID string) (*models.QuotaUsage, error) {
+	quota, err := s.GetQuota(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	counts, monthlyCost, err := s.quotaUsage(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &models.QuotaUsage{
+		Resources:        make(map[string]models.ResourceQuotaUsage, len(quotaResourceTypes)),
+		MonthlyCost:      monthlyCost,
+		MaxMonthlyBudget: quota.MaxMonthlyBudget,
+	}
+	for _, resourceType := range quotaResourceTypes {
+		resourceUsage := models.ResourceQuotaUsage{Used: counts[resourceType]}
+		if limit, ok := quota.MaxResources[resourceType]; ok {
+			resourceUsage.Limit = &limit
+		}
+		usage.Resources[resourceType] = resourceUsage
+	}
+
+	return usage, nil
+}
+
+// checkQuota returns ErrQuotaExceeded when creating a resource would take the organization past
+// its limit for the resource's type or its monthly budget
+func (s *InfrastructureService) checkQuota(ctx context.Context, infra *models.Infrastructure) error {
+	quota, err := s.repoManager.Quota.Get(ctx, infra.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("failed to get organization quota: %w", err)
+	}
+	if quota == nil {
+		return nil
+	}
+
+	limit, limited := quota.MaxResources[infra.Type]
+	if !limited && quota.MaxMonthlyBudget == nil {
+		return nil
+	}
+
+	// Enforcement counts from the primary so resources created moments ago are not missed
+	counts, monthlyCost, err := s.quotaUsage(repositories.WithPrimary(ctx), infra.OrganizationID)
+	if err != nil {
+		return err
+	}
+
+	if limited && counts[infra.Type]+1 > limit {
+		return fmt.Errorf("%w: the organization is limited to %d %s resources and already has %d",
+			ErrQuotaExceeded, limit, infra.Type, counts[infra.Type])
+	}
+
+	if quota.MaxMonthlyBudget != nil {
+		projected := monthlyCost + recordedHourlyCost(infra)*hoursPerMonth
+		if projected > *quota.MaxMonthlyBudget {
+			return fmt.Errorf("%w: the estimated monthly cost of $%.2f would exceed the organization's budget of $%.2f",
+				ErrQuotaExceeded, projected, *quota.MaxMonthlyBudget)
+		}
+	}
+
+	return nil
+}
+
+// quotaUsage counts an organization's resources by type and totals their estimated monthly cost
+func (s *InfrastructureService) quotaUsage(ctx context.Context, orgID string) (map[string]int, float64, error) {
+	counts := make(map[string]int)
+	var monthlyCost float64
+
+	params := repositories.DefaultListParams()
+	for {
+		resources, err := s.repoManager.Infrastructure.List(ctx, orgID, params)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list infrastructure: %w", err)
+		}
+
+		for _, infra := range resources {
+			counts[infra.Type]++
+			monthlyCost += recordedHourlyCost(infra) * hoursPerMonth
+		}
+
+		if len(resources) < params.Limit {
+			return counts, monthlyCost, nil
+		}
+		params.Offset += params.Limit
+	}
+}
+
+// isQuotaResourceType reports whether a quota can limit a resource type
+func isQuotaResourceType(resourceType string) bool {
+	for _, supported := range quotaResourceTypes {
+		if resourceType == supported {
+			return true
+		}
+	}
+	return false
+}
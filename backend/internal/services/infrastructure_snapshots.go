@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrSnapshotsUnsupported is returned when a resource's type or provider cannot be snapshotted
+	ErrSnapshotsUnsupported = errors.New("resource does not support snapshots")
+
+	// ErrSnapshotNotFound is returned when a snapshot does not exist or was not taken of the resource
+	ErrSnapshotNotFound = errors.New("snapshot not found")
+
+	// ErrSnapshotNotAvailable is returned when restoring a snapshot that is not ready to restore
+	ErrSnapshotNotAvailable = errors.New("snapshot is not available to restore")
+)
+
+// snapshotInvalidChars matches the characters snapshot identifiers cannot contain
+var snapshotInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// ProviderSnapshot is a snapshot as its cloud provider reports it
+type ProviderSnapshot struct {
+	ExternalID string
+	Status     string
+	SizeGB     *float64
+	Metadata   map[string]interface{}
+}
+
+// SnapshotProvider is implemented by cloud providers that can back up their database resources.
+// Only database resources are snapshotted; providers return ErrSnapshotsUnsupported for others.
+type SnapshotProvider interface {
+	// CreateSnapshot starts a snapshot of a resource. It is usually still being created when
+	// this returns.
+	CreateSnapshot(ctx context.Context, infra *models.Infrastructure, name string) (*ProviderSnapshot, error)
+
+	// ListSnapshots lists the snapshots taken of a resource that still exist at the provider
+	ListSnapshots(ctx context.Context, infra *models.Infrastructure) ([]ProviderSnapshot, error)
+
+	// RestoreFromSnapshot creates the target resource from a snapshot of the source resource and
+	// returns the target's external ID
+	RestoreFromSnapshot(ctx context.Context, source, target *models.Infrastructure, snapshotID string) (string, error)
+}
+
+// snapshotProvider returns the provider that snapshots a resource, or ErrSnapshotsUnsupported
+func (s *InfrastructureService) snapshotProvider(ctx context.Context, infra *models.Infrastructure) (SnapshotProvider, error) {
+	if infra.Type != models.InfraTypeDatabase {
+		return nil, fmt.Errorf("%w: %s resources cannot be snapshotted, only databases", ErrSnapshotsUnsupported, infra.Type)
+	}
+
+	provider, err := s.providerFor(ctx, infra.OrganizationID, infra.Provider)
+	if err != nil {
+		return nil, err
+	}
+	snapshots, ok := provider.(SnapshotProvider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s databases cannot be snapshotted", ErrSnapshotsUnsupported, infra.Provider)
+	}
+	return snapshots, nil
+}
+
+// getOrgInfrastructure retrieves a resource of an organization, or ErrInfrastructureNotFound
+func (s *InfrastructureService) getOrgInfrastructure(ctx context.Context, orgID, id string) (*models.Infrastructure, error) {
+	infra, err := s.repoManager.Infrastructure.GetByID(ctx, id)
+	if err != nil || infra.OrganizationID != orgID || infra.DeletedAt != nil {
+		return nil, ErrInfrastructureNotFound
+	}
+	return infra, nil
+}
+
+// CreateSnapshot takes a snapshot of a database resource at its provider and records it
+func (s *InfrastructureService) CreateSnapshot(ctx context.Context, orgID, id, userID string, req *models.CreateSnapshotRequest) (*models.InfrastructureSnapshot, error) {
+	infra, err := s.getOrgInfrastructure(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := s.snapshotProvider(ctx, infra)
+	if err != nil {
+		return nil, err
+	}
+	if infra.ExternalID == nil {
+		return nil, ErrInfrastructureNotManaged
+	}
+
+	name := req.Name
+	if name == "" {
+		name = fmt.Sprintf("%s-%s", infra.Name, time.Now().UTC().Format("20060102-150405"))
+	}
+
+	taken, err := providerValue(withResourceRegion(ctx, infra.Region), func(ctx context.Context) (*ProviderSnapshot, error) {
+		return provider.CreateSnapshot(ctx, infra, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &models.InfrastructureSnapshot{
+		ID:               uuid.New().String(),
+		OrganizationID:   orgID,
+		InfrastructureID: &infra.ID,
+		Provider:         infra.Provider,
+		Region:           infra.Region,
+		ExternalID:       taken.ExternalID,
+		Name:             name,
+		Status:           taken.Status,
+		SizeGB:           taken.SizeGB,
+		Metadata:         taken.Metadata,
+	}
+	if userID != "" {
+		snapshot.CreatedBy = &userID
+	}
+	if snapshot.Metadata == nil {
+		snapshot.Metadata = map[string]interface{}{}
+	}
+	if err := s.repoManager.InfraSnapshot.Create(ctx, snapshot); err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.Infrastructure.RecordChange(ctx, infra.ID, models.InfraChangeSnapshotted, nil, &name); err != nil {
+		log.Printf("Failed to record snapshot of infrastructure %s: %v", infra.ID, err)
+	}
+
+	return snapshot, nil
+}
+
+// ListSnapshots retrieves the snapshots taken of a resource, newest first, with their status
+// refreshed from the provider. Snapshots no longer at the provider are marked deleted. The
+// recorded snapshots are returned as they are when the provider cannot be reached.
+func (s *InfrastructureService) ListSnapshots(ctx context.Context, orgID, id string) ([]*models.InfrastructureSnapshot, error) {
+	infra, err := s.getOrgInfrastructure(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	provider, err := s.snapshotProvider(ctx, infra)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := s.repoManager.InfraSnapshot.ListByInfrastructure(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshots) == 0 || infra.ExternalID == nil {
+		return snapshots, nil
+	}
+
+	current, err := providerValue(withResourceRegion(ctx, infra.Region), func(ctx context.Context) ([]ProviderSnapshot, error) {
+		return provider.ListSnapshots(ctx, infra)
+	})
+	if err != nil {
+		log.Printf("Failed to refresh snapshots of infrastructure %s: %v", infra.ID, err)
+		return snapshots, nil
+	}
+
+	byID := make(map[string]ProviderSnapshot, len(current))
+	for _, snapshot := range current {
+		byID[snapshot.ExternalID] = snapshot
+	}
+	for _, snapshot := range snapshots {
+		status, sizeGB := models.SnapshotStatusDeleted, snapshot.SizeGB
+		if found, ok := byID[snapshot.ExternalID]; ok {
+			status = found.Status
+			if found.SizeGB != nil {
+				sizeGB = found.SizeGB
+			}
+		}
+		if status == snapshot.Status && sameSize(sizeGB, snapshot.SizeGB) {
+			continue
+		}
+
+		if err := s.repoManager.InfraSnapshot.UpdateStatus(ctx, snapshot.ID, status, sizeGB); err != nil {
+			log.Printf("Failed to update snapshot %s: %v", snapshot.ID, err)
+			continue
+		}
+		snapshot.Status = status
+		snapshot.SizeGB = sizeGB
+	}
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot provisions a new resource from a snapshot of a resource, in the snapshot's
+// region and with the resource's specifications. Like creating a resource, it stays pending until
+// the provider finishes restoring it.
+func (s *InfrastructureService) RestoreSnapshot(ctx context.Context, orgID, id, snapshotID string, req *models.RestoreSnapshotRequest) (*models.Infrastructure, error) {
+	source, err := s.getOrgInfrastructure(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := s.repoManager.InfraSnapshot.GetByID(ctx, orgID, snapshotID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrSnapshotNotFound
+		}
+		return nil, err
+	}
+	if snapshot.InfrastructureID == nil || *snapshot.InfrastructureID != source.ID {
+		return nil, ErrSnapshotNotFound
+	}
+	if snapshot.Status != models.SnapshotStatusAvailable {
+		return nil, fmt.Errorf("%w: snapshot %s is %s", ErrSnapshotNotAvailable, snapshot.Name, snapshot.Status)
+	}
+
+	tags := source.Tags
+	if len(req.Tags) > 0 {
+		if tags, err = NormalizeTags(req.Tags); err != nil {
+			return nil, err
+		}
+	}
+	specifications := make(map[string]interface{}, len(source.Specifications))
+	for key, value := range source.Specifications {
+		specifications[key] = value
+	}
+
+	restored := &models.Infrastructure{
+		ID:             uuid.New().String(),
+		OrganizationID: orgID,
+		Name:           req.Name,
+		Type:           source.Type,
+		Provider:       snapshot.Provider,
+		Region:         snapshot.Region,
+		Status:         models.InfraStatusPending,
+		Specifications: specifications,
+		Tags:           tags,
+	}
+	if err := s.checkQuota(ctx, restored); err != nil {
+		return nil, err
+	}
+	provider, err := s.snapshotProvider(ctx, restored)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.Infrastructure.Create(ctx, restored); err != nil {
+		return nil, fmt.Errorf("failed to create infrastructure in database: %w", err)
+	}
+
+	// Restore without the request context so restoring outlives the request
+	provisioned := *restored
+	s.startProvisioning(&provisioned, func(ctx context.Context) (string, error) {
+		return provider.RestoreFromSnapshot(ctx, source, &provisioned, snapshot.ExternalID)
+	})
+
+	return restored, nil
+}
+
+// sameSize reports whether two optional snapshot sizes are equal
+func sameSize(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// snapshotIdentifier derives a snapshot identifier from a snapshot name: lowercase letters, digits
+// and single hyphens, starting with a letter, as both RDS snapshot identifiers and Azure SQL
+// database names allow
+func snapshotIdentifier(name string) string {
+	identifier := snapshotInvalidChars.ReplaceAllString(strings.ToLower(name), "-")
+	for strings.Contains(identifier, "--") {
+		identifier = strings.ReplaceAll(identifier, "--", "-")
+	}
+	identifier = strings.Trim(identifier, "-")
+	if identifier == "" || identifier[0] < 'a' || identifier[0] > 'z' {
+		identifier = "snapshot-" + identifier
+	}
+	if len(identifier) > 255 {
+		identifier = identifier[:255]
+	}
+	return strings.TrimRight(identifier, "-")
+}
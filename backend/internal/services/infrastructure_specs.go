@@ -0,0 +1,238 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"cloudweave/internal/models"
+)
+
+// ErrInvalidSpecifications is matched by SpecValidationError
+var ErrInvalidSpecifications = errors.New("invalid infrastructure specifications")
+
+// SpecType is the JSON value type a specification key must have
+type SpecType string
+
+// Specification value types
+const (
+	SpecString     SpecType = "string"
+	SpecInteger    SpecType = "integer"
+	SpecNumber     SpecType = "number"
+	SpecBoolean    SpecType = "boolean"
+	SpecStringList SpecType = "string_list"
+	SpecStringMap  SpecType = "string_map"
+)
+
+// SpecField describes a specification key a resource type accepts. Check, when set, validates
+// values that have the right type.
+type SpecField struct {
+	Type     SpecType
+	Required bool
+	Check    func(value interface{}) error
+}
+
+// SpecSchema maps the specification keys a resource type accepts to their description
+type SpecSchema map[string]SpecField
+
+// specSchemas holds the specification schema of each provider's resource types. Adding a resource
+// type only needs an entry here; resource types without a schema are not validated.
+var specSchemas = map[string]map[string]SpecSchema{
+	models.ProviderAWS: {
+		models.InfraTypeServer: {
+			"instance_type":   {Type: SpecString},
+			"ami_id":          {Type: SpecString},
+			"key_name":        {Type: SpecString},
+			"security_groups": {Type: SpecStringList},
+		},
+		models.InfraTypeDatabase: {
+			"db_instance_class": {Type: SpecString},
+			"engine":            {Type: SpecString},
+			"allocated_storage": {Type: SpecInteger},
+		},
+		models.InfraTypeStorage:   {},
+		models.InfraTypeContainer: containerSpecSchema,
+	},
+	models.ProviderAzure: {
+		models.InfraTypeServer: {
+			"vm_size": {Type: SpecString},
+		},
+		models.InfraTypeDatabase: {
+			"sku_name": {Type: SpecString},
+		},
+		models.InfraTypeStorage: {
+			"account_tier":     {Type: SpecString},
+			"replication_type": {Type: SpecString},
+		},
+		models.InfraTypeContainer: containerSpecSchema,
+	},
+	models.ProviderGCP: {
+		models.InfraTypeServer: {
+			"machine_type": {Type: SpecString},
+		},
+		models.InfraTypeDatabase:  {},
+		models.InfraTypeStorage:   {},
+		models.InfraTypeContainer: containerSpecSchema,
+	},
+}
+
+// RegisterSpecSchema sets the specification schema of a provider's resource type, replacing any
+// schema already registered for it
+func RegisterSpecSchema(provider, resourceType string, schema SpecSchema) {
+	if specSchemas[provider] == nil {
+		specSchemas[provider] = make(map[string]SpecSchema)
+	}
+	specSchemas[provider][resourceType] = schema
+}
+
+// SpecTypeMismatch describes a specification value of the wrong type
+type SpecTypeMismatch struct {
+	Key      string   `json:"key"`
+	Expected SpecType `json:"expected"`
+}
+
+// SpecInvalidValue describes a specification value of the right type that its field's check rejected
+type SpecInvalidValue struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// SpecValidationError lists every problem found in a resource's specifications
+type SpecValidationError struct {
+	Provider     string             `json:"provider"`
+	ResourceType string             `json:"type"`
+	Unknown      []string           `json:"unknown,omitempty"`
+	Missing      []string           `json:"missing,omitempty"`
+	Mistyped     []SpecTypeMismatch `json:"mistyped,omitempty"`
+	Invalid      []SpecInvalidValue `json:"invalid,omitempty"`
+	Allowed      []string           `json:"allowed"`
+	Suggestions  map[string]string  `json:"suggestions,omitempty"`
+}
+
+func (e *SpecValidationError) Error() string {
+	var problems []string
+	if len(e.Unknown) > 0 {
+		problems = append(problems, "unknown keys: "+strings.Join(e.Unknown, ", "))
+	}
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing keys: "+strings.Join(e.Missing, ", "))
+	}
+	for _, mismatch := range e.Mistyped {
+		problems = append(problems, fmt.Sprintf("%s must be of type %s", mismatch.Key, mismatch.Expected))
+	}
+	for _, invalid := range e.Invalid {
+		problems = append(problems, fmt.Sprintf("%s %s", invalid.Key, invalid.Reason))
+	}
+	return fmt.Sprintf("%s for %s %s: %s", ErrInvalidSpecifications, e.Provider, e.ResourceType, strings.Join(problems, "; "))
+}
+
+// Is lets errors.Is match a SpecValidationError against ErrInvalidSpecifications
+func (e *SpecValidationError) Is(target error) bool {
+	return target == ErrInvalidSpecifications
+}
+
+// ValidateSpecifications checks a resource's specifications against the schema registered for its
+// provider and type, so mistakes are reported before any cloud call instead of silently falling
+// back to provider defaults
+func ValidateSpecifications(provider, resourceType string, specs map[string]interface{}) error {
+	schema, ok := specSchemas[provider][resourceType]
+	if !ok {
+		return nil
+	}
+
+	result := &SpecValidationError{
+		Provider:     provider,
+		ResourceType: resourceType,
+		Allowed:      sortedKeys(schema),
+	}
+
+	for _, key := range sortedKeys(specs) {
+		field, known := schema[key]
+		if !known {
+			result.Unknown = append(result.Unknown, key)
+			if suggestion, ok := suggestSpecKey(key, schema); ok {
+				if result.Suggestions == nil {
+					result.Suggestions = make(map[string]string)
+				}
+				result.Suggestions[key] = suggestion
+			}
+			continue
+		}
+		if !specValueHasType(specs[key], field.Type) {
+			result.Mistyped = append(result.Mistyped, SpecTypeMismatch{Key: key, Expected: field.Type})
+			continue
+		}
+		if field.Check != nil {
+			if err := field.Check(specs[key]); err != nil {
+				result.Invalid = append(result.Invalid, SpecInvalidValue{Key: key, Reason: err.Error()})
+			}
+		}
+	}
+
+	for _, key := range result.Allowed {
+		if _, present := specs[key]; schema[key].Required && !present {
+			result.Missing = append(result.Missing, key)
+		}
+	}
+
+	if len(result.Unknown) == 0 && len(result.Missing) == 0 && len(result.Mistyped) == 0 && len(result.Invalid) == 0 {
+		return nil
+	}
+	return result
+}
+
+// specValueHasType reports whether a decoded JSON value has the given specification type
+func specValueHasType(value interface{}, specType SpecType) bool {
+	switch specType {
+	case SpecString:
+		_, ok := value.(string)
+		return ok
+	case SpecBoolean:
+		_, ok := value.(bool)
+		return ok
+	case SpecNumber:
+		_, ok := value.(float64)
+		return ok
+	case SpecInteger:
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case SpecStringList:
+		items, ok := value.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range items {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	case SpecStringMap:
+		entries, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for _, entry := range entries {
+			if _, ok := entry.(string); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// suggestSpecKey finds the schema key an unknown key was probably meant to be, ignoring case,
+// underscores and dashes, e.g. instanceType for instance_type
+func suggestSpecKey(key string, schema SpecSchema) (string, bool) {
+	normalize := strings.NewReplacer("_", "", "-", "")
+	wanted := strings.ToLower(normalize.Replace(key))
+
+	for candidate := range schema {
+		if strings.ToLower(normalize.Replace(candidate)) == wanted {
+			return candidate, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"cloudweave/internal/models"
+)
+
+// ErrTagSyncUnsupported is returned when a resource's provider cannot update its tags
+var ErrTagSyncUnsupported = errors.New("provider does not support updating tags")
+
+// TagChanges are the tags to set on a cloud resource and the keys of the tags to remove from it
+type TagChanges struct {
+	Set    map[string]string
+	Remove []string
+}
+
+// TagUpdater is implemented by cloud providers that can keep their resources' tags in sync with
+// CloudWeave. Tags the changes do not mention, such as those the provider or other tools added,
+// are left alone.
+type TagUpdater interface {
+	// UpdateTags applies tag changes to a resource. When only some could be applied, it returns
+	// a *TagUpdateError listing the others.
+	UpdateTags(ctx context.Context, externalID string, tags TagChanges) error
+}
+
+// TagUpdateError lists the tag changes a provider could not apply to a resource
+type TagUpdateError struct {
+	Failed []models.TagSyncFailure
+}
+
+func (e *TagUpdateError) Error() string {
+	failed := make([]string, len(e.Failed))
+	for i, failure := range e.Failed {
+		failed[i] = fmt.Sprintf("%s %s: %s", failure.Action, failure.Key, failure.Error)
+	}
+	return "failed to update tags: " + strings.Join(failed, "; ")
+}
+
+// fail records that changing the tags with keys failed with err
+func (e *TagUpdateError) fail(action string, keys []string, err error) {
+	for _, key := range keys {
+		e.Failed = append(e.Failed, models.TagSyncFailure{Key: key, Action: action, Error: err.Error()})
+	}
+}
+
+// errOrNil returns e if any tag failed, so a provider can return it whatever happened
+func (e *TagUpdateError) errOrNil() error {
+	if len(e.Failed) == 0 {
+		return nil
+	}
+	return e
+}
+
+// sortedKeys returns the keys of tags to set, sorted
+func (c TagChanges) sortedKeys() []string {
+	keys := make([]string, 0, len(c.Set))
+	for key := range c.Set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffTags returns the changes that turn a resource's previous tags into its current tags
+func diffTags(previous, current []string) TagChanges {
+	before := make(map[string]string, len(previous))
+	for _, tag := range previous {
+		key, value := ParseTag(tag)
+		before[key] = value
+	}
+
+	changes := TagChanges{Set: make(map[string]string)}
+	after := make(map[string]bool, len(current))
+	for _, tag := range current {
+		key, value := ParseTag(tag)
+		after[key] = true
+		if old, ok := before[key]; !ok || old != value {
+			changes.Set[key] = value
+		}
+	}
+	for key := range before {
+		if !after[key] {
+			changes.Remove = append(changes.Remove, key)
+		}
+	}
+	sort.Strings(changes.Remove)
+	return changes
+}
+
+// SyncTags applies the changes between a resource's previous tags and its current tags to its
+// cloud resource, and records the outcome on infra.TagSync. CloudWeave stays the source of truth:
+// tags that could not be applied are kept and reported, not rolled back. Resources not yet
+// provisioned, and tag updates that change nothing, are left alone.
+func (s *InfrastructureService) SyncTags(ctx context.Context, infra *models.Infrastructure, previous []string) {
+	if infra.ExternalID == nil {
+		return
+	}
+	changes := diffTags(previous, infra.Tags)
+	if len(changes.Set) == 0 && len(changes.Remove) == 0 {
+		return
+	}
+
+	result := &models.TagSyncResult{Applied: []string{}, Removed: []string{}}
+	infra.TagSync = result
+
+	err := s.updateProviderTags(ctx, infra, changes)
+	var updateErr *TagUpdateError
+	if err != nil && !errors.As(err, &updateErr) {
+		updateErr = &TagUpdateError{}
+		updateErr.fail(models.TagSyncActionSet, changes.sortedKeys(), err)
+		updateErr.fail(models.TagSyncActionRemove, changes.Remove, err)
+	}
+
+	failed := make(map[string]bool)
+	if updateErr != nil {
+		log.Printf("Failed to sync tags of infrastructure %s to %s: %v", infra.ID, infra.Provider, updateErr)
+		for _, failure := range updateErr.Failed {
+			failed[failure.Action+" "+failure.Key] = true
+		}
+		result.Failed = updateErr.Failed
+	}
+	for _, key := range changes.sortedKeys() {
+		if !failed[models.TagSyncActionSet+" "+key] {
+			result.Applied = append(result.Applied, key+"="+changes.Set[key])
+		}
+	}
+	for _, key := range changes.Remove {
+		if !failed[models.TagSyncActionRemove+" "+key] {
+			result.Removed = append(result.Removed, key)
+		}
+	}
+}
+
+// updateProviderTags applies tag changes to a resource through its provider
+func (s *InfrastructureService) updateProviderTags(ctx context.Context, infra *models.Infrastructure, changes TagChanges) error {
+	provider, err := s.providerFor(ctx, infra.OrganizationID, infra.Provider)
+	if err != nil {
+		return err
+	}
+	updater, ok := provider.(TagUpdater)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrTagSyncUnsupported, infra.Provider)
+	}
+
+	return callProvider(withResourceRegion(ctx, infra.Region), ProviderCallTimeout(), func(ctx context.Context) error {
+		return updater.UpdateTags(ctx, *infra.ExternalID, changes)
+	})
+}
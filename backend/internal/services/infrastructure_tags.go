@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cloudweave/internal/models"
+)
+
+// ErrInvalidTag is returned when a tag cannot be normalized to key=value
+var ErrInvalidTag = errors.New("invalid tag")
+
+// ErrInfrastructureNotFound is returned when an infrastructure resource does not exist in the organization
+var ErrInfrastructureNotFound = errors.New("infrastructure resource not found")
+
+// Tag length limits, matching the strictest of the supported cloud providers
+const (
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// NormalizeTag normalizes a tag to "key=value" with a trimmed, lowercase key and a trimmed value.
+// A tag without "=" becomes a key with an empty value, e.g. "Production" becomes "production=".
+func NormalizeTag(tag string) (string, error) {
+	key, value, _ := strings.Cut(tag, "=")
+	key = strings.ToLower(strings.TrimSpace(key))
+	value = strings.TrimSpace(value)
+
+	if key == "" {
+		return "", fmt.Errorf("%w: %q has no key", ErrInvalidTag, tag)
+	}
+	if len(key) > maxTagKeyLength || len(value) > maxTagValueLength {
+		return "", fmt.Errorf("%w: %q is too long", ErrInvalidTag, tag)
+	}
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_.:/", r) {
+			return "", fmt.Errorf("%w: key of %q may only contain letters, digits and - _ . : /", ErrInvalidTag, tag)
+		}
+	}
+
+	return key + "=" + value, nil
+}
+
+// NormalizeTags normalizes tags and keeps the last value given for each key, sorted by key
+func NormalizeTags(tags []string) ([]string, error) {
+	byKey := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		normalized, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		key, _ := ParseTag(normalized)
+		byKey[key] = normalized
+	}
+
+	normalized := make([]string, 0, len(byKey))
+	for _, tag := range byKey {
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// ParseTag splits a normalized tag into its key and value
+func ParseTag(tag string) (string, string) {
+	key, value, _ := strings.Cut(tag, "=")
+	return key, value
+}
+
+// AddTags adds tags to an infrastructure resource, replacing any existing tags with the same keys
+func (s *InfrastructureService) AddTags(ctx context.Context, orgID, id string, tags []string) (*models.Infrastructure, error) {
+	normalized, err := NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := s.getOrgInfrastructure(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(normalized))
+	for i, tag := range normalized {
+		keys[i], _ = ParseTag(tag)
+	}
+
+	if err := s.repoManager.Infrastructure.ReplaceTags(ctx, id, orgID, keys, normalized); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInfrastructureNotFound
+		}
+		return nil, err
+	}
+
+	return s.tagsChanged(ctx, id, previous.Tags)
+}
+
+// RemoveTags removes tags from an infrastructure resource. A tag given with "=" removes that exact
+// key and value; a bare key removes the key whatever its value.
+func (s *InfrastructureService) RemoveTags(ctx context.Context, orgID, id string, tags []string) (*models.Infrastructure, error) {
+	var exact, keys []string
+	for _, tag := range tags {
+		normalized, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(tag, "=") {
+			exact = append(exact, normalized)
+		} else {
+			key, _ := ParseTag(normalized)
+			keys = append(keys, key)
+		}
+	}
+	previous, err := s.getOrgInfrastructure(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repoManager.Infrastructure.RemoveTags(ctx, id, orgID, exact, keys); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInfrastructureNotFound
+		}
+		return nil, err
+	}
+
+	return s.tagsChanged(ctx, id, previous.Tags)
+}
+
+// tagsChanged reloads a resource after its tags changed, syncs the change to its cloud resource
+// and publishes the update
+func (s *InfrastructureService) tagsChanged(ctx context.Context, id string, previous []string) (*models.Infrastructure, error) {
+	infra, err := s.repoManager.Infrastructure.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.SyncTags(ctx, infra, previous)
+	s.PublishEvent(ctx, models.WebhookEventInfrastructureUpdated, infra)
+
+	return infra, nil
+}
@@ -0,0 +1,393 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrJobNotFound is returned when a job does not exist or belongs to another organization
+	ErrJobNotFound = errors.New("job not found")
+
+	// ErrUnknownJobType is returned when a job is enqueued with a type no runner is registered for
+	ErrUnknownJobType = errors.New("unknown job type")
+
+	// ErrJobInProgress is returned when an organization already has an incomplete job of an
+	// exclusive type
+	ErrJobInProgress = errors.New("job already in progress")
+
+	// ErrJobHasNoFile is returned when a job did not produce a file to download
+	ErrJobHasNoFile = errors.New("job has no file")
+)
+
+// Job service defaults
+const (
+	DefaultJobWorkers   = 4
+	DefaultJobRetention = 7 * 24 * time.Hour
+
+	// jobPollInterval is how often idle workers check for jobs queued by other servers
+	jobPollInterval = 2 * time.Second
+
+	// maxJobAttempts is how many times a job is started before it is given up on when servers keep
+	// stopping while it runs
+	maxJobAttempts = 3
+
+	// jobLease is how long a running job stays claimed by its worker without being renewed. Jobs
+	// whose lease expires are requeued, so a job is picked up again this long after its server stops.
+	jobLease = time.Minute
+
+	// jobLeaseRenewInterval is how often a worker renews the lease on the job it runs
+	jobLeaseRenewInterval = jobLease / 3
+)
+
+// JobRunner runs a job and returns its result. progress records how far the job has got as a
+// percentage. A job that returns an error after its context is cancelled is requeued once its
+// lease expires rather than failed.
+type JobRunner func(ctx context.Context, job *models.Job, progress func(percent int)) (map[string]interface{}, error)
+
+// JobType describes how jobs of a type run
+type JobType struct {
+	Run JobRunner
+	// Exclusive allows an organization only one queued or running job of the type at a time
+	Exclusive bool
+}
+
+// JobService runs long operations in the background with a pool of workers. Jobs are stored, so
+// clients can poll them by ID and jobs interrupted by a restart run again. Workers hold a lease on
+// the jobs they run, so servers sharing the database only requeue jobs whose server has stopped.
+type JobService struct {
+	jobRepo   repositories.JobRepositoryInterface
+	types     map[string]JobType
+	workers   int
+	retention time.Duration
+	wake      chan struct{}
+	// workerID identifies this server's workers as the holders of job leases
+	workerID string
+}
+
+// NewJobService creates a job service. Job types must be registered before Start.
+func NewJobService(jobRepo repositories.JobRepositoryInterface) *JobService {
+	return &JobService{
+		jobRepo:   jobRepo,
+		types:     make(map[string]JobType),
+		workers:   DefaultJobWorkers,
+		retention: DefaultJobRetention,
+		wake:      make(chan struct{}, 1),
+		workerID:  uuid.New().String(),
+	}
+}
+
+// SetWorkers sets how many jobs run at once
+func (s *JobService) SetWorkers(workers int) {
+	if workers > 0 {
+		s.workers = workers
+	}
+}
+
+// SetRetention sets how long finished jobs are kept for polling
+func (s *JobService) SetRetention(retention time.Duration) {
+	if retention > 0 {
+		s.retention = retention
+	}
+}
+
+// Register sets how jobs of a type run
+func (s *JobService) Register(jobType string, definition JobType) {
+	s.types[jobType] = definition
+}
+
+// Enqueue queues a job of a registered type for the organization
+func (s *JobService) Enqueue(ctx context.Context, orgID, userID, jobType string, payload map[string]interface{}) (*models.Job, error) {
+	definition, ok := s.types[jobType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownJobType, jobType)
+	}
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+
+	job := &models.Job{
+		ID:             uuid.New().String(),
+		OrganizationID: orgID,
+		Type:           jobType,
+		Status:         models.JobStatusQueued,
+		Exclusive:      definition.Exclusive,
+		Payload:        payload,
+		CreatedAt:      time.Now(),
+	}
+	if userID != "" {
+		job.UserID = &userID
+	}
+
+	created, err := s.jobRepo.Create(ctx, job)
+	if err != nil {
+		return nil, err
+	}
+	if !created {
+		if existing, err := s.jobRepo.GetIncomplete(ctx, orgID, jobType); err == nil && existing != nil {
+			return nil, fmt.Errorf("%w: %s job %s", ErrJobInProgress, jobType, existing.ID)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrJobInProgress, jobType)
+	}
+
+	s.notifyWorkers()
+	return job, nil
+}
+
+// GetJob returns one of the organization's jobs. The content of a file in its result is left
+// out; it is downloaded with GetJobFile.
+func (s *JobService) GetJob(ctx context.Context, orgID, id string) (*models.Job, error) {
+	job, err := s.getJob(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	delete(job.Result, jobResultContent)
+	return job, nil
+}
+
+func (s *JobService) getJob(ctx context.Context, orgID, id string) (*models.Job, error) {
+	job, err := s.jobRepo.GetByID(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// Start runs queued jobs, requeues jobs whose lease expired because their server stopped, and
+// removes finished jobs past their retention until the context is cancelled
+func (s *JobService) Start(ctx context.Context) {
+	s.requeueExpired(ctx)
+
+	for i := 0; i < s.workers; i++ {
+		go s.work(ctx)
+	}
+
+	requeueTicker := time.NewTicker(jobLease)
+	defer requeueTicker.Stop()
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-requeueTicker.C:
+			s.requeueExpired(ctx)
+		case <-ticker.C:
+			count, err := s.jobRepo.DeleteFinishedBefore(ctx, time.Now().Add(-s.retention))
+			if err != nil {
+				log.Printf("Job cleanup failed: %v", err)
+				continue
+			}
+			if count > 0 {
+				log.Printf("Removed %d finished jobs", count)
+			}
+		}
+	}
+}
+
+// requeueExpired requeues running jobs whose lease has expired
+func (s *JobService) requeueExpired(ctx context.Context) {
+	requeued, failed, err := s.jobRepo.RequeueExpired(ctx, maxJobAttempts)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Failed to requeue interrupted jobs: %v", err)
+		}
+		return
+	}
+	if requeued > 0 || failed > 0 {
+		s.notifyWorkers()
+		log.Printf("Requeued %d interrupted jobs; failed %d interrupted too many times", requeued, failed)
+	}
+}
+
+// notifyWorkers wakes an idle worker to claim a newly queued job
+func (s *JobService) notifyWorkers() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// work claims and runs queued jobs one at a time until the context is cancelled
+func (s *JobService) work(ctx context.Context) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+
+	for {
+		job, err := s.jobRepo.ClaimNext(ctx, s.workerID, jobLease)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Failed to claim job: %v", err)
+		}
+		if job != nil {
+			// Another job may be waiting behind this one
+			s.notifyWorkers()
+			s.run(ctx, job)
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// run runs a claimed job and stores its outcome
+func (s *JobService) run(ctx context.Context, job *models.Job) {
+	definition, ok := s.types[job.Type]
+	if !ok {
+		s.fail(job, fmt.Sprintf("%v: %s", ErrUnknownJobType, job.Type))
+		return
+	}
+
+	// Renew the lease while the job runs. Losing it means the job was requeued, so the runner is
+	// stopped and its outcome left to the worker that claims the job next.
+	jobCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var leaseLost atomic.Bool
+	go s.renewLease(jobCtx, job, func() {
+		leaseLost.Store(true)
+		cancel()
+	})
+
+	// Runners may report progress from several goroutines
+	var mu sync.Mutex
+	lastProgress := 0
+	progress := func(percent int) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		percent = min(max(percent, 0), 99)
+		if percent <= lastProgress {
+			return
+		}
+		lastProgress = percent
+		if err := s.jobRepo.UpdateProgress(jobCtx, job.ID, percent); err != nil {
+			log.Printf("Failed to record progress of job %s: %v", job.ID, err)
+		}
+	}
+
+	result, err := runJob(jobCtx, definition.Run, job, progress)
+	if leaseLost.Load() {
+		log.Printf("Job %s (%s) stopped after losing its lease", job.ID, job.Type)
+		return
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			// Left running, so the job is requeued once its lease expires
+			log.Printf("Job %s (%s) interrupted by shutdown", job.ID, job.Type)
+			return
+		}
+		log.Printf("Job %s (%s) failed: %v", job.ID, job.Type, err)
+		s.fail(job, err.Error())
+		return
+	}
+
+	if err := s.jobRepo.Complete(context.Background(), job.ID, result); err != nil {
+		log.Printf("Failed to complete job %s: %v", job.ID, err)
+	}
+}
+
+// renewLease renews the lease on a running job until the context is done, calling lost if the
+// lease is no longer held
+func (s *JobService) renewLease(ctx context.Context, job *models.Job, lost func()) {
+	ticker := time.NewTicker(jobLeaseRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			held, err := s.jobRepo.RenewLease(ctx, job.ID, s.workerID, jobLease)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Failed to renew lease of job %s: %v", job.ID, err)
+				}
+				continue
+			}
+			if !held {
+				lost()
+				return
+			}
+		}
+	}
+}
+
+// runJob runs a job, turning a panic into an error so one bad job does not stop its worker
+func runJob(ctx context.Context, run JobRunner, job *models.Job, progress func(int)) (result map[string]interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return run(ctx, job, progress)
+}
+
+// fail records that a job failed
+func (s *JobService) fail(job *models.Job, message string) {
+	if err := s.jobRepo.Fail(context.Background(), job.ID, message); err != nil {
+		log.Printf("Failed to record failure of job %s: %v", job.ID, err)
+	}
+}
+
+// JobFile is a file a job produced, kept in its result until the job is removed
+type JobFile struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Keys of the file in the result of jobs that produce one
+const (
+	jobResultFilename    = "filename"
+	jobResultContentType = "contentType"
+	jobResultContent     = "content"
+)
+
+// setJobFile stores a file in a job result
+func setJobFile(result map[string]interface{}, file JobFile) {
+	result[jobResultFilename] = file.Filename
+	result[jobResultContentType] = file.ContentType
+	result[jobResultContent] = string(file.Content)
+}
+
+// GetJobFile returns the file one of the organization's completed jobs produced
+func (s *JobService) GetJobFile(ctx context.Context, orgID, id string) (*JobFile, error) {
+	job, err := s.getJob(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.JobStatusCompleted {
+		return nil, fmt.Errorf("%w: job is %s", ErrJobHasNoFile, job.Status)
+	}
+
+	filename, _ := job.Result[jobResultFilename].(string)
+	contentType, _ := job.Result[jobResultContentType].(string)
+	content, ok := job.Result[jobResultContent].(string)
+	if !ok || filename == "" {
+		return nil, fmt.Errorf("%w: %s jobs do not produce files", ErrJobHasNoFile, job.Type)
+	}
+
+	return &JobFile{
+		Filename:    filename,
+		ContentType: contentType,
+		Content:     []byte(content),
+	}, nil
+}
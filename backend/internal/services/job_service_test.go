@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// fakeJobRepo returns a fresh copy of one job, as the database would
+type fakeJobRepo struct {
+	repositories.JobRepositoryInterface
+
+	job models.Job
+}
+
+func (r *fakeJobRepo) GetByID(ctx context.Context, orgID, id string) (*models.Job, error) {
+	if id != r.job.ID || orgID != r.job.OrganizationID {
+		return nil, nil
+	}
+	job := r.job
+	job.Result = make(map[string]interface{}, len(r.job.Result))
+	for key, value := range r.job.Result {
+		job.Result[key] = value
+	}
+	return &job, nil
+}
+
+func TestGetJobLeavesOutFileContent(t *testing.T) {
+	result := map[string]interface{}{"count": 2}
+	setJobFile(result, JobFile{Filename: "export.csv", ContentType: "text/csv", Content: []byte("id,name\n")})
+	jobService := NewJobService(&fakeJobRepo{job: models.Job{
+		ID:             "job-1",
+		OrganizationID: "org-1",
+		Status:         models.JobStatusCompleted,
+		Result:         result,
+	}})
+
+	job, err := jobService.GetJob(context.Background(), "org-1", "job-1")
+	if err != nil {
+		t.Fatalf("GetJob returned an error: %v", err)
+	}
+	if _, ok := job.Result[jobResultContent]; ok {
+		t.Error("job result includes the file content")
+	}
+	if job.Result[jobResultFilename] != "export.csv" || job.Result["count"] != 2 {
+		t.Errorf("job result = %v, want the rest of the result kept", job.Result)
+	}
+
+	file, err := jobService.GetJobFile(context.Background(), "org-1", "job-1")
+	if err != nil {
+		t.Fatalf("GetJobFile returned an error: %v", err)
+	}
+	if string(file.Content) != "id,name\n" {
+		t.Errorf("file content = %q, want the exported file", file.Content)
+	}
+}
@@ -18,6 +18,7 @@ import (
 type JWTService struct {
 	config           *config.Config
 	blacklistService *TokenBlacklistService
+	keys             *jwtKeySet
 }
 
 type Claims struct {
@@ -27,25 +28,61 @@ type Claims struct {
 	Role           string `json:"role"`
 	OrganizationID string `json:"organizationId"`
 	TokenID        string `json:"jti"`
+	// ImpersonatedBy is the support user acting as UserID, for tokens issued by impersonation
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
 	jwt.RegisteredClaims
 }
 
 type RefreshClaims struct {
-	UserID  string `json:"sub"`
-	TokenID string `json:"jti"`
+	UserID   string `json:"sub"`
+	TokenID  string `json:"jti"`
+	FamilyID string `json:"fid,omitempty"`
+	// OrganizationID is the organization access tokens are scoped to, when the user switched away
+	// from their home organization
+	OrganizationID string `json:"organizationId,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// MFAClaims identify a user who has passed the password step but not yet the second factor
+type MFAClaims struct {
+	UserID string `json:"sub"`
+	jwt.RegisteredClaims
+}
+
+// EmailVerificationClaims identify a user and the email address a verification link was sent to
+type EmailVerificationClaims struct {
+	UserID string `json:"sub"`
+	Email  string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// emailVerificationTokenLifetime is how long an email verification link stays valid
+const emailVerificationTokenLifetime = 24 * time.Hour
+
+// emailVerificationKeySuffix derives the email verification token signing key from the signing key
+const emailVerificationKeySuffix = ":email-verification"
+
+// mfaTokenLifetime is how long a user has to complete the second factor after entering their password
+const mfaTokenLifetime = 5 * time.Minute
+
+// mfaKeySuffix derives the MFA token signing key from the signing key
+const mfaKeySuffix = ":mfa"
+
+// impersonationTokenLifetime is the longest an impersonation token stays valid
+const impersonationTokenLifetime = 15 * time.Minute
+
 var (
 	ErrInvalidToken     = errors.New("invalid token")
 	ErrExpiredToken     = errors.New("token has expired")
 	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenReused      = errors.New("refresh token reuse detected")
 )
 
 func NewJWTService(cfg *config.Config, blacklistService *TokenBlacklistService) *JWTService {
 	return &JWTService{
 		config:           cfg,
 		blacklistService: blacklistService,
+		keys:             newJWTKeySet(cfg),
 	}
 }
 
@@ -70,17 +107,62 @@ func (j *JWTService) GenerateAccessToken(user models.User) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.config.JWTSecret))
+	return j.signToken(claims, "")
+}
+
+// GenerateImpersonationToken creates an access token for the user that records the support user
+// impersonating them, valid for at most impersonationTokenLifetime. It returns the token's ID and
+// expiry along with it.
+func (j *JWTService) GenerateImpersonationToken(user models.User, impersonatorID string) (string, string, time.Time, error) {
+	tokenID := uuid.New().String()
+	now := time.Now()
+	expiresAt := now.Add(min(impersonationTokenLifetime, j.config.JWTExpirationTime))
+
+	claims := Claims{
+		UserID:         user.ID,
+		Email:          user.Email,
+		Name:           user.Name,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
+		TokenID:        tokenID,
+		ImpersonatedBy: impersonatorID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "cloudweave",
+			Subject:   user.ID,
+			ID:        tokenID,
+		},
+	}
+
+	token, err := j.signToken(claims, "")
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	return token, tokenID, expiresAt, nil
 }
 
-// GenerateRefreshToken creates a new JWT refresh token for the user
+// GenerateRefreshToken creates a new JWT refresh token for the user, starting a new token family
 func (j *JWTService) GenerateRefreshToken(userID string) (string, error) {
+	return j.generateRefreshToken(userID, uuid.New().String(), "")
+}
+
+// GenerateOrganizationRefreshToken creates a new JWT refresh token whose access tokens are scoped
+// to one of the user's organizations, starting a new token family
+func (j *JWTService) GenerateOrganizationRefreshToken(userID, organizationID string) (string, error) {
+	return j.generateRefreshToken(userID, uuid.New().String(), organizationID)
+}
+
+// generateRefreshToken creates a refresh token belonging to the given token family
+func (j *JWTService) generateRefreshToken(userID, familyID, organizationID string) (string, error) {
 	tokenID := uuid.New().String()
 
 	claims := RefreshClaims{
-		UserID:  userID,
-		TokenID: tokenID,
+		UserID:         userID,
+		TokenID:        tokenID,
+		FamilyID:       familyID,
+		OrganizationID: organizationID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.config.JWTRefreshTime)),
@@ -91,19 +173,12 @@ func (j *JWTService) GenerateRefreshToken(userID string) (string, error) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.config.JWTSecret))
+	return j.signToken(claims, "")
 }
 
 // ValidateToken validates a JWT token and returns the claims
 func (j *JWTService) ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidSignature
-		}
-		return []byte(j.config.JWTSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, j.keyFunc(""))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -122,6 +197,15 @@ func (j *JWTService) ValidateToken(ctx context.Context, tokenString string) (*Cl
 			if isBlacklisted {
 				return nil, ErrInvalidToken
 			}
+			if err := j.checkUserTokensRevoked(ctx, claims.UserID, claims.IssuedAt); err != nil {
+				return nil, err
+			}
+			// Impersonation ends too when the support user's own tokens are revoked
+			if claims.ImpersonatedBy != "" {
+				if err := j.checkUserTokensRevoked(ctx, claims.ImpersonatedBy, claims.IssuedAt); err != nil {
+					return nil, err
+				}
+			}
 		}
 		return claims, nil
 	}
@@ -131,13 +215,7 @@ func (j *JWTService) ValidateToken(ctx context.Context, tokenString string) (*Cl
 
 // ValidateRefreshToken validates a refresh token and returns the claims
 func (j *JWTService) ValidateRefreshToken(ctx context.Context, tokenString string) (*RefreshClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
-		// Verify signing method
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidSignature
-		}
-		return []byte(j.config.JWTSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, j.keyFunc(""))
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenExpired) {
@@ -147,15 +225,32 @@ func (j *JWTService) ValidateRefreshToken(ctx context.Context, tokenString strin
 	}
 
 	if claims, ok := token.Claims.(*RefreshClaims); ok && token.Valid {
-		// Check if token is blacklisted
 		if j.blacklistService != nil {
-			isBlacklisted, err := j.blacklistService.IsTokenBlacklisted(ctx, claims.TokenID)
+			// A rotated token being presented again means it has leaked
+			reason, isBlacklisted, err := j.blacklistService.GetBlacklistReason(ctx, claims.TokenID)
 			if err != nil {
 				return nil, fmt.Errorf("failed to check token blacklist: %w", err)
 			}
 			if isBlacklisted {
+				if reason == TokenRotatedReason {
+					return nil, j.revokeRefreshTokenFamily(ctx, claims)
+				}
 				return nil, ErrInvalidToken
 			}
+
+			if claims.FamilyID != "" {
+				revoked, err := j.blacklistService.IsRefreshTokenFamilyRevoked(ctx, claims.FamilyID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to check refresh token family: %w", err)
+				}
+				if revoked {
+					return nil, ErrInvalidToken
+				}
+			}
+
+			if err := j.checkUserTokensRevoked(ctx, claims.UserID, claims.IssuedAt); err != nil {
+				return nil, err
+			}
 		}
 		return claims, nil
 	}
@@ -163,6 +258,173 @@ func (j *JWTService) ValidateRefreshToken(ctx context.Context, tokenString strin
 	return nil, ErrInvalidToken
 }
 
+// RevokeUserTokens invalidates every access and refresh token issued to a user so far
+func (j *JWTService) RevokeUserTokens(ctx context.Context, userID, reason string) error {
+	if j.blacklistService == nil {
+		return fmt.Errorf("blacklist service not available")
+	}
+
+	expiresAt := time.Now().Add(max(j.config.JWTExpirationTime, j.config.JWTRefreshTime))
+	return j.blacklistService.BlacklistAllUserTokens(ctx, userID, expiresAt, reason)
+}
+
+// checkUserTokensRevoked returns ErrInvalidToken if the user's tokens were revoked after the token
+// was issued
+func (j *JWTService) checkUserTokensRevoked(ctx context.Context, userID string, issuedAt *jwt.NumericDate) error {
+	if issuedAt == nil {
+		return nil
+	}
+
+	revoked, err := j.blacklistService.AreUserTokensRevoked(ctx, userID, issuedAt.Time)
+	if err != nil {
+		return fmt.Errorf("failed to check token blacklist: %w", err)
+	}
+	if revoked {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// RotateRefreshToken invalidates a validated refresh token and issues its replacement in the same
+// token family. If the token has already been rotated the whole family is revoked.
+func (j *JWTService) RotateRefreshToken(ctx context.Context, claims *RefreshClaims) (string, error) {
+	if j.blacklistService == nil {
+		return "", fmt.Errorf("blacklist service not available")
+	}
+
+	rotated, err := j.blacklistService.MarkTokenRotated(ctx, claims.TokenID, claims.UserID, claims.ExpiresAt.Time)
+	if err != nil {
+		return "", err
+	}
+	if !rotated {
+		return "", j.revokeRefreshTokenFamily(ctx, claims)
+	}
+
+	familyID := claims.FamilyID
+	if familyID == "" {
+		// Tokens issued before families were introduced start a new one
+		familyID = uuid.New().String()
+	}
+
+	return j.generateRefreshToken(claims.UserID, familyID, claims.OrganizationID)
+}
+
+// revokeRefreshTokenFamily revokes the family of a reused refresh token and returns ErrTokenReused
+func (j *JWTService) revokeRefreshTokenFamily(ctx context.Context, claims *RefreshClaims) error {
+	if claims.FamilyID == "" {
+		return ErrTokenReused
+	}
+
+	// Every token in the family expires within one refresh lifetime from now
+	expiresAt := time.Now().Add(j.config.JWTRefreshTime)
+	if err := j.blacklistService.RevokeRefreshTokenFamily(ctx, claims.FamilyID, claims.UserID, expiresAt, "reuse_detected"); err != nil {
+		return fmt.Errorf("%w: %v", ErrTokenReused, err)
+	}
+
+	return ErrTokenReused
+}
+
+// GenerateMFAToken creates a short-lived token proving the password step of an MFA login succeeded.
+// It is signed with a key derived from the signing key so it can never be accepted as an access or
+// refresh token.
+func (j *JWTService) GenerateMFAToken(userID string) (string, error) {
+	claims := MFAClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(mfaTokenLifetime)),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    "cloudweave",
+			Subject:   userID,
+			ID:        uuid.New().String(),
+		},
+	}
+
+	return j.signToken(claims, mfaKeySuffix)
+}
+
+// ValidateMFAToken validates an MFA token that has not been used yet and returns its claims
+func (j *JWTService) ValidateMFAToken(ctx context.Context, tokenString string) (*MFAClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &MFAClaims{}, j.keyFunc(mfaKeySuffix))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*MFAClaims)
+	if !ok || !token.Valid || claims.UserID == "" || claims.ID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	if j.blacklistService != nil {
+		used, err := j.blacklistService.IsTokenBlacklisted(ctx, claims.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check token blacklist: %w", err)
+		}
+		if used {
+			return nil, ErrInvalidToken
+		}
+	}
+
+	return claims, nil
+}
+
+// ConsumeMFAToken marks an MFA token used so it cannot complete another login. It returns
+// ErrInvalidToken when the token was already used, e.g. by a concurrent request.
+func (j *JWTService) ConsumeMFAToken(ctx context.Context, claims *MFAClaims, reason string) error {
+	if j.blacklistService == nil {
+		return nil
+	}
+
+	consumed, err := j.blacklistService.BlacklistTokenOnce(ctx, claims.ID, claims.UserID, "mfa", claims.ExpiresAt.Time, reason)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// GenerateEmailVerificationToken creates a token proving the holder received mail at the user's
+// email address. Like MFA tokens it is signed with a derived key.
+func (j *JWTService) GenerateEmailVerificationToken(userID, email string) (string, error) {
+	claims := EmailVerificationClaims{
+		UserID: userID,
+		Email:  email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(emailVerificationTokenLifetime)),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    "cloudweave",
+			Subject:   userID,
+			ID:        uuid.New().String(),
+		},
+	}
+
+	return j.signToken(claims, emailVerificationKeySuffix)
+}
+
+// ValidateEmailVerificationToken validates an email verification token and returns its claims
+func (j *JWTService) ValidateEmailVerificationToken(tokenString string) (*EmailVerificationClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &EmailVerificationClaims{}, j.keyFunc(emailVerificationKeySuffix))
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	if claims, ok := token.Claims.(*EmailVerificationClaims); ok && token.Valid && claims.UserID != "" {
+		return claims, nil
+	}
+
+	return nil, ErrInvalidToken
+}
+
 // ParseToken extracts claims from a token without validation (for debugging)
 func (j *JWTService) ParseToken(tokenString string) (*Claims, error) {
 	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, &Claims{})
@@ -219,9 +481,7 @@ func (j *JWTService) BlacklistRefreshToken(ctx context.Context, tokenString, rea
 		return fmt.Errorf("blacklist service not available")
 	}
 
-	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(j.config.JWTSecret), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, j.keyFunc(""))
 
 	if err != nil {
 		return fmt.Errorf("failed to parse refresh token: %w", err)
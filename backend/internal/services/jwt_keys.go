@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"cloudweave/internal/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// legacyJWTKeyID identifies JWT_SECRET, which signed tokens issued before key IDs were added.
+// Tokens without a kid header are validated with it.
+const legacyJWTKeyID = "default"
+
+// jwtKey is a signing key in the key set. Previous keys have a retiresAt after which the tokens
+// they signed have all expired and they are dropped.
+type jwtKey struct {
+	secret    []byte
+	retiresAt *time.Time
+}
+
+// jwtKeySet holds the current signing key and the previous keys still accepted for validation
+type jwtKeySet struct {
+	mu        sync.RWMutex
+	currentID string
+	keys      map[string]*jwtKey
+}
+
+// newJWTKeySet builds the key set from config. Without configured keys JWT_SECRET is the only
+// key. Otherwise every key but the current one, including JWT_SECRET when it is set and not among
+// them, retires one maximum token lifetime after JWT_KEYS_ROTATED_AT.
+func newJWTKeySet(cfg *config.Config) *jwtKeySet {
+	set := &jwtKeySet{keys: make(map[string]*jwtKey)}
+
+	if len(cfg.JWTSigningKeys) == 0 {
+		set.currentID = legacyJWTKeyID
+		set.keys[legacyJWTKeyID] = &jwtKey{secret: []byte(cfg.JWTSecret)}
+		return set
+	}
+
+	set.currentID = cfg.JWTCurrentKeyID
+	if !hasJWTSigningKey(cfg.JWTSigningKeys, set.currentID) {
+		set.currentID = cfg.JWTSigningKeys[0].ID
+	}
+
+	retiresAt := cfg.JWTKeysRotatedAt.Add(maxJWTLifetime(cfg))
+
+	for _, key := range cfg.JWTSigningKeys {
+		set.keys[key.ID] = &jwtKey{secret: []byte(key.Secret), retiresAt: &retiresAt}
+	}
+	if _, ok := set.keys[legacyJWTKeyID]; !ok && cfg.JWTSecret != "" {
+		set.keys[legacyJWTKeyID] = &jwtKey{secret: []byte(cfg.JWTSecret), retiresAt: &retiresAt}
+	}
+	set.keys[set.currentID].retiresAt = nil
+
+	return set
+}
+
+func hasJWTSigningKey(keys []config.JWTSigningKey, id string) bool {
+	for _, key := range keys {
+		if key.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// maxJWTLifetime is the longest any issued token stays valid
+func maxJWTLifetime(cfg *config.Config) time.Duration {
+	return max(cfg.JWTExpirationTime, cfg.JWTRefreshTime, mfaTokenLifetime, emailVerificationTokenLifetime)
+}
+
+// current returns the ID and secret of the key new tokens are signed with
+func (s *jwtKeySet) current() (string, []byte) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.currentID, s.keys[s.currentID].secret
+}
+
+// secret returns the secret of a key that has not retired
+func (s *jwtKeySet) secret(id string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[id]
+	if !ok || (key.retiresAt != nil && !time.Now().Before(*key.retiresAt)) {
+		return nil, false
+	}
+	return key.secret, true
+}
+
+// rotate makes a new key current. The previous current key keeps validating the tokens it signed
+// until the longest of them expires.
+func (s *jwtKeySet) rotate(id string, secret []byte, lifetime time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if previous, ok := s.keys[s.currentID]; ok && s.currentID != id {
+		retiresAt := time.Now().Add(lifetime)
+		previous.retiresAt = &retiresAt
+	}
+	s.keys[id] = &jwtKey{secret: secret}
+	s.currentID = id
+}
+
+// retire drops keys past their retirement and returns their IDs
+func (s *jwtKeySet) retire() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var retired []string
+	now := time.Now()
+	for id, key := range s.keys {
+		if key.retiresAt != nil && !now.Before(*key.retiresAt) {
+			delete(s.keys, id)
+			retired = append(retired, id)
+		}
+	}
+	return retired
+}
+
+// signToken signs a token with the current key, recording its ID in the kid header. suffix
+// derives a separate key for tokens that must not be accepted as access or refresh tokens.
+func (j *JWTService) signToken(claims jwt.Claims, suffix string) (string, error) {
+	id, secret := j.keys.current()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = id
+	return token.SignedString([]byte(string(secret) + suffix))
+}
+
+// keyFunc returns the validation key of a token from its kid header, rejecting tokens signed
+// with an unknown or retired key
+func (j *JWTService) keyFunc(suffix string) jwt.Keyfunc {
+	return func(token *jwt.Token) (interface{}, error) {
+		// Verify signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidSignature
+		}
+
+		id, _ := token.Header["kid"].(string)
+		if id == "" {
+			id = legacyJWTKeyID
+		}
+		secret, ok := j.keys.secret(id)
+		if !ok {
+			return nil, ErrInvalidSignature
+		}
+		return []byte(string(secret) + suffix), nil
+	}
+}
+
+// RotateSigningKey makes a new key current for signing. Tokens signed with the previous key stay
+// valid until they expire, after which the key is retired.
+func (j *JWTService) RotateSigningKey(id, secret string) {
+	j.keys.rotate(id, []byte(secret), maxJWTLifetime(j.config))
+	log.Printf("Rotated JWT signing key to %s", id)
+}
+
+// StartKeyRetirement periodically drops signing keys whose tokens have all expired until ctx is
+// cancelled
+func (j *JWTService) StartKeyRetirement(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, id := range j.keys.retire() {
+				log.Printf("Retired JWT signing key %s", id)
+			}
+		}
+	}
+}
@@ -3,6 +3,10 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
 	"time"
 
 	"cloudweave/internal/models"
@@ -13,7 +17,14 @@ import (
 type MetricsService struct {
 	repoManager  *repositories.RepositoryManager
 	providers    map[string]CloudProvider
+	awsAccounts  *AWSAccounts
 	alertService *AlertService
+	broker       *metricBroker
+
+	// scalingExecution allows scaling recommendations to be carried out; scaling holds the IDs
+	// of resources being resized
+	scalingExecution bool
+	scaling          sync.Map
 }
 
 // NewMetricsService creates a new metrics service
@@ -22,9 +33,16 @@ func NewMetricsService(repoManager *repositories.RepositoryManager, providers ma
 		repoManager:  repoManager,
 		providers:    providers,
 		alertService: NewAlertService(repoManager),
+		broker:       newMetricBroker(),
 	}
 }
 
+// SetAWSAccounts sets where the AWS accounts organizations manage through their own credentials
+// come from, so their resources are read and resized in those accounts
+func (s *MetricsService) SetAWSAccounts(accounts *AWSAccounts) {
+	s.awsAccounts = accounts
+}
+
 // MetricData represents a single metric data point
 type MetricData struct {
 	ID           string                 `json:"id"`
@@ -102,27 +120,40 @@ func (s *MetricsService) CollectMetrics(ctx context.Context, orgID string) error
 		return fmt.Errorf("failed to get infrastructure: %w", err)
 	}
 
+	providers, err := s.awsAccounts.providersFor(ctx, orgID, s.providers)
+	if err != nil {
+		return err
+	}
+
+	// Fetch metrics in bulk from providers that support it
+	batched := batchResourceMetrics(ctx, providers, infrastructures)
+
 	// Collect metrics for each resource
 	for _, infra := range infrastructures {
 		if infra.ExternalID == nil {
 			continue
 		}
 
-		provider, exists := s.providers[infra.Provider]
+		provider, exists := providers[infra.Provider]
 		if !exists {
 			continue
 		}
 
 		// Get metrics from cloud provider
-		metrics, err := provider.GetResourceMetrics(ctx, *infra.ExternalID)
-		if err != nil {
-			// Log error but continue with other resources
-			fmt.Printf("Failed to get metrics for resource %s: %v\n", infra.ID, err)
-			continue
+		metrics, ok := batched[infra.ID]
+		if !ok {
+			metrics, err = providerValue(withResourceRegion(ctx, infra.Region), func(ctx context.Context) (map[string]interface{}, error) {
+				return provider.GetResourceMetrics(ctx, *infra.ExternalID)
+			})
+			if err != nil {
+				// Log error but continue with other resources
+				fmt.Printf("Failed to get metrics for resource %s: %v\n", infra.ID, err)
+				continue
+			}
 		}
 
 		// Store metrics in database
-		if err := s.storeMetrics(ctx, infra.ID, infra.Type, metrics); err != nil {
+		if err := s.storeMetrics(ctx, infra.OrganizationID, infra.ID, infra.Type, metrics); err != nil {
 			fmt.Printf("Failed to store metrics for resource %s: %v\n", infra.ID, err)
 			continue
 		}
@@ -134,12 +165,23 @@ func (s *MetricsService) CollectMetrics(ctx context.Context, orgID string) error
 	return nil
 }
 
-// GetResourceMetrics retrieves metrics for a specific resource
+// Thresholds above which GetResourceMetrics reads rollups instead of raw data points
+const (
+	hourlyMetricsThreshold = 6 * time.Hour
+	dailyMetricsThreshold  = 30 * 24 * time.Hour
+)
+
+// GetResourceMetrics retrieves metrics for a specific resource. Short ranges return raw data
+// points; longer ranges return hourly or daily rollups, with the bucket average as the value.
 func (s *MetricsService) GetResourceMetrics(ctx context.Context, resourceID string, duration time.Duration) ([]MetricData, error) {
 	// Get metrics from database for the specified duration
 	endTime := time.Now()
 	startTime := endTime.Add(-duration)
 
+	if resolution := metricResolutionFor(duration); resolution != models.MetricResolutionRaw {
+		return s.getRollupMetrics(ctx, resolution, resourceID, startTime, endTime)
+	}
+
 	// Use Query method instead of GetByResourceIDAndTimeRange
 	query := models.MetricQuery{
 		ResourceID: &resourceID,
@@ -170,10 +212,336 @@ func (s *MetricsService) GetResourceMetrics(ctx context.Context, resourceID stri
 	return metricData, nil
 }
 
-// GetAggregatedMetrics retrieves aggregated metrics for an organization
-func (s *MetricsService) GetAggregatedMetrics(ctx context.Context, orgID string) ([]MetricsAggregation, error) {
-	// TODO: Implement when metric repository methods are available
-	return []MetricsAggregation{}, nil
+// GetResourceMetricsPage retrieves one page of a resource's raw data points, newest first. An empty
+// cursor starts from the latest point; the returned cursor is empty on the last page.
+func (s *MetricsService) GetResourceMetricsPage(ctx context.Context, resourceID string, duration time.Duration, cursor string, limit int) ([]MetricData, string, error) {
+	endTime := time.Now()
+	startTime := endTime.Add(-duration)
+
+	query := models.MetricQuery{
+		ResourceID: &resourceID,
+		StartTime:  &startTime,
+		EndTime:    &endTime,
+		Limit:      limit,
+		Cursor:     cursor,
+	}
+	metrics, err := s.repoManager.Metric.Query(ctx, query)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get metrics from database: %w", err)
+	}
+
+	metricData := make([]MetricData, 0, len(metrics))
+	for _, metric := range metrics {
+		metricData = append(metricData, MetricData{
+			ID:           metric.ID,
+			ResourceID:   *metric.ResourceID,
+			ResourceType: metric.ResourceType,
+			MetricName:   metric.MetricName,
+			Value:        metric.Value,
+			Unit:         metric.Unit,
+			Timestamp:    metric.Timestamp,
+			Tags:         s.parseTagsFromMap(metric.Tags),
+			Metadata:     make(map[string]interface{}),
+		})
+	}
+
+	var nextCursor string
+	if len(metrics) == limit {
+		last := metrics[len(metrics)-1]
+		nextCursor = repositories.EncodeCursor(last.Timestamp, last.ID)
+	}
+
+	return metricData, nextCursor, nil
+}
+
+// metricResolutionFor picks the coarsest resolution that still suits the requested range
+func metricResolutionFor(duration time.Duration) string {
+	switch {
+	case duration < hourlyMetricsThreshold:
+		return models.MetricResolutionRaw
+	case duration < dailyMetricsThreshold:
+		return models.MetricResolutionHourly
+	default:
+		return models.MetricResolutionDaily
+	}
+}
+
+// getRollupMetrics retrieves rolled up metrics for a resource. Buckets the rollup job has not
+// reached yet, including the one in progress, are aggregated on the fly so recent data is included.
+func (s *MetricsService) getRollupMetrics(ctx context.Context, resolution, resourceID string, startTime, endTime time.Time) ([]MetricData, error) {
+	rollups, err := s.repoManager.Metric.QueryRollups(ctx, resolution, resourceID, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s metrics from database: %w", resolution, err)
+	}
+
+	pending, err := s.pendingRollups(ctx, resolution, resourceID, startTime, endTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending %s metrics from database: %w", resolution, err)
+	}
+	rollups = append(pending, rollups...)
+
+	var metricData []MetricData
+	for _, rollup := range rollups {
+		metricData = append(metricData, MetricData{
+			ID:           rollup.ID,
+			ResourceID:   rollup.ResourceID,
+			ResourceType: rollup.ResourceType,
+			MetricName:   rollup.MetricName,
+			Value:        rollup.Avg,
+			Unit:         rollup.Unit,
+			Timestamp:    rollup.Bucket,
+			Tags:         make(map[string]string),
+			Metadata: map[string]interface{}{
+				"resolution": resolution,
+				"min":        rollup.Min,
+				"max":        rollup.Max,
+				"count":      rollup.Count,
+			},
+		})
+	}
+
+	return metricData, nil
+}
+
+// pendingRollups aggregates a resource's buckets newer than the latest rolled up bucket at a
+// resolution, newest first. Hourly buckets come from raw points; daily buckets combine the hourly
+// rollups and raw points of days not yet rolled up.
+func (s *MetricsService) pendingRollups(ctx context.Context, resolution, resourceID string, startTime, endTime time.Time) ([]*models.MetricRollup, error) {
+	hourlySince, err := s.nextRollupBucket(ctx, models.MetricResolutionHourly, time.Hour, startTime)
+	if err != nil {
+		return nil, err
+	}
+	hourly, err := s.repoManager.Metric.AggregateHourly(ctx, resourceID, hourlySince, endTime)
+	if err != nil {
+		return nil, err
+	}
+	if resolution == models.MetricResolutionHourly {
+		return hourly, nil
+	}
+
+	dailySince, err := s.nextRollupBucket(ctx, models.MetricResolutionDaily, 24*time.Hour, startTime)
+	if err != nil {
+		return nil, err
+	}
+	rolledUp, err := s.repoManager.Metric.QueryRollups(ctx, models.MetricResolutionHourly, resourceID, dailySince, endTime)
+	if err != nil {
+		return nil, err
+	}
+	return combineDailyRollups(append(hourly, rolledUp...), dailySince), nil
+}
+
+// nextRollupBucket returns the start of the first bucket after the latest one rolled up at a
+// resolution, or startTime if that is later or nothing has been rolled up yet
+func (s *MetricsService) nextRollupBucket(ctx context.Context, resolution string, size time.Duration, startTime time.Time) (time.Time, error) {
+	latest, err := s.repoManager.Metric.GetLatestRollupBucket(ctx, resolution)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if latest == nil || latest.Add(size).Before(startTime) {
+		return startTime, nil
+	}
+	return latest.Add(size), nil
+}
+
+// combineDailyRollups merges hourly buckets from since onwards into daily buckets, newest first
+func combineDailyRollups(hourly []*models.MetricRollup, since time.Time) []*models.MetricRollup {
+	type dayKey struct {
+		metricName string
+		day        time.Time
+	}
+
+	days := make(map[dayKey]*models.MetricRollup)
+	var daily []*models.MetricRollup
+	for _, hour := range hourly {
+		if hour.Bucket.Before(since) || hour.Count == 0 {
+			continue
+		}
+
+		key := dayKey{hour.MetricName, hour.Bucket.UTC().Truncate(24 * time.Hour)}
+		day, ok := days[key]
+		if !ok {
+			day = &models.MetricRollup{
+				ResourceID:   hour.ResourceID,
+				ResourceType: hour.ResourceType,
+				MetricName:   hour.MetricName,
+				Unit:         hour.Unit,
+				Bucket:       key.day,
+				Min:          hour.Min,
+				Max:          hour.Max,
+			}
+			days[key] = day
+			daily = append(daily, day)
+		}
+
+		day.Min = math.Min(day.Min, hour.Min)
+		day.Max = math.Max(day.Max, hour.Max)
+		day.Avg = (day.Avg*float64(day.Count) + hour.Avg*float64(hour.Count)) / float64(day.Count+hour.Count)
+		day.Count += hour.Count
+	}
+
+	sort.Slice(daily, func(i, j int) bool {
+		if !daily[i].Bucket.Equal(daily[j].Bucket) {
+			return daily[i].Bucket.After(daily[j].Bucket)
+		}
+		return daily[i].MetricName < daily[j].MetricName
+	})
+	return daily
+}
+
+// RollupMetrics aggregates completed hours of raw metrics into hourly rollups and completed days
+// of hourly rollups into daily rollups. The retention purge deletes the data once it has been
+// rolled up.
+func (s *MetricsService) RollupMetrics(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	// The latest bucket is recomputed in case points arrived after it was last rolled up
+	var hourlySince time.Time
+	latestHourly, err := s.repoManager.Metric.GetLatestRollupBucket(ctx, models.MetricResolutionHourly)
+	if err != nil {
+		return err
+	}
+	if latestHourly != nil {
+		hourlySince = *latestHourly
+	}
+
+	hourlyUntil := now.Truncate(time.Hour)
+	if hourlySince.Before(hourlyUntil) {
+		if _, err := s.repoManager.Metric.RollupHourly(ctx, hourlySince, hourlyUntil); err != nil {
+			return err
+		}
+	}
+
+	var dailySince time.Time
+	latestDaily, err := s.repoManager.Metric.GetLatestRollupBucket(ctx, models.MetricResolutionDaily)
+	if err != nil {
+		return err
+	}
+	if latestDaily != nil {
+		dailySince = *latestDaily
+	}
+
+	dailyUntil := now.Truncate(24 * time.Hour)
+	if dailySince.Before(dailyUntil) {
+		if _, err := s.repoManager.Metric.RollupDaily(ctx, dailySince, dailyUntil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StartMetricsRollup periodically rolls up metrics until ctx is cancelled
+func (s *MetricsService) StartMetricsRollup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.RollupMetrics(ctx); err != nil {
+				log.Printf("Metrics rollup failed: %v", err)
+			}
+		}
+	}
+}
+
+// metricTrendThreshold is how far, as a fraction of the window average, the latest value must
+// move before a metric is considered trending up or down
+const metricTrendThreshold = 0.05
+
+// GetAggregatedMetrics summarizes each infrastructure resource's metrics over the given window
+func (s *MetricsService) GetAggregatedMetrics(ctx context.Context, orgID string, duration time.Duration) ([]MetricsAggregation, error) {
+	infrastructures, err := s.repoManager.Infrastructure.List(ctx, orgID, repositories.ListParams{
+		Limit:  1000,
+		Offset: 0,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get infrastructure: %w", err)
+	}
+
+	aggregations := []MetricsAggregation{}
+	for _, infra := range infrastructures {
+		metrics, err := s.GetResourceMetrics(ctx, infra.ID, duration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metrics for resource %s: %w", infra.ID, err)
+		}
+
+		aggregation := MetricsAggregation{
+			ResourceID:   infra.ID,
+			ResourceName: infra.Name,
+			ResourceType: infra.Type,
+			Provider:     infra.Provider,
+			Metrics:      aggregateMetricStats(metrics),
+			LastUpdated:  infra.UpdatedAt,
+			Status:       infra.Status,
+		}
+		for _, stats := range aggregation.Metrics {
+			if stats.LastUpdate.After(aggregation.LastUpdated) {
+				aggregation.LastUpdated = stats.LastUpdate
+			}
+		}
+
+		aggregations = append(aggregations, aggregation)
+	}
+
+	return aggregations, nil
+}
+
+// aggregateMetricStats computes statistics per metric name for a resource's data points
+func aggregateMetricStats(metrics []MetricData) map[string]MetricStats {
+	stats := make(map[string]MetricStats)
+	totals := make(map[string]float64)
+	counts := make(map[string]int)
+
+	for _, metric := range metrics {
+		current, exists := stats[metric.MetricName]
+		if !exists {
+			current = MetricStats{
+				Current:    metric.Value,
+				Min:        metric.Value,
+				Max:        metric.Value,
+				LastUpdate: metric.Timestamp,
+			}
+		}
+
+		if metric.Timestamp.After(current.LastUpdate) {
+			current.Current = metric.Value
+			current.LastUpdate = metric.Timestamp
+		}
+		if metric.Value < current.Min {
+			current.Min = metric.Value
+		}
+		if metric.Value > current.Max {
+			current.Max = metric.Value
+		}
+
+		totals[metric.MetricName] += metric.Value
+		counts[metric.MetricName]++
+		stats[metric.MetricName] = current
+	}
+
+	for name, current := range stats {
+		current.Average = totals[name] / float64(counts[name])
+		current.Trend = metricTrend(current.Current, current.Average)
+		stats[name] = current
+	}
+
+	return stats
+}
+
+// metricTrend compares the latest value of a metric to its window average
+func metricTrend(current, average float64) string {
+	threshold := math.Abs(average) * metricTrendThreshold
+	switch {
+	case current > average+threshold:
+		return "up"
+	case current < average-threshold:
+		return "down"
+	default:
+		return "stable"
+	}
 }
 
 // GetDashboardMetrics retrieves metrics for dashboard display
@@ -201,8 +569,14 @@ func (s *MetricsService) GetDashboardMetrics(ctx context.Context, orgID string)
 		RecentAlerts:       []AlertSummary{},
 	}
 
+	providers, err := s.awsAccounts.providersFor(ctx, orgID, s.providers)
+	if err != nil {
+		return nil, err
+	}
+
 	var totalCPU, totalMemory float64
 	var resourceCount int
+	batched := batchResourceMetrics(ctx, providers, infrastructures)
 
 	for _, infra := range infrastructures {
 		// Count resources by status
@@ -221,10 +595,15 @@ func (s *MetricsService) GetDashboardMetrics(ctx context.Context, orgID string)
 
 		// Get metrics for this resource
 		if infra.ExternalID != nil {
-			provider, exists := s.providers[infra.Provider]
+			provider, exists := providers[infra.Provider]
 			if exists {
-				metrics, err := provider.GetResourceMetrics(ctx, *infra.ExternalID)
-				if err == nil {
+				metrics, ok := batched[infra.ID]
+				if !ok {
+					metrics, err = providerValue(withResourceRegion(ctx, infra.Region), func(ctx context.Context) (map[string]interface{}, error) {
+						return provider.GetResourceMetrics(ctx, *infra.ExternalID)
+					})
+				}
+				if ok || err == nil {
 					// Calculate cost
 					if costInfo, ok := infra.CostInfo["monthly_cost"].(float64); ok {
 						dashboard.TotalCost += costInfo
@@ -309,7 +688,8 @@ func (s *MetricsService) GetMetricDefinitions(ctx context.Context, orgID string)
 }
 
 // Helper functions
-func (s *MetricsService) storeMetrics(ctx context.Context, resourceID, resourceType string, metrics map[string]interface{}) error {
+// storeMetrics saves a resource's collected metrics and publishes them to stream subscribers
+func (s *MetricsService) storeMetrics(ctx context.Context, orgID, resourceID, resourceType string, metrics map[string]interface{}) error {
 	timestamp := time.Now()
 
 	for metricName, value := range metrics {
@@ -350,44 +730,141 @@ func (s *MetricsService) storeMetrics(ctx context.Context, resourceID, resourceT
 		if err := s.repoManager.Metric.Create(ctx, metric); err != nil {
 			return fmt.Errorf("failed to store metric %s: %w", metricName, err)
 		}
+
+		s.publishMetric(orgID, MetricData{
+			ID:           metric.ID,
+			ResourceID:   resourceID,
+			ResourceType: resourceType,
+			MetricName:   metricName,
+			Value:        floatValue,
+			Unit:         unit,
+			Timestamp:    timestamp,
+			Tags:         make(map[string]string),
+			Metadata:     make(map[string]interface{}),
+		})
 	}
 
 	return nil
 }
 
+// checkMetricsAlerts evaluates the organization's alert rules against a resource's latest
+// metrics. A rule fires once it has been breached for its configured duration and does not fire
+// again until the metric recovers.
 func (s *MetricsService) checkMetricsAlerts(ctx context.Context, infra *models.Infrastructure, metrics map[string]interface{}) {
-	// Check CPU utilization
-	if cpu, ok := metrics["cpu_utilization"].(float64); ok {
-		if cpu > 90 {
-			s.alertService.CreateAlert(ctx, &models.Alert{
-				Type:       "high_cpu_usage",
-				Severity:   "warning",
-				Message:    fmt.Sprintf("High CPU usage detected: %.1f%%", cpu),
-				ResourceID: &infra.ID,
-			})
+	rules, err := s.repoManager.AlertRule.ListEnabledForResource(ctx, infra.OrganizationID, infra.Type)
+	if err != nil {
+		fmt.Printf("Failed to get alert rules for resource %s: %v\n", infra.ID, err)
+		return
+	}
+
+	now := time.Now()
+	for _, rule := range rules {
+		value, ok := metricValue(metrics[rule.MetricName])
+		if !ok {
+			continue
+		}
+
+		if err := s.evaluateAlertRule(ctx, rule, infra, value, now); err != nil {
+			fmt.Printf("Failed to evaluate alert rule %s for resource %s: %v\n", rule.ID, infra.ID, err)
 		}
 	}
 
-	// Check memory utilization
-	if mem, ok := metrics["memory_utilization"].(float64); ok {
-		if mem > 90 {
+	// Check for resource errors, skipping resources that already have an open error alert
+	if infra.Status == models.InfraStatusError {
+		alertType := "resource_error"
+		acknowledged := false
+		open, err := s.repoManager.Alert.Query(ctx, infra.OrganizationID, models.AlertQuery{
+			Type:         &alertType,
+			ResourceID:   &infra.ID,
+			Acknowledged: &acknowledged,
+			Limit:        1,
+		})
+		if err == nil && len(open) == 0 {
 			s.alertService.CreateAlert(ctx, &models.Alert{
-				Type:       "high_memory_usage",
-				Severity:   "warning",
-				Message:    fmt.Sprintf("High memory usage detected: %.1f%%", mem),
-				ResourceID: &infra.ID,
+				OrganizationID: infra.OrganizationID,
+				Type:           alertType,
+				Severity:       "critical",
+				Title:          "Resource error",
+				Message:        fmt.Sprintf("Resource %s is in error state", infra.Name),
+				ResourceID:     &infra.ID,
 			})
 		}
 	}
+}
 
-	// Check for resource errors
-	if infra.Status == models.InfraStatusError {
-		s.alertService.CreateAlert(ctx, &models.Alert{
-			Type:       "resource_error",
-			Severity:   "critical",
-			Message:    fmt.Sprintf("Resource %s is in error state", infra.Name),
-			ResourceID: &infra.ID,
-		})
+// evaluateAlertRule updates a rule's breach state for a resource and fires its alert when due
+func (s *MetricsService) evaluateAlertRule(ctx context.Context, rule *models.AlertRule, infra *models.Infrastructure, value float64, now time.Time) error {
+	state, err := s.repoManager.AlertRule.GetState(ctx, rule.ID, infra.ID)
+	if err != nil {
+		return err
+	}
+
+	if !alertRuleBreached(rule, value) {
+		if state != nil {
+			return s.repoManager.AlertRule.DeleteState(ctx, rule.ID, infra.ID)
+		}
+		return nil
+	}
+
+	if state == nil {
+		state = &models.AlertRuleState{
+			RuleID:        rule.ID,
+			ResourceID:    infra.ID,
+			BreachedSince: now,
+		}
+	}
+
+	// Only fire once per breach, and only after it has been sustained for the rule's duration
+	sustained := now.Sub(state.BreachedSince) >= time.Duration(rule.DurationSeconds)*time.Second
+	if state.AlertID == nil && sustained {
+		resourceType := infra.Type
+		alert := &models.Alert{
+			OrganizationID: infra.OrganizationID,
+			Type:           models.AlertTypePerformance,
+			Severity:       rule.Severity,
+			Title:          rule.Name,
+			Message:        fmt.Sprintf("%s on %s is %.2f (%s %.2f)", rule.MetricName, infra.Name, value, rule.Operator, rule.Threshold),
+			ResourceID:     &infra.ID,
+			ResourceType:   &resourceType,
+		}
+		if err := s.alertService.CreateAlert(ctx, alert); err != nil {
+			return err
+		}
+		state.AlertID = &alert.ID
+	}
+
+	return s.repoManager.AlertRule.SaveState(ctx, state)
+}
+
+// alertRuleBreached reports whether a metric value crosses a rule's threshold
+func alertRuleBreached(rule *models.AlertRule, value float64) bool {
+	switch rule.Operator {
+	case models.AlertOperatorGreaterThan:
+		return value > rule.Threshold
+	case models.AlertOperatorGreaterThanOrEqual:
+		return value >= rule.Threshold
+	case models.AlertOperatorLessThan:
+		return value < rule.Threshold
+	case models.AlertOperatorLessThanOrEqual:
+		return value <= rule.Threshold
+	case models.AlertOperatorEqual:
+		return value == rule.Threshold
+	default:
+		return false
+	}
+}
+
+// metricValue converts a numeric metric reported by a provider to float64
+func metricValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
 	}
 }
 
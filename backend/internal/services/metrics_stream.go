@@ -0,0 +1,107 @@
+package services
+
+import (
+	"sync"
+)
+
+// metricStreamBuffer is how many data points a slow subscriber can fall behind before points
+// are dropped
+const metricStreamBuffer = 256
+
+// MetricSubscription receives data points as they are collected for an organization, optionally
+// narrowed to one resource and a set of metric names
+type MetricSubscription struct {
+	OrganizationID string
+	ResourceID     string
+	MetricNames    map[string]bool
+	C              chan MetricData
+
+	// Done is closed when streams are closed for shutdown
+	Done <-chan struct{}
+}
+
+// metricBroker fans collected data points out to stream subscribers
+type metricBroker struct {
+	mu          sync.RWMutex
+	subscribers map[*MetricSubscription]struct{}
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newMetricBroker() *metricBroker {
+	return &metricBroker{
+		subscribers: make(map[*MetricSubscription]struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// matches reports whether a data point collected for the organization should be sent to the subscriber
+func (sub *MetricSubscription) matches(orgID string, metric MetricData) bool {
+	if sub.OrganizationID != orgID {
+		return false
+	}
+	if sub.ResourceID != "" && sub.ResourceID != metric.ResourceID {
+		return false
+	}
+	if len(sub.MetricNames) > 0 && !sub.MetricNames[metric.MetricName] {
+		return false
+	}
+	return true
+}
+
+// SubscribeMetrics registers a subscription for freshly collected data points. The returned
+// function must be called to release the subscription.
+func (s *MetricsService) SubscribeMetrics(orgID, resourceID string, metricNames []string) (*MetricSubscription, func()) {
+	sub := &MetricSubscription{
+		OrganizationID: orgID,
+		ResourceID:     resourceID,
+		MetricNames:    make(map[string]bool),
+		C:              make(chan MetricData, metricStreamBuffer),
+		Done:           s.broker.closed,
+	}
+	for _, name := range metricNames {
+		if name != "" {
+			sub.MetricNames[name] = true
+		}
+	}
+
+	s.broker.mu.Lock()
+	s.broker.subscribers[sub] = struct{}{}
+	s.broker.mu.Unlock()
+
+	unsubscribe := func() {
+		s.broker.mu.Lock()
+		delete(s.broker.subscribers, sub)
+		s.broker.mu.Unlock()
+	}
+
+	return sub, unsubscribe
+}
+
+// CloseStreams ends every metric stream, including any subscribed afterwards. Streams only end on
+// their own when the client disconnects, so the server calls this when it starts shutting down
+// rather than waiting the full shutdown timeout for them.
+func (s *MetricsService) CloseStreams() {
+	s.broker.closeOnce.Do(func() {
+		close(s.broker.closed)
+	})
+}
+
+// publishMetric sends a collected data point to matching subscribers without blocking collection
+func (s *MetricsService) publishMetric(orgID string, metric MetricData) {
+	s.broker.mu.RLock()
+	defer s.broker.mu.RUnlock()
+
+	for sub := range s.broker.subscribers {
+		if !sub.matches(orgID, metric) {
+			continue
+		}
+
+		select {
+		case sub.C <- metric:
+		default:
+			// Subscriber is not keeping up, drop the point
+		}
+	}
+}
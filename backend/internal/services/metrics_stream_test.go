@@ -0,0 +1,34 @@
+package services
+
+import "testing"
+
+func TestCloseStreamsEndsSubscriptions(t *testing.T) {
+	metricsService := &MetricsService{broker: newMetricBroker()}
+
+	sub, unsubscribe := metricsService.SubscribeMetrics("org-1", "", nil)
+	defer unsubscribe()
+
+	select {
+	case <-sub.Done:
+		t.Fatal("subscription ended before streams were closed")
+	default:
+	}
+
+	metricsService.CloseStreams()
+	metricsService.CloseStreams()
+
+	select {
+	case <-sub.Done:
+	default:
+		t.Error("subscription did not end when streams were closed")
+	}
+
+	late, unsubscribeLate := metricsService.SubscribeMetrics("org-1", "", nil)
+	defer unsubscribeLate()
+
+	select {
+	case <-late.Done:
+	default:
+		t.Error("subscription made after streams were closed did not end")
+	}
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloudweave/internal/config"
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+const (
+	// totpPeriod is the TOTP time step in seconds
+	totpPeriod = 30
+	// totpDigits is the number of digits in a TOTP code
+	totpDigits = 6
+	// totpSkew is the number of time steps either side of now that are accepted
+	totpSkew = 1
+	// mfaBackupCodeCount is the number of backup codes generated at enrollment
+	mfaBackupCodeCount = 10
+	// maxMFAFailedAttempts is how many invalid codes in a row lock MFA verification
+	maxMFAFailedAttempts = 5
+	// mfaLockoutDuration is how long MFA verification stays locked after too many invalid codes
+	mfaLockoutDuration = 15 * time.Minute
+)
+
+var (
+	// ErrInvalidMFACode is returned when a code is wrong or was already used
+	ErrInvalidMFACode = errors.New("invalid MFA code")
+
+	// ErrMFALocked is returned while MFA verification is locked after too many invalid codes
+	ErrMFALocked = errors.New("too many invalid MFA codes, try again later")
+)
+
+var base32NoPadding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+type MFAService struct {
+	config   *config.Config
+	userRepo *repositories.UserRepository
+}
+
+func NewMFAService(cfg *config.Config, userRepo *repositories.UserRepository) *MFAService {
+	return &MFAService{
+		config:   cfg,
+		userRepo: userRepo,
+	}
+}
+
+// IsEnabled reports whether the user has completed MFA enrollment
+func (s *MFAService) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	mfa, err := s.userRepo.GetMFA(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return mfa.Enabled, nil
+}
+
+// Enroll generates a new TOTP secret and backup codes for the user. MFA stays disabled until
+// the user confirms the secret with VerifyEnrollment.
+func (s *MFAService) Enroll(ctx context.Context, user *models.User) (*models.MFAEnrollResponse, error) {
+	mfa, err := s.userRepo.GetMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled {
+		return nil, fmt.Errorf("MFA is already enabled")
+	}
+
+	rawSecret := make([]byte, 20)
+	if _, err := rand.Read(rawSecret); err != nil {
+		return nil, fmt.Errorf("failed to generate MFA secret: %w", err)
+	}
+	secret := base32NoPadding.EncodeToString(rawSecret)
+
+	encryptedSecret, err := s.encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	backupCodes, hashedCodes, err := generateBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	mfa.Enabled = false
+	mfa.Secret = encryptedSecret
+	mfa.BackupCodes = hashedCodes
+	if err := s.userRepo.UpdateMFA(ctx, mfa); err != nil {
+		return nil, err
+	}
+
+	return &models.MFAEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: s.provisioningURI(user.Email, secret),
+		BackupCodes:     backupCodes,
+	}, nil
+}
+
+// VerifyEnrollment enables MFA once the user proves their authenticator produces valid codes
+func (s *MFAService) VerifyEnrollment(ctx context.Context, userID, code string) error {
+	mfa, err := s.userRepo.GetMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if mfa.Enabled {
+		return fmt.Errorf("MFA is already enabled")
+	}
+	if mfa.Secret == "" {
+		return fmt.Errorf("MFA enrollment has not been started")
+	}
+
+	secret, err := s.decrypt(mfa.Secret)
+	if err != nil {
+		return err
+	}
+	step, ok := validateTOTP(secret, code, time.Now())
+	if !ok {
+		return ErrInvalidMFACode
+	}
+	if _, err := s.userRepo.UseTOTPStep(ctx, userID, step); err != nil {
+		return err
+	}
+
+	mfa.Enabled = true
+	return s.userRepo.UpdateMFA(ctx, mfa)
+}
+
+// Disable turns MFA off after checking a current code or backup code
+func (s *MFAService) Disable(ctx context.Context, userID, code string) error {
+	if err := s.VerifyCode(ctx, userID, code); err != nil {
+		return err
+	}
+
+	return s.userRepo.UpdateMFA(ctx, &models.UserMFA{UserID: userID})
+}
+
+// VerifyCode checks a TOTP code, falling back to single-use backup codes. Each TOTP code is
+// accepted once, and too many invalid codes in a row lock verification for a while.
+func (s *MFAService) VerifyCode(ctx context.Context, userID, code string) error {
+	mfa, err := s.userRepo.GetMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !mfa.Enabled {
+		return fmt.Errorf("MFA is not enabled")
+	}
+	if mfa.LockedUntil != nil && mfa.LockedUntil.After(time.Now()) {
+		return ErrMFALocked
+	}
+
+	secret, err := s.decrypt(mfa.Secret)
+	if err != nil {
+		return err
+	}
+	if step, ok := validateTOTP(secret, code, time.Now()); ok {
+		fresh, err := s.userRepo.UseTOTPStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		// A code already accepted, or one older than it, is a replay
+		if !fresh {
+			return s.codeRejected(ctx, userID)
+		}
+		return s.codeAccepted(ctx, mfa)
+	}
+
+	// Backup codes are removed once used
+	hashed := hashBackupCode(code)
+	for i, stored := range mfa.BackupCodes {
+		if subtle.ConstantTimeCompare([]byte(stored), []byte(hashed)) == 1 {
+			mfa.BackupCodes = append(mfa.BackupCodes[:i], mfa.BackupCodes[i+1:]...)
+			if err := s.userRepo.UpdateMFA(ctx, mfa); err != nil {
+				return fmt.Errorf("failed to consume backup code: %w", err)
+			}
+			return s.codeAccepted(ctx, mfa)
+		}
+	}
+
+	return s.codeRejected(ctx, userID)
+}
+
+// codeAccepted clears the user's invalid code count once a code is accepted
+func (s *MFAService) codeAccepted(ctx context.Context, mfa *models.UserMFA) error {
+	if mfa.FailedAttempts == 0 {
+		return nil
+	}
+	return s.userRepo.ResetMFAFailures(ctx, mfa.UserID)
+}
+
+// codeRejected counts an invalid code, returning ErrMFALocked once it locks verification
+func (s *MFAService) codeRejected(ctx context.Context, userID string) error {
+	locked, err := s.userRepo.RecordMFAFailure(ctx, userID, maxMFAFailedAttempts, mfaLockoutDuration)
+	if err != nil {
+		return err
+	}
+	if locked {
+		return ErrMFALocked
+	}
+	return ErrInvalidMFACode
+}
+
+// provisioningURI builds the otpauth:// URI rendered as a QR code by authenticator apps
+func (s *MFAService) provisioningURI(email, secret string) string {
+	issuer := s.config.MFAIssuer
+	params := url.Values{}
+	params.Set("secret", secret)
+	params.Set("issuer", issuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprintf("%d", totpDigits))
+	params.Set("period", fmt.Sprintf("%d", totpPeriod))
+
+	label := url.PathEscape(issuer + ":" + email)
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// encrypt seals the TOTP secret with AES-GCM
+func (s *MFAService) encrypt(plaintext string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// decrypt opens a TOTP secret sealed by encrypt
+func (s *MFAService) decrypt(ciphertext string) (string, error) {
+	gcm, err := s.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode MFA secret: %w", err)
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("invalid MFA secret")
+	}
+
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt MFA secret: %w", err)
+	}
+
+	return string(plaintext), nil
+}
+
+// cipher derives the AES-256 key from the configured MFA encryption key
+func (s *MFAService) cipher() (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte(s.config.MFAEncryptionKey))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// validateTOTP checks a code against the secret, allowing for clock skew, and returns the time
+// step the code belongs to
+func validateTOTP(secret, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+
+	key, err := base32NoPadding.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return 0, false
+	}
+
+	counter := now.Unix() / totpPeriod
+	for offset := int64(-totpSkew); offset <= totpSkew; offset++ {
+		expected := generateTOTP(key, uint64(counter+offset))
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return counter + offset, true
+		}
+	}
+
+	return 0, false
+}
+
+// generateTOTP computes the RFC 6238 code for a time step
+func generateTOTP(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < totpDigits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", totpDigits, value%mod)
+}
+
+// generateBackupCodes returns plain backup codes for the user along with the hashes to store
+func generateBackupCodes() ([]string, []string, error) {
+	codes := make([]string, 0, mfaBackupCodeCount)
+	hashed := make([]string, 0, mfaBackupCodeCount)
+
+	for i := 0; i < mfaBackupCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate backup code: %w", err)
+		}
+		raw := strings.ToLower(base32NoPadding.EncodeToString(b))
+		code := raw[:4] + "-" + raw[4:]
+
+		codes = append(codes, code)
+		hashed = append(hashed, hashBackupCode(code))
+	}
+
+	return codes, hashed, nil
+}
+
+// hashBackupCode normalizes and hashes a backup code for storage
+func hashBackupCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
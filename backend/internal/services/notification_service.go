@@ -0,0 +1,456 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Notification channel errors
+var (
+	ErrInvalidNotificationChannel  = errors.New("invalid notification channel")
+	ErrNotificationChannelNotFound = errors.New("notification channel not found")
+)
+
+// Notification delivery retry defaults
+const (
+	DefaultNotificationMaxAttempts    = 5
+	DefaultNotificationInitialBackoff = 2 * time.Second
+	maxNotificationBackoff            = 5 * time.Minute
+)
+
+// SMTPConfig holds the outgoing mail server used by email notification channels
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NotificationService delivers alerts to an organization's notification channels and records
+// the outcome of every delivery
+type NotificationService struct {
+	repo       repositories.NotificationChannelRepositoryInterface
+	smtp       SMTPConfig
+	httpClient *http.Client
+
+	maxAttempts    int
+	initialBackoff time.Duration
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(repo repositories.NotificationChannelRepositoryInterface, smtpConfig SMTPConfig) *NotificationService {
+	return &NotificationService{
+		repo:           repo,
+		smtp:           smtpConfig,
+		httpClient:     newOutboundHTTPClient(10 * time.Second),
+		maxAttempts:    DefaultNotificationMaxAttempts,
+		initialBackoff: DefaultNotificationInitialBackoff,
+	}
+}
+
+// CreateChannel validates and stores a new notification channel
+func (s *NotificationService) CreateChannel(ctx context.Context, channel *models.NotificationChannel) error {
+	if err := validateNotificationChannel(channel); err != nil {
+		return err
+	}
+
+	if channel.ID == "" {
+		channel.ID = uuid.New().String()
+	}
+
+	return s.repo.Create(ctx, channel)
+}
+
+// GetChannels retrieves an organization's notification channels
+func (s *NotificationService) GetChannels(ctx context.Context, orgID string) ([]*models.NotificationChannel, error) {
+	return s.repo.List(ctx, orgID)
+}
+
+// UpdateChannel applies a partial update to a notification channel
+func (s *NotificationService) UpdateChannel(ctx context.Context, orgID, channelID string, req *models.UpdateNotificationChannelRequest) (*models.NotificationChannel, error) {
+	channel, err := s.repo.GetByID(ctx, channelID, orgID)
+	if err != nil {
+		return nil, ErrNotificationChannelNotFound
+	}
+
+	if req.Name != nil {
+		channel.Name = *req.Name
+	}
+	if req.Config != nil {
+		channel.Config = *req.Config
+	}
+	if req.Severities != nil {
+		channel.Severities = *req.Severities
+	}
+	if req.Enabled != nil {
+		channel.Enabled = *req.Enabled
+	}
+
+	if err := validateNotificationChannel(channel); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, channel); err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// DeleteChannel deletes a notification channel
+func (s *NotificationService) DeleteChannel(ctx context.Context, orgID, channelID string) error {
+	if err := s.repo.Delete(ctx, channelID, orgID); err != nil {
+		return ErrNotificationChannelNotFound
+	}
+	return nil
+}
+
+// GetDeliveries retrieves an organization's recent notification deliveries, optionally for one alert
+func (s *NotificationService) GetDeliveries(ctx context.Context, orgID, alertID string, limit int) ([]*models.NotificationDelivery, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.ListDeliveries(ctx, orgID, alertID, limit)
+}
+
+// NotifyAlert delivers an alert to every enabled channel of its organization that accepts the
+// alert's severity. Channels are delivered to concurrently and retried with exponential backoff.
+func (s *NotificationService) NotifyAlert(ctx context.Context, alert *models.Alert) {
+	channels, err := s.repo.ListEnabled(ctx, alert.OrganizationID)
+	if err != nil {
+		log.Printf("Failed to list notification channels for alert %s: %v", alert.ID, err)
+		return
+	}
+
+	for _, channel := range channels {
+		if !channel.AcceptsSeverity(alert.Severity) {
+			continue
+		}
+
+		delivery := &models.NotificationDelivery{
+			ID:             uuid.New().String(),
+			OrganizationID: alert.OrganizationID,
+			ChannelID:      channel.ID,
+			AlertID:        alert.ID,
+			Status:         models.NotificationDeliveryPending,
+		}
+		if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+			log.Printf("Failed to record notification delivery for alert %s: %v", alert.ID, err)
+			continue
+		}
+
+		go s.deliver(ctx, channel, alert, delivery)
+	}
+}
+
+// deliver sends an alert to a channel, retrying failures with exponential backoff and recording
+// the outcome of each attempt
+func (s *NotificationService) deliver(ctx context.Context, channel *models.NotificationChannel, alert *models.Alert, delivery *models.NotificationDelivery) {
+	backoff := s.initialBackoff
+
+	for {
+		delivery.Attempts++
+		err := s.send(ctx, channel, alert)
+
+		if err == nil {
+			now := time.Now()
+			delivery.Status = models.NotificationDeliverySent
+			delivery.SentAt = &now
+			delivery.LastError = nil
+		} else {
+			message := err.Error()
+			delivery.LastError = &message
+			if delivery.Attempts >= s.maxAttempts {
+				delivery.Status = models.NotificationDeliveryFailed
+				log.Printf("Giving up delivering alert %s to channel %s after %d attempts: %v", alert.ID, channel.ID, delivery.Attempts, err)
+			}
+		}
+
+		if updateErr := s.repo.UpdateDelivery(ctx, delivery); updateErr != nil {
+			log.Printf("Failed to update notification delivery %s: %v", delivery.ID, updateErr)
+		}
+
+		if delivery.Status != models.NotificationDeliveryPending {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxNotificationBackoff {
+			backoff = maxNotificationBackoff
+		}
+	}
+}
+
+// send makes a single delivery attempt
+func (s *NotificationService) send(ctx context.Context, channel *models.NotificationChannel, alert *models.Alert) error {
+	switch channel.Type {
+	case models.NotificationChannelEmail:
+		return s.sendEmail(channel, alert)
+	case models.NotificationChannelWebhook:
+		return s.sendWebhook(ctx, channel, alert)
+	default:
+		return fmt.Errorf("unsupported notification channel type %s", channel.Type)
+	}
+}
+
+// sendEmail sends an alert through the configured SMTP server
+func (s *NotificationService) sendEmail(channel *models.NotificationChannel, alert *models.Alert) error {
+	subject := fmt.Sprintf("[%s] %s", strings.ToUpper(alert.Severity), alertTitle(alert))
+
+	var body bytes.Buffer
+	fmt.Fprintf(&body, "%s\r\n\r\n", alert.Message)
+	fmt.Fprintf(&body, "Severity: %s\r\nType: %s\r\n", alert.Severity, alert.Type)
+	if alert.ResourceID != nil {
+		fmt.Fprintf(&body, "Resource: %s\r\n", *alert.ResourceID)
+	}
+	fmt.Fprintf(&body, "Alert ID: %s\r\nRaised at: %s\r\n", alert.ID, alert.CreatedAt.UTC().Format(time.RFC3339))
+
+	return s.SendEmail(channel.Config.Recipients, subject, body.String())
+}
+
+// SendEmail sends a plain text email through the configured SMTP server
+func (s *NotificationService) SendEmail(recipients []string, subject, body string) error {
+	var msg bytes.Buffer
+	s.writeEmailHeaders(&msg, recipients, subject)
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(body)
+
+	return s.sendMail(recipients, msg.Bytes())
+}
+
+// EmailAttachment is a file attached to an email
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// SendEmailWithAttachments sends a plain text email with files attached through the configured
+// SMTP server
+func (s *NotificationService) SendEmailWithAttachments(recipients []string, subject, body string, attachments ...EmailAttachment) error {
+	var msg bytes.Buffer
+	s.writeEmailHeaders(&msg, recipients, subject)
+
+	writer := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", writer.Boundary())
+
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"text/plain; charset=UTF-8"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+	if _, err := io.WriteString(part, body); err != nil {
+		return fmt.Errorf("failed to write email body: %w", err)
+	}
+
+	for _, attachment := range attachments {
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to attach %s: %w", attachment.Filename, err)
+		}
+		if err := writeBase64Lines(part, attachment.Content); err != nil {
+			return fmt.Errorf("failed to attach %s: %w", attachment.Filename, err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to finish email: %w", err)
+	}
+
+	return s.sendMail(recipients, msg.Bytes())
+}
+
+// writeEmailHeaders writes the headers every email sent by the service starts with
+func (s *NotificationService) writeEmailHeaders(msg *bytes.Buffer, recipients []string, subject string) {
+	fmt.Fprintf(msg, "From: %s\r\n", s.smtp.From)
+	fmt.Fprintf(msg, "To: %s\r\n", strings.Join(recipients, ", "))
+	fmt.Fprintf(msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+}
+
+// sendMail delivers a complete message through the configured SMTP server
+func (s *NotificationService) sendMail(recipients []string, msg []byte) error {
+	if s.smtp.Host == "" {
+		return fmt.Errorf("SMTP is not configured")
+	}
+
+	var auth smtp.Auth
+	if s.smtp.Username != "" {
+		auth = smtp.PlainAuth("", s.smtp.Username, s.smtp.Password, s.smtp.Host)
+	}
+
+	addr := net.JoinHostPort(s.smtp.Host, s.smtp.Port)
+	if err := smtp.SendMail(addr, auth, s.smtp.From, recipients, msg); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// writeBase64Lines writes content base64 encoded in lines of 76 characters, the most MIME allows
+func writeBase64Lines(w io.Writer, content []byte) error {
+	const lineLength = 76
+
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 0 {
+		n := min(lineLength, len(encoded))
+		if _, err := io.WriteString(w, encoded[:n]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+// webhookPayload is posted to webhook channels. The text and attachments fields follow Slack's
+// incoming webhook format; other receivers can read the full alert.
+type webhookPayload struct {
+	Text        string              `json:"text"`
+	Attachments []webhookAttachment `json:"attachments"`
+	Alert       *models.Alert       `json:"alert"`
+}
+
+type webhookAttachment struct {
+	Color  string         `json:"color"`
+	Title  string         `json:"title"`
+	Text   string         `json:"text"`
+	Fields []webhookField `json:"fields"`
+	Ts     int64          `json:"ts"`
+}
+
+type webhookField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// sendWebhook posts an alert to a webhook channel
+func (s *NotificationService) sendWebhook(ctx context.Context, channel *models.NotificationChannel, alert *models.Alert) error {
+	fields := []webhookField{
+		{Title: "Severity", Value: alert.Severity, Short: true},
+		{Title: "Type", Value: alert.Type, Short: true},
+	}
+	if alert.ResourceID != nil {
+		fields = append(fields, webhookField{Title: "Resource", Value: *alert.ResourceID, Short: true})
+	}
+
+	payload := webhookPayload{
+		Text: fmt.Sprintf("[%s] %s", strings.ToUpper(alert.Severity), alertTitle(alert)),
+		Attachments: []webhookAttachment{{
+			Color:  alertColor(alert.Severity),
+			Title:  alertTitle(alert),
+			Text:   alert.Message,
+			Fields: fields,
+			Ts:     alert.CreatedAt.Unix(),
+		}},
+		Alert: alert,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.Config.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// validateNotificationChannel checks a channel's type-specific configuration
+func validateNotificationChannel(channel *models.NotificationChannel) error {
+	if strings.TrimSpace(channel.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidNotificationChannel)
+	}
+
+	switch channel.Type {
+	case models.NotificationChannelEmail:
+		if len(channel.Config.Recipients) == 0 {
+			return fmt.Errorf("%w: email channels need at least one recipient", ErrInvalidNotificationChannel)
+		}
+		for _, recipient := range channel.Config.Recipients {
+			// A bare address only; display names and lists would let one recipient expand to many
+			address, err := mail.ParseAddress(recipient)
+			if err != nil || address.Name != "" || address.Address != strings.TrimSpace(recipient) {
+				return fmt.Errorf("%w: invalid recipient %q", ErrInvalidNotificationChannel, recipient)
+			}
+		}
+	case models.NotificationChannelWebhook:
+		u, err := url.Parse(channel.Config.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: webhook channels need an http or https URL", ErrInvalidNotificationChannel)
+		}
+		if err := checkURLHost(u.Hostname()); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidNotificationChannel, err)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported type %q", ErrInvalidNotificationChannel, channel.Type)
+	}
+
+	return nil
+}
+
+func alertTitle(alert *models.Alert) string {
+	if alert.Title != "" {
+		return alert.Title
+	}
+	return alert.Message
+}
+
+// alertColor maps an alert severity to a Slack attachment color
+func alertColor(severity string) string {
+	switch severity {
+	case models.AlertSeverityCritical, models.AlertSeverityError:
+		return "danger"
+	case models.AlertSeverityWarning:
+		return "warning"
+	default:
+		return "good"
+	}
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"cloudweave/internal/models"
+)
+
+func TestValidateNotificationChannel(t *testing.T) {
+	tests := []struct {
+		name   string
+		config models.NotificationChannelConfig
+		kind   string
+		valid  bool
+	}{
+		{"email", models.NotificationChannelConfig{Recipients: []string{"ops@example.com"}}, models.NotificationChannelEmail, true},
+		{"email without domain", models.NotificationChannelConfig{Recipients: []string{"ops@"}}, models.NotificationChannelEmail, false},
+		{"email with display name", models.NotificationChannelConfig{Recipients: []string{"Ops <ops@example.com>"}}, models.NotificationChannelEmail, false},
+		{"email list in one recipient", models.NotificationChannelConfig{Recipients: []string{"a@example.com, b@example.com"}}, models.NotificationChannelEmail, false},
+		{"email header injection", models.NotificationChannelConfig{Recipients: []string{"ops@example.com\r\nBcc: x@example.com"}}, models.NotificationChannelEmail, false},
+		{"webhook", models.NotificationChannelConfig{URL: "https://hooks.example.com/alerts"}, models.NotificationChannelWebhook, true},
+		{"webhook to metadata service", models.NotificationChannelConfig{URL: "http://169.254.169.254/latest/meta-data"}, models.NotificationChannelWebhook, false},
+		{"webhook to loopback", models.NotificationChannelConfig{URL: "http://127.0.0.1:8080/"}, models.NotificationChannelWebhook, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNotificationChannel(&models.NotificationChannel{Name: "alerts", Type: tt.kind, Config: tt.config})
+			if tt.valid && err != nil {
+				t.Errorf("validateNotificationChannel returned %v, want nil", err)
+			}
+			if !tt.valid && !errors.Is(err, ErrInvalidNotificationChannel) {
+				t.Errorf("validateNotificationChannel returned %v, want ErrInvalidNotificationChannel", err)
+			}
+		})
+	}
+}
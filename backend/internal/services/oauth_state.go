@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"time"
+)
+
+// OAuthStateTTL is how long a generated OAuth state remains valid
+const OAuthStateTTL = 10 * time.Minute
+
+type OAuthStateService struct {
+	db *sql.DB
+}
+
+type OAuthState struct {
+	State          string    `json:"state" db:"state"`
+	Provider       string    `json:"provider" db:"provider"`
+	OrganizationID string    `json:"organizationId" db:"organization_id"`
+	ExpiresAt      time.Time `json:"expiresAt" db:"expires_at"`
+}
+
+func NewOAuthStateService(db *sql.DB) *OAuthStateService {
+	return &OAuthStateService{db: db}
+}
+
+// CreateState generates and stores a new OAuth state for the provider and organization
+func (s *OAuthStateService) CreateState(ctx context.Context, provider, organizationID string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate OAuth state: %w", err)
+	}
+	state := base64.RawURLEncoding.EncodeToString(b)
+
+	var orgID interface{}
+	if organizationID != "" {
+		orgID = organizationID
+	}
+
+	query := `
+		INSERT INTO oauth_states (state, provider, organization_id, expires_at)
+		VALUES ($1, $2, $3, $4)`
+
+	_, err := s.db.ExecContext(ctx, query, state, provider, orgID, time.Now().Add(OAuthStateTTL))
+	if err != nil {
+		return "", fmt.Errorf("failed to store OAuth state: %w", err)
+	}
+
+	return state, nil
+}
+
+// ConsumeState removes a stored state and returns it if it was issued for the provider and has not expired.
+// A state can only be consumed once. browserState is the state kept in the browser that started the
+// login, so a login started by someone else cannot be completed in this browser.
+func (s *OAuthStateService) ConsumeState(ctx context.Context, state, browserState, provider string) (*OAuthState, error) {
+	if state == "" {
+		return nil, fmt.Errorf("OAuth state is required")
+	}
+	if subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1 {
+		return nil, fmt.Errorf("OAuth state was not issued to this browser")
+	}
+
+	query := `
+		DELETE FROM oauth_states
+		WHERE state = $1
+		RETURNING state, provider, organization_id, expires_at`
+
+	var stored OAuthState
+	var orgID sql.NullString
+	err := s.db.QueryRowContext(ctx, query, state).Scan(&stored.State, &stored.Provider, &orgID, &stored.ExpiresAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("invalid OAuth state")
+		}
+		return nil, fmt.Errorf("failed to consume OAuth state: %w", err)
+	}
+	stored.OrganizationID = orgID.String
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, fmt.Errorf("OAuth state has expired")
+	}
+
+	if stored.Provider != provider {
+		return nil, fmt.Errorf("OAuth state was issued for a different provider")
+	}
+
+	return &stored, nil
+}
+
+// CleanupExpiredStates removes expired OAuth states and returns how many were removed
+func (s *OAuthStateService) CleanupExpiredStates(ctx context.Context) (int64, error) {
+	query := `DELETE FROM oauth_states WHERE expires_at < NOW()`
+
+	result, err := s.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup expired OAuth states: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// StartExpiryCleanup removes expired OAuth states every interval until the context is cancelled,
+// so logins that are started but never completed do not accumulate
+func (s *OAuthStateService) StartExpiryCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.CleanupExpiredStates(ctx)
+			if err != nil {
+				log.Printf("OAuth state cleanup failed: %v", err)
+				continue
+			}
+			if count > 0 {
+				log.Printf("Removed %d expired OAuth states", count)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"cloudweave/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrNotOrganizationMember is returned when a user switches to an organization they do not belong to
+var ErrNotOrganizationMember = errors.New("user is not a member of the organization")
+
+// ListOrganizations retrieves the organizations a user belongs to
+func (s *AuthService) ListOrganizations(ctx context.Context, userID string) ([]*models.OrganizationMembership, error) {
+	return s.orgRepo.ListUserMemberships(ctx, userID)
+}
+
+// SwitchOrganization issues tokens scoped to one of the user's organizations, carrying the user's
+// role in it. Refreshing them keeps that scope.
+func (s *AuthService) SwitchOrganization(ctx context.Context, userID, organizationID string) (*models.SwitchOrganizationResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	membership, err := s.getMembership(ctx, userID, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	user.OrganizationID = membership.OrganizationID
+	user.Role = membership.Role
+
+	accessToken, err := s.jwtService.GenerateAccessToken(*user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, err := s.jwtService.GenerateOrganizationRefreshToken(user.ID, membership.OrganizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	return &models.SwitchOrganizationResponse{
+		Success:      true,
+		Organization: *membership,
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// scopeToOrganization points a user at the organization a refresh token was scoped to. A user
+// who has since left that organization is scoped to their home organization again.
+func (s *AuthService) scopeToOrganization(ctx context.Context, user *models.User, organizationID string) {
+	if organizationID == "" || organizationID == user.OrganizationID {
+		return
+	}
+
+	membership, err := s.getMembership(ctx, user.ID, organizationID)
+	if err != nil {
+		return
+	}
+	user.OrganizationID = membership.OrganizationID
+	user.Role = membership.Role
+}
+
+func (s *AuthService) getMembership(ctx context.Context, userID, organizationID string) (*models.OrganizationMembership, error) {
+	if _, err := uuid.Parse(organizationID); err != nil {
+		return nil, ErrNotOrganizationMember
+	}
+
+	membership, err := s.orgRepo.GetUserMembership(ctx, userID, organizationID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotOrganizationMember
+		}
+		return nil, err
+	}
+
+	return membership, nil
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrInternalDestination is returned when a request to a user-supplied URL would reach a private,
+// loopback, link-local or otherwise internal address
+var ErrInternalDestination = errors.New("destination address is not allowed")
+
+// internalNetworks are address ranges that are internal but not covered by the net.IP predicates
+var internalNetworks = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "this" network
+	mustParseCIDR("100.64.0.0/10"), // carrier-grade NAT, used by some cloud metadata services
+	mustParseCIDR("192.0.0.0/24"),  // IETF protocol assignments
+	mustParseCIDR("198.18.0.0/15"), // benchmarking
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
+
+// isInternalIP reports whether ip is an address user-supplied URLs must not reach, including
+// RFC 1918 and unique local ranges, loopback, and link-local addresses such as the cloud
+// metadata endpoint 169.254.169.254
+func isInternalIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+
+	for _, network := range internalNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// rejectInternalAddress is a net.Dialer Control function that refuses connections to internal
+// addresses. It runs on the resolved address, so hostnames that resolve or rebind to an internal
+// IP are refused too.
+func rejectInternalAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInternalDestination, address)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || isInternalIP(ip) {
+		return fmt.Errorf("%w: %s", ErrInternalDestination, host)
+	}
+	return nil
+}
+
+// newOutboundHTTPClient returns a client for requests to URLs supplied by users, such as webhook
+// and notification endpoints. It never connects to internal addresses and does not follow
+// redirects, so the response to a redirect is returned as is.
+func newOutboundHTTPClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: rejectInternalAddress,
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	// A proxy would make the connection on our behalf, bypassing the address check
+	transport.Proxy = nil
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// checkURLHost rejects URLs whose host is a literal internal IP address. Hostnames are checked
+// when they are dialed.
+func checkURLHost(host string) error {
+	if ip := net.ParseIP(host); ip != nil && isInternalIP(ip) {
+		return fmt.Errorf("%w: %s", ErrInternalDestination, host)
+	}
+	return nil
+}
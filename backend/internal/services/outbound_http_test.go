@@ -0,0 +1,62 @@
+package services
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsInternalIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.100.100.200", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"fd00:ec2::254", true},
+		{"fe80::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"93.184.216.34", false},
+		{"2606:2800:220:1::1", false},
+	}
+
+	for _, tt := range tests {
+		if got := isInternalIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("isInternalIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestOutboundHTTPClientRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := newOutboundHTTPClient(time.Second)
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrInternalDestination) {
+		t.Fatalf("request to %s returned %v, want ErrInternalDestination", server.URL, err)
+	}
+}
+
+func TestOutboundHTTPClientDoesNotFollowRedirects(t *testing.T) {
+	client := newOutboundHTTPClient(time.Second)
+	if client.CheckRedirect == nil {
+		t.Fatal("client follows redirects")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/hook", nil)
+	if err := client.CheckRedirect(req, []*http.Request{req}); !errors.Is(err, http.ErrUseLastResponse) {
+		t.Errorf("CheckRedirect returned %v, want http.ErrUseLastResponse", err)
+	}
+}
@@ -1,7 +1,12 @@
 package services
 
 import (
+	"context"
 	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"cloudweave/internal/config"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -12,13 +17,26 @@ const (
 )
 
 type PasswordService struct {
-	cost int
+	cost     int
+	policy   PasswordPolicy
+	breaches *breachedPasswordChecker
 }
 
-func NewPasswordService() *PasswordService {
-	return &PasswordService{
+func NewPasswordService(cfg *config.Config) *PasswordService {
+	p := &PasswordService{
 		cost: DefaultCost,
-	}
+		policy: PasswordPolicy{
+			MinLength:        cfg.PasswordMinLength,
+			RequireUppercase: cfg.PasswordRequireUppercase,
+			RequireLowercase: cfg.PasswordRequireLowercase,
+			RequireDigit:     cfg.PasswordRequireDigit,
+			RequireSpecial:   cfg.PasswordRequireSpecial,
+		},
+	}
+	if cfg.PasswordBreachCheck {
+		p.breaches = newBreachedPasswordChecker(cfg.PasswordBreachAPIURL, cfg.PasswordBreachListFile)
+	}
+	return p
 }
 
 // HashPassword hashes a plain text password using bcrypt
@@ -56,69 +74,55 @@ func (p *PasswordService) VerifyPassword(hashedPassword, password string) error
 	return nil
 }
 
-// IsValidPassword checks if a password meets security requirements
-func (p *PasswordService) IsValidPassword(password string) error {
-	if len(password) < 8 {
-		return fmt.Errorf("password must be at least 8 characters long")
+// ValidatePassword checks a password against the password policy, reporting every rule it fails
+// in a *PasswordPolicyError
+func (p *PasswordService) ValidatePassword(ctx context.Context, password string) error {
+	var violations []PasswordPolicyViolation
+	violate := func(rule, message string) {
+		violations = append(violations, PasswordPolicyViolation{Rule: rule, Message: message})
 	}
 
-	if len(password) > 128 {
-		return fmt.Errorf("password must be less than 128 characters long")
+	length := utf8.RuneCountInString(password)
+	if length < p.policy.MinLength {
+		violate(PasswordRuleMinLength, fmt.Sprintf("password must be at least %d characters long", p.policy.MinLength))
+	}
+	if length > maxPasswordLength {
+		violate(PasswordRuleMaxLength, fmt.Sprintf("password must be less than %d characters long", maxPasswordLength))
 	}
 
-	// Check for at least one uppercase letter
-	hasUpper := false
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
 	for _, char := range password {
-		if char >= 'A' && char <= 'Z' {
+		switch {
+		case char >= 'A' && char <= 'Z':
 			hasUpper = true
-			break
+		case char >= 'a' && char <= 'z':
+			hasLower = true
+		case char >= '0' && char <= '9':
+			hasDigit = true
+		case strings.ContainsRune(passwordSpecialChars, char):
+			hasSpecial = true
 		}
 	}
-	if !hasUpper {
-		return fmt.Errorf("password must contain at least one uppercase letter")
-	}
 
-	// Check for at least one lowercase letter
-	hasLower := false
-	for _, char := range password {
-		if char >= 'a' && char <= 'z' {
-			hasLower = true
-			break
-		}
+	if p.policy.RequireUppercase && !hasUpper {
+		violate(PasswordRuleUppercase, "password must contain at least one uppercase letter")
 	}
-	if !hasLower {
-		return fmt.Errorf("password must contain at least one lowercase letter")
+	if p.policy.RequireLowercase && !hasLower {
+		violate(PasswordRuleLowercase, "password must contain at least one lowercase letter")
 	}
-
-	// Check for at least one digit
-	hasDigit := false
-	for _, char := range password {
-		if char >= '0' && char <= '9' {
-			hasDigit = true
-			break
-		}
+	if p.policy.RequireDigit && !hasDigit {
+		violate(PasswordRuleDigit, "password must contain at least one digit")
 	}
-	if !hasDigit {
-		return fmt.Errorf("password must contain at least one digit")
+	if p.policy.RequireSpecial && !hasSpecial {
+		violate(PasswordRuleSpecial, "password must contain at least one special character ("+passwordSpecialChars+")")
 	}
 
-	// Check for at least one special character
-	hasSpecial := false
-	specialChars := "!@#$%^&*()_+-=[]{}|;:,.<>?"
-	for _, char := range password {
-		for _, special := range specialChars {
-			if char == special {
-				hasSpecial = true
-				break
-			}
-		}
-		if hasSpecial {
-			break
-		}
-	}
-	if !hasSpecial {
-		return fmt.Errorf("password must contain at least one special character (!@#$%%^&*()_+-=[]{}|;:,.<>?)")
+	if p.breaches != nil && p.breaches.isBreached(ctx, password) {
+		violate(PasswordRuleBreached, "password has appeared in a data breach, please choose a different one")
 	}
 
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
 	return nil
 }
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPasswordLength bounds passwords regardless of policy
+	maxPasswordLength = 128
+
+	passwordSpecialChars = "!@#$%^&*()_+-=[]{}|;:,.<>?"
+)
+
+// Password policy rules reported in PasswordPolicyViolation.Rule
+const (
+	PasswordRuleMinLength = "min_length"
+	PasswordRuleMaxLength = "max_length"
+	PasswordRuleUppercase = "uppercase"
+	PasswordRuleLowercase = "lowercase"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSpecial   = "special"
+	PasswordRuleBreached  = "breached"
+)
+
+// PasswordPolicy is the set of requirements new passwords must meet
+type PasswordPolicy struct {
+	MinLength        int  `json:"minLength"`
+	RequireUppercase bool `json:"requireUppercase"`
+	RequireLowercase bool `json:"requireLowercase"`
+	RequireDigit     bool `json:"requireDigit"`
+	RequireSpecial   bool `json:"requireSpecial"`
+}
+
+// PasswordPolicyViolation is a password policy rule a password does not meet
+type PasswordPolicyViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// PasswordPolicyError lists every password policy rule a password does not meet
+type PasswordPolicyError struct {
+	Violations []PasswordPolicyViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// commonPasswords are well-known breached passwords that satisfy the default character rules.
+// They are rejected even when the breach API cannot be reached.
+var commonPasswords = []string{
+	"password1!", "password123!", "password@123", "p@ssw0rd", "p@ssw0rd1", "p@ssword1", "passw0rd!",
+	"welcome1!", "welcome@123", "qwerty123!", "qwerty@123", "admin@123", "admin123!", "abc@1234",
+	"changeme1!", "letmein1!", "iloveyou1!", "test@123", "summer2024!", "winter2024!",
+}
+
+// breachedPasswordChecker looks passwords up in the HaveIBeenPwned range API, which is sent only
+// the first five characters of the password's SHA-1 hash. When the API cannot be reached it falls
+// back to a local list of breached passwords.
+type breachedPasswordChecker struct {
+	apiURL     string
+	httpClient *http.Client
+	local      map[string]struct{}
+}
+
+func newBreachedPasswordChecker(apiURL, listFile string) *breachedPasswordChecker {
+	c := &breachedPasswordChecker{
+		apiURL:     apiURL,
+		httpClient: &http.Client{Timeout: 3 * time.Second},
+		local:      make(map[string]struct{}, len(commonPasswords)),
+	}
+	for _, password := range commonPasswords {
+		c.local[password] = struct{}{}
+	}
+
+	if listFile != "" {
+		if err := c.loadList(listFile); err != nil {
+			log.Printf("Failed to load breached password list %s: %v", listFile, err)
+		}
+	}
+
+	return c
+}
+
+// loadList adds the passwords in a file, one per line, to the local list
+func (c *breachedPasswordChecker) loadList(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if password := strings.TrimSpace(scanner.Text()); password != "" {
+			c.local[strings.ToLower(password)] = struct{}{}
+		}
+	}
+	return scanner.Err()
+}
+
+func (c *breachedPasswordChecker) isBreached(ctx context.Context, password string) bool {
+	if c.apiURL != "" {
+		breached, err := c.lookup(ctx, password)
+		if err == nil {
+			return breached
+		}
+		log.Printf("Breached password lookup failed, using local list: %v", err)
+	}
+
+	_, breached := c.local[strings.ToLower(password)]
+	return breached
+}
+
+// lookup queries the range API for the hash suffixes sharing the password's hash prefix
+func (c *breachedPasswordChecker) lookup(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// Padding hides the number of matching suffixes from anyone watching the response size
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		// Padding entries have a count of zero
+		if ok && candidate == suffix && count != "0" {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
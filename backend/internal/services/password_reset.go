@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// ErrInvalidResetToken is returned when a password reset token is unknown, expired or already used
+var ErrInvalidResetToken = errors.New("password reset token is invalid or has expired")
+
+const (
+	// passwordResetTokenTTL is how long a password reset link stays valid
+	passwordResetTokenTTL = 30 * time.Minute
+
+	// passwordResetRequestInterval is the minimum time between reset emails to one user
+	passwordResetRequestInterval = time.Minute
+)
+
+// PasswordResetTokenService stores single-use password reset tokens. Only their hashes are kept.
+type PasswordResetTokenService struct {
+	db *sql.DB
+}
+
+func NewPasswordResetTokenService(db *sql.DB) *PasswordResetTokenService {
+	return &PasswordResetTokenService{db: db}
+}
+
+// CreateToken generates and stores a reset token for the user. It returns an empty token when one
+// was already issued to the user within passwordResetRequestInterval.
+func (s *PasswordResetTokenService) CreateToken(ctx context.Context, userID string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate password reset token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	query := `
+		INSERT INTO password_reset_tokens (user_id, token_hash, expires_at)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (
+			SELECT 1 FROM password_reset_tokens
+			WHERE user_id = $1 AND created_at > NOW() - $4 * INTERVAL '1 second'
+		)`
+
+	result, err := s.db.ExecContext(ctx, query, userID, hashResetToken(token),
+		time.Now().Add(passwordResetTokenTTL), passwordResetRequestInterval.Seconds())
+	if err != nil {
+		return "", fmt.Errorf("failed to store password reset token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return "", nil
+	}
+
+	return token, nil
+}
+
+// ConsumeToken marks an unused, unexpired token as used and returns the ID of the user it was
+// issued to. A token can only be consumed once.
+func (s *PasswordResetTokenService) ConsumeToken(ctx context.Context, token string) (string, error) {
+	query := `
+		UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id`
+
+	var userID string
+	err := s.db.QueryRowContext(ctx, query, hashResetToken(token)).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", ErrInvalidResetToken
+		}
+		return "", fmt.Errorf("failed to consume password reset token: %w", err)
+	}
+
+	return userID, nil
+}
+
+// InvalidateUserTokens marks every outstanding reset token of a user as used
+func (s *PasswordResetTokenService) InvalidateUserTokens(ctx context.Context, userID string) error {
+	query := `UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+
+	if _, err := s.db.ExecContext(ctx, query, userID); err != nil {
+		return fmt.Errorf("failed to invalidate password reset tokens: %w", err)
+	}
+
+	return nil
+}
+
+func hashResetToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// SetPasswordReset sets the store for password reset tokens and the URL reset links point to
+func (s *AuthService) SetPasswordReset(resetTokens *PasswordResetTokenService, resetURL string) {
+	s.resetTokens = resetTokens
+	s.passwordResetURL = resetURL
+}
+
+// ForgotPassword emails a password reset link to the user with the email address, if there is
+// one. It reports no error for unknown addresses so callers cannot use it to discover accounts.
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+	if s.resetTokens == nil || s.notifications == nil {
+		log.Printf("Password reset requested but email delivery is not configured")
+		return nil
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		log.Printf("Password reset requested for unknown email: %v", err)
+		return nil
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	token, err := s.resetTokens.CreateToken(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		// A reset email was sent moments ago
+		return nil
+	}
+
+	go func() {
+		if err := s.sendPasswordResetEmail(user, token); err != nil {
+			log.Printf("Failed to send password reset email to user %s: %v", user.ID, err)
+		}
+	}()
+
+	return nil
+}
+
+// ResetPassword sets a new password for the user a reset token was issued to, then invalidates
+// the user's other reset tokens and signs them out everywhere
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if s.resetTokens == nil {
+		return ErrInvalidResetToken
+	}
+
+	// Checked before the token is consumed so a rejected password does not use it up
+	if err := s.passwordService.ValidatePassword(ctx, newPassword); err != nil {
+		return fmt.Errorf("new password validation failed: %w", err)
+	}
+
+	hashedPassword, err := s.passwordService.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash new password: %w", err)
+	}
+
+	userID, err := s.resetTokens.ConsumeToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	if err := s.userRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+
+	if err := s.resetTokens.InvalidateUserTokens(ctx, userID); err != nil {
+		log.Printf("Failed to invalidate password reset tokens for user %s: %v", userID, err)
+	}
+
+	if err := s.jwtService.RevokeUserTokens(ctx, userID, "password_reset"); err != nil {
+		return fmt.Errorf("failed to invalidate sessions: %w", err)
+	}
+
+	return nil
+}
+
+// sendPasswordResetEmail mails a reset link carrying the token to the user
+func (s *AuthService) sendPasswordResetEmail(user *models.User, token string) error {
+	link, err := url.Parse(s.passwordResetURL)
+	if err != nil {
+		return fmt.Errorf("invalid password reset URL: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	body := fmt.Sprintf("Hi %s,\r\n\r\nSomeone asked to reset the password of your CloudWeave account. "+
+		"To choose a new password, open this link:\r\n\r\n%s\r\n\r\n"+
+		"The link expires in %s and can only be used once. If you did not ask to reset your password, "+
+		"you can ignore this email.\r\n",
+		user.Name, link.String(), passwordResetTokenTTL)
+
+	return s.notifications.SendEmail([]string{user.Email}, "Reset your CloudWeave password", body)
+}
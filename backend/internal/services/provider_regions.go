@@ -0,0 +1,152 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// DefaultRegionCacheTTL is how long a provider's fetched region list is reused
+const DefaultRegionCacheTTL = 12 * time.Hour
+
+// defaultProviderRegions are the regions of providers that cannot list their own, such as the
+// mock providers used without cloud credentials
+var defaultProviderRegions = map[string][]string{
+	models.ProviderAWS:   {"us-east-1", "us-west-2", "eu-west-1", "ap-southeast-1"},
+	models.ProviderGCP:   {"us-central1", "us-east1", "europe-west1", "asia-southeast1"},
+	models.ProviderAzure: {"eastus", "westus2", "westeurope", "southeastasia"},
+}
+
+// RegionLister is implemented by cloud providers that can list the regions available to their
+// account
+type RegionLister interface {
+	ListRegions(ctx context.Context) ([]string, error)
+}
+
+// RegionValidationError is returned when a resource is requested in a region its provider does
+// not offer
+type RegionValidationError struct {
+	Provider string   `json:"provider"`
+	Region   string   `json:"region"`
+	Valid    []string `json:"validRegions"`
+}
+
+func (e *RegionValidationError) Error() string {
+	return fmt.Sprintf("invalid region %q for %s; valid regions: %s", e.Region, e.Provider, strings.Join(e.Valid, ", "))
+}
+
+type regionCacheEntry struct {
+	regions   []string
+	expiresAt time.Time
+}
+
+// RegionResolver resolves the regions each provider offers. Providers that list their regions
+// are asked and the answer cached; the rest use their default regions. Both the regions
+// advertised to clients and those resources are validated against come from here.
+type RegionResolver struct {
+	providers map[string]CloudProvider
+	ttl       time.Duration
+	mu        sync.Mutex
+	entries   map[string]regionCacheEntry
+}
+
+// NewRegionResolver creates a region resolver for the providers, caching fetched region lists
+// for ttl, defaulting to DefaultRegionCacheTTL when ttl is not positive
+func NewRegionResolver(providers map[string]CloudProvider, ttl time.Duration) *RegionResolver {
+	if ttl <= 0 {
+		ttl = DefaultRegionCacheTTL
+	}
+	return &RegionResolver{
+		providers: providers,
+		ttl:       ttl,
+		entries:   make(map[string]regionCacheEntry),
+	}
+}
+
+// Regions returns the regions a provider offers, sorted when fetched. Errors fetching them are not
+// cached.
+func (r *RegionResolver) Regions(ctx context.Context, providerName string) ([]string, error) {
+	provider, exists := r.providers[providerName]
+	if !exists {
+		return nil, fmt.Errorf("unsupported cloud provider: %s", providerName)
+	}
+	lister, ok := provider.(RegionLister)
+	if !ok {
+		return defaultProviderRegions[providerName], nil
+	}
+
+	r.mu.Lock()
+	entry, ok := r.entries[providerName]
+	r.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.regions, nil
+	}
+
+	regions, err := providerValue(ctx, lister.ListRegions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s regions: %w", providerName, err)
+	}
+	regions = append([]string(nil), regions...)
+	sort.Strings(regions)
+
+	r.mu.Lock()
+	r.entries[providerName] = regionCacheEntry{regions: regions, expiresAt: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+
+	return regions, nil
+}
+
+// AdvertisedRegions returns the regions to offer clients for a provider, falling back to its
+// default regions when they cannot be fetched
+func (r *RegionResolver) AdvertisedRegions(ctx context.Context, providerName string) []string {
+	regions, err := r.Regions(ctx, providerName)
+	if err != nil {
+		log.Printf("Advertising default regions: %v", err)
+		return defaultProviderRegions[providerName]
+	}
+	return regions
+}
+
+// Resolve returns a region as the provider spells it, matching case-insensitively, or a
+// RegionValidationError when the provider does not offer it. A region is passed through unchanged
+// when the provider's regions cannot be fetched, leaving the provider to reject it, so an outage
+// of its region API does not block creation.
+func (r *RegionResolver) Resolve(ctx context.Context, providerName, region string) (string, error) {
+	regions, err := r.Regions(ctx, providerName)
+	if err != nil {
+		log.Printf("Skipping region validation: %v", err)
+		return region, nil
+	}
+
+	for _, valid := range regions {
+		if strings.EqualFold(valid, region) {
+			return valid, nil
+		}
+	}
+	return "", &RegionValidationError{Provider: providerName, Region: region, Valid: regions}
+}
+
+// resourceRegionKey marks a context whose provider calls are for a resource in a given region
+type resourceRegionKey struct{}
+
+// withResourceRegion returns a context telling providers the region of the resource a call is
+// for, since not every external ID says where its resource lives. An empty region leaves the
+// provider's default region.
+func withResourceRegion(ctx context.Context, region string) context.Context {
+	if region == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, resourceRegionKey{}, region)
+}
+
+// resourceRegion returns the region set with withResourceRegion, or "" when there is none
+func resourceRegion(ctx context.Context) string {
+	region, _ := ctx.Value(resourceRegionKey{}).(string)
+	return region
+}
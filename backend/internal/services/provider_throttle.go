@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"golang.org/x/time/rate"
+)
+
+// Client-side request rates kept below the providers' documented API limits. EC2 refills its
+// request bucket at 20 calls per second for describe calls, and Azure Resource Manager allows a
+// subscription about 10 writes per second.
+const (
+	awsRequestsPerSecond   = 20
+	awsRequestBurst        = 40
+	azureRequestsPerSecond = 10
+	azureRequestBurst      = 50
+)
+
+// throttlingErrorCodes are the API error codes AWS services return when a caller exceeds a limit
+var throttlingErrorCodes = map[string]bool{
+	"RequestLimitExceeded":                   true,
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+// ProviderThrottle keeps calls to a cloud provider's API under its rate limits and retries calls
+// the provider throttled with exponential backoff and jitter. One throttle is shared by all of a
+// provider's SDK clients, and it plugs into each SDK's request pipeline so every call goes through
+// it without the call sites knowing.
+type ProviderThrottle struct {
+	limiter *rate.Limiter
+	config  RetryConfig
+}
+
+// NewProviderThrottle creates a throttle allowing requestsPerSecond calls on average with bursts
+// of up to burst calls, retrying throttled calls as configured
+func NewProviderThrottle(requestsPerSecond float64, burst int, config RetryConfig) *ProviderThrottle {
+	return &ProviderThrottle{
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+		config:  config,
+	}
+}
+
+// DefaultProviderRetryConfig returns the retry configuration for throttled provider calls, which
+// backs off for longer than the default since throttling lasts until the provider's bucket refills
+func DefaultProviderRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:       6,
+		InitialDelay:      500 * time.Millisecond,
+		MaxDelay:          20 * time.Second,
+		BackoffMultiplier: 2.0,
+		JitterEnabled:     true,
+	}
+}
+
+// Wait blocks until the rate limiter allows another call or ctx is done
+func (t *ProviderThrottle) Wait(ctx context.Context) error {
+	return t.limiter.Wait(ctx)
+}
+
+// backoff returns how long to wait before retrying after the given failed attempt, counting from
+// 1. With jitter the delay is drawn from the upper half of the exponential delay, so clients
+// throttled together do not retry together.
+func (t *ProviderThrottle) backoff(attempt int) time.Duration {
+	delay := float64(t.config.InitialDelay)
+	for i := 1; i < attempt && delay < float64(t.config.MaxDelay); i++ {
+		delay *= t.config.BackoffMultiplier
+	}
+	if delay > float64(t.config.MaxDelay) {
+		delay = float64(t.config.MaxDelay)
+	}
+	if t.config.JitterEnabled {
+		delay = delay/2 + rand.Float64()*delay/2
+	}
+	return time.Duration(delay)
+}
+
+// isThrottlingError reports whether an AWS call failed because the service throttled it
+func isThrottlingError(err error) bool {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && throttlingErrorCodes[apiErr.ErrorCode()] {
+		return true
+	}
+
+	var responseErr *smithyhttp.ResponseError
+	return errors.As(err, &responseErr) && responseErr.HTTPStatusCode() == http.StatusTooManyRequests
+}
+
+// awsRetryer retries AWS calls that were throttled, as well as the transient failures the SDK
+// retries by default, with the throttle's backoff
+func (t *ProviderThrottle) awsRetryer() aws.Retryer {
+	return retry.NewStandard(func(o *retry.StandardOptions) {
+		o.MaxAttempts = t.config.MaxAttempts
+		o.MaxBackoff = t.config.MaxDelay
+		o.Backoff = awsThrottleBackoff{t}
+		// The throttle's limiter paces calls, so the SDK's retry quota is not needed
+		o.RateLimiter = ratelimit.None
+		o.Retryables = append([]retry.IsErrorRetryable{
+			retry.IsErrorRetryableFunc(func(err error) aws.Ternary {
+				if isThrottlingError(err) {
+					return aws.TrueTernary
+				}
+				return aws.UnknownTernary
+			}),
+		}, o.Retryables...)
+	})
+}
+
+// awsRateLimit adds a middleware waiting for the throttle's limiter before each attempt of an AWS
+// call, retries included
+func (t *ProviderThrottle) awsRateLimit(stack *middleware.Stack) error {
+	return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("ProviderThrottle",
+		func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+			if err := t.Wait(ctx); err != nil {
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, err
+			}
+			return next.HandleFinalize(ctx, in)
+		}), middleware.After)
+}
+
+type awsThrottleBackoff struct {
+	throttle *ProviderThrottle
+}
+
+// BackoffDelay implements the AWS SDK's backoff strategy with the throttle's backoff
+func (b awsThrottleBackoff) BackoffDelay(attempt int, err error) (time.Duration, error) {
+	return b.throttle.backoff(attempt), nil
+}
+
+// azureClientOptions configures Azure clients to wait for the throttle's limiter before each
+// attempt and to retry throttled (429) responses with the throttle's backoff. Other transient
+// failures are still retried by the Azure SDK's own retry policy.
+func (t *ProviderThrottle) azureClientOptions() *arm.ClientOptions {
+	return &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Retry: policy.RetryOptions{
+				StatusCodes: []int{
+					http.StatusRequestTimeout,
+					http.StatusInternalServerError,
+					http.StatusBadGateway,
+					http.StatusServiceUnavailable,
+					http.StatusGatewayTimeout,
+				},
+			},
+			PerCallPolicies:  []policy.Policy{azureThrottleRetryPolicy{t}},
+			PerRetryPolicies: []policy.Policy{azureRateLimitPolicy{t}},
+		},
+	}
+}
+
+type azureThrottleRetryPolicy struct {
+	throttle *ProviderThrottle
+}
+
+// Do sends the request on, retrying it while Azure responds 429 Too Many Requests. The delay is
+// the throttle's backoff or the response's Retry-After, whichever is longer.
+func (p azureThrottleRetryPolicy) Do(req *policy.Request) (*http.Response, error) {
+	ctx := req.Raw().Context()
+	for attempt := 1; ; attempt++ {
+		if err := req.RewindBody(); err != nil {
+			return nil, err
+		}
+
+		resp, err := req.Clone(ctx).Next()
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt >= p.throttle.config.MaxAttempts {
+			return resp, err
+		}
+
+		delay := p.throttle.backoff(attempt)
+		if retryAfter := retryAfterDelay(resp); retryAfter > delay {
+			delay = retryAfter
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+type azureRateLimitPolicy struct {
+	throttle *ProviderThrottle
+}
+
+// Do waits for the throttle's limiter before sending the request on
+func (p azureRateLimitPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if err := p.throttle.Wait(req.Raw().Context()); err != nil {
+		return nil, err
+	}
+	return req.Next()
+}
+
+// retryAfterDelay returns the delay a response's Retry-After header asks for, in seconds or as an
+// HTTP date, or zero without one
+func retryAfterDelay(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// ErrProviderTimeout is returned when a cloud provider call does not finish within its deadline
+var ErrProviderTimeout = errors.New("cloud provider call timed out")
+
+// Provider call deadlines. A single API call gets DefaultProviderCallTimeout, while provisioning a
+// new resource, which waits for the provider to finish creating it, gets DefaultProvisionTimeout.
+const (
+	DefaultProviderCallTimeout = 30 * time.Second
+	DefaultProvisionTimeout    = 30 * time.Minute
+)
+
+// providerCallTimeout is shared by every service that calls providers, since the cost and metrics
+// services are created per request
+var providerCallTimeout atomic.Int64
+
+// SetProviderCallTimeout sets how long a single cloud provider call may take before it is abandoned
+func SetProviderCallTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		providerCallTimeout.Store(int64(timeout))
+	}
+}
+
+// ProviderCallTimeout returns how long a single cloud provider call may take
+func ProviderCallTimeout() time.Duration {
+	if timeout := time.Duration(providerCallTimeout.Load()); timeout > 0 {
+		return timeout
+	}
+	return DefaultProviderCallTimeout
+}
+
+// callProvider runs a provider call with timeout as its deadline, returning ErrProviderTimeout
+// when the deadline passes first
+func callProvider(ctx context.Context, timeout time.Duration, call func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := call(ctx)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s: %v", ErrProviderTimeout, timeout, err)
+	}
+	return err
+}
+
+// providerValue runs a provider call that returns a value with the provider call timeout as its
+// deadline
+func providerValue[T any](ctx context.Context, call func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+	err := callProvider(ctx, ProviderCallTimeout(), func(ctx context.Context) error {
+		var err error
+		value, err = call(ctx)
+		return err
+	})
+	return value, err
+}
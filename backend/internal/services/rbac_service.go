@@ -4,8 +4,12 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
+	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"cloudweave/internal/models"
@@ -21,7 +25,8 @@ type RBACService struct {
 	resourcePermRepo repositories.ResourcePermissionRepositoryInterface
 	apiKeyRepo       repositories.APIKeyRepositoryInterface
 	sessionRepo      repositories.SessionRepositoryInterface
-	auditRepo        repositories.AuditLogRepositoryInterface
+	orgRepo          repositories.OrganizationRepositoryInterface
+	auditWriter      *AuditWriter
 	txManager        repositories.TransactionManager
 }
 
@@ -32,7 +37,8 @@ func NewRBACService(
 	resourcePermRepo repositories.ResourcePermissionRepositoryInterface,
 	apiKeyRepo repositories.APIKeyRepositoryInterface,
 	sessionRepo repositories.SessionRepositoryInterface,
-	auditRepo repositories.AuditLogRepositoryInterface,
+	orgRepo repositories.OrganizationRepositoryInterface,
+	auditWriter *AuditWriter,
 	txManager repositories.TransactionManager,
 ) *RBACService {
 	return &RBACService{
@@ -41,7 +47,8 @@ func NewRBACService(
 		resourcePermRepo: resourcePermRepo,
 		apiKeyRepo:       apiKeyRepo,
 		sessionRepo:      sessionRepo,
-		auditRepo:        auditRepo,
+		orgRepo:          orgRepo,
+		auditWriter:      auditWriter,
 		txManager:        txManager,
 	}
 }
@@ -61,6 +68,10 @@ func (s *RBACService) CreateRole(ctx context.Context, role *models.Role, creator
 	role.CreatedAt = now
 	role.UpdatedAt = now
 
+	if err := s.validateParentRole(ctx, role); err != nil {
+		return err
+	}
+
 	// Create role
 	if err := s.roleRepo.Create(ctx, role); err != nil {
 		return fmt.Errorf("failed to create role: %w", err)
@@ -98,6 +109,10 @@ func (s *RBACService) UpdateRole(ctx context.Context, role *models.Role, updater
 		return fmt.Errorf("invalid role: %w", err)
 	}
 
+	if err := s.validateParentRole(ctx, role); err != nil {
+		return err
+	}
+
 	// Update timestamp
 	role.UpdatedAt = time.Now()
 
@@ -181,30 +196,114 @@ func (s *RBACService) RemoveRole(ctx context.Context, userID, roleID, organizati
 	return nil
 }
 
-// GetUserRoles retrieves all roles for a user
-func (s *RBACService) GetUserRoles(ctx context.Context, userID, organizationID string) ([]*models.UserRole, error) {
-	userRoles, err := s.userRoleRepo.GetUserRoles(ctx, userID, organizationID)
+// GetUserRoles retrieves a user's active roles, and their expired ones when includeExpired is set
+func (s *RBACService) GetUserRoles(ctx context.Context, userID, organizationID string, includeExpired bool) ([]*models.UserRole, error) {
+	userRoles, err := s.userRoleRepo.GetUserRoles(ctx, userID, organizationID, includeExpired)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user roles: %w", err)
 	}
 	return userRoles, nil
 }
 
+// DeactivateExpiredRoles deactivates role assignments whose expiry has passed and records
+// each one in the audit trail
+func (s *RBACService) DeactivateExpiredRoles(ctx context.Context) (int, error) {
+	expired, err := s.userRoleRepo.DeactivateExpired(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to deactivate expired roles: %w", err)
+	}
+
+	for _, userRole := range expired {
+		s.logAuditEvent(ctx, userRole.OrganizationID, userRole.UserID, "role_expired",
+			fmt.Sprintf("Role %s expired for user %s", userRole.RoleID, userRole.UserID), userRole.RoleID)
+	}
+
+	return len(expired), nil
+}
+
+// StartRoleExpiryCleanup periodically deactivates expired role assignments until ctx is cancelled
+func (s *RBACService) StartRoleExpiryCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := s.DeactivateExpiredRoles(ctx)
+			if err != nil {
+				log.Printf("Role expiry cleanup failed: %v", err)
+				continue
+			}
+			if count > 0 {
+				log.Printf("Deactivated %d expired role assignments", count)
+			}
+		}
+	}
+}
+
 // GetUserPermissions retrieves effective permissions for a user
 func (s *RBACService) GetUserPermissions(ctx context.Context, userID, organizationID string) (*models.UserPermissions, error) {
 	permissions, err := s.userRoleRepo.GetUserPermissions(ctx, userID, organizationID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user permissions: %w", err)
 	}
+
+	if err := s.resolveInheritedPermissions(ctx, permissions); err != nil {
+		return nil, fmt.Errorf("failed to resolve inherited permissions: %w", err)
+	}
+
 	return permissions, nil
 }
 
+// resolveInheritedPermissions adds the permissions of every ancestor of the user's roles
+func (s *RBACService) resolveInheritedPermissions(ctx context.Context, permissions *models.UserPermissions) error {
+	permissionSet := make(map[string]bool, len(permissions.Permissions))
+	for _, perm := range permissions.Permissions {
+		permissionSet[perm] = true
+	}
+
+	// Roles already expanded, shared across branches so common ancestors are loaded once
+	visited := make(map[string]bool)
+	for _, role := range permissions.Roles {
+		visited[role.ID] = true
+	}
+
+	for _, role := range permissions.Roles {
+		parentID := role.ParentRoleID
+		for parentID != nil && !visited[*parentID] {
+			visited[*parentID] = true
+
+			parent, err := s.roleRepo.GetByID(ctx, permissions.OrganizationID, *parentID)
+			if err != nil {
+				return fmt.Errorf("failed to get parent role %s: %w", *parentID, err)
+			}
+
+			for _, perm := range parent.Permissions {
+				permissionSet[perm] = true
+			}
+			parentID = parent.ParentRoleID
+		}
+	}
+
+	permissions.Permissions = permissions.Permissions[:0]
+	for perm := range permissionSet {
+		permissions.Permissions = append(permissions.Permissions, perm)
+		if permissionMatches(perm, models.PermissionAdminFull) {
+			permissions.IsAdmin = true
+		}
+	}
+
+	return nil
+}
+
 // Permission Checking
 
 // CheckPermission checks if a user has a specific permission
 func (s *RBACService) CheckPermission(ctx context.Context, check *models.PermissionCheck) (*models.PermissionResult, error) {
-	// Get user permissions
-	permissions, err := s.userRoleRepo.GetUserPermissions(ctx, check.UserID, check.OrganizationID)
+	// Get user permissions, including those inherited from parent roles
+	permissions, err := s.GetUserPermissions(ctx, check.UserID, check.OrganizationID)
 	if err != nil {
 		return &models.PermissionResult{
 			Allowed: false,
@@ -213,7 +312,7 @@ func (s *RBACService) CheckPermission(ctx context.Context, check *models.Permiss
 	}
 
 	// Check if user is admin (has full access)
-	if permissions.IsAdmin {
+	if permissions.IsAdmin || hasPermission(permissions.Permissions, models.PermissionAdminFull) {
 		return &models.PermissionResult{
 			Allowed:   true,
 			Reason:    "User has admin privileges",
@@ -221,15 +320,13 @@ func (s *RBACService) CheckPermission(ctx context.Context, check *models.Permiss
 		}, nil
 	}
 
-	// Check direct permission
-	for _, perm := range permissions.Permissions {
-		if perm == check.Permission {
-			return &models.PermissionResult{
-				Allowed:   true,
-				Reason:    "Permission granted via role",
-				GrantedBy: "role_permission",
-			}, nil
-		}
+	// Check direct and wildcard permissions
+	if hasPermission(permissions.Permissions, check.Permission) {
+		return &models.PermissionResult{
+			Allowed:   true,
+			Reason:    "Permission granted via role",
+			GrantedBy: "role_permission",
+		}, nil
 	}
 
 	// Check resource-specific permissions if applicable
@@ -267,10 +364,133 @@ func (s *RBACService) HasPermission(ctx context.Context, userID, organizationID,
 	return result.Allowed
 }
 
+// HasResourcePermission reports whether a user was granted a permission on one specific resource
+func (s *RBACService) HasResourcePermission(ctx context.Context, userID, organizationID, resourceType, resourceID, permission string) bool {
+	allowed, err := s.resourcePermRepo.HasPermission(ctx, userID, resourceType, resourceID, permission, organizationID)
+	return err == nil && allowed
+}
+
+// Resource Permission Management
+
+// ErrInvalidResourcePermission is returned when a resource permission grant is incomplete or
+// names a malformed permission
+var ErrInvalidResourcePermission = errors.New("invalid resource permission")
+
+// ErrResourcePermissionNotFound is returned when a resource permission grant does not exist in
+// the organization
+var ErrResourcePermissionNotFound = errors.New("resource permission not found")
+
+// ErrResourcePermissionNotAllowed is returned when granting a resource permission the granter
+// does not hold themselves
+var ErrResourcePermissionNotAllowed = errors.New("resource permission not allowed")
+
+// GrantResourcePermission grants a user permissions on one resource, adding to any grant the
+// user already holds on it. The user must be a member of the organization, and the granter can
+// only grant permissions they hold.
+func (s *RBACService) GrantResourcePermission(ctx context.Context, permission *models.ResourcePermission, granterID string) error {
+	if err := validateResourcePermission(permission); err != nil {
+		return err
+	}
+
+	if _, err := s.orgRepo.GetUserMembership(ctx, permission.UserID, permission.OrganizationID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotOrganizationMember
+		}
+		return fmt.Errorf("failed to get organization membership: %w", err)
+	}
+
+	held, err := s.heldPermissions(ctx, granterID, permission.OrganizationID, permission.Permissions)
+	if err != nil {
+		return err
+	}
+	if len(held) != len(permission.Permissions) {
+		return fmt.Errorf("%w: you can only grant permissions you hold", ErrResourcePermissionNotAllowed)
+	}
+
+	permission.ID = uuid.New().String()
+	permission.GrantedBy = granterID
+	permission.GrantedAt = time.Now()
+	if permission.Metadata == nil {
+		permission.Metadata = map[string]interface{}{}
+	}
+
+	if err := s.resourcePermRepo.Grant(ctx, permission); err != nil {
+		return fmt.Errorf("failed to grant resource permission: %w", err)
+	}
+
+	s.logAuditEvent(ctx, permission.OrganizationID, granterID, "resource_permission_granted",
+		fmt.Sprintf("Granted %s on %s %s to user %s", strings.Join(permission.Permissions, ", "),
+			permission.ResourceType, permission.ResourceID, permission.UserID), permission.ID)
+
+	return nil
+}
+
+// RevokeResourcePermission removes a resource permission grant
+func (s *RBACService) RevokeResourcePermission(ctx context.Context, organizationID, id, revokerID string) error {
+	permission, err := s.resourcePermRepo.GetByID(ctx, organizationID, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrResourcePermissionNotFound
+		}
+		return err
+	}
+
+	if err := s.resourcePermRepo.Revoke(ctx, permission.UserID, permission.ResourceType, permission.ResourceID, organizationID); err != nil {
+		return fmt.Errorf("failed to revoke resource permission: %w", err)
+	}
+
+	s.logAuditEvent(ctx, organizationID, revokerID, "resource_permission_revoked",
+		fmt.Sprintf("Revoked %s on %s %s from user %s", strings.Join(permission.Permissions, ", "),
+			permission.ResourceType, permission.ResourceID, permission.UserID), permission.ID)
+
+	return nil
+}
+
+// ListResourcePermissions lists the grants on a resource, or those held by a user when no
+// resource is given
+func (s *RBACService) ListResourcePermissions(ctx context.Context, organizationID, resourceType, resourceID, userID string) ([]*models.ResourcePermission, error) {
+	switch {
+	case resourceType != "" && resourceID != "":
+		return s.resourcePermRepo.GetResourcePermissions(ctx, resourceType, resourceID, organizationID)
+	case userID != "":
+		return s.resourcePermRepo.GetUserResourcePermissions(ctx, userID, organizationID)
+	default:
+		return nil, fmt.Errorf("%w: a resource type and ID or a user ID is required", ErrInvalidResourcePermission)
+	}
+}
+
+func validateResourcePermission(permission *models.ResourcePermission) error {
+	if permission.UserID == "" || permission.ResourceType == "" || permission.ResourceID == "" {
+		return fmt.Errorf("%w: user ID, resource type and resource ID are required", ErrInvalidResourcePermission)
+	}
+	if len(permission.Permissions) == 0 {
+		return fmt.Errorf("%w: at least one permission is required", ErrInvalidResourcePermission)
+	}
+	for _, perm := range permission.Permissions {
+		if perm != models.PermissionWildcard && !strings.Contains(perm, ":") {
+			return fmt.Errorf("%w: %s", ErrInvalidResourcePermission, perm)
+		}
+	}
+	return nil
+}
+
 // API Key Management
 
 // CreateAPIKey creates a new API key
-func (s *RBACService) CreateAPIKey(ctx context.Context, userID, organizationID, name, description string, permissions []string, expiresAt *time.Time) (*models.APIKey, string, error) {
+func (s *RBACService) CreateAPIKey(ctx context.Context, userID, organizationID, name, description string, permissions, scopes []string, rateLimit int, expiresAt *time.Time) (*models.APIKey, string, error) {
+	if err := s.validateAPIKeyScopes(scopes); err != nil {
+		return nil, "", err
+	}
+	if rateLimit < 0 {
+		return nil, "", fmt.Errorf("%w: %d", ErrInvalidRateLimit, rateLimit)
+	}
+
+	// A key acts for its creator, so it can only hold permissions the creator holds
+	permissions, err := s.heldPermissions(ctx, userID, organizationID, permissions)
+	if err != nil {
+		return nil, "", err
+	}
+
 	// Generate API key
 	keyBytes := make([]byte, 32)
 	if _, err := rand.Read(keyBytes); err != nil {
@@ -291,7 +511,8 @@ func (s *RBACService) CreateAPIKey(ctx context.Context, userID, organizationID,
 		KeyHash:        keyHash,
 		KeyPrefix:      keyPrefix,
 		Permissions:    permissions,
-		Scopes:         []string{}, // TODO: Implement scopes
+		Scopes:         scopes,
+		RateLimit:      rateLimit,
 		IsActive:       true,
 		ExpiresAt:      expiresAt,
 		CreatedAt:      time.Now(),
@@ -311,6 +532,22 @@ func (s *RBACService) CreateAPIKey(ctx context.Context, userID, organizationID,
 	return apiKey, rawKey, nil
 }
 
+// heldPermissions returns the permissions of requested that the user holds in the organization
+func (s *RBACService) heldPermissions(ctx context.Context, userID, organizationID string, requested []string) ([]string, error) {
+	held, err := s.GetUserPermissions(ctx, userID, organizationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user permissions: %w", err)
+	}
+
+	permissions := make([]string, 0, len(requested))
+	for _, perm := range requested {
+		if held.IsAdmin || hasPermission(held.Permissions, perm) {
+			permissions = append(permissions, perm)
+		}
+	}
+	return permissions, nil
+}
+
 // ValidateAPIKey validates an API key and returns the associated user
 func (s *RBACService) ValidateAPIKey(ctx context.Context, rawKey string) (*models.APIKey, error) {
 	keyHash := s.hashAPIKey(rawKey)
@@ -413,6 +650,93 @@ func (s *RBACService) validateRole(role *models.Role) error {
 	return nil
 }
 
+// hasPermission reports whether any granted permission matches the required one
+// APIKeyHasPermission reports whether an API key's permissions cover the required permission.
+// Keys are matched like roles: admin:full and wildcards cover every permission they match.
+func APIKeyHasPermission(granted []string, required string) bool {
+	return hasPermission(granted, models.PermissionAdminFull) || hasPermission(granted, required)
+}
+
+func hasPermission(granted []string, required string) bool {
+	for _, perm := range granted {
+		if permissionMatches(perm, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// permissionMatches reports whether a granted permission satisfies the required one.
+// "*" matches everything and "<resource>:*" matches every action on that resource.
+func permissionMatches(granted, required string) bool {
+	switch {
+	case granted == models.PermissionWildcard:
+		return true
+	case granted == required:
+		return true
+	case strings.HasSuffix(granted, ":"+models.PermissionWildcard):
+		return strings.HasPrefix(required, strings.TrimSuffix(granted, models.PermissionWildcard))
+	default:
+		return false
+	}
+}
+
+// ErrInvalidParentRole is returned when a role's parent is missing, belongs to another
+// organization or would create an inheritance cycle
+var ErrInvalidParentRole = errors.New("invalid parent role")
+
+// validateParentRole walks the parent chain of a role, rejecting cross-organization parents
+// and chains that lead back to the role itself
+func (s *RBACService) validateParentRole(ctx context.Context, role *models.Role) error {
+	if role.ParentRoleID == nil {
+		return nil
+	}
+	if *role.ParentRoleID == "" {
+		role.ParentRoleID = nil
+		return nil
+	}
+
+	visited := map[string]bool{role.ID: true}
+	parentID := role.ParentRoleID
+	for parentID != nil {
+		if visited[*parentID] {
+			return fmt.Errorf("%w: inheritance cycle detected", ErrInvalidParentRole)
+		}
+		visited[*parentID] = true
+
+		parent, err := s.roleRepo.GetByID(ctx, role.OrganizationID, *parentID)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %v", ErrInvalidParentRole, *parentID, err)
+		}
+		if !parent.IsSystem && parent.OrganizationID != role.OrganizationID {
+			return fmt.Errorf("%w: parent role belongs to a different organization", ErrInvalidParentRole)
+		}
+
+		parentID = parent.ParentRoleID
+	}
+
+	return nil
+}
+
+// ErrInvalidScope is returned when an API key is created with an unknown scope
+var ErrInvalidScope = errors.New("invalid API key scope")
+
+// ErrInvalidRateLimit is returned when an API key is created with a negative rate limit
+var ErrInvalidRateLimit = errors.New("invalid API key rate limit")
+
+// validateAPIKeyScopes requires at least one known scope, since a key without scopes has no access
+func (s *RBACService) validateAPIKeyScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if !models.IsValidAPIKeyScope(scope) {
+			return fmt.Errorf("%w: %s", ErrInvalidScope, scope)
+		}
+	}
+	return nil
+}
+
 func (s *RBACService) hashAPIKey(key string) string {
 	hash := sha256.Sum256([]byte(key))
 	return hex.EncodeToString(hash[:])
@@ -436,18 +760,12 @@ func (s *RBACService) logAuditEvent(ctx context.Context, orgID, userID, action,
 		ResourceType:   &resourceType,
 		ResourceID:     &resourceID,
 		Details:        detailsMap,
-		IPAddress:      nil, // TODO: Extract from context
-		UserAgent:      nil, // TODO: Extract from context
 		CreatedAt:      time.Now(),
 	}
+	AuditContextFromContext(ctx).apply(auditLog)
 
-	// Log asynchronously to avoid blocking the main operation
-	go func() {
-		if err := s.auditRepo.Create(context.Background(), auditLog); err != nil {
-			// Log error but don't fail the main operation
-			fmt.Printf("Failed to create audit log: %v\n", err)
-		}
-	}()
+	// Queued for a batched write to avoid blocking the main operation
+	s.auditWriter.Write(auditLog)
 }
 
 // InitializeSystemRoles creates default system roles if they don't exist
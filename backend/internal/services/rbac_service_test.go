@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+func TestPermissionMatches(t *testing.T) {
+	tests := []struct {
+		granted  string
+		required string
+		want     bool
+	}{
+		{"infrastructure:*", models.PermissionInfrastructureView, true},
+		{"infrastructure:*", models.PermissionInfrastructureDelete, true},
+		{"infrastructure:*", models.PermissionDeploymentView, false},
+		{models.PermissionWildcard, models.PermissionDeploymentCreate, true},
+		{models.PermissionWildcard, models.PermissionAdminFull, true},
+		{models.PermissionDeploymentManage, models.PermissionDeploymentManage, true},
+		{models.PermissionDeploymentManage, models.PermissionDeploymentView, false},
+		{models.PermissionDeploymentManage, models.PermissionDeploymentCreate, false},
+		{models.PermissionDeploymentManage, models.PermissionInfrastructureManage, false},
+		{models.PermissionInfrastructureView, models.PermissionInfrastructureUpdate, false},
+	}
+
+	for _, tt := range tests {
+		if got := permissionMatches(tt.granted, tt.required); got != tt.want {
+			t.Errorf("permissionMatches(%q, %q) = %v, want %v", tt.granted, tt.required, got, tt.want)
+		}
+	}
+}
+
+func TestAPIKeyHasPermission(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		required string
+		want     bool
+	}{
+		{"wildcard resource", []string{"infrastructure:*"}, models.PermissionInfrastructureView, true},
+		{"manage does not cover view", []string{models.PermissionDeploymentManage}, models.PermissionDeploymentView, false},
+		{"admin covers everything", []string{models.PermissionAdminFull}, models.PermissionDeploymentCreate, true},
+		{"exact", []string{models.PermissionCostView}, models.PermissionCostView, true},
+		{"no permissions", nil, models.PermissionCostView, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := APIKeyHasPermission(tt.granted, tt.required); got != tt.want {
+				t.Errorf("APIKeyHasPermission(%v, %q) = %v, want %v", tt.granted, tt.required, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeUserRoleRepo returns fixed permissions per user
+type fakeUserRoleRepo struct {
+	repositories.UserRoleRepositoryInterface
+
+	permissions map[string][]string
+}
+
+func (r *fakeUserRoleRepo) GetUserPermissions(ctx context.Context, userID, organizationID string) (*models.UserPermissions, error) {
+	return &models.UserPermissions{UserID: userID, OrganizationID: organizationID, Permissions: r.permissions[userID]}, nil
+}
+
+// fakeMembershipRepo knows which users belong to the organization
+type fakeMembershipRepo struct {
+	repositories.OrganizationRepositoryInterface
+
+	members map[string]bool
+}
+
+func (r *fakeMembershipRepo) GetUserMembership(ctx context.Context, userID, organizationID string) (*models.OrganizationMembership, error) {
+	if !r.members[userID] {
+		return nil, sql.ErrNoRows
+	}
+	return &models.OrganizationMembership{OrganizationID: organizationID}, nil
+}
+
+// recordingResourcePermRepo keeps the grants made through it
+type recordingResourcePermRepo struct {
+	repositories.ResourcePermissionRepositoryInterface
+
+	granted []*models.ResourcePermission
+}
+
+func (r *recordingResourcePermRepo) Grant(ctx context.Context, permission *models.ResourcePermission) error {
+	r.granted = append(r.granted, permission)
+	return nil
+}
+
+func TestGrantResourcePermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		userID      string
+		permissions []string
+		wantErr     error
+	}{
+		{"held permission", "member", []string{models.PermissionInfrastructureView}, nil},
+		{"permission the granter lacks", "member", []string{models.PermissionInfrastructureDelete}, ErrResourcePermissionNotAllowed},
+		{"user outside the organization", "outsider", []string{models.PermissionInfrastructureView}, ErrNotOrganizationMember},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resourcePermRepo := &recordingResourcePermRepo{}
+			userRoleRepo := &fakeUserRoleRepo{permissions: map[string][]string{
+				"granter": {models.PermissionUserManage, models.PermissionInfrastructureView},
+			}}
+			orgRepo := &fakeMembershipRepo{members: map[string]bool{"granter": true, "member": true}}
+			rbacService := NewRBACService(nil, userRoleRepo, resourcePermRepo, nil, nil, orgRepo, NewAuditWriter(&recordingAuditRepo{}, 10), nil)
+
+			err := rbacService.GrantResourcePermission(context.Background(), &models.ResourcePermission{
+				UserID:         tt.userID,
+				OrganizationID: "org-1",
+				ResourceType:   "infrastructure",
+				ResourceID:     "infra-1",
+				Permissions:    tt.permissions,
+			}, "granter")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GrantResourcePermission returned %v, want %v", err, tt.wantErr)
+			}
+			if granted := len(resourcePermRepo.granted) == 1; granted != (tt.wantErr == nil) {
+				t.Errorf("granted = %v, want %v", granted, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// DefaultResourceCacheTTL is how long provider resource details and metrics are reused
+const DefaultResourceCacheTTL = 30 * time.Second
+
+// Kinds of provider data held in the resource cache
+const (
+	resourceCacheDetails = "details"
+	resourceCacheMetrics = "metrics"
+)
+
+type resourceCacheKey struct {
+	provider   string
+	externalID string
+	kind       string
+}
+
+type resourceCacheEntry struct {
+	value     map[string]interface{}
+	expiresAt time.Time
+}
+
+// ResourceCache keeps provider resource details and metrics per external ID for a short time, so
+// cost and dashboard queries that each loop over every resource share provider calls instead of
+// repeating them. Errors are not cached. Cached maps are shared between callers and must not be
+// modified. A nil ResourceCache calls the provider every time.
+type ResourceCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[resourceCacheKey]resourceCacheEntry
+}
+
+// NewResourceCache creates a resource cache whose entries expire after ttl, defaulting to
+// DefaultResourceCacheTTL when ttl is not positive
+func NewResourceCache(ttl time.Duration) *ResourceCache {
+	if ttl <= 0 {
+		ttl = DefaultResourceCacheTTL
+	}
+	return &ResourceCache{
+		ttl:     ttl,
+		entries: make(map[resourceCacheKey]resourceCacheEntry),
+	}
+}
+
+// Details returns a resource's provider details, from the cache when fresh
+func (c *ResourceCache) Details(ctx context.Context, providerName string, provider CloudProvider, externalID string) (map[string]interface{}, error) {
+	return c.get(resourceCacheKey{providerName, externalID, resourceCacheDetails}, func() (map[string]interface{}, error) {
+		return providerValue(ctx, func(ctx context.Context) (map[string]interface{}, error) {
+			return provider.GetResourceDetails(ctx, externalID)
+		})
+	})
+}
+
+// Metrics returns a resource's current provider metrics, from the cache when fresh
+func (c *ResourceCache) Metrics(ctx context.Context, providerName string, provider CloudProvider, externalID string) (map[string]interface{}, error) {
+	return c.get(resourceCacheKey{providerName, externalID, resourceCacheMetrics}, func() (map[string]interface{}, error) {
+		return providerValue(ctx, func(ctx context.Context) (map[string]interface{}, error) {
+			return provider.GetResourceMetrics(ctx, externalID)
+		})
+	})
+}
+
+func (c *ResourceCache) get(key resourceCacheKey, fetch func() (map[string]interface{}, error)) (map[string]interface{}, error) {
+	if c == nil {
+		return fetch()
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.value, nil
+	}
+
+	value, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[key] = resourceCacheEntry{value: value, expiresAt: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+
+	return value, nil
+}
+
+// PrefetchMetrics caches the metrics of many resources of one provider with a single call, when
+// the provider implements BatchMetricsProvider. Resources still fresh in the cache are skipped,
+// and failures are left for Metrics to retry one resource at a time.
+func (c *ResourceCache) PrefetchMetrics(ctx context.Context, providerName string, provider CloudProvider, externalIDs []string) {
+	batcher, ok := provider.(BatchMetricsProvider)
+	if c == nil || !ok {
+		return
+	}
+
+	now := time.Now()
+	var stale []string
+	c.mu.Lock()
+	for _, externalID := range externalIDs {
+		entry, ok := c.entries[resourceCacheKey{providerName, externalID, resourceCacheMetrics}]
+		if !ok || !now.Before(entry.expiresAt) {
+			stale = append(stale, externalID)
+		}
+	}
+	c.mu.Unlock()
+	if len(stale) == 0 {
+		return
+	}
+
+	metrics, err := providerValue(ctx, func(ctx context.Context) (map[string]map[string]interface{}, error) {
+		return batcher.GetResourcesMetrics(ctx, stale)
+	})
+	if err != nil {
+		return
+	}
+
+	expiresAt := time.Now().Add(c.ttl)
+	c.mu.Lock()
+	for externalID, value := range metrics {
+		c.entries[resourceCacheKey{providerName, externalID, resourceCacheMetrics}] = resourceCacheEntry{value: value, expiresAt: expiresAt}
+	}
+	c.mu.Unlock()
+}
+
+// Invalidate drops everything cached for an infrastructure resource
+func (c *ResourceCache) Invalidate(infra *models.Infrastructure) {
+	if c == nil || infra.ExternalID == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, kind := range []string{resourceCacheDetails, resourceCacheMetrics} {
+		delete(c.entries, resourceCacheKey{infra.Provider, *infra.ExternalID, kind})
+	}
+}
+
+// StartCleanup removes expired entries every interval until ctx is cancelled
+func (c *ResourceCache) StartCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			c.mu.Lock()
+			for key, entry := range c.entries {
+				if !now.Before(entry.expiresAt) {
+					delete(c.entries, key)
+				}
+			}
+			c.mu.Unlock()
+		}
+	}
+}
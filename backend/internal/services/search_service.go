@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// ErrInvalidSearchQuery is returned when a search query has no searchable terms
+var ErrInvalidSearchQuery = errors.New("invalid search query")
+
+// ErrInvalidSearchType is returned when searching a resource type that is not searchable
+var ErrInvalidSearchType = errors.New("invalid search type")
+
+// Search limits
+const (
+	DefaultSearchLimit = 10
+	MaxSearchLimit     = 50
+	maxSearchTerms     = 8
+)
+
+// searchableTypes lists the searchable resource types with the permission needed to view them and
+// the resource type used for resource-specific grants
+var searchableTypes = map[string]struct {
+	permission   string
+	resourceType string
+}{
+	models.SearchTypeInfrastructure: {models.PermissionInfrastructureView, "infrastructure"},
+	models.SearchTypeDeployment:     {models.PermissionDeploymentView, "deployment"},
+	models.SearchTypeAlert:          {models.PermissionMonitoringView, "alert"},
+}
+
+// SearchService searches across an organization's resources, limited to what the caller may view
+type SearchService struct {
+	searchRepo  repositories.SearchRepositoryInterface
+	rbacService *RBACService
+}
+
+// NewSearchService creates a new search service
+func NewSearchService(searchRepo repositories.SearchRepositoryInterface, rbacService *RBACService) *SearchService {
+	return &SearchService{
+		searchRepo:  searchRepo,
+		rbacService: rbacService,
+	}
+}
+
+// Search returns up to limit matches per resource type. Types default to every searchable type.
+// Users who cannot view a type only see the matches they were granted access to individually.
+func (s *SearchService) Search(ctx context.Context, orgID, userID, query string, types []string, limit int) (*models.SearchResponse, error) {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: query must contain letters or digits", ErrInvalidSearchQuery)
+	}
+
+	if len(types) == 0 {
+		for resultType := range searchableTypes {
+			types = append(types, resultType)
+		}
+		sort.Strings(types)
+	}
+	for _, resultType := range types {
+		if _, ok := searchableTypes[resultType]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidSearchType, resultType)
+		}
+	}
+
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if limit > MaxSearchLimit {
+		limit = MaxSearchLimit
+	}
+
+	permissions, err := s.rbacService.GetUserPermissions(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.SearchResponse{
+		Query:   query,
+		Results: make(map[string][]*models.SearchResult, len(types)),
+	}
+	for _, resultType := range types {
+		results, err := s.searchRepo.Search(ctx, orgID, resultType, terms, limit)
+		if err != nil {
+			return nil, err
+		}
+
+		access := searchableTypes[resultType]
+		if !permissions.IsAdmin && !hasPermission(permissions.Permissions, access.permission) {
+			visible := results[:0]
+			for _, result := range results {
+				if s.rbacService.HasResourcePermission(ctx, userID, orgID, access.resourceType, result.ID, access.permission) {
+					visible = append(visible, result)
+				}
+			}
+			results = visible
+		}
+
+		response.Results[resultType] = results
+		response.Total += len(results)
+	}
+
+	return response, nil
+}
+
+// searchTerms splits a query into lowercase terms of letters and digits, dropping everything else
+func searchTerms(query string) []string {
+	terms := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) > maxSearchTerms {
+		terms = terms[:maxSearchTerms]
+	}
+	return terms
+}
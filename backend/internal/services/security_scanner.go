@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// ErrScanUnsupported is returned when a scan's target cannot be scanned
+var ErrScanUnsupported = errors.New("scan target is not supported")
+
+// Scanner finds the vulnerabilities of a security scan's target. SecurityService runs the scanner
+// registered for each scan type.
+type Scanner interface {
+	Scan(ctx context.Context, scan *models.SecurityScan) ([]*models.Vulnerability, error)
+}
+
+// ScannerFunc adapts a function to the Scanner interface
+type ScannerFunc func(ctx context.Context, scan *models.SecurityScan) ([]*models.Vulnerability, error)
+
+// Scan calls f
+func (f ScannerFunc) Scan(ctx context.Context, scan *models.SecurityScan) ([]*models.Vulnerability, error) {
+	return f(ctx, scan)
+}
+
+// Misconfiguration is a security weakness in a cloud resource's configuration. Location tells
+// apart findings of the same rule on one resource, e.g. the security group and port a rule opens.
+type Misconfiguration struct {
+	RuleID         string
+	Title          string
+	Description    string
+	Severity       models.VulnerabilitySeverity
+	Location       string
+	Recommendation string
+	References     []string
+}
+
+// ConfigurationInspector is implemented by cloud providers that can check a provisioned
+// resource's live configuration for misconfigurations
+type ConfigurationInspector interface {
+	InspectConfiguration(ctx context.Context, infra *models.Infrastructure) ([]Misconfiguration, error)
+}
+
+// MisconfigurationScanner scans infrastructure resources for misconfigurations. A scan's target ID
+// is the ID of the infrastructure resource to inspect.
+type MisconfigurationScanner struct {
+	infraRepo   repositories.InfrastructureRepositoryInterface
+	providers   map[string]CloudProvider
+	awsAccounts *AWSAccounts
+}
+
+// NewMisconfigurationScanner creates a scanner that inspects resources with their cloud providers
+func NewMisconfigurationScanner(infraRepo repositories.InfrastructureRepositoryInterface, providers map[string]CloudProvider) *MisconfigurationScanner {
+	return &MisconfigurationScanner{
+		infraRepo: infraRepo,
+		providers: providers,
+	}
+}
+
+// SetAWSAccounts sets where the AWS accounts organizations manage through their own credentials
+// come from, so their resources are inspected in those accounts
+func (ms *MisconfigurationScanner) SetAWSAccounts(accounts *AWSAccounts) {
+	ms.awsAccounts = accounts
+}
+
+// Scan inspects the scan's target resource and reports each misconfiguration as a vulnerability
+func (ms *MisconfigurationScanner) Scan(ctx context.Context, scan *models.SecurityScan) ([]*models.Vulnerability, error) {
+	infra, err := ms.infraRepo.GetByID(ctx, scan.TargetID)
+	if err != nil || infra.OrganizationID != scan.OrganizationID {
+		return nil, fmt.Errorf("%w: infrastructure %s", ErrInfrastructureNotFound, scan.TargetID)
+	}
+	if infra.ExternalID == nil {
+		return nil, ErrInfrastructureNotManaged
+	}
+
+	provider, err := ms.awsAccounts.providerFor(ctx, infra.OrganizationID, infra.Provider, ms.providers)
+	if err != nil {
+		return nil, err
+	}
+	inspector, ok := provider.(ConfigurationInspector)
+	if !ok {
+		return nil, fmt.Errorf("%w: configuration scanning is not available for %s", ErrScanUnsupported, infra.Provider)
+	}
+
+	misconfigurations, err := providerValue(ctx, func(ctx context.Context) ([]Misconfiguration, error) {
+		return inspector.InspectConfiguration(ctx, infra)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect %s: %w", infra.Name, err)
+	}
+
+	vulnerabilities := make([]*models.Vulnerability, 0, len(misconfigurations))
+	for _, m := range misconfigurations {
+		vuln := newScanFinding(scan, m.Title, m.Description, m.Severity)
+		vuln.ResourceType = infra.Type
+		vuln.ResourceID = infra.ID
+		vuln.ResourceName = infra.Name
+		vuln.Location = m.RuleID
+		if m.Location != "" {
+			vuln.Location += "/" + m.Location
+		}
+		vuln.Recommendation = m.Recommendation
+		vuln.References = m.References
+		vuln.Tags = []string{"misconfiguration", infra.Provider, m.RuleID}
+		vulnerabilities = append(vulnerabilities, vuln)
+	}
+
+	return vulnerabilities, nil
+}
+
+// newScanFinding creates an open vulnerability found by a scan, against the scan's target
+func newScanFinding(scan *models.SecurityScan, title, description string, severity models.VulnerabilitySeverity) *models.Vulnerability {
+	now := time.Now()
+	return &models.Vulnerability{
+		ID:             uuid.New().String(),
+		OrganizationID: scan.OrganizationID,
+		ScanID:         scan.ID,
+		Title:          title,
+		Description:    description,
+		Severity:       severity,
+		Status:         models.VulnStatusOpen,
+		ResourceType:   scan.TargetType,
+		ResourceID:     scan.TargetID,
+		ResourceName:   scan.TargetName,
+		FirstDetected:  now,
+		LastSeen:       now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
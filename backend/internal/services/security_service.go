@@ -14,26 +14,37 @@ import (
 
 // SecurityService provides security-related operations
 type SecurityService struct {
-	scanRepo             repositories.SecurityScanRepositoryInterface
-	vulnerabilityRepo    repositories.VulnerabilityRepositoryInterface
-	auditRepo            repositories.AuditLogRepositoryInterface
-	vulnerabilityScanner *VulnerabilityScanner
+	scanRepo          repositories.SecurityScanRepositoryInterface
+	vulnerabilityRepo repositories.VulnerabilityRepositoryInterface
+	auditWriter       *AuditWriter
+	scanners          map[models.ScanType]Scanner
 }
 
 // NewSecurityService creates a new security service
 func NewSecurityService(
 	scanRepo repositories.SecurityScanRepositoryInterface,
 	vulnerabilityRepo repositories.VulnerabilityRepositoryInterface,
-	auditRepo repositories.AuditLogRepositoryInterface,
+	auditWriter *AuditWriter,
 ) *SecurityService {
+	vulnerabilityScanner := NewVulnerabilityScanner()
 	return &SecurityService{
-		scanRepo:             scanRepo,
-		vulnerabilityRepo:    vulnerabilityRepo,
-		auditRepo:            auditRepo,
-		vulnerabilityScanner: NewVulnerabilityScanner(),
+		scanRepo:          scanRepo,
+		vulnerabilityRepo: vulnerabilityRepo,
+		auditWriter:       auditWriter,
+		scanners: map[models.ScanType]Scanner{
+			models.ScanTypeInfrastructure: ScannerFunc(vulnerabilityScanner.ScanInfrastructure),
+			models.ScanTypeApplication:    ScannerFunc(vulnerabilityScanner.ScanApplication),
+			models.ScanTypeContainer:      ScannerFunc(vulnerabilityScanner.ScanContainer),
+			models.ScanTypeNetwork:        ScannerFunc(vulnerabilityScanner.ScanNetwork),
+		},
 	}
 }
 
+// SetScanner sets the scanner that runs scans of a type, replacing the simulated scanner
+func (s *SecurityService) SetScanner(scanType models.ScanType, scanner Scanner) {
+	s.scanners[scanType] = scanner
+}
+
 // CreateScan creates a new security scan
 func (s *SecurityService) CreateScan(ctx context.Context, userID, organizationID string, req models.CreateScanRequest) (*models.SecurityScan, error) {
 	scan := &models.SecurityScan{
@@ -42,7 +53,7 @@ func (s *SecurityService) CreateScan(ctx context.Context, userID, organizationID
 		UserID:         userID,
 		Name:           req.Name,
 		Type:           req.Type,
-		Status:         models.ScanStatusPending,
+		Status:         models.ScanStatusQueued,
 		TargetType:     req.TargetType,
 		TargetID:       req.TargetID,
 		TargetName:     req.TargetName,
@@ -96,6 +107,41 @@ func (s *SecurityService) GetVulnerabilities(ctx context.Context, organizationID
 	return vulnerabilities, total, nil
 }
 
+// GetScanFindings retrieves a page of the vulnerabilities a scan found, with the scan and the
+// number of its findings of each severity. The severity counts honor the query's status but not
+// its severity, so they describe every page of findings with that status. A finding belongs to
+// the latest scan that found it, so an earlier scan only lists what later scans did not find again.
+func (s *SecurityService) GetScanFindings(ctx context.Context, organizationID, scanID string, query models.VulnerabilityQuery) (*models.ScanFindings, error) {
+	scan, err := s.scanRepo.GetByID(ctx, organizationID, scanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scan: %w", err)
+	}
+
+	query.ScanID = &scan.ID
+	findings, total, err := s.vulnerabilityRepo.Query(ctx, organizationID, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query scan findings: %w", err)
+	}
+
+	counts, err := s.vulnerabilityRepo.GetScanCountsBySeverity(ctx, organizationID, scan.ID, query.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scan findings: %w", err)
+	}
+
+	if findings == nil {
+		findings = []*models.Vulnerability{}
+	}
+
+	return &models.ScanFindings{
+		Scan:           scan,
+		Findings:       findings,
+		Total:          total,
+		Limit:          query.Limit,
+		Offset:         query.Offset,
+		SeverityCounts: counts,
+	}, nil
+}
+
 // GetVulnerability retrieves a specific vulnerability
 func (s *SecurityService) GetVulnerability(ctx context.Context, organizationID, vulnerabilityID string) (*models.Vulnerability, error) {
 	vulnerability, err := s.vulnerabilityRepo.GetByID(ctx, organizationID, vulnerabilityID)
@@ -105,13 +151,18 @@ func (s *SecurityService) GetVulnerability(ctx context.Context, organizationID,
 	return vulnerability, nil
 }
 
-// UpdateVulnerability updates a vulnerability
+// UpdateVulnerability updates a vulnerability, recording its move to a new status
 func (s *SecurityService) UpdateVulnerability(ctx context.Context, userID, organizationID, vulnerabilityID string, req models.UpdateVulnerabilityRequest) (*models.Vulnerability, error) {
 	vulnerability, err := s.vulnerabilityRepo.GetByID(ctx, organizationID, vulnerabilityID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get vulnerability: %w", err)
 	}
 
+	oldStatus := vulnerability.Status
+	if req.Status != oldStatus && !canTransitionVulnerability(oldStatus, req.Status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidVulnerabilityTransition, oldStatus, req.Status)
+	}
+
 	// Update fields
 	vulnerability.Status = req.Status
 	if req.Recommendation != nil {
@@ -122,19 +173,32 @@ func (s *SecurityService) UpdateVulnerability(ctx context.Context, userID, organ
 	}
 	vulnerability.UpdatedAt = time.Now()
 
-	// Mark as resolved if status is resolved
-	if req.Status == models.VulnStatusResolved {
-		now := time.Now()
-		vulnerability.ResolvedAt = &now
+	// Fixed and resolved findings are closed; reopened findings are no longer resolved
+	if req.Status != oldStatus {
+		switch {
+		case req.Status == models.VulnStatusFixed || req.Status == models.VulnStatusResolved:
+			now := time.Now()
+			vulnerability.ResolvedAt = &now
+		case req.Status.IsActive():
+			vulnerability.ResolvedAt = nil
+		}
 	}
 
 	if err := s.vulnerabilityRepo.Update(ctx, vulnerability); err != nil {
 		return nil, fmt.Errorf("failed to update vulnerability: %w", err)
 	}
 
+	if req.Status != oldStatus {
+		var changedBy *string
+		if userID != "" {
+			changedBy = &userID
+		}
+		s.recordStatusChange(ctx, vulnerability, &oldStatus, changedBy, nil, req.Reason)
+	}
+
 	// Log audit event
 	s.logAuditEvent(ctx, userID, organizationID, "vulnerability_updated", "vulnerability", vulnerabilityID, map[string]interface{}{
-		"old_status": vulnerability.Status,
+		"old_status": oldStatus,
 		"new_status": req.Status,
 		"title":      vulnerability.Title,
 	})
@@ -208,17 +272,10 @@ func (s *SecurityService) executeScan(ctx context.Context, scan *models.Security
 	var vulnerabilities []*models.Vulnerability
 	var err error
 
-	// Execute scan based on type
-	switch scan.Type {
-	case models.ScanTypeInfrastructure:
-		vulnerabilities, err = s.vulnerabilityScanner.ScanInfrastructure(ctx, scan)
-	case models.ScanTypeApplication:
-		vulnerabilities, err = s.vulnerabilityScanner.ScanApplication(ctx, scan)
-	case models.ScanTypeContainer:
-		vulnerabilities, err = s.vulnerabilityScanner.ScanContainer(ctx, scan)
-	case models.ScanTypeNetwork:
-		vulnerabilities, err = s.vulnerabilityScanner.ScanNetwork(ctx, scan)
-	default:
+	// Execute scan with the scanner for its type
+	if scanner, ok := s.scanners[scan.Type]; ok {
+		vulnerabilities, err = scanner.Scan(ctx, scan)
+	} else {
 		err = fmt.Errorf("unsupported scan type: %s", scan.Type)
 	}
 
@@ -231,12 +288,8 @@ func (s *SecurityService) executeScan(ctx context.Context, scan *models.Security
 	} else {
 		log.Printf("Scan completed successfully: %s", scan.ID)
 
-		// Save vulnerabilities
-		for _, vuln := range vulnerabilities {
-			if err := s.vulnerabilityRepo.Create(ctx, vuln); err != nil {
-				log.Printf("Failed to save vulnerability: %v", err)
-			}
-		}
+		// Save vulnerabilities, merging findings seen by earlier scans
+		vulnerabilities = s.recordFindings(ctx, scan, vulnerabilities)
 
 		// Update scan with results
 		scan.Status = models.ScanStatusCompleted
@@ -349,7 +402,7 @@ func (s *SecurityService) getSecurityTrends(ctx context.Context, organizationID
 
 // logAuditEvent logs a security-related audit event
 func (s *SecurityService) logAuditEvent(ctx context.Context, userID, organizationID, action, resourceType, resourceID string, details map[string]interface{}) {
-	if s.auditRepo == nil {
+	if s.auditWriter == nil {
 		return
 	}
 
@@ -363,8 +416,7 @@ func (s *SecurityService) logAuditEvent(ctx context.Context, userID, organizatio
 		Details:        details,
 		CreatedAt:      time.Now(),
 	}
+	AuditContextFromContext(ctx).apply(auditLog)
 
-	if err := s.auditRepo.Create(ctx, auditLog); err != nil {
-		log.Printf("Failed to log audit event: %v", err)
-	}
+	s.auditWriter.Write(auditLog)
 }
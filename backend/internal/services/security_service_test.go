@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// fakeScanRepo accepts every scan update
+type fakeScanRepo struct {
+	repositories.SecurityScanRepositoryInterface
+}
+
+func (r *fakeScanRepo) Update(ctx context.Context, scan *models.SecurityScan) error {
+	return nil
+}
+
+// recordingVulnerabilityRepo keeps the findings recorded through it
+type recordingVulnerabilityRepo struct {
+	repositories.VulnerabilityRepositoryInterface
+
+	findings []*models.Vulnerability
+}
+
+func (r *recordingVulnerabilityRepo) RecordFinding(ctx context.Context, vulnerability *models.Vulnerability) (*models.VulnerabilityStatus, error) {
+	r.findings = append(r.findings, vulnerability)
+	return nil, nil
+}
+
+func (r *recordingVulnerabilityRepo) ResolveUnseen(ctx context.Context, scan *models.SecurityScan, resolvedAt time.Time, reason string) (int64, error) {
+	return 0, nil
+}
+
+func (r *recordingVulnerabilityRepo) RecordStatusChange(ctx context.Context, change *models.VulnerabilityStatusChange) error {
+	return nil
+}
+
+// mockScanner returns fixed results and keeps the scans it ran
+type mockScanner struct {
+	vulnerabilities []*models.Vulnerability
+	err             error
+
+	scanned []*models.SecurityScan
+}
+
+func (m *mockScanner) Scan(ctx context.Context, scan *models.SecurityScan) ([]*models.Vulnerability, error) {
+	m.scanned = append(m.scanned, scan)
+	return m.vulnerabilities, m.err
+}
+
+func TestExecuteScanRunsTheScannerForItsType(t *testing.T) {
+	vulnerabilityRepo := &recordingVulnerabilityRepo{}
+	securityService := NewSecurityService(&fakeScanRepo{}, vulnerabilityRepo, nil)
+
+	scan := &models.SecurityScan{ID: "scan-1", OrganizationID: "org-1", Type: models.ScanTypeContainer, TargetID: "image-1"}
+	scanner := &mockScanner{vulnerabilities: []*models.Vulnerability{
+		newScanFinding(scan, "Outdated OpenSSL", "CVE-2024-0001", models.VulnSeverityHigh),
+		newScanFinding(scan, "Root user", "Container runs as root", models.VulnSeverityMedium),
+	}}
+	unused := &mockScanner{}
+	securityService.SetScanner(models.ScanTypeContainer, scanner)
+	securityService.SetScanner(models.ScanTypeInfrastructure, unused)
+
+	securityService.executeScan(context.Background(), scan)
+
+	if len(scanner.scanned) != 1 || scanner.scanned[0] != scan {
+		t.Fatal("the container scanner did not run the scan")
+	}
+	if len(unused.scanned) != 0 {
+		t.Error("the infrastructure scanner ran a container scan")
+	}
+	if scan.Status != models.ScanStatusCompleted {
+		t.Errorf("scan status = %s, want %s", scan.Status, models.ScanStatusCompleted)
+	}
+	if len(vulnerabilityRepo.findings) != 2 {
+		t.Errorf("recorded %d findings, want 2", len(vulnerabilityRepo.findings))
+	}
+	if scan.Summary == nil || scan.Summary.TotalVulnerabilities != 2 {
+		t.Errorf("scan summary = %+v, want 2 vulnerabilities", scan.Summary)
+	}
+}
+
+func TestExecuteScanFailsWhenTheScannerFails(t *testing.T) {
+	vulnerabilityRepo := &recordingVulnerabilityRepo{}
+	securityService := NewSecurityService(&fakeScanRepo{}, vulnerabilityRepo, nil)
+	securityService.SetScanner(models.ScanTypeInfrastructure, &mockScanner{err: errors.New("provider unavailable")})
+
+	scan := &models.SecurityScan{ID: "scan-1", OrganizationID: "org-1", Type: models.ScanTypeInfrastructure}
+	securityService.executeScan(context.Background(), scan)
+
+	if scan.Status != models.ScanStatusFailed {
+		t.Errorf("scan status = %s, want %s", scan.Status, models.ScanStatusFailed)
+	}
+	if scan.ErrorMessage == nil || *scan.ErrorMessage != "provider unavailable" {
+		t.Errorf("scan error = %v, want provider unavailable", scan.ErrorMessage)
+	}
+	if len(vulnerabilityRepo.findings) != 0 {
+		t.Errorf("recorded %d findings from a failed scan, want none", len(vulnerabilityRepo.findings))
+	}
+}
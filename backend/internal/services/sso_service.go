@@ -2,9 +2,8 @@ package services
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -19,12 +18,20 @@ import (
 	"golang.org/x/oauth2"
 )
 
+// githubEmailsURL lists the email addresses of the authenticated GitHub user
+const githubEmailsURL = "https://api.github.com/user/emails"
+
+// ErrSSOEmailNotVerified is returned when an SSO login's email belongs to an existing account but
+// the provider has not verified that the user owns it, so the login cannot be linked to the account
+var ErrSSOEmailNotVerified = errors.New("SSO email address is not verified")
+
 type SSOService struct {
 	config      *config.Config
 	userRepo    *repositories.UserRepository
 	orgRepo     *repositories.OrganizationRepository
-	authService *AuthService
-	jwtService  *JWTService
+	authService  *AuthService
+	jwtService   *JWTService
+	stateService *OAuthStateService
 }
 
 func NewSSOService(
@@ -33,13 +40,15 @@ func NewSSOService(
 	orgRepo *repositories.OrganizationRepository,
 	authService *AuthService,
 	jwtService *JWTService,
+	stateService *OAuthStateService,
 ) *SSOService {
 	return &SSOService{
-		config:      cfg,
-		userRepo:    userRepo,
-		orgRepo:     orgRepo,
-		authService: authService,
-		jwtService:  jwtService,
+		config:       cfg,
+		userRepo:     userRepo,
+		orgRepo:      orgRepo,
+		authService:  authService,
+		jwtService:   jwtService,
+		stateService: stateService,
 	}
 }
 
@@ -93,28 +102,39 @@ func (s *SSOService) GetSSOConfig() *models.SSOConfigResponse {
 	}
 }
 
-// GetOAuthAuthURL generates an OAuth authorization URL
-func (s *SSOService) GetOAuthAuthURL(provider string, state string) (string, error) {
+// GetOAuthAuthURL generates an OAuth authorization URL and returns it with its state. The state is
+// stored server-side together with the organization the user should join, and is checked when the
+// provider calls back. It must also be kept in the browser that starts the login.
+func (s *SSOService) GetOAuthAuthURL(ctx context.Context, provider string, organizationID string) (string, string, error) {
 	oauthConfig, err := s.getOAuthConfig(provider)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
-	if state == "" {
-		state = s.generateState()
+	state, err := s.stateService.CreateState(ctx, strings.ToLower(provider), organizationID)
+	if err != nil {
+		return "", "", err
 	}
 
 	authURL := oauthConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
-	return authURL, nil
+	return authURL, state, nil
 }
 
-// HandleOAuthCallback processes OAuth callback and creates/logs in user
-func (s *SSOService) HandleOAuthCallback(ctx context.Context, provider, code, state string, organizationID string) (*models.LoginResponse, error) {
+// HandleOAuthCallback processes OAuth callback and creates/logs in user. browserState is the state
+// kept in the browser when the login was started.
+func (s *SSOService) HandleOAuthCallback(ctx context.Context, provider, code, state, browserState string) (*models.LoginResponse, error) {
 	oauthConfig, err := s.getOAuthConfig(provider)
 	if err != nil {
 		return nil, err
 	}
 
+	// Reject callbacks whose state we did not issue, to prevent login CSRF
+	storedState, err := s.stateService.ConsumeState(ctx, state, browserState, strings.ToLower(provider))
+	if err != nil {
+		return nil, err
+	}
+	organizationID := storedState.OrganizationID
+
 	// Exchange code for token
 	token, err := oauthConfig.Exchange(ctx, code)
 	if err != nil {
@@ -241,11 +261,11 @@ func (s *SSOService) getUserInfoFromProvider(ctx context.Context, provider, acce
 		return nil, err
 	}
 
-	return s.parseUserInfo(provider, body)
+	return s.parseUserInfo(ctx, provider, accessToken, body)
 }
 
 // parseUserInfo parses user information based on provider format
-func (s *SSOService) parseUserInfo(provider string, data []byte) (*models.SSOUserInfo, error) {
+func (s *SSOService) parseUserInfo(ctx context.Context, provider, accessToken string, data []byte) (*models.SSOUserInfo, error) {
 	var userInfo models.SSOUserInfo
 
 	switch strings.ToLower(provider) {
@@ -315,18 +335,25 @@ func (s *SSOService) parseUserInfo(provider string, data []byte) (*models.SSOUse
 			return nil, err
 		}
 
-		// GitHub might not return email in the user endpoint, need to fetch separately
-		if githubUser.Email == "" {
-			// For now, we'll use login@github.local as a fallback
-			githubUser.Email = fmt.Sprintf("%s@github.local", githubUser.Login)
+		// The user endpoint only returns the public email, which GitHub does not verify, so look
+		// up a verified one
+		email, err := s.fetchGitHubPrimaryEmail(ctx, accessToken, githubUser.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get GitHub email: %w", err)
+		}
+
+		verified := email != ""
+		if !verified {
+			// The account has no verified email addresses
+			email = fmt.Sprintf("%s@github.local", githubUser.Login)
 		}
 
 		userInfo = models.SSOUserInfo{
 			ID:            fmt.Sprintf("%d", githubUser.ID),
-			Email:         githubUser.Email,
+			Email:         email,
 			Name:          githubUser.Name,
 			AvatarURL:     githubUser.AvatarURL,
-			EmailVerified: false, // GitHub emails might not be verified
+			EmailVerified: verified,
 		}
 
 	default:
@@ -359,6 +386,57 @@ func (s *SSOService) parseUserInfo(provider string, data []byte) (*models.SSOUse
 	return &userInfo, nil
 }
 
+// fetchGitHubPrimaryEmail returns the user's primary email from the GitHub emails API, or "" when
+// the user has no verified address. The public profile email is preferred when it is one of the
+// user's verified addresses. Unverified addresses are never returned, since anyone can add them.
+func (s *SSOService) fetchGitHubPrimaryEmail(ctx context.Context, accessToken, publicEmail string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", githubEmailsURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to get GitHub emails, status: %d", resp.StatusCode)
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&emails); err != nil {
+		return "", err
+	}
+
+	for _, e := range emails {
+		if publicEmail != "" && strings.EqualFold(e.Email, publicEmail) && e.Verified {
+			return e.Email, nil
+		}
+	}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			return e.Email, nil
+		}
+	}
+	for _, e := range emails {
+		if e.Verified {
+			return e.Email, nil
+		}
+	}
+
+	return "", nil
+}
+
 // findOrCreateSSOUser finds existing user or creates new one for SSO login
 func (s *SSOService) findOrCreateSSOUser(ctx context.Context, provider string, userInfo *models.SSOUserInfo, organizationID string) (*models.User, error) {
 	// First try to find user by SSO provider and subject
@@ -370,6 +448,11 @@ func (s *SSOService) findOrCreateSSOUser(ctx context.Context, provider string, u
 	// If not found by SSO, try to find by email
 	user, err = s.userRepo.GetByEmail(ctx, userInfo.Email)
 	if err == nil {
+		// Only an address the provider verified proves the login belongs to the account's owner
+		if !userInfo.EmailVerified {
+			return nil, fmt.Errorf("%w: %s", ErrSSOEmailNotVerified, userInfo.Email)
+		}
+
 		// User exists with this email, link SSO account
 		ssoProvider := provider
 		ssoSubject := userInfo.ID
@@ -429,48 +512,10 @@ func (s *SSOService) findOrCreateSSOUser(ctx context.Context, provider string, u
 
 // getUserBySSOProvider finds a user by SSO provider and subject
 func (s *SSOService) getUserBySSOProvider(ctx context.Context, provider, subject string) (*models.User, error) {
-	// Since the user repository doesn't have this method, we'll implement it here
-	// by querying users and checking SSO fields
-	params := repositories.ListParams{
-		Limit:  100, // Should be enough for most cases
-		Offset: 0,
-	}
-	
-	users, err := s.userRepo.List(ctx, params)
-	if err != nil {
-		return nil, err
-	}
-	
-	for _, user := range users {
-		if user.SSOProvider != nil && user.SSOSubject != nil &&
-			*user.SSOProvider == provider && *user.SSOSubject == subject {
-			return user, nil
-		}
-	}
-	
-	return nil, fmt.Errorf("user not found with SSO provider %s and subject %s", provider, subject)
+	return s.userRepo.GetBySSOProviderSubject(ctx, provider, subject)
 }
 
 // updateUserSSOInfo updates the SSO information for a user
 func (s *SSOService) updateUserSSOInfo(ctx context.Context, userID, provider, subject string) error {
-	// Since the user repository doesn't have this method, we'll need to add it
-	// For now, we'll implement a basic version using the existing Update method
-	user, err := s.userRepo.GetByID(ctx, userID)
-	if err != nil {
-		return err
-	}
-	
-	ssoProvider := provider
-	ssoSubject := subject
-	user.SSOProvider = &ssoProvider
-	user.SSOSubject = &ssoSubject
-	
-	return s.userRepo.Update(ctx, user)
-}
-
-// generateState generates a random state parameter for OAuth
-func (s *SSOService) generateState() string {
-	b := make([]byte, 32)
-	rand.Read(b)
-	return base64.URLEncoding.EncodeToString(b)
+	return s.userRepo.UpdateSSOInfo(ctx, userID, provider, subject)
 }
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+)
+
+// Statistics captured in daily snapshots
+const (
+	StatTotalResources        = "total_resources"
+	StatActiveInstances       = "active_instances"
+	StatNetworks              = "networks"
+	StatComplianceScore       = "compliance_score"
+	StatActiveDeployments     = "active_deployments"
+	StatDeploymentSuccessRate = "deployment_success_rate"
+	StatFailedDeployments     = "failed_deployments"
+	StatAvgDeployTime         = "avg_deploy_time"
+)
+
+// Stat trends
+const (
+	StatTrendUp     = "up"
+	StatTrendDown   = "down"
+	StatTrendStable = "stable"
+)
+
+// ErrInvalidStatPeriod is returned when a comparison period is not supported
+var ErrInvalidStatPeriod = errors.New("invalid stat period")
+
+// DefaultStatPeriod is the period stats are compared over when none is given
+const DefaultStatPeriod = "week"
+
+// statPeriods maps the supported comparison periods to their length in days
+var statPeriods = map[string]int{
+	"day":  1,
+	"week": 7,
+}
+
+// statsListLimit bounds how many rows are read when computing statistics
+const statsListLimit = 1000
+
+// StatChange describes how a statistic changed since the previous period
+type StatChange struct {
+	Change string `json:"change"`
+	Trend  string `json:"trend"`
+}
+
+// StatsService computes organization statistics and their change over time from daily snapshots
+type StatsService struct {
+	repoManager       *repositories.RepositoryManager
+	complianceService *ComplianceService
+}
+
+// NewStatsService creates a new stats service
+func NewStatsService(repoManager *repositories.RepositoryManager, complianceService *ComplianceService) *StatsService {
+	return &StatsService{
+		repoManager:       repoManager,
+		complianceService: complianceService,
+	}
+}
+
+// InfrastructureStatValues computes the infrastructure statistics, including the current
+// compliance score, from an organization's resources
+func (s *StatsService) InfrastructureStatValues(ctx context.Context, orgID string, infrastructures []*models.Infrastructure) (map[string]float64, error) {
+	values := map[string]float64{
+		StatTotalResources:  float64(len(infrastructures)),
+		StatActiveInstances: 0,
+		StatNetworks:        0,
+	}
+
+	for _, infra := range infrastructures {
+		if infra.Status == models.InfraStatusRunning {
+			values[StatActiveInstances]++
+		}
+		if infra.Type == models.InfraTypeNetwork {
+			values[StatNetworks]++
+		}
+	}
+
+	score, _, err := s.complianceService.GetCurrentComplianceScore(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	values[StatComplianceScore] = score
+
+	return values, nil
+}
+
+// DeploymentStatValues computes the deployment statistics from an organization's deployments
+func DeploymentStatValues(deployments []*models.Deployment) map[string]float64 {
+	var active, completed, failed, timed int
+	var totalMinutes float64
+
+	for _, deployment := range deployments {
+		switch deployment.Status {
+		case models.DeploymentStatusRunning, models.DeploymentStatusPending:
+			active++
+		case models.DeploymentStatusCompleted:
+			completed++
+			if deployment.StartedAt != nil && deployment.CompletedAt != nil {
+				totalMinutes += deployment.CompletedAt.Sub(*deployment.StartedAt).Minutes()
+				timed++
+			}
+		case models.DeploymentStatusFailed:
+			failed++
+		}
+	}
+
+	values := map[string]float64{
+		StatActiveDeployments:     float64(active),
+		StatDeploymentSuccessRate: 0,
+		StatFailedDeployments:     float64(failed),
+		StatAvgDeployTime:         0,
+	}
+	if len(deployments) > 0 {
+		values[StatDeploymentSuccessRate] = float64(completed) / float64(len(deployments)) * 100
+	}
+	if timed > 0 {
+		values[StatAvgDeployTime] = math.Floor(totalMinutes / float64(timed))
+	}
+
+	return values
+}
+
+// CompareWithPeriod compares current statistic values with the latest snapshot from at least one
+// period ago. Statistics without an earlier snapshot are reported as stable.
+func (s *StatsService) CompareWithPeriod(ctx context.Context, orgID, period string, current map[string]float64) (map[string]StatChange, error) {
+	day, err := comparisonDay(period)
+	if err != nil {
+		return nil, err
+	}
+
+	previous, err := s.repoManager.StatSnapshot.GetLatestOnOrBefore(ctx, orgID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]StatChange, len(current))
+	for metric, value := range current {
+		before, ok := previous[metric]
+		if !ok {
+			changes[metric] = StatChange{Change: "0%", Trend: StatTrendStable}
+			continue
+		}
+		changes[metric] = statChange(before, value)
+	}
+
+	return changes, nil
+}
+
+// StatsVersion identifies the inputs to an organization's statistics that do not come from its
+// resources: the snapshot they are compared with and the current compliance score. It changes
+// whenever those do, so it can be used to derive cache validators.
+func (s *StatsService) StatsVersion(ctx context.Context, orgID, period string) (string, error) {
+	day, err := comparisonDay(period)
+	if err != nil {
+		return "", err
+	}
+
+	snapshotDay := ""
+	latest, err := s.repoManager.StatSnapshot.GetLatestDayOnOrBefore(ctx, orgID, day)
+	if err != nil {
+		return "", err
+	}
+	if latest != nil {
+		snapshotDay = latest.Format("2006-01-02")
+	}
+
+	score, _, err := s.complianceService.GetCurrentComplianceScore(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s:%s:%g", day.Format("2006-01-02"), snapshotDay, score), nil
+}
+
+// comparisonDay returns the day whose snapshot statistics are compared with for a period
+func comparisonDay(period string) (time.Time, error) {
+	if period == "" {
+		period = DefaultStatPeriod
+	}
+	days, ok := statPeriods[period]
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidStatPeriod, period)
+	}
+	return statDay(time.Now()).AddDate(0, 0, -days), nil
+}
+
+// CaptureSnapshots records today's statistics for every organization
+func (s *StatsService) CaptureSnapshots(ctx context.Context) error {
+	params := repositories.DefaultListParams()
+	params.Limit = 100
+
+	for {
+		organizations, err := s.repoManager.Organization.List(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to list organizations: %w", err)
+		}
+
+		for _, organization := range organizations {
+			if err := s.captureOrganization(ctx, organization.ID); err != nil {
+				log.Printf("Failed to capture stat snapshots for organization %s: %v", organization.ID, err)
+			}
+		}
+
+		if len(organizations) < params.Limit {
+			return nil
+		}
+		params.Offset += params.Limit
+	}
+}
+
+// captureOrganization records today's statistics for one organization
+func (s *StatsService) captureOrganization(ctx context.Context, orgID string) error {
+	infrastructures, err := s.repoManager.Infrastructure.List(ctx, orgID, repositories.ListParams{Limit: statsListLimit})
+	if err != nil {
+		return err
+	}
+	values, err := s.InfrastructureStatValues(ctx, orgID, infrastructures)
+	if err != nil {
+		return err
+	}
+
+	deployments, err := s.repoManager.Deployment.List(ctx, orgID, repositories.ListParams{Limit: statsListLimit})
+	if err != nil {
+		return err
+	}
+	for metric, value := range DeploymentStatValues(deployments) {
+		values[metric] = value
+	}
+
+	today := statDay(time.Now())
+	for metric, value := range values {
+		snapshot := &models.StatSnapshot{
+			OrganizationID: orgID,
+			Metric:         metric,
+			Value:          value,
+			CapturedOn:     today,
+		}
+		if err := s.repoManager.StatSnapshot.Save(ctx, snapshot); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// StartStatSnapshots periodically records today's statistics until ctx is cancelled. Snapshots are
+// kept per day, so later captures on the same day replace earlier ones.
+func (s *StatsService) StartStatSnapshots(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.CaptureSnapshots(ctx); err != nil {
+				log.Printf("Stat snapshot capture failed: %v", err)
+			}
+		}
+	}
+}
+
+// statChange formats the percentage change between two values
+func statChange(before, after float64) StatChange {
+	if before == 0 {
+		if after == 0 {
+			return StatChange{Change: "0%", Trend: StatTrendStable}
+		}
+		return StatChange{Change: "+100%", Trend: StatTrendUp}
+	}
+
+	percent := (after - before) / math.Abs(before) * 100
+	rounded := math.Round(percent)
+	switch {
+	case rounded > 0:
+		return StatChange{Change: fmt.Sprintf("+%.0f%%", rounded), Trend: StatTrendUp}
+	case rounded < 0:
+		return StatChange{Change: fmt.Sprintf("%.0f%%", rounded), Trend: StatTrendDown}
+	default:
+		return StatChange{Change: "0%", Trend: StatTrendStable}
+	}
+}
+
+// statDay truncates a time to its UTC day
+func statDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
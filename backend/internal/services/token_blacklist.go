@@ -7,6 +7,9 @@ import (
 	"time"
 )
 
+// TokenRotatedReason is the blacklist reason recorded for refresh tokens that were exchanged for new ones
+const TokenRotatedReason = "token_rotated"
+
 type TokenBlacklistService struct {
 	db *sql.DB
 }
@@ -40,6 +43,27 @@ func (s *TokenBlacklistService) BlacklistToken(ctx context.Context, tokenID, use
 	return nil
 }
 
+// BlacklistTokenOnce adds a token to the blacklist, reporting false when it was already
+// blacklisted. It lets single-use tokens be consumed exactly once.
+func (s *TokenBlacklistService) BlacklistTokenOnce(ctx context.Context, tokenID, userID, tokenType string, expiresAt time.Time, reason string) (bool, error) {
+	query := `
+		INSERT INTO token_blacklist (token_id, user_id, token_type, expires_at, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (token_id) DO NOTHING`
+
+	result, err := s.db.ExecContext(ctx, query, tokenID, userID, tokenType, expiresAt, reason)
+	if err != nil {
+		return false, fmt.Errorf("failed to blacklist token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
 // IsTokenBlacklisted checks if a token is blacklisted
 func (s *TokenBlacklistService) IsTokenBlacklisted(ctx context.Context, tokenID string) (bool, error) {
 	var exists bool
@@ -57,17 +81,86 @@ func (s *TokenBlacklistService) IsTokenBlacklisted(ctx context.Context, tokenID
 	return exists, nil
 }
 
-// BlacklistAllUserTokens blacklists all tokens for a specific user
-func (s *TokenBlacklistService) BlacklistAllUserTokens(ctx context.Context, userID string, reason string) error {
-	// This is a placeholder - in a real implementation, you'd need to track active tokens
-	// For now, we'll just add a record that can be used to invalidate future token validations
+// GetBlacklistReason returns the reason a token was blacklisted, and whether it is blacklisted at all
+func (s *TokenBlacklistService) GetBlacklistReason(ctx context.Context, tokenID string) (string, bool, error) {
+	var reason sql.NullString
+	query := `
+		SELECT reason FROM token_blacklist
+		WHERE token_id = $1 AND expires_at > NOW()`
+
+	err := s.db.QueryRowContext(ctx, query, tokenID).Scan(&reason)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to check token blacklist status: %w", err)
+	}
+
+	return reason.String, true, nil
+}
+
+// MarkTokenRotated blacklists a refresh token as rotated. It returns false if the token
+// was already blacklisted, which means it is being presented a second time.
+func (s *TokenBlacklistService) MarkTokenRotated(ctx context.Context, tokenID, userID string, expiresAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO token_blacklist (token_id, user_id, token_type, expires_at, reason)
+		VALUES ($1, $2, 'refresh', $3, $4)
+		ON CONFLICT (token_id) DO NOTHING`
+
+	result, err := s.db.ExecContext(ctx, query, tokenID, userID, expiresAt, TokenRotatedReason)
+	if err != nil {
+		return false, fmt.Errorf("failed to blacklist rotated token: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+// RevokeRefreshTokenFamily invalidates every refresh token issued in the given family
+func (s *TokenBlacklistService) RevokeRefreshTokenFamily(ctx context.Context, familyID, userID string, expiresAt time.Time, reason string) error {
+	query := `
+		INSERT INTO refresh_token_families (family_id, user_id, expires_at, reason)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (family_id) DO NOTHING`
+
+	_, err := s.db.ExecContext(ctx, query, familyID, userID, expiresAt, reason)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+// IsRefreshTokenFamilyRevoked checks if a refresh token family has been revoked
+func (s *TokenBlacklistService) IsRefreshTokenFamilyRevoked(ctx context.Context, familyID string) (bool, error) {
+	var exists bool
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM refresh_token_families
+			WHERE family_id = $1 AND expires_at > NOW()
+		)`
+
+	err := s.db.QueryRowContext(ctx, query, familyID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("failed to check refresh token family status: %w", err)
+	}
+
+	return exists, nil
+}
+
+// BlacklistAllUserTokens invalidates every token issued to a user before now. The record must be
+// kept until the longest-lived of those tokens has expired.
+func (s *TokenBlacklistService) BlacklistAllUserTokens(ctx context.Context, userID string, expiresAt time.Time, reason string) error {
 	query := `
 		INSERT INTO token_blacklist (token_id, user_id, token_type, expires_at, reason)
 		VALUES ($1, $2, 'all', $3, $4)
 		ON CONFLICT (token_id) DO NOTHING`
 
-	tokenID := fmt.Sprintf("user_%s_all_tokens_%d", userID, time.Now().Unix())
-	expiresAt := time.Now().Add(24 * time.Hour) // Expire after 24 hours
+	tokenID := fmt.Sprintf("user_%s_all_tokens_%d", userID, time.Now().UnixNano())
 
 	_, err := s.db.ExecContext(ctx, query, tokenID, userID, expiresAt, reason)
 	if err != nil {
@@ -77,6 +170,23 @@ func (s *TokenBlacklistService) BlacklistAllUserTokens(ctx context.Context, user
 	return nil
 }
 
+// AreUserTokensRevoked checks if all of a user's tokens issued at or before issuedAt were blacklisted
+func (s *TokenBlacklistService) AreUserTokensRevoked(ctx context.Context, userID string, issuedAt time.Time) (bool, error) {
+	var revoked bool
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM token_blacklist
+			WHERE user_id = $1 AND token_type = 'all' AND blacklisted_at >= $2 AND expires_at > NOW()
+		)`
+
+	err := s.db.QueryRowContext(ctx, query, userID, issuedAt).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check user token revocation: %w", err)
+	}
+
+	return revoked, nil
+}
+
 // CleanupExpiredTokens removes expired tokens from the blacklist
 func (s *TokenBlacklistService) CleanupExpiredTokens(ctx context.Context) error {
 	query := `DELETE FROM token_blacklist WHERE expires_at < NOW()`
@@ -92,6 +202,11 @@ func (s *TokenBlacklistService) CleanupExpiredTokens(ctx context.Context) error
 	}
 
 	fmt.Printf("Cleaned up %d expired blacklisted tokens\n", rowsAffected)
+
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM refresh_token_families WHERE expires_at < NOW()`); err != nil {
+		return fmt.Errorf("failed to cleanup expired refresh token families: %w", err)
+	}
+
 	return nil
 }
 
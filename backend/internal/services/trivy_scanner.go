@@ -0,0 +1,287 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+)
+
+// Trivy scanner defaults
+const (
+	DefaultTrivyPath    = "trivy"
+	DefaultTrivyTimeout = 10 * time.Minute
+
+	// maxFindingReferences caps the reference links stored with each finding
+	maxFindingReferences = 5
+
+	// maxFindingTitleLength is the longest title a vulnerability can be stored with
+	maxFindingTitleLength = 500
+)
+
+// trivySeverities maps Trivy's severities to vulnerability severities
+var trivySeverities = map[string]models.VulnerabilitySeverity{
+	"CRITICAL": models.VulnSeverityCritical,
+	"HIGH":     models.VulnSeverityHigh,
+	"MEDIUM":   models.VulnSeverityMedium,
+	"LOW":      models.VulnSeverityLow,
+}
+
+// trivyReport is the part of Trivy's JSON report the scanner reads
+type trivyReport struct {
+	Results []struct {
+		Target          string `json:"Target"`
+		Vulnerabilities []struct {
+			VulnerabilityID  string               `json:"VulnerabilityID"`
+			PkgName          string               `json:"PkgName"`
+			InstalledVersion string               `json:"InstalledVersion"`
+			FixedVersion     string               `json:"FixedVersion"`
+			PrimaryURL       string               `json:"PrimaryURL"`
+			Title            string               `json:"Title"`
+			Description      string               `json:"Description"`
+			Severity         string               `json:"Severity"`
+			References       []string             `json:"References"`
+			CVSS             map[string]trivyCVSS `json:"CVSS"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+// trivyCVSS is a CVSS score Trivy reports from one source, e.g. NVD or a distribution vendor
+type trivyCVSS struct {
+	V3Score float64 `json:"V3Score"`
+}
+
+// commandRunner runs a command and returns its standard output
+type commandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// TrivyScanner scans container images for known CVEs with the Trivy CLI. The image is the scan's
+// "image" configuration value, or its target name when that is not set.
+type TrivyScanner struct {
+	path    string
+	timeout time.Duration
+	run     commandRunner
+}
+
+// NewTrivyScanner creates a scanner that runs the Trivy binary at path
+func NewTrivyScanner(path string, timeout time.Duration) *TrivyScanner {
+	if path == "" {
+		path = DefaultTrivyPath
+	}
+	if timeout <= 0 {
+		timeout = DefaultTrivyTimeout
+	}
+
+	return &TrivyScanner{
+		path:    path,
+		timeout: timeout,
+		run:     runCommand,
+	}
+}
+
+// trivySBOMFormats maps SBOM formats to the Trivy output formats that produce them
+var trivySBOMFormats = map[models.SBOMFormat]string{
+	models.SBOMFormatCycloneDX: "cyclonedx",
+	models.SBOMFormatSPDX:      "spdx-json",
+}
+
+// Scan runs Trivy against the scan's image and reports each CVE it finds as a vulnerability
+func (ts *TrivyScanner) Scan(ctx context.Context, scan *models.SecurityScan) ([]*models.Vulnerability, error) {
+	image := scan.TargetName
+	if configured, ok := scan.Configuration["image"].(string); ok && configured != "" {
+		image = configured
+	}
+	if err := validateImage(image); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ts.timeout)
+	defer cancel()
+
+	output, err := ts.run(ctx, ts.path, "image",
+		"--format", "json",
+		"--quiet",
+		"--scanners", "vuln",
+		"--timeout", ts.timeout.String(),
+		image,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("trivy failed to scan %s: %w", image, err)
+	}
+
+	return ts.parseReport(scan, image, output, "")
+}
+
+// GenerateSBOM runs Trivy to list the components of a container image as an SBOM document
+func (ts *TrivyScanner) GenerateSBOM(ctx context.Context, image string, format models.SBOMFormat) ([]byte, error) {
+	trivyFormat, ok := trivySBOMFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported SBOM format: %s", format)
+	}
+	if err := validateImage(image); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ts.timeout)
+	defer cancel()
+
+	output, err := ts.run(ctx, ts.path, "image",
+		"--format", trivyFormat,
+		"--quiet",
+		"--timeout", ts.timeout.String(),
+		image,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("trivy failed to generate an SBOM of %s: %w", image, err)
+	}
+
+	return output, nil
+}
+
+// ScanSBOM runs Trivy against an SBOM document of an image, so the vulnerabilities found are in
+// exactly the components the SBOM lists
+func (ts *TrivyScanner) ScanSBOM(ctx context.Context, scan *models.SecurityScan, image string, document []byte) ([]*models.Vulnerability, error) {
+	file, err := os.CreateTemp("", "cloudweave-sbom-*.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SBOM file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(document)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write SBOM file: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ts.timeout)
+	defer cancel()
+
+	output, err := ts.run(ctx, ts.path, "sbom",
+		"--format", "json",
+		"--quiet",
+		"--scanners", "vuln",
+		"--timeout", ts.timeout.String(),
+		file.Name(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("trivy failed to scan the SBOM of %s: %w", image, err)
+	}
+
+	return ts.parseReport(scan, image, output, file.Name())
+}
+
+// parseReport converts a Trivy JSON report on an image into vulnerabilities. Occurrences of
+// sbomPath in result targets are replaced with the image, so findings are located the same way
+// whether the image or its SBOM was scanned.
+func (ts *TrivyScanner) parseReport(scan *models.SecurityScan, image string, output []byte, sbomPath string) ([]*models.Vulnerability, error) {
+	var report trivyReport
+	if err := json.Unmarshal(output, &report); err != nil {
+		return nil, fmt.Errorf("failed to parse trivy report: %w", err)
+	}
+
+	var vulnerabilities []*models.Vulnerability
+	for _, result := range report.Results {
+		target := result.Target
+		if sbomPath != "" {
+			target = strings.ReplaceAll(target, sbomPath, image)
+		}
+
+		for _, v := range result.Vulnerabilities {
+			severity, ok := trivySeverities[v.Severity]
+			if !ok {
+				severity = models.VulnSeverityInfo
+			}
+
+			title := v.Title
+			if title == "" {
+				title = v.VulnerabilityID
+			}
+			title = fmt.Sprintf("%s in %s %s", title, v.PkgName, v.InstalledVersion)
+			if runes := []rune(title); len(runes) > maxFindingTitleLength {
+				title = string(runes[:maxFindingTitleLength])
+			}
+
+			description := v.Description
+			if description == "" {
+				description = fmt.Sprintf("%s affects %s %s in %s", v.VulnerabilityID, v.PkgName, v.InstalledVersion, target)
+			}
+
+			vuln := newScanFinding(scan, title, description, severity)
+			vuln.ResourceName = image
+			vuln.Location = target + ": " + v.PkgName
+			if v.VulnerabilityID != "" {
+				vuln.CVEID = stringPtr(v.VulnerabilityID)
+			}
+			if score := trivyCVSSScore(v.CVSS); score > 0 {
+				vuln.CVSSScore = float64Ptr(score)
+			}
+
+			vuln.Recommendation = "No fixed version is available yet; monitor the advisory and consider an alternative package"
+			if v.FixedVersion != "" {
+				vuln.Recommendation = fmt.Sprintf("Upgrade %s to %s or later", v.PkgName, v.FixedVersion)
+			}
+
+			if v.PrimaryURL != "" {
+				vuln.References = append(vuln.References, v.PrimaryURL)
+			}
+			for _, ref := range v.References {
+				if len(vuln.References) >= maxFindingReferences {
+					break
+				}
+				if ref != v.PrimaryURL {
+					vuln.References = append(vuln.References, ref)
+				}
+			}
+			vuln.Tags = []string{"container", "cve", v.PkgName}
+
+			vulnerabilities = append(vulnerabilities, vuln)
+		}
+	}
+
+	return vulnerabilities, nil
+}
+
+// validateImage rejects image references Trivy cannot scan, including ones it would read as flags
+func validateImage(image string) error {
+	if image == "" || strings.HasPrefix(image, "-") {
+		return fmt.Errorf("%w: %q is not a container image", ErrScanUnsupported, image)
+	}
+	return nil
+}
+
+// trivyCVSSScore returns the CVSS v3 score Trivy reports, preferring NVD's over vendor scores
+func trivyCVSSScore(scores map[string]trivyCVSS) float64 {
+	if nvd, ok := scores["nvd"]; ok && nvd.V3Score > 0 {
+		return nvd.V3Score
+	}
+
+	var highest float64
+	for _, score := range scores {
+		highest = max(highest, score.V3Score)
+	}
+	return highest
+}
+
+// runCommand runs a command, including its standard error in the error when it fails
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
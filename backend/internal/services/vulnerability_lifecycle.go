@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cloudweave/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidVulnerabilityTransition is returned when a finding cannot move to the requested status
+var ErrInvalidVulnerabilityTransition = errors.New("invalid vulnerability status transition")
+
+// vulnerabilityTransitions lists the statuses a user can move a finding to from each status.
+// Closed findings can only be reopened; scans reopen fixed and resolved findings they find again.
+var vulnerabilityTransitions = map[models.VulnerabilityStatus][]models.VulnerabilityStatus{
+	models.VulnStatusOpen: {
+		models.VulnStatusAcknowledged, models.VulnStatusInProgress, models.VulnStatusFixed,
+		models.VulnStatusFalsePositive, models.VulnStatusIgnored, models.VulnStatusResolved,
+	},
+	models.VulnStatusAcknowledged: {
+		models.VulnStatusOpen, models.VulnStatusInProgress, models.VulnStatusFixed,
+		models.VulnStatusFalsePositive, models.VulnStatusIgnored, models.VulnStatusResolved,
+	},
+	models.VulnStatusInProgress: {
+		models.VulnStatusOpen, models.VulnStatusAcknowledged, models.VulnStatusFixed,
+		models.VulnStatusFalsePositive, models.VulnStatusIgnored, models.VulnStatusResolved,
+	},
+	models.VulnStatusFixed:         {models.VulnStatusOpen, models.VulnStatusResolved},
+	models.VulnStatusResolved:      {models.VulnStatusOpen},
+	models.VulnStatusFalsePositive: {models.VulnStatusOpen},
+	models.VulnStatusIgnored:       {models.VulnStatusOpen},
+}
+
+// canTransitionVulnerability reports whether a user can move a finding from one status to another
+func canTransitionVulnerability(from, to models.VulnerabilityStatus) bool {
+	for _, allowed := range vulnerabilityTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// vulnerabilityFingerprint identifies a finding across scans by its resource, its CVE (or title
+// when it has none) and its location on the resource
+func vulnerabilityFingerprint(vuln *models.Vulnerability) string {
+	identifier := vuln.Title
+	if vuln.CVEID != nil && *vuln.CVEID != "" {
+		identifier = *vuln.CVEID
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join([]string{vuln.ResourceType, vuln.ResourceID, identifier, vuln.Location}, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// recordFindings stores the findings of a completed scan, merging each into the finding with the
+// same fingerprint, and resolves the findings of earlier scans of the target it did not find
+// again. It returns the scan's findings without duplicates.
+func (s *SecurityService) recordFindings(ctx context.Context, scan *models.SecurityScan, vulnerabilities []*models.Vulnerability) []*models.Vulnerability {
+	seen := make(map[string]bool, len(vulnerabilities))
+	findings := make([]*models.Vulnerability, 0, len(vulnerabilities))
+	saved := true
+
+	for _, vuln := range vulnerabilities {
+		if vuln.Fingerprint == "" {
+			vuln.Fingerprint = vulnerabilityFingerprint(vuln)
+		}
+		if seen[vuln.Fingerprint] {
+			continue
+		}
+		seen[vuln.Fingerprint] = true
+		findings = append(findings, vuln)
+
+		previous, err := s.vulnerabilityRepo.RecordFinding(ctx, vuln)
+		if err != nil {
+			log.Printf("Failed to save vulnerability: %v", err)
+			saved = false
+			continue
+		}
+
+		switch {
+		case previous == nil:
+			s.recordStatusChange(ctx, vuln, nil, nil, &scan.ID, "Found by scan "+scan.Name)
+		case *previous != vuln.Status:
+			s.recordStatusChange(ctx, vuln, previous, nil, &scan.ID, "Found again by scan "+scan.Name)
+		}
+	}
+
+	// A finding that failed to save would look unseen, so only a fully saved scan resolves others
+	if !saved {
+		return findings
+	}
+
+	resolved, err := s.vulnerabilityRepo.ResolveUnseen(ctx, scan, time.Now(), "Not found by scan "+scan.Name)
+	if err != nil {
+		log.Printf("Failed to resolve findings not seen by scan %s: %v", scan.ID, err)
+	} else if resolved > 0 {
+		log.Printf("Resolved %d findings not seen by scan %s", resolved, scan.ID)
+	}
+
+	return findings
+}
+
+// recordStatusChange records a finding's move to its current status, logging rather than failing
+// when it cannot be recorded
+func (s *SecurityService) recordStatusChange(ctx context.Context, vuln *models.Vulnerability, from *models.VulnerabilityStatus, userID, scanID *string, reason string) {
+	change := &models.VulnerabilityStatusChange{
+		ID:              uuid.New().String(),
+		VulnerabilityID: vuln.ID,
+		OrganizationID:  vuln.OrganizationID,
+		FromStatus:      from,
+		ToStatus:        vuln.Status,
+		ChangedBy:       userID,
+		ScanID:          scanID,
+		Reason:          reason,
+		ChangedAt:       time.Now(),
+	}
+
+	if err := s.vulnerabilityRepo.RecordStatusChange(ctx, change); err != nil {
+		log.Printf("Failed to record status change of vulnerability %s: %v", vuln.ID, err)
+	}
+}
+
+// GetVulnerabilityHistory retrieves the status changes of a finding, newest first
+func (s *SecurityService) GetVulnerabilityHistory(ctx context.Context, organizationID, vulnerabilityID string) ([]*models.VulnerabilityStatusChange, error) {
+	if _, err := s.vulnerabilityRepo.GetByID(ctx, organizationID, vulnerabilityID); err != nil {
+		return nil, fmt.Errorf("failed to get vulnerability: %w", err)
+	}
+
+	changes, err := s.vulnerabilityRepo.GetStatusChanges(ctx, organizationID, vulnerabilityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vulnerability history: %w", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,330 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"cloudweave/internal/models"
+	"cloudweave/internal/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Webhook errors
+var (
+	ErrInvalidWebhook  = errors.New("invalid webhook")
+	ErrWebhookNotFound = errors.New("webhook not found")
+)
+
+// Webhook delivery retry defaults
+const (
+	DefaultWebhookMaxAttempts    = 6
+	DefaultWebhookInitialBackoff = 5 * time.Second
+	maxWebhookBackoff            = 10 * time.Minute
+)
+
+// Headers sent with every webhook request
+const (
+	WebhookEventHeader     = "X-CloudWeave-Event"
+	WebhookEventIDHeader   = "X-CloudWeave-Event-Id"
+	WebhookTimestampHeader = "X-CloudWeave-Timestamp"
+	WebhookSignatureHeader = "X-CloudWeave-Signature"
+)
+
+// WebhookService posts resource lifecycle events to the webhooks organizations register and
+// records every delivery. Each request is signed with the webhook's secret: the signature header
+// is "sha256=" followed by the hex HMAC-SHA256 of the timestamp header, a period and the body, so
+// receivers can verify both the sender and that the request is recent. A nil WebhookService emits
+// nothing.
+type WebhookService struct {
+	repo       repositories.WebhookRepositoryInterface
+	httpClient *http.Client
+
+	maxAttempts    int
+	initialBackoff time.Duration
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo repositories.WebhookRepositoryInterface) *WebhookService {
+	return &WebhookService{
+		repo:           repo,
+		httpClient:     newOutboundHTTPClient(10 * time.Second),
+		maxAttempts:    DefaultWebhookMaxAttempts,
+		initialBackoff: DefaultWebhookInitialBackoff,
+	}
+}
+
+// CreateWebhook validates and stores a new webhook, generating its signing secret if it has none
+func (s *WebhookService) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+
+	if webhook.ID == "" {
+		webhook.ID = uuid.New().String()
+	}
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+
+	return s.repo.Create(ctx, webhook)
+}
+
+// GetWebhooks retrieves an organization's webhooks
+func (s *WebhookService) GetWebhooks(ctx context.Context, orgID string) ([]*models.Webhook, error) {
+	return s.repo.List(ctx, orgID)
+}
+
+// DeleteWebhook deletes a webhook along with its deliveries
+func (s *WebhookService) DeleteWebhook(ctx context.Context, orgID, webhookID string) error {
+	if err := s.repo.Delete(ctx, webhookID, orgID); err != nil {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// GetDeliveries retrieves a webhook's recent deliveries, optionally only those with a status such
+// as dead_letter
+func (s *WebhookService) GetDeliveries(ctx context.Context, orgID, webhookID, status string, limit int) ([]*models.WebhookDelivery, error) {
+	if _, err := s.repo.GetByID(ctx, webhookID, orgID); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	return s.repo.ListDeliveries(ctx, orgID, webhookID, status, limit)
+}
+
+// TestWebhook posts a test event to a webhook once, whether or not it is enabled, and reports how
+// the endpoint responded. Test events are not recorded as deliveries.
+func (s *WebhookService) TestWebhook(ctx context.Context, orgID, webhookID string) (*models.WebhookTestResult, error) {
+	webhook, err := s.repo.GetByID(ctx, webhookID, orgID)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	event := &models.WebhookEvent{
+		ID:             uuid.New().String(),
+		Type:           models.WebhookEventTest,
+		OrganizationID: orgID,
+		CreatedAt:      time.Now(),
+		Data:           map[string]string{"webhookId": webhook.ID},
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	start := time.Now()
+	status, err := s.post(ctx, webhook, event.Type, event.ID, payload)
+
+	result := &models.WebhookTestResult{
+		Delivered:  err == nil,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if status != 0 {
+		result.ResponseStatus = &status
+	}
+	if err != nil {
+		message := err.Error()
+		result.Error = &message
+	}
+
+	return result, nil
+}
+
+// Emit posts an event to every enabled webhook of an organization subscribed to its type. Events
+// are delivered in the background, retried with exponential backoff, and kept as dead letters
+// once every attempt has failed; Emit itself only logs failures so the change that raised the
+// event is never failed by it.
+func (s *WebhookService) Emit(ctx context.Context, orgID, eventType string, data interface{}) {
+	if s == nil {
+		return
+	}
+
+	// Deliveries outlive the request that raised the event
+	ctx = context.WithoutCancel(ctx)
+
+	webhooks, err := s.repo.ListEnabled(ctx, orgID)
+	if err != nil {
+		log.Printf("Failed to list webhooks for %s event: %v", eventType, err)
+		return
+	}
+
+	event := &models.WebhookEvent{
+		ID:             uuid.New().String(),
+		Type:           eventType,
+		OrganizationID: orgID,
+		CreatedAt:      time.Now(),
+		Data:           data,
+	}
+	var payload []byte
+
+	for _, webhook := range webhooks {
+		if !webhook.AcceptsEvent(eventType) {
+			continue
+		}
+
+		if payload == nil {
+			if payload, err = json.Marshal(event); err != nil {
+				log.Printf("Failed to encode %s webhook event: %v", eventType, err)
+				return
+			}
+		}
+
+		delivery := &models.WebhookDelivery{
+			ID:             uuid.New().String(),
+			OrganizationID: orgID,
+			WebhookID:      webhook.ID,
+			EventID:        event.ID,
+			EventType:      eventType,
+			Payload:        payload,
+			Status:         models.WebhookDeliveryPending,
+		}
+		if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+			log.Printf("Failed to record webhook delivery of event %s: %v", event.ID, err)
+			continue
+		}
+
+		go s.deliver(ctx, webhook, delivery)
+	}
+}
+
+// deliver posts an event to a webhook, retrying failures with exponential backoff and recording
+// the outcome of each attempt
+func (s *WebhookService) deliver(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	backoff := s.initialBackoff
+
+	for {
+		delivery.Attempts++
+		status, err := s.post(ctx, webhook, delivery.EventType, delivery.EventID, delivery.Payload)
+
+		delivery.ResponseStatus = nil
+		if status != 0 {
+			delivery.ResponseStatus = &status
+		}
+		if err == nil {
+			now := time.Now()
+			delivery.Status = models.WebhookDeliveryDelivered
+			delivery.DeliveredAt = &now
+			delivery.LastError = nil
+		} else {
+			message := err.Error()
+			delivery.LastError = &message
+			if delivery.Attempts >= s.maxAttempts {
+				delivery.Status = models.WebhookDeliveryDeadLetter
+				log.Printf("Giving up delivering event %s to webhook %s after %d attempts: %v", delivery.EventID, webhook.ID, delivery.Attempts, err)
+			}
+		}
+
+		if updateErr := s.repo.UpdateDelivery(ctx, delivery); updateErr != nil {
+			log.Printf("Failed to update webhook delivery %s: %v", delivery.ID, updateErr)
+		}
+
+		if delivery.Status != models.WebhookDeliveryPending {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxWebhookBackoff {
+			backoff = maxWebhookBackoff
+		}
+	}
+}
+
+// post makes a single signed request to a webhook, returning the response status if there was one
+func (s *WebhookService) post(ctx context.Context, webhook *models.Webhook, eventType, eventID string, payload []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CloudWeave-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, eventType)
+	req.Header.Set(WebhookEventIDHeader, eventID)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, payload))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature header value of a webhook request body sent at a
+// timestamp, in Unix seconds
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// generateWebhookSecret creates a random signing secret
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// validateWebhook checks a webhook's URL and event filter
+func validateWebhook(webhook *models.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: webhooks need an http or https URL", ErrInvalidWebhook)
+	}
+	if err := checkURLHost(u.Hostname()); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+
+	for _, event := range webhook.Events {
+		known := false
+		for _, supported := range models.WebhookEvents {
+			if event == supported {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: unsupported event %q", ErrInvalidWebhook, event)
+		}
+	}
+
+	return nil
+}
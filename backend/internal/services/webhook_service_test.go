@@ -0,0 +1,32 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"cloudweave/internal/models"
+)
+
+func TestValidateWebhookRejectsInternalHosts(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{"https://hooks.example.com/cloudweave", true},
+		{"http://169.254.169.254/latest/meta-data/iam/security-credentials/", false},
+		{"http://127.0.0.1:6379/", false},
+		{"http://10.0.0.5/internal", false},
+		{"http://[::1]:8080/", false},
+		{"ftp://hooks.example.com/", false},
+	}
+
+	for _, tt := range tests {
+		err := validateWebhook(&models.Webhook{URL: tt.url})
+		if tt.valid && err != nil {
+			t.Errorf("validateWebhook(%s) returned %v, want nil", tt.url, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidWebhook) {
+			t.Errorf("validateWebhook(%s) returned %v, want ErrInvalidWebhook", tt.url, err)
+		}
+	}
+}
@@ -2,14 +2,25 @@ package services
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"cloudweave/internal/models"
+
 	"github.com/gorilla/websocket"
 )
 
+// Default WebSocket connection limits
+const (
+	DefaultWebSocketSendBuffer     = 256
+	DefaultWebSocketMaxConnsPerOrg = 100
+)
+
 // WebSocketService handles real-time communication
 type WebSocketService struct {
 	clients    map[*Client]bool
@@ -17,16 +28,42 @@ type WebSocketService struct {
 	register   chan *Client
 	unregister chan *Client
 	mutex      sync.RWMutex
+
+	// sendBuffer is how many messages may queue for a client before it is dropped as too slow
+	sendBuffer int
+	// maxConnsPerOrg caps concurrent connections per organization; zero means unlimited
+	maxConnsPerOrg int
+	orgConns       map[string]int
+	connsMu        sync.Mutex
+
+	droppedClients atomic.Int64
+
+	// stop is closed to shut the hub down
+	stop     chan struct{}
+	stopOnce sync.Once
 }
 
 // Client represents a WebSocket client connection
 type Client struct {
-	ID       string
-	UserID   string
-	Conn     *websocket.Conn
-	Send     chan []byte
-	Service  *WebSocketService
-	UserType string // "user", "system", etc.
+	ID             string
+	UserID         string
+	OrganizationID string
+	Conn           *websocket.Conn
+	Send           chan []byte
+	Service        *WebSocketService
+	UserType       string // "user", "system", etc.
+
+	// DeploymentID is set when the client is tailing a single deployment's logs
+	DeploymentID string
+
+	// topics the client has subscribed to, scoped to its organization
+	topics   map[string]bool
+	topicsMu sync.RWMutex
+
+	// closed is set once Send has been closed so no further messages are queued
+	closed      bool
+	sendMu      sync.Mutex
+	releaseOnce sync.Once
 }
 
 // WebSocketMessage represents a message sent through WebSocket
@@ -36,28 +73,83 @@ type WebSocketMessage struct {
 	Timestamp string      `json:"timestamp"`
 	ID        string      `json:"id,omitempty"`
 	UserID    string      `json:"userId,omitempty"`
-	Target    string      `json:"target,omitempty"` // "all", "user", "organization"
+	Target    string      `json:"target,omitempty"` // "all", "user"
+
+	DeploymentID string `json:"deploymentId,omitempty"`
+
+	Topic string `json:"topic,omitempty"`
+
+	// OrganizationID scopes delivery; messages are only sent to connections authenticated to it
+	OrganizationID string `json:"-"`
+}
+
+// subscriptionRequest is sent by clients to manage their topic subscriptions
+type subscriptionRequest struct {
+	Action string `json:"action"`
+	Topic  string `json:"topic"`
+}
+
+// Subscription actions
+const (
+	SubscriptionActionSubscribe   = "subscribe"
+	SubscriptionActionUnsubscribe = "unsubscribe"
+)
+
+// Topic kinds clients can subscribe to, written as "<kind>:<id>"
+const (
+	TopicDeployments    = "deployments"
+	TopicInfrastructure = "infrastructure"
+	TopicMetrics        = "metrics"
+	TopicAlerts         = "alerts"
+	TopicOrganization   = "organizations"
+)
+
+// maxClientTopics bounds how many topics one connection can subscribe to
+const maxClientTopics = 100
+
+// Topic builds a subscription topic name
+func Topic(kind, id string) string {
+	return kind + ":" + id
 }
 
 // Message types
 const (
-	MessageTypeDeploymentStatus = "deployment_status"
-	MessageTypeInfrastructure   = "infrastructure_update"
-	MessageTypeMetrics          = "metrics_update"
-	MessageTypeAlert            = "alert_notification"
-	MessageTypeSystem           = "system_message"
-	MessageTypeError            = "error"
-	MessageTypePing             = "ping"
-	MessageTypePong             = "pong"
+	MessageTypeDeploymentStatus   = "deployment_status"
+	MessageTypeDeploymentLog      = "deployment_log"
+	MessageTypeDeploymentFinished = "deployment_finished"
+	MessageTypeInfrastructure     = "infrastructure_update"
+	MessageTypeMetrics            = "metrics_update"
+	MessageTypeAlert              = "alert_notification"
+	MessageTypeSystem             = "system_message"
+	MessageTypeError              = "error"
+	MessageTypePing               = "ping"
+	MessageTypePong               = "pong"
+	MessageTypeSubscribed         = "subscribed"
+	MessageTypeUnsubscribed       = "unsubscribed"
 )
 
 // NewWebSocketService creates a new WebSocket service
 func NewWebSocketService() *WebSocketService {
 	return &WebSocketService{
-		clients:    make(map[*Client]bool),
-		broadcast:  make(chan *WebSocketMessage, 100),
-		register:   make(chan *Client, 10),
-		unregister: make(chan *Client, 10),
+		clients:        make(map[*Client]bool),
+		broadcast:      make(chan *WebSocketMessage, 100),
+		register:       make(chan *Client, 10),
+		unregister:     make(chan *Client, 10),
+		sendBuffer:     DefaultWebSocketSendBuffer,
+		maxConnsPerOrg: DefaultWebSocketMaxConnsPerOrg,
+		orgConns:       make(map[string]int),
+		stop:           make(chan struct{}),
+	}
+}
+
+// SetConnectionLimits sets the per-client send buffer size and the maximum concurrent
+// connections per organization (zero for unlimited)
+func (ws *WebSocketService) SetConnectionLimits(sendBuffer, maxConnsPerOrg int) {
+	if sendBuffer > 0 {
+		ws.sendBuffer = sendBuffer
+	}
+	if maxConnsPerOrg >= 0 {
+		ws.maxConnsPerOrg = maxConnsPerOrg
 	}
 }
 
@@ -67,7 +159,15 @@ func (ws *WebSocketService) Start() {
 
 	for {
 		select {
+		case <-ws.stop:
+			log.Println("WebSocket service stopped")
+			return
+
 		case client := <-ws.register:
+			// The connection may have closed before the hub got to it
+			if client.isClosed() {
+				continue
+			}
 			ws.mutex.Lock()
 			ws.clients[client] = true
 			ws.mutex.Unlock()
@@ -80,38 +180,130 @@ func (ws *WebSocketService) Start() {
 				Timestamp: time.Now().UTC().Format(time.RFC3339),
 				ID:        client.ID,
 			}
-			client.Send <- ws.marshalMessage(welcomeMsg)
+			client.enqueue(ws.marshalMessage(welcomeMsg))
 
 		case client := <-ws.unregister:
-			ws.mutex.Lock()
-			if _, ok := ws.clients[client]; ok {
-				delete(ws.clients, client)
-				close(client.Send)
-			}
-			ws.mutex.Unlock()
+			ws.removeClient(client)
 			log.Printf("Client unregistered: %s", client.ID)
 
 		case message := <-ws.broadcast:
+			var slow []*Client
+			var data []byte
+
 			ws.mutex.RLock()
 			for client := range ws.clients {
 				// Check if message should be sent to this client
 				if ws.shouldSendToClient(message, client) {
-					select {
-					case client.Send <- ws.marshalMessage(message):
-					default:
-						close(client.Send)
-						delete(ws.clients, client)
+					if data == nil {
+						data = ws.marshalMessage(message)
+					}
+					if !client.enqueue(data) {
+						slow = append(slow, client)
 					}
 				}
 			}
 			ws.mutex.RUnlock()
+
+			// Clients that cannot keep up are disconnected rather than blocking the hub
+			for _, client := range slow {
+				ws.dropSlowClient(client)
+			}
 		}
 	}
 }
 
+// Stop shuts the hub down and disconnects every client; each connection is sent a close frame
+// by its write pump
+func (ws *WebSocketService) Stop() {
+	ws.stopOnce.Do(func() {
+		close(ws.stop)
+	})
+
+	ws.mutex.RLock()
+	clients := make([]*Client, 0, len(ws.clients))
+	for client := range ws.clients {
+		clients = append(clients, client)
+	}
+	ws.mutex.RUnlock()
+
+	for _, client := range clients {
+		ws.removeClient(client)
+	}
+	log.Printf("Disconnected %d WebSocket clients", len(clients))
+}
+
+// removeClient unregisters a client, closes its send channel and frees its connection slot
+func (ws *WebSocketService) removeClient(client *Client) {
+	ws.mutex.Lock()
+	delete(ws.clients, client)
+	ws.mutex.Unlock()
+
+	client.closeSend()
+	client.clearTopics()
+	ws.releaseConnection(client)
+}
+
+// dropSlowClient disconnects a client whose send buffer is full
+func (ws *WebSocketService) dropSlowClient(client *Client) {
+	dropped := ws.droppedClients.Add(1)
+	log.Printf("Dropping slow WebSocket client %s (User: %s, Org: %s): send buffer of %d messages full, %d clients dropped in total",
+		client.ID, client.UserID, client.OrganizationID, cap(client.Send), dropped)
+
+	ws.removeClient(client)
+}
+
+// GetDroppedClientsCount returns how many clients have been disconnected for falling behind
+func (ws *WebSocketService) GetDroppedClientsCount() int64 {
+	return ws.droppedClients.Load()
+}
+
+// reserveConnection claims one of the organization's connection slots
+func (ws *WebSocketService) reserveConnection(orgID string) bool {
+	ws.connsMu.Lock()
+	defer ws.connsMu.Unlock()
+
+	if ws.maxConnsPerOrg > 0 && ws.orgConns[orgID] >= ws.maxConnsPerOrg {
+		return false
+	}
+	ws.orgConns[orgID]++
+	return true
+}
+
+// releaseConnection frees the client's connection slot, once
+func (ws *WebSocketService) releaseConnection(client *Client) {
+	client.releaseOnce.Do(func() {
+		ws.releaseOrgConnection(client.OrganizationID)
+	})
+}
+
+func (ws *WebSocketService) releaseOrgConnection(orgID string) {
+	ws.connsMu.Lock()
+	defer ws.connsMu.Unlock()
+
+	ws.orgConns[orgID]--
+	if ws.orgConns[orgID] <= 0 {
+		delete(ws.orgConns, orgID)
+	}
+}
+
 // shouldSendToClient determines if a message should be sent to a specific client
 func (ws *WebSocketService) shouldSendToClient(message *WebSocketMessage, client *Client) bool {
-	// System messages go to all clients
+	// Messages never cross organizations, and untagged messages are not delivered at all
+	if message.OrganizationID == "" || message.OrganizationID != client.OrganizationID {
+		return false
+	}
+
+	// Topic messages only reach subscribers
+	if message.Topic != "" {
+		return client.isSubscribed(message.Topic)
+	}
+
+	// Deployment log streams only receive messages for the deployment they follow
+	if client.DeploymentID != "" || message.DeploymentID != "" {
+		return client.DeploymentID == message.DeploymentID
+	}
+
+	// System messages go to all of the organization's clients
 	if message.Type == MessageTypeSystem {
 		return true
 	}
@@ -121,16 +313,11 @@ func (ws *WebSocketService) shouldSendToClient(message *WebSocketMessage, client
 		return true
 	}
 
-	// Broadcast messages
+	// Organization-wide broadcast messages
 	if message.Target == "all" {
 		return true
 	}
 
-	// Organization-specific messages (future implementation)
-	// if message.Target == "organization" && client.OrganizationID == message.OrganizationID {
-	//     return true
-	// }
-
 	return false
 }
 
@@ -144,57 +331,125 @@ func (ws *WebSocketService) marshalMessage(message *WebSocketMessage) []byte {
 	return data
 }
 
-// BroadcastMessage sends a message to all connected clients
-func (ws *WebSocketService) BroadcastMessage(messageType string, data interface{}) {
+// BroadcastMessage sends a message to all of an organization's connected clients
+func (ws *WebSocketService) BroadcastMessage(orgID, messageType string, data interface{}) {
 	message := &WebSocketMessage{
-		Type:      messageType,
-		Data:      data,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		Target:    "all",
+		Type:           messageType,
+		Data:           data,
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		Target:         "all",
+		OrganizationID: orgID,
 	}
-	ws.broadcast <- message
+	ws.publish(message)
 }
 
-// SendToUser sends a message to a specific user
-func (ws *WebSocketService) SendToUser(userID, messageType string, data interface{}) {
+// SendToUser sends a message to a specific user within an organization
+func (ws *WebSocketService) SendToUser(orgID, userID, messageType string, data interface{}) {
 	message := &WebSocketMessage{
-		Type:      messageType,
-		Data:      data,
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		UserID:    userID,
+		Type:           messageType,
+		Data:           data,
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		UserID:         userID,
+		OrganizationID: orgID,
+	}
+	ws.publish(message)
+}
+
+// publish queues a message for delivery, dropping messages not tagged with an organization
+func (ws *WebSocketService) publish(message *WebSocketMessage) {
+	if message.OrganizationID == "" {
+		log.Printf("Dropping WebSocket %s message without an organization", message.Type)
+		return
 	}
 	ws.broadcast <- message
 }
 
+// PublishToTopic sends a message to the organization's clients subscribed to a topic
+func (ws *WebSocketService) PublishToTopic(orgID, topic, messageType string, data interface{}) {
+	message := &WebSocketMessage{
+		Type:           messageType,
+		Data:           data,
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		Topic:          topic,
+		OrganizationID: orgID,
+	}
+	ws.publish(message)
+}
+
 // SendDeploymentStatus sends deployment status updates
-func (ws *WebSocketService) SendDeploymentStatus(userID string, deploymentID string, status string, progress int, message string) {
+func (ws *WebSocketService) SendDeploymentStatus(orgID, userID string, deploymentID string, status string, progress int, message string) {
 	data := map[string]interface{}{
 		"deploymentId": deploymentID,
 		"status":       status,
 		"progress":     progress,
 		"message":      message,
 	}
-	ws.SendToUser(userID, MessageTypeDeploymentStatus, data)
+	ws.SendToUser(orgID, userID, MessageTypeDeploymentStatus, data)
+}
+
+// PublishDeploymentStatus sends a deployment's current status to subscribers of its topic
+func (ws *WebSocketService) PublishDeploymentStatus(deployment *models.Deployment) {
+	ws.PublishToTopic(deployment.OrganizationID, Topic(TopicDeployments, deployment.ID), MessageTypeDeploymentStatus, map[string]interface{}{
+		"deploymentId": deployment.ID,
+		"status":       deployment.Status,
+		"progress":     deployment.Progress,
+	})
+}
+
+// NewDeploymentLogMessage builds a log line message for clients following a deployment
+func NewDeploymentLogMessage(orgID, deploymentID string, logLine interface{}) *WebSocketMessage {
+	return &WebSocketMessage{
+		Type:           MessageTypeDeploymentLog,
+		Data:           logLine,
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		DeploymentID:   deploymentID,
+		OrganizationID: orgID,
+	}
+}
+
+// NewDeploymentFinishedMessage builds the control message telling followers a deployment
+// has finished and the stream can be closed
+func NewDeploymentFinishedMessage(orgID, deploymentID, status string) *WebSocketMessage {
+	return &WebSocketMessage{
+		Type: MessageTypeDeploymentFinished,
+		Data: map[string]interface{}{
+			"deploymentId": deploymentID,
+			"status":       status,
+		},
+		Timestamp:      time.Now().UTC().Format(time.RFC3339),
+		DeploymentID:   deploymentID,
+		OrganizationID: orgID,
+	}
+}
+
+// PublishDeploymentLog streams a log line to clients following the deployment
+func (ws *WebSocketService) PublishDeploymentLog(orgID, deploymentID string, logLine interface{}) {
+	ws.publish(NewDeploymentLogMessage(orgID, deploymentID, logLine))
+}
+
+// PublishDeploymentFinished tells clients following the deployment that it has finished
+func (ws *WebSocketService) PublishDeploymentFinished(orgID, deploymentID, status string) {
+	ws.publish(NewDeploymentFinishedMessage(orgID, deploymentID, status))
 }
 
 // SendInfrastructureUpdate sends infrastructure status updates
-func (ws *WebSocketService) SendInfrastructureUpdate(userID string, infrastructureID string, status string, message string) {
+func (ws *WebSocketService) SendInfrastructureUpdate(orgID, userID string, infrastructureID string, status string, message string) {
 	data := map[string]interface{}{
 		"infrastructureId": infrastructureID,
 		"status":           status,
 		"message":          message,
 	}
-	ws.SendToUser(userID, MessageTypeInfrastructure, data)
+	ws.SendToUser(orgID, userID, MessageTypeInfrastructure, data)
 }
 
 // SendMetricsUpdate sends real-time metrics updates
-func (ws *WebSocketService) SendMetricsUpdate(userID string, metrics map[string]interface{}) {
-	ws.SendToUser(userID, MessageTypeMetrics, metrics)
+func (ws *WebSocketService) SendMetricsUpdate(orgID, userID string, metrics map[string]interface{}) {
+	ws.SendToUser(orgID, userID, MessageTypeMetrics, metrics)
 }
 
 // SendAlert sends alert notifications
-func (ws *WebSocketService) SendAlert(userID string, alert map[string]interface{}) {
-	ws.SendToUser(userID, MessageTypeAlert, alert)
+func (ws *WebSocketService) SendAlert(orgID, userID string, alert map[string]interface{}) {
+	ws.SendToUser(orgID, userID, MessageTypeAlert, alert)
 }
 
 // GetConnectedClientsCount returns the number of connected clients
@@ -205,7 +460,24 @@ func (ws *WebSocketService) GetConnectedClientsCount() int {
 }
 
 // HandleWebSocket handles the WebSocket upgrade and client connection
-func (ws *WebSocketService) HandleWebSocket(w http.ResponseWriter, r *http.Request, userID string) {
+func (ws *WebSocketService) HandleWebSocket(w http.ResponseWriter, r *http.Request, userID, orgID string) {
+	ws.serveClient(w, r, userID, orgID, "", nil)
+}
+
+// HandleDeploymentLogStream upgrades the connection to a stream of a deployment's log lines.
+// The backlog is sent before any live messages.
+func (ws *WebSocketService) HandleDeploymentLogStream(w http.ResponseWriter, r *http.Request, userID, orgID, deploymentID string, backlog []*WebSocketMessage) {
+	ws.serveClient(w, r, userID, orgID, deploymentID, backlog)
+}
+
+// serveClient upgrades the connection and registers the client
+func (ws *WebSocketService) serveClient(w http.ResponseWriter, r *http.Request, userID, orgID, deploymentID string, backlog []*WebSocketMessage) {
+	if !ws.reserveConnection(orgID) {
+		log.Printf("Rejecting WebSocket connection for organization %s: limit of %d connections reached", orgID, ws.maxConnsPerOrg)
+		http.Error(w, "Too many WebSocket connections for this organization", http.StatusTooManyRequests)
+		return
+	}
+
 	// Configure WebSocket upgrader
 	upgrader := websocket.Upgrader{
 		CheckOrigin: func(r *http.Request) bool {
@@ -222,17 +494,26 @@ func (ws *WebSocketService) HandleWebSocket(w http.ResponseWriter, r *http.Reque
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		log.Printf("WebSocket upgrade failed: %v", err)
+		ws.releaseOrgConnection(orgID)
 		return
 	}
 
 	// Create new client
 	client := &Client{
-		ID:       generateClientID(),
-		UserID:   userID,
-		Conn:     conn,
-		Send:     make(chan []byte, 256),
-		Service:  ws,
-		UserType: "user",
+		ID:             generateClientID(),
+		UserID:         userID,
+		OrganizationID: orgID,
+		Conn:           conn,
+		Send:           make(chan []byte, ws.sendBuffer+len(backlog)),
+		Service:        ws,
+		UserType:       "user",
+		DeploymentID:   deploymentID,
+		topics:         make(map[string]bool),
+	}
+
+	// Queue the backlog ahead of live messages
+	for _, message := range backlog {
+		client.Send <- ws.marshalMessage(message)
 	}
 
 	// Register client
@@ -308,6 +589,12 @@ func (c *Client) writePump() {
 
 // handleIncomingMessage processes incoming WebSocket messages
 func (c *Client) handleIncomingMessage(message []byte) {
+	var request subscriptionRequest
+	if err := json.Unmarshal(message, &request); err == nil && request.Action != "" {
+		c.handleSubscription(request)
+		return
+	}
+
 	var msg WebSocketMessage
 	if err := json.Unmarshal(message, &msg); err != nil {
 		log.Printf("Error unmarshaling message: %v", err)
@@ -321,7 +608,7 @@ func (c *Client) handleIncomingMessage(message []byte) {
 			Type:      MessageTypePong,
 			Timestamp: time.Now().UTC().Format(time.RFC3339),
 		}
-		c.Send <- c.Service.marshalMessage(pongMsg)
+		c.enqueue(c.Service.marshalMessage(pongMsg))
 
 	case MessageTypePong:
 		// Handle pong response
@@ -332,6 +619,131 @@ func (c *Client) handleIncomingMessage(message []byte) {
 	}
 }
 
+// handleSubscription subscribes or unsubscribes the client from a topic and acknowledges it
+func (c *Client) handleSubscription(request subscriptionRequest) {
+	var err error
+	var messageType string
+
+	switch request.Action {
+	case SubscriptionActionSubscribe:
+		messageType = MessageTypeSubscribed
+		err = c.subscribe(request.Topic)
+	case SubscriptionActionUnsubscribe:
+		messageType = MessageTypeUnsubscribed
+		c.unsubscribe(request.Topic)
+	default:
+		err = fmt.Errorf("unknown action %q", request.Action)
+	}
+
+	response := &WebSocketMessage{
+		Type:      messageType,
+		Data:      map[string]string{"topic": request.Topic},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err != nil {
+		response.Type = MessageTypeError
+		response.Data = map[string]string{"topic": request.Topic, "message": err.Error()}
+	}
+
+	c.enqueue(c.Service.marshalMessage(response))
+}
+
+// enqueue queues a message without blocking. It returns false when the send buffer is full.
+// Messages queued after the client has been closed are discarded.
+func (c *Client) enqueue(data []byte) bool {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if c.closed {
+		return true
+	}
+
+	select {
+	case c.Send <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+// closeSend closes the send channel, which makes writePump close the connection
+func (c *Client) closeSend() {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.Send)
+	}
+}
+
+func (c *Client) isClosed() bool {
+	c.sendMu.Lock()
+	defer c.sendMu.Unlock()
+	return c.closed
+}
+
+// subscribe adds a topic after checking it is well formed and within the client's organization
+func (c *Client) subscribe(topic string) error {
+	if err := validateClientTopic(topic, c.OrganizationID); err != nil {
+		return err
+	}
+
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+
+	if !c.topics[topic] && len(c.topics) >= maxClientTopics {
+		return fmt.Errorf("subscribed to too many topics (maximum %d)", maxClientTopics)
+	}
+	c.topics[topic] = true
+	return nil
+}
+
+func (c *Client) unsubscribe(topic string) {
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+	delete(c.topics, topic)
+}
+
+func (c *Client) isSubscribed(topic string) bool {
+	c.topicsMu.RLock()
+	defer c.topicsMu.RUnlock()
+	return c.topics[topic]
+}
+
+// clearTopics drops every subscription when the client disconnects
+func (c *Client) clearTopics() {
+	c.topicsMu.Lock()
+	defer c.topicsMu.Unlock()
+	c.topics = make(map[string]bool)
+}
+
+// validateClientTopic checks a topic is a known kind with an ID. Resource topics are matched
+// against messages published for the client's organization, so only organization topics need
+// their ID checked here.
+func validateClientTopic(topic, orgID string) error {
+	if orgID == "" {
+		return fmt.Errorf("connection is not associated with an organization")
+	}
+
+	kind, id, ok := strings.Cut(topic, ":")
+	if !ok || id == "" {
+		return fmt.Errorf("topic must be in the form <kind>:<id>")
+	}
+
+	switch kind {
+	case TopicDeployments, TopicInfrastructure, TopicMetrics, TopicAlerts:
+		return nil
+	case TopicOrganization:
+		if id != orgID {
+			return fmt.Errorf("cannot subscribe to another organization's topics")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown topic kind %q", kind)
+	}
+}
+
 // generateClientID generates a unique client ID
 func generateClientID() string {
 	return "client_" + time.Now().Format("20060102150405") + "_" + randomString(8)
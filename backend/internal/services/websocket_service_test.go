@@ -0,0 +1,76 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialWebSocket connects to the test server as a user of an organization and waits for the
+// welcome message, so the client is registered before anything is published
+func dialWebSocket(t *testing.T, server *httptest.Server, orgID, userID string) *websocket.Conn {
+	t.Helper()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "?org=" + orgID + "&user=" + userID
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("failed to connect as %s: %v", orgID, err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	if message := readWebSocketMessage(t, conn); message.Type != MessageTypeSystem {
+		t.Fatalf("first message = %s, want the welcome message", message.Type)
+	}
+	return conn
+}
+
+func readWebSocketMessage(t *testing.T, conn *websocket.Conn) WebSocketMessage {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, data, err := conn.ReadMessage()
+	if err != nil {
+		t.Fatalf("failed to read message: %v", err)
+	}
+
+	var message WebSocketMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		t.Fatalf("failed to decode message %s: %v", data, err)
+	}
+	return message
+}
+
+func TestWebSocketMessagesStayInTheirOrganization(t *testing.T) {
+	ws := NewWebSocketService()
+	go ws.Start()
+	defer ws.Stop()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws.HandleWebSocket(w, r, r.URL.Query().Get("user"), r.URL.Query().Get("org"))
+	}))
+	defer server.Close()
+
+	// The same user ID in both organizations, so only the organization separates them
+	orgA := dialWebSocket(t, server, "org-a", "user-1")
+	orgB := dialWebSocket(t, server, "org-b", "user-1")
+
+	ws.BroadcastMessage("org-a", "org_a_broadcast", map[string]string{"secret": "a"})
+	ws.SendToUser("org-a", "user-1", "org_a_direct", map[string]string{"secret": "a"})
+	ws.BroadcastMessage("org-b", "org_b_broadcast", nil)
+
+	for _, want := range []string{"org_a_broadcast", "org_a_direct"} {
+		if got := readWebSocketMessage(t, orgA).Type; got != want {
+			t.Errorf("org-a client received %s, want %s", got, want)
+		}
+	}
+
+	// Messages are delivered in order, so anything leaked from org-a would arrive first
+	if got := readWebSocketMessage(t, orgB).Type; got != "org_b_broadcast" {
+		t.Errorf("org-b client received %s, want only its own organization's org_b_broadcast", got)
+	}
+}
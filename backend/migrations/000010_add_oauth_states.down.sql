@@ -0,0 +1,2 @@
+-- Drop the OAuth state table
+DROP TABLE IF EXISTS oauth_states;
@@ -0,0 +1,11 @@
+-- OAuth state table used to validate SSO callbacks
+CREATE TABLE oauth_states (
+    state VARCHAR(255) PRIMARY KEY,
+    provider VARCHAR(50) NOT NULL,
+    organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create index for cleanup of expired states
+CREATE INDEX idx_oauth_states_expires_at ON oauth_states(expires_at);
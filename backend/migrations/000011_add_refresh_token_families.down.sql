@@ -0,0 +1,2 @@
+-- Drop the refresh token families table
+DROP TABLE IF EXISTS refresh_token_families;
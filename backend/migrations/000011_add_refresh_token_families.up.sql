@@ -0,0 +1,12 @@
+-- Revoked refresh token families, used to invalidate every token descended from a reused refresh token
+CREATE TABLE refresh_token_families (
+    family_id VARCHAR(255) PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason VARCHAR(100) DEFAULT 'reuse_detected'
+);
+
+-- Create indexes for performance
+CREATE INDEX idx_refresh_token_families_user_id ON refresh_token_families(user_id);
+CREATE INDEX idx_refresh_token_families_expires_at ON refresh_token_families(expires_at);
@@ -0,0 +1,5 @@
+-- Remove TOTP two-factor authentication fields from users table
+ALTER TABLE users
+DROP COLUMN IF EXISTS mfa_backup_codes,
+DROP COLUMN IF EXISTS mfa_secret,
+DROP COLUMN IF EXISTS mfa_enabled;
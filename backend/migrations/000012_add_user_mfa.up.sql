@@ -0,0 +1,5 @@
+-- Add TOTP two-factor authentication fields to users table
+ALTER TABLE users
+ADD COLUMN mfa_enabled BOOLEAN DEFAULT false,
+ADD COLUMN mfa_secret TEXT,
+ADD COLUMN mfa_backup_codes TEXT[];
@@ -0,0 +1,7 @@
+-- Remove pipelines
+DROP INDEX IF EXISTS idx_deployments_pipeline_id;
+
+ALTER TABLE deployments
+DROP COLUMN IF EXISTS pipeline_id;
+
+DROP TABLE IF EXISTS pipelines;
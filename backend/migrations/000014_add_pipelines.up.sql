@@ -0,0 +1,25 @@
+-- Create pipelines table with ordered stages
+CREATE TABLE pipelines (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    repository VARCHAR(500) NOT NULL DEFAULT '',
+    branch VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(50) NOT NULL DEFAULT 'pending',
+    stages JSONB NOT NULL DEFAULT '[]',
+    last_run TIMESTAMP WITH TIME ZONE,
+    next_run TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_pipelines_organization_id ON pipelines(organization_id);
+
+CREATE TRIGGER update_pipelines_updated_at BEFORE UPDATE ON pipelines FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Allow deployments to run through a pipeline
+ALTER TABLE deployments
+ADD COLUMN pipeline_id UUID REFERENCES pipelines(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_deployments_pipeline_id ON deployments(pipeline_id);
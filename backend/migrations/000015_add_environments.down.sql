@@ -0,0 +1,2 @@
+-- Remove custom deployment environments
+DROP TABLE IF EXISTS environments;
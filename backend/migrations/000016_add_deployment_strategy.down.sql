@@ -0,0 +1,4 @@
+-- Remove deployment strategy fields
+ALTER TABLE deployments
+DROP COLUMN IF EXISTS strategy_state,
+DROP COLUMN IF EXISTS strategy;
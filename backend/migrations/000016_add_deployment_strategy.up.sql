@@ -0,0 +1,4 @@
+-- Add rollout strategy and per-step strategy state to deployments
+ALTER TABLE deployments
+ADD COLUMN strategy VARCHAR(20) NOT NULL DEFAULT 'rolling',
+ADD COLUMN strategy_state JSONB NOT NULL DEFAULT '{}';
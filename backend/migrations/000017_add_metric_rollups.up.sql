@@ -0,0 +1,33 @@
+-- Create hourly and daily metric rollup tables
+CREATE TABLE metrics_hourly (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    resource_id UUID NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    metric_name VARCHAR(100) NOT NULL,
+    unit VARCHAR(20),
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL,
+    min_value DECIMAL(15,4) NOT NULL,
+    max_value DECIMAL(15,4) NOT NULL,
+    avg_value DECIMAL(15,4) NOT NULL,
+    sample_count INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(resource_id, metric_name, bucket)
+);
+
+CREATE TABLE metrics_daily (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    resource_id UUID NOT NULL,
+    resource_type VARCHAR(50) NOT NULL,
+    metric_name VARCHAR(100) NOT NULL,
+    unit VARCHAR(20),
+    bucket TIMESTAMP WITH TIME ZONE NOT NULL,
+    min_value DECIMAL(15,4) NOT NULL,
+    max_value DECIMAL(15,4) NOT NULL,
+    avg_value DECIMAL(15,4) NOT NULL,
+    sample_count INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(resource_id, metric_name, bucket)
+);
+
+CREATE INDEX idx_metrics_hourly_bucket ON metrics_hourly(bucket);
+CREATE INDEX idx_metrics_daily_bucket ON metrics_daily(bucket);
@@ -0,0 +1,3 @@
+-- Remove alert rules
+DROP TABLE IF EXISTS alert_rule_states;
+DROP TABLE IF EXISTS alert_rules;
@@ -0,0 +1,30 @@
+-- Create alert rules evaluated against collected metrics
+CREATE TABLE alert_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    resource_type VARCHAR(50) NOT NULL DEFAULT '',
+    metric_name VARCHAR(100) NOT NULL,
+    operator VARCHAR(10) NOT NULL,
+    threshold DECIMAL(15,4) NOT NULL,
+    duration_seconds INTEGER NOT NULL DEFAULT 0,
+    severity VARCHAR(20) NOT NULL DEFAULT 'warning',
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Track how long each rule has been breached per resource
+CREATE TABLE alert_rule_states (
+    rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    resource_id UUID NOT NULL,
+    breached_since TIMESTAMP WITH TIME ZONE NOT NULL,
+    alert_id UUID REFERENCES alerts(id) ON DELETE SET NULL,
+    PRIMARY KEY (rule_id, resource_id)
+);
+
+CREATE INDEX idx_alert_rules_organization_id ON alert_rules(organization_id);
+
+CREATE TRIGGER update_alert_rules_updated_at BEFORE UPDATE ON alert_rules FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Remove alert events
+DROP TABLE IF EXISTS alert_events;
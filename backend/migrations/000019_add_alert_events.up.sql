@@ -0,0 +1,11 @@
+-- Record alert state changes for the alert history
+CREATE TABLE alert_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    alert_id UUID NOT NULL REFERENCES alerts(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    event_type VARCHAR(20) NOT NULL,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_alert_events_alert_id ON alert_events(alert_id, created_at);
@@ -0,0 +1,3 @@
+-- Remove notification channels
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS notification_channels;
@@ -0,0 +1,3 @@
+-- Remove infrastructure soft-delete
+DROP INDEX IF EXISTS idx_infrastructure_deleted_at;
+ALTER TABLE infrastructure DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,4 @@
+-- Soft-delete infrastructure so deleted resources can be restored within the retention window
+ALTER TABLE infrastructure ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_infrastructure_deleted_at ON infrastructure(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Remove stat snapshots
+DROP TABLE IF EXISTS stat_snapshots;
@@ -0,0 +1,9 @@
+-- Record daily values of organization statistics so stats can report change over time
+CREATE TABLE stat_snapshots (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    metric VARCHAR(50) NOT NULL,
+    value DOUBLE PRECISION NOT NULL,
+    captured_on DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (organization_id, metric, captured_on)
+);
@@ -0,0 +1,8 @@
+-- Remove full-text search vectors
+DROP INDEX IF EXISTS idx_alerts_search_vector;
+DROP INDEX IF EXISTS idx_deployments_search_vector;
+DROP INDEX IF EXISTS idx_infrastructure_search_vector;
+
+ALTER TABLE alerts DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE deployments DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE infrastructure DROP COLUMN IF EXISTS search_vector;
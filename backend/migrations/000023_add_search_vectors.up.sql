@@ -0,0 +1,21 @@
+-- Full-text search vectors for the global search endpoint. Names rank above secondary fields.
+ALTER TABLE infrastructure ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(jsonb_to_tsvector('simple', coalesce(tags, '[]'::jsonb), '["string"]'), 'B') ||
+    setweight(to_tsvector('simple', coalesce(type, '') || ' ' || coalesce(provider, '') || ' ' || coalesce(region, '')), 'C')
+) STORED;
+
+ALTER TABLE deployments ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(application, '')), 'B') ||
+    setweight(to_tsvector('simple', coalesce(version, '') || ' ' || coalesce(environment, '')), 'C')
+) STORED;
+
+ALTER TABLE alerts ADD COLUMN search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('simple', coalesce(message, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_infrastructure_search_vector ON infrastructure USING GIN (search_vector);
+CREATE INDEX idx_deployments_search_vector ON deployments USING GIN (search_vector);
+CREATE INDEX idx_alerts_search_vector ON alerts USING GIN (search_vector);
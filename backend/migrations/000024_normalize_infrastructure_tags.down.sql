@@ -0,0 +1,2 @@
+-- Tag normalization cannot be undone; only the index is removed
+DROP INDEX IF EXISTS idx_infrastructure_tags;
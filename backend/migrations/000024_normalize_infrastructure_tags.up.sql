@@ -0,0 +1,24 @@
+-- Normalize infrastructure tags to "key=value" with lowercase keys, keeping the last tag given for
+-- each key, and index them for tag filtering
+UPDATE infrastructure
+SET tags = (
+    SELECT COALESCE(jsonb_agg(tag ORDER BY tag), '[]'::jsonb)
+    FROM (
+        SELECT DISTINCT ON (split_part(normalized, '=', 1)) normalized AS tag
+        FROM (
+            SELECT ord, CASE
+                WHEN strpos(raw, '=') > 0
+                    THEN lower(trim(split_part(raw, '=', 1))) || '=' || trim(substr(raw, strpos(raw, '=') + 1))
+                ELSE lower(trim(raw)) || '='
+            END AS normalized
+            FROM jsonb_array_elements_text(tags) WITH ORDINALITY AS elements(raw, ord)
+            WHERE trim(split_part(raw, '=', 1)) <> ''
+        ) AS normalized_tags
+        ORDER BY split_part(normalized, '=', 1), ord DESC
+    ) AS unique_tags
+)
+WHERE jsonb_typeof(tags) = 'array';
+
+UPDATE infrastructure SET tags = '[]'::jsonb WHERE tags IS NULL OR jsonb_typeof(tags) <> 'array';
+
+CREATE INDEX idx_infrastructure_tags ON infrastructure USING GIN (tags jsonb_path_ops);
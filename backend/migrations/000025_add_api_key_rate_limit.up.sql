@@ -0,0 +1,2 @@
+-- Allow API keys to carry their own rate limit in requests per minute; 0 uses the route group limits
+ALTER TABLE api_keys ADD COLUMN rate_limit INTEGER NOT NULL DEFAULT 0 CHECK (rate_limit >= 0);
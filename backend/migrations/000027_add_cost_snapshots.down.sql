@@ -0,0 +1,2 @@
+-- Remove cost snapshots
+DROP TABLE IF EXISTS cost_snapshots;
@@ -0,0 +1,8 @@
+-- Record each organization's projected daily spend so cost anomalies can be detected against a baseline
+CREATE TABLE cost_snapshots (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    daily_cost DOUBLE PRECISION NOT NULL,
+    captured_on DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (organization_id, captured_on)
+);
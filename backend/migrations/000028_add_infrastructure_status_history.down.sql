@@ -0,0 +1,4 @@
+-- Remove infrastructure status history
+DROP TRIGGER IF EXISTS record_infrastructure_status_change ON infrastructure;
+DROP FUNCTION IF EXISTS record_infrastructure_status_change();
+DROP TABLE IF EXISTS infrastructure_status_history;
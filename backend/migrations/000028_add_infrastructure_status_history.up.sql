@@ -0,0 +1,25 @@
+-- Record every infrastructure status change so uptime can be analyzed over time
+CREATE TABLE infrastructure_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    infrastructure_id UUID NOT NULL REFERENCES infrastructure(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_infrastructure_status_history_infra_changed ON infrastructure_status_history(infrastructure_id, changed_at);
+
+CREATE OR REPLACE FUNCTION record_infrastructure_status_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO infrastructure_status_history (infrastructure_id, status) VALUES (NEW.id, NEW.status);
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_infrastructure_status_change AFTER INSERT OR UPDATE OF status ON infrastructure FOR EACH ROW EXECUTE FUNCTION record_infrastructure_status_change();
+
+-- History starts now for existing resources; earlier status changes were never recorded
+INSERT INTO infrastructure_status_history (infrastructure_id, status)
+SELECT id, status FROM infrastructure WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Remove resource cost snapshots
+DROP TABLE IF EXISTS resource_cost_snapshots;
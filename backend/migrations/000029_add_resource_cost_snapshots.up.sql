@@ -0,0 +1,10 @@
+-- Record each resource's daily cost and usage so its cost history can be charted against usage
+CREATE TABLE resource_cost_snapshots (
+    infrastructure_id UUID NOT NULL REFERENCES infrastructure(id) ON DELETE CASCADE,
+    daily_cost DOUBLE PRECISION NOT NULL,
+    cpu_utilization DOUBLE PRECISION NOT NULL DEFAULT 0,
+    memory_utilization DOUBLE PRECISION NOT NULL DEFAULT 0,
+    captured_on DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (infrastructure_id, captured_on)
+);
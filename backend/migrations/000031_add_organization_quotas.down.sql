@@ -0,0 +1,2 @@
+-- Remove organization quotas
+DROP TABLE IF EXISTS organization_quotas;
@@ -0,0 +1,12 @@
+-- Create per-organization limits on the infrastructure that can be created: the most resources of
+-- each type, keyed by type, and the most estimated monthly cost
+CREATE TABLE organization_quotas (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    max_resources JSONB NOT NULL DEFAULT '{}',
+    max_monthly_budget DECIMAL(15,2),
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_organization_quotas_updated_at BEFORE UPDATE ON organization_quotas FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Remove deployment rollback history
+DROP TABLE IF EXISTS deployment_rollbacks;
@@ -0,0 +1,20 @@
+-- Record every rollback of a deployment, manual or automatic, with the reason and, for automatic
+-- rollbacks, the health metric that triggered it
+CREATE TABLE deployment_rollbacks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    deployment_id UUID NOT NULL REFERENCES deployments(id) ON DELETE CASCADE,
+    rollback_deployment_id UUID REFERENCES deployments(id) ON DELETE SET NULL,
+    from_version VARCHAR(100) NOT NULL,
+    to_version VARCHAR(100) NOT NULL,
+    trigger_type VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    metric_name VARCHAR(100),
+    metric_value DECIMAL(15,4),
+    threshold DECIMAL(15,4),
+    initiated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_deployment_rollbacks_deployment_id ON deployment_rollbacks(deployment_id, created_at);
+CREATE INDEX idx_deployment_rollbacks_rollback_deployment_id ON deployment_rollbacks(rollback_deployment_id);
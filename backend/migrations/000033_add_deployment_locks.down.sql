@@ -0,0 +1,2 @@
+-- Remove deployment locks
+DROP TABLE IF EXISTS deployment_locks;
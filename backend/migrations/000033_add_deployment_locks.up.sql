@@ -0,0 +1,13 @@
+-- Allow only one deployment at a time per application and environment. A lock whose deployment
+-- stops renewing it, e.g. because the server crashed, expires and can be taken over.
+CREATE TABLE deployment_locks (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    application VARCHAR(255) NOT NULL,
+    environment VARCHAR(50) NOT NULL,
+    deployment_id UUID NOT NULL,
+    acquired_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (organization_id, application, environment)
+);
+
+CREATE INDEX idx_deployment_locks_deployment_id ON deployment_locks(deployment_id);
@@ -0,0 +1,3 @@
+-- Remove deployment schedules
+DROP INDEX IF EXISTS idx_deployments_scheduled_for;
+ALTER TABLE deployments DROP COLUMN IF EXISTS scheduled_for;
@@ -0,0 +1,4 @@
+-- Deployments can be scheduled to start at a later time, e.g. in a maintenance window
+ALTER TABLE deployments ADD COLUMN scheduled_for TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_deployments_scheduled_for ON deployments(scheduled_for) WHERE status = 'scheduled';
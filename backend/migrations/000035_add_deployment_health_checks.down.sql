@@ -0,0 +1,2 @@
+-- Remove deployment health check results
+DROP TABLE IF EXISTS deployment_health_checks;
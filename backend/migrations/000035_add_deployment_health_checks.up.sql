@@ -0,0 +1,13 @@
+-- Record the results of the HTTP health checks a deployment must pass before it completes
+CREATE TABLE deployment_health_checks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    deployment_id UUID NOT NULL REFERENCES deployments(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    healthy BOOLEAN NOT NULL,
+    status_code INTEGER,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    checked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_deployment_health_checks_deployment_id ON deployment_health_checks(deployment_id, checked_at DESC);
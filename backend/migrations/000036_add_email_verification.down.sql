@@ -0,0 +1,2 @@
+-- Remove email verification tracking
+ALTER TABLE users DROP COLUMN IF EXISTS email_verification_sent_at;
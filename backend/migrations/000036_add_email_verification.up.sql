@@ -0,0 +1,2 @@
+-- Track when a verification email was last sent so resends can be rate limited
+ALTER TABLE users ADD COLUMN email_verification_sent_at TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,2 @@
+-- Remove organization memberships
+DROP TABLE IF EXISTS organization_memberships;
@@ -0,0 +1,11 @@
+-- Let users belong to organizations besides their home organization (users.organization_id), with
+-- a role in each. The home organization is always a membership, with the user's own role.
+CREATE TABLE organization_memberships (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL DEFAULT 'user',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, organization_id)
+);
+
+CREATE INDEX idx_organization_memberships_organization_id ON organization_memberships(organization_id);
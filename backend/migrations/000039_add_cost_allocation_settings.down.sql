@@ -0,0 +1,2 @@
+-- Remove cost allocation settings
+DROP TABLE IF EXISTS cost_allocation_settings;
@@ -0,0 +1,11 @@
+-- Create per-organization cost allocation settings: the tag keys, e.g. cost-center or team, that
+-- allocation reports group resource costs by
+CREATE TABLE cost_allocation_settings (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    dimensions TEXT[] NOT NULL DEFAULT '{}',
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TRIGGER update_cost_allocation_settings_updated_at BEFORE UPDATE ON cost_allocation_settings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+-- Remove the infrastructure change log
+DROP TRIGGER IF EXISTS record_infrastructure_change ON infrastructure;
+DROP FUNCTION IF EXISTS record_infrastructure_change();
+DROP TABLE IF EXISTS infrastructure_changes;
@@ -0,0 +1,64 @@
+-- Keep a change log of every infrastructure resource: field-level before and after values of each
+-- change, and the results of syncing the resource with its cloud provider
+CREATE TABLE infrastructure_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    infrastructure_id UUID NOT NULL REFERENCES infrastructure(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    change_type VARCHAR(50) NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',
+    message TEXT,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_infrastructure_changes_infra_changed ON infrastructure_changes(infrastructure_id, changed_at DESC);
+CREATE INDEX idx_infrastructure_changes_org_changed ON infrastructure_changes(organization_id, changed_at DESC);
+
+CREATE OR REPLACE FUNCTION record_infrastructure_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    -- Tracked columns and the API field names their changes are recorded under
+    tracked_columns TEXT[] := ARRAY['name', 'type', 'provider', 'region', 'status', 'specifications', 'cost_info', 'tags', 'external_id', 'deleted_at'];
+    tracked_fields TEXT[] := ARRAY['name', 'type', 'provider', 'region', 'status', 'specifications', 'costInfo', 'tags', 'externalId', 'deletedAt'];
+    old_row JSONB;
+    new_row JSONB;
+    field_changes JSONB := '{}'::jsonb;
+    kind VARCHAR(50) := 'updated';
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO infrastructure_changes (infrastructure_id, organization_id, change_type)
+        VALUES (NEW.id, NEW.organization_id, 'created');
+        RETURN NEW;
+    END IF;
+
+    old_row := to_jsonb(OLD);
+    new_row := to_jsonb(NEW);
+    FOR i IN 1 .. array_length(tracked_columns, 1) LOOP
+        IF old_row -> tracked_columns[i] IS DISTINCT FROM new_row -> tracked_columns[i] THEN
+            field_changes := field_changes || jsonb_build_object(tracked_fields[i],
+                jsonb_build_object('before', old_row -> tracked_columns[i], 'after', new_row -> tracked_columns[i]));
+        END IF;
+    END LOOP;
+
+    IF field_changes = '{}'::jsonb THEN
+        RETURN NEW;
+    END IF;
+
+    IF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+        kind := 'deleted';
+    ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+        kind := 'restored';
+    ELSIF field_changes - 'status' = '{}'::jsonb THEN
+        kind := 'status_changed';
+    END IF;
+
+    INSERT INTO infrastructure_changes (infrastructure_id, organization_id, change_type, changes)
+    VALUES (NEW.id, NEW.organization_id, kind, field_changes);
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+
+CREATE TRIGGER record_infrastructure_change AFTER INSERT OR UPDATE ON infrastructure FOR EACH ROW EXECUTE FUNCTION record_infrastructure_change();
+
+-- Existing resources start their change log with their creation; earlier changes were never recorded
+INSERT INTO infrastructure_changes (infrastructure_id, organization_id, change_type, changed_at)
+SELECT id, organization_id, 'created', created_at FROM infrastructure;
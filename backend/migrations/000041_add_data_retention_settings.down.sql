@@ -0,0 +1,2 @@
+-- Remove data retention settings
+DROP TABLE IF EXISTS data_retention_settings;
@@ -0,0 +1,17 @@
+-- Create per-organization data retention settings: how many days raw metrics, metric rollups and
+-- resolved alerts are kept before the retention purge deletes them
+CREATE TABLE data_retention_settings (
+    organization_id UUID PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    raw_metrics_days INTEGER NOT NULL,
+    hourly_metrics_days INTEGER NOT NULL,
+    daily_metrics_days INTEGER NOT NULL,
+    resolved_alerts_days INTEGER NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    -- Rollups summarize the finer data, so they are kept at least as long
+    CHECK (raw_metrics_days > 0 AND raw_metrics_days <= hourly_metrics_days AND hourly_metrics_days <= daily_metrics_days),
+    CHECK (resolved_alerts_days > 0)
+);
+
+CREATE TRIGGER update_data_retention_settings_updated_at BEFORE UPDATE ON data_retention_settings FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,8 @@
+-- Remove vulnerability lifecycle tracking; findings merged across scans stay merged
+DROP TABLE IF EXISTS vulnerability_status_changes;
+DROP INDEX IF EXISTS idx_vulnerabilities_org_status;
+DROP INDEX IF EXISTS idx_vulnerabilities_org_fingerprint;
+ALTER TABLE vulnerabilities
+    DROP COLUMN IF EXISTS scan_count,
+    DROP COLUMN IF EXISTS fingerprint,
+    DROP COLUMN IF EXISTS location;
@@ -0,0 +1,50 @@
+-- Track each vulnerability as one finding across scans: a fingerprint of the resource, CVE (or
+-- title) and location identifies a finding, and every status change it goes through is recorded
+ALTER TABLE vulnerabilities
+    ADD COLUMN location VARCHAR(500) NOT NULL DEFAULT '',
+    ADD COLUMN fingerprint VARCHAR(64),
+    ADD COLUMN scan_count INTEGER NOT NULL DEFAULT 1;
+
+UPDATE vulnerabilities
+SET fingerprint = encode(sha256(convert_to(
+    concat_ws('|', resource_type, resource_id, COALESCE(cve_id, title), location), 'UTF8')), 'hex');
+
+-- Merge findings that were stored once per scan into the most recently seen one
+WITH ranked AS (
+    SELECT id,
+           ROW_NUMBER() OVER w AS position,
+           MIN(first_detected) OVER (PARTITION BY organization_id, fingerprint) AS first_detected,
+           COUNT(*) OVER (PARTITION BY organization_id, fingerprint) AS sightings
+    FROM vulnerabilities
+    WINDOW w AS (PARTITION BY organization_id, fingerprint ORDER BY last_seen DESC, created_at DESC, id)
+)
+UPDATE vulnerabilities v
+SET first_detected = ranked.first_detected, scan_count = ranked.sightings
+FROM ranked
+WHERE v.id = ranked.id AND ranked.position = 1;
+
+DELETE FROM vulnerabilities v
+USING (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY organization_id, fingerprint ORDER BY last_seen DESC, created_at DESC, id) AS position
+    FROM vulnerabilities
+) ranked
+WHERE v.id = ranked.id AND ranked.position > 1;
+
+ALTER TABLE vulnerabilities ALTER COLUMN fingerprint SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_vulnerabilities_org_fingerprint ON vulnerabilities(organization_id, fingerprint);
+CREATE INDEX idx_vulnerabilities_org_status ON vulnerabilities(organization_id, status);
+
+CREATE TABLE vulnerability_status_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vulnerability_id UUID NOT NULL REFERENCES vulnerabilities(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    scan_id UUID REFERENCES security_scans(id) ON DELETE SET NULL,
+    reason TEXT,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_vulnerability_status_changes_vuln_changed ON vulnerability_status_changes(vulnerability_id, changed_at DESC);
@@ -0,0 +1,2 @@
+-- Remove stored deployment SBOMs
+DROP TABLE IF EXISTS deployment_sboms;
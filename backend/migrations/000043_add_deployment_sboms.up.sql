@@ -0,0 +1,13 @@
+-- Store the software bill of materials generated for each deployment's artifact, once per format
+CREATE TABLE deployment_sboms (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    deployment_id UUID NOT NULL REFERENCES deployments(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    format VARCHAR(20) NOT NULL,
+    artifact VARCHAR(500) NOT NULL,
+    document TEXT NOT NULL,
+    generated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (deployment_id, format)
+);
+
+CREATE INDEX idx_deployment_sboms_organization_id ON deployment_sboms(organization_id);
@@ -0,0 +1,2 @@
+-- Remove background jobs
+DROP TABLE IF EXISTS jobs;
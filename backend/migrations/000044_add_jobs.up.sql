@@ -0,0 +1,27 @@
+-- Track long-running operations that run in the background and are polled by ID
+CREATE TABLE jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    exclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    payload JSONB NOT NULL DEFAULT '{}',
+    progress INTEGER NOT NULL DEFAULT 0 CHECK (progress >= 0 AND progress <= 100),
+    result JSONB,
+    error TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_jobs_organization_id ON jobs(organization_id, created_at DESC);
+
+-- Workers claim the oldest queued job
+CREATE INDEX idx_jobs_queued ON jobs(created_at) WHERE status = 'queued';
+
+-- An organization has at most one incomplete job of each exclusive type
+CREATE UNIQUE INDEX idx_jobs_exclusive ON jobs(organization_id, type)
+    WHERE exclusive AND status IN ('queued', 'running');
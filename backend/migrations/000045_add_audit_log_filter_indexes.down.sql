@@ -0,0 +1,7 @@
+-- Remove audit log filter indexes
+CREATE INDEX IF NOT EXISTS idx_audit_logs_organization_id ON audit_logs(organization_id);
+
+DROP INDEX IF EXISTS idx_audit_logs_org_resource_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_org_action_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_org_user_created_at;
+DROP INDEX IF EXISTS idx_audit_logs_org_created_at;
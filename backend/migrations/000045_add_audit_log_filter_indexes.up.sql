@@ -0,0 +1,9 @@
+-- Index audit log listing by organization and time, and each filter it supports, so filtered and
+-- sorted pages and their counts do not scan the organization's whole history
+CREATE INDEX IF NOT EXISTS idx_audit_logs_org_created_at ON audit_logs(organization_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_org_user_created_at ON audit_logs(organization_id, user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_org_action_created_at ON audit_logs(organization_id, action, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_org_resource_created_at ON audit_logs(organization_id, resource_type, resource_id, created_at);
+
+-- Superseded by idx_audit_logs_org_created_at
+DROP INDEX IF EXISTS idx_audit_logs_organization_id;
@@ -0,0 +1,15 @@
+-- Remove scheduled compliance reports
+DROP INDEX IF EXISTS idx_compliance_reports_org_created_at;
+
+ALTER TABLE compliance_reports DROP COLUMN IF EXISTS delivery_error;
+ALTER TABLE compliance_reports DROP COLUMN IF EXISTS delivered_at;
+ALTER TABLE compliance_reports DROP COLUMN IF EXISTS schedule_id;
+
+-- Reports without a user cannot satisfy the original constraint
+DELETE FROM compliance_reports WHERE user_id IS NULL;
+ALTER TABLE compliance_reports DROP CONSTRAINT IF EXISTS compliance_reports_user_id_fkey;
+ALTER TABLE compliance_reports ADD CONSTRAINT compliance_reports_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE;
+ALTER TABLE compliance_reports ALTER COLUMN user_id SET NOT NULL;
+
+DROP TABLE IF EXISTS compliance_report_schedules;
@@ -0,0 +1,29 @@
+-- Scheduled compliance reports: each schedule generates a report on one framework every month or
+-- quarter and emails it to its recipients
+CREATE TABLE IF NOT EXISTS compliance_report_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    framework_id UUID NOT NULL REFERENCES compliance_frameworks(id) ON DELETE CASCADE,
+    frequency VARCHAR(20) NOT NULL CHECK (frequency IN ('monthly', 'quarterly')),
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_compliance_report_schedules_organization_id ON compliance_report_schedules(organization_id);
+CREATE INDEX IF NOT EXISTS idx_compliance_report_schedules_due ON compliance_report_schedules(next_run_at) WHERE enabled;
+
+-- Scheduled reports have no requesting user, and record the schedule and the outcome of delivering them
+ALTER TABLE compliance_reports ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE compliance_reports DROP CONSTRAINT IF EXISTS compliance_reports_user_id_fkey;
+ALTER TABLE compliance_reports ADD CONSTRAINT compliance_reports_user_id_fkey
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE compliance_reports ADD COLUMN IF NOT EXISTS schedule_id UUID REFERENCES compliance_report_schedules(id) ON DELETE SET NULL;
+ALTER TABLE compliance_reports ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE compliance_reports ADD COLUMN IF NOT EXISTS delivery_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_compliance_reports_org_created_at ON compliance_reports(organization_id, created_at DESC);
@@ -0,0 +1,3 @@
+-- Remove rollback safety checks, along with the refused rollbacks only they describe
+DELETE FROM deployment_rollbacks WHERE safety_check->>'decision' = 'refused';
+ALTER TABLE deployment_rollbacks DROP COLUMN IF EXISTS safety_check;
@@ -0,0 +1,3 @@
+-- Record the vulnerability check made before each rollback. Refused rollbacks are kept in the
+-- history too, without a rollback deployment.
+ALTER TABLE deployment_rollbacks ADD COLUMN safety_check JSONB;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_audit_logs_org_impersonated_by_created_at;
+
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS impersonated_by;
+
+DELETE FROM permissions WHERE name = 'admin:impersonate';
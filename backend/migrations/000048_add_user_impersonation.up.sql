@@ -0,0 +1,10 @@
+-- Support engineers with admin:impersonate can act as a user; actions taken while impersonating
+-- record who was behind them
+INSERT INTO permissions (name, resource, action, description, category) VALUES
+('admin:impersonate', 'admin', 'impersonate', 'Impersonate users for support', 'admin')
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE audit_logs ADD COLUMN impersonated_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_org_impersonated_by_created_at
+    ON audit_logs(organization_id, impersonated_by, created_at) WHERE impersonated_by IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Remove infrastructure snapshot records
+DROP TABLE IF EXISTS infrastructure_snapshots;
@@ -0,0 +1,20 @@
+-- Record the snapshots taken of database resources at their provider, and the resources restored
+-- from them. A snapshot outlives its resource, so it is kept when the resource is purged.
+CREATE TABLE infrastructure_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    infrastructure_id UUID REFERENCES infrastructure(id) ON DELETE SET NULL,
+    provider VARCHAR(50) NOT NULL,
+    region VARCHAR(100) NOT NULL,
+    external_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    size_gb DECIMAL(15,2),
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_infrastructure_snapshots_infrastructure_id ON infrastructure_snapshots(infrastructure_id, created_at);
+CREATE INDEX idx_infrastructure_snapshots_organization_id ON infrastructure_snapshots(organization_id);
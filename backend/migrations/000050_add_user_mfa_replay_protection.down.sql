@@ -0,0 +1,5 @@
+-- Remove MFA replay protection fields from users table
+ALTER TABLE users
+DROP COLUMN IF EXISTS mfa_locked_until,
+DROP COLUMN IF EXISTS mfa_failed_attempts,
+DROP COLUMN IF EXISTS mfa_last_totp_step;
@@ -0,0 +1,6 @@
+-- Track the last accepted TOTP time step so codes cannot be replayed, and failed MFA attempts so
+-- codes cannot be guessed
+ALTER TABLE users
+ADD COLUMN mfa_last_totp_step BIGINT,
+ADD COLUMN mfa_failed_attempts INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN mfa_locked_until TIMESTAMP WITH TIME ZONE;
@@ -0,0 +1,6 @@
+-- Remove job leases
+DROP INDEX IF EXISTS idx_jobs_lease_expires_at;
+
+ALTER TABLE jobs
+DROP COLUMN IF EXISTS lease_expires_at,
+DROP COLUMN IF EXISTS worker_id;
@@ -0,0 +1,8 @@
+-- Record which worker runs a job and until when its lease holds, so only jobs whose worker stopped
+-- renewing the lease are requeued
+ALTER TABLE jobs
+ADD COLUMN worker_id VARCHAR(100),
+ADD COLUMN lease_expires_at TIMESTAMP WITH TIME ZONE;
+
+-- Running jobs are checked for expired leases
+CREATE INDEX idx_jobs_lease_expires_at ON jobs(lease_expires_at) WHERE status = 'running';
@@ -1577,7 +1577,7 @@ const InfrastructureTab: React.FC<{ isDark: boolean }> = ({ isDark }) => {
 
   // Resource distribution chart data
   const distributionChartData: ChartData = {
-    labels: ['EC2 Instances', 'S3 Buckets', 'RDS Databases', 'Lambda Functions'],
+    labels: ['EC2 Instances', 'S3 Buckets', 'RDS Databases', 'ECS Services'],
     datasets: [
       {
         label: 'Resource Count',
@@ -1585,7 +1585,7 @@ const InfrastructureTab: React.FC<{ isDark: boolean }> = ({ isDark }) => {
           distribution?.ec2Instances || 0,
           distribution?.s3Buckets || 0,
           distribution?.rdsDatabases || 0,
-          distribution?.lambdaFunctions || 0,
+          distribution?.ecsServices || 0,
         ],
       },
     ],
@@ -334,7 +334,7 @@ const InfrastructureOverview: React.FC<{ isDark: boolean }> = ({ isDark }) => {
             <p>EC2 Instances: {distribution.ec2Instances} ({Math.round((distribution.ec2Instances / distribution.totalCount) * 100)}%)</p>
             <p>S3 Buckets: {distribution.s3Buckets} ({Math.round((distribution.s3Buckets / distribution.totalCount) * 100)}%)</p>
             <p>RDS Databases: {distribution.rdsDatabases} ({Math.round((distribution.rdsDatabases / distribution.totalCount) * 100)}%)</p>
-            <p>Lambda Functions: {distribution.lambdaFunctions} ({Math.round((distribution.lambdaFunctions / distribution.totalCount) * 100)}%)</p>
+            <p>ECS Services: {distribution.ecsServices} ({Math.round((distribution.ecsServices / distribution.totalCount) * 100)}%)</p>
           </div>
         </GlassCard>
 
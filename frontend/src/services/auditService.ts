@@ -1,7 +1,28 @@
 import { apiService } from './apiService';
 
+export interface AuditLogQuery {
+  userId?: string;
+  action?: string;
+  resourceType?: string;
+  resourceId?: string;
+  // RFC 3339 timestamp or YYYY-MM-DD; a date endDate includes the whole day
+  startDate?: string;
+  endDate?: string;
+  sort?: 'asc' | 'desc';
+  limit?: number;
+  offset?: number;
+}
+
+export interface AuditLogPage {
+  logs: any[];
+  total: number;
+  limit: number;
+  offset: number;
+  sort: 'asc' | 'desc';
+}
+
 export const auditService = {
-  getAuditLogs: async (query: any) => {
+  getAuditLogs: async (query: AuditLogQuery = {}): Promise<AuditLogPage> => {
     try {
       const response = await apiService.get('/audit', { params: query });
       return response.data;
@@ -10,4 +31,4 @@ export const auditService = {
       throw error;
     }
   },
-};
\ No newline at end of file
+};
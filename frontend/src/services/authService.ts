@@ -230,12 +230,12 @@ export class AuthService {
     }
   }
 
-  static async initiateOAuthLogin(provider: string, state?: string): Promise<{ authUrl: string }> {
+  static async initiateOAuthLogin(provider: string, organizationId?: string): Promise<{ authUrl: string }> {
     try {
       const response = await apiService.post('/auth/sso/oauth/login', {
         provider,
-        state,
-      }, { skipAuth: true });
+        organizationId,
+      }, { skipAuth: true, withCredentials: true });
       return response;
     } catch (error: any) {
       ErrorHandler.logError(error, 'AuthService.initiateOAuthLogin');
@@ -243,14 +243,13 @@ export class AuthService {
     }
   }
 
-  static async handleOAuthCallback(provider: string, code: string, state?: string, organizationId?: string): Promise<AuthResponse> {
+  static async handleOAuthCallback(provider: string, code: string, state: string): Promise<AuthResponse> {
     try {
-      const params = organizationId ? `?organizationId=${organizationId}` : '';
-      const response = await apiService.post<LoginResponse>(`/auth/sso/oauth/callback${params}`, {
+      const response = await apiService.post<LoginResponse>('/auth/sso/oauth/callback', {
         provider,
         code,
         state,
-      }, { skipAuth: true });
+      }, { skipAuth: true, withCredentials: true });
       
       const { user, token, refreshToken } = response;
       
@@ -132,6 +132,42 @@ export interface ComplianceViolation {
   createdAt: string;
 }
 
+export interface ComplianceReportSchedule {
+  id: string;
+  organizationId: string;
+  frameworkId: string;
+  frequency: 'monthly' | 'quarterly';
+  recipients: string[];
+  enabled: boolean;
+  nextRunAt: string;
+  lastRunAt?: string;
+  createdBy?: string;
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface ComplianceReport {
+  id: string;
+  organizationId: string;
+  userId?: string;
+  scheduleId?: string;
+  name: string;
+  description: string;
+  type: string;
+  frameworks: string[];
+  period: {
+    startDate: string;
+    endDate: string;
+  };
+  status: string;
+  data?: Record<string, any>;
+  generatedAt?: string;
+  deliveredAt?: string;
+  deliveryError?: string;
+  createdAt: string;
+  updatedAt: string;
+}
+
 class ComplianceService {
   private static instance: ComplianceService;
 
@@ -265,6 +301,69 @@ class ComplianceService {
     }
   }
 
+  // Scheduled Reports
+  async getReportSchedules(): Promise<ComplianceReportSchedule[]> {
+    try {
+      const response = await apiService.get('/api/compliance/report-schedules');
+      return response.data.schedules || [];
+    } catch (error) {
+      console.error('Failed to fetch compliance report schedules:', error);
+      throw new Error('Failed to load compliance report schedules');
+    }
+  }
+
+  async createReportSchedule(schedule: Pick<ComplianceReportSchedule, 'frameworkId' | 'frequency' | 'recipients'> & { enabled?: boolean }): Promise<ComplianceReportSchedule> {
+    try {
+      const response = await apiService.post('/api/compliance/report-schedules', schedule);
+      return response.data;
+    } catch (error) {
+      console.error('Failed to create compliance report schedule:', error);
+      throw new Error('Failed to create compliance report schedule');
+    }
+  }
+
+  async updateReportSchedule(scheduleId: string, updates: Partial<Pick<ComplianceReportSchedule, 'frequency' | 'recipients' | 'enabled'>>): Promise<ComplianceReportSchedule> {
+    try {
+      const response = await apiService.put(`/api/compliance/report-schedules/${scheduleId}`, updates);
+      return response.data;
+    } catch (error) {
+      console.error('Failed to update compliance report schedule:', error);
+      throw new Error('Failed to update compliance report schedule');
+    }
+  }
+
+  async deleteReportSchedule(scheduleId: string): Promise<void> {
+    try {
+      await apiService.delete(`/api/compliance/report-schedules/${scheduleId}`);
+    } catch (error) {
+      console.error('Failed to delete compliance report schedule:', error);
+      throw new Error('Failed to delete compliance report schedule');
+    }
+  }
+
+  async getReports(limit = 50, offset = 0): Promise<{ reports: ComplianceReport[]; total: number }> {
+    try {
+      const response = await apiService.get('/api/compliance/reports', { params: { limit, offset } });
+      return { reports: response.data.reports || [], total: response.data.total || 0 };
+    } catch (error) {
+      console.error('Failed to fetch compliance reports:', error);
+      throw new Error('Failed to load compliance reports');
+    }
+  }
+
+  async downloadReport(reportId: string, format: 'pdf' | 'json' = 'pdf'): Promise<Blob> {
+    try {
+      const response = await apiService.get(`/api/compliance/reports/${reportId}/download`, {
+        params: { format },
+        responseType: 'blob',
+      });
+      return response.data;
+    } catch (error) {
+      console.error('Failed to download compliance report:', error);
+      throw new Error('Failed to download compliance report');
+    }
+  }
+
   // Utility Methods
   getFrameworkDisplayName(framework: string): string {
     const frameworkNames: Record<string, string> = {
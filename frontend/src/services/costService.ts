@@ -15,6 +15,7 @@ export interface ResourceCost {
   resourceName: string;
   resourceType: string;
   provider: string;
+  costModel?: 'fixed' | 'usage';
   hourlyCost: number;
   dailyCost: number;
   monthlyCost: number;
@@ -519,7 +519,7 @@ class DemoDataService {
       ec2Instances: 24,
       s3Buckets: 45,
       rdsDatabases: 8,
-      lambdaFunctions: 79,
+      ecsServices: 79,
       totalCount: 156,
     };
   }
@@ -167,11 +167,13 @@ export class DeploymentService {
     await apiService.post(`/deployments/${id}/cancel`, { reason });
   }
 
-  // Rollback deployment
-  async rollbackDeployment(id: string, targetVersion: string, reason?: string): Promise<Deployment> {
+  // Rollback deployment. Rollbacks reintroducing fixed critical vulnerabilities are refused
+  // unless override is set with a reason.
+  async rollbackDeployment(id: string, targetVersion: string, reason?: string, override?: boolean): Promise<Deployment> {
     return await apiService.post<Deployment>(`/deployments/${id}/rollback`, { 
       targetVersion, 
-      reason 
+      reason,
+      override
     });
   }
 
@@ -46,7 +46,7 @@ export interface ResourceDistribution {
   ec2Instances: number;
   s3Buckets: number;
   rdsDatabases: number;
-  lambdaFunctions: number;
+  ecsServices: number;
   totalCount: number;
 }
 